@@ -1,30 +1,93 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
+	// Embed tzdata, so zones load where there is no zoneinfo directory,
+	// as in scratch images
+	_ "time/tzdata"
 
 	"github.com/hspedro/mcp-server-time/internal/app"
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/daemon"
+	"github.com/hspedro/mcp-server-time/internal/preflight"
 )
 
 var (
-	// Version is set by build flags
+	// Version, Commit and BuildTime are set by build flags
 	Version   = "dev"
-	BuildTime = "unknown"
+	Commit    = ""
+	BuildTime = ""
 )
 
 func main() {
+	configFile := flag.String("config", "",
+		"path to a YAML, JSON or TOML config file (default: $MCP_CONFIG_FILE, then config.yaml in ./ or ./config)")
+	strictConfig := flag.Bool("strict-config", false,
+		"reject unknown keys in the config file (default: $MCP_CONFIG_STRICT)")
+	distroless := flag.Bool("distroless", false,
+		"read configuration from MCP_* variables alone and refuse settings that need the filesystem (default: $MCP_DISTROLESS)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags]\n       %s config schema\n       %s [flags] preflight\n\nFlags:\n", os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	opts := config.LoadOptions{File: *configFile, Strict: *strictConfig, Distroless: *distroless}
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Args(), opts))
+	}
+
 	// Create and initialize the application
-	application, err := app.New(Version, BuildTime)
+	application, err := app.New(buildinfo.New(Version, Commit, BuildTime), opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize application: %v\n", err)
 		os.Exit(1)
 	}
 	defer application.Close()
 
+	// Run under the Windows service control manager when it started us
+	if isService, err := daemon.RunService("mcp-server-time", application); isService || err != nil {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Service error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Run the application
 	if err := application.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Application error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runCommand runs a subcommand and returns the process exit code
+func runCommand(args []string, opts config.LoadOptions) int {
+	if len(args) == 2 && args[0] == "config" && args[1] == "schema" {
+		out, err := json.MarshalIndent(config.Schema(), "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to build config schema: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(out))
+		return 0
+	}
+
+	if len(args) == 1 && args[0] == "preflight" {
+		report := preflight.Run(opts)
+		report.Write(os.Stdout)
+		if failures := report.Failures(); failures > 0 {
+			fmt.Fprintf(os.Stderr, "Preflight failed: %d of %d checks\n", failures, len(report.Results))
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command: %v\n", args)
+	flag.Usage()
+	return 2
+}
@@ -3,19 +3,43 @@ package app
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
+	"net/http"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.uber.org/zap"
 
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/calendar"
+	"github.com/hspedro/mcp-server-time/internal/chaos"
+	"github.com/hspedro/mcp-server-time/internal/clockjump"
 	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/daemon"
+	"github.com/hspedro/mcp-server-time/internal/envelope"
+	"github.com/hspedro/mcp-server-time/internal/extensions"
+	"github.com/hspedro/mcp-server-time/internal/features"
+	"github.com/hspedro/mcp-server-time/internal/holidays"
+	"github.com/hspedro/mcp-server-time/internal/i18n"
+	"github.com/hspedro/mcp-server-time/internal/limits"
 	"github.com/hspedro/mcp-server-time/internal/logger"
 	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/negotiate"
+	"github.com/hspedro/mcp-server-time/internal/profiles"
+	"github.com/hspedro/mcp-server-time/internal/recovery"
+	"github.com/hspedro/mcp-server-time/internal/redact"
+	"github.com/hspedro/mcp-server-time/internal/replay"
+	"github.com/hspedro/mcp-server-time/internal/resume"
 	"github.com/hspedro/mcp-server-time/internal/server"
+	"github.com/hspedro/mcp-server-time/internal/sessionstate"
+	"github.com/hspedro/mcp-server-time/internal/shed"
+	"github.com/hspedro/mcp-server-time/internal/slo"
 	timeservice "github.com/hspedro/mcp-server-time/internal/time"
 	"github.com/hspedro/mcp-server-time/internal/tools"
+	"github.com/hspedro/mcp-server-time/internal/tzsnapshot"
+	"github.com/hspedro/mcp-server-time/internal/updates"
 )
 
 // App represents the MCP Time Server application
@@ -23,12 +47,23 @@ type App struct {
 	config     *config.Config
 	logger     *zap.Logger
 	httpServer *server.HTTPServer
+	recorder   *replay.Recorder
+	updates    *updates.Checker
+	holidays   *holidays.Importer
+	slo        *slo.Tracker
+	clockJumps *clockjump.Detector
+	sessions   *sessionstate.Store
+	streams    resume.SharedStore
 }
 
-// New creates a new App instance
-func New(version, buildTime string) (*App, error) {
+// streamStoreTimeout bounds connecting to a shared stream store at startup
+const streamStoreTimeout = 5 * time.Second
+
+// New creates a new App instance for the build described by build, loading
+// configuration as opts describe
+func New(build buildinfo.Info, opts config.LoadOptions) (*App, error) {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
@@ -40,20 +75,110 @@ func New(version, buildTime string) (*App, error) {
 	}
 
 	appLogger.Info("Starting MCP Time Server",
-		zap.String("version", version),
-		zap.String("build_time", buildTime),
+		zap.String("version", build.Version),
+		zap.String("commit", build.ShortCommit()),
+		zap.String("build_time", build.BuildTime),
+		zap.String("go_version", build.GoVersion),
+		zap.String("config_file", cfg.File),
+		zap.String("environment", cfg.Environment),
+		zap.String("config_overlay", cfg.Overlay),
 		zap.String("server_name", cfg.Server.Name),
 		zap.String("host", cfg.Server.Host),
 		zap.Int("port", cfg.Server.Port),
-		zap.Bool("metrics_enabled", cfg.Metrics.Enabled))
+		zap.Bool("metrics_enabled", cfg.Metrics.Enabled),
+		zap.String("log_backend", cfg.Logging.Backend))
+
+	// Setup record-and-replay
+	var timeOpts []timeservice.Option
+	var recorder *replay.Recorder
+	var player *replay.Player
+
+	switch cfg.Replay.Mode {
+	case config.ReplayModeRecord:
+		recorder, err = replay.NewRecorder(cfg.Replay.File, logger.Module(appLogger, config.LogModuleReplay))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup recorder: %w", err)
+		}
+		appLogger.Info("Recording tool calls", zap.String("file", cfg.Replay.File))
+	case config.ReplayModeReplay:
+		player, err = replay.LoadPlayer(cfg.Replay.File, logger.Module(appLogger, config.LogModuleReplay))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load recording: %w", err)
+		}
+		appLogger.Info("Replaying recorded tool calls", zap.String("file", cfg.Replay.File))
+	}
+
+	// Pin zones to an embedded tzdata snapshot for host-independent output
+	var snapshot *tzsnapshot.Snapshot
+	var loadZone timeservice.ZoneLoader
+	if cfg.Determinism.Enabled {
+		// Already validated by config.Load
+		snapshot, _ = tzsnapshot.Select(cfg.Determinism.TZData)
+		appLogger.Info("Deterministic mode", zap.String("tzdata", snapshot.Version))
+		loadZone = snapshot.LoadLocation
+		timeOpts = append(timeOpts, timeservice.WithZoneLoader(loadZone))
+	}
+
+	// clock stays nil for the system clock
+	var clock timeservice.Clock
+	if frozen, ok := frozenTime(cfg, player); ok {
+		appLogger.Info("Clock frozen", zap.Time("time", frozen))
+		clock = timeservice.FixedClock{Time: frozen}
+		timeOpts = append(timeOpts, timeservice.WithClock(clock))
+	}
+
+	timeOpts = append(timeOpts, timeservice.WithTwoDigitYearPivot(cfg.Time.TwoDigitYearPivot))
+	timeOpts = append(timeOpts, timeservice.WithRoundedOffsets(cfg.Time.OffsetSeconds == config.OffsetSecondsRound))
+	timeOpts = append(timeOpts, timeservice.WithBulkBudget(cfg.Limits.MaxBulkBytes))
+	if cfg.Time.GregorianCutover != "" {
+		// Already validated by config.Load
+		cutover, _ := time.Parse(time.DateOnly, cfg.Time.GregorianCutover)
+		timeOpts = append(timeOpts, timeservice.WithGregorianCutover(cutover))
+	}
+	if cfg.Time.DSTLookaheadDays > 0 {
+		timeOpts = append(timeOpts, timeservice.WithDSTLookahead(cfg.Time.DSTLookaheadDays))
+	}
+	if len(cfg.Time.TZDataSources) > 0 {
+		sources := make(map[string]string, len(cfg.Time.TZDataSources))
+		for _, source := range cfg.Time.TZDataSources {
+			sources[source.Name] = source.Path
+		}
+		timeOpts = append(timeOpts, timeservice.WithTZDataSources(sources))
+	}
+	if len(cfg.Time.PreloadTimezones) > 0 {
+		timeOpts = append(timeOpts, timeservice.WithPreloadedTimezones(cfg.Time.PreloadTimezones))
+	}
+	if vr := cfg.Time.ValidRange; vr.Enabled {
+		timeOpts = append(timeOpts, timeservice.WithValidRange(timeservice.ValidRange{
+			MinYear: vr.MinYear,
+			MaxYear: vr.MaxYear,
+			Reject:  vr.Mode == config.ValidRangeModeReject,
+		}))
+	}
+	var precisionCap *envelope.PrecisionCap
+	if cfg.Time.MaxPrecision != "" {
+		// The envelope caps results below; the service caps the formats
+		// the envelope can't read back with the same truncation
+		if precisionCap, err = envelope.NewPrecisionCap(cfg.Time.MaxPrecision, logger.Module(appLogger, config.LogModuleEnvelope)); err != nil {
+			return nil, fmt.Errorf("failed to setup precision cap: %w", err)
+		}
+		timeOpts = append(timeOpts, timeservice.WithPrecisionCap(precisionCap.Truncate))
+	}
 
 	// Initialize components
 	metricsCollector := metrics.New()
+	metricsCollector.RecordBuildInfo(build.Version, build.Commit, build.BuildTime, build.GoVersion)
+	if shadow := cfg.Time.FormatShadow; shadow.Engine != "" {
+		appLogger.Info("Shadowing formats", zap.String("engine", shadow.Engine), zap.Float64("sample_rate", shadow.SampleRate))
+		timeOpts = append(timeOpts, timeservice.WithFormatShadow(shadow.Engine, timeservice.ShadowEngines[shadow.Engine],
+			shadow.SampleRate, metricsCollector.RecordFormatShadow))
+	}
 	timeService := timeservice.NewTimeService(
 		cfg.Time.DefaultTimezone,
 		cfg.Time.DefaultFormat,
 		cfg.Time.SupportedFormats,
-		appLogger,
+		logger.Slog(logger.Module(appLogger, config.LogModuleTime)),
+		timeOpts...,
 	)
 
 	// Create MCP server
@@ -62,52 +187,346 @@ func New(version, buildTime string) (*App, error) {
 		Version: cfg.Server.Version,
 	}, nil)
 
-	// Register time tools
-	tools.RegisterTimeTools(mcpServer, timeService, metricsCollector, appLogger)
+	// Register time tools, then the ones feature flags enable
+	flags, err := features.New(cfg.Features)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup feature flags: %w", err)
+	}
+	tools.RegisterTimeTools(mcpServer, timeService, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+	tools.RegisterFeatureTools(mcpServer, flags, timeService, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+	// Metrics label calls with these names only, as clients can call any
+	// name; the server may serve fewer of them
+	toolNames := tools.ReservedNames(timeService, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+	if len(cfg.Extensions) > 0 {
+		extensionTools, err := extensions.Load(context.Background(), cfg.Extensions, toolNames, metricsCollector, logger.Module(appLogger, config.LogModuleExtension))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load extensions: %w", err)
+		}
+		tools.RegisterExternalTools(mcpServer, extensionTools)
+		for _, tool := range extensionTools {
+			toolNames[tool.Name()] = true
+		}
+	}
+	if len(cfg.Calendar.Sources) > 0 {
+		calendars, err := calendar.New(cfg.Calendar, cfg.Time.DefaultTimezone, clock, loadZone, build.Version, metricsCollector, logger.Module(appLogger, config.LogModuleCalendar))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup calendars: %w", err)
+		}
+		tools.RegisterCalendarTools(mcpServer, calendars, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+		appLogger.Info("Reading calendars", zap.Strings("calendars", calendars.Names()))
+	}
+	var importer *holidays.Importer
+	if len(cfg.Holidays.Feeds) > 0 {
+		importer, err = holidays.New(cfg.Holidays, cfg.Time.DefaultTimezone, clock, loadZone, build.Version, metricsCollector, logger.Module(appLogger, config.LogModuleHolidays))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup holiday feeds: %w", err)
+		}
+		tools.RegisterHolidayTools(mcpServer, importer, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+		appLogger.Info("Importing holiday feeds",
+			zap.Strings("feeds", importer.Names()),
+			zap.Duration("interval", cfg.Holidays.Interval))
+	}
+	tzdataVersion := updates.LocalTZDataVersion()
+	if snapshot != nil {
+		tzdataVersion = snapshot.Version
+	}
+	var checker *updates.Checker
+	if cfg.Updates.Enabled {
+		checker = updates.New(cfg.Updates, build.Version, tzdataVersion, metricsCollector, logger.Module(appLogger, config.LogModuleUpdates))
+		appLogger.Info("Checking for updates",
+			zap.Duration("interval", cfg.Updates.Interval),
+			zap.String("tzdata", tzdataVersion))
+	}
+	tools.RegisterServerInfoTool(mcpServer, tools.ServerInfo{
+		Service:     cfg.Server.Name,
+		Environment: cfg.Environment,
+		Build:       build,
+		TZData:      tzdataVersion,
+	}, checker, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+	appLogger.Info("Feature flags", zap.Strings("enabled", flags.List()))
+
+	// Keep per-session state bounded: it expires, can be cleared, and goes
+	// with its session
+	sessions := sessionstate.New(cfg.SessionState, mcpServer, metricsCollector, logger.Module(appLogger, config.LogModuleSession))
+	tools.RegisterSessionStateTool(mcpServer, sessions, metricsCollector, logger.Module(appLogger, config.LogModuleTools))
+
+	// Apply the session's negotiated format before anything inspects the call
+	var negotiator *negotiate.Negotiator
+	if cfg.Time.NegotiateFormat {
+		negotiator = negotiate.New(cfg.Time.SupportedFormats, cfg.Time.DefaultFormat, sessions, logger.Module(appLogger, config.LogModuleTools))
+		mcpServer.AddReceivingMiddleware(negotiator.Middleware())
+	}
+
+	// Render tool errors in the locale the session negotiated
+	mcpServer.AddReceivingMiddleware(i18n.New(cfg.I18n.DefaultLocale, logger.Module(appLogger, config.LogModuleTools)).Middleware())
+
+	// Add renamed fields under their old names before the cap and style
+	// rewrite results, so both names read the same
+	if cfg.Compat.EmitLegacyFields {
+		mcpServer.AddReceivingMiddleware(envelope.NewLegacyFields(envelope.Renames, logger.Module(appLogger, config.LogModuleEnvelope)).Middleware())
+	}
+
+	// Cap result precision so every later middleware only sees capped results
+	if precisionCap != nil {
+		mcpServer.AddReceivingMiddleware(precisionCap.Middleware())
+		appLogger.Info("Capping result precision", zap.String("max_precision", cfg.Time.MaxPrecision))
+	}
+
+	// Restyle timestamps after the cap, which renders them in Go's defaults
+	if cfg.Time.RFC3339.Restyles() {
+		style, err := envelope.NewRFC3339Style(cfg.Time.RFC3339.UTCOffset, cfg.Time.RFC3339.Fraction, logger.Module(appLogger, config.LogModuleEnvelope))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup RFC3339 style: %w", err)
+		}
+		mcpServer.AddReceivingMiddleware(style.Middleware())
+		appLogger.Info("Restyling RFC3339 timestamps",
+			zap.String("utc_offset", cfg.Time.RFC3339.UTCOffset),
+			zap.String("fraction", cfg.Time.RFC3339.Fraction))
+	}
+
+	// Shape results per client once every rewrite above is done
+	if len(cfg.ClientProfiles) > 0 {
+		mcpServer.AddReceivingMiddleware(profiles.New(cfg.ClientProfiles, metricsCollector, logger.Module(appLogger, config.LogModuleEnvelope)).Middleware())
+	}
+
+	// Hide sensitive arguments echoed back in tool errors
+	redactor, err := redact.New(cfg.Logging.Redaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup redaction: %w", err)
+	}
+	if redactor != nil {
+		mcpServer.AddReceivingMiddleware(redactor.Middleware())
+	}
+
+	// Reject oversized arguments before the middlewares above or any tool
+	// parse them, and check the size of the results they produce
+	mcpServer.AddReceivingMiddleware(limits.New(cfg.Limits, metricsCollector, logger.Module(appLogger, config.LogModuleTools)).Middleware())
+
+	// Record or replay tool calls if configured
+	if recorder != nil {
+		mcpServer.AddReceivingMiddleware(recorder.Middleware())
+	}
+	if player != nil {
+		mcpServer.AddReceivingMiddleware(player.Middleware())
+	}
+
+	// Enable fault injection if configured
+	var injector *chaos.Injector
+	if cfg.Chaos.Enabled {
+		appLogger.Warn("Chaos mode enabled, faults will be injected into responses",
+			zap.Float64("sse_drop_probability", cfg.Chaos.SSEDropProbability))
+		injector = chaos.New(cfg.Chaos, metricsCollector, logger.Module(appLogger, config.LogModuleChaos))
+		mcpServer.AddReceivingMiddleware(injector.ToolMiddleware())
+	}
+
+	// Time tool calls against their objectives outside the middlewares
+	// above, so injected faults count, but inside recovery so panics do
+	var tracker *slo.Tracker
+	if cfg.SLO.Enabled {
+		tracker = slo.New(cfg.SLO, toolNames, build.Version, metricsCollector, logger.Module(appLogger, config.LogModuleSLO))
+		mcpServer.AddReceivingMiddleware(tracker.Middleware())
+		appLogger.Info("Tracking tool SLOs",
+			zap.Duration("window", cfg.SLO.Window),
+			zap.Duration("latency", cfg.SLO.Default.Latency),
+			zap.Float64("objective", cfg.SLO.Default.Objective))
+	}
+
+	// Shed low-priority calls outside the SLO tracker, so rejected calls
+	// aren't timed and don't pull the p99 shedding reads down
+	if cfg.Shed.Enabled {
+		var latency shed.LatencySource
+		if tracker != nil {
+			latency = tracker
+		}
+		shedder := shed.New(cfg.Shed, toolNames, latency, metricsCollector, logger.Module(appLogger, config.LogModuleShed))
+		mcpServer.AddReceivingMiddleware(shedder.Middleware())
+		appLogger.Info("Shedding load under pressure",
+			zap.Int("max_in_flight", cfg.Shed.MaxInFlight),
+			zap.Duration("max_p99", cfg.Shed.MaxP99))
+	}
+
+	// Check the clocks around calls outside every middleware that may take
+	// time, so a jump during any of them marks the result
+	var detector *clockjump.Detector
+	if cfg.ClockJump.Enabled {
+		detector = clockjump.New(cfg.ClockJump, mcpServer, metricsCollector, logger.Module(appLogger, config.LogModuleClock))
+		mcpServer.AddReceivingMiddleware(detector.Middleware())
+	}
+
+	// Recover from panics last so it wraps every other middleware
+	mcpServer.AddReceivingMiddleware(recovery.New(metricsCollector, logger.Module(appLogger, config.LogModuleRecovery)).Middleware())
+
+	// Streamable sessions keep their events and state in one store; a store
+	// the replicas share lets any of them resume a session
+	storeCtx, cancelStore := context.WithTimeout(context.Background(), streamStoreTimeout)
+	defer cancelStore()
+	streamStore, err := resume.NewSharedStore(storeCtx, cfg.Server, metricsCollector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup stream store: %w", err)
+	}
+	streams := resume.NewHandler(mcpServer, streamStore, metricsCollector, logger.Module(appLogger, config.LogModuleTransport))
+	if negotiator != nil {
+		streams.OnRestore(negotiator.Restore)
+	}
+	appLogger.Info("Stream store ready", zap.String("backend", cfg.Server.StreamStore.Backend))
 
 	// Create HTTP server
-	httpServer := server.NewHTTPServer(cfg, mcpServer, metricsCollector, appLogger)
+	httpServer := server.NewHTTPServer(cfg, build, mcpServer, metricsCollector, injector, streams, logger.Module(appLogger, config.LogModuleTransport))
 
 	return &App{
 		config:     cfg,
 		logger:     appLogger,
 		httpServer: httpServer,
+		recorder:   recorder,
+		updates:    checker,
+		holidays:   importer,
+		slo:        tracker,
+		clockJumps: detector,
+		sessions:   sessions,
+		streams:    streamStore,
 	}, nil
 }
 
-// Run starts the application and handles graceful shutdown
+// frozenTime returns the instant the clock should be frozen at, if any.
+// An explicit replay.frozen_time wins over the start of a loaded recording,
+// and either over determinism.frozen_time.
+func frozenTime(cfg *config.Config, player *replay.Player) (time.Time, bool) {
+	// Already validated by config.Load
+	if cfg.Replay.FrozenTime != "" {
+		t, _ := time.Parse(time.RFC3339Nano, cfg.Replay.FrozenTime)
+		return t, true
+	}
+	if player != nil && !player.StartTime().IsZero() {
+		return player.StartTime(), true
+	}
+	if cfg.Determinism.Enabled {
+		t, _ := time.Parse(time.RFC3339Nano, cfg.Determinism.FrozenTime)
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Run starts the application and handles graceful shutdown on SIGINT or
+// SIGTERM
 func (a *App) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return a.Serve(ctx)
+}
+
+// Serve runs the application until ctx is done, then shuts it down
+// gracefully. Under systemd with Type=notify, it reports readiness and
+// shutdown, and pings the watchdog while the server answers its health check
+func (a *App) Serve(ctx context.Context) error {
+	background, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Check for updates in the background until shutdown
+	if a.updates != nil {
+		go a.updates.Run(background)
+	}
+
+	// Sync holiday feeds in the background until shutdown
+	if a.holidays != nil {
+		go a.holidays.Run(background)
+	}
+
+	// Evaluate tool SLOs in the background until shutdown
+	if a.slo != nil {
+		go a.slo.Run(background)
+	}
+
+	// Watch for wall-clock jumps between tool calls until shutdown
+	if a.clockJumps != nil {
+		go a.clockJumps.Run(background)
+	}
+
+	// Expire session state and drop closed sessions' until shutdown
+	go a.sessions.Run(background)
+
 	// Start HTTP server in background
 	serverErr := make(chan error, 1)
 	go func() {
-		if err := a.httpServer.Start(); err != nil {
+		if err := a.httpServer.Start(); err != nil && err != http.ErrServerClosed {
 			a.logger.Error("Server failed", zap.Error(err))
 			serverErr <- err
 		}
 	}()
 
-	// Wait for either interrupt signal or server error
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case <-quit:
-		a.logger.Info("Received shutdown signal")
-	case err := <-serverErr:
-		a.logger.Error("Server failed to start", zap.Error(err))
-		return err
+	// Wait for a shutdown request or server error, telling systemd once
+	// the server listens
+	ready := a.httpServer.Ready()
+	for running := true; running; {
+		select {
+		case <-ready:
+			ready = nil
+			a.notifyReady(background)
+		case <-ctx.Done():
+			a.logger.Info("Received shutdown signal")
+			running = false
+		case err := <-serverErr:
+			a.logger.Error("Server failed to start", zap.Error(err))
+			return err
+		}
 	}
+	a.notify(daemon.StateStopping)
 
 	// Create shutdown context with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.config.Server.GracefulShutdownTimeout)
-	defer cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), a.config.Server.GracefulShutdownTimeout)
+	defer cancelShutdown()
 
 	// Shutdown gracefully
 	return a.httpServer.Shutdown(shutdownCtx)
 }
 
+// notifyReady tells systemd the server is ready and starts the watchdog
+// pings it asked for, until ctx is done
+func (a *App) notifyReady(ctx context.Context) {
+	if !a.notify(daemon.StateReady) {
+		return
+	}
+	interval, err := daemon.WatchdogInterval()
+	if err != nil {
+		a.logger.Warn("Ignoring systemd watchdog", zap.Error(err))
+		return
+	}
+	if interval > 0 {
+		a.logger.Info("Pinging systemd watchdog", zap.Duration("interval", interval))
+		go daemon.Watchdog(ctx, interval, a.httpServer.CheckHealth, a.logger)
+	}
+}
+
+// notify sends a state to systemd, reporting whether it was sent
+func (a *App) notify(state string) bool {
+	sent, err := daemon.Notify(state)
+	if err != nil {
+		a.logger.Warn("Failed to notify systemd", zap.String("state", state), zap.Error(err))
+	}
+	return sent
+}
+
+// Ready is closed once the server accepts connections
+func (a *App) Ready() <-chan struct{} {
+	return a.httpServer.Ready()
+}
+
+// SetPaused pauses or resumes the MCP endpoints, as a service manager asks
+func (a *App) SetPaused(paused bool) {
+	a.httpServer.SetPaused(paused)
+}
+
 // Close performs cleanup operations
 func (a *App) Close() error {
+	if closer, ok := a.streams.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	if a.recorder != nil {
+		if err := a.recorder.Close(); err != nil {
+			return err
+		}
+	}
 	if a.logger != nil {
 		return a.logger.Sync()
 	}
@@ -0,0 +1,68 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// unknown is what the ldflags defaults report when a value wasn't embedded
+const unknown = "unknown"
+
+// Info identifies the running build. Version, Commit and BuildTime are
+// embedded with -ldflags "-X main.Version=... -X main.Commit=... -X main.BuildTime=..."
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	// Modified is true when the binary was built from a tree with
+	// uncommitted changes, as recorded by the Go toolchain
+	Modified bool `json:"modified"`
+}
+
+// New describes the build from values embedded through ldflags. Values
+// that weren't embedded fall back to the VCS details the Go toolchain
+// records, so plain `go build` binaries still report their commit
+func New(version, commit, buildTime string) Info {
+	info := Info{
+		Version:   defaultString(version, "dev"),
+		Commit:    defaultString(commit, unknown),
+		BuildTime: defaultString(buildTime, unknown),
+		GoVersion: runtime.Version(),
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == unknown {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == unknown {
+				info.BuildTime = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// ShortCommit returns the first 12 characters of the commit, for banners
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 12 {
+		return i.Commit[:12]
+	}
+	return i.Commit
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,44 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		commit    string
+		buildTime string
+		want      Info
+	}{
+		{
+			name:      "embedded values",
+			version:   "v1.4.0",
+			commit:    "0123456789abcdef0123",
+			buildTime: "2026-10-16T08:00:00Z",
+			want:      Info{Version: "v1.4.0", Commit: "0123456789abcdef0123", BuildTime: "2026-10-16T08:00:00Z"},
+		},
+		{
+			// Test binaries carry no VCS settings, so nothing fills the gaps
+			name: "defaults",
+			want: Info{Version: "dev", Commit: "unknown", BuildTime: "unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := New(tt.version, tt.commit, tt.buildTime)
+			tt.want.GoVersion = runtime.Version()
+			assert.Equal(t, tt.want, info)
+		})
+	}
+}
+
+func TestInfo_ShortCommit(t *testing.T) {
+	assert.Equal(t, "0123456789ab", Info{Commit: "0123456789abcdef0123"}.ShortCommit())
+	assert.Equal(t, "unknown", Info{Commit: "unknown"}.ShortCommit())
+}
@@ -0,0 +1,631 @@
+// Package calendar reads free/busy information from ICS feeds and CalDAV
+// calendars, and finds free slots across them.
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// maxFeedBytes bounds what is read from a calendar
+const maxFeedBytes = 10 << 20
+
+// Query limits
+const (
+	DefaultRange = 7 * 24 * time.Hour
+	MaxRange     = 92 * 24 * time.Hour
+)
+
+// source is a configured calendar
+type source struct {
+	cfg config.CalendarSourceConfig
+	loc *time.Location
+}
+
+// cacheEntry is a fetched calendar. ICS feeds are cached whole; CalDAV
+// answers are cached per queried range
+type cacheEntry struct {
+	calendar  *Calendar
+	fetchedAt time.Time
+}
+
+// Client reads the configured calendars
+type Client struct {
+	cfg     config.CalendarConfig
+	sources map[string]source
+	names   []string
+	// defaultTimezone renders results when the caller names none
+	defaultTimezone string
+	clock           timeservice.Clock
+	loadZone        timeservice.ZoneLoader
+	client          *http.Client
+	userAgent       string
+	metrics         *metrics.Metrics
+	logger          *zap.Logger
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// New creates a client for the configured calendars. Floating times of a
+// source without a timezone are read in defaultTimezone. clock provides
+// the current time for queries that don't say when to start, and may be
+// nil for the system clock. Zones load with loadZone, such as from a pinned
+// tzdata snapshot, or time.LoadLocation when nil
+func New(cfg config.CalendarConfig, defaultTimezone string, clock timeservice.Clock, loadZone timeservice.ZoneLoader, version string, metrics *metrics.Metrics, logger *zap.Logger) (*Client, error) {
+	if loadZone == nil {
+		loadZone = time.LoadLocation
+	}
+	c := &Client{
+		cfg:             cfg,
+		sources:         make(map[string]source, len(cfg.Sources)),
+		defaultTimezone: defaultTimezone,
+		clock:           clock,
+		loadZone:        loadZone,
+		client:          &http.Client{Timeout: cfg.Timeout},
+		userAgent:       "mcp-server-time/" + version,
+		metrics:         metrics,
+		logger:          logger,
+		cache:           make(map[string]cacheEntry),
+	}
+	for _, sourceCfg := range cfg.Sources {
+		timezone := sourceCfg.Timezone
+		if timezone == "" {
+			timezone = defaultTimezone
+		}
+		loc, err := loadZone(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %s: %w", sourceCfg.Name, err)
+		}
+		c.sources[sourceCfg.Name] = source{cfg: sourceCfg, loc: loc}
+		c.names = append(c.names, sourceCfg.Name)
+	}
+	return c, nil
+}
+
+// Names returns the configured calendar names, in config order
+func (c *Client) Names() []string {
+	return c.names
+}
+
+// Period is a rendered interval
+type Period struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// CalendarBusy is the busy time of one calendar
+type CalendarBusy struct {
+	Name string   `json:"name"`
+	Busy []Period `json:"busy"`
+	// Skipped counts events the calendar holds that couldn't be read, such
+	// as ones with unsupported recurrence rules
+	Skipped int `json:"skipped,omitempty"`
+}
+
+// FreeBusyInput represents input for reading free/busy time
+type FreeBusyInput struct {
+	Calendars []string              `json:"calendars,omitempty"` // calendar names, defaults to all
+	Start     timeservice.Timestamp `json:"start,omitempty"`     // defaults to now
+	End       timeservice.Timestamp `json:"end,omitempty"`       // defaults to a week after start
+	Timezone  string                `json:"timezone,omitempty"`  // timezone for rendered times, defaults to the server default
+	// IntervalSemantics reads end, and writes every end, as the first
+	// second after the range (half_open, the default) or its last (closed)
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// FreeBusyResult represents the busy and free time across calendars
+type FreeBusyResult struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	// IntervalSemantics is how ends are written: half_open or closed
+	IntervalSemantics string         `json:"interval_semantics"`
+	Calendars         []CalendarBusy `json:"calendars"`
+	// Busy merges the busy time of all calendars, and Free is the rest of the range
+	Busy []Period `json:"busy"`
+	Free []Period `json:"free"`
+}
+
+// FreeBusy reads busy time from calendars over a range
+func (c *Client) FreeBusy(ctx context.Context, input FreeBusyInput) (FreeBusyResult, error) {
+	loc, timezone, err := c.location(input.Timezone)
+	if err != nil {
+		return FreeBusyResult{}, err
+	}
+	start, err := c.resolve("start", input.Start, c.now())
+	if err != nil {
+		return FreeBusyResult{}, err
+	}
+	semantics, err := timeservice.ResolveIntervalSemantics(input.IntervalSemantics, timeservice.IntervalHalfOpen)
+	if err != nil {
+		return FreeBusyResult{}, err
+	}
+	end, err := c.resolve("end", input.End, start.Add(DefaultRange))
+	if err != nil {
+		return FreeBusyResult{}, err
+	}
+	if !input.End.IsZero() {
+		end = timeservice.ExclusiveEnd(end, semantics, timeservice.IntervalSecond)
+	}
+	if !end.After(start) {
+		return FreeBusyResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "end must be after start")
+	}
+	if end.Sub(start) > MaxRange {
+		return FreeBusyResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "range cannot exceed %d days", int(MaxRange/(24*time.Hour)))
+	}
+
+	names, err := c.selected(input.Calendars)
+	if err != nil {
+		return FreeBusyResult{}, err
+	}
+	perCalendar, merged, err := c.busy(ctx, names, start, end, loc, semantics)
+	if err != nil {
+		return FreeBusyResult{}, err
+	}
+
+	return FreeBusyResult{
+		Start:             start.In(loc).Format(time.RFC3339),
+		End:               timeservice.ReportedEnd(end, semantics, timeservice.IntervalSecond).In(loc).Format(time.RFC3339),
+		Timezone:          timezone,
+		IntervalSemantics: semantics,
+		Calendars:         perCalendar,
+		Busy:              render(merged, loc, semantics),
+		Free:              render(complement(merged, start, end), loc, semantics),
+	}, nil
+}
+
+// WorkingHours limits slots to a daily window in the query's timezone
+type WorkingHours struct {
+	Start string `json:"start"` // HH:MM
+	End   string `json:"end"`   // HH:MM, after start
+	// Weekdays limits slots to Monday to Friday
+	Weekdays bool `json:"weekdays,omitempty"`
+}
+
+// NextFreeSlotInput represents input for finding the next free slot
+type NextFreeSlotInput struct {
+	Calendars    []string              `json:"calendars,omitempty"`   // calendar names, defaults to all
+	Duration     string                `json:"duration"`              // Go duration of the slot, e.g. 30m
+	After        timeservice.Timestamp `json:"after,omitempty"`       // earliest start, defaults to now
+	Within       string                `json:"within,omitempty"`      // Go duration to search, defaults to a week
+	Granularity  string                `json:"granularity,omitempty"` // Go duration slot starts align to after local midnight, defaults to 15m
+	WorkingHours *WorkingHours         `json:"working_hours,omitempty"`
+	Timezone     string                `json:"timezone,omitempty"` // timezone for working hours and rendered times
+	// IntervalSemantics writes the slot's end as the first second after
+	// it (half_open, the default) or its last (closed)
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// NextFreeSlotResult represents the first slot free in every calendar
+type NextFreeSlotResult struct {
+	Found         bool   `json:"found"`
+	Start         string `json:"start,omitempty"`
+	End           string `json:"end,omitempty"`
+	SearchedUntil string `json:"searched_until"`
+	Timezone      string `json:"timezone"`
+	// IntervalSemantics is how end is written: half_open or closed
+	IntervalSemantics string   `json:"interval_semantics"`
+	Calendars         []string `json:"calendars"`
+}
+
+// defaultGranularity aligns slot starts to quarter hours
+const defaultGranularity = 15 * time.Minute
+
+// NextFreeSlot finds the earliest slot of a duration free in every calendar
+func (c *Client) NextFreeSlot(ctx context.Context, input NextFreeSlotInput) (NextFreeSlotResult, error) {
+	loc, timezone, err := c.location(input.Timezone)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	duration, err := parsePositiveDuration("duration", input.Duration, 0)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	within, err := parsePositiveDuration("within", input.Within, DefaultRange)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	if within > MaxRange {
+		return NextFreeSlotResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "within cannot exceed %d days", int(MaxRange/(24*time.Hour)))
+	}
+	granularity, err := parsePositiveDuration("granularity", input.Granularity, defaultGranularity)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	hours, err := parseWorkingHours(input.WorkingHours)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	semantics, err := timeservice.ResolveIntervalSemantics(input.IntervalSemantics, timeservice.IntervalHalfOpen)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	if hours != nil && duration > hours.end-hours.start {
+		return NextFreeSlotResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "duration %s does not fit in working hours", duration)
+	}
+	after, err := c.resolve("after", input.After, c.now())
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	limit := after.Add(within)
+
+	names, err := c.selected(input.Calendars)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+	_, merged, err := c.busy(ctx, names, after, limit, loc, semantics)
+	if err != nil {
+		return NextFreeSlotResult{}, err
+	}
+
+	result := NextFreeSlotResult{
+		SearchedUntil:     limit.In(loc).Format(time.RFC3339),
+		Timezone:          timezone,
+		IntervalSemantics: semantics,
+		Calendars:         names,
+	}
+	if start, ok := findSlot(merged, after.In(loc), limit, duration, granularity, hours); ok {
+		result.Found = true
+		result.Start = start.Format(time.RFC3339)
+		result.End = timeservice.ReportedEnd(start.Add(duration), semantics, timeservice.IntervalSecond).Format(time.RFC3339)
+	}
+	return result, nil
+}
+
+// workingHours is a parsed daily window, as offsets from midnight
+type workingHours struct {
+	start, end time.Duration
+	weekdays   bool
+}
+
+func parseWorkingHours(input *WorkingHours) (*workingHours, error) {
+	if input == nil {
+		return nil, nil
+	}
+	parse := func(field, value string) (time.Duration, error) {
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid working_hours.%s %q: must be HH:MM", field, value)
+		}
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+	start, err := parse("start", input.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parse("end", input.End)
+	if err != nil {
+		return nil, err
+	}
+	if end <= start {
+		return nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "working_hours.end must be after working_hours.start")
+	}
+	return &workingHours{start: start, end: end, weekdays: input.Weekdays}, nil
+}
+
+// window returns the working window of the day containing t
+func (h *workingHours) window(t time.Time) (time.Time, time.Time) {
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	return wallClock(midnight, h.start), wallClock(midnight, h.end)
+}
+
+// wallClock returns the wall clock time offset from midnight, so windows
+// keep their hours on DST change days
+func wallClock(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, midnight.Location())
+}
+
+// findSlot walks forward from after, skipping busy intervals and time
+// outside working hours, until a slot fits or the search passes limit
+func findSlot(busy []Interval, after, limit time.Time, duration, granularity time.Duration, hours *workingHours) (time.Time, bool) {
+	loc := after.Location()
+	t := align(after, granularity)
+	next := 0
+	for !t.Add(duration).After(limit) {
+		if hours != nil {
+			dayStart, dayEnd := hours.window(t)
+			switch {
+			case hours.weekdays && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday),
+				t.Add(duration).After(dayEnd):
+				year, month, day := t.Date()
+				t = wallClock(time.Date(year, month, day+1, 0, 0, 0, 0, loc), hours.start)
+				continue
+			case t.Before(dayStart):
+				t = dayStart
+				continue
+			}
+		}
+
+		// Busy intervals are merged and sorted, so skip those that end
+		// before t and check the first one left
+		for next < len(busy) && !busy[next].End.After(t) {
+			next++
+		}
+		if next < len(busy) && busy[next].Start.Before(t.Add(duration)) {
+			t = align(busy[next].End.In(loc), granularity)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// align rounds t up to a multiple of granularity after local midnight
+func align(t time.Time, granularity time.Duration) time.Time {
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	since := t.Sub(midnight)
+	if rem := since % granularity; rem != 0 {
+		return t.Add(granularity - rem)
+	}
+	return t
+}
+
+// busy reads calendars over [from, to) and returns each calendar's busy
+// time, rendered in loc with semantics, and all of it merged
+func (c *Client) busy(ctx context.Context, names []string, from, to time.Time, loc *time.Location, semantics string) ([]CalendarBusy, []Interval, error) {
+	type fetched struct {
+		calendar *Calendar
+		err      error
+	}
+	results := make([]fetched, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, src source) {
+			defer wg.Done()
+			results[i].calendar, results[i].err = c.fetch(ctx, src, from, to)
+		}(i, c.sources[name])
+	}
+	wg.Wait()
+
+	var all []Interval
+	perCalendar := make([]CalendarBusy, len(names))
+	for i, name := range names {
+		if results[i].err != nil {
+			return nil, nil, fmt.Errorf("calendar %s: %w", name, results[i].err)
+		}
+		intervals := results[i].calendar.Busy(from, to)
+		all = append(all, intervals...)
+		perCalendar[i] = CalendarBusy{Name: name, Busy: render(merge(clip(intervals, from, to)), loc, semantics), Skipped: results[i].calendar.Skipped}
+	}
+	return perCalendar, merge(clip(all, from, to)), nil
+}
+
+// selected validates calendar names, defaulting to all of them
+func (c *Client) selected(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return c.names, nil
+	}
+	seen := make(map[string]bool, len(names))
+	var unique []string
+	for _, name := range names {
+		if _, ok := c.sources[name]; !ok {
+			return nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "unknown calendar %q (configured: %s)", name, strings.Join(c.names, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique, nil
+}
+
+// fetch returns a source's calendar, from the cache while it is fresh
+func (c *Client) fetch(ctx context.Context, src source, from, to time.Time) (*Calendar, error) {
+	key := src.cfg.Name
+	if src.cfg.Type == config.CalendarTypeCalDAV {
+		key += "|" + from.UTC().Format(time.RFC3339) + "|" + to.UTC().Format(time.RFC3339)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.cfg.CacheTTL {
+		c.metrics.RecordCalendarFetch(src.cfg.Name, metrics.StatusCached)
+		return entry.calendar, nil
+	}
+
+	cal, err := c.download(ctx, src, from, to)
+	if err != nil {
+		c.metrics.RecordCalendarFetch(src.cfg.Name, metrics.StatusError)
+		c.logger.Warn("Calendar fetch failed", zap.String("calendar", src.cfg.Name), zap.Error(err))
+		return nil, err
+	}
+	c.metrics.RecordCalendarFetch(src.cfg.Name, metrics.StatusSuccess)
+	if cal.Skipped > 0 {
+		c.logger.Debug("Calendar events skipped", zap.String("calendar", src.cfg.Name), zap.Int("skipped", cal.Skipped))
+	}
+
+	if c.cfg.CacheTTL > 0 {
+		c.mu.Lock()
+		// Drop expired entries so per-range CalDAV keys don't pile up
+		for k, e := range c.cache {
+			if time.Since(e.fetchedAt) >= c.cfg.CacheTTL {
+				delete(c.cache, k)
+			}
+		}
+		c.cache[key] = cacheEntry{calendar: cal, fetchedAt: time.Now()}
+		c.mu.Unlock()
+	}
+	return cal, nil
+}
+
+// download fetches a calendar: a GET for an ICS feed, a free-busy-query
+// REPORT (RFC 4791 7.10) for a CalDAV collection
+func (c *Client) download(ctx context.Context, src source, from, to time.Time) (*Calendar, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	method, body := http.MethodGet, io.Reader(nil)
+	if src.cfg.Type == config.CalendarTypeCalDAV {
+		method = "REPORT"
+		body = strings.NewReader(fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<C:free-busy-query xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <C:time-range start="%s" end="%s"/>
+</C:free-busy-query>`, from.UTC().Format("20060102T150405Z"), to.UTC().Format("20060102T150405Z")))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, src.cfg.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+	req.Header.Set("User-Agent", c.userAgent)
+	if method == "REPORT" {
+		req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+		req.Header.Set("Depth", "1")
+	}
+	switch {
+	case src.cfg.Token != "":
+		req.Header.Set("Authorization", "Bearer "+src.cfg.Token)
+	case src.cfg.Username != "":
+		req.SetBasicAuth(src.cfg.Username, src.cfg.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFeedBytes {
+		return nil, fmt.Errorf("calendar exceeds %d bytes", maxFeedBytes)
+	}
+	return ParseICS(bytes.NewReader(data), src.loc, c.loadZone)
+}
+
+// location resolves the timezone results are rendered in
+func (c *Client) location(timezone string) (*time.Location, string, error) {
+	if timezone == "" {
+		timezone = c.defaultTimezone
+	}
+	loc, err := c.loadZone(timezone)
+	if err != nil {
+		return nil, "", timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	return loc, timezone, nil
+}
+
+// resolve reads an optional timestamp argument
+func (c *Client) resolve(field string, ts timeservice.Timestamp, fallback time.Time) (time.Time, error) {
+	if ts.IsZero() {
+		return fallback, nil
+	}
+	t, err := ts.Resolve()
+	if err != nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s: %w", field, err)
+	}
+	return t, nil
+}
+
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// parsePositiveDuration reads an optional Go duration argument; fallback
+// applies when it is empty, and a zero fallback makes it required
+func parsePositiveDuration(field, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		if fallback == 0 {
+			return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "%s is required", field)
+		}
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s: %w", field, err)
+	}
+	if d <= 0 {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "%s must be positive, got: %s", field, value)
+	}
+	return d, nil
+}
+
+// clip trims intervals to [from, to)
+func clip(intervals []Interval, from, to time.Time) []Interval {
+	clipped := make([]Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if iv.Start.Before(from) {
+			iv.Start = from
+		}
+		if iv.End.After(to) {
+			iv.End = to
+		}
+		if iv.End.After(iv.Start) {
+			clipped = append(clipped, iv)
+		}
+	}
+	return clipped
+}
+
+// merge sorts intervals and joins overlapping or touching ones
+func merge(intervals []Interval) []Interval {
+	sorted := append([]Interval(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	var merged []Interval
+	for _, iv := range sorted {
+		if n := len(merged); n > 0 && !iv.Start.After(merged[n-1].End) {
+			if iv.End.After(merged[n-1].End) {
+				merged[n-1].End = iv.End
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// complement returns the gaps between merged intervals within [from, to)
+func complement(merged []Interval, from, to time.Time) []Interval {
+	var free []Interval
+	cursor := from
+	for _, iv := range merged {
+		if iv.Start.After(cursor) {
+			free = append(free, Interval{Start: cursor, End: iv.Start})
+		}
+		if iv.End.After(cursor) {
+			cursor = iv.End
+		}
+	}
+	if to.After(cursor) {
+		free = append(free, Interval{Start: cursor, End: to})
+	}
+	return free
+}
+
+// render writes half-open intervals in loc, with ends written with semantics
+func render(intervals []Interval, loc *time.Location, semantics string) []Period {
+	periods := make([]Period, 0, len(intervals))
+	for _, iv := range intervals {
+		end := timeservice.ReportedEnd(iv.End, semantics, timeservice.IntervalSecond)
+		periods = append(periods, Period{Start: iv.Start.In(loc).Format(time.RFC3339), End: end.In(loc).Format(time.RFC3339)})
+	}
+	return periods
+}
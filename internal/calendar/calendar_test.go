@@ -0,0 +1,282 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+	"github.com/hspedro/mcp-server-time/internal/tzsnapshot"
+)
+
+// friday is 2026-10-16 08:00 UTC, a Friday
+var friday = time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+// calendarServer serves an ICS feed at /team.ics behind basic auth and a
+// CalDAV collection at /dav/room/ behind a bearer token
+func calendarServer(t *testing.T, fetches *atomic.Int32) *httptest.Server {
+	feed := ics(
+		"BEGIN:VEVENT",
+		"DTSTART:20261016T090000Z",
+		"DTEND:20261016T100000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20261016T093000Z",
+		"DTEND:20261016T103000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20261016T130000Z",
+		"DTEND:20261016T170000Z",
+		"END:VEVENT",
+	)
+	freeBusy := ics(
+		"BEGIN:VFREEBUSY",
+		"FREEBUSY:20261016T110000Z/20261016T120000Z,20261019T070000Z/20261019T080000Z",
+		"END:VFREEBUSY",
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		switch r.URL.Path {
+		case "/team.ics":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "bot" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = io.WriteString(w, feed)
+		case "/dav/room/":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != "REPORT" || r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("Depth") != "1" ||
+				!strings.Contains(string(body), "<C:time-range start=") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, freeBusy)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newClient(t *testing.T, url string, cacheTTL time.Duration) (*Client, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	client, err := New(config.CalendarConfig{
+		Timeout:  time.Second,
+		CacheTTL: cacheTTL,
+		Sources: []config.CalendarSourceConfig{
+			{Name: "team", Type: config.CalendarTypeICS, URL: url + "/team.ics", Username: "bot", Password: "secret"},
+			{Name: "room", Type: config.CalendarTypeCalDAV, URL: url + "/dav/room/", Token: "token"},
+			{Name: "broken", Type: config.CalendarTypeICS, URL: url + "/missing.ics"},
+		},
+	}, "UTC", timeservice.FixedClock{Time: friday}, nil, "test", m, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	return client, m
+}
+
+func TestClient_FreeBusy(t *testing.T) {
+	var fetches atomic.Int32
+	server := calendarServer(t, &fetches)
+	client, m := newClient(t, server.URL, time.Minute)
+	ctx := context.Background()
+
+	input := FreeBusyInput{
+		Calendars: []string{"team", "room"},
+		End:       timeservice.TimestampFromTime(friday.Add(10 * time.Hour)),
+		Timezone:  "Europe/Paris",
+	}
+	result, err := client.FreeBusy(ctx, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-10-16T10:00:00+02:00", result.Start)
+	assert.Equal(t, "2026-10-16T20:00:00+02:00", result.End)
+	require.Len(t, result.Calendars, 2)
+	assert.Equal(t, []Period{
+		{Start: "2026-10-16T11:00:00+02:00", End: "2026-10-16T12:30:00+02:00"},
+		{Start: "2026-10-16T15:00:00+02:00", End: "2026-10-16T19:00:00+02:00"},
+	}, result.Calendars[0].Busy)
+	assert.Equal(t, []Period{
+		{Start: "2026-10-16T11:00:00+02:00", End: "2026-10-16T12:30:00+02:00"},
+		{Start: "2026-10-16T13:00:00+02:00", End: "2026-10-16T14:00:00+02:00"},
+		{Start: "2026-10-16T15:00:00+02:00", End: "2026-10-16T19:00:00+02:00"},
+	}, result.Busy)
+	assert.Equal(t, []Period{
+		{Start: "2026-10-16T10:00:00+02:00", End: "2026-10-16T11:00:00+02:00"},
+		{Start: "2026-10-16T12:30:00+02:00", End: "2026-10-16T13:00:00+02:00"},
+		{Start: "2026-10-16T14:00:00+02:00", End: "2026-10-16T15:00:00+02:00"},
+		{Start: "2026-10-16T19:00:00+02:00", End: "2026-10-16T20:00:00+02:00"},
+	}, result.Free)
+
+	// A second read within the cache TTL doesn't fetch again
+	_, err = client.FreeBusy(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CalendarFetchesTotal.WithLabelValues("team", metrics.StatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CalendarFetchesTotal.WithLabelValues("team", metrics.StatusCached)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CalendarFetchesTotal.WithLabelValues("room", metrics.StatusCached)))
+
+	// Closed semantics read end as the last second and write ends that way
+	input.End = timeservice.TimestampFromTime(friday.Add(10*time.Hour - time.Second))
+	input.IntervalSemantics = timeservice.IntervalClosed
+	result, err = client.FreeBusy(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, timeservice.IntervalClosed, result.IntervalSemantics)
+	assert.Equal(t, "2026-10-16T19:59:59+02:00", result.End)
+	assert.Equal(t, Period{Start: "2026-10-16T11:00:00+02:00", End: "2026-10-16T12:29:59+02:00"}, result.Busy[0])
+	assert.Equal(t, Period{Start: "2026-10-16T19:00:00+02:00", End: "2026-10-16T19:59:59+02:00"}, result.Free[3])
+}
+
+func TestClient_FreeBusy_Errors(t *testing.T) {
+	var fetches atomic.Int32
+	server := calendarServer(t, &fetches)
+	client, m := newClient(t, server.URL, 0)
+	ctx := context.Background()
+
+	_, err := client.FreeBusy(ctx, FreeBusyInput{Calendars: []string{"payroll"}})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	assert.EqualError(t, err, `unknown calendar "payroll" (configured: team, room, broken)`)
+
+	_, err = client.FreeBusy(ctx, FreeBusyInput{End: timeservice.TimestampFromTime(friday.Add(-time.Hour))})
+	assert.EqualError(t, err, "end must be after start")
+
+	_, err = client.FreeBusy(ctx, FreeBusyInput{End: timeservice.TimestampFromTime(friday.Add(100 * 24 * time.Hour))})
+	assert.EqualError(t, err, "range cannot exceed 92 days")
+
+	_, err = client.FreeBusy(ctx, FreeBusyInput{Timezone: "Mars/Olympus"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidTimezone))
+
+	_, err = client.FreeBusy(ctx, FreeBusyInput{})
+	assert.EqualError(t, err, "calendar broken: unexpected status 404 Not Found")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CalendarFetchesTotal.WithLabelValues("broken", metrics.StatusError)))
+}
+
+// TestClient_ZoneLoader checks a client given a snapshot loader loads its
+// zones from the snapshot alone, so the host's Local zone is rejected
+func TestClient_ZoneLoader(t *testing.T) {
+	snapshot, err := tzsnapshot.Select(tzsnapshot.Latest)
+	require.NoError(t, err)
+	var loaded []string
+	loadZone := func(name string) (*time.Location, error) {
+		loaded = append(loaded, name)
+		return snapshot.LoadLocation(name)
+	}
+	newWith := func(timezone string) (*Client, error) {
+		prometheus.DefaultRegisterer = prometheus.NewRegistry()
+		return New(config.CalendarConfig{
+			Timeout: time.Second,
+			Sources: []config.CalendarSourceConfig{{Name: "team", Type: config.CalendarTypeICS, URL: "http://127.0.0.1/team.ics", Timezone: timezone}},
+		}, "UTC", timeservice.FixedClock{Time: friday}, loadZone, "test", metrics.New(), zaptest.NewLogger(t))
+	}
+
+	_, err = newWith("Local")
+	assert.ErrorContains(t, err, "calendar team:")
+	assert.ErrorContains(t, err, "has no Local")
+
+	client, err := newWith("Europe/Paris")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Local", "Europe/Paris"}, loaded)
+
+	_, err = client.FreeBusy(context.Background(), FreeBusyInput{Calendars: []string{"team"}, Timezone: "Local"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidTimezone))
+	assert.Equal(t, []string{"Local", "Europe/Paris", "Local"}, loaded)
+}
+
+func TestClient_NextFreeSlot(t *testing.T) {
+	var fetches atomic.Int32
+	server := calendarServer(t, &fetches)
+	client, _ := newClient(t, server.URL, time.Minute)
+	ctx := context.Background()
+	team := []string{"team", "room"}
+
+	tests := []struct {
+		name  string
+		input NextFreeSlotInput
+		want  NextFreeSlotResult
+	}{
+		{
+			name:  "first gap that fits",
+			input: NextFreeSlotInput{Calendars: team, Duration: "45m"},
+			want: NextFreeSlotResult{Found: true, Start: "2026-10-16T08:00:00Z", End: "2026-10-16T08:45:00Z",
+				SearchedUntil: "2026-10-23T08:00:00Z", Timezone: "UTC", IntervalSemantics: timeservice.IntervalHalfOpen, Calendars: team},
+		},
+		{
+			name:  "skips busy time",
+			input: NextFreeSlotInput{Calendars: team, Duration: "1h30m"},
+			want: NextFreeSlotResult{Found: true, Start: "2026-10-16T17:00:00Z", End: "2026-10-16T18:30:00Z",
+				SearchedUntil: "2026-10-23T08:00:00Z", Timezone: "UTC", IntervalSemantics: timeservice.IntervalHalfOpen, Calendars: team},
+		},
+		{
+			name: "working hours on weekdays roll over the weekend",
+			input: NextFreeSlotInput{
+				Calendars: team, Duration: "1h30m", Timezone: "Europe/Paris",
+				After:        timeservice.TimestampFromTime(friday.Add(2*time.Hour + 7*time.Minute)),
+				WorkingHours: &WorkingHours{Start: "09:00", End: "17:30", Weekdays: true},
+			},
+			want: NextFreeSlotResult{Found: true, Start: "2026-10-19T10:00:00+02:00", End: "2026-10-19T11:30:00+02:00",
+				SearchedUntil: "2026-10-23T12:07:00+02:00", Timezone: "Europe/Paris", IntervalSemantics: timeservice.IntervalHalfOpen, Calendars: team},
+		},
+		{
+			name:  "nothing within the search window",
+			input: NextFreeSlotInput{Calendars: []string{"team"}, Duration: "3h", Within: "6h", Granularity: "1h"},
+			want:  NextFreeSlotResult{SearchedUntil: "2026-10-16T14:00:00Z", Timezone: "UTC", IntervalSemantics: timeservice.IntervalHalfOpen, Calendars: []string{"team"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.NextFreeSlot(ctx, tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestClient_NextFreeSlot_Errors(t *testing.T) {
+	client, _ := newClient(t, "http://127.0.0.1:0", 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		input   NextFreeSlotInput
+		wantErr string
+	}{
+		{name: "missing duration", input: NextFreeSlotInput{}, wantErr: "duration is required"},
+		{name: "negative duration", input: NextFreeSlotInput{Duration: "-1h"}, wantErr: "duration must be positive, got: -1h"},
+		{name: "window too long", input: NextFreeSlotInput{Duration: "1h", Within: "2400h"}, wantErr: "within cannot exceed 92 days"},
+		{
+			name:    "malformed working hours",
+			input:   NextFreeSlotInput{Duration: "1h", WorkingHours: &WorkingHours{Start: "9am", End: "17:00"}},
+			wantErr: `invalid working_hours.start "9am": must be HH:MM`,
+		},
+		{
+			name:    "slot longer than working hours",
+			input:   NextFreeSlotInput{Duration: "9h", WorkingHours: &WorkingHours{Start: "09:00", End: "17:00"}},
+			wantErr: "duration 9h0m0s does not fit in working hours",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.NextFreeSlot(ctx, tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,454 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// maxOccurrences bounds the instances generated for one recurring event,
+// counting those before the queried range
+const maxOccurrences = 100000
+
+// Interval is a busy period, End exclusive
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Occurrence is one instance of an event, End exclusive
+type Occurrence struct {
+	Start   time.Time
+	End     time.Time
+	Summary string
+	// AllDay is set for events with DATE values, whose Start is midnight
+	AllDay bool
+}
+
+// Calendar is a parsed iCalendar (RFC 5545) document, reduced to what
+// free/busy and holiday imports need: events and VFREEBUSY periods
+type Calendar struct {
+	events   []*event
+	freeBusy []Interval
+	// Skipped counts events dropped for using recurrence rules or values
+	// this parser does not support
+	Skipped int
+}
+
+// event is a VEVENT, possibly recurring
+type event struct {
+	uid      string
+	summary  string
+	start    time.Time
+	duration time.Duration
+	allDay   bool
+	// transparent events don't make anyone busy, as with holidays
+	transparent bool
+	rule        *rule
+	// exdates are excluded occurrence starts, as Unix seconds
+	exdates map[int64]bool
+	// recurrenceID is set on an override of one occurrence of a series
+	recurrenceID time.Time
+}
+
+// property is a content line: NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// zones places the times of a document: floating and all-day times in loc,
+// and times with a TZID in the zone load finds
+type zones struct {
+	loc  *time.Location
+	load timeservice.ZoneLoader
+}
+
+// ParseICS parses an iCalendar document. Floating and all-day times are
+// placed in loc, as are times with a TZID that isn't a zone loadZone
+// finds. TZIDs load with time.LoadLocation when loadZone is nil
+func ParseICS(r io.Reader, loc *time.Location, loadZone timeservice.ZoneLoader) (*Calendar, error) {
+	if loadZone == nil {
+		loadZone = time.LoadLocation
+	}
+	z := zones{loc: loc, load: loadZone}
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(firstLine(lines), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar document")
+	}
+
+	cal := &Calendar{}
+	var stack []string
+	var props []property
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		switch prop.name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(prop.value))
+			if len(stack) == 2 {
+				props = props[:0]
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(prop.value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", n+1, prop.value)
+			}
+			if len(stack) == 2 {
+				cal.addComponent(stack[1], props, z)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		// Only properties of top-level components matter; nested ones,
+		// such as VALARM and VTIMEZONE parts, are ignored
+		if len(stack) == 2 {
+			props = append(props, prop)
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("unterminated %s", stack[len(stack)-1])
+	}
+	return cal, nil
+}
+
+// addComponent records a VEVENT or VFREEBUSY; other components are ignored
+func (c *Calendar) addComponent(name string, props []property, z zones) {
+	var err error
+	switch name {
+	case "VEVENT":
+		var ev *event
+		if ev, err = parseEvent(props, z); err == nil && ev != nil {
+			c.events = append(c.events, ev)
+		}
+	case "VFREEBUSY":
+		var periods []Interval
+		if periods, err = parseFreeBusy(props, z); err == nil {
+			c.freeBusy = append(c.freeBusy, periods...)
+		}
+	}
+	if err != nil {
+		c.Skipped++
+	}
+}
+
+// Busy returns the busy intervals overlapping [from, to), sorted by start
+// and not merged. Overrides of single occurrences replace the occurrence
+// their series would have produced
+func (c *Calendar) Busy(from, to time.Time) []Interval {
+	var busy []Interval
+	add := func(iv Interval) {
+		if iv.End.After(from) && iv.Start.Before(to) {
+			busy = append(busy, iv)
+		}
+	}
+	c.expand(to, func(ev *event, start time.Time) {
+		if !ev.transparent {
+			add(Interval{Start: start, End: start.Add(ev.duration)})
+		}
+	})
+	for _, iv := range c.freeBusy {
+		add(iv)
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+	return busy
+}
+
+// Occurrences returns the event instances overlapping [from, to), busy or
+// not, sorted by start
+func (c *Calendar) Occurrences(from, to time.Time) []Occurrence {
+	var occurrences []Occurrence
+	c.expand(to, func(ev *event, start time.Time) {
+		end := start.Add(ev.duration)
+		if ev.allDay {
+			// Keep whole days across DST changes
+			days := int(ev.duration / (24 * time.Hour))
+			end = start.AddDate(0, 0, max(days, 1))
+		}
+		if end.After(from) && start.Before(to) {
+			occurrences = append(occurrences, Occurrence{Start: start, End: end, Summary: ev.summary, AllDay: ev.allDay})
+		}
+	})
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Start.Before(occurrences[j].Start) })
+	return occurrences
+}
+
+// expand calls yield with each event instance starting before to
+func (c *Calendar) expand(to time.Time, yield func(ev *event, start time.Time)) {
+	overridden := make(map[string]map[int64]bool)
+	for _, ev := range c.events {
+		if !ev.recurrenceID.IsZero() {
+			if overridden[ev.uid] == nil {
+				overridden[ev.uid] = make(map[int64]bool)
+			}
+			overridden[ev.uid][ev.recurrenceID.Unix()] = true
+		}
+	}
+
+	for _, ev := range c.events {
+		if ev.rule == nil || !ev.recurrenceID.IsZero() {
+			yield(ev, ev.start)
+			continue
+		}
+		ev.rule.expand(ev.start, to, func(start time.Time) bool {
+			unix := start.Unix()
+			if !ev.exdates[unix] && !overridden[ev.uid][unix] {
+				yield(ev, start)
+			}
+			return true
+		})
+	}
+}
+
+// parseEvent reads a VEVENT. Cancelled events and instants don't happen
+// for anyone and return nil
+func parseEvent(props []property, z zones) (*event, error) {
+	ev := &event{exdates: make(map[int64]bool)}
+	var end time.Time
+	var hasDuration bool
+	for _, prop := range props {
+		var err error
+		switch prop.name {
+		case "UID":
+			ev.uid = prop.value
+		case "SUMMARY":
+			ev.summary = unescapeText(prop.value)
+		case "DTSTART":
+			ev.start, ev.allDay, err = parseDateTime(prop, z)
+		case "DTEND":
+			end, _, err = parseDateTime(prop, z)
+		case "DURATION":
+			ev.duration, err = parseDuration(prop.value)
+			hasDuration = true
+		case "RRULE":
+			ev.rule, err = parseRule(prop.value, z)
+		case "RDATE":
+			err = fmt.Errorf("RDATE is not supported")
+		case "EXDATE":
+			for _, value := range strings.Split(prop.value, ",") {
+				var t time.Time
+				if t, _, err = parseDateTime(property{params: prop.params, value: value}, z); err != nil {
+					break
+				}
+				ev.exdates[t.Unix()] = true
+			}
+		case "RECURRENCE-ID":
+			ev.recurrenceID, _, err = parseDateTime(prop, z)
+		case "TRANSP":
+			ev.transparent = strings.EqualFold(prop.value, "TRANSPARENT")
+		case "STATUS":
+			if strings.EqualFold(prop.value, "CANCELLED") {
+				return nil, nil
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", prop.name, err)
+		}
+	}
+
+	if ev.start.IsZero() {
+		return nil, fmt.Errorf("event without DTSTART")
+	}
+	switch {
+	case !end.IsZero():
+		ev.duration = end.Sub(ev.start)
+	case !hasDuration && ev.allDay:
+		ev.duration = 24 * time.Hour
+	}
+	if ev.duration <= 0 {
+		// An instant takes no time, so it never blocks a slot
+		return nil, nil
+	}
+	return ev, nil
+}
+
+// parseFreeBusy reads the busy FREEBUSY periods of a VFREEBUSY
+func parseFreeBusy(props []property, z zones) ([]Interval, error) {
+	var periods []Interval
+	for _, prop := range props {
+		if prop.name != "FREEBUSY" {
+			continue
+		}
+		if fbtype := strings.ToUpper(prop.params["FBTYPE"]); fbtype == "FREE" {
+			continue
+		}
+		for _, value := range strings.Split(prop.value, ",") {
+			startValue, endValue, ok := strings.Cut(value, "/")
+			if !ok {
+				return nil, fmt.Errorf("FREEBUSY period without end: %s", value)
+			}
+			start, _, err := parseDateTime(property{value: startValue}, z)
+			if err != nil {
+				return nil, err
+			}
+			var end time.Time
+			if strings.HasPrefix(endValue, "P") || strings.HasPrefix(endValue, "+P") {
+				d, err := parseDuration(endValue)
+				if err != nil {
+					return nil, err
+				}
+				end = start.Add(d)
+			} else if end, _, err = parseDateTime(property{value: endValue}, z); err != nil {
+				return nil, err
+			}
+			if end.After(start) {
+				periods = append(periods, Interval{Start: start, End: end})
+			}
+		}
+	}
+	return periods, nil
+}
+
+// parseDateTime reads a DATE or DATE-TIME value. It reports whether the
+// value was a date, which makes an all-day event
+func parseDateTime(prop property, z zones) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, z.loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	zone := z.loc
+	if tzid := strings.Trim(prop.params["TZID"], "/"); tzid != "" {
+		// Exchange and Outlook use Windows zone names, which the VTIMEZONE
+		// would define; fall back to the calendar's zone for those
+		if named, err := z.load(tzid); err == nil {
+			zone = named
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, zone)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads an RFC 5545 duration such as PT1H30M or P1D
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		d += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// unescapeText reads a TEXT value, undoing the escaping of backslashes,
+// commas, semicolons and newlines
+func unescapeText(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped && (r == 'n' || r == 'N'):
+			b.WriteByte('\n')
+		case escaped:
+			b.WriteRune(r)
+		case r == '\\':
+			escaped = true
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		escaped = false
+	}
+	return b.String()
+}
+
+// unfold reads content lines, joining continuation lines that start with
+// a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func firstLine(lines []string) string {
+	for _, line := range lines {
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// parseProperty splits a content line into its name, parameters and value.
+// Colons and semicolons inside quoted parameter values don't split
+func parseProperty(line string) (property, error) {
+	var parts []string
+	var value string
+	quoted, start := false, 0
+	found := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			parts = append(parts, line[start:i])
+			start = i + 1
+		case r == ':' && !quoted:
+			parts = append(parts, line[start:i])
+			value = line[i+1:]
+			found = true
+		}
+		if found {
+			break
+		}
+	}
+	if !found || parts[0] == "" {
+		return property{}, fmt.Errorf("malformed content line: %q", line)
+	}
+
+	prop := property{name: strings.ToUpper(parts[0]), value: value, params: make(map[string]string)}
+	for _, param := range parts[1:] {
+		name, paramValue, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(name)] = strings.Trim(paramValue, `"`)
+	}
+	return prop, nil
+}
@@ -0,0 +1,323 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/tzsnapshot"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func ics(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+// starts renders the busy interval starts in loc
+func starts(busy []Interval, loc *time.Location) []string {
+	var out []string
+	for _, iv := range busy {
+		out = append(out, iv.Start.In(loc).Format("2006-01-02 15:04"))
+	}
+	return out
+}
+
+func TestParseICS_Events(t *testing.T) {
+	paris := mustLoad(t, "Europe/Paris")
+	doc := ics(
+		"BEGIN:VEVENT",
+		"UID:utc",
+		"DTSTART:20261016T080000Z",
+		"DTEND:20261016T090000Z",
+		"SUMMARY:Standup with a folded",
+		"  summary",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:zoned",
+		"DTSTART;TZID=America/New_York:20261016T090000",
+		"DURATION:PT30M",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:all-day",
+		"DTSTART;VALUE=DATE:20261017",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:windows-zone",
+		`DTSTART;TZID="W. Europe Standard Time":20261018T100000`,
+		"DTEND;TZID=\"W. Europe Standard Time\":20261018T110000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:free",
+		"DTSTART:20261016T120000Z",
+		"DTEND:20261016T130000Z",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled",
+		"DTSTART:20261016T140000Z",
+		"DTEND:20261016T150000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:unsupported",
+		"DTSTART:20261016T160000Z",
+		"DTEND:20261016T170000Z",
+		"RRULE:FREQ=HOURLY",
+		"END:VEVENT",
+	)
+
+	cal, err := ParseICS(strings.NewReader(doc), paris, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cal.Skipped)
+
+	busy := cal.Busy(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	require.Len(t, busy, 4)
+	assert.Equal(t, []string{"2026-10-16 08:00", "2026-10-16 13:00", "2026-10-16 22:00", "2026-10-18 08:00"}, starts(busy, time.UTC))
+	assert.Equal(t, 30*time.Minute, busy[1].End.Sub(busy[1].Start))
+	// All-day events span the whole day in the calendar's zone
+	assert.Equal(t, 24*time.Hour, busy[2].End.Sub(busy[2].Start))
+}
+
+func TestParseICS_FreeBusy(t *testing.T) {
+	doc := ics(
+		"BEGIN:VFREEBUSY",
+		"DTSTART:20261016T000000Z",
+		"DTEND:20261017T000000Z",
+		"FREEBUSY;FBTYPE=BUSY:20261016T090000Z/20261016T100000Z,20261016T140000Z/PT1H30M",
+		"FREEBUSY;FBTYPE=BUSY-TENTATIVE:20261016T110000Z/20261016T113000Z",
+		"FREEBUSY;FBTYPE=FREE:20261016T120000Z/20261016T130000Z",
+		"END:VFREEBUSY",
+	)
+
+	cal, err := ParseICS(strings.NewReader(doc), time.UTC, nil)
+	require.NoError(t, err)
+	busy := cal.Busy(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, []string{"2026-10-16 09:00", "2026-10-16 11:00", "2026-10-16 14:00"}, starts(busy, time.UTC))
+	assert.Equal(t, time.Date(2026, 10, 16, 15, 30, 0, 0, time.UTC), busy[2].End)
+}
+
+// TestParseICS_ZoneLoader checks TZIDs load with the given loader, so a
+// snapshot loader places a TZID it lacks, such as Local, in the calendar's
+// zone rather than the host's
+func TestParseICS_ZoneLoader(t *testing.T) {
+	snapshot, err := tzsnapshot.Select(tzsnapshot.Latest)
+	require.NoError(t, err)
+	var loaded []string
+	loadZone := func(name string) (*time.Location, error) {
+		loaded = append(loaded, name)
+		return snapshot.LoadLocation(name)
+	}
+	doc := ics(
+		"BEGIN:VEVENT",
+		"UID:ny",
+		"DTSTART;TZID=America/New_York:20261016T090000",
+		"DURATION:PT1H",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:local",
+		"DTSTART;TZID=Local:20261016T090000",
+		"DURATION:PT1H",
+		"END:VEVENT",
+	)
+
+	cal, err := ParseICS(strings.NewReader(doc), time.UTC, loadZone)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"America/New_York", "Local"}, loaded)
+	busy := cal.Busy(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, []string{"2026-10-16 09:00", "2026-10-16 13:00"}, starts(busy, time.UTC))
+}
+
+func TestParseICS_Occurrences(t *testing.T) {
+	doc := ics(
+		"BEGIN:VEVENT",
+		"UID:new-year",
+		"DTSTART;VALUE=DATE:20260101",
+		"DTEND;VALUE=DATE:20260102",
+		"RRULE:FREQ=YEARLY",
+		"SUMMARY:New Year's Day",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:bridge",
+		"DTSTART;VALUE=DATE:20261224",
+		"DTEND;VALUE=DATE:20261227",
+		`SUMMARY:Christmas\, observed\; office closed`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:cancelled",
+		"DTSTART;VALUE=DATE:20261231",
+		"SUMMARY:Cancelled",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+	)
+
+	cal, err := ParseICS(strings.NewReader(doc), time.UTC, nil)
+	require.NoError(t, err)
+	from, to := time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	occurrences := cal.Occurrences(from, to)
+	require.Len(t, occurrences, 2)
+	assert.Equal(t, "Christmas, observed; office closed", occurrences[0].Summary)
+	assert.True(t, occurrences[0].AllDay)
+	assert.Equal(t, time.Date(2026, 12, 27, 0, 0, 0, 0, time.UTC), occurrences[0].End)
+	assert.Equal(t, "New Year's Day", occurrences[1].Summary)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), occurrences[1].Start)
+
+	// Transparent events are never busy
+	assert.Empty(t, cal.Busy(from, to))
+}
+
+func TestParseICS_Recurrence(t *testing.T) {
+	paris := mustLoad(t, "Europe/Paris")
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, paris)
+
+	tests := []struct {
+		name  string
+		lines []string
+		to    time.Time
+		want  []string
+	}{
+		{
+			name: "weekly by day keeps wall clock across DST",
+			lines: []string{
+				"DTSTART;TZID=Europe/Paris:20261019T093000",
+				"DTEND;TZID=Europe/Paris:20261019T100000",
+				"RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+			},
+			to:   time.Date(2026, 11, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-19 09:30", "2026-10-21 09:30", "2026-10-26 09:30", "2026-10-28 09:30"},
+		},
+		{
+			name: "daily with count and exdate",
+			lines: []string{
+				"DTSTART:20261005T080000Z",
+				"DURATION:PT15M",
+				"RRULE:FREQ=DAILY;COUNT=4",
+				"EXDATE:20261006T080000Z",
+			},
+			to:   time.Date(2026, 11, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-05 10:00", "2026-10-07 10:00", "2026-10-08 10:00"},
+		},
+		{
+			name: "daily until",
+			lines: []string{
+				"DTSTART;TZID=Europe/Paris:20261029T090000",
+				"DURATION:PT1H",
+				"RRULE:FREQ=DAILY;INTERVAL=2;UNTIL=20261102T235959Z",
+			},
+			to:   time.Date(2026, 12, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-29 09:00", "2026-10-31 09:00", "2026-11-02 09:00"},
+		},
+		{
+			name: "monthly last friday",
+			lines: []string{
+				"DTSTART;TZID=Europe/Paris:20260925T160000",
+				"DURATION:PT1H",
+				"RRULE:FREQ=MONTHLY;BYDAY=-1FR",
+			},
+			to:   time.Date(2027, 1, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-30 16:00", "2026-11-27 16:00", "2026-12-25 16:00"},
+		},
+		{
+			name: "monthly on the 31st skips short months",
+			lines: []string{
+				"DTSTART;TZID=Europe/Paris:20260831T120000",
+				"DURATION:PT1H",
+				"RRULE:FREQ=MONTHLY",
+			},
+			to:   time.Date(2027, 2, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-31 12:00", "2026-12-31 12:00", "2027-01-31 12:00"},
+		},
+		{
+			name: "yearly",
+			lines: []string{
+				"DTSTART;VALUE=DATE:20201016",
+				"RRULE:FREQ=YEARLY",
+			},
+			to:   time.Date(2027, 1, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-16 00:00"},
+		},
+		{
+			name: "overridden occurrence",
+			lines: []string{
+				"UID:series",
+				"DTSTART:20261001T080000Z",
+				"DURATION:PT1H",
+				"RRULE:FREQ=DAILY;COUNT=3",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:series",
+				"RECURRENCE-ID:20261002T080000Z",
+				"DTSTART:20261002T150000Z",
+				"DURATION:PT1H",
+			},
+			to:   time.Date(2026, 11, 1, 0, 0, 0, 0, paris),
+			want: []string{"2026-10-01 10:00", "2026-10-02 17:00", "2026-10-03 10:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VEVENT"}, tt.lines...)
+			lines = append(lines, "END:VEVENT")
+			cal, err := ParseICS(strings.NewReader(ics(lines...)), paris, nil)
+			require.NoError(t, err)
+			require.Zero(t, cal.Skipped)
+			assert.Equal(t, tt.want, starts(cal.Busy(from, tt.to), paris))
+		})
+	}
+}
+
+func TestParseICS_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{name: "not a calendar", doc: "<html></html>", wantErr: "not an iCalendar document"},
+		{name: "unterminated", doc: "BEGIN:VCALENDAR\nBEGIN:VEVENT\n", wantErr: "unterminated VEVENT"},
+		{name: "mismatched end", doc: "BEGIN:VCALENDAR\nEND:VEVENT\n", wantErr: "unexpected END:VEVENT"},
+		{name: "malformed line", doc: "BEGIN:VCALENDAR\nnonsense\nEND:VCALENDAR\n", wantErr: "malformed content line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseICS(strings.NewReader(tt.doc), time.UTC, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M":  90 * time.Minute,
+		"P1D":      24 * time.Hour,
+		"P1W":      7 * 24 * time.Hour,
+		"P1DT2H":   26 * time.Hour,
+		"-PT15M":   -15 * time.Minute,
+		"PT45S":    45 * time.Second,
+		"+PT1M30S": 90 * time.Second,
+	}
+	for value, want := range tests {
+		got, err := parseDuration(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"P", "PT", "1H", "P1H"} {
+		_, err := parseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
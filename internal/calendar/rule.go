@@ -0,0 +1,253 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+	freqYearly  = "YEARLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// byDay is a BYDAY entry: a weekday, with an ordinal within the month for
+// MONTHLY rules (1MO is the first Monday, -1FR the last Friday)
+type byDay struct {
+	weekday time.Weekday
+	ordinal int
+}
+
+// rule is the subset of RRULE (RFC 5545 3.3.10) calendars commonly use:
+// FREQ, INTERVAL, COUNT, UNTIL, WKST, BYDAY and, for MONTHLY rules,
+// BYMONTHDAY. Other parts make parseRule fail so the event is skipped
+// rather than expanded wrongly
+type rule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []byDay
+	byMonthDay []int
+}
+
+// parseRule reads an RRULE value
+func parseRule(value string, z zones) (*rule, error) {
+	r := &rule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		name, partValue, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.freq = strings.ToUpper(partValue)
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(partValue); err == nil && r.interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			if r.count, err = strconv.Atoi(partValue); err == nil && r.count < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "UNTIL":
+			r.until, _, err = parseDateTime(property{value: partValue}, z)
+		case "WKST":
+			// Only changes weekly rules with INTERVAL > 1 and BYDAY; Monday is assumed
+		case "BYDAY":
+			for _, day := range strings.Split(partValue, ",") {
+				var entry byDay
+				if entry, err = parseByDay(day); err != nil {
+					break
+				}
+				r.byDay = append(r.byDay, entry)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(partValue, ",") {
+				var n int
+				if n, err = strconv.Atoi(day); err == nil && (n == 0 || n < -31 || n > 31) {
+					err = fmt.Errorf("out of range: %d", n)
+				}
+				if err != nil {
+					break
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		default:
+			return nil, fmt.Errorf("RRULE %s is not supported", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("RRULE %s: %w", name, err)
+		}
+	}
+
+	switch r.freq {
+	case freqDaily, freqWeekly, freqMonthly, freqYearly:
+	default:
+		return nil, fmt.Errorf("RRULE FREQ %q is not supported", r.freq)
+	}
+	if len(r.byMonthDay) > 0 && r.freq != freqMonthly {
+		return nil, fmt.Errorf("RRULE BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	if len(r.byDay) > 0 && r.freq == freqYearly {
+		return nil, fmt.Errorf("RRULE BYDAY is not supported with FREQ=YEARLY")
+	}
+	for _, day := range r.byDay {
+		if day.ordinal != 0 && r.freq != freqMonthly {
+			return nil, fmt.Errorf("RRULE BYDAY ordinals are only supported with FREQ=MONTHLY")
+		}
+	}
+	return r, nil
+}
+
+func parseByDay(value string) (byDay, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return byDay{}, fmt.Errorf("invalid day: %s", value)
+	}
+	weekday, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return byDay{}, fmt.Errorf("invalid day: %s", value)
+	}
+	entry := byDay{weekday: weekday}
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return byDay{}, fmt.Errorf("invalid day: %s", value)
+		}
+		entry.ordinal = n
+	}
+	return entry, nil
+}
+
+// expand calls yield with each occurrence start from dtstart, in order,
+// until one starts at or after to, the rule ends or yield returns false.
+// Occurrences keep dtstart's wall clock time across DST changes
+func (r *rule) expand(dtstart, to time.Time, yield func(time.Time) bool) {
+	emitted := 0
+	// Periods are bounded too, as some never match, such as the 31st of
+	// every twelfth month from April
+	for period := 0; period < maxOccurrences; period++ {
+		for _, start := range r.period(dtstart, period) {
+			if start.Before(dtstart) {
+				continue
+			}
+			if !start.Before(to) || (!r.until.IsZero() && start.After(r.until)) {
+				return
+			}
+			emitted++
+			if !yield(start) || (r.count > 0 && emitted >= r.count) || emitted >= maxOccurrences {
+				return
+			}
+		}
+	}
+}
+
+// period returns the sorted candidate starts in the n-th period of the
+// rule, none for a period such as February for a rule on the 30th
+func (r *rule) period(dtstart time.Time, n int) []time.Time {
+	year, month, day := dtstart.Date()
+	hour, minute, second := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) (time.Time, bool) {
+		t := time.Date(y, m, d, hour, minute, second, 0, loc)
+		// Day overflow, such as April 31, is not a valid occurrence
+		return t, t.Day() == d
+	}
+
+	var candidates []time.Time
+	switch r.freq {
+	case freqDaily:
+		t := dtstart.AddDate(0, 0, n*r.interval)
+		if r.matchesWeekday(t.Weekday()) {
+			candidates = append(candidates, t)
+		}
+	case freqWeekly:
+		// Weeks start on Monday
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := time.Date(year, month, day-offset+7*n*r.interval, hour, minute, second, 0, loc)
+		if len(r.byDay) == 0 {
+			candidates = append(candidates, monday.AddDate(0, 0, offset))
+			break
+		}
+		for _, entry := range r.byDay {
+			candidates = append(candidates, monday.AddDate(0, 0, (int(entry.weekday)+6)%7))
+		}
+	case freqMonthly:
+		first := time.Date(year, month+time.Month(n*r.interval), 1, 0, 0, 0, 0, loc)
+		y, m := first.Year(), first.Month()
+		days := daysIn(y, m)
+		switch {
+		case len(r.byMonthDay) > 0:
+			for _, d := range r.byMonthDay {
+				if d < 0 {
+					d = days + d + 1
+				}
+				if t, ok := at(y, m, d); ok && d >= 1 && r.matchesWeekday(t.Weekday()) {
+					candidates = append(candidates, t)
+				}
+			}
+		case len(r.byDay) > 0:
+			for d := 1; d <= days; d++ {
+				t, _ := at(y, m, d)
+				if r.matchesMonthlyDay(t.Weekday(), d, days) {
+					candidates = append(candidates, t)
+				}
+			}
+		default:
+			if t, ok := at(y, m, day); ok {
+				candidates = append(candidates, t)
+			}
+		}
+	case freqYearly:
+		if t, ok := at(year+n*r.interval, month, day); ok {
+			candidates = append(candidates, t)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	return candidates
+}
+
+// matchesWeekday reports whether a weekday passes the BYDAY filter
+func (r *rule) matchesWeekday(weekday time.Weekday) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, entry := range r.byDay {
+		if entry.weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthlyDay reports whether day d of a month with days days
+// matches a BYDAY entry, honouring ordinals
+func (r *rule) matchesMonthlyDay(weekday time.Weekday, d, days int) bool {
+	for _, entry := range r.byDay {
+		if entry.weekday != weekday {
+			continue
+		}
+		switch {
+		case entry.ordinal == 0:
+			return true
+		case entry.ordinal > 0 && (d-1)/7+1 == entry.ordinal:
+			return true
+		case entry.ordinal < 0 && (days-d)/7+1 == -entry.ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
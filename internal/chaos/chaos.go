@@ -0,0 +1,117 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Injector injects delays, transient tool errors and dropped SSE connections
+// so MCP client authors can exercise their retry logic against a real server
+type Injector struct {
+	cfg     config.ChaosConfig
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// New creates a new fault injector. A zero seed uses the current time.
+func New(cfg config.ChaosConfig, metrics *metrics.Metrics, logger *zap.Logger) *Injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Injector{
+		cfg:     cfg,
+		metrics: metrics,
+		logger:  logger,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+// roll reports whether an event with probability p happens
+func (i *Injector) roll(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Float64() < p
+}
+
+// ToolMiddleware returns an MCP receiving middleware that injects faults into tool calls
+func (i *Injector) ToolMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			tool := callReq.Params.Name
+			faults := i.cfg.FaultsFor(tool)
+
+			if i.roll(faults.DelayProbability) {
+				i.logger.Debug("Injecting delay",
+					zap.String("tool", tool),
+					zap.Duration("delay", faults.Delay))
+				i.metrics.RecordChaosInjection(tool, metrics.FaultDelay)
+
+				select {
+				case <-time.After(faults.Delay):
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+
+			if i.roll(faults.ErrorProbability) {
+				i.logger.Debug("Injecting transient error", zap.String("tool", tool))
+				i.metrics.RecordChaosInjection(tool, metrics.FaultError)
+
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("chaos: injected transient error for tool %s, retry the request", tool)},
+					},
+					IsError: true,
+				}, nil
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// WrapSSE wraps an SSE handler so that new event streams are dropped after
+// chaos.sse_drop_after with probability chaos.sse_drop_probability
+func (i *Injector) WrapSSE(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !i.roll(i.cfg.SSEDropProbability) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		i.logger.Debug("Scheduling SSE connection drop",
+			zap.String("remote_addr", r.RemoteAddr),
+			zap.Duration("after", i.cfg.SSEDropAfter))
+		i.metrics.RecordChaosInjection(metrics.TransportSSE, metrics.FaultSSEDrop)
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		timer := time.AfterFunc(i.cfg.SSEDropAfter, cancel)
+		defer timer.Stop()
+
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,135 @@
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func newTestInjector(t *testing.T, cfg config.ChaosConfig) (*Injector, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	return New(cfg, m, zaptest.NewLogger(t)), m
+}
+
+func callTool(mw mcp.Middleware, tool string) (mcp.Result, bool, error) {
+	called := false
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		called = true
+		return &mcp.CallToolResult{}, nil
+	}
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: tool}}
+	res, err := mw(next)(context.Background(), "tools/call", req)
+	return res, called, err
+}
+
+func TestInjector_ToolMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        config.ChaosConfig
+		tool       string
+		wantError  bool
+		wantCalled bool
+	}{
+		{
+			name:       "no faults configured",
+			cfg:        config.ChaosConfig{Enabled: true, Seed: 1},
+			tool:       "get_time",
+			wantCalled: true,
+		},
+		{
+			name: "default error probability applies to all tools",
+			cfg: config.ChaosConfig{Enabled: true, Seed: 1,
+				Default: config.ChaosFaultConfig{ErrorProbability: 1}},
+			tool:      "get_time",
+			wantError: true,
+		},
+		{
+			name: "per-tool override takes precedence",
+			cfg: config.ChaosConfig{Enabled: true, Seed: 1,
+				Default: config.ChaosFaultConfig{ErrorProbability: 1},
+				Tools:   map[string]config.ChaosFaultConfig{"parse_time": {}}},
+			tool:       "parse_time",
+			wantCalled: true,
+		},
+		{
+			name: "delay then pass through",
+			cfg: config.ChaosConfig{Enabled: true, Seed: 1,
+				Tools: map[string]config.ChaosFaultConfig{"get_time": {DelayProbability: 1, Delay: time.Millisecond}}},
+			tool:       "get_time",
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector, _ := newTestInjector(t, tt.cfg)
+
+			res, called, err := callTool(injector.ToolMiddleware(), tt.tool)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCalled, called)
+			result, ok := res.(*mcp.CallToolResult)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantError, result.IsError)
+		})
+	}
+}
+
+func TestInjector_ToolMiddleware_RecordsMetrics(t *testing.T) {
+	injector, m := newTestInjector(t, config.ChaosConfig{Enabled: true, Seed: 1,
+		Default: config.ChaosFaultConfig{ErrorProbability: 1}})
+
+	_, _, _ = callTool(injector.ToolMiddleware(), "format_time")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ChaosInjectionsTotal.WithLabelValues("format_time", metrics.FaultError)))
+}
+
+func TestInjector_ToolMiddleware_IgnoresOtherMethods(t *testing.T) {
+	injector, _ := newTestInjector(t, config.ChaosConfig{Enabled: true, Seed: 1,
+		Default: config.ChaosFaultConfig{ErrorProbability: 1}})
+
+	called := false
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		called = true
+		return nil, nil
+	}
+	_, err := injector.ToolMiddleware()(next)(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestInjector_WrapSSE(t *testing.T) {
+	injector, m := newTestInjector(t, config.ChaosConfig{Enabled: true, Seed: 1,
+		SSEDropProbability: 1, SSEDropAfter: 10 * time.Millisecond})
+
+	handler := injector.WrapSSE(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sse", nil))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SSE connection was not dropped")
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ChaosInjectionsTotal.WithLabelValues(metrics.TransportSSE, metrics.FaultSSEDrop)))
+}
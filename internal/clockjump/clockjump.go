@@ -0,0 +1,180 @@
+// Package clockjump detects steps of the system wall clock, such as an NTP
+// step or a VM resuming from suspend, by comparing it with the monotonic
+// clock, which only moves forward at a steady rate. Jumps are logged,
+// counted and optionally sent to sessions, and tool calls that ran across
+// one are marked, since their answers may mix times from both sides.
+package clockjump
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Jump directions
+const (
+	DirectionForward  = "forward"
+	DirectionBackward = "backward"
+)
+
+// MetaKey is the _meta key of the jump a tool call ran across
+const MetaKey = "clock_jump"
+
+// notifyTimeout bounds sending a jump to each session
+const notifyTimeout = 5 * time.Second
+
+// Jump is a step of the wall clock against the monotonic clock
+type Jump struct {
+	// DetectedAt is the wall time, after the step, it was detected at
+	DetectedAt string `json:"detected_at"`
+	// OffsetSeconds is the size of the step, negative for a backward one
+	OffsetSeconds float64 `json:"offset_seconds"`
+	Direction     string  `json:"direction"`
+}
+
+// Detector compares the wall clock with the monotonic clock
+type Detector struct {
+	cfg     config.ClockJumpConfig
+	server  *mcp.Server
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+
+	// read returns the wall clock and the monotonic time since start
+	read      func() (time.Time, time.Duration)
+	startWall time.Time
+
+	mu sync.Mutex
+	// skew is how far the wall clock had moved from the monotonic clock
+	// since start at the last check
+	skew time.Duration
+}
+
+// New creates a detector. Jumps are sent to server's sessions when
+// cfg.Notify is set and server isn't nil
+func New(cfg config.ClockJumpConfig, server *mcp.Server, metrics *metrics.Metrics, logger *zap.Logger) *Detector {
+	start := time.Now()
+	return &Detector{
+		cfg:     cfg,
+		server:  server,
+		metrics: metrics,
+		logger:  logger,
+		read: func() (time.Time, time.Duration) {
+			now := time.Now()
+			return now.Round(0), now.Sub(start)
+		},
+		startWall: start.Round(0),
+	}
+}
+
+// Run compares the clocks every interval until ctx is done
+func (d *Detector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Check()
+		}
+	}
+}
+
+// Check compares the clocks, reporting a jump since the last check, and
+// returns the current skew between them
+func (d *Detector) Check() time.Duration {
+	d.mu.Lock()
+	wall, elapsed := d.read()
+	skew := wall.Sub(d.startWall) - elapsed
+	step := skew - d.skew
+	d.skew = skew
+	d.mu.Unlock()
+
+	if jump, ok := d.jump(wall, step); ok {
+		d.report(jump)
+	}
+	return skew
+}
+
+// jump describes step as a jump, if it is one
+func (d *Detector) jump(wall time.Time, step time.Duration) (Jump, bool) {
+	if step.Abs() < d.cfg.Threshold {
+		return Jump{}, false
+	}
+	direction := DirectionForward
+	if step < 0 {
+		direction = DirectionBackward
+	}
+	return Jump{
+		DetectedAt:    wall.UTC().Format(time.RFC3339Nano),
+		OffsetSeconds: step.Seconds(),
+		Direction:     direction,
+	}, true
+}
+
+// report logs and counts a jump, and sends it to sessions if configured
+func (d *Detector) report(jump Jump) {
+	d.logger.Warn("Wall clock jumped",
+		zap.String("direction", jump.Direction),
+		zap.Float64("offset_seconds", jump.OffsetSeconds),
+		zap.String("detected_at", jump.DetectedAt))
+	d.metrics.RecordClockJump(jump.Direction, jump.OffsetSeconds)
+
+	if !d.cfg.Notify || d.server == nil {
+		return
+	}
+	// Send in the background, so a slow session doesn't hold up the tool
+	// call that found the jump
+	go func() {
+		for session := range d.server.Sessions() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			err := session.Log(ctx, &mcp.LoggingMessageParams{Level: "warning", Logger: "clock", Data: jump})
+			cancel()
+			if err != nil {
+				d.logger.Debug("Failed to send clock jump", zap.String("session_id", session.ID()), zap.Error(err))
+			}
+		}
+	}()
+}
+
+// Middleware returns an MCP receiving middleware that checks the clocks
+// around every tool call. A call the wall clock stepped during gets the
+// jump in its result's _meta and a note in its content
+func (d *Detector) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			before := d.Check()
+			res, err := next(ctx, method, req)
+			after := d.Check()
+
+			callRes, ok := res.(*mcp.CallToolResult)
+			if err != nil || !ok || callRes == nil {
+				return res, err
+			}
+			jump, ok := d.jump(time.Now(), after-before)
+			if !ok {
+				return res, err
+			}
+			if callRes.Meta == nil {
+				callRes.Meta = mcp.Meta{}
+			}
+			callRes.Meta[MetaKey] = jump
+			callRes.Content = append(callRes.Content, &mcp.TextContent{Text: fmt.Sprintf(
+				"Note: the server's wall clock stepped %s by %.3fs during this call, so times in this answer may straddle the step",
+				jump.Direction, math.Abs(jump.OffsetSeconds))})
+			return res, err
+		}
+	}
+}
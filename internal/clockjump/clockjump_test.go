@@ -0,0 +1,136 @@
+package clockjump
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+var start = time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+// fakeClocks drives a detector's wall and monotonic clocks by hand
+type fakeClocks struct {
+	elapsed time.Duration
+	step    time.Duration // total wall-clock steps so far
+}
+
+func (c *fakeClocks) advance(d time.Duration) { c.elapsed += d }
+
+func (c *fakeClocks) jump(d time.Duration) { c.step += d }
+
+func newTestDetector(t *testing.T, server *mcp.Server, notify bool) (*Detector, *fakeClocks, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	cfg := config.ClockJumpConfig{Enabled: true, Interval: time.Second, Threshold: time.Second, Notify: notify}
+	d := New(cfg, server, m, zaptest.NewLogger(t))
+
+	clocks := &fakeClocks{}
+	d.startWall = start
+	d.read = func() (time.Time, time.Duration) {
+		return start.Add(clocks.elapsed + clocks.step), clocks.elapsed
+	}
+	return d, clocks, m
+}
+
+func TestDetector_Check(t *testing.T) {
+	d, clocks, m := newTestDetector(t, nil, false)
+
+	clocks.advance(time.Minute)
+	d.Check()
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.ClockJumpsTotal.WithLabelValues(DirectionForward)))
+
+	// Slewing below the threshold isn't a jump, and doesn't add up
+	for i := 0; i < 3; i++ {
+		clocks.jump(500 * time.Millisecond)
+		clocks.advance(time.Second)
+		d.Check()
+	}
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.ClockJumpsTotal.WithLabelValues(DirectionForward)))
+
+	// A VM resuming: the wall clock moved on while the monotonic one stood
+	clocks.jump(10 * time.Minute)
+	assert.Equal(t, 10*time.Minute+1500*time.Millisecond, d.Check())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ClockJumpsTotal.WithLabelValues(DirectionForward)))
+	assert.Equal(t, 600.0, testutil.ToFloat64(m.ClockJumpLastSeconds))
+
+	// An NTP step back is reported once
+	clocks.jump(-3 * time.Second)
+	d.Check()
+	d.Check()
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ClockJumpsTotal.WithLabelValues(DirectionBackward)))
+	assert.Equal(t, -3.0, testutil.ToFloat64(m.ClockJumpLastSeconds))
+}
+
+func TestDetector_Middleware(t *testing.T) {
+	d, clocks, _ := newTestDetector(t, nil, false)
+
+	call := func(during func()) *mcp.CallToolResult {
+		next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			during()
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+		}
+		res, err := d.Middleware()(next)(context.Background(), "tools/call", &mcp.CallToolRequest{})
+		require.NoError(t, err)
+		return res.(*mcp.CallToolResult)
+	}
+
+	// A jump before the call doesn't mark it
+	clocks.jump(time.Hour)
+	res := call(func() { clocks.advance(10 * time.Millisecond) })
+	assert.Nil(t, res.Meta)
+	assert.Len(t, res.Content, 1)
+
+	res = call(func() { clocks.jump(-2 * time.Second) })
+	require.Contains(t, res.Meta, MetaKey)
+	jump := res.Meta[MetaKey].(Jump)
+	assert.Equal(t, DirectionBackward, jump.Direction)
+	assert.Equal(t, -2.0, jump.OffsetSeconds)
+	require.Len(t, res.Content, 2)
+	assert.Equal(t, "Note: the server's wall clock stepped backward by 2.000s during this call, so times in this answer may straddle the step",
+		res.Content[1].(*mcp.TextContent).Text)
+}
+
+func TestDetector_Notify(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	d, clocks, _ := newTestDetector(t, server, true)
+
+	messages := make(chan *mcp.LoggingMessageParams, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "clockjump-test", Version: "test"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			messages <- req.Params
+		},
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	require.NoError(t, session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "warning"}))
+
+	clocks.jump(5 * time.Second)
+	d.Check()
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, mcp.LoggingLevel("warning"), msg.Level)
+		assert.Equal(t, "clock", msg.Logger)
+		data := msg.Data.(map[string]any)
+		assert.Equal(t, DirectionForward, data["direction"])
+		assert.Equal(t, 5.0, data["offset_seconds"])
+	case <-time.After(time.Second):
+		t.Fatal("clock jump was not sent")
+	}
+}
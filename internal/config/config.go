@@ -2,10 +2,21 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/hspedro/mcp-server-time/internal/features"
+	"github.com/hspedro/mcp-server-time/internal/tzsnapshot"
 )
 
 // Config represents the complete application configuration
@@ -14,6 +25,56 @@ type Config struct {
 	Time    TimeConfig    `mapstructure:"time"`
 	Logging LogConfig     `mapstructure:"logging"`
 	Metrics MetricsConfig `mapstructure:"metrics"`
+	Chaos   ChaosConfig   `mapstructure:"chaos"`
+	Replay  ReplayConfig  `mapstructure:"replay"`
+	// Determinism pins tzdata and the clock for byte-identical outputs
+	Determinism DeterminismConfig `mapstructure:"determinism"`
+	Updates     UpdatesConfig     `mapstructure:"updates"`
+	// Calendar holds the calendars the free/busy tools read
+	Calendar CalendarConfig `mapstructure:"calendar"`
+	// Holidays holds the public holiday feeds imported in the background
+	Holidays HolidaysConfig `mapstructure:"holidays"`
+	// Extensions are external programs serving extra tools
+	Extensions []ExtensionConfig `mapstructure:"extensions"`
+	// Features turns feature flags on by name, see internal/features
+	Features map[string]bool `mapstructure:"features"`
+	Compat   CompatConfig    `mapstructure:"compat"`
+	Limits   LimitsConfig    `mapstructure:"limits"`
+	// SLO tracks per-tool latency objectives from the server's own
+	// measurements
+	SLO SLOConfig `mapstructure:"slo"`
+	// Shed rejects low-priority tool calls while the server is overloaded
+	Shed ShedConfig `mapstructure:"shed"`
+	// ClockJump detects steps of the system wall clock
+	ClockJump ClockJumpConfig `mapstructure:"clock_jump"`
+	// SessionState bounds the state kept per MCP session
+	SessionState SessionStateConfig `mapstructure:"session_state"`
+	// ClientProfiles shape responses for the clients they match, first
+	// match wins
+	ClientProfiles []ClientProfileConfig `mapstructure:"client_profiles"`
+	// I18n selects the language of tool error messages
+	I18n I18nConfig `mapstructure:"i18n"`
+
+	// File is the config file that was read, empty when running on
+	// defaults and environment variables alone
+	File string `mapstructure:"-"`
+	// Environment is the MCP_ENV overlay applied, and Overlay its file
+	Environment string `mapstructure:"-"`
+	Overlay     string `mapstructure:"-"`
+	// Distroless is set when the config was loaded in distroless mode
+	Distroless bool `mapstructure:"-"`
+}
+
+// ConfigFileEnv names the environment variable pointing at a config file
+// when none is passed to Load
+const ConfigFileEnv = "MCP_CONFIG_FILE"
+
+// configFileTypes maps config file extensions to their format
+var configFileTypes = map[string]string{
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".json": "json",
+	".toml": "toml",
 }
 
 // ServerConfig contains HTTP server configuration
@@ -24,6 +85,72 @@ type ServerConfig struct {
 	Port                    int           `mapstructure:"port"`
 	GracefulShutdownTimeout time.Duration `mapstructure:"graceful_shutdown_timeout"`
 	ConnectionStaleTimeout  time.Duration `mapstructure:"connection_stale_timeout"`
+	// Affinity names this replica in a cookie on MCP responses, so a load
+	// balancer can keep each session on the replica that holds it
+	Affinity AffinityConfig `mapstructure:"affinity"`
+	// StreamBufferBytes caps the events kept per session for clients
+	// resuming a streamable HTTP stream with Last-Event-ID
+	StreamBufferBytes int `mapstructure:"stream_buffer_bytes"`
+	// StreamStore keeps streamable sessions and their events; a store the
+	// replicas share lets any of them resume a session
+	StreamStore StreamStoreConfig `mapstructure:"stream_store"`
+	// Heartbeat writes the server's time on open SSE streams, so clients
+	// can detect dead connections and clock drift
+	Heartbeat HeartbeatConfig `mapstructure:"heartbeat"`
+}
+
+// Heartbeat styles
+const (
+	// HeartbeatStyleComment writes heartbeats as SSE comments, which every
+	// client skips unless it reads the raw stream
+	HeartbeatStyleComment = "comment"
+	// HeartbeatStyleEvent writes heartbeats as events named heartbeat, for
+	// clients that dispatch on event names
+	HeartbeatStyleEvent = "event"
+)
+
+// HeartbeatConfig contains SSE heartbeat configuration
+type HeartbeatConfig struct {
+	// Interval between heartbeats on each stream; zero disables them
+	Interval time.Duration `mapstructure:"interval"`
+	Style    string        `mapstructure:"style"`
+}
+
+// Stream store backends
+const (
+	// StreamStoreMemory keeps sessions in the replica's memory
+	StreamStoreMemory = "memory"
+	// StreamStoreRedis keeps sessions in Redis, shared by the replicas
+	StreamStoreRedis = "redis"
+)
+
+// StreamStoreConfig contains the configuration of the store streamable
+// sessions and their events are kept in
+type StreamStoreConfig struct {
+	Backend string      `mapstructure:"backend"`
+	Redis   RedisConfig `mapstructure:"redis"`
+}
+
+// RedisConfig contains the connection to a Redis stream store
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	// KeyPrefix starts every key, so servers can share a database
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// TTL drops a session's keys once it has been idle this long
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// AffinityConfig contains session affinity configuration. Sessions live in
+// the memory of the replica that created them, so behind a load balancer
+// every request of a session must reach that replica
+type AffinityConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Cookie  string `mapstructure:"cookie"`
+	// Replica identifies this replica in the cookie; empty means the hostname
+	Replica string `mapstructure:"replica"`
 }
 
 // TimeConfig contains time service configuration
@@ -31,14 +158,165 @@ type TimeConfig struct {
 	DefaultTimezone  string   `mapstructure:"default_timezone"`
 	DefaultFormat    string   `mapstructure:"default_format"`
 	SupportedFormats []string `mapstructure:"supported_formats"`
+	// NegotiateFormat lets clients declare preferred output formats at
+	// initialize; the first supported one becomes the session default
+	NegotiateFormat bool `mapstructure:"negotiate_format"`
+	// MaxPrecision caps the precision of every instant in tool results
+	// (day, hour, minute, second, milli, micro or nano); empty means no cap
+	MaxPrecision string `mapstructure:"max_precision"`
+	// RFC3339 sets how RFC3339 timestamps in tool results are rendered
+	RFC3339    RFC3339Config    `mapstructure:"rfc3339"`
+	ValidRange ValidRangeConfig `mapstructure:"valid_range"`
+	// TwoDigitYearPivot expands "06" years: years below the pivot are in the
+	// 2000s, the rest in the 1900s. Go's own pivot is 69.
+	TwoDigitYearPivot int `mapstructure:"two_digit_year_pivot"`
+	// GregorianCutover is the first Gregorian date (YYYY-MM-DD); earlier
+	// dates are flagged and read as Julian with calendar=auto
+	GregorianCutover string `mapstructure:"gregorian_cutover"`
+	// PreloadTimezones are loaded and indexed at startup so first requests
+	// for popular zones skip cold tzdata reads and transition scans
+	PreloadTimezones []string `mapstructure:"preload_timezones"`
+	// DSTLookaheadDays is how far ahead timezone_info looks for the next
+	// transition; zones that suspend DST for years need more than a year.
+	// Zero uses the service default of 365
+	DSTLookaheadDays int `mapstructure:"dst_lookahead_days"`
+	// TZDataSources are extra tzdata releases diff_zone_rules can compare
+	// against the one the process loads zones from
+	TZDataSources []TZDataSourceConfig `mapstructure:"tzdata_sources"`
+	// OffsetSeconds sets how offsets with seconds, such as local mean time
+	// before standard time, are displayed: show writes ±HH:MM:SS, round the
+	// nearest ±HH:MM
+	OffsetSeconds string `mapstructure:"offset_seconds"`
+	// FormatShadow runs a new formatting engine next to the current one
+	FormatShadow FormatShadowConfig `mapstructure:"format_shadow"`
 }
 
+// Offset seconds constants
+const (
+	OffsetSecondsShow  = "show"
+	OffsetSecondsRound = "round"
+)
+
+// FormatShadowConfig renders a sample of formatted times again with a
+// candidate engine and reports where it diverges; responses are unchanged
+type FormatShadowConfig struct {
+	Engine     string  `mapstructure:"engine"` // empty disables shadowing
+	SampleRate float64 `mapstructure:"sample_rate"`
+}
+
+// Format shadow engine constants
+const (
+	FormatShadowEngineStrftime = "strftime"
+)
+
+// TZDataSourceConfig names a zoneinfo directory or zoneinfo.zip archive
+type TZDataSourceConfig struct {
+	Name string `mapstructure:"name"`
+	Path string `mapstructure:"path"`
+}
+
+// RFC3339Config sets the offset and fraction style of every RFC3339
+// timestamp in tool results, for downstream parsers strict about either
+type RFC3339Config struct {
+	UTCOffset string `mapstructure:"utc_offset"` // Z or +00:00 for a zero offset
+	// Fraction is auto (as each tool renders it), none, milli, micro or
+	// nano; the fixed styles always emit that many digits
+	Fraction string `mapstructure:"fraction"`
+}
+
+// Restyles reports whether timestamps are rendered differently from the
+// Go defaults tools produce
+func (c RFC3339Config) Restyles() bool {
+	return c.UTCOffset == "+00:00" || (c.Fraction != "" && c.Fraction != "auto")
+}
+
+// ValidRangeConfig bounds the years of timestamps accepted by parse_time and
+// format_time, catching epoch unit mistakes such as milliseconds read as seconds
+type ValidRangeConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	MinYear int    `mapstructure:"min_year"`
+	MaxYear int    `mapstructure:"max_year"`
+	Mode    string `mapstructure:"mode"` // flag or reject
+}
+
+// Valid range mode constants
+const (
+	ValidRangeModeFlag   = "flag"
+	ValidRangeModeReject = "reject"
+)
+
+// maxDSTLookaheadDays mirrors the time service's ten-year cap
+const maxDSTLookaheadDays = 3660
+
 // LogConfig contains logging configuration
 type LogConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level   string `mapstructure:"level"`
+	Format  string `mapstructure:"format"`
+	Backend string `mapstructure:"backend"` // zap or slog
+	// Sinks are the log destinations; empty means stderr only
+	Sinks []LogSinkConfig `mapstructure:"sinks"`
+	// ModuleLevels overrides Level for individual modules, e.g. {transport: warn}
+	ModuleLevels map[string]string `mapstructure:"module_levels"`
+	// Redaction hides sensitive values in logs and in tool errors sent to clients
+	Redaction RedactionConfig `mapstructure:"redaction"`
 }
 
+// RedactionConfig contains the rules for hiding sensitive values
+type RedactionConfig struct {
+	Fields   []string `mapstructure:"fields"`   // field names whose values are always hidden
+	Patterns []string `mapstructure:"patterns"` // regular expressions hidden wherever they match
+}
+
+// Log module names accepted in logging.module_levels
+const (
+	LogModuleTime      = "time"
+	LogModuleTools     = "tools"
+	LogModuleTransport = "transport"
+	LogModuleReplay    = "replay"
+	LogModuleChaos     = "chaos"
+	LogModuleEnvelope  = "envelope"
+	LogModuleRecovery  = "recovery"
+	LogModuleUpdates   = "updates"
+	LogModuleExtension = "extensions"
+	LogModuleCalendar  = "calendar"
+	LogModuleHolidays  = "holidays"
+	LogModuleSLO       = "slo"
+	LogModuleShed      = "shed"
+	LogModuleClock     = "clock"
+	LogModuleSession   = "session"
+)
+
+// LogSinkConfig contains one log destination
+type LogSinkConfig struct {
+	Type string `mapstructure:"type"` // stdout, stderr, file or syslog
+
+	// File sink: rotated once max_size_mb or max_age is reached (0 disables
+	// either limit), keeping max_backups rotated files (0 keeps all)
+	Path       string        `mapstructure:"path"`
+	MaxSizeMB  int           `mapstructure:"max_size_mb"`
+	MaxAge     time.Duration `mapstructure:"max_age"`
+	MaxBackups int           `mapstructure:"max_backups"`
+
+	// Syslog sink: an empty network and address use the local syslog daemon
+	Network string `mapstructure:"network"`
+	Address string `mapstructure:"address"`
+	Tag     string `mapstructure:"tag"`
+}
+
+// Log sink type constants
+const (
+	LogSinkStdout = "stdout"
+	LogSinkStderr = "stderr"
+	LogSinkFile   = "file"
+	LogSinkSyslog = "syslog"
+)
+
+// Log backend constants
+const (
+	LogBackendZap  = "zap"
+	LogBackendSlog = "slog"
+)
+
 // MetricsConfig contains Prometheus metrics configuration
 type MetricsConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
@@ -46,12 +324,373 @@ type MetricsConfig struct {
 	Path    string `mapstructure:"path"`
 }
 
-// Load reads configuration from file and environment variables
-func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
+// ChaosConfig contains fault-injection settings used to exercise client retry logic
+type ChaosConfig struct {
+	Enabled            bool                        `mapstructure:"enabled"`
+	Seed               int64                       `mapstructure:"seed"`
+	SSEDropProbability float64                     `mapstructure:"sse_drop_probability"`
+	SSEDropAfter       time.Duration               `mapstructure:"sse_drop_after"`
+	Default            ChaosFaultConfig            `mapstructure:"default"`
+	Tools              map[string]ChaosFaultConfig `mapstructure:"tools"`
+}
+
+// ChaosFaultConfig contains per-tool fault probabilities
+type ChaosFaultConfig struct {
+	DelayProbability float64       `mapstructure:"delay_probability"`
+	Delay            time.Duration `mapstructure:"delay"`
+	ErrorProbability float64       `mapstructure:"error_probability"`
+}
+
+// FaultsFor returns the fault settings for a tool, falling back to the default
+func (c *ChaosConfig) FaultsFor(tool string) ChaosFaultConfig {
+	if faults, ok := c.Tools[tool]; ok {
+		return faults
+	}
+	return c.Default
+}
+
+// ReplayConfig contains record-and-replay settings for deterministic agent tests
+type ReplayConfig struct {
+	Mode       string `mapstructure:"mode"`
+	File       string `mapstructure:"file"`
+	FrozenTime string `mapstructure:"frozen_time"`
+}
+
+// DeterminismConfig makes tool outputs independent of the host: zones load
+// from an embedded tzdata snapshot and the service clock is frozen. Builds
+// tagged deterministic enable it by default
+type DeterminismConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// TZData is the embedded snapshot's tzdata version, or latest
+	TZData string `mapstructure:"tzdata"`
+	// FrozenTime is the RFC3339 instant the clock is frozen at, unless
+	// replay freezes it
+	FrozenTime string `mapstructure:"frozen_time"`
+}
+
+// CompatConfig contains backward compatibility settings
+type CompatConfig struct {
+	// EmitLegacyFields also emits renamed result fields under their old
+	// names until the release each rename drops them in. Those releases
+	// are fixed in envelope.Renames, not configured
+	EmitLegacyFields bool `mapstructure:"emit_legacy_fields"`
+}
+
+// LimitsConfig bounds the size of requests, tool arguments and tool
+// results. Zero turns a limit off
+type LimitsConfig struct {
+	// MaxRequestBytes caps the body of an MCP HTTP request
+	MaxRequestBytes int64 `mapstructure:"max_request_bytes"`
+	// MaxStringLength caps string arguments, in characters
+	MaxStringLength int `mapstructure:"max_string_length"`
+	// MaxTextLength caps arguments holding documents, such as crontab
+	MaxTextLength int `mapstructure:"max_text_length"`
+	// MaxArrayItems caps array arguments of bulk tools
+	MaxArrayItems int `mapstructure:"max_array_items"`
+	// MaxResultBytes caps the encoded tool result
+	MaxResultBytes int `mapstructure:"max_result_bytes"`
+	// MaxBulkBytes caps the memory decoding a call's arguments takes, checked
+	// before they are decoded, and the memory a bulk tool estimates it needs,
+	// checked before it expands its input, as into empty buckets
+	MaxBulkBytes int64 `mapstructure:"max_bulk_bytes"`
+}
+
+// SLOConfig contains the per-tool latency objectives tracked over a
+// rolling window, and the webhook alerted when one is violated
+type SLOConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Window is the rolling window percentiles and burn rates cover
+	Window time.Duration `mapstructure:"window"`
+	// Interval is how often objectives are evaluated and metrics updated
+	Interval time.Duration `mapstructure:"interval"`
+	// MinCalls is the fewest calls in the window for a tool's objective to
+	// be judged, so one slow call on an idle tool doesn't alert
+	MinCalls int                           `mapstructure:"min_calls"`
+	Default  SLOObjectiveConfig            `mapstructure:"default"`
+	Tools    map[string]SLOObjectiveConfig `mapstructure:"tools"`
+	// AlertURL receives a JSON POST when a tool's burn rate reaches
+	// AlertBurnRate; empty sends no alerts
+	AlertURL      string        `mapstructure:"alert_url"`
+	AlertBurnRate float64       `mapstructure:"alert_burn_rate"`
+	AlertTimeout  time.Duration `mapstructure:"alert_timeout"`
+	// AlertCooldown is the least time between two alerts for one tool
+	AlertCooldown time.Duration `mapstructure:"alert_cooldown"`
+}
+
+// SLOObjectiveConfig is a latency objective: the share of calls that must
+// succeed within Latency
+type SLOObjectiveConfig struct {
+	Latency   time.Duration `mapstructure:"latency"`
+	Objective float64       `mapstructure:"objective"` // e.g. 0.99
+}
+
+// ObjectiveFor returns the objective for a tool, falling back to the default
+func (c *SLOConfig) ObjectiveFor(tool string) SLOObjectiveConfig {
+	if objective, ok := c.Tools[tool]; ok {
+		return objective
+	}
+	return c.Default
+}
+
+// Tool priorities for load shedding, from never shed to shed first
+const (
+	ShedPriorityCritical = "critical"
+	ShedPriorityHigh     = "high"
+	ShedPriorityNormal   = "normal"
+	ShedPriorityLow      = "low"
+)
+
+// ClockJumpConfig contains wall-clock jump detection: the wall clock is
+// compared with the monotonic clock, and a step between them, such as an
+// NTP step or a VM resuming, is logged, counted and marked on the tool
+// calls it happened during
+type ClockJumpConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is how often the clocks are compared between tool calls
+	Interval time.Duration `mapstructure:"interval"`
+	// Threshold is the smallest step reported as a jump
+	Threshold time.Duration `mapstructure:"threshold"`
+	// Notify sends each jump to connected sessions as an MCP log message
+	Notify bool `mapstructure:"notify"`
+}
+
+// SessionStateConfig bounds the state kept per MCP session, such as
+// negotiated defaults. Expired and cleared entries are soft-deleted, and
+// purged once they can no longer be restored
+type SessionStateConfig struct {
+	// TTL is how long an entry lives without being read or written
+	TTL time.Duration `mapstructure:"ttl"`
+	// PurgeAfter is how long soft-deleted entries can be restored
+	PurgeAfter time.Duration `mapstructure:"purge_after"`
+	// SweepInterval is how often entries are expired and purged, and the
+	// state of closed sessions dropped
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+	// MaxEntries caps the entries of one session, live or soft-deleted
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// ShedConfig contains adaptive load shedding: while calls in flight or the
+// recent p99 latency are over their thresholds, tools are rejected from the
+// lowest priority up, with a hint of when to retry
+type ShedConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxInFlight is the most tool calls running at once before shedding
+	// starts; 0 turns the check off
+	MaxInFlight int `mapstructure:"max_in_flight"`
+	// MaxP99 is the p99 latency across tools over P99Window before
+	// shedding starts; 0 turns the check off. Needs slo.enabled
+	MaxP99    time.Duration `mapstructure:"max_p99"`
+	P99Window time.Duration `mapstructure:"p99_window"`
+	// RetryAfter is the retry hint at the first shedding level, scaled up
+	// with the level
+	RetryAfter      time.Duration `mapstructure:"retry_after"`
+	DefaultPriority string        `mapstructure:"default_priority"`
+	// Priorities set a tool's priority by name. get_time is critical
+	// unless set here
+	Priorities map[string]string `mapstructure:"priorities"`
+}
+
+// UpdatesConfig contains the background check for newer server releases
+// and tzdata versions
+type UpdatesConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// ServerURL answers with the latest release, as a GitHub release JSON
+	// object (tag_name) or plain text; empty skips the server check
+	ServerURL string `mapstructure:"server_url"`
+	// TZDataURL answers with the latest tzdata version as plain text, e.g.
+	// 2025b; empty skips the tzdata check
+	TZDataURL string `mapstructure:"tzdata_url"`
+}
+
+// ExtensionConfig contains one external tool program. The program is run
+// once per request, reading a JSON request on stdin and writing a JSON
+// response on stdout
+type ExtensionConfig struct {
+	Name    string   `mapstructure:"name"`
+	Command []string `mapstructure:"command"` // program and arguments, not run through a shell
+	Dir     string   `mapstructure:"dir"`     // working directory, empty for the server's
+	// Env is the program's whole environment as KEY=VALUE entries; nothing
+	// else from the server's environment is passed on
+	Env []string `mapstructure:"env"`
+	// Timeout bounds each run; the program is killed when it expires
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxOutputBytes bounds the response read from stdout
+	MaxOutputBytes int `mapstructure:"max_output_bytes"`
+}
+
+// I18nConfig contains error message localization configuration
+type I18nConfig struct {
+	// DefaultLocale applies to sessions that declare no supported locale
+	DefaultLocale string `mapstructure:"default_locale"`
+}
+
+// Error message locales
+const (
+	LocaleEnglish      = "en"
+	LocalePortugueseBR = "pt-BR"
+	LocaleSpanish      = "es"
+)
+
+// Locales are the error message locales, in the order they are advertised
+var Locales = []string{LocaleEnglish, LocalePortugueseBR, LocaleSpanish}
+
+// ClientProfileConfig shapes responses for a client that mishandles parts
+// of them. It applies to sessions whose client declared Client as its name
+// at initialize and, with BeforeVersion, a lower version
+type ClientProfileConfig struct {
+	Name          string `mapstructure:"name"`
+	Client        string `mapstructure:"client"`
+	BeforeVersion string `mapstructure:"before_version"`
+	// DropStructuredContent removes structuredContent from tool results and
+	// output schemas from tools/list, leaving the text content
+	DropStructuredContent bool `mapstructure:"drop_structured_content"`
+	// DropAnnotations removes tool titles and annotations from tools/list
+	DropAnnotations bool `mapstructure:"drop_annotations"`
+}
+
+// versionPattern matches the dotted versions client profiles compare
+var versionPattern = regexp.MustCompile(`^v?\d+(\.\d+)*$`)
+
+// CalendarConfig contains the calendars read by the free/busy tools
+type CalendarConfig struct {
+	Timeout  time.Duration          `mapstructure:"timeout"`   // bounds each fetch
+	CacheTTL time.Duration          `mapstructure:"cache_ttl"` // how long a fetched calendar is reused, 0 to always fetch
+	Sources  []CalendarSourceConfig `mapstructure:"sources"`
+}
+
+// CalendarSourceConfig contains one calendar. An ics source is a feed
+// fetched whole; a caldav source is a calendar collection queried with a
+// free-busy REPORT for the requested range
+type CalendarSourceConfig struct {
+	Name     string `mapstructure:"name"`
+	Type     string `mapstructure:"type"`
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"` // HTTP basic auth, with password
+	Password string `mapstructure:"password"`
+	Token    string `mapstructure:"token"` // bearer token, instead of basic auth
+	// Timezone places floating and all-day times, defaults to time.default_timezone
+	Timezone string `mapstructure:"timezone"`
+}
+
+// HolidaysConfig contains the public holiday ICS feeds, such as Google's
+// public holiday calendars, synced in the background
+type HolidaysConfig struct {
+	Interval time.Duration `mapstructure:"interval"` // how often feeds are revalidated
+	Timeout  time.Duration `mapstructure:"timeout"`  // bounds each fetch
+	// CacheDir keeps the last copy of each feed, so holidays are served
+	// after a restart while a feed is unreachable; empty keeps them in memory
+	CacheDir string              `mapstructure:"cache_dir"`
+	Feeds    []HolidayFeedConfig `mapstructure:"feeds"`
+}
+
+// HolidayFeedConfig contains one holiday feed
+type HolidayFeedConfig struct {
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	// Timezone places timed events on a date, defaults to time.default_timezone
+	Timezone string `mapstructure:"timezone"`
+}
+
+// Calendar source types
+const (
+	CalendarTypeICS    = "ics"
+	CalendarTypeCalDAV = "caldav"
+)
+
+// Extension defaults applied when a setting is zero
+const (
+	DefaultExtensionTimeout        = 5 * time.Second
+	DefaultExtensionMaxOutputBytes = 1 << 20
+)
+
+// DefaultStreamBufferBytes applies when server.stream_buffer_bytes is zero
+const DefaultStreamBufferBytes = 1 << 20
+
+// Session state defaults, applying where session_state leaves a value zero
+const (
+	DefaultSessionStateTTL           = time.Hour
+	DefaultSessionStatePurgeAfter    = 10 * time.Minute
+	DefaultSessionStateSweepInterval = time.Minute
+	DefaultSessionStateMaxEntries    = 64
+)
+
+// namePattern keeps extension, calendar and holiday feed names usable as
+// metric labels
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ReplicaPattern keeps affinity replicas, hostnames included, usable as
+// cookie values without quoting
+var ReplicaPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Replay mode constants
+const (
+	ReplayModeOff    = "off"
+	ReplayModeRecord = "record"
+	ReplayModeReplay = "replay"
+)
+
+// LoadOptions controls how Load finds and checks the config file
+type LoadOptions struct {
+	// File names an explicit YAML, JSON or TOML config file, picked by
+	// extension; it must exist. Empty means MCP_CONFIG_FILE, then a lookup
+	// in ./ and ./config where running without a file is fine.
+	File string
+	// Strict rejects keys in the config file that no setting reads, such as
+	// time.default_timzone. MCP_CONFIG_STRICT=true enables it too.
+	Strict bool
+	// Distroless reads configuration from the environment alone, without
+	// looking for a config file, and rejects settings that need the
+	// filesystem at runtime. MCP_DISTROLESS=true enables it too.
+	Distroless bool
+}
+
+// Load reads configuration from file and environment variables.
+//
+// When MCP_ENV is set, config.<env>.yaml (matching the base file's name and
+// extension) is merged over the base file before anything is validated.
+// Settings take precedence in this order, highest first: MCP_<KEY>
+// environment variables, the overlay, the base file, and defaults.
+//
+// String values in the file may reference ${ENV} variables, and any setting
+// with a default can be read from a file through <key>_file or MCP_<KEY>_FILE.
+func Load(opts LoadOptions) (*Config, error) {
+	configFile := opts.File
+	if configFile == "" {
+		configFile = os.Getenv(ConfigFileEnv)
+	}
+	strict := opts.Strict
+	if !strict {
+		strict, _ = strconv.ParseBool(os.Getenv(ConfigStrictEnv))
+	}
+	distroless := opts.Distroless
+	if !distroless {
+		distroless, _ = strconv.ParseBool(os.Getenv(DistrolessEnv))
+	}
+
+	if distroless {
+		if configFile != "" {
+			return nil, fmt.Errorf("distroless mode reads no config file, got %s; set MCP_* variables instead", configFile)
+		}
+		if environment := os.Getenv(EnvironmentEnv); environment != "" {
+			return nil, fmt.Errorf("distroless mode reads no config overlay, got %s=%s", EnvironmentEnv, environment)
+		}
+	} else if configFile != "" {
+		configType, ok := configFileTypes[strings.ToLower(filepath.Ext(configFile))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported config file %s: extension must be .yaml, .yml, .json or .toml", configFile)
+		}
+		if _, err := os.Stat(configFile); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", configFile, err)
+		}
+		viper.SetConfigFile(configFile)
+		viper.SetConfigType(configType)
+	} else {
+		viper.SetConfigName("config")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("./config")
+	}
 
 	// Set environment variable prefix and replacement
 	viper.SetEnvPrefix("MCP")
@@ -59,42 +698,92 @@ func Load() (*Config, error) {
 	viper.AutomaticEnv()
 
 	// Set default values
-	setDefaults()
+	setDefaults(viper.GetViper())
+	known := viper.AllKeys()
+
+	// Read config file if available; distroless mode never looks for one
+	if !distroless {
+		if err := viper.ReadInConfig(); err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok || configFile != "" {
+				return nil, fmt.Errorf("error reading config file: %w", err)
+			}
+			// Config file not found is OK, we'll use defaults and env vars
+		}
+	}
+
+	sources := viper.ConfigFileUsed()
+	environment := os.Getenv(EnvironmentEnv)
+	var overlay string
+	if environment != "" {
+		var err error
+		if overlay, err = mergeOverlay(environment); err != nil {
+			return nil, err
+		}
+		sources += " + " + overlay
+	}
 
-	// Read config file if available
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("error reading config file: %w", err)
+	if strict {
+		if unknown := unknownKeys(); len(unknown) > 0 {
+			return nil, fmt.Errorf("unknown config keys in %s: %s", sources, strings.Join(unknown, ", "))
 		}
-		// Config file not found is OK, we'll use defaults and env vars
+	}
+
+	// Expand ${ENV} references first so _file paths can use them too
+	if err := interpolateEnv(); err != nil {
+		return nil, fmt.Errorf("error interpolating config: %w", err)
+	}
+	if err := resolveFileRefs(known, distroless); err != nil {
+		return nil, fmt.Errorf("error reading config secrets: %w", err)
 	}
 
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	config.File = viper.ConfigFileUsed()
+	config.Environment = environment
+	config.Overlay = overlay
+	config.Distroless = distroless
 
 	if err := validate(&config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
+	if distroless {
+		if err := validateDistroless(&config); err != nil {
+			return nil, err
+		}
+	}
 
 	return &config, nil
 }
 
-// setDefaults sets default configuration values
-func setDefaults() {
+// setDefaults sets default configuration values on v
+func setDefaults(v *viper.Viper) {
 	// Server defaults
-	viper.SetDefault("server.name", "mcp-server-time")
-	viper.SetDefault("server.version", "1.0.0")
-	viper.SetDefault("server.host", "localhost")
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("server.graceful_shutdown_timeout", "1s")
-	viper.SetDefault("server.connection_stale_timeout", "2m")
+	v.SetDefault("server.name", "mcp-server-time")
+	v.SetDefault("server.version", "1.0.0")
+	v.SetDefault("server.host", "localhost")
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.graceful_shutdown_timeout", "1s")
+	v.SetDefault("server.connection_stale_timeout", "2m")
+	v.SetDefault("server.stream_buffer_bytes", DefaultStreamBufferBytes)
+	v.SetDefault("server.stream_store.backend", StreamStoreMemory)
+	v.SetDefault("server.stream_store.redis.addr", "localhost:6379")
+	v.SetDefault("server.stream_store.redis.username", "")
+	v.SetDefault("server.stream_store.redis.password", "")
+	v.SetDefault("server.stream_store.redis.db", 0)
+	v.SetDefault("server.stream_store.redis.key_prefix", "mcp-server-time:")
+	v.SetDefault("server.stream_store.redis.ttl", "1h")
+	v.SetDefault("server.affinity.enabled", false)
+	v.SetDefault("server.affinity.cookie", "mcp_replica")
+	v.SetDefault("server.affinity.replica", "")
+	v.SetDefault("server.heartbeat.interval", "0s")
+	v.SetDefault("server.heartbeat.style", HeartbeatStyleComment)
 
 	// Time service defaults
-	viper.SetDefault("time.default_timezone", "UTC")
-	viper.SetDefault("time.default_format", "RFC3339")
-	viper.SetDefault("time.supported_formats", []string{
+	v.SetDefault("time.default_timezone", "UTC")
+	v.SetDefault("time.default_format", "RFC3339")
+	v.SetDefault("time.supported_formats", []string{
 		"RFC3339",
 		"RFC3339Nano",
 		"Unix",
@@ -102,16 +791,132 @@ func setDefaults() {
 		"UnixMicro",
 		"UnixNano",
 		"Layout",
+		"EpochDay",
+		"Ordinal",
+		"RataDie",
+		"MySQLDateTime",
+		"PostgresTimestamptz",
+		"JulianDay",
+		"GPSSeconds",
+		"GPSWeek",
+		"TLEEpoch",
+		"SwatchBeats",
+		"DecimalTime",
+		"DayKiloseconds",
 	})
+	v.SetDefault("time.negotiate_format", true)
+	v.SetDefault("time.max_precision", "")
+	v.SetDefault("time.rfc3339.utc_offset", "Z")
+	v.SetDefault("time.rfc3339.fraction", "auto")
+	v.SetDefault("time.two_digit_year_pivot", 69)
+	v.SetDefault("time.offset_seconds", OffsetSecondsShow)
+	v.SetDefault("time.gregorian_cutover", "1582-10-15")
+	v.SetDefault("time.preload_timezones", []string{})
+	v.SetDefault("time.dst_lookahead_days", 365)
+	v.SetDefault("time.tzdata_sources", []map[string]any{})
+	v.SetDefault("time.valid_range.enabled", false)
+	v.SetDefault("time.valid_range.min_year", 1900)
+	v.SetDefault("time.valid_range.max_year", 2100)
+	v.SetDefault("time.valid_range.mode", ValidRangeModeFlag)
+	v.SetDefault("time.format_shadow.engine", "")
+	v.SetDefault("time.format_shadow.sample_rate", 1.0)
 
 	// Logging defaults
-	viper.SetDefault("logging.level", "info")
-	viper.SetDefault("logging.format", "json")
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.format", "json")
+	v.SetDefault("logging.backend", LogBackendZap)
 
 	// Metrics defaults
-	viper.SetDefault("metrics.enabled", true)
-	viper.SetDefault("metrics.port", 9080)
-	viper.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.port", 9080)
+	v.SetDefault("metrics.path", "/metrics")
+
+	// Chaos defaults
+	v.SetDefault("chaos.enabled", false)
+	v.SetDefault("chaos.seed", 0)
+	v.SetDefault("chaos.sse_drop_probability", 0.0)
+	v.SetDefault("chaos.sse_drop_after", "5s")
+
+	// Replay defaults
+	v.SetDefault("replay.mode", ReplayModeOff)
+	v.SetDefault("replay.file", "recording.jsonl")
+	v.SetDefault("replay.frozen_time", "")
+
+	// Determinism defaults
+	v.SetDefault("determinism.enabled", deterministicBuild)
+	v.SetDefault("determinism.tzdata", tzsnapshot.Latest)
+	v.SetDefault("determinism.frozen_time", "2026-01-01T00:00:00Z")
+
+	// Update check defaults
+	v.SetDefault("updates.enabled", false)
+	v.SetDefault("updates.interval", "24h")
+	v.SetDefault("updates.timeout", "10s")
+	v.SetDefault("updates.server_url", "https://api.github.com/repos/hspedro/mcp-server-time/releases/latest")
+	v.SetDefault("updates.tzdata_url", "https://data.iana.org/time-zones/tzdb/version")
+
+	// Calendar defaults
+	v.SetDefault("calendar.timeout", "10s")
+	v.SetDefault("calendar.cache_ttl", "5m")
+	v.SetDefault("calendar.sources", []map[string]any{})
+
+	// Holiday feed defaults
+	v.SetDefault("holidays.interval", "24h")
+	v.SetDefault("holidays.timeout", "10s")
+	v.SetDefault("holidays.cache_dir", "")
+	v.SetDefault("holidays.feeds", []map[string]any{})
+
+	// Extension defaults
+	v.SetDefault("extensions", []map[string]any{})
+	v.SetDefault("client_profiles", []map[string]any{})
+	v.SetDefault("i18n.default_locale", LocaleEnglish)
+
+	// Size limits
+	v.SetDefault("limits.max_request_bytes", 1<<20)
+	v.SetDefault("limits.max_string_length", 256)
+	v.SetDefault("limits.max_text_length", 64<<10)
+	v.SetDefault("limits.max_array_items", 10000)
+	v.SetDefault("limits.max_result_bytes", 4<<20)
+	v.SetDefault("limits.max_bulk_bytes", 64<<20)
+
+	// SLO tracking defaults, paging on the fast burn of a 99% objective
+	v.SetDefault("slo.enabled", false)
+	v.SetDefault("slo.window", "1h")
+	v.SetDefault("slo.interval", "30s")
+	v.SetDefault("slo.min_calls", 20)
+	v.SetDefault("slo.default.latency", "100ms")
+	v.SetDefault("slo.default.objective", 0.99)
+	v.SetDefault("slo.alert_url", "")
+	v.SetDefault("slo.alert_burn_rate", 14.4)
+	v.SetDefault("slo.alert_timeout", "10s")
+	v.SetDefault("slo.alert_cooldown", "1h")
+
+	// Load shedding defaults
+	v.SetDefault("shed.enabled", false)
+	v.SetDefault("shed.max_in_flight", 256)
+	v.SetDefault("shed.max_p99", "0s")
+	v.SetDefault("shed.p99_window", "1m")
+	v.SetDefault("shed.retry_after", "5s")
+	v.SetDefault("shed.default_priority", ShedPriorityNormal)
+
+	// Clock jump detection defaults
+	v.SetDefault("clock_jump.enabled", true)
+	v.SetDefault("clock_jump.interval", "5s")
+	v.SetDefault("clock_jump.threshold", "1s")
+	v.SetDefault("clock_jump.notify", false)
+
+	// Session state defaults
+	v.SetDefault("session_state.ttl", DefaultSessionStateTTL)
+	v.SetDefault("session_state.purge_after", DefaultSessionStatePurgeAfter)
+	v.SetDefault("session_state.sweep_interval", DefaultSessionStateSweepInterval)
+	v.SetDefault("session_state.max_entries", DefaultSessionStateMaxEntries)
+
+	// Renamed result fields keep their old names until removed
+	v.SetDefault("compat.emit_legacy_fields", true)
+
+	// Feature flags ship dark
+	for _, flag := range features.Known {
+		v.SetDefault("features."+string(flag), false)
+	}
 }
 
 // validate checks configuration for required values and consistency
@@ -125,6 +930,24 @@ func validate(config *Config) error {
 		return fmt.Errorf("server.host cannot be empty")
 	}
 
+	if config.Server.StreamBufferBytes < 0 {
+		return fmt.Errorf("server.stream_buffer_bytes cannot be negative, got: %d", config.Server.StreamBufferBytes)
+	}
+
+	if err := validateStreamStore(config.Server.StreamStore); err != nil {
+		return err
+	}
+
+	if config.Server.Affinity.Enabled {
+		if err := validateAffinity(config.Server.Affinity); err != nil {
+			return err
+		}
+	}
+
+	if err := validateHeartbeat(config.Server.Heartbeat); err != nil {
+		return err
+	}
+
 	// Validate time configuration
 	if config.Time.DefaultTimezone == "" {
 		return fmt.Errorf("time.default_timezone cannot be empty")
@@ -144,6 +967,83 @@ func validate(config *Config) error {
 		return fmt.Errorf("time.supported_formats cannot be empty")
 	}
 
+	validPrecisions := map[string]bool{
+		"": true, "day": true, "hour": true, "minute": true, "second": true, "milli": true, "micro": true, "nano": true,
+	}
+	if !validPrecisions[config.Time.MaxPrecision] {
+		return fmt.Errorf("invalid time.max_precision: %s (must be one of: day, hour, minute, second, milli, micro, nano)", config.Time.MaxPrecision)
+	}
+
+	if offset := config.Time.RFC3339.UTCOffset; offset != "" && offset != "Z" && offset != "+00:00" {
+		return fmt.Errorf("invalid time.rfc3339.utc_offset: %s (must be one of: Z, +00:00)", offset)
+	}
+	validFractions := map[string]bool{
+		"": true, "auto": true, "none": true, "milli": true, "micro": true, "nano": true,
+	}
+	if !validFractions[config.Time.RFC3339.Fraction] {
+		return fmt.Errorf("invalid time.rfc3339.fraction: %s (must be one of: auto, none, milli, micro, nano)", config.Time.RFC3339.Fraction)
+	}
+
+	if mode := config.Time.OffsetSeconds; mode != "" && mode != OffsetSecondsShow && mode != OffsetSecondsRound {
+		return fmt.Errorf("invalid time.offset_seconds: %s (must be one of: %s, %s)", mode, OffsetSecondsShow, OffsetSecondsRound)
+	}
+
+	if config.Time.TwoDigitYearPivot < 0 || config.Time.TwoDigitYearPivot > 100 {
+		return fmt.Errorf("time.two_digit_year_pivot must be between 0 and 100, got: %d", config.Time.TwoDigitYearPivot)
+	}
+
+	if config.Time.GregorianCutover != "" {
+		if _, err := time.Parse(time.DateOnly, config.Time.GregorianCutover); err != nil {
+			return fmt.Errorf("invalid time.gregorian_cutover %s (must be YYYY-MM-DD): %w", config.Time.GregorianCutover, err)
+		}
+	}
+
+	if config.Time.DSTLookaheadDays < 0 || config.Time.DSTLookaheadDays > maxDSTLookaheadDays {
+		return fmt.Errorf("time.dst_lookahead_days must be between 0 (default) and %d, got: %d", maxDSTLookaheadDays, config.Time.DSTLookaheadDays)
+	}
+
+	sourceNames := make(map[string]bool)
+	for i, source := range config.Time.TZDataSources {
+		if source.Name == "" || source.Path == "" {
+			return fmt.Errorf("time.tzdata_sources[%d] needs a name and a path", i)
+		}
+		if source.Name == "default" {
+			return fmt.Errorf("time.tzdata_sources[%d]: the name default is reserved for the process tzdata", i)
+		}
+		if sourceNames[source.Name] {
+			return fmt.Errorf("duplicate time.tzdata_sources name %s", source.Name)
+		}
+		sourceNames[source.Name] = true
+		if _, err := os.Stat(source.Path); err != nil {
+			return fmt.Errorf("invalid time.tzdata_sources path for %s: %w", source.Name, err)
+		}
+	}
+
+	for _, zone := range config.Time.PreloadTimezones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			return fmt.Errorf("invalid time.preload_timezones entry %s: %w", zone, err)
+		}
+	}
+
+	if config.Time.ValidRange.Enabled {
+		if config.Time.ValidRange.MinYear > config.Time.ValidRange.MaxYear {
+			return fmt.Errorf("time.valid_range.min_year (%d) cannot be after time.valid_range.max_year (%d)",
+				config.Time.ValidRange.MinYear, config.Time.ValidRange.MaxYear)
+		}
+		if config.Time.ValidRange.Mode != ValidRangeModeFlag && config.Time.ValidRange.Mode != ValidRangeModeReject {
+			return fmt.Errorf("invalid time.valid_range.mode: %s (must be one of: flag, reject)", config.Time.ValidRange.Mode)
+		}
+	}
+
+	if shadow := config.Time.FormatShadow; shadow.Engine != "" {
+		if shadow.Engine != FormatShadowEngineStrftime {
+			return fmt.Errorf("invalid time.format_shadow.engine: %s (must be one of: %s)", shadow.Engine, FormatShadowEngineStrftime)
+		}
+		if shadow.SampleRate <= 0 || shadow.SampleRate > 1 {
+			return fmt.Errorf("time.format_shadow.sample_rate must be in (0, 1], got: %g", shadow.SampleRate)
+		}
+	}
+
 	// Validate logging configuration
 	validLogLevels := map[string]bool{
 		"debug": true, "info": true, "warn": true, "error": true, "fatal": true,
@@ -152,6 +1052,21 @@ func validate(config *Config) error {
 		return fmt.Errorf("invalid logging.level: %s (must be one of: debug, info, warn, error, fatal)", config.Logging.Level)
 	}
 
+	validLogModules := map[string]bool{
+		LogModuleTime: true, LogModuleTools: true, LogModuleTransport: true, LogModuleReplay: true,
+		LogModuleChaos: true, LogModuleEnvelope: true, LogModuleRecovery: true, LogModuleUpdates: true,
+		LogModuleExtension: true, LogModuleCalendar: true, LogModuleHolidays: true, LogModuleSLO: true,
+		LogModuleShed: true, LogModuleClock: true, LogModuleSession: true,
+	}
+	for module, level := range config.Logging.ModuleLevels {
+		if !validLogModules[module] {
+			return fmt.Errorf("invalid logging.module_levels module: %s (must be one of: %s)", module, strings.Join(slices.Sorted(maps.Keys(validLogModules)), ", "))
+		}
+		if !validLogLevels[level] {
+			return fmt.Errorf("invalid logging.module_levels.%s: %s (must be one of: debug, info, warn, error, fatal)", module, level)
+		}
+	}
+
 	validLogFormats := map[string]bool{
 		"json": true, "console": true,
 	}
@@ -159,6 +1074,22 @@ func validate(config *Config) error {
 		return fmt.Errorf("invalid logging.format: %s (must be one of: json, console)", config.Logging.Format)
 	}
 
+	if config.Logging.Backend != LogBackendZap && config.Logging.Backend != LogBackendSlog {
+		return fmt.Errorf("invalid logging.backend: %s (must be one of: zap, slog)", config.Logging.Backend)
+	}
+
+	for i, pattern := range config.Logging.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid logging.redaction.patterns[%d]: %w", i, err)
+		}
+	}
+
+	for i, sink := range config.Logging.Sinks {
+		if err := validateLogSink(fmt.Sprintf("logging.sinks[%d]", i), sink); err != nil {
+			return err
+		}
+	}
+
 	// Validate metrics configuration
 	if config.Metrics.Enabled {
 		if config.Metrics.Port <= 0 || config.Metrics.Port > 65535 {
@@ -178,6 +1109,469 @@ func validate(config *Config) error {
 		}
 	}
 
+	// Validate chaos configuration
+	if config.Chaos.Enabled {
+		if err := validateProbability("chaos.sse_drop_probability", config.Chaos.SSEDropProbability); err != nil {
+			return err
+		}
+		if err := validateFaults("chaos.default", config.Chaos.Default); err != nil {
+			return err
+		}
+		for tool, faults := range config.Chaos.Tools {
+			if err := validateFaults("chaos.tools."+tool, faults); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Validate replay configuration
+	switch config.Replay.Mode {
+	case "", ReplayModeOff:
+	case ReplayModeRecord, ReplayModeReplay:
+		if config.Replay.File == "" {
+			return fmt.Errorf("replay.file cannot be empty when replay.mode is %s", config.Replay.Mode)
+		}
+	default:
+		return fmt.Errorf("invalid replay.mode: %s (must be one of: off, record, replay)", config.Replay.Mode)
+	}
+
+	if config.Replay.FrozenTime != "" {
+		if _, err := time.Parse(time.RFC3339Nano, config.Replay.FrozenTime); err != nil {
+			return fmt.Errorf("invalid replay.frozen_time %s: %w", config.Replay.FrozenTime, err)
+		}
+	}
+
+	if config.Determinism.Enabled {
+		if _, err := tzsnapshot.Select(config.Determinism.TZData); err != nil {
+			return fmt.Errorf("invalid determinism.tzdata: %w", err)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, config.Determinism.FrozenTime); err != nil {
+			return fmt.Errorf("invalid determinism.frozen_time %q: %w", config.Determinism.FrozenTime, err)
+		}
+	}
+
+	// Validate update check configuration
+	if config.Updates.Enabled {
+		if config.Updates.Interval < time.Minute {
+			return fmt.Errorf("updates.interval must be at least 1m, got: %s", config.Updates.Interval)
+		}
+		if config.Updates.Timeout <= 0 {
+			return fmt.Errorf("updates.timeout must be positive, got: %s", config.Updates.Timeout)
+		}
+		if err := validateHTTPURL("updates.server_url", config.Updates.ServerURL); err != nil {
+			return err
+		}
+		if err := validateHTTPURL("updates.tzdata_url", config.Updates.TZDataURL); err != nil {
+			return err
+		}
+	}
+
+	// Validate calendars
+	if config.Calendar.Timeout <= 0 && len(config.Calendar.Sources) > 0 {
+		return fmt.Errorf("calendar.timeout must be positive, got: %s", config.Calendar.Timeout)
+	}
+	if config.Calendar.CacheTTL < 0 {
+		return fmt.Errorf("calendar.cache_ttl cannot be negative, got: %s", config.Calendar.CacheTTL)
+	}
+	calendarNames := make(map[string]bool)
+	for i, source := range config.Calendar.Sources {
+		if err := validateCalendarSource(fmt.Sprintf("calendar.sources[%d]", i), source); err != nil {
+			return err
+		}
+		if calendarNames[source.Name] {
+			return fmt.Errorf("calendar.sources[%d].name %s is used more than once", i, source.Name)
+		}
+		calendarNames[source.Name] = true
+	}
+
+	// Validate holiday feeds
+	if len(config.Holidays.Feeds) > 0 {
+		if config.Holidays.Interval < time.Minute {
+			return fmt.Errorf("holidays.interval must be at least 1m, got: %s", config.Holidays.Interval)
+		}
+		if config.Holidays.Timeout <= 0 {
+			return fmt.Errorf("holidays.timeout must be positive, got: %s", config.Holidays.Timeout)
+		}
+	}
+	feedNames := make(map[string]bool)
+	for i, feed := range config.Holidays.Feeds {
+		if err := validateHolidayFeed(fmt.Sprintf("holidays.feeds[%d]", i), feed); err != nil {
+			return err
+		}
+		if feedNames[feed.Name] {
+			return fmt.Errorf("holidays.feeds[%d].name %s is used more than once", i, feed.Name)
+		}
+		feedNames[feed.Name] = true
+	}
+
+	// Validate extensions
+	extensionNames := make(map[string]bool)
+	for i, extension := range config.Extensions {
+		if err := validateExtension(fmt.Sprintf("extensions[%d]", i), extension); err != nil {
+			return err
+		}
+		if extensionNames[extension.Name] {
+			return fmt.Errorf("extensions[%d].name %s is used more than once", i, extension.Name)
+		}
+		extensionNames[extension.Name] = true
+	}
+
+	// Validate client profiles
+	profileNames := make(map[string]bool)
+	for i, profile := range config.ClientProfiles {
+		if err := validateClientProfile(fmt.Sprintf("client_profiles[%d]", i), profile); err != nil {
+			return err
+		}
+		if profileNames[profile.Name] {
+			return fmt.Errorf("client_profiles[%d].name %s is used more than once", i, profile.Name)
+		}
+		profileNames[profile.Name] = true
+	}
+
+	// Validate i18n configuration
+	if locale := config.I18n.DefaultLocale; locale != "" && !slices.Contains(Locales, locale) {
+		return fmt.Errorf("invalid i18n.default_locale: %s (must be one of: %s)", locale, strings.Join(Locales, ", "))
+	}
+
+	// Validate limits
+	for _, limit := range []struct {
+		key   string
+		value int64
+	}{
+		{"limits.max_request_bytes", config.Limits.MaxRequestBytes},
+		{"limits.max_string_length", int64(config.Limits.MaxStringLength)},
+		{"limits.max_text_length", int64(config.Limits.MaxTextLength)},
+		{"limits.max_array_items", int64(config.Limits.MaxArrayItems)},
+		{"limits.max_result_bytes", int64(config.Limits.MaxResultBytes)},
+		{"limits.max_bulk_bytes", config.Limits.MaxBulkBytes},
+	} {
+		if limit.value < 0 {
+			return fmt.Errorf("%s cannot be negative, got: %d", limit.key, limit.value)
+		}
+	}
+
+	// Validate SLO tracking
+	if config.SLO.Enabled {
+		if err := validateSLO(config.SLO); err != nil {
+			return err
+		}
+	}
+
+	// Validate load shedding
+	if config.Shed.Enabled {
+		if err := validateShed(config.Shed, config.SLO.Enabled); err != nil {
+			return err
+		}
+	}
+
+	// Validate clock jump detection
+	if config.ClockJump.Enabled {
+		if config.ClockJump.Interval <= 0 {
+			return fmt.Errorf("clock_jump.interval must be positive, got: %s", config.ClockJump.Interval)
+		}
+		if config.ClockJump.Threshold < 10*time.Millisecond {
+			return fmt.Errorf("clock_jump.threshold must be at least 10ms, got: %s", config.ClockJump.Threshold)
+		}
+	}
+
+	// Validate session state configuration; zero values use the defaults
+	if ttl := config.SessionState.TTL; ttl != 0 && ttl < time.Second {
+		return fmt.Errorf("session_state.ttl must be at least 1s, got: %s", ttl)
+	}
+	if config.SessionState.PurgeAfter < 0 {
+		return fmt.Errorf("session_state.purge_after cannot be negative, got: %s", config.SessionState.PurgeAfter)
+	}
+	if config.SessionState.SweepInterval < 0 {
+		return fmt.Errorf("session_state.sweep_interval cannot be negative, got: %s", config.SessionState.SweepInterval)
+	}
+	if config.SessionState.MaxEntries < 0 {
+		return fmt.Errorf("session_state.max_entries cannot be negative, got: %d", config.SessionState.MaxEntries)
+	}
+
+	// Validate feature flags
+	if err := features.Validate(config.Features); err != nil {
+		return fmt.Errorf("invalid features: %w", err)
+	}
+
+	return nil
+}
+
+// validateLogSink checks a log sink configuration block
+func validateLogSink(key string, sink LogSinkConfig) error {
+	switch sink.Type {
+	case LogSinkStdout, LogSinkStderr, LogSinkSyslog:
+	case LogSinkFile:
+		if sink.Path == "" {
+			return fmt.Errorf("%s.path cannot be empty for a file sink", key)
+		}
+		if sink.MaxSizeMB < 0 {
+			return fmt.Errorf("%s.max_size_mb cannot be negative, got: %d", key, sink.MaxSizeMB)
+		}
+		if sink.MaxAge < 0 {
+			return fmt.Errorf("%s.max_age cannot be negative, got: %s", key, sink.MaxAge)
+		}
+		if sink.MaxBackups < 0 {
+			return fmt.Errorf("%s.max_backups cannot be negative, got: %d", key, sink.MaxBackups)
+		}
+	default:
+		return fmt.Errorf("invalid %s.type: %s (must be one of: stdout, stderr, file, syslog)", key, sink.Type)
+	}
+	return nil
+}
+
+// validateFaults checks a fault configuration block
+func validateFaults(key string, faults ChaosFaultConfig) error {
+	if err := validateProbability(key+".delay_probability", faults.DelayProbability); err != nil {
+		return err
+	}
+	if err := validateProbability(key+".error_probability", faults.ErrorProbability); err != nil {
+		return err
+	}
+	if faults.Delay < 0 {
+		return fmt.Errorf("%s.delay cannot be negative, got: %s", key, faults.Delay)
+	}
+	return nil
+}
+
+// validateSLO checks the SLO tracking configuration
+func validateSLO(slo SLOConfig) error {
+	if slo.Window < time.Minute {
+		return fmt.Errorf("slo.window must be at least 1m, got: %s", slo.Window)
+	}
+	if slo.Interval <= 0 {
+		return fmt.Errorf("slo.interval must be positive, got: %s", slo.Interval)
+	}
+	if slo.MinCalls < 0 {
+		return fmt.Errorf("slo.min_calls cannot be negative, got: %d", slo.MinCalls)
+	}
+	if err := validateObjective("slo.default", slo.Default); err != nil {
+		return err
+	}
+	for tool, objective := range slo.Tools {
+		if err := validateObjective("slo.tools."+tool, objective); err != nil {
+			return err
+		}
+	}
+	if err := validateHTTPURL("slo.alert_url", slo.AlertURL); err != nil {
+		return err
+	}
+	if slo.AlertURL != "" {
+		if slo.AlertBurnRate <= 0 {
+			return fmt.Errorf("slo.alert_burn_rate must be positive, got: %v", slo.AlertBurnRate)
+		}
+		if slo.AlertTimeout <= 0 {
+			return fmt.Errorf("slo.alert_timeout must be positive, got: %s", slo.AlertTimeout)
+		}
+		if slo.AlertCooldown < 0 {
+			return fmt.Errorf("slo.alert_cooldown cannot be negative, got: %s", slo.AlertCooldown)
+		}
+	}
+	return nil
+}
+
+// validateObjective checks a latency objective block
+func validateObjective(key string, objective SLOObjectiveConfig) error {
+	if objective.Latency <= 0 {
+		return fmt.Errorf("%s.latency must be positive, got: %s", key, objective.Latency)
+	}
+	if objective.Objective <= 0 || objective.Objective >= 1 {
+		return fmt.Errorf("%s.objective must be between 0 and 1, exclusive, got: %v", key, objective.Objective)
+	}
+	return nil
+}
+
+// validateStreamStore checks the stream store backend and its connection.
+// No backend keeps sessions in memory
+func validateStreamStore(store StreamStoreConfig) error {
+	switch store.Backend {
+	case "", StreamStoreMemory:
+		return nil
+	case StreamStoreRedis:
+	default:
+		return fmt.Errorf("invalid server.stream_store.backend: %s (must be one of: memory, redis)", store.Backend)
+	}
+	if store.Redis.Addr == "" {
+		return fmt.Errorf("server.stream_store.redis.addr cannot be empty")
+	}
+	if store.Redis.DB < 0 {
+		return fmt.Errorf("server.stream_store.redis.db cannot be negative, got: %d", store.Redis.DB)
+	}
+	if store.Redis.TTL <= 0 {
+		return fmt.Errorf("server.stream_store.redis.ttl must be positive, got: %s", store.Redis.TTL)
+	}
+	return nil
+}
+
+// validateAffinity checks the affinity cookie can be set as configured
+func validateAffinity(affinity AffinityConfig) error {
+	if affinity.Cookie == "" {
+		return fmt.Errorf("server.affinity.cookie cannot be empty")
+	}
+	if err := (&http.Cookie{Name: affinity.Cookie}).Valid(); err != nil {
+		return fmt.Errorf("invalid server.affinity.cookie %q: %w", affinity.Cookie, err)
+	}
+	if affinity.Replica != "" && !ReplicaPattern.MatchString(affinity.Replica) {
+		return fmt.Errorf("invalid server.affinity.replica %q: must be letters, digits, ., - and _", affinity.Replica)
+	}
+	return nil
+}
+
+// validateHeartbeat checks the SSE heartbeat interval and style
+func validateHeartbeat(heartbeat HeartbeatConfig) error {
+	if heartbeat.Interval < 0 {
+		return fmt.Errorf("server.heartbeat.interval cannot be negative, got: %s", heartbeat.Interval)
+	}
+	if heartbeat.Interval == 0 {
+		return nil
+	}
+	if heartbeat.Interval < time.Second {
+		return fmt.Errorf("server.heartbeat.interval must be at least 1s, got: %s", heartbeat.Interval)
+	}
+	switch heartbeat.Style {
+	case HeartbeatStyleComment, HeartbeatStyleEvent:
+		return nil
+	}
+	return fmt.Errorf("server.heartbeat.style must be %s or %s, got: %q", HeartbeatStyleComment, HeartbeatStyleEvent, heartbeat.Style)
+}
+
+// validateShed checks the load shedding configuration
+func validateShed(shed ShedConfig, sloEnabled bool) error {
+	if shed.MaxInFlight < 0 {
+		return fmt.Errorf("shed.max_in_flight cannot be negative, got: %d", shed.MaxInFlight)
+	}
+	if shed.MaxP99 < 0 {
+		return fmt.Errorf("shed.max_p99 cannot be negative, got: %s", shed.MaxP99)
+	}
+	if shed.MaxInFlight == 0 && shed.MaxP99 == 0 {
+		return fmt.Errorf("shed needs max_in_flight or max_p99 to be set")
+	}
+	if shed.MaxP99 > 0 {
+		if !sloEnabled {
+			return fmt.Errorf("shed.max_p99 needs slo.enabled, which measures latency")
+		}
+		if shed.P99Window < time.Second {
+			return fmt.Errorf("shed.p99_window must be at least 1s, got: %s", shed.P99Window)
+		}
+	}
+	if shed.RetryAfter < time.Second {
+		return fmt.Errorf("shed.retry_after must be at least 1s, got: %s", shed.RetryAfter)
+	}
+	validPriorities := map[string]bool{
+		ShedPriorityCritical: true, ShedPriorityHigh: true, ShedPriorityNormal: true, ShedPriorityLow: true,
+	}
+	if !validPriorities[shed.DefaultPriority] {
+		return fmt.Errorf("invalid shed.default_priority: %s (must be one of: critical, high, normal, low)", shed.DefaultPriority)
+	}
+	for tool, priority := range shed.Priorities {
+		if !validPriorities[priority] {
+			return fmt.Errorf("invalid shed.priorities.%s: %s (must be one of: critical, high, normal, low)", tool, priority)
+		}
+	}
+	return nil
+}
+
+// validateClientProfile checks a client profile configuration block
+func validateClientProfile(key string, profile ClientProfileConfig) error {
+	if !namePattern.MatchString(profile.Name) {
+		return fmt.Errorf("%s.name must be lowercase letters, digits, - and _, got: %q", key, profile.Name)
+	}
+	if profile.Client == "" {
+		return fmt.Errorf("%s.client cannot be empty", key)
+	}
+	if profile.BeforeVersion != "" && !versionPattern.MatchString(profile.BeforeVersion) {
+		return fmt.Errorf("%s.before_version must be a dotted version like 1.4.0, got: %q", key, profile.BeforeVersion)
+	}
+	if !profile.DropStructuredContent && !profile.DropAnnotations {
+		return fmt.Errorf("%s changes nothing; set drop_structured_content or drop_annotations", key)
+	}
+	return nil
+}
+
+// validateCalendarSource checks a calendar source configuration block
+func validateCalendarSource(key string, source CalendarSourceConfig) error {
+	if !namePattern.MatchString(source.Name) {
+		return fmt.Errorf("%s.name must be lowercase letters, digits, - and _, got: %q", key, source.Name)
+	}
+	if source.Type != CalendarTypeICS && source.Type != CalendarTypeCalDAV {
+		return fmt.Errorf("invalid %s.type: %s (must be one of: ics, caldav)", key, source.Type)
+	}
+	if source.URL == "" {
+		return fmt.Errorf("%s.url cannot be empty", key)
+	}
+	if err := validateHTTPURL(key+".url", source.URL); err != nil {
+		return err
+	}
+	if (source.Username == "") != (source.Password == "") {
+		return fmt.Errorf("%s.username and %s.password must be set together", key, key)
+	}
+	if source.Token != "" && source.Username != "" {
+		return fmt.Errorf("%s cannot set both token and username", key)
+	}
+	if source.Timezone != "" {
+		if _, err := time.LoadLocation(source.Timezone); err != nil {
+			return fmt.Errorf("invalid %s.timezone %s: %w", key, source.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// validateHolidayFeed checks a holiday feed configuration block
+func validateHolidayFeed(key string, feed HolidayFeedConfig) error {
+	if !namePattern.MatchString(feed.Name) {
+		return fmt.Errorf("%s.name must be lowercase letters, digits, - and _, got: %q", key, feed.Name)
+	}
+	if feed.URL == "" {
+		return fmt.Errorf("%s.url cannot be empty", key)
+	}
+	if err := validateHTTPURL(key+".url", feed.URL); err != nil {
+		return err
+	}
+	if feed.Timezone != "" {
+		if _, err := time.LoadLocation(feed.Timezone); err != nil {
+			return fmt.Errorf("invalid %s.timezone %s: %w", key, feed.Timezone, err)
+		}
+	}
+	return nil
+}
+
+// validateExtension checks an extension configuration block
+func validateExtension(key string, extension ExtensionConfig) error {
+	if !namePattern.MatchString(extension.Name) {
+		return fmt.Errorf("%s.name must be lowercase letters, digits, - and _, got: %q", key, extension.Name)
+	}
+	if len(extension.Command) == 0 || extension.Command[0] == "" {
+		return fmt.Errorf("%s.command cannot be empty", key)
+	}
+	if extension.Timeout < 0 {
+		return fmt.Errorf("%s.timeout cannot be negative, got: %s", key, extension.Timeout)
+	}
+	if extension.MaxOutputBytes < 0 {
+		return fmt.Errorf("%s.max_output_bytes cannot be negative, got: %d", key, extension.MaxOutputBytes)
+	}
+	for _, entry := range extension.Env {
+		if name, _, ok := strings.Cut(entry, "="); !ok || name == "" {
+			return fmt.Errorf("%s.env entries must be KEY=VALUE, got: %q", key, entry)
+		}
+	}
+	return nil
+}
+
+// validateHTTPURL checks an optional http or https URL
+func validateHTTPURL(key, raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL, got: %s", key, raw)
+	}
+	return nil
+}
+
+// validateProbability checks that a probability lies within [0, 1]
+func validateProbability(key string, p float64) error {
+	if p < 0 || p > 1 {
+		return fmt.Errorf("%s must be between 0 and 1, got: %v", key, p)
+	}
 	return nil
 }
 
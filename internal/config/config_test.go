@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +12,17 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// clearMCPEnv blanks the MCP_ overrides earlier tests leave behind for the
+// rest of t; Load ignores empty values
+func clearMCPEnv(t *testing.T) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "MCP_") {
+			t.Setenv(name, "")
+		}
+	}
+}
+
 func TestLoad(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -28,9 +41,24 @@ func TestLoad(t *testing.T) {
 				assert.Equal(t, "UTC", cfg.Time.DefaultTimezone)
 				assert.Equal(t, "RFC3339", cfg.Time.DefaultFormat)
 				assert.Contains(t, cfg.Time.SupportedFormats, "RFC3339")
+				assert.True(t, cfg.Time.NegotiateFormat)
 				assert.Equal(t, "info", cfg.Logging.Level)
+				assert.Equal(t, LogBackendZap, cfg.Logging.Backend)
 				assert.True(t, cfg.Metrics.Enabled)
 				assert.Equal(t, 9080, cfg.Metrics.Port)
+				assert.Equal(t, map[string]bool{"natural_language": false, "astronomy": false, "scheduler": false}, cfg.Features)
+				assert.False(t, cfg.Updates.Enabled)
+				assert.Equal(t, 24*time.Hour, cfg.Updates.Interval)
+				assert.Equal(t, "https://data.iana.org/time-zones/tzdb/version", cfg.Updates.TZDataURL)
+				assert.Empty(t, cfg.Extensions)
+				assert.Equal(t, 5*time.Minute, cfg.Calendar.CacheTTL)
+				assert.Empty(t, cfg.Calendar.Sources)
+				assert.True(t, cfg.Compat.EmitLegacyFields)
+				assert.Equal(t, 256, cfg.Limits.MaxStringLength)
+				assert.Equal(t, int64(1<<20), cfg.Limits.MaxRequestBytes)
+				assert.Equal(t, int64(64<<20), cfg.Limits.MaxBulkBytes)
+				assert.Equal(t, deterministicBuild, cfg.Determinism.Enabled)
+				assert.Equal(t, "latest", cfg.Determinism.TZData)
 			},
 		},
 		{
@@ -41,9 +69,13 @@ func TestLoad(t *testing.T) {
 				os.Setenv("MCP_TIME_DEFAULT_TIMEZONE", "America/New_York")
 				os.Setenv("MCP_LOGGING_LEVEL", "debug")
 				os.Setenv("MCP_METRICS_ENABLED", "false")
+				os.Setenv("MCP_FEATURES_ASTRONOMY", "true")
+				os.Setenv("MCP_COMPAT_EMIT_LEGACY_FIELDS", "false")
 			},
 			wantErr: false,
 			validate: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.Features["astronomy"])
+				assert.False(t, cfg.Compat.EmitLegacyFields)
 				assert.Equal(t, 8081, cfg.Server.Port)
 				assert.Equal(t, "0.0.0.0", cfg.Server.Host)
 				assert.Equal(t, "America/New_York", cfg.Time.DefaultTimezone)
@@ -108,7 +140,7 @@ func TestLoad(t *testing.T) {
 				viper.Reset()
 			}()
 
-			cfg, err := Load()
+			cfg, err := Load(LoadOptions{})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -147,8 +179,9 @@ func TestValidate(t *testing.T) {
 					SupportedFormats: []string{"RFC3339", "Unix"},
 				},
 				Logging: LogConfig{
-					Level:  "info",
-					Format: "json",
+					Level:   "info",
+					Format:  "json",
+					Backend: "zap",
 				},
 				Metrics: MetricsConfig{
 					Enabled: true,
@@ -163,7 +196,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Port: 0},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "server.port must be between 1 and 65535",
@@ -173,7 +206,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Port: 70000},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "server.port must be between 1 and 65535",
@@ -183,7 +216,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "server.host cannot be empty",
@@ -193,7 +226,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "localhost", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "Invalid/Zone", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "invalid default timezone",
@@ -203,7 +236,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "localhost", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "time.default_format cannot be empty",
@@ -213,7 +246,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "localhost", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "time.supported_formats cannot be empty",
@@ -223,11 +256,107 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "localhost", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "invalid", Format: "json"},
+				Logging: LogConfig{Level: "invalid", Format: "json", Backend: "zap"},
 			},
 			wantErr: true,
 			errMsg:  "invalid logging.level",
 		},
+		{
+			name: "invalid log backend",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "logrus"},
+			},
+			wantErr: true,
+			errMsg:  "invalid logging.backend",
+		},
+		{
+			name: "log sinks",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time:   TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap", Sinks: []LogSinkConfig{
+					{Type: LogSinkStdout},
+					{Type: LogSinkFile, Path: "/var/log/mcp-server-time.log", MaxSizeMB: 100, MaxAge: 24 * time.Hour, MaxBackups: 7},
+					{Type: LogSinkSyslog},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "module log levels",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time:   TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap",
+					ModuleLevels: map[string]string{LogModuleTransport: "warn", LogModuleTime: "debug"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown log module",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time:   TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap",
+					ModuleLevels: map[string]string{"database": "debug"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid logging.module_levels module: database (must be one of: calendar, chaos, clock, envelope, extensions, holidays, recovery, replay, session, shed, slo, time, tools, transport, updates)",
+		},
+		{
+			name: "invalid module log level",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time:   TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap",
+					ModuleLevels: map[string]string{LogModuleTransport: "loud"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid logging.module_levels.transport: loud",
+		},
+		{
+			name: "invalid redaction pattern",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time:   TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap",
+					Redaction: RedactionConfig{Fields: []string{"time_string"}, Patterns: []string{`\d+`, "("}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid logging.redaction.patterns[1]",
+		},
+		{
+			name: "invalid log sink type",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap", Sinks: []LogSinkConfig{{Type: "kafka"}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid logging.sinks[0].type",
+		},
+		{
+			name: "file sink without path",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap", Sinks: []LogSinkConfig{{Type: LogSinkStderr}, {Type: LogSinkFile}}},
+			},
+			wantErr: true,
+			errMsg:  "logging.sinks[1].path cannot be empty",
+		},
+		{
+			name: "file sink with negative rotation limit",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap", Sinks: []LogSinkConfig{{Type: LogSinkFile, Path: "a.log", MaxAge: -time.Hour}}},
+			},
+			wantErr: true,
+			errMsg:  "logging.sinks[0].max_age cannot be negative",
+		},
 		{
 			name: "invalid log format",
 			config: &Config{
@@ -243,7 +372,7 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "localhost", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 				Metrics: MetricsConfig{Enabled: true, Port: 8080, Path: "/metrics"},
 			},
 			wantErr: true,
@@ -254,12 +383,554 @@ func TestValidate(t *testing.T) {
 			config: &Config{
 				Server:  ServerConfig{Host: "localhost", Port: 8080},
 				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
-				Logging: LogConfig{Level: "info", Format: "json"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
 				Metrics: MetricsConfig{Enabled: true, Port: 9090, Path: "metrics"},
 			},
 			wantErr: true,
 			errMsg:  "metrics.path must start with '/'",
 		},
+		{
+			name: "invalid chaos probability",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Chaos: ChaosConfig{
+					Enabled: true,
+					Tools:   map[string]ChaosFaultConfig{"get_time": {ErrorProbability: 1.5}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "chaos.tools.get_time.error_probability must be between 0 and 1",
+		},
+		{
+			name: "invalid replay mode",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Replay:  ReplayConfig{Mode: "rewind", File: "recording.jsonl"},
+			},
+			wantErr: true,
+			errMsg:  "invalid replay.mode",
+		},
+		{
+			name: "invalid replay frozen time",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Replay:  ReplayConfig{Mode: ReplayModeReplay, File: "recording.jsonl", FrozenTime: "yesterday"},
+			},
+			wantErr: true,
+			errMsg:  "invalid replay.frozen_time",
+		},
+		{
+			name: "update interval too short",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Updates: UpdatesConfig{Enabled: true, Interval: time.Second, Timeout: time.Second},
+			},
+			wantErr: true,
+			errMsg:  "updates.interval must be at least 1m",
+		},
+		{
+			name: "invalid update source",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Updates: UpdatesConfig{Enabled: true, Interval: time.Hour, Timeout: time.Second, TZDataURL: "ftp://ftp.iana.org/tz/version"},
+			},
+			wantErr: true,
+			errMsg:  "updates.tzdata_url must be an http or https URL",
+		},
+		{
+			name: "invalid calendar type",
+			config: &Config{
+				Server:   ServerConfig{Host: "localhost", Port: 8080},
+				Time:     TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:  LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Calendar: CalendarConfig{Timeout: time.Second, Sources: []CalendarSourceConfig{{Name: "team", Type: "exchange", URL: "https://cal.example.com/team"}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid calendar.sources[0].type: exchange",
+		},
+		{
+			name: "calendar password without username",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Calendar: CalendarConfig{Timeout: time.Second, Sources: []CalendarSourceConfig{
+					{Name: "team", Type: CalendarTypeICS, URL: "https://cal.example.com/team.ics", Password: "secret"},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "calendar.sources[0].username and calendar.sources[0].password must be set together",
+		},
+		{
+			name: "duplicate calendar name",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Calendar: CalendarConfig{Timeout: time.Second, Sources: []CalendarSourceConfig{
+					{Name: "team", Type: CalendarTypeICS, URL: "https://cal.example.com/team.ics"},
+					{Name: "team", Type: CalendarTypeCalDAV, URL: "https://dav.example.com/team/"},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "calendar.sources[1].name team is used more than once",
+		},
+		{
+			name: "holiday feed interval too short",
+			config: &Config{
+				Server:   ServerConfig{Host: "localhost", Port: 8080},
+				Time:     TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:  LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Holidays: HolidaysConfig{Interval: time.Second, Timeout: time.Second, Feeds: []HolidayFeedConfig{{Name: "us", URL: "https://example.com/us.ics"}}},
+			},
+			wantErr: true,
+			errMsg:  "holidays.interval must be at least 1m, got: 1s",
+		},
+		{
+			name: "invalid holiday feed url",
+			config: &Config{
+				Server:   ServerConfig{Host: "localhost", Port: 8080},
+				Time:     TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:  LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Holidays: HolidaysConfig{Interval: time.Hour, Timeout: time.Second, Feeds: []HolidayFeedConfig{{Name: "us", URL: "webcal://example.com/us.ics"}}},
+			},
+			wantErr: true,
+			errMsg:  "holidays.feeds[0].url must be an http or https URL",
+		},
+		{
+			name: "duplicate holiday feed name",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Holidays: HolidaysConfig{Interval: time.Hour, Timeout: time.Second, Feeds: []HolidayFeedConfig{
+					{Name: "us", URL: "https://example.com/us.ics"},
+					{Name: "us", URL: "https://example.com/us-2.ics"},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "holidays.feeds[1].name us is used more than once",
+		},
+		{
+			name: "invalid slo objective",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				SLO: SLOConfig{Enabled: true, Window: time.Hour, Interval: time.Minute,
+					Default: SLOObjectiveConfig{Latency: 100 * time.Millisecond, Objective: 0.99},
+					Tools:   map[string]SLOObjectiveConfig{"sync_holidays": {Latency: time.Second, Objective: 1}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "slo.tools.sync_holidays.objective must be between 0 and 1, exclusive",
+		},
+		{
+			name: "invalid slo alert url",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				SLO: SLOConfig{Enabled: true, Window: time.Hour, Interval: time.Minute,
+					Default:  SLOObjectiveConfig{Latency: 100 * time.Millisecond, Objective: 0.99},
+					AlertURL: "ftp://alerts.example.com",
+				},
+			},
+			wantErr: true,
+			errMsg:  "slo.alert_url must be an http or https URL",
+		},
+		{
+			name: "shed on p99 without slo",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Shed: ShedConfig{Enabled: true, MaxP99: time.Second, P99Window: time.Minute, RetryAfter: time.Second,
+					DefaultPriority: ShedPriorityNormal},
+			},
+			wantErr: true,
+			errMsg:  "shed.max_p99 needs slo.enabled",
+		},
+		{
+			name: "invalid shed priority",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Shed: ShedConfig{Enabled: true, MaxInFlight: 10, RetryAfter: time.Second, DefaultPriority: ShedPriorityNormal,
+					Priorities: map[string]string{"get_holidays": "lowest"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid shed.priorities.get_holidays: lowest (must be one of: critical, high, normal, low)",
+		},
+		{
+			name: "invalid affinity replica",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080,
+					Affinity: AffinityConfig{Enabled: true, Cookie: "mcp_replica", Replica: "pod 1"}},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  `invalid server.affinity.replica "pod 1"`,
+		},
+		{
+			name: "invalid stream store",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080,
+					StreamStore: StreamStoreConfig{Backend: "etcd"}},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.stream_store.backend: etcd (must be one of: memory, redis)",
+		},
+		{
+			name: "redis stream store without ttl",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080,
+					StreamStore: StreamStoreConfig{Backend: StreamStoreRedis, Redis: RedisConfig{Addr: "redis:6379"}}},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "server.stream_store.redis.ttl must be positive, got: 0s",
+		},
+		{
+			name: "heartbeat too frequent",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080,
+					Heartbeat: HeartbeatConfig{Interval: 100 * time.Millisecond, Style: HeartbeatStyleEvent}},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "server.heartbeat.interval must be at least 1s, got: 100ms",
+		},
+		{
+			name: "invalid heartbeat style",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080,
+					Heartbeat: HeartbeatConfig{Interval: 15 * time.Second, Style: "ping"}},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  `server.heartbeat.style must be comment or event, got: "ping"`,
+		},
+		{
+			name: "client profile without changes",
+			config: &Config{
+				Server:         ServerConfig{Host: "localhost", Port: 8080},
+				Time:           TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:        LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				ClientProfiles: []ClientProfileConfig{{Name: "desk", Client: "Desk", BeforeVersion: "2.0.0"}},
+			},
+			wantErr: true,
+			errMsg:  "client_profiles[0] changes nothing; set drop_structured_content or drop_annotations",
+		},
+		{
+			name: "client profile with an invalid version",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				ClientProfiles: []ClientProfileConfig{{Name: "desk", Client: "Desk", BeforeVersion: "2.x",
+					DropStructuredContent: true}},
+			},
+			wantErr: true,
+			errMsg:  `client_profiles[0].before_version must be a dotted version like 1.4.0, got: "2.x"`,
+		},
+		{
+			name: "unsupported default locale",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				I18n:    I18nConfig{DefaultLocale: "pt"},
+			},
+			wantErr: true,
+			errMsg:  "invalid i18n.default_locale: pt (must be one of: en, pt-BR, es)",
+		},
+		{
+			name: "invalid extension name",
+			config: &Config{
+				Server:     ServerConfig{Host: "localhost", Port: 8080},
+				Time:       TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:    LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Extensions: []ExtensionConfig{{Name: "Team Calendar", Command: []string{"/usr/local/bin/calendar"}}},
+			},
+			wantErr: true,
+			errMsg:  "extensions[0].name must be lowercase letters",
+		},
+		{
+			name: "extension without command",
+			config: &Config{
+				Server:     ServerConfig{Host: "localhost", Port: 8080},
+				Time:       TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:    LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Extensions: []ExtensionConfig{{Name: "calendar"}},
+			},
+			wantErr: true,
+			errMsg:  "extensions[0].command cannot be empty",
+		},
+		{
+			name: "malformed extension env",
+			config: &Config{
+				Server:     ServerConfig{Host: "localhost", Port: 8080},
+				Time:       TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:    LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Extensions: []ExtensionConfig{{Name: "calendar", Command: []string{"calendar"}, Env: []string{"TOKEN"}}},
+			},
+			wantErr: true,
+			errMsg:  "extensions[0].env entries must be KEY=VALUE",
+		},
+		{
+			name: "duplicate extension name",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Extensions: []ExtensionConfig{
+					{Name: "calendar", Command: []string{"calendar"}},
+					{Name: "calendar", Command: []string{"calendar-v2"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "extensions[1].name calendar is used more than once",
+		},
+		{
+			name: "unknown feature flag",
+			config: &Config{
+				Server:   ServerConfig{Host: "localhost", Port: 8080},
+				Time:     TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:  LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Features: map[string]bool{"astronmy": true},
+			},
+			wantErr: true,
+			errMsg:  "invalid features: unknown feature flags: astronmy",
+		},
+		{
+			name: "negative limit",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Limits:  LimitsConfig{MaxArrayItems: -1},
+			},
+			wantErr: true,
+			errMsg:  "limits.max_array_items cannot be negative, got: -1",
+		},
+		{
+			name: "invalid max precision",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, MaxPrecision: "week"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.max_precision",
+		},
+		{
+			name: "invalid rfc3339 utc offset",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					RFC3339: RFC3339Config{UTCOffset: "UTC"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.rfc3339.utc_offset",
+		},
+		{
+			name: "invalid rfc3339 fraction",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					RFC3339: RFC3339Config{UTCOffset: "+00:00", Fraction: "centi"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.rfc3339.fraction",
+		},
+		{
+			name: "inverted valid range",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					ValidRange: ValidRangeConfig{Enabled: true, MinYear: 2100, MaxYear: 1900, Mode: ValidRangeModeFlag}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "time.valid_range.min_year",
+		},
+		{
+			name: "invalid valid range mode",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					ValidRange: ValidRangeConfig{Enabled: true, MinYear: 1900, MaxYear: 2100, Mode: "clamp"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.valid_range.mode",
+		},
+		{
+			name: "invalid offset seconds",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, OffsetSeconds: "truncate"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.offset_seconds: truncate (must be one of: show, round)",
+		},
+		{
+			name: "invalid format shadow engine",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					FormatShadow: FormatShadowConfig{Engine: "icu", SampleRate: 1}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.format_shadow.engine: icu (must be one of: strftime)",
+		},
+		{
+			name: "invalid format shadow sample rate",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					FormatShadow: FormatShadowConfig{Engine: FormatShadowEngineStrftime, SampleRate: 0}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "time.format_shadow.sample_rate must be in (0, 1], got: 0",
+		},
+		{
+			name: "unknown determinism tzdata snapshot",
+			config: &Config{
+				Server:      ServerConfig{Host: "localhost", Port: 8080},
+				Time:        TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:     LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Determinism: DeterminismConfig{Enabled: true, TZData: "1999z", FrozenTime: "2026-01-01T00:00:00Z"},
+			},
+			wantErr: true,
+			errMsg:  "invalid determinism.tzdata: unknown tzdata snapshot 1999z",
+		},
+		{
+			name: "determinism without frozen time",
+			config: &Config{
+				Server:      ServerConfig{Host: "localhost", Port: 8080},
+				Time:        TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:     LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				Determinism: DeterminismConfig{Enabled: true, TZData: "latest"},
+			},
+			wantErr: true,
+			errMsg:  `invalid determinism.frozen_time ""`,
+		},
+		{
+			name: "negative session state max entries",
+			config: &Config{
+				Server:       ServerConfig{Host: "localhost", Port: 8080},
+				Time:         TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}},
+				Logging:      LogConfig{Level: "info", Format: "json", Backend: "zap"},
+				SessionState: SessionStateConfig{MaxEntries: -1},
+			},
+			wantErr: true,
+			errMsg:  "session_state.max_entries cannot be negative, got: -1",
+		},
+		{
+			name: "invalid two digit year pivot",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, TwoDigitYearPivot: 150},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "time.two_digit_year_pivot must be between 0 and 100",
+		},
+		{
+			name: "invalid gregorian cutover",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, GregorianCutover: "14 Sep 1752"},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.gregorian_cutover",
+		},
+		{
+			name: "invalid preload timezone",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, PreloadTimezones: []string{"Europe/London", "Mars/Olympus"}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.preload_timezones entry Mars/Olympus",
+		},
+		{
+			name: "dst lookahead above maximum",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, DSTLookaheadDays: 5000},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "time.dst_lookahead_days must be between 0 (default) and 3660",
+		},
+		{
+			name: "tzdata source without a path",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, TZDataSources: []TZDataSourceConfig{{Name: "2019c"}}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "time.tzdata_sources[0] needs a name and a path",
+		},
+		{
+			name: "tzdata source with the reserved name",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, TZDataSources: []TZDataSourceConfig{{Name: "default", Path: "/usr/share/zoneinfo"}}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "the name default is reserved",
+		},
+		{
+			name: "duplicate tzdata source",
+			config: &Config{
+				Server: ServerConfig{Host: "localhost", Port: 8080},
+				Time: TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"},
+					TZDataSources: []TZDataSourceConfig{{Name: "old", Path: "."}, {Name: "old", Path: "."}}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "duplicate time.tzdata_sources name old",
+		},
+		{
+			name: "missing tzdata source path",
+			config: &Config{
+				Server:  ServerConfig{Host: "localhost", Port: 8080},
+				Time:    TimeConfig{DefaultTimezone: "UTC", DefaultFormat: "RFC3339", SupportedFormats: []string{"RFC3339"}, TZDataSources: []TZDataSourceConfig{{Name: "old", Path: "/nonexistent/zoneinfo"}}},
+				Logging: LogConfig{Level: "info", Format: "json", Backend: "zap"},
+			},
+			wantErr: true,
+			errMsg:  "invalid time.tzdata_sources path for old",
+		},
 	}
 
 	for _, tt := range tests {
@@ -319,3 +990,116 @@ func TestTimeConfig_GetValidFormats(t *testing.T) {
 	result[0] = "Modified"
 	assert.Equal(t, "RFC3339", config.SupportedFormats[0])
 }
+
+func TestLoad_ConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	yamlFile := write("server.yaml", "server:\n  port: 8101\n")
+	jsonFile := write("server.json", `{"server": {"port": 8102}, "time": {"default_timezone": "Europe/Paris"}}`)
+	tomlFile := write("server.toml", "[server]\nport = 8103\n\n[logging]\nlevel = \"debug\"\n")
+	iniFile := write("server.ini", "[server]\nport = 8104\n")
+	brokenFile := write("broken.json", `{"server": `)
+	extensionsFile := write("extensions.yaml", "extensions:\n  - name: calendar\n    command: [\"/usr/local/bin/calendar\", \"--serve\"]\n    env: [\"TOKEN=secret\"]\n    timeout: 2s\n")
+
+	tests := []struct {
+		name     string
+		file     string
+		env      string
+		wantErr  string
+		validate func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "yaml",
+			file: yamlFile,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8101, cfg.Server.Port)
+			},
+		},
+		{
+			name: "json",
+			file: jsonFile,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8102, cfg.Server.Port)
+				assert.Equal(t, "Europe/Paris", cfg.Time.DefaultTimezone)
+			},
+		},
+		{
+			name: "toml",
+			file: tomlFile,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8103, cfg.Server.Port)
+				assert.Equal(t, "debug", cfg.Logging.Level)
+			},
+		},
+		{
+			name: "extensions",
+			file: extensionsFile,
+			validate: func(t *testing.T, cfg *Config) {
+				require.Len(t, cfg.Extensions, 1)
+				assert.Equal(t, "calendar", cfg.Extensions[0].Name)
+				assert.Equal(t, []string{"/usr/local/bin/calendar", "--serve"}, cfg.Extensions[0].Command)
+				assert.Equal(t, []string{"TOKEN=secret"}, cfg.Extensions[0].Env)
+				assert.Equal(t, 2*time.Second, cfg.Extensions[0].Timeout)
+			},
+		},
+		{
+			name: "from environment",
+			env:  jsonFile,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8102, cfg.Server.Port)
+			},
+		},
+		{
+			name: "flag wins over environment",
+			file: yamlFile,
+			env:  jsonFile,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8101, cfg.Server.Port)
+			},
+		},
+		{
+			name:    "missing file",
+			file:    filepath.Join(dir, "missing.yaml"),
+			wantErr: "missing.yaml: no such file or directory",
+		},
+		{
+			name:    "missing file from environment",
+			env:     filepath.Join(dir, "missing.toml"),
+			wantErr: "missing.toml: no such file or directory",
+		},
+		{
+			name:    "unsupported extension",
+			file:    iniFile,
+			wantErr: "extension must be .yaml, .yml, .json or .toml",
+		},
+		{
+			name:    "malformed file",
+			file:    brokenFile,
+			wantErr: "error reading config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			clearMCPEnv(t)
+			t.Setenv(ConfigFileEnv, tt.env)
+
+			cfg, err := Load(LoadOptions{File: tt.file})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, cfg.File)
+			tt.validate(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+//go:build deterministic
+
+package config
+
+// deterministicBuild turns determinism on by default in builds tagged
+// deterministic, for CI images that must never fall back to host tzdata
+const deterministicBuild = true
@@ -0,0 +1,6 @@
+//go:build !deterministic
+
+package config
+
+// deterministicBuild is false outside builds tagged deterministic
+const deterministicBuild = false
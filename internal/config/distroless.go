@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DistrolessEnv names the environment variable enabling distroless mode
+// when LoadOptions.Distroless is not set
+const DistrolessEnv = "MCP_DISTROLESS"
+
+// distrolessProbeZone must load from the tzdata embedded in the binary,
+// as there is no zoneinfo directory in a scratch image
+const distrolessProbeZone = "America/New_York"
+
+// validateDistroless checks that a configuration needs no filesystem at
+// runtime: every setting that reads or writes files is off, and zones load
+// without a zoneinfo directory. It lists every offending setting at once
+func validateDistroless(config *Config) error {
+	var problems []string
+	for i, sink := range config.Logging.Sinks {
+		switch {
+		case sink.Type == LogSinkFile:
+			problems = append(problems, fmt.Sprintf("logging.sinks[%d] writes to a file", i))
+		case sink.Type == LogSinkSyslog && sink.Address == "":
+			problems = append(problems, fmt.Sprintf("logging.sinks[%d] uses the local syslog socket; set network and address", i))
+		}
+	}
+	if len(config.Holidays.Feeds) > 0 && config.Holidays.CacheDir != "" {
+		problems = append(problems, "holidays.cache_dir is set; feeds are kept in memory without it")
+	}
+	if config.Replay.Mode == ReplayModeRecord || config.Replay.Mode == ReplayModeReplay {
+		problems = append(problems, fmt.Sprintf("replay.mode %s reads or writes replay.file", config.Replay.Mode))
+	}
+	if len(config.Time.TZDataSources) > 0 {
+		problems = append(problems, "time.tzdata_sources reads zoneinfo from disk")
+	}
+	if len(config.Extensions) > 0 {
+		problems = append(problems, "extensions run programs from disk")
+	}
+	if _, err := time.LoadLocation(distrolessProbeZone); err != nil {
+		problems = append(problems, fmt.Sprintf("no tzdata to load %s from: %v", distrolessProbeZone, err))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("distroless mode needs no filesystem, but %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
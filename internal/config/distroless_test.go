@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Distroless(t *testing.T) {
+	// A config file in the working directory must not be found
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("server:\n  port: 9999\n"), 0o644))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	tests := []struct {
+		name    string
+		opts    LoadOptions
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "environment only",
+			opts: LoadOptions{Distroless: true},
+			env:  map[string]string{"MCP_TIME_DEFAULT_TIMEZONE": "Europe/Paris"},
+		},
+		{
+			name: "enabled from environment",
+			env:  map[string]string{DistrolessEnv: "true"},
+		},
+		{
+			name:    "config file",
+			opts:    LoadOptions{Distroless: true, File: filepath.Join(dir, "config.yaml")},
+			wantErr: "distroless mode reads no config file",
+		},
+		{
+			name:    "config file from environment",
+			env:     map[string]string{DistrolessEnv: "true", ConfigFileEnv: "/etc/mcp/config.yaml"},
+			wantErr: "distroless mode reads no config file, got /etc/mcp/config.yaml",
+		},
+		{
+			name:    "overlay",
+			opts:    LoadOptions{Distroless: true},
+			env:     map[string]string{EnvironmentEnv: "production"},
+			wantErr: "distroless mode reads no config overlay",
+		},
+		{
+			name:    "file reference",
+			opts:    LoadOptions{Distroless: true},
+			env:     map[string]string{"MCP_SERVER_HOST_FILE": "/run/secrets/host"},
+			wantErr: "distroless mode reads no files; set server.host instead of MCP_SERVER_HOST_FILE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			clearMCPEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(tt.opts)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, cfg.Distroless)
+			assert.Empty(t, cfg.File)
+			assert.Equal(t, 8080, cfg.Server.Port, "the config file in the working directory is not read")
+			if zone := tt.env["MCP_TIME_DEFAULT_TIMEZONE"]; zone != "" {
+				assert.Equal(t, zone, cfg.Time.DefaultTimezone)
+			}
+		})
+	}
+}
+
+func TestValidateDistroless(t *testing.T) {
+	assert.NoError(t, validateDistroless(&Config{
+		Logging:  LogConfig{Sinks: []LogSinkConfig{{Type: LogSinkStderr}, {Type: LogSinkSyslog, Network: "udp", Address: "logs.internal:514"}}},
+		Holidays: HolidaysConfig{Feeds: []HolidayFeedConfig{{Name: "us", URL: "https://example.com/us.ics"}}},
+		Replay:   ReplayConfig{Mode: ReplayModeOff},
+	}))
+
+	err := validateDistroless(&Config{
+		Logging:    LogConfig{Sinks: []LogSinkConfig{{Type: LogSinkFile, Path: "/var/log/server.log"}, {Type: LogSinkSyslog}}},
+		Holidays:   HolidaysConfig{CacheDir: "/var/cache", Feeds: []HolidayFeedConfig{{Name: "us", URL: "https://example.com/us.ics"}}},
+		Replay:     ReplayConfig{Mode: ReplayModeRecord, File: "recording.jsonl"},
+		Time:       TimeConfig{TZDataSources: []TZDataSourceConfig{{Name: "old", Path: "/opt/zoneinfo"}}},
+		Extensions: []ExtensionConfig{{Name: "calendar", Command: []string{"/usr/local/bin/calendar"}}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "distroless mode needs no filesystem, but logging.sinks[0] writes to a file; "+
+		"logging.sinks[1] uses the local syslog socket; set network and address; "+
+		"holidays.cache_dir is set; feeds are kept in memory without it; "+
+		"replay.mode record reads or writes replay.file; "+
+		"time.tzdata_sources reads zoneinfo from disk; extensions run programs from disk", err.Error())
+}
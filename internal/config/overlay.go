@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvironmentEnv names the environment variable selecting a config overlay,
+// e.g. MCP_ENV=prod layers config.prod.yaml over config.yaml
+const EnvironmentEnv = "MCP_ENV"
+
+// environmentPattern keeps environment names usable as a file name part
+var environmentPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// overlayPath returns the overlay file for environment next to base:
+// config.yaml becomes config.<environment>.yaml
+func overlayPath(base, environment string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + environment + ext
+}
+
+// mergeOverlay merges the environment's overlay over the config file already
+// read. Nested sections merge key by key, while lists and single values in
+// the overlay replace the base ones. It returns the overlay file used
+func mergeOverlay(environment string) (string, error) {
+	if !environmentPattern.MatchString(environment) {
+		return "", fmt.Errorf("invalid %s %q: use letters, digits, - and _", EnvironmentEnv, environment)
+	}
+	base := viper.ConfigFileUsed()
+	if base == "" {
+		return "", fmt.Errorf("%s=%s needs a base config file to overlay", EnvironmentEnv, environment)
+	}
+
+	path := overlayPath(base, environment)
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("config overlay for %s=%s: %w", EnvironmentEnv, environment, err)
+	}
+	defer f.Close()
+
+	if err := viper.MergeConfig(f); err != nil {
+		return "", fmt.Errorf("error reading config overlay %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Overlay(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	base := write("config.yaml", `server:
+  host: localhost
+  port: 8080
+time:
+  default_timezone: UTC
+  preload_timezones: [UTC, Europe/London]
+logging:
+  level: info
+`)
+	write("config.prod.yaml", `server:
+  port: 9090
+time:
+  preload_timezones: [America/New_York]
+logging:
+  level: warn
+`)
+	write("config.broken.yaml", "server:\n  port: 0\n")
+	write("config.typo.yaml", "server:\n  prot: 9090\n")
+	jsonBase := write("base.json", `{"server": {"port": 8080}}`)
+	write("base.staging.json", `{"server": {"port": 8181}}`)
+
+	tests := []struct {
+		name        string
+		file        string
+		environment string
+		env         map[string]string
+		strict      bool
+		wantErr     string
+		validate    func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "no environment",
+			file: base,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8080, cfg.Server.Port)
+				assert.Empty(t, cfg.Overlay)
+			},
+		},
+		{
+			name:        "overlay merged",
+			file:        base,
+			environment: "prod",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 9090, cfg.Server.Port)
+				assert.Equal(t, "localhost", cfg.Server.Host, "keys missing from the overlay keep the base value")
+				assert.Equal(t, "UTC", cfg.Time.DefaultTimezone)
+				assert.Equal(t, []string{"America/New_York"}, cfg.Time.PreloadTimezones, "lists are replaced, not appended")
+				assert.Equal(t, "warn", cfg.Logging.Level)
+				assert.Equal(t, "prod", cfg.Environment)
+				assert.Equal(t, filepath.Join(dir, "config.prod.yaml"), cfg.Overlay)
+			},
+		},
+		{
+			name:        "environment variables win over the overlay",
+			file:        base,
+			environment: "prod",
+			env:         map[string]string{"MCP_SERVER_PORT": "7070"},
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 7070, cfg.Server.Port)
+			},
+		},
+		{
+			name:        "overlay follows the base extension",
+			file:        jsonBase,
+			environment: "staging",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8181, cfg.Server.Port)
+			},
+		},
+		{
+			name:        "merged before validation",
+			file:        base,
+			environment: "broken",
+			wantErr:     "config validation failed",
+		},
+		{
+			name:        "strict checks the overlay",
+			file:        base,
+			environment: "typo",
+			strict:      true,
+			wantErr:     "config.typo.yaml: server.prot",
+		},
+		{
+			name:        "missing overlay",
+			file:        base,
+			environment: "dev",
+			wantErr:     "config.dev.yaml: no such file or directory",
+		},
+		{
+			name:        "invalid environment",
+			file:        base,
+			environment: "../prod",
+			wantErr:     `invalid MCP_ENV "../prod"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			clearMCPEnv(t)
+			t.Setenv(EnvironmentEnv, tt.environment)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(LoadOptions{File: tt.file, Strict: tt.strict})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ConfigStrictEnv names the environment variable enabling strict key checks
+// when LoadOptions.Strict is not set
+const ConfigStrictEnv = "MCP_CONFIG_STRICT"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configField is a Config struct field as it appears in config files
+type configField struct {
+	name string
+	typ  reflect.Type
+}
+
+// configFields lists the keys a config struct type accepts
+func configFields(t reflect.Type) []configField {
+	fields := make([]configField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, configField{name: name, typ: field.Type})
+	}
+	return fields
+}
+
+// lookupField finds the field for a config key segment
+func lookupField(t reflect.Type, name string) (configField, bool) {
+	for _, field := range configFields(t) {
+		if field.name == name {
+			return field, true
+		}
+	}
+	return configField{}, false
+}
+
+// isScalar reports whether a field holds a single value, which is what a
+// <key>_file variant can stand in for
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		return false
+	default:
+		return true
+	}
+}
+
+// unknownKeys returns the keys set in the config file that no Config field
+// reads, such as time.default_timzone, sorted
+func unknownKeys() []string {
+	var unknown []string
+	for _, key := range viper.AllKeys() {
+		if viper.InConfig(key) {
+			unknown = append(unknown, checkKey(reflect.TypeOf(Config{}), strings.Split(key, "."), key, viper.Get(key))...)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// checkKey walks path through t and returns the unknown keys it reaches.
+// Lists of structs, such as log sinks, are checked item by item
+func checkKey(t reflect.Type, path []string, key string, value any) []string {
+	if len(path) == 0 {
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct {
+			items, _ := value.([]any)
+			var unknown []string
+			for i, item := range items {
+				entry, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				for name, v := range entry {
+					itemKey := fmt.Sprintf("%s[%d].%s", key, i, name)
+					unknown = append(unknown, checkKey(t.Elem(), []string{strings.ToLower(name)}, itemKey, v)...)
+				}
+			}
+			return unknown
+		}
+		return nil
+	}
+
+	switch {
+	case t == durationType:
+		return []string{key}
+	case t.Kind() == reflect.Map:
+		return checkKey(t.Elem(), path[1:], key, value)
+	case t.Kind() == reflect.Struct:
+		field, ok := lookupField(t, path[0])
+		if !ok {
+			base, isFile := strings.CutSuffix(path[0], fileKeySuffix)
+			if baseField, found := lookupField(t, base); isFile && found && len(path) == 1 && isScalar(baseField.typ) {
+				return nil
+			}
+			return []string{key}
+		}
+		return checkKey(field.typ, path[1:], key, value)
+	default:
+		return []string{key}
+	}
+}
+
+// Schema returns a JSON Schema (draft 2020-12) describing config files, for
+// editor validation. Settings carry their defaults, and every single-value
+// setting has a <key>_file variant
+func Schema() map[string]any {
+	defaults := viper.New()
+	setDefaults(defaults)
+
+	schema := schemaFor(reflect.TypeOf(Config{}), "", defaults)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "mcp-server-time configuration"
+	return schema
+}
+
+// schemaFor describes type t found at key, reading defaults for leaf settings
+func schemaFor(t reflect.Type, key string, defaults *viper.Viper) map[string]any {
+	var schema map[string]any
+	switch {
+	case t == durationType:
+		schema = map[string]any{"type": "string", "description": "Go duration, e.g. 30s or 2m"}
+	case t.Kind() == reflect.Struct:
+		properties := make(map[string]any)
+		for _, field := range configFields(t) {
+			fieldKey := field.name
+			if key != "" {
+				fieldKey = key + "." + field.name
+			}
+			properties[field.name] = schemaFor(field.typ, fieldKey, defaults)
+			if isScalar(field.typ) {
+				properties[field.name+fileKeySuffix] = map[string]any{
+					"type":        "string",
+					"description": "File to read " + fieldKey + " from",
+				}
+			}
+		}
+		return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	case t.Kind() == reflect.Map:
+		schema = map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), "", nil)}
+	case t.Kind() == reflect.Slice:
+		schema = map[string]any{"type": "array", "items": schemaFor(t.Elem(), "", nil)}
+	case t.Kind() == reflect.String:
+		schema = map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		schema = map[string]any{"type": "boolean"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		schema = map[string]any{"type": "number"}
+	default:
+		schema = map[string]any{"type": "integer"}
+	}
+
+	if defaults != nil && key != "" && defaults.IsSet(key) {
+		value := defaults.Get(key)
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		schema["default"] = value
+	}
+	return schema
+}
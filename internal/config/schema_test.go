@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Strict(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "host"), []byte("secret.internal\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		config  string
+		strict  bool
+		env     string
+		wantErr string
+	}{
+		{
+			name:    "typo rejected",
+			config:  "time:\n  default_timzone: Europe/Paris\n",
+			strict:  true,
+			wantErr: "unknown config keys in " + filepath.Join(dir, "config.yaml") + ": time.default_timzone",
+		},
+		{
+			name:   "typo allowed without strict",
+			config: "time:\n  default_timzone: Europe/Paris\n",
+		},
+		{
+			name:    "strict from environment",
+			config:  "metrics:\n  enabeld: false\n",
+			env:     "true",
+			wantErr: "metrics.enabeld",
+		},
+		{
+			name:    "unknown section",
+			config:  "tracing:\n  enabled: true\n",
+			strict:  true,
+			wantErr: "tracing.enabled",
+		},
+		{
+			name:    "key below a setting",
+			config:  "server:\n  port:\n    number: 8080\n",
+			strict:  true,
+			wantErr: "server.port.number",
+		},
+		{
+			name:    "list item typo",
+			config:  "logging:\n  sinks:\n    - type: file\n      pth: /tmp/server.log\n",
+			strict:  true,
+			wantErr: "logging.sinks[0].pth",
+		},
+		{
+			name: "known keys",
+			config: `server:
+  port: 8080
+  graceful_shutdown_timeout: 10s
+logging:
+  sinks:
+    - type: file
+      path: /tmp/server.log
+      max_age: 24h
+  module_levels:
+    transport: debug
+chaos:
+  tools:
+    get_time:
+      delay: 1s
+time:
+  tzdata_sources: []
+`,
+			strict: true,
+		},
+		{
+			name:   "file variant",
+			config: "server:\n  host_file: " + filepath.Join(dir, "host") + "\n",
+			strict: true,
+		},
+		{
+			name:    "file variant of a section",
+			config:  "server_file: /etc/server.yaml\n",
+			strict:  true,
+			wantErr: "server_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			clearMCPEnv(t)
+			t.Setenv(ConfigStrictEnv, tt.env)
+
+			_, err := Load(LoadOptions{File: write("config.yaml", tt.config), Strict: tt.strict})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := schema["properties"].(map[string]any)
+	for _, section := range []string{"server", "time", "logging", "metrics", "chaos", "replay"} {
+		assert.Contains(t, properties, section)
+	}
+
+	server := properties["server"].(map[string]any)
+	assert.Equal(t, false, server["additionalProperties"])
+	serverProps := server["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer", "default": 8080}, serverProps["port"])
+	assert.Equal(t, "string", serverProps["port_file"].(map[string]any)["type"])
+	timeout := serverProps["graceful_shutdown_timeout"].(map[string]any)
+	assert.Equal(t, "string", timeout["type"])
+	assert.Equal(t, "1s", timeout["default"])
+
+	logging := properties["logging"].(map[string]any)["properties"].(map[string]any)
+	assert.NotContains(t, logging, "sinks_file")
+	sinks := logging["sinks"].(map[string]any)
+	assert.Equal(t, "array", sinks["type"])
+	sink := sinks["items"].(map[string]any)
+	assert.Equal(t, false, sink["additionalProperties"])
+	assert.Contains(t, sink["properties"], "max_age")
+	assert.Equal(t, map[string]any{"type": "string"},
+		logging["module_levels"].(map[string]any)["additionalProperties"])
+}
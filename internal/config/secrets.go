@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// fileKeySuffix marks a key whose value is read from the named file, so
+// secrets can be mounted (e.g. as Kubernetes secrets) instead of written
+// into the config file
+const fileKeySuffix = "_file"
+
+// envRefPattern matches ${NAME} and ${NAME:-default}; a leading $ escapes
+// the reference, so $${NAME} stays a literal ${NAME}
+var envRefPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolateEnv expands ${ENV} references in every string value read from
+// the config file, including values nested in lists and maps
+func interpolateEnv() error {
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !viper.InConfig(key) {
+			continue
+		}
+		value, changed, err := expandValue(viper.Get(key))
+		if err != nil {
+			return fmt.Errorf("config key %s: %w", key, err)
+		}
+		if changed {
+			viper.Set(key, value)
+		}
+	}
+	return nil
+}
+
+// expandValue expands ${ENV} references in strings, recursing into lists
+// and maps. changed reports whether anything was expanded
+func expandValue(value any) (any, bool, error) {
+	switch v := value.(type) {
+	case string:
+		expanded, err := expandEnv(v)
+		return expanded, expanded != v, err
+	case []any:
+		out := make([]any, len(v))
+		changed := false
+		for i, item := range v {
+			expanded, itemChanged, err := expandValue(item)
+			if err != nil {
+				return nil, false, err
+			}
+			out[i], changed = expanded, changed || itemChanged
+		}
+		return out, changed, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		changed := false
+		for k, item := range v {
+			expanded, itemChanged, err := expandValue(item)
+			if err != nil {
+				return nil, false, err
+			}
+			out[k], changed = expanded, changed || itemChanged
+		}
+		return out, changed, nil
+	default:
+		return value, false, nil
+	}
+}
+
+// expandEnv replaces ${NAME} and ${NAME:-default} references in s. An
+// unset variable without a default is an error rather than an empty value
+func expandEnv(s string) (string, error) {
+	var missing []string
+	expanded := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		match := envRefPattern.FindStringSubmatch(ref)
+		name, fallback := match[1], match[2]
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		if strings.Contains(ref, ":-") {
+			return fallback
+		}
+		missing = append(missing, name)
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// resolveFileRefs reads each known key set through its _file variant,
+// either <key>_file in the config file or MCP_<KEY>_FILE in the environment.
+// Trailing newlines are trimmed, as secret files usually end with one.
+// Distroless mode reads no files, so there a reference is an error
+func resolveFileRefs(known []string, distroless bool) error {
+	for _, key := range known {
+		fileKey := key + fileKeySuffix
+		envName := "MCP_" + strings.ToUpper(strings.ReplaceAll(fileKey, ".", "_"))
+
+		path := os.Getenv(envName)
+		source := envName
+		if path == "" && viper.IsSet(fileKey) {
+			path, source = viper.GetString(fileKey), fileKey
+		}
+		if path == "" {
+			continue
+		}
+
+		if viper.InConfig(key) {
+			return fmt.Errorf("set either %s or %s, not both", key, source)
+		}
+		if distroless {
+			return fmt.Errorf("distroless mode reads no files; set %s instead of %s", key, source)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading %s for %s: %w", path, key, err)
+		}
+		viper.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
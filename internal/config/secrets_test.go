@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("MCP_TEST_HOST", "example.internal")
+	t.Setenv("MCP_TEST_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"no references", "2006-01-02 $HOME", "2006-01-02 $HOME", ""},
+		{"reference", "${MCP_TEST_HOST}:8080", "example.internal:8080", ""},
+		{"default used", "${MCP_TEST_UNSET:-localhost}", "localhost", ""},
+		{"default ignored", "${MCP_TEST_HOST:-localhost}", "example.internal", ""},
+		{"set but empty", "[${MCP_TEST_EMPTY}]", "[]", ""},
+		{"escaped", "$${MCP_TEST_HOST}", "${MCP_TEST_HOST}", ""},
+		{"unset", "${MCP_TEST_UNSET}/${MCP_TEST_OTHER}", "", "environment variable MCP_TEST_UNSET, MCP_TEST_OTHER is not set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnv(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad_Secrets(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	hostFile := write("host", "secret.internal\n")
+
+	tests := []struct {
+		name     string
+		config   string
+		env      map[string]string
+		wantErr  string
+		validate func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:   "interpolation in values and nested lists",
+			config: "server:\n  host: ${MCP_TEST_HOST}\nlogging:\n  sinks:\n    - type: file\n      path: ${MCP_TEST_LOG_DIR:-/var/log}/server.log\n",
+			env:    map[string]string{"MCP_TEST_HOST": "0.0.0.0"},
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "0.0.0.0", cfg.Server.Host)
+				require.Len(t, cfg.Logging.Sinks, 1)
+				assert.Equal(t, "/var/log/server.log", cfg.Logging.Sinks[0].Path)
+			},
+		},
+		{
+			name:    "unset variable",
+			config:  "server:\n  host: ${MCP_TEST_UNSET}\n",
+			wantErr: "config key server.host: environment variable MCP_TEST_UNSET is not set",
+		},
+		{
+			name:   "file variant in config",
+			config: "server:\n  host_file: " + hostFile + "\n",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "secret.internal", cfg.Server.Host)
+			},
+		},
+		{
+			name:   "file variant path from environment reference",
+			config: "server:\n  host_file: ${MCP_TEST_SECRETS}/host\n",
+			env:    map[string]string{"MCP_TEST_SECRETS": dir},
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "secret.internal", cfg.Server.Host)
+			},
+		},
+		{
+			name:   "file variant in environment",
+			config: "server:\n  port: 8090\n",
+			env:    map[string]string{"MCP_SERVER_HOST_FILE": hostFile},
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "secret.internal", cfg.Server.Host)
+			},
+		},
+		{
+			name:    "value and file variant",
+			config:  "server:\n  host: localhost\n  host_file: " + hostFile + "\n",
+			wantErr: "set either server.host or server.host_file, not both",
+		},
+		{
+			name:    "missing secret file",
+			config:  "server:\n  host_file: " + filepath.Join(dir, "missing") + "\n",
+			wantErr: "for server.host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			for _, kv := range os.Environ() {
+				if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "MCP_") {
+					t.Setenv(name, "")
+					os.Unsetenv(name)
+				}
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(LoadOptions{File: write("config.yaml", tt.config)})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package daemon integrates the server with host service managers:
+// systemd's notify protocol, with watchdog pings while the server answers
+// its own health check, and the Windows service control manager.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Environment variables systemd sets for Type=notify services
+const (
+	NotifySocketEnv = "NOTIFY_SOCKET"
+	WatchdogUsecEnv = "WATCHDOG_USEC"
+	WatchdogPIDEnv  = "WATCHDOG_PID"
+)
+
+// States sent to systemd
+const (
+	StateReady    = "READY=1"
+	StateStopping = "STOPPING=1"
+	StateWatchdog = "WATCHDOG=1"
+)
+
+// Server is what a service manager controls
+type Server interface {
+	// Serve runs the server until ctx is done, then shuts it down
+	Serve(ctx context.Context) error
+	// Ready is closed once the server accepts connections
+	Ready() <-chan struct{}
+	// SetPaused stops or resumes serving requests without shutting down
+	SetPaused(paused bool)
+}
+
+// Notify sends a state to systemd. It reports false, doing nothing, when
+// the process was not started by systemd with Type=notify
+func Notify(state string) (bool, error) {
+	socket := os.Getenv(NotifySocketEnv)
+	if socket == "" {
+		return false, nil
+	}
+	// A leading @ names a socket in the abstract namespace
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("notify %s: %w", NotifySocketEnv, err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, fmt.Errorf("notify %s: %w", NotifySocketEnv, err)
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the interval systemd expects watchdog pings
+// within, from WatchdogSec=; 0 when the watchdog is off or meant for
+// another process
+func WatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv(WatchdogUsecEnv)
+	if usec == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", WatchdogUsecEnv, usec)
+	}
+	if pid := os.Getenv(WatchdogPIDEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+	return time.Duration(n) * time.Microsecond, nil
+}
+
+// Watchdog pings systemd at half the watchdog interval until ctx is done.
+// Each ping waits for probe to pass, so systemd restarts a server that
+// stopped answering instead of one whose process merely runs
+func Watchdog(ctx context.Context, interval time.Duration, probe func(context.Context) error, logger *zap.Logger) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, interval/2)
+		err := probe(probeCtx)
+		cancel()
+		if err != nil {
+			logger.Warn("Health check failed, skipping watchdog ping", zap.Error(err))
+			continue
+		}
+		if _, err := Notify(StateWatchdog); err != nil {
+			logger.Warn("Watchdog ping failed", zap.Error(err))
+		}
+	}
+}
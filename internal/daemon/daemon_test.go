@@ -0,0 +1,114 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// listenNotify stands in for systemd's notify socket
+func listenNotify(t *testing.T) *net.UnixConn {
+	// Socket paths are short, so not under t.TempDir
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv(NotifySocketEnv, path)
+	return conn
+}
+
+// receive reads one state sent to the notify socket
+func receive(t *testing.T, conn *net.UnixConn) string {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	return string(buf[:n])
+}
+
+func TestNotify(t *testing.T) {
+	t.Setenv(NotifySocketEnv, "")
+	sent, err := Notify(StateReady)
+	require.NoError(t, err)
+	assert.False(t, sent, "nothing to notify outside systemd")
+
+	conn := listenNotify(t)
+	sent, err = Notify(StateReady)
+	require.NoError(t, err)
+	assert.True(t, sent)
+	assert.Equal(t, "READY=1", receive(t, conn))
+
+	t.Setenv(NotifySocketEnv, filepath.Join(t.TempDir(), "missing"))
+	_, err = Notify(StateReady)
+	assert.Error(t, err)
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		usec     string
+		pid      string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "off"},
+		{name: "on", usec: "30000000", expected: 30 * time.Second},
+		{name: "this process", usec: "30000000", pid: strconv.Itoa(os.Getpid()), expected: 30 * time.Second},
+		{name: "another process", usec: "30000000", pid: "1"},
+		{name: "invalid", usec: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(WatchdogUsecEnv, tt.usec)
+			t.Setenv(WatchdogPIDEnv, tt.pid)
+			interval, err := WatchdogInterval()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, interval)
+		})
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	conn := listenNotify(t)
+	var healthy atomic.Bool
+	var probes atomic.Int32
+	probe := func(ctx context.Context) error {
+		probes.Add(1)
+		if !healthy.Load() {
+			return errors.New("not answering")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Watchdog(ctx, 20*time.Millisecond, probe, zaptest.NewLogger(t))
+
+	// No pings while the probe fails
+	require.Eventually(t, func() bool { return probes.Load() >= 3 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Millisecond)))
+	_, err := conn.Read(make([]byte, 256))
+	assert.Error(t, err)
+
+	healthy.Store(true)
+	assert.Equal(t, "WATCHDOG=1", receive(t, conn))
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package daemon
+
+// RunService is a no-op off Windows, where systemd is notified instead
+func RunService(name string, server Server) (bool, error) {
+	return false, nil
+}
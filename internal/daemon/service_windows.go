@@ -0,0 +1,69 @@
+//go:build windows
+
+package daemon
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+// serviceAccepts are the controls the service handles
+const serviceAccepts = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptPauseAndContinue
+
+// RunService runs server under the Windows service control manager when it
+// started the process, returning once the service stops. It reports false,
+// doing nothing, when the process is not a service
+func RunService(name string, server Server) (bool, error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return false, err
+	}
+	return true, svc.Run(name, &serviceHandler{server: server})
+}
+
+// serviceHandler maps service controls onto the server
+type serviceHandler struct {
+	server Server
+}
+
+// Execute serves until a stop or shutdown control, pausing and continuing
+// on request. The service reports running once the server listens
+func (h *serviceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- h.server.Serve(ctx) }()
+
+	ready := h.server.Ready()
+	for {
+		select {
+		case <-ready:
+			ready = nil
+			changes <- svc.Status{State: svc.Running, Accepts: serviceAccepts}
+		case err := <-done:
+			changes <- svc.Status{State: svc.StopPending}
+			if err != nil {
+				// A service-specific exit code, so the manager can restart it
+				return true, 1
+			}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				cancel()
+			case svc.Pause:
+				h.server.SetPaused(true)
+				changes <- svc.Status{State: svc.Paused, Accepts: serviceAccepts}
+			case svc.Continue:
+				h.server.SetPaused(false)
+				changes <- svc.Status{State: svc.Running, Accepts: serviceAccepts}
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+// Rename records a top-level result field that was renamed
+type Rename struct {
+	Tool   string
+	Field  string // current name
+	Legacy string // name before the rename
+	// Omit lists the fields of an object value added since the rename,
+	// left out under the legacy name so it keeps its original shape
+	Omit []string
+	// RemovedIn is the release that stops emitting the legacy name
+	RemovedIn string
+}
+
+// Renames are the renamed result fields still emitted under their old
+// names when compat.emit_legacy_fields is on. The window is fixed per
+// rename rather than configured: drop an entry in the release it names
+var Renames = []Rename{
+	{Tool: "timezone_info", Field: "next_transition", Legacy: "dst_transition", Omit: []string{"kind"}, RemovedIn: "v3.0.0"},
+}
+
+// LegacyFields adds renamed fields back to tool results under their old
+// names, next to the new ones, so clients and prompts written against the
+// old names keep working through a deprecation cycle
+type LegacyFields struct {
+	renames map[string][]Rename
+	logger  *zap.Logger
+}
+
+// NewLegacyFields creates a LegacyFields for a list of renames
+func NewLegacyFields(renames []Rename, logger *zap.Logger) *LegacyFields {
+	byTool := make(map[string][]Rename)
+	for _, r := range renames {
+		byTool[r.Tool] = append(byTool[r.Tool], r)
+	}
+	return &LegacyFields{renames: byTool, logger: logger}
+}
+
+// Middleware returns an MCP receiving middleware that adds legacy fields
+// to tool results and to the output schemas tools/list advertises
+func (l *LegacyFields) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if err != nil {
+				return res, err
+			}
+
+			switch method {
+			case "tools/list":
+				if result, ok := res.(*mcp.ListToolsResult); ok && result != nil {
+					l.addSchemas(result)
+				}
+			case "tools/call":
+				callReq, ok := req.(*mcp.CallToolRequest)
+				result, isResult := res.(*mcp.CallToolResult)
+				if !ok || callReq.Params == nil || !isResult || result == nil {
+					return res, err
+				}
+				renames := l.renames[callReq.Params.Name]
+				if len(renames) == 0 || result.IsError || result.StructuredContent == nil {
+					return res, err
+				}
+				if addErr := addLegacyFields(result, renames); addErr != nil {
+					l.logger.Error("Failed to add legacy fields",
+						zap.String("tool", callReq.Params.Name),
+						zap.Error(addErr))
+					return nil, fmt.Errorf("failed to add legacy fields: %w", addErr)
+				}
+			}
+			return res, err
+		}
+	}
+}
+
+// addLegacyFields copies each renamed field the result has to its old name,
+// after the fields already there
+func addLegacyFields(result *mcp.CallToolResult, renames []Rename) error {
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return fmt.Errorf("failed to decode structured content: %w", err)
+	}
+	obj, ok := v.(*object)
+	if !ok {
+		return nil
+	}
+
+	for _, r := range renames {
+		value, has := obj.values[r.Field]
+		if _, taken := obj.values[r.Legacy]; !has || taken {
+			continue
+		}
+		obj.keys = append(obj.keys, r.Legacy)
+		obj.values[r.Legacy] = omitFields(value, r.Omit)
+	}
+
+	rewritten, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	result.StructuredContent = json.RawMessage(rewritten)
+	return nil
+}
+
+// omitFields copies an object value without the named fields; other values
+// are returned as they are
+func omitFields(value any, names []string) any {
+	obj, ok := value.(*object)
+	if !ok || len(names) == 0 {
+		return value
+	}
+	kept := &object{values: make(map[string]any, len(obj.values))}
+	for _, key := range obj.keys {
+		if !slices.Contains(names, key) {
+			kept.keys = append(kept.keys, key)
+			kept.values[key] = obj.values[key]
+		}
+	}
+	return kept
+}
+
+// addSchemas declares the legacy fields in the output schemas of the
+// listed tools, marked deprecated. Tools and schemas are copied, as the
+// listed ones are shared with the server
+func (l *LegacyFields) addSchemas(result *mcp.ListToolsResult) {
+	for i, tool := range result.Tools {
+		renames := l.renames[tool.Name]
+		schema, ok := tool.OutputSchema.(*jsonschema.Schema)
+		if len(renames) == 0 || !ok || schema == nil {
+			continue
+		}
+
+		s := *schema
+		s.Properties = maps.Clone(schema.Properties)
+		for _, r := range renames {
+			field, has := s.Properties[r.Field]
+			if _, taken := s.Properties[r.Legacy]; !has || taken {
+				continue
+			}
+			legacy := *field
+			if len(r.Omit) > 0 && legacy.Properties != nil {
+				legacy.Properties = maps.Clone(field.Properties)
+				for _, name := range r.Omit {
+					delete(legacy.Properties, name)
+				}
+				legacy.Required = slices.DeleteFunc(slices.Clone(field.Required), func(name string) bool {
+					return slices.Contains(r.Omit, name)
+				})
+			}
+			legacy.Deprecated = true
+			legacy.Description = fmt.Sprintf("Renamed to %s; this name is removed in %s", r.Field, r.RemovedIn)
+			s.Properties[r.Legacy] = &legacy
+		}
+
+		t := *tool
+		t.OutputSchema = &s
+		result.Tools[i] = &t
+	}
+}
@@ -0,0 +1,108 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+var testRenames = []Rename{{Tool: "timezone_info", Field: "next_transition", Legacy: "dst_transition", Omit: []string{"kind"}, RemovedIn: "v3.0.0"}}
+
+func TestLegacyFields_Call(t *testing.T) {
+	tests := []struct {
+		name       string
+		tool       string
+		structured any
+		expected   string
+	}{
+		{
+			name:       "renamed field is emitted under both names",
+			tool:       "timezone_info",
+			structured: map[string]any{"timezone": "UTC", "next_transition": map[string]any{"offset_change": 3600}},
+			expected:   `{"next_transition":{"offset_change":3600},"timezone":"UTC","dst_transition":{"offset_change":3600}}`,
+		},
+		{
+			name: "legacy name keeps its original shape",
+			tool: "timezone_info",
+			structured: map[string]any{"next_transition": map[string]any{
+				"next_transition": "2026-11-01T06:00:00Z", "transition_type": "exit_dst", "kind": "dst_transition", "offset_change": -3600,
+			}},
+			expected: `{"next_transition":{"kind":"dst_transition","next_transition":"2026-11-01T06:00:00Z","offset_change":-3600,"transition_type":"exit_dst"},` +
+				`"dst_transition":{"next_transition":"2026-11-01T06:00:00Z","offset_change":-3600,"transition_type":"exit_dst"}}`,
+		},
+		{
+			name:       "absent field adds nothing",
+			tool:       "timezone_info",
+			structured: map[string]any{"timezone": "UTC"},
+			expected:   `{"timezone":"UTC"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				return &mcp.CallToolResult{StructuredContent: tt.structured}, nil
+			}
+			out, err := NewLegacyFields(testRenames, zaptest.NewLogger(t)).Middleware()(next)(context.Background(), "tools/call",
+				&mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: tt.tool}})
+			require.NoError(t, err)
+
+			raw, ok := out.(*mcp.CallToolResult).StructuredContent.(json.RawMessage)
+			require.True(t, ok)
+			// Compared as text: the legacy name goes after the existing fields
+			assert.Equal(t, tt.expected, string(raw))
+		})
+	}
+}
+
+func TestLegacyFields_OtherTool(t *testing.T) {
+	structured := map[string]any{"next_transition": "2024-03-10T07:00:00Z"}
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{StructuredContent: structured}, nil
+	}
+	out, err := NewLegacyFields(testRenames, zaptest.NewLogger(t)).Middleware()(next)(context.Background(), "tools/call",
+		&mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get_time"}})
+	require.NoError(t, err)
+	assert.Equal(t, structured, out.(*mcp.CallToolResult).StructuredContent)
+}
+
+func TestLegacyFields_List(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"next_transition": {
+				Type:        "object",
+				Description: "next offset change",
+				Properties:  map[string]*jsonschema.Schema{"transition_type": {Type: "string"}, "kind": {Type: "string"}},
+				Required:    []string{"transition_type", "kind"},
+			},
+		},
+	}
+	tool := &mcp.Tool{Name: "timezone_info", OutputSchema: schema}
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.ListToolsResult{Tools: []*mcp.Tool{tool}}, nil
+	}
+	out, err := NewLegacyFields(testRenames, zaptest.NewLogger(t)).Middleware()(next)(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+	require.NoError(t, err)
+
+	listed := out.(*mcp.ListToolsResult).Tools[0].OutputSchema.(*jsonschema.Schema)
+	legacy := listed.Properties["dst_transition"]
+	require.NotNil(t, legacy)
+	assert.True(t, legacy.Deprecated)
+	assert.Equal(t, "object", legacy.Type)
+	assert.Equal(t, "Renamed to next_transition; this name is removed in v3.0.0", legacy.Description)
+	assert.Equal(t, []string{"transition_type"}, legacy.Required)
+	assert.NotContains(t, legacy.Properties, "kind")
+	assert.Contains(t, listed.Properties["next_transition"].Properties, "kind")
+	assert.Equal(t, "next offset change", listed.Properties["next_transition"].Description)
+
+	// The server's own tool and schema are untouched
+	assert.Same(t, schema, tool.OutputSchema)
+	assert.NotContains(t, schema.Properties, "dst_transition")
+	assert.Equal(t, []string{"transition_type", "kind"}, schema.Properties["next_transition"].Required)
+}
@@ -0,0 +1,38 @@
+package envelope
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteResult_KeepsFieldOrder(t *testing.T) {
+	type nested struct {
+		Zone  string `json:"zone"`
+		Start string `json:"start"`
+	}
+	res := &mcp.CallToolResult{
+		StructuredContent: struct {
+			Timezone string   `json:"timezone"`
+			Big      int64    `json:"big"`
+			Windows  []nested `json:"windows"`
+			Empty    []string `json:"empty"`
+			Absent   string   `json:"absent,omitempty"`
+		}{
+			Timezone: "UTC",
+			Big:      9007199254740993,
+			Windows:  []nested{{Zone: "UTC", Start: "2023-12-25T15:30:45Z"}},
+			Empty:    []string{},
+		},
+	}
+
+	err := rewriteResult(res, func(s string) string { return s }, func(key string, v any) any { return v })
+	require.NoError(t, err)
+
+	raw, ok := res.StructuredContent.(json.RawMessage)
+	require.True(t, ok)
+	assert.Equal(t, `{"timezone":"UTC","big":9007199254740993,"windows":[{"zone":"UTC","start":"2023-12-25T15:30:45Z"}],"empty":[]}`, string(raw))
+}
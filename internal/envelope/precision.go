@@ -0,0 +1,274 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+// Precision levels, from coarsest to finest
+const (
+	PrecisionDay    = "day"
+	PrecisionHour   = "hour"
+	PrecisionMinute = "minute"
+	PrecisionSecond = "second"
+	PrecisionMilli  = "milli"
+	PrecisionMicro  = "micro"
+	PrecisionNano   = "nano"
+)
+
+// precisionUnits maps each precision level to the unit instants are truncated to
+var precisionUnits = map[string]time.Duration{
+	PrecisionDay:    24 * time.Hour,
+	PrecisionHour:   time.Hour,
+	PrecisionMinute: time.Minute,
+	PrecisionSecond: time.Second,
+	PrecisionMilli:  time.Millisecond,
+	PrecisionMicro:  time.Microsecond,
+	PrecisionNano:   time.Nanosecond,
+}
+
+// epochFields maps result fields holding epoch integers to their unit
+var epochFields = map[string]time.Duration{
+	"unix_timestamp":    time.Second,
+	"unix_timestamp_ms": time.Millisecond,
+	"unix_timestamp_us": time.Microsecond,
+	"unix_timestamp_ns": time.Nanosecond,
+	"unix_seconds":      time.Second,
+	"unix_ms":           time.Millisecond,
+}
+
+// rfc3339Pattern matches RFC3339 timestamps embedded in text
+var rfc3339Pattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`)
+
+// httpDatePattern matches HTTP-dates (IMF-fixdate) embedded in text
+var httpDatePattern = regexp.MustCompile(`(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} (?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) \d{4} \d{2}:\d{2}:\d{2} GMT`)
+
+// PrecisionCap truncates every instant in tool results to a maximum
+// precision, so privacy-sensitive deployments never return finer times.
+// It rewrites RFC3339 timestamps and HTTP-dates in text and structured
+// content, epoch fields and calendar components, whichever tool produced
+// them. Formats it can't read back, such as custom layouts, are capped with
+// Truncate where they are rendered.
+type PrecisionCap struct {
+	unit   time.Duration
+	logger *zap.Logger
+}
+
+// NewPrecisionCap creates a precision cap for one of the precision levels
+func NewPrecisionCap(precision string, logger *zap.Logger) (*PrecisionCap, error) {
+	unit, ok := precisionUnits[precision]
+	if !ok {
+		return nil, fmt.Errorf("invalid precision %s (must be one of: day, hour, minute, second, milli, micro, nano)", precision)
+	}
+	return &PrecisionCap{unit: unit, logger: logger}, nil
+}
+
+// Middleware returns an MCP receiving middleware that caps the precision of tool results
+func (p *PrecisionCap) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if err != nil || method != "tools/call" {
+				return res, err
+			}
+
+			result, ok := res.(*mcp.CallToolResult)
+			if !ok || result == nil {
+				return res, err
+			}
+
+			if capErr := p.apply(result); capErr != nil {
+				// Never leak uncapped values
+				p.logger.Error("Failed to cap result precision", zap.Error(capErr))
+				return nil, fmt.Errorf("failed to cap result precision: %w", capErr)
+			}
+
+			return result, nil
+		}
+	}
+}
+
+// apply caps the precision of a tool result in place
+func (p *PrecisionCap) apply(result *mcp.CallToolResult) error {
+	return rewriteResult(result, p.capText, p.capValue)
+}
+
+// capValue walks a decoded JSON value, capping instants it recognizes
+func (p *PrecisionCap) capValue(key string, v any) any {
+	switch val := v.(type) {
+	case *object:
+		for _, k := range val.keys {
+			val.values[k] = p.capValue(k, val.values[k])
+		}
+		if isComponents(val.values) {
+			p.capComponents(val.values)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = p.capValue(key, child)
+		}
+		return val
+	case string:
+		if rfc3339Pattern.FindString(val) == val {
+			return p.capRFC3339(val)
+		}
+		if httpDatePattern.FindString(val) == val {
+			return p.capHTTPDate(val)
+		}
+		return val
+	case json.Number:
+		if unit, ok := epochFields[key]; ok {
+			return p.capEpoch(val, unit)
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// capText caps every RFC3339 timestamp and HTTP-date embedded in a text
+func (p *PrecisionCap) capText(text string) string {
+	text = rfc3339Pattern.ReplaceAllStringFunc(text, p.capRFC3339)
+	return httpDatePattern.ReplaceAllStringFunc(text, p.capHTTPDate)
+}
+
+// capHTTPDate truncates an HTTP-date, which is always in GMT
+func (p *PrecisionCap) capHTTPDate(s string) string {
+	t, err := time.Parse(http.TimeFormat, s)
+	if err != nil {
+		return s
+	}
+	return p.Truncate(t).Format(http.TimeFormat)
+}
+
+// capRFC3339 truncates an RFC3339 timestamp on its own wall clock, keeping its offset
+func (p *PrecisionCap) capRFC3339(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+
+	t = p.Truncate(t)
+	if p.unit >= time.Second {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// Truncate zeroes the wall-clock fields of t finer than the cap, keeping
+// its location: a day is capped to the local midnight
+func (p *PrecisionCap) Truncate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	nanos := t.Nanosecond()
+
+	switch {
+	case p.unit >= 24*time.Hour:
+		hour, minute, second, nanos = 0, 0, 0, 0
+	case p.unit >= time.Hour:
+		minute, second, nanos = 0, 0, 0
+	case p.unit >= time.Minute:
+		second, nanos = 0, 0
+	default:
+		nanos -= nanos % int(p.unit)
+	}
+
+	return time.Date(year, month, day, hour, minute, second, nanos, t.Location())
+}
+
+// capEpoch floors an epoch integer in the given unit to the cap
+func (p *PrecisionCap) capEpoch(n json.Number, unit time.Duration) any {
+	if p.unit <= unit {
+		return n
+	}
+	i, err := n.Int64()
+	if err != nil {
+		return n
+	}
+
+	step := int64(p.unit / unit)
+	floored := i - i%step
+	if i < 0 && i%step != 0 {
+		floored -= step
+	}
+	return floored
+}
+
+// isComponents reports whether a map is a calendar components breakdown
+func isComponents(m map[string]any) bool {
+	_, hasYear := m["year"]
+	_, hasDayOfYear := m["day_of_year"]
+	_, hasNanos := m["nanosecond"]
+	return hasYear && hasDayOfYear && hasNanos
+}
+
+// capComponents zeroes calendar components finer than the cap
+func (p *PrecisionCap) capComponents(m map[string]any) {
+	fields := []struct {
+		name string
+		unit time.Duration
+	}{
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+	for _, f := range fields {
+		if f.unit < p.unit {
+			m[f.name] = 0
+		}
+	}
+
+	if nanos, ok := m["nanosecond"].(json.Number); ok && p.unit > time.Nanosecond {
+		if n, err := nanos.Int64(); err == nil {
+			if p.unit >= time.Second {
+				m["nanosecond"] = 0
+			} else {
+				m["nanosecond"] = n - n%int64(p.unit)
+			}
+		}
+	}
+}
+
+// rewriteResult rewrites a tool result in place: text content with text,
+// and structured content with value. Structured content is decoded with
+// objects in field order and numbers kept exact, so rewriting changes
+// nothing but the values
+func rewriteResult(result *mcp.CallToolResult, text func(string) string, value func(key string, v any) any) error {
+	for _, content := range result.Content {
+		if c, ok := content.(*mcp.TextContent); ok {
+			c.Text = text(c.Text)
+		}
+	}
+
+	if result.StructuredContent == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return fmt.Errorf("failed to decode structured content: %w", err)
+	}
+
+	rewritten, err := json.Marshal(value("", v))
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	result.StructuredContent = json.RawMessage(rewritten)
+	return nil
+}
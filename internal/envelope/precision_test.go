@@ -0,0 +1,155 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func callWithResult(t *testing.T, p *PrecisionCap, method string, res *mcp.CallToolResult) *mcp.CallToolResult {
+	t.Helper()
+
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return res, nil
+	}
+	out, err := p.Middleware()(next)(context.Background(), method, &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "tool"}})
+	require.NoError(t, err)
+
+	result, ok := out.(*mcp.CallToolResult)
+	require.True(t, ok)
+	return result
+}
+
+func TestNewPrecisionCap(t *testing.T) {
+	_, err := NewPrecisionCap("minute", zaptest.NewLogger(t))
+	assert.NoError(t, err)
+
+	_, err = NewPrecisionCap("fortnight", zaptest.NewLogger(t))
+	assert.Error(t, err)
+}
+
+func TestPrecisionCap_Middleware(t *testing.T) {
+	structured := func() map[string]any {
+		return map[string]any{
+			"formatted_time":    "2023-12-25T20:59:45.123456789+05:30",
+			"unix_timestamp":    1703518245,
+			"unix_timestamp_ms": 1703518245123,
+			"elapsed_ns":        123456789,
+			"history":           []any{"2023-12-25T15:30:45Z"},
+			"note":              "not a time",
+			"components": map[string]any{
+				"year": 2023, "day_of_year": 359, "hour": 20, "minute": 59, "second": 45, "nanosecond": 123456789,
+			},
+		}
+	}
+
+	tests := []struct {
+		precision string
+		text      string
+		expected  string
+	}{
+		{
+			precision: "minute",
+			text:      "Current time: 2023-12-25T20:59:00+05:30",
+			expected: `{
+				"formatted_time": "2023-12-25T20:59:00+05:30",
+				"unix_timestamp": 1703518200,
+				"unix_timestamp_ms": 1703518200000,
+				"elapsed_ns": 123456789,
+				"history": ["2023-12-25T15:30:00Z"],
+				"note": "not a time",
+				"components": {"year": 2023, "day_of_year": 359, "hour": 20, "minute": 59, "second": 0, "nanosecond": 0}
+			}`,
+		},
+		{
+			precision: "day",
+			text:      "Current time: 2023-12-25T00:00:00+05:30",
+			expected: `{
+				"formatted_time": "2023-12-25T00:00:00+05:30",
+				"unix_timestamp": 1703462400,
+				"unix_timestamp_ms": 1703462400000,
+				"elapsed_ns": 123456789,
+				"history": ["2023-12-25T00:00:00Z"],
+				"note": "not a time",
+				"components": {"year": 2023, "day_of_year": 359, "hour": 0, "minute": 0, "second": 0, "nanosecond": 0}
+			}`,
+		},
+		{
+			precision: "milli",
+			text:      "Current time: 2023-12-25T20:59:45.123+05:30",
+			expected: `{
+				"formatted_time": "2023-12-25T20:59:45.123+05:30",
+				"unix_timestamp": 1703518245,
+				"unix_timestamp_ms": 1703518245123,
+				"elapsed_ns": 123456789,
+				"history": ["2023-12-25T15:30:45Z"],
+				"note": "not a time",
+				"components": {"year": 2023, "day_of_year": 359, "hour": 20, "minute": 59, "second": 45, "nanosecond": 123000000}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.precision, func(t *testing.T) {
+			p, err := NewPrecisionCap(tt.precision, zaptest.NewLogger(t))
+			require.NoError(t, err)
+
+			result := callWithResult(t, p, "tools/call", &mcp.CallToolResult{
+				Content:           []mcp.Content{&mcp.TextContent{Text: "Current time: 2023-12-25T20:59:45.123456789+05:30"}},
+				StructuredContent: structured(),
+			})
+
+			assert.Equal(t, tt.text, result.Content[0].(*mcp.TextContent).Text)
+			raw, ok := result.StructuredContent.(json.RawMessage)
+			require.True(t, ok)
+			assert.JSONEq(t, tt.expected, string(raw))
+		})
+	}
+}
+
+func TestPrecisionCap_HTTPDatesAndEpochs(t *testing.T) {
+	p, err := NewPrecisionCap("hour", zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	result := callWithResult(t, p, "tools/call", &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "Retry at Fri, 16 Oct 2026 08:30:45 GMT"}},
+		StructuredContent: map[string]any{
+			"date":         "Fri, 16 Oct 2026 08:30:45 GMT",
+			"unix_seconds": 1792139445,
+			"unix_ms":      1792139445123,
+		},
+	})
+
+	assert.Equal(t, "Retry at Fri, 16 Oct 2026 08:00:00 GMT", result.Content[0].(*mcp.TextContent).Text)
+	raw, ok := result.StructuredContent.(json.RawMessage)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"date": "Fri, 16 Oct 2026 08:00:00 GMT", "unix_seconds": 1792137600, "unix_ms": 1792137600000}`, string(raw))
+}
+
+func TestPrecisionCap_Truncate(t *testing.T) {
+	p, err := NewPrecisionCap("day", zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := time.Date(2026, 10, 16, 23, 30, 45, 0, loc)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, loc), p.Truncate(at))
+}
+
+func TestPrecisionCap_IgnoresOtherMethods(t *testing.T) {
+	p, err := NewPrecisionCap("hour", zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	text := "2023-12-25T15:30:45Z"
+	result := callWithResult(t, p, "prompts/get", &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	})
+
+	assert.Equal(t, text, result.Content[0].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,148 @@
+package envelope
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+// UTC offset styles
+const (
+	OffsetZ       = "Z"
+	OffsetNumeric = "+00:00"
+)
+
+// Fraction styles. FractionAuto keeps fractions as tools render them
+// (none for whole seconds); the fixed styles always emit that many digits
+const (
+	FractionAuto  = "auto"
+	FractionNone  = "none"
+	FractionMilli = "milli"
+	FractionMicro = "micro"
+	FractionNano  = "nano"
+)
+
+var fractionDigits = map[string]int{
+	FractionAuto:  -1,
+	FractionNone:  0,
+	FractionMilli: 3,
+	FractionMicro: 6,
+	FractionNano:  9,
+}
+
+// rfc3339Parts splits an RFC3339 timestamp into its seconds, fraction and offset
+var rfc3339Parts = regexp.MustCompile(`^(.{19})(\.\d+)?(Z|[+-]\d{2}:\d{2})$`)
+
+// RFC3339Style renders every RFC3339 timestamp in tool results with one
+// offset and fraction style, for downstream parsers strict about either
+type RFC3339Style struct {
+	numericOffset bool
+	digits        int
+	logger        *zap.Logger
+}
+
+// NewRFC3339Style creates a style from a UTC offset style and a fraction
+// style; empty ones mean Z and auto
+func NewRFC3339Style(utcOffset, fraction string, logger *zap.Logger) (*RFC3339Style, error) {
+	if utcOffset == "" {
+		utcOffset = OffsetZ
+	}
+	if fraction == "" {
+		fraction = FractionAuto
+	}
+	if utcOffset != OffsetZ && utcOffset != OffsetNumeric {
+		return nil, fmt.Errorf("invalid utc_offset %s (must be one of: Z, +00:00)", utcOffset)
+	}
+	digits, ok := fractionDigits[fraction]
+	if !ok {
+		return nil, fmt.Errorf("invalid fraction %s (must be one of: auto, none, milli, micro, nano)", fraction)
+	}
+	return &RFC3339Style{numericOffset: utcOffset == OffsetNumeric, digits: digits, logger: logger}, nil
+}
+
+// Middleware returns an MCP receiving middleware that restyles the RFC3339 timestamps in tool results
+func (r *RFC3339Style) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if err != nil || method != "tools/call" {
+				return res, err
+			}
+
+			result, ok := res.(*mcp.CallToolResult)
+			if !ok || result == nil {
+				return res, err
+			}
+
+			if styleErr := rewriteResult(result, r.styleText, r.styleValue); styleErr != nil {
+				r.logger.Error("Failed to restyle result timestamps", zap.Error(styleErr))
+				return nil, fmt.Errorf("failed to restyle result timestamps: %w", styleErr)
+			}
+
+			return result, nil
+		}
+	}
+}
+
+// styleValue walks a decoded JSON value, restyling strings that are
+// RFC3339 timestamps
+func (r *RFC3339Style) styleValue(key string, v any) any {
+	switch val := v.(type) {
+	case *object:
+		for _, k := range val.keys {
+			val.values[k] = r.styleValue(k, val.values[k])
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = r.styleValue(key, child)
+		}
+		return val
+	case string:
+		if rfc3339Pattern.FindString(val) == val {
+			return r.style(val)
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// styleText restyles every RFC3339 timestamp embedded in a text
+func (r *RFC3339Style) styleText(text string) string {
+	return rfc3339Pattern.ReplaceAllStringFunc(text, r.style)
+}
+
+// style rewrites one timestamp. It works on the text rather than parsing
+// it, so the wall clock and offset are kept exactly
+func (r *RFC3339Style) style(s string) string {
+	m := rfc3339Parts.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+	seconds, fraction, offset := m[1], m[2], m[3]
+
+	if r.digits >= 0 {
+		digits := strings.TrimPrefix(fraction, ".")
+		if len(digits) > r.digits {
+			digits = digits[:r.digits]
+		}
+		digits += strings.Repeat("0", r.digits-len(digits))
+		fraction = ""
+		if digits != "" {
+			fraction = "." + digits
+		}
+	}
+
+	switch {
+	case r.numericOffset && offset == "Z":
+		offset = OffsetNumeric
+	case !r.numericOffset && offset == OffsetNumeric:
+		offset = OffsetZ
+	}
+	return seconds + fraction + offset
+}
@@ -0,0 +1,78 @@
+package envelope
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNewRFC3339Style(t *testing.T) {
+	_, err := NewRFC3339Style("", "", zaptest.NewLogger(t))
+	assert.NoError(t, err)
+
+	_, err = NewRFC3339Style("UTC", "auto", zaptest.NewLogger(t))
+	assert.EqualError(t, err, "invalid utc_offset UTC (must be one of: Z, +00:00)")
+
+	_, err = NewRFC3339Style("Z", "centi", zaptest.NewLogger(t))
+	assert.EqualError(t, err, "invalid fraction centi (must be one of: auto, none, milli, micro, nano)")
+}
+
+func TestRFC3339Style_Middleware(t *testing.T) {
+	tests := []struct {
+		utcOffset string
+		fraction  string
+		text      string
+		expected  string
+	}{
+		{
+			utcOffset: "+00:00",
+			fraction:  "auto",
+			text:      "Current time: 2023-12-25T15:30:45+00:00",
+			expected:  `{"time": "2023-12-25T15:30:45.5+00:00", "local": "2023-12-25T21:00:45+05:30", "runs": ["2023-12-26T00:00:00+00:00"], "note": "not a time"}`,
+		},
+		{
+			utcOffset: "Z",
+			fraction:  "milli",
+			text:      "Current time: 2023-12-25T15:30:45.000Z",
+			expected:  `{"time": "2023-12-25T15:30:45.500Z", "local": "2023-12-25T21:00:45.000+05:30", "runs": ["2023-12-26T00:00:00.000Z"], "note": "not a time"}`,
+		},
+		{
+			utcOffset: "Z",
+			fraction:  "none",
+			text:      "Current time: 2023-12-25T15:30:45Z",
+			expected:  `{"time": "2023-12-25T15:30:45Z", "local": "2023-12-25T21:00:45+05:30", "runs": ["2023-12-26T00:00:00Z"], "note": "not a time"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.utcOffset+" "+tt.fraction, func(t *testing.T) {
+			style, err := NewRFC3339Style(tt.utcOffset, tt.fraction, zaptest.NewLogger(t))
+			require.NoError(t, err)
+
+			res := &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: "Current time: 2023-12-25T15:30:45Z"}},
+				StructuredContent: map[string]any{
+					"time":  "2023-12-25T15:30:45.5Z",
+					"local": "2023-12-25T21:00:45+05:30",
+					"runs":  []any{"2023-12-26T00:00:00Z"},
+					"note":  "not a time",
+				},
+			}
+			next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				return res, nil
+			}
+			out, err := style.Middleware()(next)(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "tool"}})
+			require.NoError(t, err)
+
+			result := out.(*mcp.CallToolResult)
+			assert.Equal(t, tt.text, result.Content[0].(*mcp.TextContent).Text)
+			raw, ok := result.StructuredContent.(json.RawMessage)
+			require.True(t, ok)
+			assert.JSONEq(t, tt.expected, string(raw))
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Package extensions serves tools implemented by external programs, so a
+// deployment can add its own tools without forking the server. Each run of
+// an extension program gets one request on stdin and must write one
+// response to stdout. At startup, describe lists the program's tools:
+//
+//	{"method": "describe"}
+//	-> {"tools": [{"name": "free_busy", "description": "...", "input_schema": {"type": "object", ...}}]}
+//
+// and each call of one of them runs the program again with call:
+//
+//	{"method": "call", "tool": "free_busy", "arguments": {...}}
+//	-> {"result": {...}, "text": "..."} or {"error": "calendar unreachable"}
+//
+// Runs are bounded by a timeout and an output size, and get only the
+// environment configured for them.
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/tools"
+)
+
+// Protocol methods, see the package documentation
+const (
+	MethodDescribe = "describe"
+	MethodCall     = "call"
+)
+
+// stderrLimit bounds the stderr kept from a run for error reports
+const stderrLimit = 4 << 10
+
+// request is what an extension program reads from stdin
+type request struct {
+	Method    string          `json:"method"`
+	Tool      string          `json:"tool,omitempty"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// toolSpec describes one tool in a describe response
+type toolSpec struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// describeResponse answers the describe method
+type describeResponse struct {
+	Tools []toolSpec `json:"tools"`
+	Error string     `json:"error"`
+}
+
+// callResponse answers the call method. Result becomes the structured
+// content, and Text the text content; Error makes the call a tool error
+type callResponse struct {
+	Result map[string]any `json:"result"`
+	Text   string         `json:"text"`
+	Error  string         `json:"error"`
+}
+
+// response is a decoded protocol response
+type response interface {
+	// reported is the error the program reported, if any
+	reported() string
+}
+
+func (r *describeResponse) reported() string { return r.Error }
+func (r *callResponse) reported() string     { return r.Error }
+
+// errTimeout reports a run killed because it exceeded its timeout
+var errTimeout = errors.New("timed out")
+
+// reportedError is an error the program itself reported
+type reportedError struct {
+	message string
+}
+
+func (e *reportedError) Error() string {
+	return e.message
+}
+
+// Extension is an external program serving tools
+type Extension struct {
+	cfg     config.ExtensionConfig
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+}
+
+// New creates an extension from its config, applying default limits
+func New(cfg config.ExtensionConfig, metrics *metrics.Metrics, logger *zap.Logger) *Extension {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = config.DefaultExtensionTimeout
+	}
+	if cfg.MaxOutputBytes == 0 {
+		cfg.MaxOutputBytes = config.DefaultExtensionMaxOutputBytes
+	}
+	if cfg.Env == nil {
+		// A nil Env would hand the program the server's environment
+		cfg.Env = []string{}
+	}
+	return &Extension{cfg: cfg, metrics: metrics, logger: logger.With(zap.String("extension", cfg.Name))}
+}
+
+// Load runs describe on each configured extension and returns their tools.
+// reserved holds names already taken, such as the core tools; an extension
+// tool claiming one of them, or a name another extension claimed, is an error
+func Load(ctx context.Context, cfgs []config.ExtensionConfig, reserved map[string]bool, metrics *metrics.Metrics, logger *zap.Logger) ([]tools.ToolProvider, error) {
+	taken := make(map[string]string)
+	var providers []tools.ToolProvider
+	for _, cfg := range cfgs {
+		extension := New(cfg, metrics, logger)
+		described, err := extension.Describe(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("extension %s: %w", cfg.Name, err)
+		}
+		for _, provider := range described {
+			name := provider.Name()
+			if reserved[name] {
+				return nil, fmt.Errorf("extension %s: tool %s is a built-in tool", cfg.Name, name)
+			}
+			if other, ok := taken[name]; ok {
+				return nil, fmt.Errorf("extension %s: tool %s is already served by extension %s", cfg.Name, name, other)
+			}
+			taken[name] = cfg.Name
+			providers = append(providers, provider)
+		}
+		logger.Info("Loaded extension", zap.String("extension", cfg.Name), zap.Int("tools", len(described)))
+	}
+	return providers, nil
+}
+
+// Describe asks the program for its tools
+func (e *Extension) Describe(ctx context.Context) ([]tools.ToolProvider, error) {
+	var resp describeResponse
+	if err := e.run(ctx, "", request{Method: MethodDescribe}, &resp); err != nil {
+		return nil, fmt.Errorf("describe: %w", err)
+	}
+	if len(resp.Tools) == 0 {
+		return nil, errors.New("describe: no tools")
+	}
+
+	providers := make([]tools.ToolProvider, 0, len(resp.Tools))
+	for _, spec := range resp.Tools {
+		if spec.Name == "" {
+			return nil, errors.New("describe: tool without a name")
+		}
+		var schema map[string]any
+		if len(spec.InputSchema) == 0 {
+			schema = map[string]any{"type": "object"}
+		} else if err := json.Unmarshal(spec.InputSchema, &schema); err != nil {
+			return nil, fmt.Errorf("describe: tool %s: input_schema: %w", spec.Name, err)
+		}
+		if schema["type"] != "object" {
+			return nil, fmt.Errorf("describe: tool %s: input_schema must have type \"object\"", spec.Name)
+		}
+		providers = append(providers, &extensionTool{
+			extension: e,
+			tool:      &mcp.Tool{Name: spec.Name, Description: spec.Description, InputSchema: schema},
+		})
+	}
+	return providers, nil
+}
+
+// run executes the program once with req on stdin and decodes its stdout
+// into resp. Runs that outlive the timeout are killed, and an error the
+// program reports is returned as an error. tool labels metrics
+func (e *Extension) run(ctx context.Context, tool string, req request, resp response) error {
+	start := time.Now()
+	err := e.exec(ctx, req, resp)
+	if err == nil && resp.reported() != "" {
+		err = &reportedError{message: resp.reported()}
+	}
+
+	var reported *reportedError
+	status := metrics.StatusSuccess
+	switch {
+	case errors.As(err, &reported):
+		status = metrics.StatusError
+	case errors.Is(err, errTimeout):
+		status = metrics.StatusTimeout
+	case err != nil:
+		status = metrics.StatusInvalid
+	}
+	if tool == "" {
+		tool = req.Method
+	}
+	e.metrics.RecordExtensionCall(e.cfg.Name, tool, status, time.Since(start).Seconds())
+	return err
+}
+
+func (e *Extension) exec(ctx context.Context, req request, resp response) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.cfg.Command[0], e.cfg.Command[1:]...)
+	cmd.Dir = e.cfg.Dir
+	cmd.Env = e.cfg.Env
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	stdout := &limitedBuffer{limit: e.cfg.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: stderrLimit}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Don't wait on pipes held open by the program's own children
+	cmd.WaitDelay = time.Second
+
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w after %s", errTimeout, e.cfg.Timeout)
+	}
+	if stdout.overflow {
+		return fmt.Errorf("response exceeds %d bytes", e.cfg.MaxOutputBytes)
+	}
+	if runErr != nil {
+		if message := bytes.TrimSpace(stderr.Bytes()); len(message) > 0 {
+			return fmt.Errorf("%w: %s", runErr, message)
+		}
+		return runErr
+	}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// extensionTool serves one tool of an extension
+type extensionTool struct {
+	extension *Extension
+	tool      *mcp.Tool
+}
+
+func (t *extensionTool) Name() string {
+	return t.tool.Name
+}
+
+func (t *extensionTool) Schema() *mcp.Tool {
+	return t.tool
+}
+
+// Handle runs the program for a call. Failures to run it, and errors the
+// program reports, are tool errors so the client sees them
+func (t *extensionTool) Handle(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	start := time.Now()
+	name := t.tool.Name
+	e := t.extension
+
+	arguments := req.Params.Arguments
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	var resp callResponse
+	if err := e.run(ctx, name, request{Method: MethodCall, Tool: name, Arguments: arguments}, &resp); err != nil {
+		e.metrics.RecordToolRequestDuration(name, metrics.StatusError, time.Since(start).Seconds())
+		e.logger.Warn("Extension tool failed", zap.String("tool", name), zap.Error(err))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("extension %s: %v", e.cfg.Name, err)}},
+			IsError: true,
+		}, nil
+	}
+	e.metrics.RecordToolRequestDuration(name, metrics.StatusSuccess, time.Since(start).Seconds())
+
+	result := &mcp.CallToolResult{}
+	text := resp.Text
+	if resp.Result != nil {
+		result.StructuredContent = resp.Result
+		if text == "" {
+			raw, _ := json.Marshal(resp.Result)
+			text = string(raw)
+		}
+	}
+	result.Content = []mcp.Content{&mcp.TextContent{Text: text}}
+	return result, nil
+}
+
+// limitedBuffer keeps up to limit bytes and notes whether more were
+// written. The buffer is not embedded so io.Copy can't bypass Write
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.overflow = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		// Report the whole write so the program isn't killed by a broken pipe
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
@@ -0,0 +1,188 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/tools"
+)
+
+// TestHelperExtension is not a test: it is the extension program the
+// tests run, re-executing the test binary with HELPER_EXTENSION set
+func TestHelperExtension(t *testing.T) {
+	mode := os.Getenv("HELPER_EXTENSION")
+	if mode == "" {
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	switch {
+	case mode == "sleep":
+		time.Sleep(time.Minute)
+	case mode == "flood":
+		fmt.Print(strings.Repeat("x", 1<<16))
+	case mode == "crash":
+		fmt.Fprintln(os.Stderr, "calendar unreachable")
+		os.Exit(3)
+	case mode == "array-schema":
+		fmt.Print(`{"tools": [{"name": "team_holiday", "input_schema": {"type": "array"}}]}`)
+	case req.Method == MethodDescribe:
+		fmt.Print(`{"tools": [{"name": "team_holiday", "description": "Check the team holiday calendar", "input_schema": {"type": "object", "properties": {"date": {"type": "string"}}}}]}`)
+	case req.Method == MethodCall:
+		var args struct {
+			Date string `json:"date"`
+		}
+		_ = json.Unmarshal(req.Arguments, &args)
+		if args.Date == "" {
+			fmt.Print(`{"error": "date is required"}`)
+			break
+		}
+		result := map[string]any{"date": args.Date, "holiday": args.Date == "2026-12-25", "env": os.Environ()}
+		_ = json.NewEncoder(os.Stdout).Encode(map[string]any{"result": result})
+	}
+	os.Exit(0)
+}
+
+func helperConfig(mode string) config.ExtensionConfig {
+	return config.ExtensionConfig{
+		Name:    "holidays",
+		Command: []string{os.Args[0], "-test.run=^TestHelperExtension$"},
+		Env:     []string{"HELPER_EXTENSION=" + mode},
+	}
+}
+
+func newMetrics() *metrics.Metrics {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	return metrics.New()
+}
+
+func connect(t *testing.T, providers []tools.ToolProvider) *mcp.ClientSession {
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	tools.RegisterExternalTools(server, providers)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "extensions-test", Version: "test"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestLoad(t *testing.T) {
+	m := newMetrics()
+	providers, err := Load(context.Background(), []config.ExtensionConfig{helperConfig("ok")}, nil, m, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	assert.Equal(t, "team_holiday", providers[0].Name())
+	assert.Equal(t, "Check the team holiday calendar", providers[0].Schema().Description)
+
+	session := connect(t, providers)
+	ctx := context.Background()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "team_holiday", Arguments: map[string]any{"date": "2026-12-25"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	structured, ok := result.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, true, structured["holiday"])
+	// The program sees only its configured environment
+	assert.Equal(t, []any{"HELPER_EXTENSION=ok"}, structured["env"])
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `"holiday":true`)
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "team_holiday", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "extension holidays: date is required", result.Content[0].(*mcp.TextContent).Text)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ExtensionCallsTotal.WithLabelValues("holidays", MethodDescribe, metrics.StatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ExtensionCallsTotal.WithLabelValues("holidays", "team_holiday", metrics.StatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ExtensionCallsTotal.WithLabelValues("holidays", "team_holiday", metrics.StatusError)))
+}
+
+func TestLoad_Errors(t *testing.T) {
+	timeout := helperConfig("sleep")
+	timeout.Timeout = 100 * time.Millisecond
+	flood := helperConfig("flood")
+	flood.MaxOutputBytes = 1024
+	renamed := helperConfig("ok")
+	renamed.Name = "holidays-v2"
+
+	tests := []struct {
+		name     string
+		cfgs     []config.ExtensionConfig
+		reserved map[string]bool
+		wantErr  string
+		status   string
+	}{
+		{
+			name:    "timeout",
+			cfgs:    []config.ExtensionConfig{timeout},
+			wantErr: "extension holidays: describe: timed out after 100ms",
+			status:  metrics.StatusTimeout,
+		},
+		{
+			name:    "output too large",
+			cfgs:    []config.ExtensionConfig{flood},
+			wantErr: "extension holidays: describe: response exceeds 1024 bytes",
+			status:  metrics.StatusInvalid,
+		},
+		{
+			name:    "program fails",
+			cfgs:    []config.ExtensionConfig{helperConfig("crash")},
+			wantErr: "exit status 3: calendar unreachable",
+			status:  metrics.StatusInvalid,
+		},
+		{
+			name:    "input schema not an object",
+			cfgs:    []config.ExtensionConfig{helperConfig("array-schema")},
+			wantErr: `tool team_holiday: input_schema must have type "object"`,
+			status:  metrics.StatusSuccess,
+		},
+		{
+			name:     "built-in tool name",
+			cfgs:     []config.ExtensionConfig{helperConfig("ok")},
+			reserved: map[string]bool{"team_holiday": true},
+			wantErr:  "extension holidays: tool team_holiday is a built-in tool",
+			status:   metrics.StatusSuccess,
+		},
+		{
+			name:    "name served by another extension",
+			cfgs:    []config.ExtensionConfig{helperConfig("ok"), renamed},
+			wantErr: "extension holidays-v2: tool team_holiday is already served by extension holidays",
+			status:  metrics.StatusSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMetrics()
+			_, err := Load(context.Background(), tt.cfgs, tt.reserved, m, zaptest.NewLogger(t))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Equal(t, 1.0, testutil.ToFloat64(m.ExtensionCallsTotal.WithLabelValues("holidays", MethodDescribe, tt.status)))
+		})
+	}
+}
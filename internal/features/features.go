@@ -0,0 +1,94 @@
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Flag names a subsystem that ships dark until a deployment enables it
+type Flag string
+
+// Known feature flags. All of them default to off
+const (
+	// NaturalLanguage enables parsing phrases such as "next Tuesday at 3pm"
+	NaturalLanguage Flag = "natural_language"
+	// Astronomy enables sunrise, sunset and moon phase tools
+	Astronomy Flag = "astronomy"
+	// Scheduler enables tools that schedule and track future work
+	Scheduler Flag = "scheduler"
+)
+
+// Known lists every flag a deployment can set
+var Known = []Flag{NaturalLanguage, Astronomy, Scheduler}
+
+// Flags is the set of feature flags enabled for this deployment. A nil
+// *Flags has every flag off
+type Flags struct {
+	enabled map[Flag]bool
+}
+
+// New builds the flag set from config, where keys are flag names. Unknown
+// names are an error so a typo doesn't leave a feature silently off
+func New(config map[string]bool) (*Flags, error) {
+	if err := Validate(config); err != nil {
+		return nil, err
+	}
+	flags := &Flags{enabled: make(map[Flag]bool)}
+	for name, on := range config {
+		if on {
+			flags.enabled[Flag(name)] = true
+		}
+	}
+	return flags, nil
+}
+
+// Validate reports flag names in config that aren't known
+func Validate(config map[string]bool) error {
+	var unknown []string
+	for name := range config {
+		if !isKnown(Flag(name)) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown feature flags: %s (known: %s)", strings.Join(unknown, ", "), knownNames())
+	}
+	return nil
+}
+
+// Enabled reports whether flag is on
+func (f *Flags) Enabled(flag Flag) bool {
+	return f != nil && f.enabled[flag]
+}
+
+// List returns the enabled flag names, sorted, for logging
+func (f *Flags) List() []string {
+	names := []string{}
+	if f == nil {
+		return names
+	}
+	for flag := range f.enabled {
+		names = append(names, string(flag))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isKnown(flag Flag) bool {
+	for _, known := range Known {
+		if flag == known {
+			return true
+		}
+	}
+	return false
+}
+
+func knownNames() string {
+	names := make([]string, len(Known))
+	for i, flag := range Known {
+		names[i] = string(flag)
+	}
+	return strings.Join(names, ", ")
+}
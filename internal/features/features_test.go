@@ -0,0 +1,44 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]bool
+		enabled []string
+		wantErr string
+	}{
+		{"empty", nil, []string{}, ""},
+		{"all off", map[string]bool{"astronomy": false, "scheduler": false}, []string{}, ""},
+		{"some on", map[string]bool{"scheduler": true, "astronomy": true, "natural_language": false}, []string{"astronomy", "scheduler"}, ""},
+		{"unknown", map[string]bool{"astronmy": true, "sheduler": false}, nil,
+			"unknown feature flags: astronmy, sheduler (known: natural_language, astronomy, scheduler)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := New(tt.config)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.enabled, flags.List())
+			for _, flag := range Known {
+				assert.Equal(t, tt.config[string(flag)], flags.Enabled(flag), flag)
+			}
+		})
+	}
+}
+
+func TestFlags_Nil(t *testing.T) {
+	var flags *Flags
+	assert.False(t, flags.Enabled(Astronomy))
+	assert.Empty(t, flags.List())
+}
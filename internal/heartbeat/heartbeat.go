@@ -0,0 +1,125 @@
+package heartbeat
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Payload is what each heartbeat carries. Sequence counts the heartbeats of
+// one stream from 1, so a client notices heartbeats it missed
+type Payload struct {
+	Time     string `json:"time"`
+	Sequence uint64 `json:"sequence"`
+}
+
+// Wrap writes a heartbeat every cfg.Interval on the SSE streams handler
+// opens, with the server's UTC time. Heartbeats start after the handler's
+// first write, so the stream's headers and endpoint event go first. A zero
+// interval returns handler unchanged
+func Wrap(handler http.Handler, cfg config.HeartbeatConfig, metrics *metrics.Metrics, logger *zap.Logger) http.Handler {
+	if cfg.Interval <= 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		stream := &streamWriter{ResponseWriter: w, started: make(chan struct{})}
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			stream.beat(cfg, stop, metrics, logger)
+		}()
+
+		handler.ServeHTTP(stream, r)
+
+		// Writing after ServeHTTP returns is invalid, so wait for the
+		// heartbeat to stop first
+		close(stop)
+		<-done
+	})
+}
+
+// streamWriter serializes the handler's writes with heartbeats. The SDK
+// writes each event in one Write, so heartbeats fall between events
+type streamWriter struct {
+	http.ResponseWriter
+
+	mu      sync.Mutex
+	once    sync.Once
+	started chan struct{}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	n, err := s.ResponseWriter.Write(p)
+	s.mu.Unlock()
+	s.once.Do(func() { close(s.started) })
+	return n, err
+}
+
+func (s *streamWriter) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	http.NewResponseController(s.ResponseWriter).Flush()
+}
+
+func (s *streamWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// beat writes heartbeats until stop closes, or a write fails because the
+// client went away
+func (s *streamWriter) beat(cfg config.HeartbeatConfig, stop <-chan struct{}, metrics *metrics.Metrics, logger *zap.Logger) {
+	select {
+	case <-s.started:
+	case <-stop:
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	var sequence uint64
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			sequence++
+			if err := s.writeHeartbeat(cfg.Style, Payload{Time: now.UTC().Format(time.RFC3339Nano), Sequence: sequence}); err != nil {
+				logger.Debug("Stopped SSE heartbeat", zap.Uint64("sequence", sequence), zap.Error(err))
+				return
+			}
+			metrics.RecordSSEHeartbeat()
+		}
+	}
+}
+
+// writeHeartbeat writes one heartbeat as an SSE comment or a heartbeat event
+func (s *streamWriter) writeHeartbeat(style string, payload Payload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	frame := ": heartbeat " + string(data) + "\n\n"
+	if style == config.HeartbeatStyleEvent {
+		frame = "event: heartbeat\ndata: " + string(data) + "\n\n"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.ResponseWriter.Write([]byte(frame)); err != nil {
+		return err
+	}
+	return http.NewResponseController(s.ResponseWriter).Flush()
+}
@@ -0,0 +1,92 @@
+package heartbeat
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// streamHandler opens an SSE stream like the SDK: an endpoint event, then
+// nothing until the client leaves
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Write([]byte("event: endpoint\ndata: /sse?sessionid=1\n\n"))
+	w.(http.Flusher).Flush()
+	<-r.Context().Done()
+}
+
+// readFrames reads n SSE frames from the stream
+func readFrames(t *testing.T, server *httptest.Server, n int) []string {
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var frames []string
+	var frame strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for len(frames) < n && scanner.Scan() {
+		if scanner.Text() == "" {
+			frames = append(frames, frame.String())
+			frame.Reset()
+			continue
+		}
+		frame.WriteString(scanner.Text() + "\n")
+	}
+	require.Len(t, frames, n)
+	return frames
+}
+
+func TestWrap(t *testing.T) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	logger := zaptest.NewLogger(t)
+
+	t.Run("comments", func(t *testing.T) {
+		cfg := config.HeartbeatConfig{Interval: 10 * time.Millisecond, Style: config.HeartbeatStyleComment}
+		server := httptest.NewServer(Wrap(http.HandlerFunc(streamHandler), cfg, m, logger))
+		defer server.Close()
+
+		frames := readFrames(t, server, 3)
+		assert.Equal(t, "event: endpoint\ndata: /sse?sessionid=1\n", frames[0])
+		for i, frame := range frames[1:] {
+			data, ok := strings.CutPrefix(frame, ": heartbeat ")
+			require.True(t, ok, frame)
+			var payload Payload
+			require.NoError(t, json.Unmarshal([]byte(data), &payload))
+			assert.Equal(t, uint64(i+1), payload.Sequence)
+			at, err := time.Parse(time.RFC3339Nano, payload.Time)
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now(), at, time.Minute)
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		cfg := config.HeartbeatConfig{Interval: 10 * time.Millisecond, Style: config.HeartbeatStyleEvent}
+		server := httptest.NewServer(Wrap(http.HandlerFunc(streamHandler), cfg, m, logger))
+		defer server.Close()
+
+		frames := readFrames(t, server, 2)
+		assert.True(t, strings.HasPrefix(frames[1], "event: heartbeat\ndata: {\"time\":"), frames[1])
+		assert.True(t, strings.HasSuffix(frames[1], "\"sequence\":1}\n"), frames[1])
+	})
+
+	assert.GreaterOrEqual(t, testutil.ToFloat64(m.SSEHeartbeatsTotal), 3.0)
+
+	t.Run("disabled", func(t *testing.T) {
+		mux := http.NewServeMux()
+		assert.Same(t, mux, Wrap(mux, config.HeartbeatConfig{}, m, logger))
+	})
+}
@@ -0,0 +1,494 @@
+// Package holidays imports public holiday ICS feeds, such as Google's
+// public holiday calendars or national feeds, and keeps them in sync in the
+// background.
+package holidays
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/calendar"
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// maxFeedBytes bounds what is read from a feed
+const maxFeedBytes = 10 << 20
+
+// dateLayout is the layout of holiday dates
+const dateLayout = "2006-01-02"
+
+// maxRangeYears bounds the dates one query covers
+const maxRangeYears = 3
+
+// feed is a configured holiday feed and what was last synced from it
+type feed struct {
+	cfg config.HolidayFeedConfig
+	loc *time.Location
+
+	// Guarded by Importer.mu
+	calendar     *calendar.Calendar
+	etag         string
+	lastModified string
+	syncedAt     time.Time
+	err          string
+}
+
+// cacheMeta is stored next to a cached feed
+type cacheMeta struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	SyncedAt     time.Time `json:"synced_at"`
+}
+
+// Importer syncs the configured holiday feeds and answers holiday queries
+// from the last synced copy of each
+type Importer struct {
+	cfg   config.HolidaysConfig
+	feeds []*feed
+	// defaultTimezone decides what today is when the caller names no zone
+	defaultTimezone string
+	clock           timeservice.Clock
+	loadZone        timeservice.ZoneLoader
+	client          *http.Client
+	userAgent       string
+	metrics         *metrics.Metrics
+	logger          *zap.Logger
+
+	mu sync.RWMutex
+}
+
+// New creates an importer for the configured feeds, loading the copies left
+// in cfg.CacheDir by a previous run. clock provides today's date for
+// queries that don't name a start, and may be nil for the system clock.
+// Zones load with loadZone, such as from a pinned tzdata snapshot, or
+// time.LoadLocation when nil
+func New(cfg config.HolidaysConfig, defaultTimezone string, clock timeservice.Clock, loadZone timeservice.ZoneLoader, version string, metrics *metrics.Metrics, logger *zap.Logger) (*Importer, error) {
+	if loadZone == nil {
+		loadZone = time.LoadLocation
+	}
+	i := &Importer{
+		cfg:             cfg,
+		defaultTimezone: defaultTimezone,
+		clock:           clock,
+		loadZone:        loadZone,
+		client:          &http.Client{Timeout: cfg.Timeout},
+		userAgent:       "mcp-server-time/" + version,
+		metrics:         metrics,
+		logger:          logger,
+	}
+	for _, feedCfg := range cfg.Feeds {
+		timezone := feedCfg.Timezone
+		if timezone == "" {
+			timezone = defaultTimezone
+		}
+		loc, err := loadZone(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("holiday feed %s: %w", feedCfg.Name, err)
+		}
+		f := &feed{cfg: feedCfg, loc: loc}
+		if err := i.loadCache(f); err != nil {
+			logger.Warn("Ignoring cached holiday feed", zap.String("feed", feedCfg.Name), zap.Error(err))
+		}
+		i.feeds = append(i.feeds, f)
+	}
+	return i, nil
+}
+
+// Names returns the configured feed names, in config order
+func (i *Importer) Names() []string {
+	names := make([]string, 0, len(i.feeds))
+	for _, f := range i.feeds {
+		names = append(names, f.cfg.Name)
+	}
+	return names
+}
+
+// Run syncs every feed immediately and then every interval until ctx is done
+func (i *Importer) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		i.Sync(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync revalidates every feed once. A feed that fails keeps serving its
+// last synced copy
+func (i *Importer) Sync(ctx context.Context) {
+	for _, f := range i.feeds {
+		i.syncFeed(ctx, f)
+	}
+}
+
+// syncFeed fetches a feed, sending the validators of the copy held so an
+// unchanged feed is answered with 304 Not Modified
+func (i *Importer) syncFeed(ctx context.Context, f *feed) {
+	i.mu.RLock()
+	etag, lastModified := f.etag, f.lastModified
+	if f.calendar == nil {
+		etag, lastModified = "", ""
+	}
+	i.mu.RUnlock()
+
+	result, err := i.download(ctx, f, etag, lastModified)
+	now := time.Now()
+	if err != nil {
+		i.mu.Lock()
+		f.err = err.Error()
+		i.mu.Unlock()
+		i.metrics.RecordHolidayFeedSync(f.cfg.Name, metrics.StatusError, now)
+		i.logger.Warn("Holiday feed sync failed", zap.String("feed", f.cfg.Name), zap.Error(err))
+		return
+	}
+
+	i.mu.Lock()
+	f.syncedAt, f.err = now, ""
+	if result.calendar != nil {
+		f.calendar, f.etag, f.lastModified = result.calendar, result.etag, result.lastModified
+	}
+	meta := cacheMeta{URL: f.cfg.URL, ETag: f.etag, LastModified: f.lastModified, SyncedAt: now}
+	i.mu.Unlock()
+
+	status := metrics.StatusSuccess
+	if result.calendar == nil {
+		status = metrics.StatusNotModified
+	}
+	i.metrics.RecordHolidayFeedSync(f.cfg.Name, status, now)
+	i.logger.Debug("Holiday feed synced", zap.String("feed", f.cfg.Name), zap.String("status", status))
+
+	if err := i.saveCache(f, meta, result.data); err != nil {
+		i.logger.Warn("Failed to cache holiday feed", zap.String("feed", f.cfg.Name), zap.Error(err))
+	}
+}
+
+// downloaded is a fetched feed; calendar is nil when it was not modified
+type downloaded struct {
+	calendar     *calendar.Calendar
+	data         []byte
+	etag         string
+	lastModified string
+}
+
+// download fetches and parses a feed
+func (i *Importer) download(ctx context.Context, f *feed, etag, lastModified string) (downloaded, error) {
+	ctx, cancel := context.WithTimeout(ctx, i.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.cfg.URL, nil)
+	if err != nil {
+		return downloaded{}, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+	req.Header.Set("User-Agent", i.userAgent)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return downloaded{}, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && (etag != "" || lastModified != ""):
+		return downloaded{}, nil
+	case resp.StatusCode != http.StatusOK:
+		return downloaded{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return downloaded{}, err
+	}
+	if len(data) > maxFeedBytes {
+		return downloaded{}, fmt.Errorf("feed exceeds %d bytes", maxFeedBytes)
+	}
+	cal, err := calendar.ParseICS(bytes.NewReader(data), f.loc, i.loadZone)
+	if err != nil {
+		return downloaded{}, err
+	}
+	return downloaded{
+		calendar:     cal,
+		data:         data,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// cachePaths returns where a feed and its metadata are cached
+func (i *Importer) cachePaths(f *feed) (string, string) {
+	base := filepath.Join(i.cfg.CacheDir, f.cfg.Name)
+	return base + ".ics", base + ".json"
+}
+
+// loadCache restores a feed cached by a previous run. A copy of another
+// URL, after the feed was repointed, is ignored
+func (i *Importer) loadCache(f *feed) error {
+	if i.cfg.CacheDir == "" {
+		return nil
+	}
+	icsPath, metaPath := i.cachePaths(f)
+	metaData, err := os.ReadFile(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var meta cacheMeta
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return err
+	}
+	if meta.URL != f.cfg.URL {
+		return nil
+	}
+	data, err := os.ReadFile(icsPath)
+	if err != nil {
+		return err
+	}
+	cal, err := calendar.ParseICS(bytes.NewReader(data), f.loc, i.loadZone)
+	if err != nil {
+		return err
+	}
+	f.calendar, f.etag, f.lastModified, f.syncedAt = cal, meta.ETag, meta.LastModified, meta.SyncedAt
+	return nil
+}
+
+// saveCache stores a feed's metadata, and its contents when they were
+// downloaded again. Files are replaced whole so a crash never leaves a
+// truncated copy
+func (i *Importer) saveCache(f *feed, meta cacheMeta, data []byte) error {
+	if i.cfg.CacheDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(i.cfg.CacheDir, 0o755); err != nil {
+		return err
+	}
+	icsPath, metaPath := i.cachePaths(f)
+	if data != nil {
+		if err := writeFile(icsPath, data); err != nil {
+			return err
+		}
+	}
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return writeFile(metaPath, metaData)
+}
+
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Holiday is one holiday of one feed
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+	Feed string `json:"feed"`
+}
+
+// FeedStatus is the sync state of a feed
+type FeedStatus struct {
+	Name     string `json:"name"`
+	SyncedAt string `json:"synced_at,omitempty"` // last sync that confirmed the copy held is current
+	// Stale is set when the feed has not been confirmed current for two
+	// sync intervals, or was never synced
+	Stale bool   `json:"stale"`
+	Error string `json:"error,omitempty"` // last sync error, cleared by a successful sync
+}
+
+// Input represents input for listing holidays
+type Input struct {
+	Feeds    []string `json:"feeds,omitempty"`    // feed names, defaults to all
+	Start    string   `json:"start,omitempty"`    // first date, YYYY-MM-DD, defaults to today
+	End      string   `json:"end,omitempty"`      // last date, or the date after it when half-open, defaults to a year after start
+	Timezone string   `json:"timezone,omitempty"` // decides today's date, defaults to the server default
+	// IntervalSemantics reads end as the last date listed (closed, the
+	// default) or as the first date not listed (half_open)
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// Result represents the holidays in a date range
+type Result struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// IntervalSemantics is how end is written: closed or half_open
+	IntervalSemantics string       `json:"interval_semantics"`
+	Holidays          []Holiday    `json:"holidays"`
+	Feeds             []FeedStatus `json:"feeds"`
+}
+
+// Holidays lists the holidays of the selected feeds between two dates,
+// sorted by date. Multi-day holidays are listed once per day
+func (i *Importer) Holidays(input Input) (Result, error) {
+	selected, err := i.selected(input.Feeds)
+	if err != nil {
+		return Result{}, err
+	}
+	semantics, err := timeservice.ResolveIntervalSemantics(input.IntervalSemantics, timeservice.IntervalClosed)
+	if err != nil {
+		return Result{}, err
+	}
+	start, end, err := i.dateRange(input, semantics)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// end is the last date listed; a half-open end is the date after it
+	reported := end
+	if semantics == timeservice.IntervalHalfOpen {
+		reported = end.AddDate(0, 0, 1)
+	}
+	result := Result{Start: start.Format(dateLayout), End: reported.Format(dateLayout), IntervalSemantics: semantics, Holidays: []Holiday{}}
+	staleAfter := time.Now().Add(-2 * i.cfg.Interval)
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, f := range selected {
+		status := FeedStatus{Name: f.cfg.Name, Error: f.err, Stale: f.syncedAt.Before(staleAfter)}
+		if !f.syncedAt.IsZero() {
+			status.SyncedAt = f.syncedAt.UTC().Format(time.RFC3339)
+		}
+		if f.calendar == nil && status.Error == "" {
+			status.Error = "not synced yet"
+		}
+		result.Feeds = append(result.Feeds, status)
+		if f.calendar != nil {
+			result.Holidays = append(result.Holidays, feedHolidays(f, start, end)...)
+		}
+	}
+
+	sort.SliceStable(result.Holidays, func(a, b int) bool {
+		return result.Holidays[a].Date < result.Holidays[b].Date
+	})
+	return result, nil
+}
+
+// feedHolidays lists a feed's holidays between two dates, inclusive. Timed
+// events count on the date they start in the feed's timezone
+func feedHolidays(f *feed, start, end time.Time) []Holiday {
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, f.loc)
+	to := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, f.loc)
+	first, last := start.Format(dateLayout), end.Format(dateLayout)
+
+	var holidays []Holiday
+	seen := make(map[Holiday]bool)
+	add := func(day time.Time, name string) {
+		h := Holiday{Date: day.Format(dateLayout), Name: name, Feed: f.cfg.Name}
+		if h.Date < first || h.Date > last || seen[h] {
+			return
+		}
+		seen[h] = true
+		holidays = append(holidays, h)
+	}
+	for _, occurrence := range f.calendar.Occurrences(from, to) {
+		if !occurrence.AllDay {
+			add(occurrence.Start.In(f.loc), occurrence.Summary)
+			continue
+		}
+		for day := occurrence.Start; day.Before(occurrence.End); day = day.AddDate(0, 0, 1) {
+			add(day, occurrence.Summary)
+		}
+	}
+	return holidays
+}
+
+// today returns today's date in a timezone, or the default one, as a UTC
+// midnight like the dates parsed from queries
+func (i *Importer) today(timezone string) (time.Time, error) {
+	if timezone == "" {
+		timezone = i.defaultTimezone
+	}
+	loc, err := i.loadZone(timezone)
+	if err != nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	now := time.Now()
+	if i.clock != nil {
+		now = i.clock.Now()
+	}
+	today := now.In(loc)
+	return time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// dateRange resolves the queried dates to the first and last date listed.
+// A half-open end equal to start lists no dates
+func (i *Importer) dateRange(input Input, semantics string) (time.Time, time.Time, error) {
+	start, err := i.today(input.Timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if input.Start != "" {
+		if start, err = time.Parse(dateLayout, input.Start); err != nil {
+			return time.Time{}, time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid start %s (must be YYYY-MM-DD)", input.Start)
+		}
+	}
+	end := start.AddDate(1, 0, -1)
+	if input.End != "" {
+		if end, err = time.Parse(dateLayout, input.End); err != nil {
+			return time.Time{}, time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid end %s (must be YYYY-MM-DD)", input.End)
+		}
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "end cannot be before start")
+	}
+	if end.After(start.AddDate(maxRangeYears, 0, 0)) {
+		return time.Time{}, time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "range cannot exceed %d years", maxRangeYears)
+	}
+	if input.End != "" && semantics == timeservice.IntervalHalfOpen {
+		end = end.AddDate(0, 0, -1)
+	}
+	return start, end, nil
+}
+
+// selected returns the named feeds, or all of them
+func (i *Importer) selected(names []string) ([]*feed, error) {
+	if len(names) == 0 {
+		return i.feeds, nil
+	}
+	seen := make(map[string]bool, len(names))
+	var selected []*feed
+	for _, name := range names {
+		index := slices.IndexFunc(i.feeds, func(f *feed) bool { return f.cfg.Name == name })
+		if index < 0 {
+			return nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "unknown holiday feed %q (configured: %s)", name, strings.Join(i.Names(), ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, i.feeds[index])
+		}
+	}
+	return selected, nil
+}
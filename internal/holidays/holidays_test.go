@@ -0,0 +1,269 @@
+package holidays
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+	"github.com/hspedro/mcp-server-time/internal/tzsnapshot"
+)
+
+// today is 2026-10-16 in UTC, and already 2026-10-17 in Auckland
+var today = time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)
+
+const usFeed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261126\r\n" +
+	"DTEND;VALUE=DATE:20261127\r\n" +
+	"SUMMARY:Thanksgiving Day\r\n" +
+	"TRANSP:TRANSPARENT\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20261226\r\n" +
+	"SUMMARY:Christmas Break\r\n" +
+	"TRANSP:TRANSPARENT\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20260101\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:New Year's Day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// feedServer serves usFeed with an ETag, answering 304 to a matching
+// If-None-Match. fail makes it answer 503
+type feedServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []http.Header
+	fail     bool
+}
+
+func newFeedServer(t *testing.T) *feedServer {
+	fs := &feedServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fs.mu.Lock()
+		fs.requests = append(fs.requests, r.Header.Clone())
+		fail := fs.fail
+		fs.mu.Unlock()
+
+		switch {
+		case fail:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Type", "text/calendar")
+			_, _ = io.WriteString(w, usFeed)
+		}
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *feedServer) setFail(fail bool) {
+	fs.mu.Lock()
+	fs.fail = fail
+	fs.mu.Unlock()
+}
+
+func (fs *feedServer) lastRequest() http.Header {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.requests[len(fs.requests)-1]
+}
+
+func newImporter(t *testing.T, url, cacheDir string) (*Importer, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	importer, err := New(config.HolidaysConfig{
+		Interval: time.Hour,
+		Timeout:  time.Second,
+		CacheDir: cacheDir,
+		Feeds:    []config.HolidayFeedConfig{{Name: "us", URL: url + "/us.ics"}},
+	}, "UTC", timeservice.FixedClock{Time: today}, nil, "test", m, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	return importer, m
+}
+
+func dates(holidays []Holiday) []string {
+	var out []string
+	for _, h := range holidays {
+		out = append(out, h.Date+" "+h.Name)
+	}
+	return out
+}
+
+func TestImporter_SyncAndRevalidate(t *testing.T) {
+	server := newFeedServer(t)
+	importer, m := newImporter(t, server.URL, "")
+
+	// Nothing is served before the first sync
+	result, err := importer.Holidays(Input{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Holidays)
+	assert.Equal(t, []FeedStatus{{Name: "us", Stale: true, Error: "not synced yet"}}, result.Feeds)
+
+	importer.Sync(context.Background())
+	assert.Empty(t, server.lastRequest().Get("If-None-Match"))
+
+	result, err = importer.Holidays(Input{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16", result.Start)
+	assert.Equal(t, "2027-10-15", result.End)
+	assert.Equal(t, []string{
+		"2026-11-26 Thanksgiving Day",
+		"2026-12-24 Christmas Break",
+		"2026-12-25 Christmas Break",
+		"2027-01-01 New Year's Day",
+	}, dates(result.Holidays))
+	assert.Equal(t, "us", result.Holidays[0].Feed)
+	assert.False(t, result.Feeds[0].Stale)
+	assert.NotEmpty(t, result.Feeds[0].SyncedAt)
+
+	// An unchanged feed is revalidated with its ETag
+	importer.Sync(context.Background())
+	assert.Equal(t, `"v1"`, server.lastRequest().Get("If-None-Match"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.HolidayFeedSyncsTotal.WithLabelValues("us", metrics.StatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.HolidayFeedSyncsTotal.WithLabelValues("us", metrics.StatusNotModified)))
+	assert.Greater(t, testutil.ToFloat64(m.HolidayFeedLastSync.WithLabelValues("us")), 0.0)
+
+	// A failing feed keeps serving its last copy
+	server.setFail(true)
+	importer.Sync(context.Background())
+	result, err = importer.Holidays(Input{Start: "2026-12-25", End: "2026-12-25"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-12-25 Christmas Break"}, dates(result.Holidays))
+	assert.Equal(t, "unexpected status 503 Service Unavailable", result.Feeds[0].Error)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.HolidayFeedSyncsTotal.WithLabelValues("us", metrics.StatusError)))
+}
+
+func TestImporter_Cache(t *testing.T) {
+	server := newFeedServer(t)
+	dir := t.TempDir()
+
+	first, _ := newImporter(t, server.URL, dir)
+	first.Sync(context.Background())
+
+	// A restarted server serves the cached copy while the feed is down,
+	// and revalidates it rather than downloading it again
+	server.setFail(true)
+	second, _ := newImporter(t, server.URL, dir)
+	result, err := second.Holidays(Input{Start: "2026-11-26", End: "2026-11-26"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2026-11-26 Thanksgiving Day"}, dates(result.Holidays))
+	assert.Empty(t, result.Feeds[0].Error)
+
+	server.setFail(false)
+	second.Sync(context.Background())
+	assert.Equal(t, `"v1"`, server.lastRequest().Get("If-None-Match"))
+
+	// A repointed feed doesn't use the copy of its old URL
+	third, _ := newImporter(t, server.URL+"/moved", dir)
+	result, err = third.Holidays(Input{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Holidays)
+}
+
+// TestImporter_ZoneLoader checks an importer given a snapshot loader loads
+// its zones from the snapshot alone, so the host's Local zone is rejected
+func TestImporter_ZoneLoader(t *testing.T) {
+	snapshot, err := tzsnapshot.Select(tzsnapshot.Latest)
+	require.NoError(t, err)
+	var loaded []string
+	loadZone := func(name string) (*time.Location, error) {
+		loaded = append(loaded, name)
+		return snapshot.LoadLocation(name)
+	}
+	newWith := func(timezone string) (*Importer, error) {
+		prometheus.DefaultRegisterer = prometheus.NewRegistry()
+		return New(config.HolidaysConfig{
+			Interval: time.Hour,
+			Timeout:  time.Second,
+			Feeds:    []config.HolidayFeedConfig{{Name: "us", URL: "http://127.0.0.1/us.ics", Timezone: timezone}},
+		}, "UTC", timeservice.FixedClock{Time: today}, loadZone, "test", metrics.New(), zaptest.NewLogger(t))
+	}
+
+	_, err = newWith("Local")
+	assert.ErrorContains(t, err, "holiday feed us:")
+	assert.ErrorContains(t, err, "has no Local")
+
+	importer, err := newWith("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Local", "America/New_York"}, loaded)
+
+	_, err = importer.Holidays(Input{Timezone: "Local"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidTimezone))
+	result, err := importer.Holidays(Input{Timezone: "Pacific/Auckland"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-17", result.Start)
+	assert.Equal(t, []string{"Local", "America/New_York", "Local", "Pacific/Auckland"}, loaded)
+}
+
+func TestImporter_Holidays(t *testing.T) {
+	server := newFeedServer(t)
+	importer, _ := newImporter(t, server.URL, "")
+	importer.Sync(context.Background())
+
+	t.Run("today follows the timezone", func(t *testing.T) {
+		result, err := importer.Holidays(Input{Timezone: "Pacific/Auckland"})
+		require.NoError(t, err)
+		assert.Equal(t, "2026-10-17", result.Start)
+	})
+
+	t.Run("multi-day holidays are clipped to the range", func(t *testing.T) {
+		result, err := importer.Holidays(Input{Start: "2026-12-25", End: "2027-01-01"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2026-12-25 Christmas Break", "2027-01-01 New Year's Day"}, dates(result.Holidays))
+	})
+
+	t.Run("half-open ends exclude the end date", func(t *testing.T) {
+		result, err := importer.Holidays(Input{Start: "2026-12-25", End: "2027-01-01", IntervalSemantics: "half_open"})
+		require.NoError(t, err)
+		assert.Equal(t, "2027-01-01", result.End)
+		assert.Equal(t, "half_open", result.IntervalSemantics)
+		assert.Equal(t, []string{"2026-12-25 Christmas Break"}, dates(result.Holidays))
+	})
+
+	tests := []struct {
+		name  string
+		input Input
+		err   error
+		want  string
+	}{
+		{"unknown feed", Input{Feeds: []string{"fr"}}, timeerrors.ErrInvalidArgument, `unknown holiday feed "fr" (configured: us)`},
+		{"invalid interval semantics", Input{IntervalSemantics: "inclusive"}, timeerrors.ErrInvalidArgument, `invalid interval_semantics "inclusive"`},
+		{"invalid start", Input{Start: "12/25/2026"}, timeerrors.ErrInvalidArgument, "invalid start 12/25/2026 (must be YYYY-MM-DD)"},
+		{"end before start", Input{Start: "2026-12-25", End: "2026-12-24"}, timeerrors.ErrInvalidArgument, "end cannot be before start"},
+		{"range too long", Input{Start: "2026-01-01", End: "2029-01-02"}, timeerrors.ErrInvalidArgument, "range cannot exceed 3 years"},
+		{"invalid timezone", Input{Timezone: "Mars/Olympus"}, timeerrors.ErrInvalidTimezone, "invalid timezone Mars/Olympus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.Holidays(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.True(t, strings.HasPrefix(err.Error(), tt.want), err.Error())
+		})
+	}
+}
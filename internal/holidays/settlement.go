@@ -0,0 +1,147 @@
+package holidays
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+const (
+	// defaultSettlementDays is the T+2 convention of FX spot and most
+	// bond markets
+	defaultSettlementDays = 2
+	maxSettlementDays     = 30
+)
+
+// SettlementInput represents input for computing a settlement date
+type SettlementInput struct {
+	Trade string `json:"trade,omitempty"` // trade date, YYYY-MM-DD, defaults to today
+	// Days is N in T+N, the business days from trade to settlement;
+	// defaults to 2
+	Days *int `json:"days,omitempty"`
+	// Feeds are the market calendars every business day must be open in,
+	// such as both currencies of an FX pair; defaults to all
+	Feeds []string `json:"feeds,omitempty"`
+	// SettlementFeeds are calendars only the settlement date must be open
+	// in, as USD is for FX spot: a US holiday on T+1 doesn't delay it
+	SettlementFeeds []string `json:"settlement_feeds,omitempty"`
+	Timezone        string   `json:"timezone,omitempty"` // decides today's date, defaults to the server default
+}
+
+// SkippedDay is a day between trade and settlement that is not a
+// business day
+type SkippedDay struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"` // weekend, or the holidays that close it
+}
+
+// SettlementResult represents the settlement date of a trade
+type SettlementResult struct {
+	Trade      string       `json:"trade"`
+	Days       int          `json:"days"`
+	Settlement string       `json:"settlement"`
+	Skipped    []SkippedDay `json:"skipped"`
+	Feeds      []FeedStatus `json:"feeds"`
+	Notes      []string     `json:"notes,omitempty"`
+}
+
+// SettlementDate counts N business days from a trade date. Weekends and the
+// holidays of the selected feeds are skipped; the settlement feeds are only
+// checked on the day the count ends on
+func (i *Importer) SettlementDate(input SettlementInput) (SettlementResult, error) {
+	selected, err := i.selected(input.Feeds)
+	if err != nil {
+		return SettlementResult{}, err
+	}
+	var settlementOnly []*feed
+	if len(input.SettlementFeeds) > 0 {
+		if settlementOnly, err = i.selected(input.SettlementFeeds); err != nil {
+			return SettlementResult{}, err
+		}
+	}
+	days := defaultSettlementDays
+	if input.Days != nil {
+		if days = *input.Days; days < 0 || days > maxSettlementDays {
+			return SettlementResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "days must be between 0 and %d, got %d", maxSettlementDays, days)
+		}
+	}
+	trade, err := i.today(input.Timezone)
+	if err != nil {
+		return SettlementResult{}, err
+	}
+	if input.Trade != "" {
+		if trade, err = time.Parse(dateLayout, input.Trade); err != nil {
+			return SettlementResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid trade %s (must be YYYY-MM-DD)", input.Trade)
+		}
+	}
+
+	// Thirty business days fit in a year however many holidays there are
+	last := trade.AddDate(1, 0, 0)
+	staleAfter := time.Now().Add(-2 * i.cfg.Interval)
+	result := SettlementResult{Trade: trade.Format(dateLayout), Days: days, Skipped: []SkippedDay{}}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	closures := func(feeds []*feed) (map[string][]string, error) {
+		closed := make(map[string][]string)
+		for _, f := range feeds {
+			if f.calendar == nil {
+				return nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "holiday feed %q has not synced yet", f.cfg.Name)
+			}
+			for _, h := range feedHolidays(f, trade, last) {
+				closed[h.Date] = append(closed[h.Date], fmt.Sprintf("%s (%s)", h.Name, h.Feed))
+			}
+		}
+		return closed, nil
+	}
+	closed, err := closures(selected)
+	if err != nil {
+		return SettlementResult{}, err
+	}
+	closedOnSettlement, err := closures(settlementOnly)
+	if err != nil {
+		return SettlementResult{}, err
+	}
+	for _, f := range slices.Concat(selected, settlementOnly) {
+		if slices.ContainsFunc(result.Feeds, func(status FeedStatus) bool { return status.Name == f.cfg.Name }) {
+			continue
+		}
+		status := FeedStatus{Name: f.cfg.Name, Error: f.err, Stale: f.syncedAt.Before(staleAfter)}
+		if !f.syncedAt.IsZero() {
+			status.SyncedAt = f.syncedAt.UTC().Format(time.RFC3339)
+		}
+		result.Feeds = append(result.Feeds, status)
+	}
+
+	reason := func(day time.Time, closed map[string][]string) string {
+		if weekday := day.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			return "weekend"
+		}
+		return strings.Join(closed[day.Format(dateLayout)], ", ")
+	}
+	if why := reason(trade, closed); why != "" {
+		result.Notes = append(result.Notes, fmt.Sprintf("the trade date %s is not a business day (%s); days are still counted from it", result.Trade, why))
+	}
+
+	settlement := trade
+	for counted := 0; counted < days; {
+		settlement = settlement.AddDate(0, 0, 1)
+		if settlement.After(last) {
+			return SettlementResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "no settlement date within a year of %s", result.Trade)
+		}
+		why := reason(settlement, closed)
+		if why == "" && counted == days-1 {
+			why = reason(settlement, closedOnSettlement)
+		}
+		if why != "" {
+			result.Skipped = append(result.Skipped, SkippedDay{Date: settlement.Format(dateLayout), Reason: why})
+			continue
+		}
+		counted++
+	}
+	result.Settlement = settlement.Format(dateLayout)
+	return result, nil
+}
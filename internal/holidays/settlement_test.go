@@ -0,0 +1,171 @@
+package holidays
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+const ukFeed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261225\r\n" +
+	"SUMMARY:Christmas Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261228\r\n" +
+	"SUMMARY:Boxing Day (substitute day)\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// newMarketImporter imports usFeed as "us" and ukFeed as "uk". The "eu"
+// feed is configured but never synced
+func newMarketImporter(t *testing.T) *Importer {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/us.ics":
+			_, _ = io.WriteString(w, usFeed)
+		case "/uk.ics":
+			_, _ = io.WriteString(w, ukFeed)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	importer, err := New(config.HolidaysConfig{
+		Interval: time.Hour,
+		Timeout:  time.Second,
+		Feeds: []config.HolidayFeedConfig{
+			{Name: "us", URL: server.URL + "/us.ics"},
+			{Name: "uk", URL: server.URL + "/uk.ics"},
+			{Name: "eu", URL: server.URL + "/eu.ics"},
+		},
+	}, "UTC", timeservice.FixedClock{Time: today}, nil, "test", metrics.New(), zaptest.NewLogger(t))
+	require.NoError(t, err)
+	importer.Sync(context.Background())
+	return importer
+}
+
+func TestImporter_SettlementDate(t *testing.T) {
+	importer := newMarketImporter(t)
+	days := func(v int) *int { return &v }
+
+	tests := []struct {
+		name       string
+		input      SettlementInput
+		settlement string
+		skipped    []SkippedDay
+	}{
+		{
+			name:       "T+2 from today over a weekend",
+			input:      SettlementInput{Feeds: []string{"us"}},
+			settlement: "2026-10-20",
+			skipped:    []SkippedDay{{Date: "2026-10-17", Reason: "weekend"}, {Date: "2026-10-18", Reason: "weekend"}},
+		},
+		{
+			name:       "holiday skipped",
+			input:      SettlementInput{Trade: "2026-11-25", Days: days(1), Feeds: []string{"us"}},
+			settlement: "2026-11-27",
+			skipped:    []SkippedDay{{Date: "2026-11-26", Reason: "Thanksgiving Day (us)"}},
+		},
+		{
+			name:       "both calendars of a pair",
+			input:      SettlementInput{Trade: "2026-12-23", Feeds: []string{"us", "uk"}},
+			settlement: "2026-12-30",
+			skipped: []SkippedDay{
+				{Date: "2026-12-24", Reason: "Christmas Break (us)"},
+				{Date: "2026-12-25", Reason: "Christmas Break (us), Christmas Day (uk)"},
+				{Date: "2026-12-26", Reason: "weekend"},
+				{Date: "2026-12-27", Reason: "weekend"},
+				{Date: "2026-12-28", Reason: "Boxing Day (substitute day) (uk)"},
+			},
+		},
+		{
+			name:       "settlement feed closed on the settlement date",
+			input:      SettlementInput{Trade: "2026-11-24", Feeds: []string{"uk"}, SettlementFeeds: []string{"us"}},
+			settlement: "2026-11-27",
+			skipped:    []SkippedDay{{Date: "2026-11-26", Reason: "Thanksgiving Day (us)"}},
+		},
+		{
+			name:       "settlement feed closed on T+1",
+			input:      SettlementInput{Trade: "2026-11-25", Feeds: []string{"uk"}, SettlementFeeds: []string{"us"}},
+			settlement: "2026-11-27",
+			skipped:    []SkippedDay{},
+		},
+		{
+			name:       "T+0",
+			input:      SettlementInput{Trade: "2026-11-26", Days: days(0), Feeds: []string{"uk"}},
+			settlement: "2026-11-26",
+			skipped:    []SkippedDay{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := importer.SettlementDate(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.settlement, result.Settlement)
+			assert.Equal(t, tt.skipped, result.Skipped)
+			assert.Empty(t, result.Notes)
+		})
+	}
+
+	t.Run("trade on a weekend", func(t *testing.T) {
+		result, err := importer.SettlementDate(SettlementInput{Trade: "2026-10-17", Days: days(1), Feeds: []string{"uk"}})
+		require.NoError(t, err)
+		assert.Equal(t, "2026-10-19", result.Settlement)
+		require.Len(t, result.Notes, 1)
+		assert.Contains(t, result.Notes[0], "the trade date 2026-10-17 is not a business day (weekend)")
+	})
+
+	t.Run("feeds reported once", func(t *testing.T) {
+		result, err := importer.SettlementDate(SettlementInput{Feeds: []string{"us", "uk"}, SettlementFeeds: []string{"us"}})
+		require.NoError(t, err)
+		require.Len(t, result.Feeds, 2)
+		assert.Equal(t, "us", result.Feeds[0].Name)
+		assert.Equal(t, "uk", result.Feeds[1].Name)
+	})
+}
+
+func TestImporter_SettlementDateErrors(t *testing.T) {
+	importer := newMarketImporter(t)
+	days := func(v int) *int { return &v }
+
+	tests := []struct {
+		name  string
+		input SettlementInput
+		err   error
+		want  string
+	}{
+		{"unknown feed", SettlementInput{Feeds: []string{"fr"}}, timeerrors.ErrInvalidArgument, `unknown holiday feed "fr"`},
+		{"unknown settlement feed", SettlementInput{Feeds: []string{"us"}, SettlementFeeds: []string{"fr"}}, timeerrors.ErrInvalidArgument, `unknown holiday feed "fr"`},
+		{"unsynced feed", SettlementInput{}, timeerrors.ErrInvalidArgument, `holiday feed "eu" has not synced yet`},
+		{"too many days", SettlementInput{Feeds: []string{"us"}, Days: days(31)}, timeerrors.ErrInvalidArgument, "days must be between 0 and 30, got 31"},
+		{"invalid trade", SettlementInput{Feeds: []string{"us"}, Trade: "12/23/2026"}, timeerrors.ErrInvalidArgument, "invalid trade 12/23/2026 (must be YYYY-MM-DD)"},
+		{"invalid timezone", SettlementInput{Feeds: []string{"us"}, Timezone: "Mars/Olympus"}, timeerrors.ErrInvalidTimezone, "invalid timezone Mars/Olympus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := importer.SettlementDate(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
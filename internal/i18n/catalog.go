@@ -0,0 +1,101 @@
+package i18n
+
+import "github.com/hspedro/mcp-server-time/internal/config"
+
+// catalogs translate timeerrors.Errorf formats, keyed by the English
+// format. Translations keep the verbs of the format in the same order, or
+// index them explicitly. English needs no catalog
+var catalogs = map[string]map[string]string{
+	config.LocalePortugueseBR: {
+		"invalid timezone %s: %w":                               "fuso horário inválido %s: %w",
+		"invalid timezone %s":                                   "fuso horário inválido %s",
+		"invalid source timezone %s: %w":                        "fuso horário de origem inválido %s: %w",
+		"invalid destination timezone %s: %w":                   "fuso horário de destino inválido %s: %w",
+		"unsupported format: %s (supported: %v)":                "formato não suportado: %s (suportados: %v)",
+		"layout cannot be empty":                                "o layout não pode ficar vazio",
+		"failed to parse time string %s: %w":                    "não foi possível interpretar a data %s: %w",
+		"failed to parse timestamp string: %w":                  "não foi possível interpretar o timestamp: %w",
+		"invalid timestamp: %w":                                 "timestamp inválido: %w",
+		"invalid timestamp string: %w":                          "texto de timestamp inválido: %w",
+		"invalid %s: %w":                                        "%s inválido: %w",
+		"invalid at: %w":                                        "at inválido: %w",
+		"invalid from: %w":                                      "from inválido: %w",
+		"invalid to: %w":                                        "to inválido: %w",
+		"invalid start: %w":                                     "start inválido: %w",
+		"invalid end: %w":                                       "end inválido: %w",
+		"invalid duration: %w":                                  "duração inválida: %w",
+		"invalid deadline: %w":                                  "prazo inválido: %w",
+		"invalid epoch: %w":                                     "epoch inválido: %w",
+		"invalid epoch unit %q (must be one of: s, ms, us, ns)": "unidade de epoch inválida %q (use s, ms, us ou ns)",
+		"timestamp cannot be empty":                             "o timestamp não pode ficar vazio",
+		"timestamps cannot be empty":                            "a lista de timestamps não pode ficar vazia",
+		"timestamp is required":                                 "o timestamp é obrigatório",
+		"start is required":                                     "start é obrigatório",
+		"start and end are required":                            "start e end são obrigatórios",
+		"format is required":                                    "o formato é obrigatório",
+		"end must be after start":                               "end deve ser posterior a start",
+		"end cannot be before start":                            "end não pode ser anterior a start",
+		"duration cannot be empty":                              "a duração não pode ficar vazia",
+		"value cannot be empty":                                 "o valor não pode ficar vazio",
+		"text cannot be empty":                                  "o texto não pode ficar vazio",
+		"invalid duration %q: %s is too large":                  "duração inválida %q: %s é grande demais",
+		"invalid digit_grouping %q (must be one of: %q)":        "digit_grouping inválido %q (use um de: %q)",
+		"unsupported locale %q (supported: %s, %s, %s)":         "idioma não suportado %q (suportados: %s, %s, %s)",
+		"year %d cannot be written in roman numerals: only years 1 to %d are supported": "o ano %d não pode ser escrito em algarismos romanos: só os anos 1 a %d são suportados",
+		"%s cannot be written out: only years 1 to 9999 are supported":                  "%s não pode ser escrito por extenso: só os anos 1 a 9999 são suportados",
+		"%s plus %s is outside the years 1 to 9999":                                     "%s mais %s fica fora dos anos 1 a 9999",
+		"%s %s is out of range":                    "%s %s está fora do intervalo permitido",
+		"%s %s is past the year 9999":              "%s %s passa do ano 9999",
+		"range cannot exceed %d days":              "o intervalo não pode passar de %d dias",
+		"range cannot exceed %d years":             "o intervalo não pode passar de %d anos",
+		"unknown calendar %q (configured: %s)":     "calendário desconhecido %q (configurados: %s)",
+		"unknown holiday feed %q (configured: %s)": "feed de feriados desconhecido %q (configurados: %s)",
+		"holiday feed %q has not synced yet":       "o feed de feriados %q ainda não foi sincronizado",
+	},
+	config.LocaleSpanish: {
+		"invalid timezone %s: %w":                               "zona horaria no válida %s: %w",
+		"invalid timezone %s":                                   "zona horaria no válida %s",
+		"invalid source timezone %s: %w":                        "zona horaria de origen no válida %s: %w",
+		"invalid destination timezone %s: %w":                   "zona horaria de destino no válida %s: %w",
+		"unsupported format: %s (supported: %v)":                "formato no admitido: %s (admitidos: %v)",
+		"layout cannot be empty":                                "el layout no puede estar vacío",
+		"failed to parse time string %s: %w":                    "no se pudo interpretar la fecha %s: %w",
+		"failed to parse timestamp string: %w":                  "no se pudo interpretar el timestamp: %w",
+		"invalid timestamp: %w":                                 "timestamp no válido: %w",
+		"invalid timestamp string: %w":                          "texto de timestamp no válido: %w",
+		"invalid %s: %w":                                        "%s no válido: %w",
+		"invalid at: %w":                                        "at no válido: %w",
+		"invalid from: %w":                                      "from no válido: %w",
+		"invalid to: %w":                                        "to no válido: %w",
+		"invalid start: %w":                                     "start no válido: %w",
+		"invalid end: %w":                                       "end no válido: %w",
+		"invalid duration: %w":                                  "duración no válida: %w",
+		"invalid deadline: %w":                                  "plazo no válido: %w",
+		"invalid epoch: %w":                                     "epoch no válido: %w",
+		"invalid epoch unit %q (must be one of: s, ms, us, ns)": "unidad de epoch no válida %q (use s, ms, us o ns)",
+		"timestamp cannot be empty":                             "el timestamp no puede estar vacío",
+		"timestamps cannot be empty":                            "la lista de timestamps no puede estar vacía",
+		"timestamp is required":                                 "el timestamp es obligatorio",
+		"start is required":                                     "start es obligatorio",
+		"start and end are required":                            "start y end son obligatorios",
+		"format is required":                                    "el formato es obligatorio",
+		"end must be after start":                               "end debe ser posterior a start",
+		"end cannot be before start":                            "end no puede ser anterior a start",
+		"duration cannot be empty":                              "la duración no puede estar vacía",
+		"value cannot be empty":                                 "el valor no puede estar vacío",
+		"text cannot be empty":                                  "el texto no puede estar vacío",
+		"invalid duration %q: %s is too large":                  "duración no válida %q: %s es demasiado grande",
+		"invalid digit_grouping %q (must be one of: %q)":        "digit_grouping no válido %q (use uno de: %q)",
+		"unsupported locale %q (supported: %s, %s, %s)":         "idioma no admitido %q (admitidos: %s, %s, %s)",
+		"year %d cannot be written in roman numerals: only years 1 to %d are supported": "el año %d no se puede escribir en números romanos: solo se admiten los años 1 a %d",
+		"%s cannot be written out: only years 1 to 9999 are supported":                  "%s no se puede escribir con letras: solo se admiten los años 1 a 9999",
+		"%s plus %s is outside the years 1 to 9999":                                     "%s más %s queda fuera de los años 1 a 9999",
+		"%s %s is out of range":                    "%s %s está fuera del rango admitido",
+		"%s %s is past the year 9999":              "%s %s pasa del año 9999",
+		"range cannot exceed %d days":              "el rango no puede superar %d días",
+		"range cannot exceed %d years":             "el rango no puede superar %d años",
+		"unknown calendar %q (configured: %s)":     "calendario desconocido %q (configurados: %s)",
+		"unknown holiday feed %q (configured: %s)": "feed de festivos desconocido %q (configurados: %s)",
+		"holiday feed %q has not synced yet":       "el feed de festivos %q aún no se ha sincronizado",
+	},
+}
@@ -0,0 +1,197 @@
+// Package i18n renders tool error messages in the session's language.
+// Clients declare the locales they prefer at initialize:
+//
+//	"capabilities": {"experimental": {"time/locale": {"preferred": ["pt-BR", "en"]}}}
+//
+// and tool errors are rendered from the message catalog of the first one
+// supported. Messages missing from a catalog stay in English, and the
+// error code in each error result's _meta never changes with the locale.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// CapabilityKey is the experimental capability both sides use to agree on
+// a locale
+const CapabilityKey = "time/locale"
+
+// Localizer picks each session's locale and makes it available to tools
+type Localizer struct {
+	defaultLocale string
+	logger        *zap.Logger
+}
+
+// New creates a localizer falling back to defaultLocale, English when empty
+func New(defaultLocale string, logger *zap.Logger) *Localizer {
+	if defaultLocale == "" {
+		defaultLocale = config.LocaleEnglish
+	}
+	return &Localizer{defaultLocale: defaultLocale, logger: logger}
+}
+
+// Select returns the first locale the client prefers that has a catalog,
+// matching a bare language such as pt or es-MX to its supported locale.
+// ok is false when the client did not declare one or nothing matches
+func (l *Localizer) Select(params *mcp.InitializeParams) (locale string, ok bool) {
+	if params == nil || params.Capabilities == nil {
+		return "", false
+	}
+	declared, ok := params.Capabilities.Experimental[CapabilityKey].(map[string]any)
+	if !ok {
+		return "", false
+	}
+
+	var preferred []string
+	switch v := declared["preferred"].(type) {
+	case string:
+		preferred = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				preferred = append(preferred, s)
+			}
+		}
+	}
+
+	for _, want := range preferred {
+		if locale, ok := Match(want); ok {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Match returns the supported locale for a language tag: the locale itself,
+// or the one sharing its language
+func Match(tag string) (string, bool) {
+	for _, locale := range config.Locales {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	for _, locale := range config.Locales {
+		if base, _, _ := strings.Cut(locale, "-"); strings.EqualFold(base, language) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Locale returns a session's locale
+func (l *Localizer) Locale(params *mcp.InitializeParams) string {
+	if locale, ok := l.Select(params); ok {
+		return locale
+	}
+	return l.defaultLocale
+}
+
+// Middleware returns an MCP receiving middleware that advertises the
+// locale capability on initialize and passes the session's locale to
+// tool calls in their context
+func (l *Localizer) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			switch method {
+			case "initialize":
+				res, err := next(ctx, method, req)
+				if err != nil {
+					return res, err
+				}
+				if result, ok := res.(*mcp.InitializeResult); ok && result != nil {
+					params, _ := req.GetParams().(*mcp.InitializeParams)
+					l.advertise(result, params)
+				}
+				return res, err
+
+			case "tools/call":
+				if callReq, ok := req.(*mcp.CallToolRequest); ok && callReq.Session != nil {
+					ctx = WithLocale(ctx, l.Locale(callReq.Session.InitializeParams()))
+				}
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// advertise adds the locale capability, and the client's selection if
+// any, to an initialize result
+func (l *Localizer) advertise(result *mcp.InitializeResult, params *mcp.InitializeParams) {
+	capability := map[string]any{
+		"supported": config.Locales,
+		"default":   l.defaultLocale,
+	}
+	if selected, ok := l.Select(params); ok {
+		capability["selected"] = selected
+		l.logger.Debug("Negotiated locale", zap.String("locale", selected))
+	}
+
+	if result.Capabilities == nil {
+		result.Capabilities = &mcp.ServerCapabilities{}
+	}
+	if result.Capabilities.Experimental == nil {
+		result.Capabilities.Experimental = make(map[string]any)
+	}
+	result.Capabilities.Experimental[CapabilityKey] = capability
+}
+
+type localeKey struct{}
+
+// WithLocale returns ctx carrying a locale for tool errors
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale in ctx, English when there is none
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return config.LocaleEnglish
+}
+
+// Localize renders an error's message in locale. Errors made with
+// timeerrors.Errorf are rendered from the locale's catalog, with the errors
+// they wrap localized in turn; anything else keeps its own message
+func Localize(err error, locale string) string {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return err.Error()
+	}
+	return localize(err, catalog)
+}
+
+func localize(err error, catalog map[string]string) string {
+	e, ok := err.(*timeerrors.Error)
+	if !ok {
+		return err.Error()
+	}
+	if e.Format == "" {
+		return localize(e.Err, catalog)
+	}
+
+	format, ok := catalog[e.Format]
+	if !ok {
+		format = e.Format
+	}
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		if argErr, ok := arg.(error); ok {
+			args[i] = localize(argErr, catalog)
+		} else {
+			args[i] = arg
+		}
+	}
+	// Sprintf has no %w; the wrapped errors are strings by now
+	return fmt.Sprintf(strings.ReplaceAll(format, "%w", "%v"), args...)
+}
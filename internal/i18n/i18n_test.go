@@ -0,0 +1,147 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func paramsPreferring(preferred any) *mcp.InitializeParams {
+	return &mcp.InitializeParams{Capabilities: &mcp.ClientCapabilities{
+		Experimental: map[string]any{CapabilityKey: map[string]any{"preferred": preferred}},
+	}}
+}
+
+func TestLocalizer_Select(t *testing.T) {
+	localizer := New(config.LocaleEnglish, zaptest.NewLogger(t))
+
+	tests := []struct {
+		name   string
+		params *mcp.InitializeParams
+		want   string
+		ok     bool
+	}{
+		{"no params", nil, "", false},
+		{"no capability", &mcp.InitializeParams{Capabilities: &mcp.ClientCapabilities{}}, "", false},
+		{"exact locale", paramsPreferring([]any{"pt-BR", "en"}), config.LocalePortugueseBR, true},
+		{"case insensitive", paramsPreferring("PT-br"), config.LocalePortugueseBR, true},
+		{"same language", paramsPreferring([]any{"de", "es-MX"}), config.LocaleSpanish, true},
+		{"nothing supported", paramsPreferring([]any{"de", "fr"}), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := localizer.Select(tt.params)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, config.LocaleEnglish, localizer.Locale(paramsPreferring("de")))
+	assert.Equal(t, config.LocaleSpanish, New(config.LocaleSpanish, zaptest.NewLogger(t)).Locale(nil))
+}
+
+func TestLocalize(t *testing.T) {
+	cause := errors.New("unknown time zone Mars/Olympus")
+	err := timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w",
+		timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", "Mars/Olympus", cause))
+
+	assert.Equal(t, "invalid at: invalid timezone Mars/Olympus: unknown time zone Mars/Olympus", Localize(err, config.LocaleEnglish))
+	// Causes from outside the catalog stay in English
+	assert.Equal(t, "at inválido: fuso horário inválido Mars/Olympus: unknown time zone Mars/Olympus", Localize(err, config.LocalePortugueseBR))
+	assert.Equal(t, "at no válido: zona horaria no válida Mars/Olympus: unknown time zone Mars/Olympus", Localize(err, config.LocaleSpanish))
+
+	// Messages missing from the catalog keep their English format
+	untranslated := timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window %s: %w", "lunch",
+		timeerrors.Errorf(timeerrors.ErrInvalidArgument, "end must be after start"))
+	assert.Equal(t, "window lunch: end debe ser posterior a start", Localize(untranslated, config.LocaleSpanish))
+
+	// Wrapped errors are localized through their cause
+	wrapped := timeerrors.Wrap(timeerrors.ErrParseFailure, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp is required"))
+	assert.Equal(t, "o timestamp é obrigatório", Localize(wrapped, config.LocalePortugueseBR))
+	assert.Equal(t, "boom", Localize(errors.New("boom"), config.LocalePortugueseBR))
+}
+
+func TestCatalogs(t *testing.T) {
+	// Each translation takes the same arguments as its format
+	for locale, catalog := range catalogs {
+		for format, translated := range catalog {
+			assert.Equal(t, verbs(format), verbs(translated), "%s: %q", locale, format)
+		}
+	}
+}
+
+// verbs lists the formatting verbs of a format, in order
+func verbs(format string) []byte {
+	var list []byte
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] == '%' {
+			i++
+			if format[i] != '%' {
+				list = append(list, format[i])
+			}
+		}
+	}
+	return list
+}
+
+func TestLocalizer_Middleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred any
+		locale    string
+	}{
+		{name: "declared", preferred: []any{"pt-BR"}, locale: config.LocalePortugueseBR},
+		{name: "undeclared", locale: config.LocaleEnglish},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+			server.AddTool(&mcp.Tool{Name: "locale", InputSchema: map[string]any{"type": "object"}},
+				func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: FromContext(ctx)}}}, nil
+				})
+			server.AddReceivingMiddleware(New(config.LocaleEnglish, zaptest.NewLogger(t)).Middleware())
+
+			client := mcp.NewClient(&mcp.Implementation{Name: "i18n-test", Version: "test"}, nil)
+			if tt.preferred != nil {
+				client.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+					return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+						if params, ok := req.GetParams().(*mcp.InitializeParams); ok {
+							params.Capabilities.Experimental = paramsPreferring(tt.preferred).Capabilities.Experimental
+						}
+						return next(ctx, method, req)
+					}
+				})
+			}
+
+			serverTransport, clientTransport := mcp.NewInMemoryTransports()
+			ctx := context.Background()
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+			session, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			capability := session.InitializeResult().Capabilities.Experimental[CapabilityKey].(map[string]any)
+			assert.Equal(t, config.LocaleEnglish, capability["default"])
+			if tt.preferred != nil {
+				assert.Equal(t, tt.locale, capability["selected"])
+			} else {
+				assert.NotContains(t, capability, "selected")
+			}
+			res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "locale"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.locale, res.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Package limits rejects oversized tool calls before any tool parses them:
+// long string arguments, large arrays and results past a size cap. It
+// bounds the work a single call can cause, whatever text an agent was
+// talked into sending.
+package limits
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/rpcerror"
+)
+
+// Limit names, as reported in errors
+const (
+	LimitStringLength = "max_string_length"
+	LimitTextLength   = "max_text_length"
+	LimitArrayItems   = "max_array_items"
+	LimitBulkBytes    = "max_bulk_bytes"
+)
+
+// decodedItemBytes estimates the bytes decoding allocates for each array
+// item beyond its JSON text: the interface or slice element holding it and
+// its share of the slice's growth. Measured at about 120 bytes an item
+// decoding timestamps with encoding/json, into interfaces and typed slices
+const decodedItemBytes = 128
+
+// TextFields are arguments holding whole documents rather than single
+// values, held to max_text_length instead of max_string_length
+var TextFields = map[string]bool{
+	"crontab":     true,
+	"jwt":         true,
+	"description": true,
+	"text":        true,
+}
+
+// Violation describes an argument over a limit. It is sent to clients as
+// the data of the invalid params error
+type Violation struct {
+	Limit  string `json:"limit"`
+	Path   string `json:"path"` // e.g. arguments.timestamps[3]
+	Max    int    `json:"max"`
+	Actual int    `json:"actual"`
+}
+
+// Limiter enforces the configured limits on tool calls. Zero limits are off.
+type Limiter struct {
+	cfg     config.LimitsConfig
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+}
+
+// New creates a limiter for the configured limits
+func New(cfg config.LimitsConfig, metrics *metrics.Metrics, logger *zap.Logger) *Limiter {
+	return &Limiter{cfg: cfg, metrics: metrics, logger: logger}
+}
+
+// Middleware returns an MCP receiving middleware that rejects tool calls
+// with oversized arguments, and results larger than max_result_bytes
+func (l *Limiter) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			if v := l.Check(callReq.Params.Arguments); v != nil {
+				l.metrics.RecordError(metrics.ErrorCategoryValidation, metrics.ErrorTypeInvalidRequest)
+				l.logger.Warn("Rejected oversized tool arguments",
+					zap.String("tool", callReq.Params.Name),
+					zap.String("limit", v.Limit),
+					zap.String("path", v.Path),
+					zap.Int("max", v.Max),
+					zap.Int("actual", v.Actual))
+				message := fmt.Sprintf("%s is %d long, more than %s (%d)", v.Path, v.Actual, v.Limit, v.Max)
+				if v.Limit == LimitBulkBytes {
+					message = fmt.Sprintf("%s would take about %d bytes to decode, more than %s (%d)", v.Path, v.Actual, v.Limit, v.Max)
+				}
+				return nil, rpcerror.New(rpcerror.CodeInvalidParams, message, v)
+			}
+
+			res, err := next(ctx, method, req)
+			if err != nil || l.cfg.MaxResultBytes <= 0 {
+				return res, err
+			}
+			result, ok := res.(*mcp.CallToolResult)
+			if !ok || result == nil {
+				return res, err
+			}
+			raw, marshalErr := json.Marshal(result)
+			if marshalErr != nil || len(raw) <= l.cfg.MaxResultBytes {
+				return res, err
+			}
+			l.logger.Warn("Dropped oversized tool result",
+				zap.String("tool", callReq.Params.Name),
+				zap.Int("bytes", len(raw)),
+				zap.Int("max_result_bytes", l.cfg.MaxResultBytes))
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+					"result is %d bytes, more than max_result_bytes (%d); narrow the request", len(raw), l.cfg.MaxResultBytes)}},
+				IsError: true,
+			}, nil
+		}
+	}
+}
+
+// Check returns the first limit the arguments break, or nil. Arguments
+// that would take more than max_bulk_bytes to decode are rejected from
+// their JSON text, before they are decoded here or by the tool
+func (l *Limiter) Check(arguments json.RawMessage) *Violation {
+	if len(arguments) == 0 {
+		return nil
+	}
+	if max := l.cfg.MaxBulkBytes; max > 0 {
+		if n := decodedBytes(arguments); n > max {
+			return &Violation{Limit: LimitBulkBytes, Path: "arguments", Max: int(max), Actual: int(n)}
+		}
+	}
+	var args any
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		// Malformed arguments are the tool's to reject
+		return nil
+	}
+	return l.check("arguments", "", args)
+}
+
+// check walks a decoded argument; field is the name of the closest
+// enclosing object key
+func (l *Limiter) check(path, field string, v any) *Violation {
+	switch val := v.(type) {
+	case string:
+		limit, max := LimitStringLength, l.cfg.MaxStringLength
+		if TextFields[field] {
+			limit, max = LimitTextLength, l.cfg.MaxTextLength
+		}
+		if n := utf8.RuneCountInString(val); max > 0 && n > max {
+			return &Violation{Limit: limit, Path: path, Max: max, Actual: n}
+		}
+	case []any:
+		if max := l.cfg.MaxArrayItems; max > 0 && len(val) > max {
+			return &Violation{Limit: LimitArrayItems, Path: path, Max: max, Actual: len(val)}
+		}
+		for i, item := range val {
+			if v := l.check(path+"["+strconv.Itoa(i)+"]", field, item); v != nil {
+				return v
+			}
+		}
+	case map[string]any:
+		// In key order, so the same call always reports the same violation
+		for _, key := range slices.Sorted(maps.Keys(val)) {
+			if v := l.check(path+"."+key, key, val[key]); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// decodedBytes estimates the bytes decoding a JSON document allocates, from
+// its length and the items of its arrays, without decoding it
+func decodedBytes(data []byte) int64 {
+	type container struct{ array, empty bool }
+	var open []container
+	var items int64
+	inString, escaped := false, false
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			continue
+		}
+		// An array's first item starts at its first byte that doesn't
+		// close it; the others each follow a comma
+		if n := len(open); n > 0 && open[n-1].array && open[n-1].empty && c != ']' {
+			open[n-1].empty = false
+			items++
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '[', '{':
+			open = append(open, container{array: c == '[', empty: true})
+		case ']', '}':
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case ',':
+			if n := len(open); n > 0 && open[n-1].array {
+				items++
+			}
+		}
+	}
+	return int64(len(data)) + items*decodedItemBytes
+}
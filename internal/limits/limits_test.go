@@ -0,0 +1,166 @@
+package limits
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+var testLimits = config.LimitsConfig{MaxStringLength: 10, MaxTextLength: 40, MaxArrayItems: 3, MaxResultBytes: 200}
+
+func newTestLimiter(t *testing.T, cfg config.LimitsConfig) *Limiter {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	return New(cfg, metrics.New(), zaptest.NewLogger(t))
+}
+
+func TestLimiter_Check(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments string
+		expected  *Violation
+	}{
+		{
+			name:      "within limits",
+			arguments: `{"time_string": "2024-01-01", "timestamps": ["a", "b", "c"]}`,
+		},
+		{
+			name:      "long string",
+			arguments: `{"timezone": "America/New_York"}`,
+			expected:  &Violation{Limit: LimitStringLength, Path: "arguments.timezone", Max: 10, Actual: 16},
+		},
+		{
+			name:      "characters, not bytes",
+			arguments: `{"format": "ééééééééé"}`,
+		},
+		{
+			name:      "text field gets the text limit",
+			arguments: `{"crontab": "0 3 * * * /usr/bin/backup"}`,
+		},
+		{
+			name:      "long text field",
+			arguments: `{"crontab": "` + strings.Repeat("x", 41) + `"}`,
+			expected:  &Violation{Limit: LimitTextLength, Path: "arguments.crontab", Max: 40, Actual: 41},
+		},
+		{
+			name:      "too many items",
+			arguments: `{"timestamps": ["a", "b", "c", "d"]}`,
+			expected:  &Violation{Limit: LimitArrayItems, Path: "arguments.timestamps", Max: 3, Actual: 4},
+		},
+		{
+			name:      "long string in an array",
+			arguments: `{"timestamps": ["a", "2024-01-01T00:00:00Z"]}`,
+			expected:  &Violation{Limit: LimitStringLength, Path: "arguments.timestamps[1]", Max: 10, Actual: 20},
+		},
+		{
+			name:      "first violation in key order",
+			arguments: `{"zone": "Europe/Amsterdam", "format": "2006-01-02T15:04"}`,
+			expected:  &Violation{Limit: LimitStringLength, Path: "arguments.format", Max: 10, Actual: 16},
+		},
+	}
+	limiter := newTestLimiter(t, testLimits)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, limiter.Check(json.RawMessage(tt.arguments)))
+		})
+	}
+}
+
+func TestDecodedBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		items    int64
+	}{
+		{name: "no arrays", document: `{"timezone": "UTC"}`},
+		{name: "empty array", document: `{"timestamps": [ ]}`},
+		{name: "items", document: `{"timestamps": ["a", "b", "c"]}`, items: 3},
+		{name: "brackets and commas in strings", document: `{"dates": ["[1, 2]", "\"],", "x"]}`, items: 3},
+		{name: "nested arrays", document: `[[1, 2], [], {"a": [3]}]`, items: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, int64(len(tt.document))+tt.items*decodedItemBytes, decodedBytes([]byte(tt.document)))
+		})
+	}
+}
+
+func TestLimiter_BulkBytes(t *testing.T) {
+	limiter := newTestLimiter(t, config.LimitsConfig{MaxBulkBytes: 1024})
+	items := func(n int) json.RawMessage {
+		return json.RawMessage(`{"timestamps": [` + strings.TrimSuffix(strings.Repeat("1, ", n), ", ") + `]}`)
+	}
+
+	assert.Nil(t, limiter.Check(items(7)))
+	arguments := items(8)
+	assert.Equal(t, &Violation{Limit: LimitBulkBytes, Path: "arguments", Max: 1024, Actual: len(arguments) + 8*decodedItemBytes},
+		limiter.Check(arguments))
+
+	called := false
+	_, err := limiter.Middleware()(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		called = true
+		return &mcp.CallToolResult{}, nil
+	})(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "bucket_timestamps", Arguments: arguments}})
+	require.Error(t, err)
+	assert.False(t, called, "the tool must not decode arguments over the budget")
+	assert.Contains(t, err.Error(), "arguments would take about 1064 bytes to decode, more than max_bulk_bytes (1024)")
+}
+
+func TestLimiter_Off(t *testing.T) {
+	limiter := newTestLimiter(t, config.LimitsConfig{})
+	assert.Nil(t, limiter.Check(json.RawMessage(`{"timezone": "`+strings.Repeat("x", 10000)+`"}`)))
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	called := false
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		called = true
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, nil
+	}
+	handler := newTestLimiter(t, testLimits).Middleware()(next)
+
+	_, err := handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name:      "parse_time",
+		Arguments: json.RawMessage(`{"time_string": "` + strings.Repeat("9", 50) + `"}`),
+	}})
+	require.Error(t, err)
+	assert.False(t, called, "the tool must not see oversized arguments")
+
+	raw, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `{
+		"code": -32602,
+		"message": "arguments.time_string is 50 long, more than max_string_length (10)",
+		"data": {"limit": "max_string_length", "path": "arguments.time_string", "max": 10, "actual": 50}
+	}`, string(raw))
+
+	res, err := handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name:      "parse_time",
+		Arguments: json.RawMessage(`{"time_string": "2024"}`),
+	}})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.False(t, res.(*mcp.CallToolResult).IsError)
+}
+
+func TestLimiter_ResultBytes(t *testing.T) {
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: strings.Repeat("x", 500)}}}, nil
+	}
+	res, err := newTestLimiter(t, testLimits).Middleware()(next)(context.Background(), "tools/call",
+		&mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "bucket_timestamps"}})
+	require.NoError(t, err)
+
+	result := res.(*mcp.CallToolResult)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "more than max_result_bytes (200)")
+}
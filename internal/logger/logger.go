@@ -2,31 +2,46 @@ package logger
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/redact"
 )
 
-// New creates a new zap logger based on the provided configuration
+// New creates a new zap logger based on the provided configuration.
+// With the slog backend, the returned logger writes through a log/slog
+// handler; use Slog to get the slog logger itself. Use Module to get the
+// logger for a package, so logging.module_levels applies to it.
 func New(cfg config.LogConfig) (*zap.Logger, error) {
 	level := parseLogLevel(cfg.Level)
+	modules, lowest := parseModuleLevels(level, cfg.ModuleLevels)
 
-	var logger *zap.Logger
-	var err error
-
-	if cfg.Format == "json" {
-		logger, err = newProductionLogger(level)
-	} else {
-		logger, err = newDevelopmentLogger(level)
+	redactor, err := redact.New(cfg.Redaction)
+	if err != nil {
+		return nil, err
 	}
 
+	out, err := openSinks(cfg.Sinks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build logger: %w", err)
+		return nil, fmt.Errorf("failed to open log sinks: %w", err)
 	}
 
-	return logger, nil
+	var logger *zap.Logger
+	switch {
+	case cfg.Backend == config.LogBackendSlog:
+		logger = newSlogBackedLogger(cfg.Format, lowest, out, redactor)
+	case cfg.Format == "json":
+		logger = newProductionLogger(lowest, out, redactor)
+	default:
+		logger = newDevelopmentLogger(lowest, out, redactor)
+	}
+
+	return withModules(logger, level, modules), nil
 }
 
 // parseLogLevel converts string log level to zapcore.Level
@@ -48,15 +63,53 @@ func parseLogLevel(level string) zapcore.Level {
 }
 
 // newProductionLogger creates a production-ready logger with JSON output
-func newProductionLogger(level zapcore.Level) (*zap.Logger, error) {
+func newProductionLogger(level zapcore.Level, out zapcore.WriteSyncer, redactor *redact.Redactor) *zap.Logger {
 	config := zap.NewProductionConfig()
 	config.Level = zap.NewAtomicLevelAt(level)
-	return config.Build()
+	return buildLogger(config, zapcore.NewJSONEncoder(config.EncoderConfig), out, redactor)
 }
 
 // newDevelopmentLogger creates a development logger with console output
-func newDevelopmentLogger(level zapcore.Level) (*zap.Logger, error) {
+func newDevelopmentLogger(level zapcore.Level, out zapcore.WriteSyncer, redactor *redact.Redactor) *zap.Logger {
 	config := zap.NewDevelopmentConfig()
 	config.Level = zap.NewAtomicLevelAt(level)
-	return config.Build()
+	return buildLogger(config, zapcore.NewConsoleEncoder(config.EncoderConfig), out, redactor)
+}
+
+// buildLogger applies the options zap.Config.Build would, but writes to out
+// instead of the config's output paths and redacts entries if configured
+func buildLogger(config zap.Config, encoder zapcore.Encoder, out zapcore.WriteSyncer, redactor *redact.Redactor) *zap.Logger {
+	core := zapcore.NewCore(encoder, out, config.Level)
+	if redactor != nil {
+		core = &redactCore{Core: core, redactor: redactor}
+	}
+	if config.Sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, config.Sampling.Initial, config.Sampling.Thereafter)
+	}
+
+	opts := []zap.Option{zap.ErrorOutput(zapcore.Lock(os.Stderr)), zap.AddCaller()}
+	if config.Development {
+		opts = append(opts, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
+	} else {
+		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+	return zap.New(core, opts...)
+}
+
+// newSlogBackedLogger creates a zap logger on top of a log/slog handler
+// writing JSON or text to out
+func newSlogBackedLogger(format string, level zapcore.Level, out zapcore.WriteSyncer, redactor *redact.Redactor) *zap.Logger {
+	opts := &slog.HandlerOptions{Level: slogLevel(level)}
+	if redactor != nil {
+		opts.ReplaceAttr = redactAttr(redactor)
+	}
+
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(out, opts)
+	} else {
+		handler = slog.NewTextHandler(out, opts)
+	}
+
+	return zap.New(&slogCore{handler: handler, out: out})
 }
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// moduleCore filters entries to a logger's own level. The wrapped core is
+// enabled at the lowest configured level, so a module can be more verbose
+// than the root logger as well as quieter.
+type moduleCore struct {
+	zapcore.Core
+	level   zapcore.Level
+	modules map[string]zapcore.Level
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *moduleCore) Enabled(level zapcore.Level) bool {
+	return level >= c.level && c.Core.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	return &moduleCore{Core: c.Core.With(fields), level: c.level, modules: c.modules}
+}
+
+// Check implements zapcore.Core
+func (c *moduleCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.level {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// Module returns a child of root named after module, logging at the level
+// set for it in logging.module_levels or at root's level otherwise
+func Module(root *zap.Logger, module string) *zap.Logger {
+	named := root.Named(module)
+	core, ok := root.Core().(*moduleCore)
+	if !ok {
+		return named
+	}
+	level, ok := core.modules[module]
+	if !ok {
+		return named
+	}
+	return named.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return &moduleCore{Core: core.Core, level: level, modules: core.modules}
+	}))
+}
+
+// withModules wraps logger's core so it logs at level and Module can find
+// the per-module overrides
+func withModules(logger *zap.Logger, level zapcore.Level, modules map[string]zapcore.Level) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return &moduleCore{Core: c, level: level, modules: modules}
+	}))
+}
+
+// parseModuleLevels parses logging.module_levels and returns the lowest
+// level across them and the root level
+func parseModuleLevels(root zapcore.Level, levels map[string]string) (map[string]zapcore.Level, zapcore.Level) {
+	modules := make(map[string]zapcore.Level, len(levels))
+	lowest := root
+	for module, name := range levels {
+		level := parseLogLevel(name)
+		modules[module] = level
+		lowest = min(lowest, level)
+	}
+	return modules, lowest
+}
+
+// levelHandler is a slog.Handler that drops records below a minimum level
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+// Enabled implements slog.Handler
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs implements slog.Handler
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+// WithGroup implements slog.Handler
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+func TestModule_Levels(t *testing.T) {
+	modules, lowest := parseModuleLevels(zapcore.InfoLevel, map[string]string{
+		config.LogModuleTransport: "warn",
+		config.LogModuleTime:      "debug",
+	})
+	assert.Equal(t, zapcore.DebugLevel, lowest)
+
+	core, logs := observer.New(lowest)
+	root := withModules(zap.New(core), zapcore.InfoLevel, modules)
+
+	transport := Module(root, config.LogModuleTransport)
+	timeLogger := Slog(Module(root, config.LogModuleTime))
+	tools := Module(root, config.LogModuleTools)
+
+	root.Debug("root debug")
+	root.Info("root info")
+	transport.Info("transport info")
+	transport.Warn("transport warn")
+	timeLogger.Debug("time debug")
+	tools.Debug("tools debug")
+	tools.Info("tools info")
+
+	var got []string
+	for _, entry := range logs.All() {
+		got = append(got, entry.LoggerName+": "+entry.Message)
+	}
+	assert.Equal(t, []string{
+		": root info",
+		"transport: transport warn",
+		"time: time debug",
+		"tools: tools info",
+	}, got)
+}
+
+func TestModule_WithFieldsKeepsLevel(t *testing.T) {
+	modules, lowest := parseModuleLevels(zapcore.InfoLevel, map[string]string{config.LogModuleTransport: "error"})
+	core, logs := observer.New(lowest)
+	root := withModules(zap.New(core), zapcore.InfoLevel, modules)
+
+	transport := Module(root, config.LogModuleTransport).With(zap.String("session", "abc"))
+	transport.Warn("dropped")
+	transport.Error("kept")
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "kept", entries[0].Message)
+	assert.Equal(t, "abc", entries[0].ContextMap()["session"])
+}
+
+func TestModule_PlainZapLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	Module(zap.New(core), config.LogModuleChaos).Info("named")
+
+	assert.Equal(t, "chaos", logs.All()[0].LoggerName)
+}
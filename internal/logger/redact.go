@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hspedro/mcp-server-time/internal/redact"
+)
+
+// redactCore is a zapcore.Core that redacts messages and fields before they
+// are encoded. It wraps the I/O core directly, below sampling, so its Check
+// only has to consult the level.
+type redactCore struct {
+	zapcore.Core
+	redactor *redact.Redactor
+}
+
+// With implements zapcore.Core
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(redactFields(c.redactor, fields)), redactor: c.redactor}
+}
+
+// Check implements zapcore.Core
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.Text(entry.Message)
+	return c.Core.Write(entry, redactFields(c.redactor, fields))
+}
+
+// redactFields hides sensitive fields and pattern matches in string and error fields
+func redactFields(r *redact.Redactor, fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch {
+		case r.IsSensitive(field.Key):
+			redacted[i] = zap.String(field.Key, redact.Placeholder)
+		case field.Type == zapcore.StringType:
+			redacted[i] = zap.String(field.Key, r.Text(field.String))
+		case field.Type == zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				redacted[i] = zap.String(field.Key, r.Text(err.Error()))
+			} else {
+				redacted[i] = field
+			}
+		default:
+			redacted[i] = field
+		}
+	}
+	return redacted
+}
+
+// redactAttr is a slog.HandlerOptions.ReplaceAttr function applying the same
+// rules as redactCore, including to the message
+func redactAttr(r *redact.Redactor) func(groups []string, attr slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey) {
+			return attr
+		}
+		if r.IsSensitive(attr.Key) {
+			return slog.String(attr.Key, redact.Placeholder)
+		}
+		switch value := attr.Value.Resolve(); {
+		case value.Kind() == slog.KindString:
+			return slog.String(attr.Key, r.Text(value.String()))
+		case value.Kind() == slog.KindAny:
+			if err, ok := value.Any().(error); ok {
+				return slog.String(attr.Key, r.Text(err.Error()))
+			}
+		}
+		return attr
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/redact"
+)
+
+func TestRedaction(t *testing.T) {
+	redactor, err := redact.New(config.RedactionConfig{
+		Fields:   []string{"time_string"},
+		Patterns: []string{`patient-\d+`},
+	})
+	require.NoError(t, err)
+
+	loggers := map[string]func(out zapcore.WriteSyncer) *zap.Logger{
+		"zap": func(out zapcore.WriteSyncer) *zap.Logger {
+			return newProductionLogger(zapcore.InfoLevel, out, redactor)
+		},
+		"slog": func(out zapcore.WriteSyncer) *zap.Logger {
+			return newSlogBackedLogger("json", zapcore.InfoLevel, out, redactor)
+		},
+	}
+
+	for name, build := range loggers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := build(zapcore.AddSync(&buf))
+
+			logger.With(zap.String("time_string", "2023-12-25")).Info("parsed for patient-1",
+				zap.String("note", "patient-22 admitted"),
+				zap.Error(errors.New("lookup failed for patient-333")),
+				zap.String("timezone", "UTC"))
+			Slog(logger).Info("slog path", "time_string", "2024-01-01")
+
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			require.Len(t, lines, 2)
+
+			var first, second map[string]any
+			require.NoError(t, json.Unmarshal(lines[0], &first))
+			require.NoError(t, json.Unmarshal(lines[1], &second))
+
+			assert.Equal(t, "parsed for [REDACTED]", first["msg"])
+			assert.Equal(t, "[REDACTED]", first["time_string"])
+			assert.Equal(t, "[REDACTED] admitted", first["note"])
+			assert.Equal(t, "lookup failed for [REDACTED]", first["error"])
+			assert.Equal(t, "UTC", first["timezone"])
+			assert.Equal(t, "[REDACTED]", second["time_string"])
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// rotatedSuffixLayout is appended to rotated file names
+const rotatedSuffixLayout = "20060102T150405.000"
+
+// rotatingFile is a log file that is rotated once it grows past maxSize
+// bytes or has been open for maxAge. Rotated files are renamed to
+// <path>.<timestamp> and only the newest maxBackups are kept.
+type rotatingFile struct {
+	path       string
+	maxSize    int64         // 0 disables size-based rotation
+	maxAge     time.Duration // 0 disables age-based rotation
+	maxBackups int           // 0 keeps every rotated file
+	now        func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// openRotatingFile opens or creates the log file at path, appending to it
+func openRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write implements io.Writer, rotating first if p would exceed a limit
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the current file to disk
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Sync()
+}
+
+// Close closes the current file
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// shouldRotate reports whether writing n more bytes needs a new file.
+// An empty file is never rotated, so oversized writes still land somewhere.
+func (r *rotatingFile) shouldRotate(n int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+n > r.maxSize {
+		return true
+	}
+	return r.maxAge > 0 && r.now().Sub(r.openedAt) >= r.maxAge
+}
+
+// open opens the log file for appending
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = r.now()
+	return nil
+}
+
+// rotate renames the current file aside, opens a fresh one and prunes backups
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	rotated := r.path + "." + r.now().UTC().Format(rotatedSuffixLayout)
+	if err := os.Rename(r.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	return r.prune()
+}
+
+// prune removes the oldest rotated files beyond maxBackups
+func (r *rotatingFile) prune() error {
+	if r.maxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(r.path + ".*")
+	if err != nil {
+		return err
+	}
+	// The timestamp suffix sorts chronologically
+	slices.Sort(backups)
+	for len(backups) > r.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return fmt.Errorf("failed to remove old log file: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
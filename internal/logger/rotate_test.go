@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRotatingFile opens a rotating file in a temp dir with a controllable clock
+func newTestRotatingFile(t *testing.T, maxSize int64, maxAge time.Duration, maxBackups int) (*rotatingFile, *time.Time) {
+	path := filepath.Join(t.TempDir(), "logs", "server.log")
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+
+	r, err := openRotatingFile(path, maxSize, maxAge, maxBackups)
+	require.NoError(t, err)
+	r.now = func() time.Time { return now }
+	r.openedAt = now
+	t.Cleanup(func() { r.Close() })
+	return r, &now
+}
+
+func rotatedFiles(t *testing.T, r *rotatingFile) []string {
+	files, err := filepath.Glob(r.path + ".*")
+	require.NoError(t, err)
+	return files
+}
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	r, now := newTestRotatingFile(t, 10, 0, 0)
+
+	_, err := r.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+	assert.Empty(t, rotatedFiles(t, r))
+
+	*now = now.Add(time.Second)
+	_, err = r.Write([]byte("abc\n"))
+	require.NoError(t, err)
+
+	rotated := rotatedFiles(t, r)
+	require.Len(t, rotated, 1)
+	assert.Equal(t, r.path+".20231225T153046.000", rotated[0])
+
+	old, err := os.ReadFile(rotated[0])
+	require.NoError(t, err)
+	assert.Equal(t, "12345678\n", string(old))
+
+	current, err := os.ReadFile(r.path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc\n", string(current))
+}
+
+func TestRotatingFile_OversizedWriteToEmptyFile(t *testing.T) {
+	r, _ := newTestRotatingFile(t, 4, 0, 0)
+
+	_, err := r.Write([]byte("longer than the limit\n"))
+	require.NoError(t, err)
+	assert.Empty(t, rotatedFiles(t, r))
+}
+
+func TestRotatingFile_RotatesByAge(t *testing.T) {
+	r, now := newTestRotatingFile(t, 0, time.Hour, 0)
+
+	_, err := r.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	*now = now.Add(59 * time.Minute)
+	_, err = r.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Empty(t, rotatedFiles(t, r))
+
+	*now = now.Add(time.Minute)
+	_, err = r.Write([]byte("third\n"))
+	require.NoError(t, err)
+	assert.Len(t, rotatedFiles(t, r), 1)
+}
+
+func TestRotatingFile_PrunesBackups(t *testing.T) {
+	r, now := newTestRotatingFile(t, 1, 0, 2)
+
+	for i := 0; i < 5; i++ {
+		*now = now.Add(time.Second)
+		_, err := r.Write([]byte("x\n"))
+		require.NoError(t, err)
+	}
+
+	rotated := rotatedFiles(t, r)
+	assert.Equal(t, []string{
+		r.path + ".20231225T153049.000",
+		r.path + ".20231225T153050.000",
+	}, rotated)
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	require.NoError(t, os.WriteFile(path, []byte("before restart\n"), 0o644))
+
+	r, err := openRotatingFile(path, 0, 0, 0)
+	require.NoError(t, err)
+	_, err = r.Write([]byte("after restart\n"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "before restart\nafter restart\n", string(data))
+}
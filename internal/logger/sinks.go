@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+// openSinks opens every configured sink and combines them into a single
+// writer. No sinks means stderr, zap's and slog's usual destination.
+func openSinks(sinks []config.LogSinkConfig) (zapcore.WriteSyncer, error) {
+	if len(sinks) == 0 {
+		return zapcore.Lock(os.Stderr), nil
+	}
+
+	syncers := make([]zapcore.WriteSyncer, 0, len(sinks))
+	for i, sink := range sinks {
+		writer, err := openSink(sink)
+		if err != nil {
+			return nil, fmt.Errorf("logging.sinks[%d]: %w", i, err)
+		}
+		syncers = append(syncers, writer)
+	}
+	return zapcore.NewMultiWriteSyncer(syncers...), nil
+}
+
+// openSink opens a single sink
+func openSink(sink config.LogSinkConfig) (zapcore.WriteSyncer, error) {
+	switch sink.Type {
+	case config.LogSinkStdout:
+		return zapcore.Lock(os.Stdout), nil
+	case config.LogSinkStderr:
+		return zapcore.Lock(os.Stderr), nil
+	case config.LogSinkFile:
+		file, err := openRotatingFile(sink.Path, int64(sink.MaxSizeMB)<<20, sink.MaxAge, sink.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	case config.LogSinkSyslog:
+		writer, err := openSyslog(sink)
+		if err != nil {
+			return nil, err
+		}
+		return zapcore.AddSync(writer), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %s", sink.Type)
+	}
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+func TestNew_MultipleSinks(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.log")
+	second := filepath.Join(dir, "b.log")
+
+	for _, backend := range []string{config.LogBackendZap, config.LogBackendSlog} {
+		t.Run(backend, func(t *testing.T) {
+			logger, err := New(config.LogConfig{
+				Level:   "info",
+				Format:  "json",
+				Backend: backend,
+				Sinks: []config.LogSinkConfig{
+					{Type: config.LogSinkFile, Path: first},
+					{Type: config.LogSinkFile, Path: second},
+				},
+			})
+			require.NoError(t, err)
+
+			logger.Info("hello " + backend)
+			require.NoError(t, logger.Sync())
+
+			for _, path := range []string{first, second} {
+				assert.Equal(t, "hello "+backend, lastMessage(t, path))
+			}
+		})
+	}
+}
+
+func TestNew_InvalidSink(t *testing.T) {
+	_, err := New(config.LogConfig{
+		Level:   "info",
+		Format:  "json",
+		Backend: config.LogBackendZap,
+		Sinks:   []config.LogSinkConfig{{Type: config.LogSinkFile, Path: filepath.Join(t.TempDir(), "dir")}, {Type: "kafka"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logging.sinks[1]")
+}
+
+// lastMessage returns the message of the last JSON log line in path
+func lastMessage(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var last map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &last))
+	}
+	require.NotNil(t, last, "no log lines in %s", path)
+
+	// zap writes "msg", slog writes "msg" too
+	return last["msg"].(string)
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Slog returns a *slog.Logger that writes to the same sink and at the same
+// level as z. Loggers built by New with the slog backend hand back their
+// slog handler directly; any other zap logger is wrapped in a zap-backed
+// slog.Handler. The zap logger name is kept as the "logger" attribute.
+func Slog(z *zap.Logger) *slog.Logger {
+	if core, ok := z.Core().(*moduleCore); ok {
+		if base, ok := core.Core.(*slogCore); ok {
+			var handler slog.Handler = &levelHandler{Handler: base.handler, level: slogLevel(core.level)}
+			if z.Name() != "" {
+				handler = handler.WithAttrs([]slog.Attr{slog.String("logger", z.Name())})
+			}
+			return slog.New(handler)
+		}
+	}
+	return slog.New(&zapHandler{core: z.Core(), name: z.Name()})
+}
+
+// zapHandler is a slog.Handler that writes records to a zap core
+type zapHandler struct {
+	core zapcore.Core
+	name string
+}
+
+// Enabled implements slog.Handler
+func (h *zapHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(zapLevel(level))
+}
+
+// Handle implements slog.Handler
+func (h *zapHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := make([]zap.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = append(fields, zapField(attr))
+		return true
+	})
+
+	entry := zapcore.Entry{
+		LoggerName: h.name,
+		Level:      zapLevel(record.Level),
+		Time:       record.Time,
+		Message:    record.Message,
+	}
+	if checked := h.core.Check(entry, nil); checked != nil {
+		checked.Write(fields...)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *zapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, zapField(attr))
+	}
+	return &zapHandler{core: h.core.With(fields), name: h.name}
+}
+
+// WithGroup implements slog.Handler by nesting later fields under name
+func (h *zapHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &zapHandler{core: h.core.With([]zap.Field{zap.Namespace(name)}), name: h.name}
+}
+
+// zapField converts a slog attribute into a zap field
+func zapField(attr slog.Attr) zap.Field {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return zap.String(attr.Key, value.String())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, value.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, value.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, value.Float64())
+	case slog.KindBool:
+		return zap.Bool(attr.Key, value.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, value.Duration())
+	case slog.KindTime:
+		return zap.Time(attr.Key, value.Time())
+	case slog.KindGroup:
+		group := value.Group()
+		fields := make([]zap.Field, 0, len(group))
+		for _, member := range group {
+			fields = append(fields, zapField(member))
+		}
+		return zap.Dict(attr.Key, fields...)
+	default:
+		if err, ok := value.Any().(error); ok {
+			return zap.NamedError(attr.Key, err)
+		}
+		return zap.Any(attr.Key, value.Any())
+	}
+}
+
+// zapLevel maps a slog level onto the nearest zap level
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// slogCore is a zapcore.Core that writes entries to a slog.Handler, so the
+// zap-based packages log through slog when logging.backend is slog
+type slogCore struct {
+	handler slog.Handler
+	out     zapcore.WriteSyncer // synced by Sync; may be nil
+}
+
+// Enabled implements zapcore.LevelEnabler
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), slogLevel(level))
+}
+
+// With implements zapcore.Core
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{handler: c.handler.WithAttrs(slogAttrs(fields)), out: c.out}
+}
+
+// Check implements zapcore.Core
+func (c *slogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core
+func (c *slogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	record := slog.NewRecord(entry.Time, slogLevel(entry.Level), entry.Message, 0)
+	if entry.LoggerName != "" {
+		record.AddAttrs(slog.String("logger", entry.LoggerName))
+	}
+	record.AddAttrs(slogAttrs(fields)...)
+	return c.handler.Handle(context.Background(), record)
+}
+
+// Sync implements zapcore.Core by syncing the handler's output
+func (c *slogCore) Sync() error {
+	if c.out == nil {
+		return nil
+	}
+	return c.out.Sync()
+}
+
+// slogAttrs converts zap fields into slog attributes, sorted by key.
+// Fields are rendered with a map encoder so every zap field type is supported.
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	attrs := make([]slog.Attr, 0, len(enc.Fields))
+	for _, key := range slices.Sorted(maps.Keys(enc.Fields)) {
+		attrs = append(attrs, slog.Any(key, enc.Fields[key]))
+	}
+	return attrs
+}
+
+// slogLevel maps a zap level onto slog. Levels above error have no slog
+// equivalent and are logged 4 above slog.LevelError, like slog's own gaps.
+func slogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	case level == zapcore.ErrorLevel:
+		return slog.LevelError
+	default:
+		return slog.LevelError + 4
+	}
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+func TestSlog_WritesToZap(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := Slog(zap.New(core))
+
+	logger.Debug("dropped")
+	logger.With("service", "time").WithGroup("request").Info("parsed",
+		slog.String("format", "RFC3339"),
+		slog.Int("year", 2023),
+		slog.Duration("took", time.Millisecond),
+		slog.Any("error", errors.New("boom")),
+		slog.Group("range", slog.Int("min", 1900)))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "parsed", entries[0].Message)
+
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "time", fields["service"])
+	assert.Equal(t, map[string]any{
+		"format": "RFC3339",
+		"year":   int64(2023),
+		"took":   time.Millisecond,
+		"error":  "boom",
+		"range":  map[string]any{"min": int64(1900)},
+	}, fields["request"])
+}
+
+func TestSlogCore_WritesToSlog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := zap.New(&slogCore{handler: handler})
+
+	logger.Debug("dropped")
+	logger.With(zap.String("service", "time")).Warn("slow", zap.Int("ms", 250), zap.Error(errors.New("boom")))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "slow", record["msg"])
+	assert.Equal(t, "time", record["service"])
+	assert.Equal(t, float64(250), record["ms"])
+	assert.Equal(t, "boom", record["error"])
+}
+
+func TestSlog_UnwrapsSlogBackend(t *testing.T) {
+	logger, err := New(config.LogConfig{Level: "warn", Format: "json", Backend: config.LogBackendSlog})
+	require.NoError(t, err)
+
+	core, ok := logger.Core().(*moduleCore)
+	require.True(t, ok)
+	base, ok := core.Core.(*slogCore)
+	require.True(t, ok)
+
+	handler, ok := Slog(logger).Handler().(*levelHandler)
+	require.True(t, ok)
+	assert.Same(t, base.handler, handler.Handler)
+	assert.False(t, logger.Core().Enabled(zapcore.InfoLevel))
+	assert.True(t, logger.Core().Enabled(zapcore.WarnLevel))
+}
+
+func TestLevelMapping(t *testing.T) {
+	tests := []struct {
+		slog slog.Level
+		zap  zapcore.Level
+	}{
+		{slog.LevelDebug, zapcore.DebugLevel},
+		{slog.LevelInfo, zapcore.InfoLevel},
+		{slog.LevelWarn, zapcore.WarnLevel},
+		{slog.LevelError, zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.zap, zapLevel(tt.slog))
+		assert.Equal(t, tt.slog, slogLevel(tt.zap))
+	}
+	assert.Equal(t, slog.LevelError+4, slogLevel(zapcore.FatalLevel))
+}
@@ -0,0 +1,25 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+// openSyslog connects to syslog. Entries are already encoded with their
+// level, so they are all sent at a single info priority.
+func openSyslog(cfg config.LogSinkConfig) (io.Writer, error) {
+	tag := cfg.Tag
+	if tag == "" {
+		tag = "mcp-server-time"
+	}
+	writer, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return writer, nil
+}
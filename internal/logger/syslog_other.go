@@ -0,0 +1,16 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+// openSyslog is not supported on this platform
+func openSyslog(cfg config.LogSinkConfig) (io.Writer, error) {
+	return nil, fmt.Errorf("syslog sink is not supported on %s", runtime.GOOS)
+}
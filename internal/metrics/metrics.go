@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -18,6 +20,67 @@ type Metrics struct {
 
 	// Error metrics
 	ErrorsTotal prometheus.CounterVec
+
+	// Fault injection metrics
+	ChaosInjectionsTotal prometheus.CounterVec
+
+	// Build metadata, constant 1 labelled with the running build
+	BuildInfo prometheus.GaugeVec
+
+	// Update check metrics
+	UpdateAvailable   prometheus.GaugeVec
+	UpdateChecksTotal prometheus.CounterVec
+
+	// Extension metrics
+	ExtensionCallsTotal   prometheus.CounterVec
+	ExtensionCallDuration prometheus.HistogramVec
+
+	// Calendar metrics
+	CalendarFetchesTotal prometheus.CounterVec
+
+	// Holiday feed metrics
+	HolidayFeedSyncsTotal prometheus.CounterVec
+	HolidayFeedLastSync   prometheus.GaugeVec
+
+	// Tool versioning metrics
+	DeprecatedToolCallsTotal prometheus.CounterVec
+
+	// SLO metrics
+	ToolCallsInFlight prometheus.GaugeVec
+	SLOLatencySeconds prometheus.GaugeVec
+	SLOBurnRate       prometheus.GaugeVec
+	SLOAlertsTotal    prometheus.CounterVec
+
+	// Load shedding metrics
+	ShedLevel      prometheus.Gauge
+	ShedCallsTotal prometheus.CounterVec
+
+	// Session affinity metrics
+	AffinityMisroutedTotal prometheus.Counter
+	SessionsRestoredTotal  prometheus.Counter
+
+	// SSE heartbeat metrics
+	SSEHeartbeatsTotal prometheus.Counter
+
+	// Clock jump metrics
+	ClockJumpsTotal      prometheus.CounterVec
+	ClockJumpLastSeconds prometheus.Gauge
+
+	// Stream resumption metrics
+	StreamBufferBytes         prometheus.Gauge
+	StreamReplaysTotal        prometheus.CounterVec
+	StreamReplayedEventsTotal prometheus.Counter
+
+	// Client profile metrics
+	ClientProfileSessionsTotal prometheus.CounterVec
+
+	// Format shadow metrics
+	FormatShadowTotal prometheus.CounterVec
+
+	// Session state metrics
+	SessionStateEntries      prometheus.Gauge
+	SessionStateRemovedTotal prometheus.CounterVec
+	SessionStatePurgedTotal  prometheus.Counter
 }
 
 // New creates a new Metrics instance with all metrics registered
@@ -56,6 +119,230 @@ func New() *Metrics {
 			},
 			[]string{"category", "error_type"},
 		),
+
+		ChaosInjectionsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_chaos_injections_total",
+				Help: "Total number of injected faults by target and fault type",
+			},
+			[]string{"target", "fault"},
+		),
+
+		BuildInfo: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_build_info",
+				Help: "Build metadata of the running server, always 1",
+			},
+			[]string{"version", "commit", "build_time", "go_version"},
+		),
+
+		UpdateAvailable: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_update_available",
+				Help: "1 when the last update check found a newer version of the component",
+			},
+			[]string{"component"},
+		),
+
+		UpdateChecksTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_update_checks_total",
+				Help: "Total number of update checks by component and status",
+			},
+			[]string{"component", "status"},
+		),
+
+		ExtensionCallsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_extension_calls_total",
+				Help: "Total number of extension program runs by extension, tool and status",
+			},
+			[]string{"extension", "tool", "status"},
+		),
+
+		ExtensionCallDuration: *promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "mcp_time_extension_call_duration_seconds",
+				Help:    "Duration of extension program runs in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"extension", "tool"},
+		),
+
+		CalendarFetchesTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_calendar_fetches_total",
+				Help: "Total number of calendar reads by calendar and status (success, error or cached)",
+			},
+			[]string{"calendar", "status"},
+		),
+
+		HolidayFeedSyncsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_holiday_feed_syncs_total",
+				Help: "Total number of holiday feed syncs by feed and status (success, not_modified or error)",
+			},
+			[]string{"feed", "status"},
+		),
+
+		HolidayFeedLastSync: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_holiday_feed_last_sync_timestamp_seconds",
+				Help: "Unix time of the last sync that confirmed a holiday feed's contents were current",
+			},
+			[]string{"feed"},
+		),
+
+		DeprecatedToolCallsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_deprecated_tool_calls_total",
+				Help: "Total number of calls to deprecated tools by tool",
+			},
+			[]string{"tool"},
+		),
+
+		ToolCallsInFlight: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_tool_calls_in_flight",
+				Help: "Tool calls currently running, by tool",
+			},
+			[]string{"tool"},
+		),
+
+		SLOLatencySeconds: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_slo_latency_seconds",
+				Help: "Tool call latency percentiles over the SLO window, by tool and quantile",
+			},
+			[]string{"tool", "quantile"},
+		),
+
+		SLOBurnRate: *promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_slo_burn_rate",
+				Help: "Rate a tool spends its error budget over the SLO window; 1 spends it exactly",
+			},
+			[]string{"tool"},
+		),
+
+		SLOAlertsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_slo_alerts_total",
+				Help: "Total number of SLO alerts sent by tool and status",
+			},
+			[]string{"tool", "status"},
+		),
+
+		ShedLevel: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_shed_level",
+				Help: "Load shedding level: 0 sheds nothing, 1 low, 2 normal and 3 high priority tools",
+			},
+		),
+
+		ShedCallsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_shed_calls_total",
+				Help: "Total number of tool calls rejected by load shedding, by tool and priority",
+			},
+			[]string{"tool", "priority"},
+		),
+
+		AffinityMisroutedTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "mcp_time_affinity_misrouted_total",
+				Help: "Total number of session requests whose affinity cookie named another replica",
+			},
+		),
+
+		SessionsRestoredTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "mcp_time_sessions_restored_total",
+				Help: "Total number of streamable sessions restored from the shared store on a replica that did not create them",
+			},
+		),
+
+		SSEHeartbeatsTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "mcp_time_sse_heartbeats_total",
+				Help: "Total number of heartbeats written on SSE streams",
+			},
+		),
+
+		ClockJumpsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_clock_jumps_total",
+				Help: "Total number of wall-clock jumps detected, by direction",
+			},
+			[]string{"direction"},
+		),
+
+		ClockJumpLastSeconds: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_clock_jump_last_seconds",
+				Help: "Size of the last wall-clock jump, negative for a backward step",
+			},
+		),
+
+		FormatShadowTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_format_shadow_total",
+				Help: "Total number of formats rendered again by a shadow engine, by engine, format kind and outcome",
+			},
+			[]string{"engine", "kind", "outcome"},
+		),
+
+		SessionStateEntries: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_session_state_entries",
+				Help: "Live session state entries across sessions",
+			},
+		),
+
+		SessionStateRemovedTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_session_state_removed_total",
+				Help: "Total number of session state entries removed, by reason (ttl, cleared or session_closed)",
+			},
+			[]string{"reason"},
+		),
+
+		SessionStatePurgedTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "mcp_time_session_state_purged_total",
+				Help: "Total number of soft-deleted session state entries purged",
+			},
+		),
+
+		StreamBufferBytes: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "mcp_time_stream_buffer_bytes",
+				Help: "Size of the events buffered across sessions for streams to be resumed",
+			},
+		),
+
+		StreamReplaysTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_stream_replays_total",
+				Help: "Total number of streams resumed with Last-Event-ID, by result (replayed or purged)",
+			},
+			[]string{"result"},
+		),
+
+		StreamReplayedEventsTotal: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "mcp_time_stream_replayed_events_total",
+				Help: "Total number of events sent again to resumed streams",
+			},
+		),
+
+		ClientProfileSessionsTotal: *promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mcp_time_client_profile_sessions_total",
+				Help: "Total number of sessions shaped by a client profile, by profile",
+			},
+			[]string{"profile"},
+		),
 	}
 }
 
@@ -79,21 +366,215 @@ func (m *Metrics) RecordError(category, errorType string) {
 	m.ErrorsTotal.WithLabelValues(category, errorType).Inc()
 }
 
+// RecordChaosInjection records an injected fault
+func (m *Metrics) RecordChaosInjection(target, fault string) {
+	m.ChaosInjectionsTotal.WithLabelValues(target, fault).Inc()
+}
+
+// RecordBuildInfo publishes the running build's metadata
+func (m *Metrics) RecordBuildInfo(version, commit, buildTime, goVersion string) {
+	m.BuildInfo.WithLabelValues(version, commit, buildTime, goVersion).Set(1)
+}
+
+// RecordUpdateCheck records an update check. available only counts when
+// the check succeeded
+func (m *Metrics) RecordUpdateCheck(component, status string, available bool) {
+	m.UpdateChecksTotal.WithLabelValues(component, status).Inc()
+	if status != StatusSuccess {
+		return
+	}
+	value := 0.0
+	if available {
+		value = 1
+	}
+	m.UpdateAvailable.WithLabelValues(component).Set(value)
+}
+
+// RecordExtensionCall records one run of an extension program
+func (m *Metrics) RecordExtensionCall(extension, tool, status string, duration float64) {
+	m.ExtensionCallsTotal.WithLabelValues(extension, tool, status).Inc()
+	m.ExtensionCallDuration.WithLabelValues(extension, tool).Observe(duration)
+}
+
+// RecordCalendarFetch records one read of a calendar
+func (m *Metrics) RecordCalendarFetch(calendar, status string) {
+	m.CalendarFetchesTotal.WithLabelValues(calendar, status).Inc()
+}
+
+// RecordHolidayFeedSync records one sync of a holiday feed. Successful and
+// not modified syncs both mark the feed fresh as of syncedAt
+func (m *Metrics) RecordHolidayFeedSync(feed, status string, syncedAt time.Time) {
+	m.HolidayFeedSyncsTotal.WithLabelValues(feed, status).Inc()
+	if status != StatusError {
+		m.HolidayFeedLastSync.WithLabelValues(feed).Set(float64(syncedAt.Unix()))
+	}
+}
+
+// RecordDeprecatedToolCall records a call to a deprecated tool, so
+// operators can tell when its callers have moved on
+func (m *Metrics) RecordDeprecatedToolCall(tool string) {
+	m.DeprecatedToolCallsTotal.WithLabelValues(tool).Inc()
+}
+
+// RecordToolCallStart counts a tool call as in flight until
+// RecordToolCallEnd
+func (m *Metrics) RecordToolCallStart(tool string) {
+	m.ToolCallsInFlight.WithLabelValues(tool).Inc()
+}
+
+// RecordToolCallEnd records the end of a call counted by RecordToolCallStart
+func (m *Metrics) RecordToolCallEnd(tool string) {
+	m.ToolCallsInFlight.WithLabelValues(tool).Dec()
+}
+
+// RecordSLO publishes a tool's latency percentiles and burn rate over the
+// SLO window
+func (m *Metrics) RecordSLO(tool string, p95, p99 time.Duration, burnRate float64) {
+	m.SLOLatencySeconds.WithLabelValues(tool, "0.95").Set(p95.Seconds())
+	m.SLOLatencySeconds.WithLabelValues(tool, "0.99").Set(p99.Seconds())
+	m.SLOBurnRate.WithLabelValues(tool).Set(burnRate)
+}
+
+// RecordSLOAlert records one SLO alert sent to the webhook
+func (m *Metrics) RecordSLOAlert(tool, status string) {
+	m.SLOAlertsTotal.WithLabelValues(tool, status).Inc()
+}
+
+// SetShedLevel publishes the current load shedding level
+func (m *Metrics) SetShedLevel(level int) {
+	m.ShedLevel.Set(float64(level))
+}
+
+// RecordShedCall records a tool call rejected by load shedding
+func (m *Metrics) RecordShedCall(tool, priority string) {
+	m.ShedCallsTotal.WithLabelValues(tool, priority).Inc()
+}
+
+// RecordAffinityMisrouted records a session request that reached the wrong
+// replica
+func (m *Metrics) RecordAffinityMisrouted() {
+	m.AffinityMisroutedTotal.Inc()
+}
+
+// RecordSessionRestored records a session another replica created, restored
+// here from the shared store
+func (m *Metrics) RecordSessionRestored() {
+	m.SessionsRestoredTotal.Inc()
+}
+
+// RecordSSEHeartbeat records a heartbeat written on an SSE stream
+func (m *Metrics) RecordSSEHeartbeat() {
+	m.SSEHeartbeatsTotal.Inc()
+}
+
+// RecordClockJump records a wall-clock jump of the given size, negative for
+// a backward step
+func (m *Metrics) RecordClockJump(direction string, seconds float64) {
+	m.ClockJumpsTotal.WithLabelValues(direction).Inc()
+	m.ClockJumpLastSeconds.Set(seconds)
+}
+
+// RecordFormatShadow records how a shadow format engine compared with the
+// current formatter
+func (m *Metrics) RecordFormatShadow(engine, kind, outcome string) {
+	m.FormatShadowTotal.WithLabelValues(engine, kind, outcome).Inc()
+}
+
+// SetSessionStateEntries publishes the live session state entries
+func (m *Metrics) SetSessionStateEntries(entries int) {
+	m.SessionStateEntries.Set(float64(entries))
+}
+
+// RecordSessionStateRemoved records session state entries removed for a
+// reason
+func (m *Metrics) RecordSessionStateRemoved(reason string, entries int) {
+	m.SessionStateRemovedTotal.WithLabelValues(reason).Add(float64(entries))
+}
+
+// RecordSessionStatePurged records soft-deleted session state entries purged
+func (m *Metrics) RecordSessionStatePurged(entries int) {
+	m.SessionStatePurgedTotal.Add(float64(entries))
+}
+
+// SetStreamBufferBytes publishes the size of the buffered stream events
+func (m *Metrics) SetStreamBufferBytes(bytes int) {
+	m.StreamBufferBytes.Set(float64(bytes))
+}
+
+// RecordStreamReplay records a resumed stream and the events sent again
+func (m *Metrics) RecordStreamReplay(result string, events int) {
+	m.StreamReplaysTotal.WithLabelValues(result).Inc()
+	m.StreamReplayedEventsTotal.Add(float64(events))
+}
+
+// RecordClientProfileSession records a session a client profile applies to
+func (m *Metrics) RecordClientProfileSession(profile string) {
+	m.ClientProfileSessionsTotal.WithLabelValues(profile).Inc()
+}
+
 // Status constants for metrics
 const (
 	StatusSuccess = "success"
 	StatusError   = "error"
 	StatusTimeout = "timeout"
 	StatusInvalid = "invalid"
+	StatusCached  = "cached"
+	// StatusNotModified is a conditional fetch answered with 304
+	StatusNotModified = "not_modified"
 )
 
 // Tool operation constants
 const (
-	OperationGetTime         = "get_time"
-	OperationFormatTime      = "format_time"
-	OperationParseTime       = "parse_time"
-	OperationTimezoneInfo    = "timezone_info"
-	OperationConvertTimezone = "convert_timezone"
+	OperationGetTime           = "get_time"
+	OperationFormatTime        = "format_time"
+	OperationParseTime         = "parse_time"
+	OperationTimezoneInfo      = "timezone_info"
+	OperationConvertTimezone   = "convert_timezone"
+	OperationServerUptime      = "get_server_uptime"
+	OperationCompareClock      = "compare_clock"
+	OperationTOTPWindow        = "totp_window"
+	OperationCheckExpiry       = "check_expiry"
+	OperationAnalyzeTimestamps = "analyze_timestamps"
+	OperationBucketTimestamps  = "bucket_timestamps"
+	OperationAlignPeriod       = "align_period"
+	OperationCheckDeadline     = "check_deadline"
+	OperationAnonymizeTime     = "anonymize_time"
+	OperationDiffZoneRules     = "diff_zone_rules"
+	OperationZoneAbbreviations = "get_zone_abbreviations"
+	OperationResolveAbbrev     = "resolve_abbreviation"
+	OperationTranslateDate     = "translate_date"
+	OperationWasDST            = "was_dst"
+	OperationServerInfo        = "get_server_info"
+	OperationCreateICS         = "create_ics"
+	OperationValidateWebhook   = "validate_webhook_timestamp"
+	OperationHTTPDate          = "http_date"
+	OperationRetryAfter        = "retry_after"
+	OperationAuditCrontab      = "audit_crontab"
+	OperationCheckCronJob      = "check_cronjob_schedule"
+	OperationMaintenance       = "parse_maintenance_window"
+	OperationExplainLayout     = "explain_layout"
+	OperationVerifyFormat      = "verify_format"
+	OperationParseTable        = "parse_table"
+	OperationDetectGaps        = "detect_gaps"
+	OperationToSQLLiteral      = "to_sql_literal"
+	OperationDecodeSnowflake   = "decode_snowflake"
+	OperationDecodeIDTimestamp = "decode_id_timestamp"
+	OperationAddTime           = "add_time"
+	OperationNextBillingDate   = "next_billing_date"
+	OperationProrate           = "prorate"
+	OperationTimesheet         = "timesheet"
+	OperationShiftPremium      = "evaluate_shift_premium"
+	OperationFreeBusy          = "get_free_busy"
+	OperationNextFreeSlot      = "next_free_slot"
+	OperationGetHolidays       = "get_holidays"
+	OperationSettlementDate    = "settlement_date"
+	OperationClearSessionState = "clear_session_state"
+)
+
+// Update check components
+const (
+	ComponentServer = "server"
+	ComponentTZData = "tzdata"
 )
 
 // Transport constants
@@ -117,4 +598,27 @@ const (
 	ErrorTypeParseFailure    = "parse_failure"
 	ErrorTypeConnectionLost  = "connection_lost"
 	ErrorTypeInvalidRequest  = "invalid_request"
+	ErrorTypeOutOfRange      = "out_of_range"
+	ErrorTypeUnknown         = "unknown"
+	ErrorTypePanic           = "panic"
 )
+
+// Fault injection constants
+const (
+	FaultDelay   = "delay"
+	FaultError   = "error"
+	FaultSSEDrop = "sse_drop"
+)
+
+// ToolUnknown labels the calls of tools the server doesn't serve, so
+// clients can't add label values by calling made-up names
+const ToolUnknown = "unknown"
+
+// ToolLabel returns the tool label of a call: the tool's name when known
+// holds it, ToolUnknown otherwise
+func ToolLabel(known map[string]bool, tool string) string {
+	if known[tool] {
+		return tool
+	}
+	return ToolUnknown
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
@@ -77,6 +78,79 @@ func TestMetrics_RecordError(t *testing.T) {
 	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ErrorsTotal.WithLabelValues(ErrorCategoryTime, ErrorTypeParseFailure)))
 }
 
+func TestMetrics_RecordBuildInfo(t *testing.T) {
+	// Clear any existing metrics
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+
+	metrics := New()
+
+	metrics.RecordBuildInfo("v1.4.0", "0123456789ab", "2026-10-16T08:00:00Z", "go1.23.4")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.BuildInfo.WithLabelValues("v1.4.0", "0123456789ab", "2026-10-16T08:00:00Z", "go1.23.4")))
+}
+
+func TestMetrics_RecordUpdateCheck(t *testing.T) {
+	// Clear any existing metrics
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+
+	metrics := New()
+
+	metrics.RecordUpdateCheck(ComponentTZData, StatusSuccess, true)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.UpdateAvailable.WithLabelValues(ComponentTZData)))
+
+	// A failed check keeps the last known answer
+	metrics.RecordUpdateCheck(ComponentTZData, StatusError, false)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.UpdateAvailable.WithLabelValues(ComponentTZData)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.UpdateChecksTotal.WithLabelValues(ComponentTZData, StatusError)))
+
+	metrics.RecordUpdateCheck(ComponentTZData, StatusSuccess, false)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.UpdateAvailable.WithLabelValues(ComponentTZData)))
+}
+
+func TestMetrics_RecordExtensionCall(t *testing.T) {
+	// Clear any existing metrics
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+
+	metrics := New()
+
+	metrics.RecordExtensionCall("caldav", "free_busy", StatusSuccess, 0.2)
+	metrics.RecordExtensionCall("caldav", "free_busy", StatusTimeout, 5)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ExtensionCallsTotal.WithLabelValues("caldav", "free_busy", StatusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ExtensionCallsTotal.WithLabelValues("caldav", "free_busy", StatusTimeout)))
+}
+
+func TestMetrics_RecordCalendarFetch(t *testing.T) {
+	// Clear any existing metrics
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+
+	metrics := New()
+
+	metrics.RecordCalendarFetch("team", StatusSuccess)
+	metrics.RecordCalendarFetch("team", StatusCached)
+	metrics.RecordCalendarFetch("team", StatusCached)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.CalendarFetchesTotal.WithLabelValues("team", StatusSuccess)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.CalendarFetchesTotal.WithLabelValues("team", StatusCached)))
+}
+
+func TestMetrics_RecordHolidayFeedSync(t *testing.T) {
+	// Clear any existing metrics
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+
+	metrics := New()
+
+	synced := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	metrics.RecordHolidayFeedSync("us", StatusSuccess, synced)
+	metrics.RecordHolidayFeedSync("us", StatusNotModified, synced.Add(time.Hour))
+	metrics.RecordHolidayFeedSync("us", StatusError, synced.Add(2*time.Hour))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.HolidayFeedSyncsTotal.WithLabelValues("us", StatusNotModified)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.HolidayFeedSyncsTotal.WithLabelValues("us", StatusError)))
+	// A failed sync leaves the feed as fresh as it was
+	assert.Equal(t, float64(synced.Add(time.Hour).Unix()), testutil.ToFloat64(metrics.HolidayFeedLastSync.WithLabelValues("us")))
+}
+
 func TestConstants(t *testing.T) {
 	// Test that all constants are defined and have expected values
 	assert.Equal(t, "success", StatusSuccess)
@@ -0,0 +1,195 @@
+package negotiate
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/sessionstate"
+)
+
+// CapabilityKey is the experimental capability both sides use to negotiate
+// output formats. A client declares the formats it prefers, in order:
+//
+//	"capabilities": {"experimental": {"time/format": {"preferred": ["UnixMilli", "RFC3339"]}}}
+//
+// and the server answers with the formats it supports and the one it selected.
+const CapabilityKey = "time/format"
+
+// StateKey is the session state key holding the negotiated format
+const StateKey = "format"
+
+// formatTools are the tools whose format argument picks the output format
+var formatTools = map[string]bool{
+	"get_time":    true,
+	"format_time": true,
+}
+
+// Negotiator agrees on a default output format with each client at
+// initialize time and applies it to calls that don't set a format
+type Negotiator struct {
+	supported     []string
+	defaultFormat string
+	state         *sessionstate.Store
+	logger        *zap.Logger
+}
+
+// New creates a format negotiator over the server's supported formats.
+// With a state store, the negotiated format is kept as session state, so
+// it expires and can be cleared like the rest; without one it is read
+// from the client's initialize parameters on every call
+func New(supported []string, defaultFormat string, state *sessionstate.Store, logger *zap.Logger) *Negotiator {
+	return &Negotiator{supported: supported, defaultFormat: defaultFormat, state: state, logger: logger}
+}
+
+// Select returns the first format the client prefers that the server
+// supports. ok is false when the client did not negotiate or nothing matches
+func (n *Negotiator) Select(params *mcp.InitializeParams) (format string, ok bool) {
+	if params == nil || params.Capabilities == nil {
+		return "", false
+	}
+	declared, ok := params.Capabilities.Experimental[CapabilityKey].(map[string]any)
+	if !ok {
+		return "", false
+	}
+
+	var preferred []string
+	switch v := declared["preferred"].(type) {
+	case string:
+		preferred = []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				preferred = append(preferred, s)
+			}
+		}
+	}
+
+	for _, want := range preferred {
+		for _, have := range n.supported {
+			if want == have {
+				return have, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Middleware returns an MCP receiving middleware that advertises the
+// negotiation capability on initialize and fills in the negotiated format
+// on get_time and format_time calls without one
+func (n *Negotiator) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			switch method {
+			case "initialize":
+				res, err := next(ctx, method, req)
+				if err != nil {
+					return res, err
+				}
+				if result, ok := res.(*mcp.InitializeResult); ok && result != nil {
+					params, _ := req.GetParams().(*mcp.InitializeParams)
+					session, _ := req.GetSession().(*mcp.ServerSession)
+					n.advertise(result, params, session)
+				}
+				return res, err
+
+			case "tools/call":
+				if callReq, ok := req.(*mcp.CallToolRequest); ok {
+					n.apply(callReq)
+				}
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// advertise adds the negotiation capability, and the client's selection if
+// any, to an initialize result, keeping the selection as session state
+func (n *Negotiator) advertise(result *mcp.InitializeResult, params *mcp.InitializeParams, session *mcp.ServerSession) {
+	capability := map[string]any{
+		"supported": n.supported,
+		"default":   n.defaultFormat,
+	}
+	if selected, ok := n.Select(params); ok {
+		capability["selected"] = selected
+		n.logger.Debug("Negotiated default format", zap.String("format", selected))
+		if n.state != nil && session != nil {
+			if err := n.state.Set(session, StateKey, selected); err != nil {
+				n.logger.Warn("Failed to keep negotiated format", zap.Error(err))
+			}
+		}
+	}
+
+	if result.Capabilities == nil {
+		result.Capabilities = &mcp.ServerCapabilities{}
+	}
+	if result.Capabilities.Experimental == nil {
+		result.Capabilities.Experimental = make(map[string]any)
+	}
+	result.Capabilities.Experimental[CapabilityKey] = capability
+}
+
+// Restore keeps the format a session restored on another replica
+// negotiated, from its initialize parameters, as session state. Session
+// state isn't shared between replicas; a format the client cleared comes
+// back on the replica it moves to
+func (n *Negotiator) Restore(session *mcp.ServerSession) {
+	if n.state == nil {
+		return
+	}
+	selected, ok := n.Select(session.InitializeParams())
+	if !ok {
+		return
+	}
+	if err := n.state.Set(session, StateKey, selected); err != nil {
+		n.logger.Warn("Failed to keep negotiated format", zap.Error(err))
+	}
+}
+
+// apply sets the session's negotiated format on a format tool call that
+// doesn't carry its own
+func (n *Negotiator) apply(req *mcp.CallToolRequest) {
+	if req.Params == nil || !formatTools[req.Params.Name] || req.Session == nil {
+		return
+	}
+	format, ok := n.sessionFormat(req.Session)
+	if !ok {
+		return
+	}
+
+	args := make(map[string]any)
+	if len(req.Params.Arguments) > 0 {
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			// Leave malformed arguments for the tool to reject
+			return
+		}
+	}
+	if existing, ok := args["format"].(string); ok && existing != "" {
+		return
+	}
+
+	args["format"] = format
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return
+	}
+	req.Params.Arguments = raw
+}
+
+// sessionFormat returns the format a session negotiated, from its state
+// when kept there
+func (n *Negotiator) sessionFormat(session *mcp.ServerSession) (string, bool) {
+	if n.state == nil {
+		return n.Select(session.InitializeParams())
+	}
+	value, ok := n.state.Get(session, StateKey)
+	if !ok {
+		return "", false
+	}
+	format, ok := value.(string)
+	return format, ok
+}
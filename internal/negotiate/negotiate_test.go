@@ -0,0 +1,167 @@
+package negotiate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/logger"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/sessionstate"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/tools"
+)
+
+var supported = []string{"RFC3339", "Unix", "UnixMilli"}
+
+func paramsPreferring(preferred any) *mcp.InitializeParams {
+	return &mcp.InitializeParams{Capabilities: &mcp.ClientCapabilities{
+		Experimental: map[string]any{CapabilityKey: map[string]any{"preferred": preferred}},
+	}}
+}
+
+func TestNegotiator_Select(t *testing.T) {
+	negotiator := New(supported, "RFC3339", nil, zaptest.NewLogger(t))
+
+	tests := []struct {
+		name   string
+		params *mcp.InitializeParams
+		want   string
+		ok     bool
+	}{
+		{"no params", nil, "", false},
+		{"no capability", &mcp.InitializeParams{Capabilities: &mcp.ClientCapabilities{}}, "", false},
+		{"first supported preference", paramsPreferring([]any{"UnixNano", "UnixMilli", "Unix"}), "UnixMilli", true},
+		{"single preference", paramsPreferring("Unix"), "Unix", true},
+		{"nothing supported", paramsPreferring([]any{"Kitchen"}), "", false},
+		{"malformed capability", &mcp.InitializeParams{Capabilities: &mcp.ClientCapabilities{
+			Experimental: map[string]any{CapabilityKey: "UnixMilli"},
+		}}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := negotiator.Select(tt.params)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// connect starts the time tools behind the negotiator and connects a client
+// declaring preferred, if set, as its format preference. With withState the
+// negotiated format is kept as session state, and clear_session_state served
+func connect(t *testing.T, preferred any, withState bool) *mcp.ClientSession {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	zapLogger := zaptest.NewLogger(t)
+	m := metrics.New()
+
+	timeService := timeservice.NewTimeService("UTC", "RFC3339", supported, logger.Slog(zapLogger))
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	tools.RegisterTimeTools(server, timeService, m, zapLogger)
+	var state *sessionstate.Store
+	if withState {
+		state = sessionstate.New(config.SessionStateConfig{}, server, m, zapLogger)
+		tools.RegisterSessionStateTool(server, state, m, zapLogger)
+	}
+	server.AddReceivingMiddleware(New(supported, "RFC3339", state, zapLogger).Middleware())
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "negotiate-test", Version: "test"}, nil)
+	if preferred != nil {
+		client.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+			return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				if params, ok := req.GetParams().(*mcp.InitializeParams); ok {
+					params.Capabilities.Experimental = paramsPreferring(preferred).Capabilities.Experimental
+				}
+				return next(ctx, method, req)
+			}
+		})
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func callFormat(t *testing.T, session *mcp.ClientSession, tool string, args map[string]any) string {
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: tool, Arguments: args})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	return structured["format"].(string)
+}
+
+func TestNegotiator_Session(t *testing.T) {
+	session := connect(t, []any{"UnixMilli"}, false)
+
+	capability := session.InitializeResult().Capabilities.Experimental[CapabilityKey].(map[string]any)
+	assert.Equal(t, "UnixMilli", capability["selected"])
+	assert.Equal(t, "RFC3339", capability["default"])
+	assert.ElementsMatch(t, []any{"RFC3339", "Unix", "UnixMilli"}, capability["supported"])
+
+	assert.Equal(t, "UnixMilli", callFormat(t, session, "get_time", map[string]any{}))
+	assert.Equal(t, "UnixMilli", callFormat(t, session, "format_time", map[string]any{"timestamp": "2023-12-25T15:30:45Z"}))
+	assert.Equal(t, "Unix", callFormat(t, session, "get_time", map[string]any{"format": "Unix"}), "explicit format wins")
+}
+
+func TestNegotiator_WithoutPreference(t *testing.T) {
+	session := connect(t, nil, false)
+
+	capability := session.InitializeResult().Capabilities.Experimental[CapabilityKey].(map[string]any)
+	assert.NotContains(t, capability, "selected")
+	assert.Equal(t, "RFC3339", callFormat(t, session, "get_time", map[string]any{}))
+}
+
+func TestNegotiator_SessionState(t *testing.T) {
+	session := connect(t, []any{"UnixMilli"}, true)
+	assert.Equal(t, "UnixMilli", callFormat(t, session, "get_time", map[string]any{}))
+
+	ctx := context.Background()
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "clear_session_state", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, []any{StateKey}, res.StructuredContent.(map[string]any)["cleared"])
+	assert.Equal(t, "RFC3339", callFormat(t, session, "get_time", map[string]any{}), "cleared sessions use the server default")
+
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "clear_session_state", Arguments: map[string]any{"restore": true}})
+	require.NoError(t, err)
+	assert.Equal(t, "UnixMilli", callFormat(t, session, "get_time", map[string]any{}))
+}
+
+func TestNegotiator_Restore(t *testing.T) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	zapLogger := zaptest.NewLogger(t)
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	state := sessionstate.New(config.SessionStateConfig{}, server, metrics.New(), zapLogger)
+	negotiator := New(supported, "RFC3339", state, zapLogger)
+
+	// A session restored on another replica never sees initialize
+	serverTransport, _ := mcp.NewInMemoryTransports()
+	session, err := server.Connect(context.Background(), serverTransport, &mcp.ServerSessionOptions{State: &mcp.ServerSessionState{
+		InitializeParams:  paramsPreferring([]any{"UnixMilli"}),
+		InitializedParams: &mcp.InitializedParams{},
+	}})
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	_, ok := negotiator.sessionFormat(session)
+	assert.False(t, ok)
+
+	negotiator.Restore(session)
+	format, ok := negotiator.sessionFormat(session)
+	assert.True(t, ok)
+	assert.Equal(t, "UnixMilli", format)
+}
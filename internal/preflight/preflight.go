@@ -0,0 +1,213 @@
+// Package preflight checks that a deployment has what the server needs
+// before it starts: a valid config, loadable tzdata, bindable ports,
+// writable storage and runnable extension programs. It reports every
+// problem at once, so a broken deploy fails fast instead of crash-looping.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/updates"
+)
+
+// Check outcomes
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// probeZone is loaded whatever the config says, and its summer offset
+// checked, so a tzdata that loads but lost its rules still fails
+const probeZone = "America/New_York"
+
+// Result is the outcome of one check
+type Result struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report holds the results of every check, in the order they ran
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Failures returns the number of failed checks
+func (r Report) Failures() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// Write prints the report, one check per line
+func (r Report) Write(w io.Writer) {
+	for _, result := range r.Results {
+		fmt.Fprintf(w, "%-4s  %-24s  %s\n", result.Status, result.Name, result.Detail)
+	}
+}
+
+// add records a check, failed when err is set
+func (r *Report) add(name string, detail string, err error) {
+	if err != nil {
+		r.Results = append(r.Results, Result{Name: name, Status: StatusFail, Detail: err.Error()})
+		return
+	}
+	r.Results = append(r.Results, Result{Name: name, Status: StatusOK, Detail: detail})
+}
+
+// Run loads the configuration as opts describe and checks the deployment
+// it configures. Without a valid config, nothing else is checked
+func Run(opts config.LoadOptions) Report {
+	cfg, err := config.Load(opts)
+	if err != nil {
+		var report Report
+		report.add("config", "", err)
+		return report
+	}
+	source := cfg.File
+	if source == "" {
+		source = "defaults and environment"
+	}
+	report := Report{Results: []Result{{Name: "config", Status: StatusOK, Detail: source}}}
+	report.Results = append(report.Results, Check(cfg).Results...)
+	return report
+}
+
+// Check checks the deployment a loaded configuration describes
+func Check(cfg *config.Config) Report {
+	var report Report
+
+	report.add("tzdata", fmt.Sprintf("version %s", updates.LocalTZDataVersion()), checkZones(cfg.Time))
+	for _, source := range cfg.Time.TZDataSources {
+		_, err := os.Stat(source.Path)
+		report.add("tzdata source "+source.Name, source.Path, err)
+	}
+
+	serverAddr := net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port))
+	report.add("port server", serverAddr, bindable(serverAddr))
+	if cfg.Metrics.Enabled {
+		metricsAddr := net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Metrics.Port))
+		report.add("port metrics", metricsAddr, bindable(metricsAddr))
+	}
+
+	for i, sink := range cfg.Logging.Sinks {
+		if sink.Type == config.LogSinkFile {
+			report.add(fmt.Sprintf("logging.sinks[%d]", i), sink.Path, writableFile(sink.Path))
+		}
+	}
+	if len(cfg.Holidays.Feeds) > 0 && cfg.Holidays.CacheDir != "" {
+		report.add("holidays.cache_dir", cfg.Holidays.CacheDir, writableDir(cfg.Holidays.CacheDir))
+	}
+	switch cfg.Replay.Mode {
+	case config.ReplayModeRecord:
+		report.add("replay.file", cfg.Replay.File, writableFile(cfg.Replay.File))
+	case config.ReplayModeReplay:
+		report.add("replay.file", cfg.Replay.File, readable(cfg.Replay.File))
+	}
+
+	for _, ext := range cfg.Extensions {
+		path, err := program(ext)
+		report.add("extension "+ext.Name, path, err)
+	}
+	return report
+}
+
+// checkZones loads the default, preloaded and probe zones
+func checkZones(cfg config.TimeConfig) error {
+	zones := append([]string{cfg.DefaultTimezone, probeZone}, cfg.PreloadTimezones...)
+	for _, zone := range zones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			return fmt.Errorf("cannot load %s: %w", zone, err)
+		}
+	}
+	loc, _ := time.LoadLocation(probeZone)
+	if _, offset := time.Date(2024, 7, 1, 12, 0, 0, 0, loc).Zone(); offset != -4*60*60 {
+		return fmt.Errorf("%s has offset %ds on 2024-07-01, not -14400s; the tzdata is damaged", probeZone, offset)
+	}
+	return nil
+}
+
+// bindable listens on an address and lets it go again
+func bindable(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return listener.Close()
+}
+
+// writableDir reports whether files can be created in dir, or in its
+// closest existing parent when the server would create dir
+func writableDir(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if errors.Is(err, os.ErrNotExist) && filepath.Dir(dir) != dir {
+			dir = filepath.Dir(dir)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		break
+	}
+	probe, err := os.CreateTemp(dir, ".preflight-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// writableFile reports whether path can be appended to, or created when
+// it doesn't exist yet
+func writableFile(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return writableDir(filepath.Dir(path))
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// readable reports whether a file can be read
+func readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// program resolves an extension's executable, relative to its dir
+func program(ext config.ExtensionConfig) (string, error) {
+	name := ext.Command[0]
+	if ext.Dir != "" && !filepath.IsAbs(name) && filepath.Base(name) != name {
+		name = filepath.Join(ext.Dir, name)
+	}
+	return exec.LookPath(name)
+}
@@ -0,0 +1,89 @@
+package preflight
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+// freePort returns a port nothing listens on
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	recording := filepath.Join(dir, "recording.jsonl")
+	require.NoError(t, os.WriteFile(recording, []byte("{}\n"), 0o644))
+
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Host: "127.0.0.1", Port: freePort(t)},
+		Metrics:  config.MetricsConfig{Enabled: true, Port: freePort(t)},
+		Time:     config.TimeConfig{DefaultTimezone: "Europe/Berlin", PreloadTimezones: []string{"Asia/Tokyo"}},
+		Logging:  config.LogConfig{Sinks: []config.LogSinkConfig{{Type: config.LogSinkFile, Path: filepath.Join(dir, "logs", "server.log")}}},
+		Holidays: config.HolidaysConfig{CacheDir: filepath.Join(dir, "cache"), Feeds: []config.HolidayFeedConfig{{Name: "us"}}},
+		Replay:   config.ReplayConfig{Mode: config.ReplayModeReplay, File: recording},
+		Extensions: []config.ExtensionConfig{
+			{Name: "shell", Command: []string{"sh", "-c", "true"}},
+		},
+	}
+	report := Check(cfg)
+	assert.Zero(t, report.Failures(), "%+v", report.Results)
+
+	var names []string
+	for _, result := range report.Results {
+		names = append(names, result.Name)
+	}
+	assert.Equal(t, []string{"tzdata", "port server", "port metrics", "logging.sinks[0]", "holidays.cache_dir", "replay.file", "extension shell"}, names)
+	_, err := os.Stat(filepath.Join(dir, "cache"))
+	assert.True(t, os.IsNotExist(err), "checks must not create directories")
+}
+
+func TestCheck_Failures(t *testing.T) {
+	dir := t.TempDir()
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+	notADir := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(notADir, nil, 0o644))
+
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Host: "127.0.0.1", Port: taken.Addr().(*net.TCPAddr).Port},
+		Time:     config.TimeConfig{DefaultTimezone: "UTC", PreloadTimezones: []string{"Mars/Olympus_Mons"}},
+		Holidays: config.HolidaysConfig{CacheDir: filepath.Join(notADir, "cache"), Feeds: []config.HolidayFeedConfig{{Name: "us"}}},
+		Replay:   config.ReplayConfig{Mode: config.ReplayModeReplay, File: filepath.Join(dir, "missing.jsonl")},
+		Extensions: []config.ExtensionConfig{
+			{Name: "calendar", Command: []string{filepath.Join(dir, "no-such-program")}},
+		},
+	}
+	report := Check(cfg)
+	assert.Equal(t, 5, report.Failures())
+	for _, result := range report.Results {
+		assert.Equal(t, StatusFail, result.Status, result.Name)
+	}
+	assert.Contains(t, report.Results[0].Detail, "cannot load Mars/Olympus_Mons")
+
+	var out bytes.Buffer
+	report.Write(&out)
+	assert.Contains(t, out.String(), "fail  holidays.cache_dir        ")
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("time:\n  default_timezone: Mars/Olympus_Mons\n"), 0o644))
+
+	report := Run(config.LoadOptions{File: file})
+	require.Len(t, report.Results, 1, "nothing else is checked without a valid config")
+	assert.Equal(t, "config", report.Results[0].Name)
+	assert.Equal(t, StatusFail, report.Results[0].Status)
+}
@@ -0,0 +1,191 @@
+// Package profiles shapes responses per client. The client a session
+// declared at initialize is matched against the configured profiles, and
+// the first match strips what that client mishandles from its responses,
+// such as structuredContent for clients predating it.
+package profiles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Profiles applies client profiles to sessions
+type Profiles struct {
+	profiles []config.ClientProfileConfig
+	metrics  *metrics.Metrics
+	logger   *zap.Logger
+}
+
+// New creates a Profiles for the configured client profiles
+func New(profiles []config.ClientProfileConfig, metrics *metrics.Metrics, logger *zap.Logger) *Profiles {
+	return &Profiles{profiles: profiles, metrics: metrics, logger: logger}
+}
+
+// Match returns the first profile for a client, as it declared itself at
+// initialize. ok is false when none applies
+func (p *Profiles) Match(client *mcp.Implementation) (profile config.ClientProfileConfig, ok bool) {
+	if client == nil {
+		return config.ClientProfileConfig{}, false
+	}
+	for _, profile := range p.profiles {
+		if !strings.EqualFold(profile.Client, client.Name) {
+			continue
+		}
+		if profile.BeforeVersion == "" {
+			return profile, true
+		}
+		if older, known := before(client.Version, profile.BeforeVersion); known && older {
+			return profile, true
+		}
+	}
+	return config.ClientProfileConfig{}, false
+}
+
+// Middleware returns an MCP receiving middleware that logs the profile
+// applying to each new session and shapes tools/list and tools/call
+// results by it
+func (p *Profiles) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if err != nil {
+				return res, err
+			}
+
+			switch method {
+			case "initialize":
+				params, _ := req.GetParams().(*mcp.InitializeParams)
+				if params == nil {
+					return res, err
+				}
+				if profile, ok := p.Match(params.ClientInfo); ok {
+					p.metrics.RecordClientProfileSession(profile.Name)
+					p.logger.Info("Applying client profile",
+						zap.String("profile", profile.Name),
+						zap.String("client", params.ClientInfo.Name),
+						zap.String("client_version", params.ClientInfo.Version))
+				}
+			case "tools/list":
+				profile, ok := p.session(req)
+				result, isResult := res.(*mcp.ListToolsResult)
+				if ok && isResult && result != nil {
+					shapeTools(result, profile)
+				}
+			case "tools/call":
+				profile, ok := p.session(req)
+				result, isResult := res.(*mcp.CallToolResult)
+				if ok && isResult && result != nil && profile.DropStructuredContent {
+					if dropErr := dropStructuredContent(result); dropErr != nil {
+						p.logger.Error("Failed to drop structured content",
+							zap.String("profile", profile.Name),
+							zap.Error(dropErr))
+						return nil, fmt.Errorf("failed to shape result: %w", dropErr)
+					}
+				}
+			}
+			return res, err
+		}
+	}
+}
+
+// session returns the profile of the session a request belongs to
+func (p *Profiles) session(req mcp.Request) (config.ClientProfileConfig, bool) {
+	session, ok := req.GetSession().(*mcp.ServerSession)
+	if !ok || session == nil {
+		return config.ClientProfileConfig{}, false
+	}
+	params := session.InitializeParams()
+	if params == nil {
+		return config.ClientProfileConfig{}, false
+	}
+	return p.Match(params.ClientInfo)
+}
+
+// shapeTools strips what a profile drops from listed tools. Tools are
+// copied, as the listed ones are shared with the server
+func shapeTools(result *mcp.ListToolsResult, profile config.ClientProfileConfig) {
+	if !profile.DropStructuredContent && !profile.DropAnnotations {
+		return
+	}
+	for i, tool := range result.Tools {
+		shaped := *tool
+		if profile.DropStructuredContent {
+			shaped.OutputSchema = nil
+		}
+		if profile.DropAnnotations {
+			shaped.Title = ""
+			shaped.Annotations = nil
+		}
+		result.Tools[i] = &shaped
+	}
+}
+
+// dropStructuredContent removes structuredContent from a result, first
+// rendering it as text content when the result has none
+func dropStructuredContent(result *mcp.CallToolResult) error {
+	if result.StructuredContent == nil {
+		return nil
+	}
+	if len(result.Content) == 0 {
+		text, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return fmt.Errorf("failed to marshal structured content: %w", err)
+		}
+		result.Content = []mcp.Content{&mcp.TextContent{Text: string(text)}}
+	}
+	result.StructuredContent = nil
+	return nil
+}
+
+// before reports whether version is lower than bound. known is false when
+// version isn't a dotted version; pre-release and build suffixes are
+// ignored
+func before(version, bound string) (older, known bool) {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false, false
+	}
+	b, _ := parseVersion(bound)
+	for i := 0; i < max(len(v), len(b)); i++ {
+		var x, y int
+		if i < len(v) {
+			x = v[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x < y, true
+		}
+	}
+	return false, true
+}
+
+// parseVersion splits a dotted version such as v1.4.0-beta into its numbers
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
@@ -0,0 +1,101 @@
+package profiles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+var testProfiles = []config.ClientProfileConfig{
+	{Name: "old-desk", Client: "Desk", BeforeVersion: "2.0", DropStructuredContent: true, DropAnnotations: true},
+	{Name: "plain", Client: "plain-client", DropAnnotations: true},
+}
+
+func TestProfiles_Match(t *testing.T) {
+	p := New(testProfiles, nil, zaptest.NewLogger(t))
+
+	tests := []struct {
+		name    string
+		client  *mcp.Implementation
+		profile string
+	}{
+		{name: "no client info"},
+		{name: "older version", client: &mcp.Implementation{Name: "desk", Version: "1.9.3"}, profile: "old-desk"},
+		{name: "pre-release", client: &mcp.Implementation{Name: "Desk", Version: "v1.10.0-rc.1"}, profile: "old-desk"},
+		{name: "fixed version", client: &mcp.Implementation{Name: "Desk", Version: "2.0.0"}},
+		{name: "unknown version", client: &mcp.Implementation{Name: "Desk", Version: "nightly"}},
+		{name: "any version", client: &mcp.Implementation{Name: "plain-client"}, profile: "plain"},
+		{name: "other client", client: &mcp.Implementation{Name: "cli", Version: "1.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, ok := p.Match(tt.client)
+			assert.Equal(t, tt.profile != "", ok)
+			assert.Equal(t, tt.profile, profile.Name)
+		})
+	}
+}
+
+type output struct {
+	Time string `json:"time"`
+}
+
+// connect opens a session as the named client against a server with one
+// structured tool, shaped by the test profiles
+func connect(t *testing.T, m *metrics.Metrics, client *mcp.Implementation) *mcp.ClientSession {
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_time", Title: "Get time", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}},
+		func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, output, error) {
+			return nil, output{Time: "2026-10-16T08:00:00Z"}, nil
+		})
+	server.AddReceivingMiddleware(New(testProfiles, m, zaptest.NewLogger(t)).Middleware())
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+	session, err := mcp.NewClient(client, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestProfiles_Middleware(t *testing.T) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	ctx := context.Background()
+
+	// Other clients get results as tools produce them
+	session := connect(t, m, &mcp.Implementation{Name: "cli", Version: "1.0.0"})
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, tools.Tools[0].OutputSchema)
+	assert.Equal(t, "Get time", tools.Tools[0].Title)
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "get_time"})
+	require.NoError(t, err)
+	assert.NotNil(t, res.StructuredContent)
+
+	session = connect(t, m, &mcp.Implementation{Name: "desk", Version: "1.2.0"})
+	tools, err = session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	assert.Nil(t, tools.Tools[0].OutputSchema)
+	assert.Empty(t, tools.Tools[0].Title)
+	assert.Nil(t, tools.Tools[0].Annotations)
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "get_time"})
+	require.NoError(t, err)
+	assert.Nil(t, res.StructuredContent)
+	require.Len(t, res.Content, 1)
+	assert.JSONEq(t, `{"time":"2026-10-16T08:00:00Z"}`, res.Content[0].(*mcp.TextContent).Text)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ClientProfileSessionsTotal.WithLabelValues("old-desk")))
+}
@@ -0,0 +1,65 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Recoverer turns panics in MCP handlers into error responses, so one bad
+// input fails a single request instead of the SSE connection or the process
+type Recoverer struct {
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+}
+
+// New creates a new panic recoverer
+func New(metrics *metrics.Metrics, logger *zap.Logger) *Recoverer {
+	return &Recoverer{metrics: metrics, logger: logger}
+}
+
+// Middleware returns an MCP receiving middleware that recovers from panics.
+// Panicking tool calls return an internal-error tool result; other methods
+// return a JSON-RPC error.
+func (r *Recoverer) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (res mcp.Result, err error) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+
+				tool := ""
+				if callReq, ok := req.(*mcp.CallToolRequest); ok && callReq.Params != nil {
+					tool = callReq.Params.Name
+				}
+
+				r.logger.Error("Recovered from panic in MCP handler",
+					zap.String("method", method),
+					zap.String("tool", tool),
+					zap.Any("panic", recovered),
+					zap.ByteString("stack", debug.Stack()))
+				r.metrics.RecordError(metrics.ErrorCategoryInternal, metrics.ErrorTypePanic)
+
+				if method != "tools/call" {
+					res, err = nil, fmt.Errorf("internal error handling %s", method)
+					return
+				}
+				res, err = &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("internal error: tool %s failed unexpectedly", tool)},
+					},
+					IsError: true,
+				}, nil
+			}()
+
+			return next(ctx, method, req)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package recovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func newTestRecoverer(t *testing.T) (*Recoverer, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	return New(m, zaptest.NewLogger(t)), m
+}
+
+func panicking(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+	panic("boom")
+}
+
+func TestRecoverer_ToolCall(t *testing.T) {
+	recoverer, m := newTestRecoverer(t)
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get_time"}}
+	res, err := recoverer.Middleware()(panicking)(context.Background(), "tools/call", req)
+	require.NoError(t, err)
+
+	result, ok := res.(*mcp.CallToolResult)
+	require.True(t, ok)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "internal error: tool get_time failed unexpectedly", result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ErrorsTotal.WithLabelValues(metrics.ErrorCategoryInternal, metrics.ErrorTypePanic)))
+}
+
+func TestRecoverer_OtherMethod(t *testing.T) {
+	recoverer, m := newTestRecoverer(t)
+
+	res, err := recoverer.Middleware()(panicking)(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+	assert.Nil(t, res)
+	assert.EqualError(t, err, "internal error handling tools/list")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ErrorsTotal.WithLabelValues(metrics.ErrorCategoryInternal, metrics.ErrorTypePanic)))
+}
+
+func TestRecoverer_PassesThrough(t *testing.T) {
+	recoverer, m := newTestRecoverer(t)
+
+	want := &mcp.CallToolResult{}
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return want, nil
+	}
+	res, err := recoverer.Middleware()(next)(context.Background(), "tools/call", &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Same(t, want, res)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.ErrorsTotal.WithLabelValues(metrics.ErrorCategoryInternal, metrics.ErrorTypePanic)))
+}
@@ -0,0 +1,156 @@
+// Package redact hides sensitive values in logs and in tool errors sent to
+// clients. Field rules hide a value entirely based on its name; pattern
+// rules hide every match of a regular expression in free text.
+package redact
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+// Placeholder replaces redacted values
+const Placeholder = "[REDACTED]"
+
+// Redactor applies a set of redaction rules. A nil Redactor redacts nothing.
+type Redactor struct {
+	fields   map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// New creates a redactor from the configured rules, or returns nil if there
+// are none
+func New(cfg config.RedactionConfig) (*Redactor, error) {
+	if len(cfg.Fields) == 0 && len(cfg.Patterns) == 0 {
+		return nil, nil
+	}
+
+	r := &Redactor{fields: make(map[string]bool, len(cfg.Fields))}
+	for _, field := range cfg.Fields {
+		r.fields[strings.ToLower(field)] = true
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// IsSensitive reports whether values named key are always hidden
+func (r *Redactor) IsSensitive(key string) bool {
+	return r != nil && r.fields[strings.ToLower(key)]
+}
+
+// Text hides every pattern match in s
+func (r *Redactor) Text(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Placeholder)
+	}
+	return s
+}
+
+// Value hides s entirely if key is sensitive, or its pattern matches otherwise
+func (r *Redactor) Value(key, s string) string {
+	if r.IsSensitive(key) {
+		return Placeholder
+	}
+	return r.Text(s)
+}
+
+// SensitiveValues returns the values of sensitive fields in JSON tool
+// arguments, at any depth, so they can be hidden where they are echoed
+func (r *Redactor) SensitiveValues(arguments json.RawMessage) []string {
+	if r == nil || len(r.fields) == 0 || len(arguments) == 0 {
+		return nil
+	}
+	// Numbers keep their literal text, which is how errors echo them
+	dec := json.NewDecoder(bytes.NewReader(arguments))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil
+	}
+
+	var values []string
+	var walk func(key string, v any)
+	walk = func(key string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, child := range v {
+				walk(k, child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(key, child)
+			}
+		case string:
+			if r.IsSensitive(key) && v != "" {
+				values = append(values, v)
+			}
+		case json.Number:
+			if r.IsSensitive(key) {
+				values = append(values, v.String())
+			}
+		}
+	}
+	walk("", decoded)
+	return values
+}
+
+// Message hides the given values and every pattern match in msg. Longer
+// values are replaced first so a value containing another is fully hidden.
+func (r *Redactor) Message(msg string, values []string) string {
+	if r == nil {
+		return msg
+	}
+	values = slices.Clone(values)
+	slices.SortFunc(values, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	for _, value := range values {
+		msg = strings.ReplaceAll(msg, value, Placeholder)
+	}
+	return r.Text(msg)
+}
+
+// Middleware returns an MCP receiving middleware that redacts the text of
+// failed tool results, which echo tool arguments back to the client
+func (r *Redactor) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if err != nil || method != "tools/call" {
+				return res, err
+			}
+
+			result, ok := res.(*mcp.CallToolResult)
+			if !ok || result == nil || !result.IsError {
+				return res, err
+			}
+
+			var values []string
+			if callReq, ok := req.(*mcp.CallToolRequest); ok && callReq.Params != nil {
+				values = r.SensitiveValues(callReq.Params.Arguments)
+			}
+			for _, content := range result.Content {
+				if text, ok := content.(*mcp.TextContent); ok {
+					text.Text = r.Message(text.Text, values)
+				}
+			}
+			return res, err
+		}
+	}
+}
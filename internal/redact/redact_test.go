@@ -0,0 +1,92 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+func newTestRedactor(t *testing.T) *Redactor {
+	r, err := New(config.RedactionConfig{
+		Fields:   []string{"time_string", "JWT"},
+		Patterns: []string{`patient-\d+`},
+	})
+	require.NoError(t, err)
+	return r
+}
+
+func TestNew(t *testing.T) {
+	r, err := New(config.RedactionConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, r)
+	assert.Equal(t, "unchanged", r.Value("time_string", "unchanged"))
+
+	_, err = New(config.RedactionConfig{Patterns: []string{"("}})
+	assert.Error(t, err)
+}
+
+func TestRedactor_Value(t *testing.T) {
+	r := newTestRedactor(t)
+
+	assert.Equal(t, Placeholder, r.Value("time_string", "2023-12-25"))
+	assert.Equal(t, Placeholder, r.Value("jwt", "eyJ..."))
+	assert.Equal(t, "admitted [REDACTED] at noon", r.Value("note", "admitted patient-42 at noon"))
+	assert.Equal(t, "2023-12-25", r.Value("timezone", "2023-12-25"))
+}
+
+func TestRedactor_SensitiveValues(t *testing.T) {
+	r := newTestRedactor(t)
+
+	values := r.SensitiveValues(json.RawMessage(`{
+		"time_string": "25/12/2023 09:00",
+		"nested": {"jwt": "abc.def.ghi", "timezone": "UTC"},
+		"list": [{"time_string": 1703494800}]
+	}`))
+	assert.ElementsMatch(t, []string{"25/12/2023 09:00", "abc.def.ghi", "1703494800"}, values)
+
+	assert.Nil(t, r.SensitiveValues(json.RawMessage(`not json`)))
+}
+
+func TestRedactor_Message(t *testing.T) {
+	r := newTestRedactor(t)
+
+	msg := r.Message("failed to parse time string 2023-12-25 09:00 for patient-7", []string{"2023-12-25", "2023-12-25 09:00"})
+	assert.Equal(t, "failed to parse time string [REDACTED] for [REDACTED]", msg)
+}
+
+func TestRedactor_Middleware(t *testing.T) {
+	r := newTestRedactor(t)
+
+	failing := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "failed to parse time string 25/12/2023 with format RFC3339"}},
+			IsError: true,
+		}, nil
+	}
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name:      "parse_time",
+		Arguments: json.RawMessage(`{"time_string": "25/12/2023", "format": "RFC3339"}`),
+	}}
+
+	res, err := r.Middleware()(failing)(context.Background(), "tools/call", req)
+	require.NoError(t, err)
+	text := res.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text
+	assert.Equal(t, "failed to parse time string [REDACTED] with format RFC3339", text)
+}
+
+func TestRedactor_Middleware_LeavesSuccessfulResults(t *testing.T) {
+	r := newTestRedactor(t)
+
+	ok := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "patient-1"}}}, nil
+	}
+	res, err := r.Middleware()(ok)(context.Background(), "tools/call", &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "patient-1", res.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,235 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+// Entry is a single recorded tool call
+type Entry struct {
+	Tool       string          `json:"tool"`
+	Arguments  json.RawMessage `json:"arguments"`
+	Result     json.RawMessage `json:"result"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
+
+// key returns the lookup key for a tool call, canonicalizing the arguments
+// so that key order and whitespace differences between clients don't matter
+func key(tool string, arguments json.RawMessage) (string, error) {
+	if len(arguments) == 0 {
+		return tool + " {}", nil
+	}
+
+	var v any
+	if err := json.Unmarshal(arguments, &v); err != nil {
+		return "", fmt.Errorf("invalid tool arguments: %w", err)
+	}
+
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize tool arguments: %w", err)
+	}
+
+	return tool + " " + string(canonical), nil
+}
+
+// Recorder appends every tool call and its result to a JSON lines file
+type Recorder struct {
+	mu     sync.Mutex
+	file   *os.File
+	enc    *json.Encoder
+	logger *zap.Logger
+}
+
+// NewRecorder creates a recorder writing to path, truncating any previous recording
+func NewRecorder(path string, logger *zap.Logger) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file %s: %w", path, err)
+	}
+
+	return &Recorder{
+		file:   f,
+		enc:    json.NewEncoder(f),
+		logger: logger,
+	}, nil
+}
+
+// Middleware returns an MCP receiving middleware that records tool calls
+func (r *Recorder) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" || err != nil {
+				return res, err
+			}
+
+			if recErr := r.record(callReq.Params.Name, callReq.Params.Arguments, res); recErr != nil {
+				r.logger.Error("Failed to record tool call",
+					zap.String("tool", callReq.Params.Name),
+					zap.Error(recErr))
+			}
+
+			return res, err
+		}
+	}
+}
+
+// record writes one entry to the recording file
+func (r *Recorder) record(tool string, arguments json.RawMessage, res mcp.Result) error {
+	result, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool result: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.enc.Encode(Entry{
+		Tool:       tool,
+		Arguments:  arguments,
+		Result:     result,
+		RecordedAt: time.Now().UTC(),
+	})
+}
+
+// Close flushes and closes the recording file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Player serves recorded tool results instead of executing tools
+type Player struct {
+	mu        sync.Mutex
+	responses map[string][]json.RawMessage
+	served    map[string]int
+	startTime time.Time
+	logger    *zap.Logger
+}
+
+// LoadPlayer reads a recording file created by a Recorder
+func LoadPlayer(path string, logger *zap.Logger) (*Player, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	p := &Player{
+		responses: make(map[string][]json.RawMessage),
+		served:    make(map[string]int),
+		logger:    logger,
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid recording entry at line %d: %w", line, err)
+		}
+
+		k, err := key(entry.Tool, entry.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recording entry at line %d: %w", line, err)
+		}
+
+		p.responses[k] = append(p.responses[k], entry.Result)
+		if p.startTime.IsZero() {
+			p.startTime = entry.RecordedAt
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// StartTime returns when the recording started, used as the frozen clock
+func (p *Player) StartTime() time.Time {
+	return p.startTime
+}
+
+// next returns the next recorded result for a key. Repeated calls are served
+// in recording order, and the last result is repeated once they run out.
+func (p *Player) next(k string) (json.RawMessage, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	results, ok := p.responses[k]
+	if !ok {
+		return nil, false
+	}
+
+	i := p.served[k]
+	if i >= len(results) {
+		i = len(results) - 1
+	}
+	p.served[k]++
+
+	return results[i], true
+}
+
+// Middleware returns an MCP receiving middleware that serves recorded results.
+// Calls missing from the recording fall through to the live tool.
+func (p *Player) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			k, err := key(callReq.Params.Name, callReq.Params.Arguments)
+			if err != nil {
+				return next(ctx, method, req)
+			}
+
+			raw, ok := p.next(k)
+			if !ok {
+				p.logger.Warn("No recorded response for tool call, executing live",
+					zap.String("tool", callReq.Params.Name))
+				return next(ctx, method, req)
+			}
+
+			return decodeResult(raw)
+		}
+	}
+}
+
+// decodeResult rebuilds a tool result, keeping structured content byte-identical
+func decodeResult(raw json.RawMessage) (*mcp.CallToolResult, error) {
+	var res mcp.CallToolResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("invalid recorded result: %w", err)
+	}
+
+	var structured struct {
+		StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &structured); err != nil {
+		return nil, fmt.Errorf("invalid recorded result: %w", err)
+	}
+	if len(structured.StructuredContent) > 0 {
+		res.StructuredContent = structured.StructuredContent
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,95 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func toolRequest(tool, arguments string) *mcp.CallToolRequest {
+	return &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: tool, Arguments: json.RawMessage(arguments)}}
+}
+
+func TestKey(t *testing.T) {
+	a, err := key("get_time", json.RawMessage(`{"timezone":"UTC","format":"Unix"}`))
+	require.NoError(t, err)
+	b, err := key("get_time", json.RawMessage(`{ "format": "Unix", "timezone": "UTC" }`))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	empty, err := key("get_time", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "get_time {}", empty)
+
+	_, err = key("get_time", json.RawMessage(`{`))
+	assert.Error(t, err)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+
+	recorder, err := NewRecorder(path, logger)
+	require.NoError(t, err)
+
+	calls := 0
+	live := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		calls++
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{&mcp.TextContent{Text: "live"}},
+			StructuredContent: json.RawMessage(`{"z":1,"a":2}`),
+		}, nil
+	}
+
+	_, err = recorder.Middleware()(live)(context.Background(), "tools/call", toolRequest("get_time", `{"timezone":"UTC"}`))
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+	require.Equal(t, 1, calls)
+
+	player, err := LoadPlayer(path, logger)
+	require.NoError(t, err)
+	assert.False(t, player.StartTime().IsZero())
+
+	// Recorded call is served without executing the tool
+	res, err := player.Middleware()(live)(context.Background(), "tools/call", toolRequest("get_time", `{ "timezone": "UTC" }`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	result, ok := res.(*mcp.CallToolResult)
+	require.True(t, ok)
+	assert.Equal(t, json.RawMessage(`{"z":1,"a":2}`), result.StructuredContent)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "live", result.Content[0].(*mcp.TextContent).Text)
+
+	// Unrecorded call falls through to the live tool
+	_, err = player.Middleware()(live)(context.Background(), "tools/call", toolRequest("get_time", `{"timezone":"Asia/Tokyo"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPlayer_ServesRepeatedCallsInOrder(t *testing.T) {
+	p := &Player{
+		responses: map[string][]json.RawMessage{"k": {json.RawMessage(`1`), json.RawMessage(`2`)}},
+		served:    make(map[string]int),
+	}
+
+	for _, want := range []string{"1", "2", "2"} {
+		got, ok := p.next("k")
+		require.True(t, ok)
+		assert.Equal(t, want, string(got))
+	}
+
+	_, ok := p.next("missing")
+	assert.False(t, ok)
+}
+
+func TestLoadPlayer_MissingFile(t *testing.T) {
+	_, err := LoadPlayer(filepath.Join(t.TempDir(), "missing.jsonl"), zaptest.NewLogger(t))
+	assert.Error(t, err)
+}
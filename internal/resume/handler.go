@@ -0,0 +1,372 @@
+package resume
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Headers of the streamable HTTP transport
+const (
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "Mcp-Protocol-Version"
+	lastEventIDHeader     = "Last-Event-ID"
+)
+
+// protocolVersions are the versions the SDK negotiates; a request naming
+// another is rejected, as the SDK's own handler does
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// defaultProtocolVersion is assumed when a request names none
+const defaultProtocolVersion = "2025-03-26"
+
+// Handler serves the streamable HTTP transport like
+// mcp.StreamableHTTPHandler, whose transports each buffer events in a
+// store of their own, but keeps every session's events and state in one
+// SharedStore. The buffer is bounded per session, replays are measured, and
+// a session another replica created is restored from the store, so its
+// client resumes here when the load balancer moves it
+type Handler struct {
+	server   *mcp.Server
+	store    SharedStore
+	metrics  *metrics.Metrics
+	logger   *zap.Logger
+	restored func(*mcp.ServerSession)
+
+	mu        sync.Mutex
+	sessions  map[string]*session
+	restoring map[string]*restore
+}
+
+// restore is a session being restored from the store; other requests for it
+// wait for it to finish
+type restore struct {
+	done    chan struct{}
+	session *session
+	err     error
+}
+
+// session is a connected session and its transport
+type session struct {
+	transport *mcp.StreamableServerTransport
+	session   *mcp.ServerSession
+
+	mu      sync.Mutex
+	streams map[string]bool // opened on this replica
+}
+
+// NewHandler creates a handler serving server's sessions with events and
+// state kept in store
+func NewHandler(server *mcp.Server, store SharedStore, metrics *metrics.Metrics, logger *zap.Logger) *Handler {
+	return &Handler{
+		server:    server,
+		store:     store,
+		metrics:   metrics,
+		logger:    logger,
+		sessions:  make(map[string]*session),
+		restoring: make(map[string]*restore),
+	}
+}
+
+// OnRestore calls fn with every session restored from the store, before it
+// serves a request. The store keeps a session's initialize state only, so
+// fn rebuilds what the server derives from it. Call it before serving
+func (h *Handler) OnRestore(fn func(*mcp.ServerSession)) {
+	h.restored = fn
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := checkAccept(r); err != "" {
+		http.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	id := r.Header.Get(sessionIDHeader)
+	var s *session
+	if id != "" {
+		var err error
+		if s, err = h.lookup(r.Context(), id); err != nil {
+			h.logger.Error("Failed to restore session", zap.String("session_id", id), zap.Error(err))
+			http.Error(w, "failed to restore session", http.StatusInternalServerError)
+			return
+		}
+		if s == nil {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		if s == nil {
+			http.Error(w, "Bad Request: DELETE requires an Mcp-Session-Id header", http.StatusBadRequest)
+			return
+		}
+		h.remove(id)
+		s.session.Close()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case http.MethodGet:
+		if s == nil {
+			http.Error(w, "GET requires an active session", http.StatusMethodNotAllowed)
+			return
+		}
+	case http.MethodPost:
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method Not Allowed: streamable MCP servers support GET, POST, and DELETE requests", http.StatusMethodNotAllowed)
+		return
+	}
+
+	version := r.Header.Get(protocolVersionHeader)
+	if version == "" {
+		version = defaultProtocolVersion
+	}
+	if !slices.Contains(protocolVersions, version) {
+		http.Error(w, fmt.Sprintf("Bad Request: Unsupported protocol version (supported versions: %s)",
+			strings.Join(protocolVersions, ",")), http.StatusBadRequest)
+		return
+	}
+
+	if s == nil {
+		var err error
+		if s, err = h.connect(r.Context(), newSessionID(), nil); err != nil {
+			h.logger.Error("Failed to connect session", zap.Error(err))
+			http.Error(w, "failed connection", http.StatusInternalServerError)
+			return
+		}
+		h.mu.Lock()
+		h.sessions[s.transport.SessionID] = s
+		h.mu.Unlock()
+	}
+	if r.Method == http.MethodGet && len(r.Header.Values(lastEventIDHeader)) > 0 {
+		h.logger.Debug("Resuming stream",
+			zap.String("session_id", id),
+			zap.String("last_event_id", r.Header.Get(lastEventIDHeader)))
+		r = r.WithContext(withReplay(r.Context()))
+		// The transport only knows the streams opened here; the store has
+		// the events of those another replica opened
+		if stream, index, ok := parseEventID(r.Header.Get(lastEventIDHeader)); ok && !s.opened(stream) {
+			h.replay(w, r, s.transport.SessionID, stream, index)
+			return
+		}
+	}
+	s.transport.ServeHTTP(w, r)
+
+	// initialize is answered before the POST carrying it returns, so the
+	// session is initialized now, and another replica can restore it
+	if id == "" {
+		if params := s.session.InitializeParams(); params != nil {
+			if err := h.store.SaveSession(r.Context(), s.transport.SessionID, &mcp.ServerSessionState{InitializeParams: params}); err != nil {
+				h.logger.Error("Failed to save session", zap.String("session_id", s.transport.SessionID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// lookup returns the session with id, restoring it from the store when
+// another replica created it, or nil when the store doesn't hold it either.
+// The store is read without holding h.mu, and only once for concurrent
+// requests restoring one session
+func (h *Handler) lookup(ctx context.Context, id string) (*session, error) {
+	h.mu.Lock()
+	if s, ok := h.sessions[id]; ok {
+		h.mu.Unlock()
+		return s, nil
+	}
+	if p, ok := h.restoring[id]; ok {
+		h.mu.Unlock()
+		select {
+		case <-p.done:
+			return p.session, p.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	p := &restore{done: make(chan struct{})}
+	h.restoring[id] = p
+	h.mu.Unlock()
+
+	p.session, p.err = h.restore(ctx, id)
+	h.mu.Lock()
+	delete(h.restoring, id)
+	if p.session != nil {
+		h.sessions[id] = p.session
+	}
+	h.mu.Unlock()
+	close(p.done)
+	return p.session, p.err
+}
+
+// restore connects a session from its state in the store, or returns nil
+// when the store doesn't hold it
+func (h *Handler) restore(ctx context.Context, id string) (*session, error) {
+	state, err := h.store.LoadSession(ctx, id)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	// A client only moves replicas once initialize is answered, and sends
+	// notifications/initialized right after it
+	if state.InitializedParams == nil {
+		state.InitializedParams = &mcp.InitializedParams{}
+	}
+	s, err := h.connect(ctx, id, state)
+	if err != nil {
+		return nil, err
+	}
+	if h.restored != nil {
+		h.restored(s.session)
+	}
+	h.metrics.RecordSessionRestored()
+	h.logger.Info("Restored session created by another replica", zap.String("session_id", id))
+	return s, nil
+}
+
+// connect starts a session with id, from state when it is restored. The
+// session is forgotten once it closes
+func (h *Handler) connect(ctx context.Context, id string, state *mcp.ServerSessionState) (*session, error) {
+	s := &session{streams: make(map[string]bool)}
+	s.transport = &mcp.StreamableServerTransport{
+		SessionID:  id,
+		EventStore: localStreams{SharedStore: h.store, session: s},
+	}
+	// The request context carries middleware values; the SDK detaches it
+	// for the long-running session
+	ss, err := h.server.Connect(ctx, s.transport, &mcp.ServerSessionOptions{State: state})
+	if err != nil {
+		return nil, err
+	}
+	s.session = ss
+	go func() {
+		ss.Wait()
+		h.remove(id)
+	}()
+	return s, nil
+}
+
+// replay writes the events of a stream this replica didn't open, from the
+// store, and ends the response: the request the stream answered was served
+// elsewhere, so no more events come here
+func (h *Handler) replay(w http.ResponseWriter, r *http.Request, sessionID, streamID string, index int) {
+	written := false
+	for data, err := range h.store.After(r.Context(), sessionID, streamID, index) {
+		if err != nil {
+			if !written {
+				status := http.StatusInternalServerError
+				if errors.Is(err, ErrUnknownStream) {
+					status = http.StatusBadRequest
+				}
+				http.Error(w, err.Error(), status)
+			}
+			return
+		}
+		if !written {
+			w.Header().Set("Cache-Control", "no-cache, no-transform")
+			w.Header().Set("Content-Type", "text/event-stream")
+			written = true
+		}
+		index++
+		if _, err := fmt.Fprintf(w, "event: message\nid: %s_%d\ndata: %s\n\n", streamID, index, data); err != nil {
+			return
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	if !written {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// opened reports whether the stream was opened on this replica
+func (s *session) opened(streamID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams[streamID]
+}
+
+// localStreams is the event store of one session's transport. It records
+// the streams the transport opens, which it serves and resumes itself
+type localStreams struct {
+	SharedStore
+	session *session
+}
+
+// Open implements mcp.EventStore
+func (l localStreams) Open(ctx context.Context, sessionID, streamID string) error {
+	l.session.mu.Lock()
+	l.session.streams[streamID] = true
+	l.session.mu.Unlock()
+	return l.SharedStore.Open(ctx, sessionID, streamID)
+}
+
+// remove forgets a session
+func (h *Handler) remove(id string) {
+	h.mu.Lock()
+	delete(h.sessions, id)
+	h.mu.Unlock()
+}
+
+// Sessions returns the number of open sessions
+func (h *Handler) Sessions() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.sessions)
+}
+
+// checkAccept checks the Accept headers allow the responses the method
+// gets, returning the error to answer with
+func checkAccept(r *http.Request) string {
+	var jsonOK, streamOK bool
+	for _, c := range strings.Split(strings.Join(r.Header.Values("Accept"), ","), ",") {
+		switch strings.TrimSpace(c) {
+		case "application/json", "application/*":
+			jsonOK = true
+		case "text/event-stream", "text/*":
+			streamOK = true
+		case "*/*":
+			jsonOK, streamOK = true, true
+		}
+	}
+	switch {
+	case r.Method == http.MethodGet && !streamOK:
+		return "Accept must contain 'text/event-stream' for GET requests"
+	case r.Method != http.MethodGet && r.Method != http.MethodDelete && (!jsonOK || !streamOK):
+		return "Accept must contain both 'application/json' and 'text/event-stream'"
+	}
+	return ""
+}
+
+// parseEventID splits an SSE event id the SDK wrote, <stream>_<index>
+func parseEventID(eventID string) (string, int, bool) {
+	stream, index, ok := strings.Cut(eventID, "_")
+	if !ok || strings.Contains(index, "_") {
+		return "", 0, false
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 {
+		return "", 0, false
+	}
+	return stream, i, true
+}
+
+// newSessionID returns a random session ID, unique across replicas
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+}
@@ -0,0 +1,285 @@
+package resume
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// eventIDPattern finds the ids of the SSE events in a response
+var eventIDPattern = regexp.MustCompile(`(?m)^id: (\S+)$`)
+
+// post sends a JSON-RPC message to the handler
+func post(t *testing.T, url, session, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if session != "" {
+		req.Header.Set(sessionIDHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// resumeFrom sends a GET resuming a stream after the event lastEventID
+func resumeFrom(t *testing.T, url, session, lastEventID string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionIDHeader, session)
+	req.Header.Set(lastEventIDHeader, lastEventID)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// newSlowServer creates a server whose slow tool sends a progress
+// notification ahead of its result
+func newSlowServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "slow"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: req.Params.GetProgressToken(), Progress: 1, Total: 2,
+		})
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil, err
+	})
+	return server
+}
+
+// startSession initializes a session and calls the slow tool, returning the
+// session and the ids of the call's events
+func startSession(t *testing.T, url string) (string, []string) {
+	t.Helper()
+	resp := post(t, url, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	session := resp.Header.Get(sessionIDHeader)
+	require.NotEmpty(t, session)
+	readBody(t, resp)
+	assert.Equal(t, http.StatusAccepted, post(t, url, session, `{"jsonrpc":"2.0","method":"notifications/initialized"}`).StatusCode)
+
+	resp = post(t, url, session, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow","arguments":{},"_meta":{"progressToken":"p"}}}`)
+	body := readBody(t, resp)
+	matches := eventIDPattern.FindAllStringSubmatch(body, -1)
+	require.Len(t, matches, 2, body)
+	assert.Contains(t, body, "notifications/progress")
+	assert.Contains(t, body, "done")
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match[1]
+	}
+	return session, ids
+}
+
+func TestHandler_Resume(t *testing.T) {
+	m := newTestMetrics()
+	handler := NewHandler(newSlowServer(), NewStore(1<<20, m), m, zaptest.NewLogger(t))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	session, ids := startSession(t, ts.URL)
+
+	// A client that only saw the progress notification gets the result again
+	resp := resumeFrom(t, ts.URL, session, ids[0])
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := readBody(t, resp)
+	assert.Contains(t, body, "id: "+ids[1])
+	assert.Contains(t, body, "done")
+	assert.NotContains(t, body, "notifications/progress")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.StreamReplaysTotal.WithLabelValues(ReplayReplayed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.StreamReplayedEventsTotal))
+
+	// Deleting the session drops it and its events
+	req, err := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(sessionIDHeader, session)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 0, handler.Sessions())
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.StreamBufferBytes))
+	assert.Equal(t, http.StatusNotFound, post(t, ts.URL, session, `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`).StatusCode)
+}
+
+func TestHandler_ResumeOnAnotherReplica(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		m := newTestMetrics()
+		store := NewStore(1<<20, m)
+		testResumeOnAnotherReplica(t, store, store, m)
+	})
+	t.Run("redis", func(t *testing.T) {
+		// Each replica has its own client, as separate processes would
+		m := newTestMetrics()
+		redisServer := miniredis.RunT(t)
+		first := NewRedisStore(newRedisClient(t, redisServer), "test:", time.Hour, 1<<20, m)
+		second := NewRedisStore(newRedisClient(t, redisServer), "test:", time.Hour, 1<<20, m)
+		testResumeOnAnotherReplica(t, first, second, m)
+	})
+}
+
+// testResumeOnAnotherReplica runs two replicas behind a load balancer, over
+// one store reached through firstStore and secondStore
+func testResumeOnAnotherReplica(t *testing.T, firstStore, secondStore SharedStore, m *metrics.Metrics) {
+	first := httptest.NewServer(NewHandler(newSlowServer(), firstStore, m, zaptest.NewLogger(t)))
+	defer first.Close()
+	handler := NewHandler(newSlowServer(), secondStore, m, zaptest.NewLogger(t))
+	var restored atomic.Int32
+	handler.OnRestore(func(*mcp.ServerSession) { restored.Add(1) })
+	second := httptest.NewServer(handler)
+	defer second.Close()
+
+	session, ids := startSession(t, first.URL)
+
+	// The first replica went away after the progress notification; the
+	// second replays the result from the store
+	resp := resumeFrom(t, second.URL, session, ids[0])
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	body := readBody(t, resp)
+	assert.Contains(t, body, "id: "+ids[1])
+	assert.Contains(t, body, "done")
+	assert.NotContains(t, body, "notifications/progress")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionsRestoredTotal))
+	assert.Equal(t, int32(1), restored.Load())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.StreamReplaysTotal.WithLabelValues(ReplayReplayed)))
+
+	// The restored session takes new calls without initializing again
+	resp = post(t, second.URL, session, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"slow","arguments":{}}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, readBody(t, resp), "done")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionsRestoredTotal))
+
+	assert.Equal(t, http.StatusBadRequest, resumeFrom(t, second.URL, session, "unknown_0").StatusCode)
+
+	// Deleting the session on either replica drops it from the store
+	req, err := http.NewRequest(http.MethodDelete, second.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(sessionIDHeader, session)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	state, err := firstStore.LoadSession(context.Background(), session)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestHandler_RestoresOnce(t *testing.T) {
+	m := newTestMetrics()
+	store := NewStore(1<<20, m)
+	first := httptest.NewServer(NewHandler(newSlowServer(), store, m, zaptest.NewLogger(t)))
+	defer first.Close()
+	second := httptest.NewServer(NewHandler(newSlowServer(), store, m, zaptest.NewLogger(t)))
+	defer second.Close()
+	session, _ := startSession(t, first.URL)
+
+	// Requests racing to a replica restore the session once between them
+	var wg sync.WaitGroup
+	statuses := make([]int, 8)
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodPost, second.URL, strings.NewReader(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/list"}`, i+3)))
+			if err != nil {
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json, text/event-stream")
+			req.Header.Set(sessionIDHeader, session)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			statuses[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	for _, status := range statuses {
+		assert.Equal(t, http.StatusOK, status)
+	}
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionsRestoredTotal))
+}
+
+func TestParseEventID(t *testing.T) {
+	stream, index, ok := parseEventID("abc_3")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", stream)
+	assert.Equal(t, 3, index)
+
+	stream, index, ok = parseEventID("_0")
+	assert.True(t, ok)
+	assert.Equal(t, "", stream)
+	assert.Equal(t, 0, index)
+
+	for _, id := range []string{"abc", "a_b_1", "abc_-1", "abc_x"} {
+		_, _, ok := parseEventID(id)
+		assert.False(t, ok, id)
+	}
+}
+
+func TestHandler_Rejects(t *testing.T) {
+	m := newTestMetrics()
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	ts := httptest.NewServer(NewHandler(server, NewStore(0, m), m, zaptest.NewLogger(t)))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		expected int
+	}{
+		{name: "json only", method: http.MethodPost, headers: map[string]string{"Accept": "application/json"}, expected: http.StatusBadRequest},
+		{name: "get without session", method: http.MethodGet, headers: map[string]string{"Accept": "text/event-stream"}, expected: http.StatusMethodNotAllowed},
+		{name: "unknown session", method: http.MethodGet, headers: map[string]string{"Accept": "text/event-stream", sessionIDHeader: "gone"}, expected: http.StatusNotFound},
+		{name: "delete without session", method: http.MethodDelete, expected: http.StatusBadRequest},
+		{name: "put", method: http.MethodPut, headers: map[string]string{"Accept": "*/*"}, expected: http.StatusMethodNotAllowed},
+		{name: "unsupported version", method: http.MethodPost, headers: map[string]string{"Accept": "*/*", protocolVersionHeader: "2099-01-01"}, expected: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, ts.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+			require.NoError(t, err)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.expected, resp.StatusCode)
+		})
+	}
+}
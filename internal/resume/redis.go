@@ -0,0 +1,225 @@
+package resume
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// RedisStore is a SharedStore in Redis, for replicas to resume each other's
+// sessions. Like Store, it keeps at most maxBytes of events per session,
+// dropping the oldest first; a session's keys expire once it has been idle
+// for the TTL.
+//
+// A session is three keys sharing a hash tag, so they live on one Redis
+// Cluster slot: a hash of counters, a list of events in the order they were
+// appended, and the session's state
+type RedisStore struct {
+	client   redis.UniversalClient
+	prefix   string
+	ttl      time.Duration
+	maxBytes int
+	metrics  *metrics.Metrics
+}
+
+var _ SharedStore = (*RedisStore)(nil)
+
+// appendScript drops a session's oldest events until the new one fits,
+// then appends it and returns its stream index. Each event is stored as
+// "<stream>\n<index>\n<data>"; the meta hash holds the session's bytes and,
+// per stream, the next index and the index of its oldest event kept
+var appendScript = redis.NewScript(`
+local meta, log, state = KEYS[1], KEYS[2], KEYS[3]
+local stream, data = ARGV[1], ARGV[2]
+local maxBytes, ttl = tonumber(ARGV[3]), tonumber(ARGV[4])
+local size = string.len(data)
+local bytes = tonumber(redis.call('HGET', meta, 'bytes') or 0)
+while bytes > 0 and bytes + size > maxBytes do
+	local oldest = redis.call('LPOP', log)
+	if not oldest then
+		bytes = 0
+		break
+	end
+	local i = string.find(oldest, '\n', 1, true)
+	local j = string.find(oldest, '\n', i + 1, true)
+	redis.call('HINCRBY', meta, 'first:' .. string.sub(oldest, 1, i - 1), 1)
+	bytes = bytes - (string.len(oldest) - j)
+end
+local index = redis.call('HINCRBY', meta, 'next:' .. stream, 1) - 1
+redis.call('RPUSH', log, stream .. '\n' .. index .. '\n' .. data)
+redis.call('HSET', meta, 'bytes', bytes + size)
+redis.call('PEXPIRE', meta, ttl)
+redis.call('PEXPIRE', log, ttl)
+redis.call('PEXPIRE', state, ttl)
+return index
+`)
+
+// NewRedisStore creates a store in Redis keeping at most maxBytes of events
+// per session, config.DefaultStreamBufferBytes when zero. Keys start with
+// prefix and expire after ttl without events
+func NewRedisStore(client redis.UniversalClient, prefix string, ttl time.Duration, maxBytes int, metrics *metrics.Metrics) *RedisStore {
+	if maxBytes <= 0 {
+		maxBytes = config.DefaultStreamBufferBytes
+	}
+	return &RedisStore{
+		client:   client,
+		prefix:   prefix,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		metrics:  metrics,
+	}
+}
+
+// keys returns the meta, log and state keys of a session
+func (s *RedisStore) keys(sessionID string) []string {
+	base := s.prefix + "session:{" + sessionID + "}"
+	return []string{base + ":meta", base + ":log", base + ":state"}
+}
+
+// Open implements mcp.EventStore
+func (s *RedisStore) Open(ctx context.Context, sessionID, streamID string) error {
+	keys := s.keys(sessionID)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSetNX(ctx, keys[0], "next:"+streamID, 0)
+		pipe.HSetNX(ctx, keys[0], "first:"+streamID, 0)
+		pipe.PExpire(ctx, keys[0], s.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("open stream %q in session %q: %w", streamID, sessionID, err)
+	}
+	return nil
+}
+
+// Append implements mcp.EventStore, first dropping the session's oldest
+// events until data fits. An event larger than the buffer is still kept,
+// alone, so the live stream never loses it
+func (s *RedisStore) Append(ctx context.Context, sessionID, streamID string, data []byte) error {
+	args := []any{streamID, data, s.maxBytes, s.ttl.Milliseconds()}
+	if err := appendScript.Run(ctx, s.client, s.keys(sessionID), args...).Err(); err != nil {
+		return fmt.Errorf("append to stream %q in session %q: %w", streamID, sessionID, err)
+	}
+	return nil
+}
+
+// After implements mcp.EventStore. The first read of a request carrying
+// Last-Event-ID is the replay, and is counted by its result
+func (s *RedisStore) After(ctx context.Context, sessionID, streamID string, index int) iter.Seq2[[]byte, error] {
+	events, err := s.after(ctx, sessionID, streamID, index)
+	countReplay(ctx, s.metrics, len(events), err)
+	return replayEvents(events, err)
+}
+
+// after reads the events of a stream after index
+func (s *RedisStore) after(ctx context.Context, sessionID, streamID string, index int) ([][]byte, error) {
+	keys := s.keys(sessionID)
+	var meta *redis.MapStringStringCmd
+	var log *redis.StringSliceCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		meta = pipe.HGetAll(ctx, keys[0])
+		log = pipe.LRange(ctx, keys[1], 0, -1)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read stream %q in session %q: %w", streamID, sessionID, err)
+	}
+
+	counters := meta.Val()
+	if len(counters) == 0 {
+		return nil, fmt.Errorf("session %q: %w", sessionID, ErrUnknownStream)
+	}
+	if _, ok := counters["next:"+streamID]; !ok {
+		return nil, fmt.Errorf("stream %q in session %q: %w", streamID, sessionID, ErrUnknownStream)
+	}
+	first, _ := strconv.Atoi(counters["first:"+streamID])
+	start := index + 1
+	if start < first {
+		return nil, fmt.Errorf("stream %q in session %q after %d: %w", streamID, sessionID, index, mcp.ErrEventsPurged)
+	}
+
+	var events [][]byte
+	for _, entry := range log.Val() {
+		parts := strings.SplitN(entry, "\n", 3)
+		if len(parts) != 3 || parts[0] != streamID {
+			continue
+		}
+		if i, err := strconv.Atoi(parts[1]); err == nil && i >= start {
+			events = append(events, []byte(parts[2]))
+		}
+	}
+	return events, nil
+}
+
+// SaveSession implements SharedStore
+func (s *RedisStore) SaveSession(ctx context.Context, sessionID string, state *mcp.ServerSessionState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode session %q: %w", sessionID, err)
+	}
+	if err := s.client.Set(ctx, s.keys(sessionID)[2], data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("save session %q: %w", sessionID, err)
+	}
+	return nil
+}
+
+// LoadSession implements SharedStore
+func (s *RedisStore) LoadSession(ctx context.Context, sessionID string) (*mcp.ServerSessionState, error) {
+	data, err := s.client.Get(ctx, s.keys(sessionID)[2]).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load session %q: %w", sessionID, err)
+	}
+	var state mcp.ServerSessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("decode session %q: %w", sessionID, err)
+	}
+	return &state, nil
+}
+
+// SessionClosed implements mcp.EventStore, dropping the session's events
+// and state
+func (s *RedisStore) SessionClosed(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, s.keys(sessionID)...).Err(); err != nil {
+		return fmt.Errorf("close session %q: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Close closes the Redis client
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// NewSharedStore creates the store cfg.StreamStore selects: a Store in
+// this process, or a RedisStore the replicas share, checked to be
+// reachable
+func NewSharedStore(ctx context.Context, cfg config.ServerConfig, metrics *metrics.Metrics) (SharedStore, error) {
+	if cfg.StreamStore.Backend != config.StreamStoreRedis {
+		return NewStore(cfg.StreamBufferBytes, metrics), nil
+	}
+	redisCfg := cfg.StreamStore.Redis
+	client := redis.NewClient(&redis.Options{
+		Addr:     redisCfg.Addr,
+		Username: redisCfg.Username,
+		Password: redisCfg.Password,
+		DB:       redisCfg.DB,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("connect to redis at %s: %w", redisCfg.Addr, err)
+	}
+	return NewRedisStore(client, redisCfg.KeyPrefix, redisCfg.TTL, cfg.StreamBufferBytes, metrics), nil
+}
@@ -0,0 +1,161 @@
+package resume
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+)
+
+// newRedisClient connects to a test Redis server
+func newRedisClient(t *testing.T, server *miniredis.Miniredis) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func newTestRedisStore(t *testing.T, maxBytes int) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	return NewRedisStore(newRedisClient(t, server), "test:", time.Hour, maxBytes, newTestMetrics()), server
+}
+
+func TestRedisStore_After(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRedisStore(t, 1024)
+	require.NoError(t, store.Open(ctx, "s1", "a"))
+	for _, data := range []string{"one", "two", "three"} {
+		require.NoError(t, store.Append(ctx, "s1", "a", []byte(data)))
+	}
+
+	events, err := collect(t, ctx, store, "s1", "a", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two", "three"}, events)
+
+	events, err = collect(t, ctx, store, "s1", "a", 2)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// An opened stream without events is known
+	require.NoError(t, store.Open(ctx, "s1", "b"))
+	events, err = collect(t, ctx, store, "s1", "b", -1)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	_, err = collect(t, ctx, store, "s1", "c", 0)
+	assert.ErrorIs(t, err, ErrUnknownStream)
+	_, err = collect(t, ctx, store, "s2", "a", 0)
+	assert.ErrorIs(t, err, ErrUnknownStream)
+	assert.ErrorContains(t, err, `session "s2"`)
+}
+
+func TestRedisStore_Bounded(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRedisStore(t, 10)
+
+	// The oldest event goes first, whichever stream holds it
+	require.NoError(t, store.Append(ctx, "s1", "a", []byte("aaaa")))
+	require.NoError(t, store.Append(ctx, "s1", "b", []byte("bbbb")))
+	require.NoError(t, store.Append(ctx, "s1", "a", []byte("cccc")))
+
+	_, err := collect(t, ctx, store, "s1", "a", -1)
+	assert.ErrorIs(t, err, mcp.ErrEventsPurged)
+	events, err := collect(t, ctx, store, "s1", "a", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cccc"}, events)
+	events, err = collect(t, ctx, store, "s1", "b", -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bbbb"}, events)
+
+	// An event larger than the buffer is kept alone
+	require.NoError(t, store.Append(ctx, "s1", "b", []byte("eeeeeeeeeeee")))
+	events, err = collect(t, ctx, store, "s1", "b", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eeeeeeeeeeee"}, events)
+	_, err = collect(t, ctx, store, "s1", "a", 0)
+	assert.ErrorIs(t, err, mcp.ErrEventsPurged)
+}
+
+func TestRedisStore_Sessions(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestRedisStore(t, 1024)
+
+	state, err := store.LoadSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	saved := &mcp.ServerSessionState{InitializeParams: &mcp.InitializeParams{ProtocolVersion: "2025-06-18"}}
+	require.NoError(t, store.SaveSession(ctx, "s1", saved))
+	state, err = store.LoadSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, saved, state)
+
+	// Closing the session drops its state with its events
+	require.NoError(t, store.Append(ctx, "s1", "a", []byte("one")))
+	require.NoError(t, store.SessionClosed(ctx, "s1"))
+	state, err = store.LoadSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	assert.Empty(t, server.Keys())
+
+	// An idle session expires, events and state
+	require.NoError(t, store.SaveSession(ctx, "s2", saved))
+	require.NoError(t, store.Append(ctx, "s2", "a", []byte("one")))
+	server.FastForward(30 * time.Minute)
+	require.NoError(t, store.Append(ctx, "s2", "a", []byte("two")))
+	server.FastForward(30 * time.Minute)
+	state, err = store.LoadSession(ctx, "s2")
+	require.NoError(t, err)
+	assert.NotNil(t, state, "events renew the session")
+	server.FastForward(time.Hour)
+	assert.Empty(t, server.Keys())
+}
+
+func TestRedisStore_ReplayMetrics(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRedisStore(t, 8)
+	for _, data := range []string{"one", "two", "three"} {
+		require.NoError(t, store.Append(ctx, "s1", "a", []byte(data)))
+	}
+
+	events, err := collect(t, withReplay(ctx), store, "s1", "a", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three"}, events)
+	assert.Equal(t, 1.0, testutil.ToFloat64(store.metrics.StreamReplaysTotal.WithLabelValues(ReplayReplayed)))
+
+	_, err = collect(t, withReplay(ctx), store, "s1", "a", -1)
+	assert.ErrorIs(t, err, mcp.ErrEventsPurged)
+	assert.Equal(t, 1.0, testutil.ToFloat64(store.metrics.StreamReplaysTotal.WithLabelValues(ReplayPurged)))
+}
+
+func TestNewSharedStore(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMetrics()
+
+	store, err := NewSharedStore(ctx, config.ServerConfig{}, m)
+	require.NoError(t, err)
+	assert.IsType(t, &Store{}, store)
+
+	server := miniredis.RunT(t)
+	cfg := config.ServerConfig{StreamStore: config.StreamStoreConfig{
+		Backend: config.StreamStoreRedis,
+		Redis:   config.RedisConfig{Addr: server.Addr(), KeyPrefix: "test:", TTL: time.Hour},
+	}}
+	store, err = NewSharedStore(ctx, cfg, m)
+	require.NoError(t, err)
+	require.IsType(t, &RedisStore{}, store)
+	require.NoError(t, store.(*RedisStore).Close())
+
+	server.Close()
+	_, err = NewSharedStore(ctx, cfg, m)
+	assert.ErrorContains(t, err, "connect to redis")
+}
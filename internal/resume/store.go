@@ -0,0 +1,275 @@
+// Package resume lets streamable HTTP clients resume a stream after a
+// dropped connection. Every event sent on a session's streams is kept in a
+// bounded per-session buffer, and a GET with Last-Event-ID replays what the
+// client missed, so a network blip doesn't lose tool results. Replicas
+// sharing a SharedStore resume each other's sessions and streams.
+package resume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Replay results
+const (
+	ReplayReplayed = "replayed"
+	ReplayPurged   = "purged"
+)
+
+// ErrUnknownStream is returned by SharedStore.After for a session or stream
+// the store holds nothing of
+var ErrUnknownStream = errors.New("unknown stream")
+
+// SharedStore holds what a replica needs to serve a session another
+// replica created: the session's events, and its state from initialize.
+// Replicas behind one load balancer share a store to resume each other's
+// sessions; each replica's own Store only shares within the process
+type SharedStore interface {
+	mcp.EventStore
+
+	// SaveSession records the state of an initialized session
+	SaveSession(ctx context.Context, sessionID string, state *mcp.ServerSessionState) error
+
+	// LoadSession returns the state of a session, or nil when the store
+	// doesn't hold it. The state is dropped with the session's events, by
+	// SessionClosed
+	LoadSession(ctx context.Context, sessionID string) (*mcp.ServerSessionState, error)
+}
+
+// Store is a SharedStore keeping at most maxBytes of events per session,
+// dropping the session's oldest events first
+type Store struct {
+	maxBytes int
+	metrics  *metrics.Metrics
+
+	mu       sync.Mutex
+	bytes    int // across sessions
+	sessions map[string]*sessionBuffer
+	states   map[string]mcp.ServerSessionState
+}
+
+// sessionBuffer holds the events of one session's streams. Events are
+// numbered across streams in the order they were appended, so the oldest
+// can be found whichever stream holds it
+type sessionBuffer struct {
+	bytes   int
+	next    int
+	streams map[string]*streamBuffer
+}
+
+// streamBuffer holds the events of one stream still buffered; first is the
+// stream index of events[0]
+type streamBuffer struct {
+	first  int
+	events []event
+}
+
+type event struct {
+	seq  int
+	data []byte
+}
+
+var _ SharedStore = (*Store)(nil)
+
+// NewStore creates a store keeping at most maxBytes of events per session,
+// config.DefaultStreamBufferBytes when zero
+func NewStore(maxBytes int, metrics *metrics.Metrics) *Store {
+	if maxBytes <= 0 {
+		maxBytes = config.DefaultStreamBufferBytes
+	}
+	return &Store{
+		maxBytes: maxBytes,
+		metrics:  metrics,
+		sessions: make(map[string]*sessionBuffer),
+		states:   make(map[string]mcp.ServerSessionState),
+	}
+}
+
+// stream returns a session's stream buffer, creating it as needed. The
+// caller holds s.mu
+func (s *Store) stream(sessionID, streamID string) *streamBuffer {
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		session = &sessionBuffer{streams: make(map[string]*streamBuffer)}
+		s.sessions[sessionID] = session
+	}
+	stream, ok := session.streams[streamID]
+	if !ok {
+		stream = &streamBuffer{}
+		session.streams[streamID] = stream
+	}
+	return stream
+}
+
+// Open implements mcp.EventStore
+func (s *Store) Open(_ context.Context, sessionID, streamID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stream(sessionID, streamID)
+	return nil
+}
+
+// Append implements mcp.EventStore, first dropping the session's oldest
+// events until data fits. An event larger than the buffer is still kept,
+// alone, so the live stream never loses it
+func (s *Store) Append(_ context.Context, sessionID, streamID string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stream := s.stream(sessionID, streamID)
+	session := s.sessions[sessionID]
+	for session.bytes > 0 && session.bytes+len(data) > s.maxBytes {
+		s.dropOldest(session)
+	}
+	stream.events = append(stream.events, event{seq: session.next, data: data})
+	session.next++
+	session.bytes += len(data)
+	s.bytes += len(data)
+	s.metrics.SetStreamBufferBytes(s.bytes)
+	return nil
+}
+
+// dropOldest drops the session's oldest buffered event. The caller holds
+// s.mu
+func (s *Store) dropOldest(session *sessionBuffer) {
+	var oldest *streamBuffer
+	for _, stream := range session.streams {
+		if len(stream.events) > 0 && (oldest == nil || stream.events[0].seq < oldest.events[0].seq) {
+			oldest = stream
+		}
+	}
+	if oldest == nil {
+		return
+	}
+	size := len(oldest.events[0].data)
+	oldest.events[0] = event{}
+	oldest.events = oldest.events[1:]
+	oldest.first++
+	session.bytes -= size
+	s.bytes -= size
+}
+
+// After implements mcp.EventStore. The first read of a request carrying
+// Last-Event-ID is the replay, and is counted by its result
+func (s *Store) After(ctx context.Context, sessionID, streamID string, index int) iter.Seq2[[]byte, error] {
+	events, err := s.after(sessionID, streamID, index)
+	countReplay(ctx, s.metrics, len(events), err)
+	return replayEvents(events, err)
+}
+
+// after copies the events of a stream after index
+func (s *Store) after(sessionID, streamID string, index int) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("session %q: %w", sessionID, ErrUnknownStream)
+	}
+	stream, ok := session.streams[streamID]
+	if !ok {
+		return nil, fmt.Errorf("stream %q in session %q: %w", streamID, sessionID, ErrUnknownStream)
+	}
+	start := index + 1
+	if start < stream.first {
+		return nil, fmt.Errorf("stream %q in session %q after %d: %w", streamID, sessionID, index, mcp.ErrEventsPurged)
+	}
+	if start-stream.first > len(stream.events) {
+		return nil, nil
+	}
+	events := make([][]byte, 0, len(stream.events)-(start-stream.first))
+	for _, e := range stream.events[start-stream.first:] {
+		events = append(events, e.data)
+	}
+	return events, nil
+}
+
+// SaveSession implements SharedStore
+func (s *Store) SaveSession(_ context.Context, sessionID string, state *mcp.ServerSessionState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[sessionID] = *state
+	return nil
+}
+
+// LoadSession implements SharedStore
+func (s *Store) LoadSession(_ context.Context, sessionID string) (*mcp.ServerSessionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// SessionClosed implements mcp.EventStore, dropping the session's events
+// and state
+func (s *Store) SessionClosed(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, sessionID)
+	if session, ok := s.sessions[sessionID]; ok {
+		s.bytes -= session.bytes
+		delete(s.sessions, sessionID)
+		s.metrics.SetStreamBufferBytes(s.bytes)
+	}
+	return nil
+}
+
+// Bytes returns the size of the events buffered across sessions
+func (s *Store) Bytes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bytes
+}
+
+// replayKey marks the context of a request resuming a stream
+type replayKey struct{}
+
+// replay records whether a resuming request's replay was counted. Reads of
+// one request happen on its own goroutine
+type replay struct {
+	counted bool
+}
+
+// countReplay counts the first read of a request resuming a stream, by its
+// result
+func countReplay(ctx context.Context, m *metrics.Metrics, events int, err error) {
+	r, ok := ctx.Value(replayKey{}).(*replay)
+	if !ok || r.counted {
+		return
+	}
+	r.counted = true
+	if err != nil {
+		m.RecordStreamReplay(ReplayPurged, 0)
+	} else {
+		m.RecordStreamReplay(ReplayReplayed, events)
+	}
+}
+
+// replayEvents iterates events read from a store, or the error reading them
+func replayEvents(events [][]byte, err error) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, data := range events {
+			if !yield(data, nil) {
+				return
+			}
+		}
+	}
+}
+
+// withReplay marks ctx as resuming a stream
+func withReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, &replay{})
+}
@@ -0,0 +1,141 @@
+package resume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func newTestMetrics() *metrics.Metrics {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	return metrics.New()
+}
+
+// collect reads a stream after index
+func collect(t *testing.T, ctx context.Context, store mcp.EventStore, session, stream string, index int) ([]string, error) {
+	t.Helper()
+	var events []string
+	for data, err := range store.After(ctx, session, stream, index) {
+		if err != nil {
+			return events, err
+		}
+		events = append(events, string(data))
+	}
+	return events, nil
+}
+
+func TestStore_After(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(1024, newTestMetrics())
+	require.NoError(t, store.Open(ctx, "s1", "a"))
+	for _, data := range []string{"one", "two", "three"} {
+		require.NoError(t, store.Append(ctx, "s1", "a", []byte(data)))
+	}
+
+	events, err := collect(t, ctx, store, "s1", "a", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two", "three"}, events)
+
+	events, err = collect(t, ctx, store, "s1", "a", 2)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	_, err = collect(t, ctx, store, "s1", "b", 0)
+	assert.ErrorIs(t, err, ErrUnknownStream)
+	_, err = collect(t, ctx, store, "s2", "a", 0)
+	assert.ErrorIs(t, err, ErrUnknownStream)
+	assert.ErrorContains(t, err, `session "s2"`)
+}
+
+func TestStore_Sessions(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(1024, newTestMetrics())
+
+	state, err := store.LoadSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	saved := &mcp.ServerSessionState{InitializeParams: &mcp.InitializeParams{ProtocolVersion: "2025-06-18"}}
+	require.NoError(t, store.SaveSession(ctx, "s1", saved))
+	state, err = store.LoadSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, saved, state)
+
+	// Closing the session drops its state with its events
+	require.NoError(t, store.SessionClosed(ctx, "s1"))
+	state, err = store.LoadSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestStore_Bounded(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMetrics()
+	store := NewStore(10, m)
+
+	// The oldest event goes first, whichever stream holds it
+	require.NoError(t, store.Append(ctx, "s1", "a", []byte("aaaa")))
+	require.NoError(t, store.Append(ctx, "s1", "b", []byte("bbbb")))
+	require.NoError(t, store.Append(ctx, "s1", "a", []byte("cccc")))
+	assert.Equal(t, 8, store.Bytes())
+
+	_, err := collect(t, ctx, store, "s1", "a", -1)
+	assert.ErrorIs(t, err, mcp.ErrEventsPurged)
+	events, err := collect(t, ctx, store, "s1", "a", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cccc"}, events)
+	events, err = collect(t, ctx, store, "s1", "b", -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bbbb"}, events)
+
+	// Sessions are bounded on their own
+	require.NoError(t, store.Append(ctx, "s2", "a", []byte("dddddddd")))
+	assert.Equal(t, 16, store.Bytes())
+
+	// An event larger than the buffer is kept alone
+	require.NoError(t, store.Append(ctx, "s2", "a", []byte("eeeeeeeeeeee")))
+	events, err = collect(t, ctx, store, "s2", "a", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eeeeeeeeeeee"}, events)
+	assert.Equal(t, 20.0, testutil.ToFloat64(m.StreamBufferBytes))
+
+	require.NoError(t, store.SessionClosed(ctx, "s1"))
+	require.NoError(t, store.SessionClosed(ctx, "s2"))
+	assert.Equal(t, 0, store.Bytes())
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.StreamBufferBytes))
+}
+
+func TestStore_ReplayMetrics(t *testing.T) {
+	m := newTestMetrics()
+	store := NewStore(8, m)
+	ctx := context.Background()
+	for _, data := range []string{"one", "two", "three"} {
+		require.NoError(t, store.Append(ctx, "s1", "a", []byte(data)))
+	}
+
+	// Reads of the live stream are not replays
+	_, err := collect(t, ctx, store, "s1", "a", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.StreamReplaysTotal.WithLabelValues(ReplayReplayed)))
+
+	// Only the first read of a resuming request is the replay
+	replayCtx := withReplay(ctx)
+	events, err := collect(t, replayCtx, store, "s1", "a", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three"}, events)
+	_, err = collect(t, replayCtx, store, "s1", "a", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.StreamReplaysTotal.WithLabelValues(ReplayReplayed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.StreamReplayedEventsTotal))
+
+	_, err = collect(t, withReplay(ctx), store, "s1", "a", -1)
+	assert.ErrorIs(t, err, mcp.ErrEventsPurged)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.StreamReplaysTotal.WithLabelValues(ReplayPurged)))
+}
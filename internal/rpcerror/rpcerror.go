@@ -0,0 +1,33 @@
+// Package rpcerror builds JSON-RPC errors that MCP handlers and middleware
+// can return with their own code and data. The SDK sends such an error to
+// the client as is, rather than as an internal error, but doesn't export
+// its wire error type, so errors are taken from a decoded response.
+package rpcerror
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// CodeInvalidParams is the spec's code for invalid method parameters
+const CodeInvalidParams = -32602
+
+// New builds a JSON-RPC error with a code and message, carrying data
+// unless it is nil
+func New(code int, message string, data any) error {
+	wire := map[string]any{"code": code, "message": message}
+	if data != nil {
+		wire["data"] = data
+	}
+	raw, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "error": wire})
+	if err != nil {
+		return fmt.Errorf("json-rpc error %d: %s", code, message)
+	}
+	msg, err := jsonrpc.DecodeMessage(raw)
+	if err != nil {
+		return fmt.Errorf("json-rpc error %d: %s", code, message)
+	}
+	return msg.(*jsonrpc.Response).Error
+}
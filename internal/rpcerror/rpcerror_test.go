@@ -0,0 +1,47 @@
+package rpcerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int
+		message  string
+		data     any
+		expected string
+	}{
+		{
+			name:     "with data",
+			code:     -32000,
+			message:  "server overloaded",
+			data:     map[string]int{"level": 2},
+			expected: `{"code": -32000, "message": "server overloaded", "data": {"level": 2}}`,
+		},
+		{
+			name:     "without data",
+			code:     CodeInvalidParams,
+			message:  "invalid params",
+			expected: `{"code": -32602, "message": "invalid params"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.code, tt.message, tt.data)
+			raw, marshalErr := json.Marshal(err)
+			require.NoError(t, marshalErr)
+			assert.JSONEq(t, tt.expected, string(raw))
+
+			// Wrapping keeps the error the SDK sends as is
+			wrapped := fmt.Errorf("%w: more detail", err)
+			assert.ErrorIs(t, wrapped, err)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/logger"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/resume"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/tools"
+)
+
+// Benchmarks of one get_time call over each transport, end to end through
+// the MCP server, the tool and the time service. make bench-compare runs
+// them on the base branch and on HEAD, and fails on a regression
+
+// newBenchServer creates an MCP server with the core tools, on a frozen clock
+func newBenchServer() (*mcp.Server, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	service := timeservice.NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix"}, logger.Slog(zap.NewNop()),
+		timeservice.WithClock(timeservice.FixedClock{Time: time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC)}))
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "bench"}, nil)
+	tools.RegisterTimeTools(server, service, m, zap.NewNop())
+	return server, m
+}
+
+// benchCalls connects a client over transport and times get_time calls
+func benchCalls(b *testing.B, transport mcp.Transport) {
+	ctx := context.Background()
+	client := mcp.NewClient(&mcp.Implementation{Name: "bench", Version: "bench"}, nil)
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer session.Close()
+
+	params := &mcp.CallToolParams{Name: "get_time", Arguments: map[string]any{"timezone": "America/New_York"}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res, err := session.CallTool(ctx, params)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if res.IsError {
+			b.Fatalf("get_time failed: %v", res.Content)
+		}
+	}
+}
+
+// BenchmarkTransport_InMemory runs over newline-delimited JSON on a pipe,
+// as stdio does
+func BenchmarkTransport_InMemory(b *testing.B) {
+	server, _ := newBenchServer()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer session.Close()
+	benchCalls(b, clientTransport)
+}
+
+func BenchmarkTransport_Streamable(b *testing.B) {
+	httpServer := newBenchHTTPServer()
+	defer httpServer.Close()
+	benchCalls(b, &mcp.StreamableClientTransport{Endpoint: httpServer.URL + "/mcp", HTTPClient: httpServer.Client()})
+}
+
+func BenchmarkTransport_SSE(b *testing.B) {
+	httpServer := newBenchHTTPServer()
+	defer httpServer.Close()
+	benchCalls(b, &mcp.SSEClientTransport{Endpoint: httpServer.URL + "/sse", HTTPClient: httpServer.Client()})
+}
+
+// newStreams serves streamable sessions with their events kept in process
+func newStreams(server *mcp.Server, m *metrics.Metrics) *resume.Handler {
+	return resume.NewHandler(server, resume.NewStore(0, m), m, zap.NewNop())
+}
+
+// newBenchHTTPServer serves the MCP endpoints with the middleware of a
+// default deployment
+func newBenchHTTPServer() *httptest.Server {
+	server, m := newBenchServer()
+	cfg := &config.Config{Limits: config.LimitsConfig{MaxRequestBytes: 1 << 20}}
+	return httptest.NewServer(setupMainHandler(cfg, buildinfo.Info{}, server, m, nil, newStreams(server, m), new(atomic.Bool), zap.NewNop()))
+}
@@ -2,16 +2,25 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/chaos"
 	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/heartbeat"
 	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/resume"
 )
 
 // HTTPServer wraps HTTP server functionality
@@ -19,11 +28,17 @@ type HTTPServer struct {
 	Server        *http.Server
 	MetricsServer *http.Server
 	logger        *zap.Logger
+	ready         chan struct{}
+	paused        *atomic.Bool
+	healthURL     string // set once listening
 }
 
-// NewHTTPServer creates a new HTTP server with MCP endpoints
-func NewHTTPServer(cfg *config.Config, mcpServer *mcp.Server, metrics *metrics.Metrics, logger *zap.Logger) *HTTPServer {
-	mux := setupMainHandler(cfg, mcpServer, metrics, logger)
+// NewHTTPServer creates a new HTTP server with MCP endpoints.
+// The injector is optional and only set when chaos mode is enabled;
+// streams serves the streamable endpoints.
+func NewHTTPServer(cfg *config.Config, build buildinfo.Info, mcpServer *mcp.Server, metrics *metrics.Metrics, injector *chaos.Injector, streams *resume.Handler, logger *zap.Logger) *HTTPServer {
+	paused := &atomic.Bool{}
+	mux := setupMainHandler(cfg, build, mcpServer, metrics, injector, streams, paused, logger)
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -39,21 +54,37 @@ func NewHTTPServer(cfg *config.Config, mcpServer *mcp.Server, metrics *metrics.M
 		Server:        server,
 		MetricsServer: metricsServer,
 		logger:        logger,
+		ready:         make(chan struct{}),
+		paused:        paused,
 	}
 }
 
 // setupMainHandler configures the main HTTP handler with all endpoints
-func setupMainHandler(cfg *config.Config, mcpServer *mcp.Server, metrics *metrics.Metrics, logger *zap.Logger) *http.ServeMux {
+func setupMainHandler(cfg *config.Config, build buildinfo.Info, mcpServer *mcp.Server, metrics *metrics.Metrics, injector *chaos.Injector, streams *resume.Handler, paused *atomic.Bool, logger *zap.Logger) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Create MCP transport handlers
-	sseHandler := mcp.NewSSEHandler(func(r *http.Request) *mcp.Server {
+	var sseHandler http.Handler = mcp.NewSSEHandler(func(r *http.Request) *mcp.Server {
 		return mcpServer
 	}, nil)
+	sseHandler = heartbeat.Wrap(sseHandler, cfg.Server.Heartbeat, metrics, logger)
+	if injector != nil {
+		sseHandler = injector.WrapSSE(sseHandler)
+	}
 
-	streamableHandler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-		return mcpServer
-	}, nil)
+	// Streamable sessions are served by streams, over its store
+	var streamableHandler http.Handler = streams
+	sseHandler = withPause(withBodyLimit(sseHandler, cfg.Limits.MaxRequestBytes), paused)
+	streamableHandler = withPause(withBodyLimit(streamableHandler, cfg.Limits.MaxRequestBytes), paused)
+	if cfg.Server.Affinity.Enabled {
+		replica, err := affinityReplica(cfg.Server.Affinity)
+		if err != nil {
+			logger.Warn("Session affinity disabled", zap.Error(err))
+		} else {
+			sseHandler = withAffinity(sseHandler, cfg.Server.Affinity.Cookie, replica, metrics, logger)
+			streamableHandler = withAffinity(streamableHandler, cfg.Server.Affinity.Cookie, replica, metrics, logger)
+		}
+	}
 
 	// Register MCP endpoints with metrics
 	mux.Handle("/sse", withMetrics(sseHandler, metrics, logger, "sse"))
@@ -61,7 +92,9 @@ func setupMainHandler(cfg *config.Config, mcpServer *mcp.Server, metrics *metric
 	mux.Handle("/mcp", withMetrics(streamableHandler, metrics, logger, "streamable")) // Alias
 
 	// Register health check
-	mux.HandleFunc("/health", createHealthHandler(cfg))
+	healthHandler := createHealthHandler(cfg, build, paused)
+	mux.HandleFunc("/health", healthHandler)
+	mux.HandleFunc("/healthz", healthHandler) // Alias
 
 	// Register metrics endpoint if enabled on same port
 	if cfg.Metrics.Enabled && cfg.Metrics.Port == cfg.Server.Port {
@@ -82,13 +115,33 @@ func setupMetricsServer(cfg *config.Config, logger *zap.Logger) *http.Server {
 	}
 }
 
-// createHealthHandler creates the health check endpoint handler
-func createHealthHandler(cfg *config.Config) http.HandlerFunc {
+// healthResponse is the health check payload. Version is the configured
+// server.version; Build identifies the binary actually running
+type healthResponse struct {
+	Status    string         `json:"status"`
+	Service   string         `json:"service"`
+	Version   string         `json:"version"`
+	Timestamp string         `json:"timestamp"`
+	Build     buildinfo.Info `json:"build"`
+}
+
+// createHealthHandler creates the health check endpoint handler. A paused
+// server answers 503, so load balancers route around it
+func createHealthHandler(cfg *config.Config, build buildinfo.Info, paused *atomic.Bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		status, code := "healthy", http.StatusOK
+		if paused.Load() {
+			status, code = "paused", http.StatusServiceUnavailable
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `{"status":"healthy","service":"%s","version":"%s","timestamp":"%s"}`,
-			cfg.Server.Name, cfg.Server.Version, time.Now().UTC().Format(time.RFC3339))
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(healthResponse{
+			Status:    status,
+			Service:   cfg.Server.Name,
+			Version:   cfg.Server.Version,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Build:     build,
+		})
 	}
 }
 
@@ -109,9 +162,70 @@ func (s *HTTPServer) Start() error {
 	// Start main server
 	s.logger.Info("Starting MCP server",
 		zap.String("addr", s.Server.Addr),
-		zap.Strings("endpoints", []string{"/sse", "/streamable", "/mcp", "/health"}))
+		zap.Strings("endpoints", []string{"/sse", "/streamable", "/mcp", "/health", "/healthz"}))
+
+	listener, err := net.Listen("tcp", s.Server.Addr)
+	if err != nil {
+		return err
+	}
+	s.healthURL = "http://" + loopback(listener.Addr().(*net.TCPAddr)) + "/health"
+	close(s.ready)
+	return s.Server.Serve(listener)
+}
+
+// loopback returns the address to reach a listener on from this host. A
+// listener on every address, including Go's dual-stack [::], takes IPv4
+func loopback(addr *net.TCPAddr) string {
+	ip := addr.IP
+	if ip.IsUnspecified() {
+		ip = net.IPv4(127, 0, 0, 1)
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(addr.Port))
+}
+
+// CheckHealth asks the running server's own health endpoint, so it fails
+// when the server stops answering requests. A paused server is alive
+func (s *HTTPServer) CheckHealth(ctx context.Context) error {
+	select {
+	case <-s.ready:
+	default:
+		return fmt.Errorf("server is not listening yet")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.healthURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var health healthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("health check answered %s: %w", resp.Status, err)
+	}
+	if health.Status != "healthy" && health.Status != "paused" {
+		return fmt.Errorf("health check answered %s: %s", resp.Status, health.Status)
+	}
+	return nil
+}
 
-	return s.Server.ListenAndServe()
+// Ready is closed once the main server listens
+func (s *HTTPServer) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// SetPaused pauses or resumes the MCP endpoints. A paused server answers
+// them with 503 and keeps its connections, so it resumes at once
+func (s *HTTPServer) SetPaused(paused bool) {
+	if s.paused.Swap(paused) == paused {
+		return
+	}
+	if paused {
+		s.logger.Info("Paused MCP endpoints")
+		return
+	}
+	s.logger.Info("Resumed MCP endpoints")
 }
 
 // Shutdown gracefully shuts down both servers
@@ -136,6 +250,74 @@ func (s *HTTPServer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// withBodyLimit caps request bodies at max bytes; reading past it fails and
+// the transport answers with an error. Zero leaves bodies uncapped
+func withBodyLimit(handler http.Handler, max int64) http.Handler {
+	if max <= 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, max)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// affinityReplica returns the configured replica, or the hostname
+func affinityReplica(cfg config.AffinityConfig) (string, error) {
+	if cfg.Replica != "" {
+		return cfg.Replica, nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("server.affinity.replica is empty and the hostname is unknown: %w", err)
+	}
+	if !config.ReplicaPattern.MatchString(hostname) {
+		return "", fmt.Errorf("hostname %q cannot name the replica, set server.affinity.replica", hostname)
+	}
+	return hostname, nil
+}
+
+// withAffinity sets the affinity cookie naming this replica, which load
+// balancers hash or match on to keep a session where it was created. A
+// session request whose cookie names another replica was routed elsewhere,
+// typically because that replica went away. Streamable sessions are restored
+// here from the stream store when it is shared between the replicas; other
+// sessions answer 404, and the client starts a new session here
+func withAffinity(handler http.Handler, cookie, replica string, metrics *metrics.Metrics, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current, err := r.Cookie(cookie)
+		if err != nil || current.Value != replica {
+			if err == nil && r.Header.Get("Mcp-Session-Id") != "" {
+				metrics.RecordAffinityMisrouted()
+				logger.Debug("Session request for another replica",
+					zap.String("replica", current.Value),
+					zap.String("session_id", r.Header.Get("Mcp-Session-Id")))
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     cookie,
+				Value:    replica,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		w.Header().Set("Mcp-Replica", replica)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// withPause answers requests with 503 while the server is paused
+func withPause(handler http.Handler, paused *atomic.Bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if paused.Load() {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, "server paused", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
 // withMetrics wraps an HTTP handler with metrics collection
 func withMetrics(handler http.Handler, metrics *metrics.Metrics, logger *zap.Logger, transport string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -147,10 +329,13 @@ func withMetrics(handler http.Handler, metrics *metrics.Metrics, logger *zap.Log
 			zap.String("path", r.URL.Path),
 			zap.String("remote_addr", r.RemoteAddr))
 
-		// Set CORS headers for all transports
+		// Set CORS headers for all transports. Browser clients send the
+		// session and resume headers of the streamable transport, close
+		// sessions with DELETE, and read the session and replica headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id, Mcp-Replica")
 
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
@@ -192,3 +377,13 @@ func (w *responseWriterWrapper) WriteHeader(code int) {
 	w.statusCode = code
 	w.ResponseWriter.WriteHeader(code)
 }
+
+// Flush passes flushes through, so SSE events leave as they are written
+func (w *responseWriterWrapper) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *responseWriterWrapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+
+func newTestMetrics() *metrics.Metrics {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	return metrics.New()
+}
+
+// okHandler answers 200 after reading the whole body, and 413 when the body
+// is over its limit
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if _, err := io.ReadAll(r.Body); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+})
+
+// affinityCookie returns the affinity cookie a response sets, or nil
+func affinityCookie(resp *http.Response) *http.Cookie {
+	for _, c := range resp.Cookies() {
+		if c.Name == "replica" {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestWithAffinity(t *testing.T) {
+	m := newTestMetrics()
+	ts := httptest.NewServer(withAffinity(okHandler, "replica", "replica-a", m, zaptest.NewLogger(t)))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		cookie    string
+		session   string
+		setCookie bool
+		misrouted float64
+	}{
+		{name: "no cookie", setCookie: true},
+		{name: "new session", cookie: "replica-b", setCookie: true},
+		{name: "this replica", cookie: "replica-a", session: "s1"},
+		{name: "other replica", cookie: "replica-b", session: "s1", setCookie: true, misrouted: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("{}"))
+			require.NoError(t, err)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "replica", Value: tt.cookie})
+			}
+			if tt.session != "" {
+				req.Header.Set("Mcp-Session-Id", tt.session)
+			}
+			before := testutil.ToFloat64(m.AffinityMisroutedTotal)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "replica-a", resp.Header.Get("Mcp-Replica"))
+			cookie := affinityCookie(resp)
+			if tt.setCookie {
+				require.NotNil(t, cookie)
+				assert.Equal(t, "replica-a", cookie.Value)
+				assert.Equal(t, "/", cookie.Path)
+				assert.True(t, cookie.HttpOnly)
+			} else {
+				assert.Nil(t, cookie)
+			}
+			assert.Equal(t, tt.misrouted, testutil.ToFloat64(m.AffinityMisroutedTotal)-before)
+		})
+	}
+}
+
+func TestWithMetrics_CORS(t *testing.T) {
+	ts := httptest.NewServer(withMetrics(okHandler, newTestMetrics(), zaptest.NewLogger(t), "streamable"))
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodOptions, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "mcp-session-id, last-event-id")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST, DELETE, OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, Mcp-Session-Id, Mcp-Protocol-Version, Last-Event-ID", resp.Header.Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "Mcp-Session-Id, Mcp-Replica", resp.Header.Get("Access-Control-Expose-Headers"))
+}
+
+func TestWithBodyLimit(t *testing.T) {
+	limited := httptest.NewServer(withBodyLimit(okHandler, 8))
+	defer limited.Close()
+	unlimited := httptest.NewServer(withBodyLimit(okHandler, 0))
+	defer unlimited.Close()
+
+	tests := []struct {
+		name     string
+		url      string
+		body     string
+		expected int
+	}{
+		{name: "under the limit", url: limited.URL, body: "12345678", expected: http.StatusOK},
+		{name: "over the limit", url: limited.URL, body: "123456789", expected: http.StatusRequestEntityTooLarge},
+		{name: "no limit", url: unlimited.URL, body: strings.Repeat("x", 1<<16), expected: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(tt.url, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.expected, resp.StatusCode)
+		})
+	}
+}
+
+func TestWithPause(t *testing.T) {
+	paused := new(atomic.Bool)
+	ts := httptest.NewServer(withPause(okHandler, paused))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	paused.Store(true)
+	resp, err = http.Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+
+	paused.Store(false)
+	resp, err = http.Get(ts.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// TestMainHandler_Middleware checks the middleware wraps the MCP endpoints
+// of a deployment with affinity, a body limit and pausing
+func TestMainHandler_Middleware(t *testing.T) {
+	server, m := newBenchServer()
+	cfg := &config.Config{
+		Server: config.ServerConfig{Affinity: config.AffinityConfig{Enabled: true, Cookie: "replica", Replica: "replica-a"}},
+		Limits: config.LimitsConfig{MaxRequestBytes: 1024},
+	}
+	paused := new(atomic.Bool)
+	ts := httptest.NewServer(setupMainHandler(cfg, buildinfo.Info{}, server, m, nil, newStreams(server, m), paused, zap.NewNop()))
+	defer ts.Close()
+
+	post := func(body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := post(initializeRequest)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Mcp-Session-Id"))
+	assert.Equal(t, "replica-a", resp.Header.Get("Mcp-Replica"))
+	require.NotNil(t, affinityCookie(resp))
+	assert.Equal(t, "replica-a", affinityCookie(resp).Value)
+
+	// The transport can't read a body over the limit
+	resp = post(initializeRequest + strings.Repeat(" ", 1024))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	paused.Store(true)
+	resp = post(initializeRequest)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
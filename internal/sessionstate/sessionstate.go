@@ -0,0 +1,307 @@
+// Package sessionstate keeps state scoped to an MCP session, such as the
+// defaults a client negotiated at initialize. Entries expire after an idle
+// TTL, can be cleared by the client, and go with their session when it
+// closes, so thousands of short-lived agent sessions can't leak memory.
+// Expired and cleared entries are soft-deleted: they read as absent at
+// once, but can be restored until they are purged.
+package sessionstate
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Reasons entries are removed
+const (
+	ReasonTTL           = "ttl"
+	ReasonCleared       = "cleared"
+	ReasonSessionClosed = "session_closed"
+)
+
+// ClearInput represents input for clearing a session's state
+type ClearInput struct {
+	Keys []string `json:"keys,omitempty"` // defaults to every key
+	// Restore brings back entries cleared or expired instead, until purged
+	Restore bool `json:"restore,omitempty"`
+}
+
+// ClearResult represents a session's state after clearing or restoring
+type ClearResult struct {
+	Cleared  []string `json:"cleared"`
+	Restored []string `json:"restored"`
+	// Keys are the session's live keys afterwards
+	Keys []string `json:"keys"`
+	// RestorableUntil is when the cleared entries are purged
+	RestorableUntil string `json:"restorable_until,omitempty"`
+}
+
+// Store holds the state of each session, by key
+type Store struct {
+	cfg     config.SessionStateConfig
+	server  *mcp.Server
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu       sync.Mutex
+	live     int // live entries across sessions
+	sessions map[*mcp.ServerSession]map[string]*entry
+}
+
+// entry is one value. A soft-deleted entry keeps its value until it is
+// purged, cfg.PurgeAfter after deletion
+type entry struct {
+	value   any
+	touched time.Time
+	// deletedAt is zero while the entry is live
+	deletedAt time.Time
+}
+
+// New creates a store, with the config defaults for zero settings.
+// Sessions that server no longer has are dropped at each sweep; a nil
+// server keeps state until it expires
+func New(cfg config.SessionStateConfig, server *mcp.Server, metrics *metrics.Metrics, logger *zap.Logger) *Store {
+	if cfg.TTL <= 0 {
+		cfg.TTL = config.DefaultSessionStateTTL
+	}
+	if cfg.SweepInterval <= 0 {
+		cfg.SweepInterval = config.DefaultSessionStateSweepInterval
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = config.DefaultSessionStateMaxEntries
+	}
+	return &Store{
+		cfg:      cfg,
+		server:   server,
+		metrics:  metrics,
+		logger:   logger,
+		now:      time.Now,
+		sessions: make(map[*mcp.ServerSession]map[string]*entry),
+	}
+}
+
+// Set stores a value for a session, replacing a live or soft-deleted one.
+// A session holds at most cfg.MaxEntries entries, live or soft-deleted: a
+// new key at the cap purges the session's oldest soft-deleted entry, and
+// fails when all of them are live
+func (s *Store) Set(session *mcp.ServerSession, key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, ok := s.sessions[session]
+	if !ok {
+		entries = make(map[string]*entry)
+		s.sessions[session] = entries
+	}
+	e, ok := entries[key]
+	switch {
+	case ok && e.deletedAt.IsZero():
+	case liveCount(entries) >= s.cfg.MaxEntries:
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"session holds %d state entries, at most %d allowed; clear some with clear_session_state", s.cfg.MaxEntries, s.cfg.MaxEntries)
+	default:
+		if !ok && len(entries) >= s.cfg.MaxEntries {
+			purgeOldest(entries)
+			s.metrics.RecordSessionStatePurged(1)
+		}
+		s.live++
+	}
+	entries[key] = &entry{value: value, touched: s.now()}
+	s.metrics.SetSessionStateEntries(s.live)
+	return nil
+}
+
+// Get returns a session's live value for key, renewing its TTL
+func (s *Store) Get(session *mcp.ServerSession, key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.sessions[session][key]
+	if !ok || !e.deletedAt.IsZero() {
+		return nil, false
+	}
+	e.touched = s.now()
+	return e.value, true
+}
+
+// Keys lists a session's live keys, sorted
+func (s *Store) Keys(session *mcp.ServerSession) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key, e := range s.sessions[session] {
+		if e.deletedAt.IsZero() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Clear soft-deletes a session's live entries for keys, or all of them
+// when keys is empty, and returns the keys cleared, sorted
+func (s *Store) Clear(session *mcp.ServerSession, keys []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var cleared []string
+	for key, e := range s.sessions[session] {
+		if e.deletedAt.IsZero() && (len(keys) == 0 || slices.Contains(keys, key)) {
+			s.softDelete(e, now)
+			cleared = append(cleared, key)
+		}
+	}
+	sort.Strings(cleared)
+	s.metrics.RecordSessionStateRemoved(ReasonCleared, len(cleared))
+	s.metrics.SetSessionStateEntries(s.live)
+	return cleared
+}
+
+// Restore brings back a session's soft-deleted entries for keys, or all
+// of them when keys is empty, and returns the keys restored, sorted
+func (s *Store) Restore(session *mcp.ServerSession, keys []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entries := s.sessions[session]
+	var restored []string
+	for key, e := range entries {
+		if e.deletedAt.IsZero() || (len(keys) > 0 && !slices.Contains(keys, key)) {
+			continue
+		}
+		if liveCount(entries) >= s.cfg.MaxEntries {
+			break
+		}
+		e.deletedAt, e.touched = time.Time{}, now
+		s.live++
+		restored = append(restored, key)
+	}
+	sort.Strings(restored)
+	s.metrics.SetSessionStateEntries(s.live)
+	return restored
+}
+
+// ClearSession clears or restores a session's state as input asks
+func (s *Store) ClearSession(session *mcp.ServerSession, input ClearInput) ClearResult {
+	result := ClearResult{Cleared: []string{}, Restored: []string{}}
+	if input.Restore {
+		result.Restored = append(result.Restored, s.Restore(session, input.Keys)...)
+	} else {
+		result.Cleared = append(result.Cleared, s.Clear(session, input.Keys)...)
+		if len(result.Cleared) > 0 {
+			result.RestorableUntil = s.now().Add(s.cfg.PurgeAfter).UTC().Format(time.RFC3339)
+		}
+	}
+	result.Keys = append([]string{}, s.Keys(session)...)
+	return result
+}
+
+// Run sweeps the store every cfg.SweepInterval until ctx is done
+func (s *Store) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
+
+// Sweep drops the state of closed sessions, soft-deletes entries idle for
+// longer than cfg.TTL and purges entries soft-deleted cfg.PurgeAfter ago
+func (s *Store) Sweep() {
+	open := make(map[*mcp.ServerSession]bool)
+	if s.server != nil {
+		for session := range s.server.Sessions() {
+			open[session] = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	closed, expired, purged := 0, 0, 0
+	for session, entries := range s.sessions {
+		if s.server != nil && !open[session] {
+			n := liveCount(entries)
+			s.live -= n
+			closed += n
+			purged += len(entries) - n
+			delete(s.sessions, session)
+			continue
+		}
+		for key, e := range entries {
+			switch {
+			case e.deletedAt.IsZero() && now.Sub(e.touched) >= s.cfg.TTL:
+				s.softDelete(e, now)
+				expired++
+			case !e.deletedAt.IsZero() && now.Sub(e.deletedAt) >= s.cfg.PurgeAfter:
+				delete(entries, key)
+				purged++
+			}
+		}
+		if len(entries) == 0 {
+			delete(s.sessions, session)
+		}
+	}
+
+	s.metrics.RecordSessionStateRemoved(ReasonTTL, expired)
+	s.metrics.RecordSessionStateRemoved(ReasonSessionClosed, closed)
+	s.metrics.RecordSessionStatePurged(purged)
+	s.metrics.SetSessionStateEntries(s.live)
+	if expired+closed+purged > 0 {
+		s.logger.Debug("Swept session state",
+			zap.Int("expired", expired),
+			zap.Int("session_closed", closed),
+			zap.Int("purged", purged),
+			zap.Int("live", s.live))
+	}
+}
+
+// softDelete marks a live entry deleted. The caller holds s.mu
+func (s *Store) softDelete(e *entry, now time.Time) {
+	e.deletedAt = now
+	s.live--
+}
+
+// purgeOldest purges the entry soft-deleted longest ago, if any
+func purgeOldest(entries map[string]*entry) {
+	oldest := ""
+	for key, e := range entries {
+		if e.deletedAt.IsZero() {
+			continue
+		}
+		if oldest == "" || e.deletedAt.Before(entries[oldest].deletedAt) {
+			oldest = key
+		}
+	}
+	delete(entries, oldest)
+}
+
+// liveCount counts the entries not soft-deleted
+func liveCount(entries map[string]*entry) int {
+	n := 0
+	for _, e := range entries {
+		if e.deletedAt.IsZero() {
+			n++
+		}
+	}
+	return n
+}
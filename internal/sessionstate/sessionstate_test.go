@@ -0,0 +1,148 @@
+package sessionstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+var start = time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+// newTestStore creates a store over a server and a clock driven by hand
+func newTestStore(t *testing.T) (*Store, *mcp.Server, *time.Time, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	cfg := config.SessionStateConfig{TTL: time.Hour, PurgeAfter: 10 * time.Minute, SweepInterval: time.Minute, MaxEntries: 2}
+	store := New(cfg, server, m, zaptest.NewLogger(t))
+
+	now := start
+	store.now = func() time.Time { return now }
+	return store, server, &now, m
+}
+
+// connect opens a session on server
+func connect(t *testing.T, server *mcp.Server) *mcp.ServerSession {
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	session, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	client := mcp.NewClient(&mcp.Implementation{Name: "sessionstate-test", Version: "test"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { clientSession.Close() })
+	return session
+}
+
+func TestStore_SetGet(t *testing.T) {
+	store, server, _, m := newTestStore(t)
+	a, b := connect(t, server), connect(t, server)
+
+	require.NoError(t, store.Set(a, "format", "Unix"))
+	require.NoError(t, store.Set(a, "timezone", "Asia/Tokyo"))
+	require.NoError(t, store.Set(a, "format", "UnixMilli"), "replacing doesn't count against the cap")
+	err := store.Set(a, "locale", "pt-BR")
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+
+	value, ok := store.Get(a, "format")
+	require.True(t, ok)
+	assert.Equal(t, "UnixMilli", value)
+	_, ok = store.Get(b, "format")
+	assert.False(t, ok, "sessions don't share state")
+
+	assert.Equal(t, []string{"format", "timezone"}, store.Keys(a))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.SessionStateEntries))
+}
+
+func TestStore_ClearRestore(t *testing.T) {
+	store, server, now, m := newTestStore(t)
+	session := connect(t, server)
+	require.NoError(t, store.Set(session, "format", "Unix"))
+	require.NoError(t, store.Set(session, "timezone", "Asia/Tokyo"))
+
+	result := store.ClearSession(session, ClearInput{Keys: []string{"format", "missing"}})
+	assert.Equal(t, ClearResult{
+		Cleared:         []string{"format"},
+		Restored:        []string{},
+		Keys:            []string{"timezone"},
+		RestorableUntil: "2026-10-16T08:10:00Z",
+	}, result)
+	_, ok := store.Get(session, "format")
+	assert.False(t, ok)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionStateRemovedTotal.WithLabelValues(ReasonCleared)))
+
+	*now = now.Add(5 * time.Minute)
+	result = store.ClearSession(session, ClearInput{Restore: true})
+	assert.Equal(t, []string{"format"}, result.Restored)
+	assert.Equal(t, []string{"format", "timezone"}, result.Keys)
+
+	// Once purged, cleared state is gone for good
+	store.ClearSession(session, ClearInput{})
+	*now = now.Add(10 * time.Minute)
+	store.Sweep()
+	assert.Empty(t, store.ClearSession(session, ClearInput{Restore: true}).Restored)
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.SessionStatePurgedTotal))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.SessionStateEntries))
+}
+
+func TestStore_SoftDeletedCountAgainstCap(t *testing.T) {
+	store, server, now, m := newTestStore(t)
+	session := connect(t, server)
+
+	// Setting and clearing new keys purges the oldest cleared entry rather
+	// than growing the session past the cap
+	for _, key := range []string{"format", "timezone", "locale", "calendar"} {
+		require.NoError(t, store.Set(session, key, "value"))
+		store.Clear(session, []string{key})
+		*now = now.Add(time.Second)
+	}
+	assert.Len(t, store.sessions[session], 2)
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.SessionStatePurgedTotal))
+	assert.Equal(t, []string{"calendar", "locale"}, store.Restore(session, nil))
+
+	// Replacing a soft-deleted key purges nothing
+	store.Clear(session, []string{"locale"})
+	require.NoError(t, store.Set(session, "locale", "pt-BR"))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.SessionStatePurgedTotal))
+	assert.Len(t, store.sessions[session], 2)
+}
+
+func TestStore_Sweep(t *testing.T) {
+	store, server, now, m := newTestStore(t)
+	idle, active, closed := connect(t, server), connect(t, server), connect(t, server)
+	for _, session := range []*mcp.ServerSession{idle, active, closed} {
+		require.NoError(t, store.Set(session, "format", "Unix"))
+	}
+
+	*now = now.Add(59 * time.Minute)
+	_, ok := store.Get(active, "format")
+	require.True(t, ok)
+	require.NoError(t, closed.Close())
+	*now = now.Add(time.Minute)
+	store.Sweep()
+
+	_, ok = store.Get(idle, "format")
+	assert.False(t, ok, "idle state expires")
+	_, ok = store.Get(active, "format")
+	assert.True(t, ok, "reading renews the TTL")
+	assert.NotContains(t, store.sessions, closed)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionStateRemovedTotal.WithLabelValues(ReasonTTL)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionStateRemovedTotal.WithLabelValues(ReasonSessionClosed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SessionStateEntries))
+
+	// Expired state can be restored until it is purged
+	assert.Equal(t, []string{"format"}, store.Restore(idle, nil))
+}
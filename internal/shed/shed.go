@@ -0,0 +1,178 @@
+// Package shed rejects low-priority tool calls while the server is
+// overloaded, so the calls that matter most, get_time above all, keep being
+// answered. Pressure is read from the calls in flight and the recent p99
+// latency, and shed callers are told when to retry.
+package shed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/rpcerror"
+)
+
+// CodeOverloaded is the JSON-RPC error code of a shed call, from the range
+// the spec leaves to servers
+const CodeOverloaded = -32000
+
+// p99TTL is how long a p99 reading is reused, so a burst of calls doesn't
+// merge the latency histograms on each
+const p99TTL = time.Second
+
+// ranks orders priorities: a call is shed once the level reaches its rank,
+// so critical tools never are
+var ranks = map[string]int{
+	config.ShedPriorityLow:      1,
+	config.ShedPriorityNormal:   2,
+	config.ShedPriorityHigh:     3,
+	config.ShedPriorityCritical: 4,
+}
+
+// DefaultPriorities apply to tools shed.priorities doesn't name
+var DefaultPriorities = map[string]string{
+	"get_time": config.ShedPriorityCritical,
+}
+
+// LatencySource reports the recent p99 latency across tools, as the SLO
+// tracker does
+type LatencySource interface {
+	P99(since time.Duration) time.Duration
+}
+
+// Overload is sent to shed callers as the data of the error
+type Overload struct {
+	Tool              string `json:"tool"`
+	Priority          string `json:"priority"`
+	Level             int    `json:"level"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+// Shedder tracks the load on the server and sheds tool calls under pressure
+type Shedder struct {
+	cfg     config.ShedConfig
+	known   map[string]bool
+	latency LatencySource
+	now     func() time.Time
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+
+	inFlight atomic.Int64
+
+	mu    sync.Mutex
+	p99   time.Duration
+	p99At time.Time
+	level int // last published
+}
+
+// New creates a shedder. latency may be nil when max_p99 is off. Shed
+// calls of tools outside known, which clients can name freely, are counted
+// as metrics.ToolUnknown
+func New(cfg config.ShedConfig, known map[string]bool, latency LatencySource, metrics *metrics.Metrics, logger *zap.Logger) *Shedder {
+	return &Shedder{cfg: cfg, known: known, latency: latency, now: time.Now, metrics: metrics, logger: logger}
+}
+
+// PriorityOf returns a tool's priority
+func (s *Shedder) PriorityOf(tool string) string {
+	if priority, ok := s.cfg.Priorities[tool]; ok {
+		return priority
+	}
+	if priority, ok := DefaultPriorities[tool]; ok {
+		return priority
+	}
+	return s.cfg.DefaultPriority
+}
+
+// Level returns the shedding level for admitting one more call: 0 sheds
+// nothing, 1 low, 2 normal and 3 high priority tools. Pressure is the
+// larger of the calls in flight over max_in_flight and the recent p99 over
+// max_p99; the level rises past 1, 1.5 and 2
+func (s *Shedder) Level() int {
+	pressure := 0.0
+	if s.cfg.MaxInFlight > 0 {
+		pressure = float64(s.inFlight.Load()+1) / float64(s.cfg.MaxInFlight)
+	}
+	if s.cfg.MaxP99 > 0 && s.latency != nil {
+		pressure = max(pressure, float64(s.recentP99())/float64(s.cfg.MaxP99))
+	}
+	switch {
+	case pressure > 2:
+		return 3
+	case pressure > 1.5:
+		return 2
+	case pressure > 1:
+		return 1
+	}
+	return 0
+}
+
+// recentP99 returns the p99 over p99_window, read at most once per p99TTL
+func (s *Shedder) recentP99() time.Duration {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.p99At.IsZero() || now.Sub(s.p99At) >= p99TTL {
+		s.p99, s.p99At = s.latency.P99(s.cfg.P99Window), now
+	}
+	return s.p99
+}
+
+// publish exports the level and logs when it changes
+func (s *Shedder) publish(level int) {
+	s.mu.Lock()
+	previous := s.level
+	s.level = level
+	p99 := s.p99
+	s.mu.Unlock()
+	if level == previous {
+		return
+	}
+	s.metrics.SetShedLevel(level)
+	fields := []zap.Field{zap.Int("level", level), zap.Int64("in_flight", s.inFlight.Load()), zap.Duration("p99", p99)}
+	if level > previous {
+		s.logger.Warn("Load shedding raised", fields...)
+		return
+	}
+	s.logger.Info("Load shedding lowered", fields...)
+}
+
+// Middleware returns an MCP receiving middleware that rejects tool calls
+// whose priority the current level sheds, and counts the others in flight
+func (s *Shedder) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+			tool := callReq.Params.Name
+			priority := s.PriorityOf(tool)
+
+			level := s.Level()
+			s.publish(level)
+			if level >= ranks[priority] {
+				// Back off further the harder the server is pressed
+				retryAfter := s.cfg.RetryAfter * time.Duration(level)
+				s.metrics.RecordShedCall(metrics.ToolLabel(s.known, tool), priority)
+				s.logger.Debug("Shed tool call",
+					zap.String("tool", tool),
+					zap.String("priority", priority),
+					zap.Int("level", level))
+				return nil, rpcerror.New(CodeOverloaded,
+					fmt.Sprintf("server overloaded, shedding %s priority tools; retry %s after %s", priority, tool, retryAfter),
+					Overload{Tool: tool, Priority: priority, Level: level, RetryAfterSeconds: int(retryAfter / time.Second)})
+			}
+
+			s.inFlight.Add(1)
+			defer s.inFlight.Add(-1)
+			return next(ctx, method, req)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package shed
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+var testShed = config.ShedConfig{
+	Enabled:         true,
+	MaxInFlight:     4,
+	MaxP99:          100 * time.Millisecond,
+	P99Window:       time.Minute,
+	RetryAfter:      5 * time.Second,
+	DefaultPriority: config.ShedPriorityNormal,
+	Priorities:      map[string]string{"get_holidays": config.ShedPriorityLow, "convert_time": config.ShedPriorityHigh},
+}
+
+// fixedLatency is a latency source with a settable p99
+type fixedLatency struct {
+	p99   time.Duration
+	reads int
+}
+
+func (f *fixedLatency) P99(since time.Duration) time.Duration {
+	f.reads++
+	return f.p99
+}
+
+func newTestShedder(t *testing.T, cfg config.ShedConfig, latency LatencySource) (*Shedder, *metrics.Metrics) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	known := map[string]bool{"get_time": true, "convert_time": true, "parse_time": true, "get_holidays": true}
+	return New(cfg, known, latency, m, zaptest.NewLogger(t)), m
+}
+
+func TestShedder_PriorityOf(t *testing.T) {
+	shedder, _ := newTestShedder(t, testShed, nil)
+	assert.Equal(t, config.ShedPriorityCritical, shedder.PriorityOf("get_time"))
+	assert.Equal(t, config.ShedPriorityLow, shedder.PriorityOf("get_holidays"))
+	assert.Equal(t, config.ShedPriorityNormal, shedder.PriorityOf("parse_time"))
+
+	cfg := testShed
+	cfg.Priorities = map[string]string{"get_time": config.ShedPriorityHigh}
+	shedder, _ = newTestShedder(t, cfg, nil)
+	assert.Equal(t, config.ShedPriorityHigh, shedder.PriorityOf("get_time"), "configured priorities win over the defaults")
+}
+
+func TestShedder_LevelFromLatency(t *testing.T) {
+	latency := &fixedLatency{}
+	shedder, _ := newTestShedder(t, testShed, latency)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	shedder.now = func() time.Time { return now }
+
+	tests := []struct {
+		p99   time.Duration
+		level int
+	}{
+		{p99: 50 * time.Millisecond, level: 0},
+		{p99: 100 * time.Millisecond, level: 0},
+		{p99: 120 * time.Millisecond, level: 1},
+		{p99: 160 * time.Millisecond, level: 2},
+		{p99: time.Second, level: 3},
+	}
+	for _, tt := range tests {
+		latency.p99 = tt.p99
+		now = now.Add(p99TTL)
+		assert.Equal(t, tt.level, shedder.Level(), "p99 %s", tt.p99)
+	}
+
+	// Readings are reused within p99TTL
+	reads := latency.reads
+	latency.p99 = 0
+	assert.Equal(t, 3, shedder.Level())
+	assert.Equal(t, reads, latency.reads)
+}
+
+func TestShedder_Middleware(t *testing.T) {
+	shedder, m := newTestShedder(t, testShed, nil)
+	handler := shedder.Middleware()
+
+	// Hold calls open to build up calls in flight
+	release := make(chan struct{})
+	var started, done sync.WaitGroup
+	blocking := handler(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		started.Done()
+		<-release
+		return &mcp.CallToolResult{}, nil
+	})
+	call := func(h mcp.MethodHandler, tool string) error {
+		_, err := h(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: tool}})
+		return err
+	}
+	for i := 0; i < 6; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			assert.NoError(t, call(blocking, "get_time"))
+		}()
+	}
+	started.Wait()
+
+	// Six in flight of four: one more is 1.75 times the limit
+	quick := handler(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	assert.Equal(t, 2, shedder.Level())
+	assert.NoError(t, call(quick, "get_time"), "critical tools are never shed")
+	assert.NoError(t, call(quick, "convert_time"))
+
+	err := call(quick, "parse_time")
+	require.Error(t, err)
+	raw, marshalErr := json.Marshal(err)
+	require.NoError(t, marshalErr)
+	assert.JSONEq(t, `{
+		"code": -32000,
+		"message": "server overloaded, shedding normal priority tools; retry parse_time after 10s",
+		"data": {"tool": "parse_time", "priority": "normal", "level": 2, "retry_after_seconds": 10}
+	}`, string(raw))
+	assert.Error(t, call(quick, "get_holidays"))
+	// Names the server doesn't serve share one label
+	assert.Error(t, call(quick, "no_such_tool"))
+	assert.Error(t, call(quick, "another_one"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ShedCallsTotal.WithLabelValues("parse_time", config.ShedPriorityNormal)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.ShedCallsTotal.WithLabelValues(metrics.ToolUnknown, config.ShedPriorityNormal)))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.ShedCallsTotal))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ShedCallsTotal.WithLabelValues("get_holidays", config.ShedPriorityLow)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.ShedLevel))
+
+	close(release)
+	done.Wait()
+	assert.NoError(t, call(quick, "get_holidays"), "shedding stops with the pressure")
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.ShedLevel))
+
+	_, err = handler(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return nil, nil
+	})(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,340 @@
+// Package slo tracks per-tool latency objectives from the server's own
+// measurements: rolling p95 and p99 latencies, error budget burn rates and
+// calls in flight, with an optional webhook alert when a tool burns its
+// budget too fast. Operators get SLO monitoring without recording rules.
+package slo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// slots divides the window; a call leaves the window with its slot, so the
+// window is accurate to a sixtieth of its length
+const slots = 60
+
+// bucketBounds are the upper bounds of the latency buckets, 20% apart from
+// 50µs to past two minutes. Percentiles are interpolated within a bucket
+var bucketBounds = func() []time.Duration {
+	var bounds []time.Duration
+	for bound := 50 * time.Microsecond; bound < 3*time.Minute; bound = bound * 6 / 5 {
+		bounds = append(bounds, bound)
+	}
+	return bounds
+}()
+
+// slot holds the calls of one slice of the window
+type slot struct {
+	index   int64 // which slice of time the counts belong to
+	calls   int
+	bad     int
+	buckets []int // one more than bucketBounds, for slower calls
+}
+
+// toolWindow is the rolling window of one tool
+type toolWindow struct {
+	slots [slots]slot
+}
+
+// Status is a tool's standing against its objective over the window
+type Status struct {
+	Tool      string        `json:"tool"`
+	Calls     int           `json:"calls"`
+	P95       time.Duration `json:"-"`
+	P99       time.Duration `json:"-"`
+	Latency   time.Duration `json:"-"` // the objective's target
+	Objective float64       `json:"objective"`
+	// BadRatio is the share of calls that failed or took longer than Latency
+	BadRatio float64 `json:"bad_ratio"`
+	// BurnRate is BadRatio over the error budget, 1 - Objective; at 1 the
+	// budget lasts exactly the SLO period
+	BurnRate float64 `json:"burn_rate"`
+	// Judged is set once the window holds min_calls calls
+	Judged bool `json:"-"`
+}
+
+// Alert is the JSON body posted to the alert webhook
+type Alert struct {
+	Tool                 string  `json:"tool"`
+	Window               string  `json:"window"`
+	Calls                int     `json:"calls"`
+	P95Seconds           float64 `json:"p95_seconds"`
+	P99Seconds           float64 `json:"p99_seconds"`
+	LatencyTargetSeconds float64 `json:"latency_target_seconds"`
+	Objective            float64 `json:"objective"`
+	BadRatio             float64 `json:"bad_ratio"`
+	BurnRate             float64 `json:"burn_rate"`
+	AlertBurnRate        float64 `json:"alert_burn_rate"`
+	At                   string  `json:"at"`
+}
+
+// Tracker measures tool calls and evaluates them against their objectives
+type Tracker struct {
+	cfg       config.SLOConfig
+	known     map[string]bool
+	slotWidth time.Duration
+	now       func() time.Time
+	client    *http.Client
+	userAgent string
+	metrics   *metrics.Metrics
+	logger    *zap.Logger
+
+	mu        sync.Mutex
+	tools     map[string]*toolWindow
+	lastAlert map[string]time.Time
+}
+
+// New creates a tracker for the configured objectives. Calls of tools
+// outside known, which clients can name freely, are tracked together as
+// metrics.ToolUnknown
+func New(cfg config.SLOConfig, known map[string]bool, version string, metrics *metrics.Metrics, logger *zap.Logger) *Tracker {
+	return &Tracker{
+		cfg:       cfg,
+		known:     known,
+		slotWidth: cfg.Window / slots,
+		now:       time.Now,
+		client:    &http.Client{Timeout: cfg.AlertTimeout},
+		userAgent: "mcp-server-time/" + version,
+		metrics:   metrics,
+		logger:    logger,
+		tools:     make(map[string]*toolWindow),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// Middleware returns an MCP receiving middleware that times every tool
+// call and counts it in flight while it runs. A call fails the objective
+// when it is slower than the target or ends in a protocol error or panic;
+// tool results flagged as errors are the caller's mistakes and count as good.
+func (t *Tracker) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+			tool := metrics.ToolLabel(t.known, callReq.Params.Name)
+
+			t.metrics.RecordToolCallStart(tool)
+			start := t.now()
+			failed := true
+			defer func() {
+				t.metrics.RecordToolCallEnd(tool)
+				t.Observe(tool, t.now().Sub(start), failed)
+			}()
+
+			res, err := next(ctx, method, req)
+			failed = err != nil
+			return res, err
+		}
+	}
+}
+
+// Observe records one call of a tool
+func (t *Tracker) Observe(tool string, latency time.Duration, failed bool) {
+	bad := failed || latency > t.cfg.ObjectiveFor(tool).Latency
+	index := t.now().UnixNano() / int64(t.slotWidth)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	window, ok := t.tools[tool]
+	if !ok {
+		window = &toolWindow{}
+		t.tools[tool] = window
+	}
+	s := &window.slots[index%slots]
+	if s.index != index || s.buckets == nil {
+		*s = slot{index: index, buckets: make([]int, len(bucketBounds)+1)}
+	}
+	s.calls++
+	if bad {
+		s.bad++
+	}
+	s.buckets[sort.Search(len(bucketBounds), func(i int) bool { return bucketBounds[i] >= latency })]++
+}
+
+// Statuses evaluates every tool called within the window, sorted by name.
+// A tool idle for the whole window is reported once with no calls
+func (t *Tracker) Statuses() []Status {
+	current := t.now().UnixNano() / int64(t.slotWidth)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	statuses := make([]Status, 0, len(t.tools))
+	for tool, window := range t.tools {
+		objective := t.cfg.ObjectiveFor(tool)
+		status := Status{Tool: tool, Latency: objective.Latency, Objective: objective.Objective}
+		buckets := make([]int, len(bucketBounds)+1)
+		var bad int
+		status.Calls, bad = window.merge(buckets, current-slots)
+		if status.Calls == 0 {
+			// Report the idle tool once more, so its gauges fall to zero,
+			// and free its place
+			delete(t.tools, tool)
+			statuses = append(statuses, status)
+			continue
+		}
+		status.P95 = percentile(buckets, status.Calls, 0.95)
+		status.P99 = percentile(buckets, status.Calls, 0.99)
+		status.BadRatio = float64(bad) / float64(status.Calls)
+		status.BurnRate = status.BadRatio / (1 - objective.Objective)
+		status.Judged = status.Calls >= t.cfg.MinCalls
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(a, b int) bool { return statuses[a].Tool < statuses[b].Tool })
+	return statuses
+}
+
+// P99 estimates the p99 latency of all tools together over the last
+// since, rounded up to the next slot; 0 when there were no calls. Load
+// shedding reads it, so it reacts faster than the window
+func (t *Tracker) P99(since time.Duration) time.Duration {
+	current := t.now().UnixNano() / int64(t.slotWidth)
+	recent := min(int64((since+t.slotWidth-1)/t.slotWidth), slots-1)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buckets := make([]int, len(bucketBounds)+1)
+	calls := 0
+	for _, window := range t.tools {
+		n, _ := window.merge(buckets, current-recent-1)
+		calls += n
+	}
+	if calls == 0 {
+		return 0
+	}
+	return percentile(buckets, calls, 0.99)
+}
+
+// merge adds the slots after index after into buckets, returning their
+// calls and bad calls
+func (w *toolWindow) merge(buckets []int, after int64) (calls, bad int) {
+	for _, s := range w.slots {
+		if s.buckets == nil || s.index <= after {
+			continue
+		}
+		calls += s.calls
+		bad += s.bad
+		for i, n := range s.buckets {
+			buckets[i] += n
+		}
+	}
+	return calls, bad
+}
+
+// percentile estimates the q-th latency percentile of calls counted into
+// buckets, interpolating within the bucket the rank falls in
+func percentile(buckets []int, calls int, q float64) time.Duration {
+	rank := int(math.Ceil(q * float64(calls)))
+	seen := 0
+	for i, n := range buckets {
+		if seen+n < rank {
+			seen += n
+			continue
+		}
+		if i == len(bucketBounds) {
+			return bucketBounds[i-1]
+		}
+		lower := time.Duration(0)
+		if i > 0 {
+			lower = bucketBounds[i-1]
+		}
+		return lower + time.Duration(float64(bucketBounds[i]-lower)*float64(rank-seen)/float64(n))
+	}
+	return 0
+}
+
+// Run evaluates the objectives every interval until ctx is done
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Evaluate(ctx)
+		}
+	}
+}
+
+// Evaluate publishes every tool's percentiles and burn rate, and alerts on
+// the judged tools burning their budget at alert_burn_rate or faster
+func (t *Tracker) Evaluate(ctx context.Context) {
+	for _, status := range t.Statuses() {
+		t.metrics.RecordSLO(status.Tool, status.P95, status.P99, status.BurnRate)
+		if !status.Judged || t.cfg.AlertURL == "" || status.BurnRate < t.cfg.AlertBurnRate {
+			continue
+		}
+
+		now := t.now()
+		t.mu.Lock()
+		last, alerted := t.lastAlert[status.Tool]
+		if alerted && now.Sub(last) < t.cfg.AlertCooldown {
+			t.mu.Unlock()
+			continue
+		}
+		t.lastAlert[status.Tool] = now
+		t.mu.Unlock()
+
+		t.logger.Warn("SLO burn rate over the alert threshold",
+			zap.String("tool", status.Tool),
+			zap.Float64("burn_rate", status.BurnRate),
+			zap.Duration("p99", status.P99),
+			zap.Int("calls", status.Calls))
+		if err := t.alert(ctx, status, now); err != nil {
+			t.metrics.RecordSLOAlert(status.Tool, metrics.StatusError)
+			t.logger.Warn("SLO alert failed", zap.String("tool", status.Tool), zap.Error(err))
+			continue
+		}
+		t.metrics.RecordSLOAlert(status.Tool, metrics.StatusSuccess)
+	}
+}
+
+// alert posts a violated objective to the alert webhook
+func (t *Tracker) alert(ctx context.Context, status Status, at time.Time) error {
+	body, err := json.Marshal(Alert{
+		Tool:                 status.Tool,
+		Window:               t.cfg.Window.String(),
+		Calls:                status.Calls,
+		P95Seconds:           status.P95.Seconds(),
+		P99Seconds:           status.P99.Seconds(),
+		LatencyTargetSeconds: status.Latency.Seconds(),
+		Objective:            status.Objective,
+		BadRatio:             status.BadRatio,
+		BurnRate:             status.BurnRate,
+		AlertBurnRate:        t.cfg.AlertBurnRate,
+		At:                   at.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.AlertURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", t.userAgent)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+var testSLO = config.SLOConfig{
+	Enabled:       true,
+	Window:        time.Hour,
+	Interval:      time.Minute,
+	MinCalls:      10,
+	Default:       config.SLOObjectiveConfig{Latency: 100 * time.Millisecond, Objective: 0.9},
+	Tools:         map[string]config.SLOObjectiveConfig{"sync_holidays": {Latency: 2 * time.Second, Objective: 0.5}},
+	AlertBurnRate: 2,
+	AlertTimeout:  time.Second,
+	AlertCooldown: time.Hour,
+}
+
+// newTestTracker returns a tracker on a clock the test moves
+func newTestTracker(t *testing.T, cfg config.SLOConfig) (*Tracker, *metrics.Metrics, *time.Time) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	tracker := New(cfg, map[string]bool{"get_time": true, "sync_holidays": true}, "test", m, zaptest.NewLogger(t))
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	return tracker, m, &now
+}
+
+func TestTracker_Statuses(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, testSLO)
+	for i := 1; i <= 100; i++ {
+		tracker.Observe("get_time", time.Duration(i)*time.Millisecond, false)
+	}
+	tracker.Observe("sync_holidays", time.Second, false)
+
+	statuses := tracker.Statuses()
+	require.Len(t, statuses, 2)
+	get := statuses[0]
+	assert.Equal(t, "get_time", get.Tool)
+	assert.Equal(t, 100, get.Calls)
+	// Buckets are 20% wide, so percentiles are good to that
+	assert.InEpsilon(t, 95*time.Millisecond, get.P95, 0.2)
+	assert.InEpsilon(t, 99*time.Millisecond, get.P99, 0.2)
+	assert.Equal(t, 0.0, get.BadRatio)
+	assert.True(t, get.Judged)
+
+	sync := statuses[1]
+	assert.Equal(t, "sync_holidays", sync.Tool)
+	assert.Equal(t, 2*time.Second, sync.Latency, "per-tool objectives override the default")
+	assert.Equal(t, 0.0, sync.BadRatio)
+	assert.False(t, sync.Judged, "one call is fewer than min_calls")
+}
+
+func TestTracker_BurnRate(t *testing.T) {
+	tracker, _, _ := newTestTracker(t, testSLO)
+	for i := 0; i < 16; i++ {
+		tracker.Observe("get_time", time.Millisecond, false)
+	}
+	tracker.Observe("get_time", time.Second, false)
+	tracker.Observe("get_time", time.Second, false)
+	tracker.Observe("get_time", time.Millisecond, true)
+	tracker.Observe("get_time", time.Millisecond, true)
+
+	status := tracker.Statuses()[0]
+	assert.Equal(t, 20, status.Calls)
+	assert.InDelta(t, 0.2, status.BadRatio, 1e-9)
+	// A fifth of calls bad against a budget of a tenth burns it twice as fast
+	assert.InDelta(t, 2, status.BurnRate, 1e-9)
+}
+
+func TestTracker_Window(t *testing.T) {
+	tracker, _, now := newTestTracker(t, testSLO)
+	tracker.Observe("get_time", time.Millisecond, false)
+	*now = now.Add(30 * time.Minute)
+	tracker.Observe("get_time", time.Millisecond, false)
+	assert.Equal(t, 2, tracker.Statuses()[0].Calls)
+
+	*now = now.Add(45 * time.Minute)
+	assert.Equal(t, 1, tracker.Statuses()[0].Calls, "the first call has left the window")
+
+	*now = now.Add(time.Hour)
+	idle := tracker.Statuses()
+	require.Len(t, idle, 1)
+	assert.Equal(t, 0, idle[0].Calls)
+	assert.Equal(t, 0.0, idle[0].BurnRate)
+	assert.Empty(t, tracker.Statuses(), "an idle tool is reported once")
+}
+
+func TestTracker_P99(t *testing.T) {
+	tracker, _, now := newTestTracker(t, testSLO)
+	assert.Equal(t, time.Duration(0), tracker.P99(time.Minute))
+
+	for i := 0; i < 100; i++ {
+		tracker.Observe("get_holidays", 2*time.Second, false)
+	}
+	*now = now.Add(10 * time.Minute)
+	for i := 0; i < 50; i++ {
+		tracker.Observe("get_time", time.Millisecond, false)
+		tracker.Observe("parse_time", time.Millisecond, false)
+	}
+	// Only the recent calls count, across tools
+	assert.InEpsilon(t, time.Millisecond, tracker.P99(time.Minute), 0.2)
+	assert.InEpsilon(t, 2*time.Second, tracker.P99(15*time.Minute), 0.2)
+}
+
+func TestTracker_Alert(t *testing.T) {
+	var alerts []Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "mcp-server-time/test", r.Header.Get("User-Agent"))
+		var alert Alert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts = append(alerts, alert)
+	}))
+	defer server.Close()
+
+	cfg := testSLO
+	cfg.AlertURL = server.URL
+	tracker, m, now := newTestTracker(t, cfg)
+	for i := 0; i < 10; i++ {
+		tracker.Observe("get_time", time.Second, false)
+		tracker.Observe("parse_time", time.Millisecond, false)
+	}
+
+	tracker.Evaluate(context.Background())
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "get_time", alerts[0].Tool)
+	assert.Equal(t, 10, alerts[0].Calls)
+	assert.Equal(t, 1.0, alerts[0].BadRatio)
+	assert.InDelta(t, 10, alerts[0].BurnRate, 1e-9)
+	assert.Equal(t, 0.1, alerts[0].LatencyTargetSeconds)
+	assert.Equal(t, "1h0m0s", alerts[0].Window)
+	assert.InDelta(t, 10, testutil.ToFloat64(m.SLOBurnRate.WithLabelValues("get_time")), 1e-9)
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.SLOBurnRate.WithLabelValues("parse_time")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SLOAlertsTotal.WithLabelValues("get_time", metrics.StatusSuccess)))
+
+	// Within the cooldown the tool is not alerted on again
+	*now = now.Add(time.Minute)
+	tracker.Evaluate(context.Background())
+	assert.Len(t, alerts, 1)
+
+	*now = now.Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		tracker.Observe("get_time", time.Second, false)
+	}
+	tracker.Evaluate(context.Background())
+	assert.Len(t, alerts, 2)
+}
+
+func TestTracker_AlertFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cfg := testSLO
+	cfg.AlertURL = server.URL
+	tracker, m, _ := newTestTracker(t, cfg)
+	for i := 0; i < 10; i++ {
+		tracker.Observe("get_time", time.Millisecond, true)
+	}
+	tracker.Evaluate(context.Background())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.SLOAlertsTotal.WithLabelValues("get_time", metrics.StatusError)))
+}
+
+func TestTracker_Middleware(t *testing.T) {
+	tracker, m, now := newTestTracker(t, testSLO)
+	call := func(next mcp.MethodHandler) {
+		handler := tracker.Middleware()(next)
+		defer func() { _ = recover() }()
+		_, _ = handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get_time"}})
+	}
+
+	call(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.ToolCallsInFlight.WithLabelValues("get_time")))
+		*now = now.Add(10 * time.Millisecond)
+		return &mcp.CallToolResult{IsError: true}, nil
+	})
+	call(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return nil, errors.New("boom")
+	})
+	call(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		panic("boom")
+	})
+	call(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		*now = now.Add(time.Second)
+		return &mcp.CallToolResult{}, nil
+	})
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.ToolCallsInFlight.WithLabelValues("get_time")))
+	status := tracker.Statuses()[0]
+	assert.Equal(t, 4, status.Calls)
+	// The error, the panic and the slow call are bad; a tool error result is not
+	assert.Equal(t, 0.75, status.BadRatio)
+
+	_, err := tracker.Middleware()(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return nil, nil
+	})(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, tracker.Statuses(), 1, "only tool calls are tracked")
+}
+
+// TestTracker_Middleware_UnknownTools checks calls of tools the server
+// doesn't serve share one label, however many names clients make up
+func TestTracker_Middleware_UnknownTools(t *testing.T) {
+	tracker, m, _ := newTestTracker(t, testSLO)
+	handler := tracker.Middleware()(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.ToolCallsInFlight.WithLabelValues(metrics.ToolUnknown)))
+		return nil, errors.New("unknown tool")
+	})
+	for _, name := range []string{"no_such_tool", "another_one"} {
+		_, _ = handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name}})
+	}
+
+	assert.Equal(t, 1, testutil.CollectAndCount(m.ToolCallsInFlight))
+	statuses := tracker.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, metrics.ToolUnknown, statuses[0].Tool)
+	assert.Equal(t, 2, statuses[0].Calls)
+}
@@ -0,0 +1,193 @@
+package time
+
+import (
+	"strings"
+	"time"
+)
+
+// abbreviationScanStart precedes every transition in tzdata, so the scan
+// starts in each zone's earliest recorded offset (usually LMT)
+var abbreviationScanStart = time.Date(1800, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// abbreviationEraGap is the longest pause between two uses of an
+// abbreviation that still counts as one era, so seasonal DST years merge
+const abbreviationEraGap = 366 * 24 * time.Hour
+
+// ZoneAbbreviationsInput represents input for listing a zone's abbreviations
+type ZoneAbbreviationsInput struct {
+	Timezone string `json:"timezone"`
+	// TZData names a configured tzdata source; defaults to "default"
+	TZData string `json:"tzdata,omitempty"`
+	// Abbreviation keeps only the entries for this abbreviation, case-insensitively
+	Abbreviation string `json:"abbreviation,omitempty"`
+}
+
+// ZoneAbbreviationsResult lists the abbreviations a zone has used, in order
+// of first use
+type ZoneAbbreviationsResult struct {
+	Timezone      string             `json:"timezone"`
+	TZData        string             `json:"tzdata"`
+	Current       string             `json:"current"`
+	Abbreviations []ZoneAbbreviation `json:"abbreviations"`
+}
+
+// ZoneAbbreviation is one abbreviation at one offset. An abbreviation used
+// with different offsets over time, such as Lisbon's WET before and after
+// it changed rules, keeps one entry per offset
+type ZoneAbbreviation struct {
+	Abbreviation  string `json:"abbreviation"`
+	Offset        string `json:"offset"`
+	OffsetSeconds int    `json:"offset_seconds"`
+	DST           bool   `json:"dst"`
+	// Numeric marks tzdata's "+03" style placeholders for zones without an
+	// abbreviation in common use
+	Numeric bool              `json:"numeric"`
+	Eras    []AbbreviationEra `json:"eras"`
+}
+
+// AbbreviationEra is a stretch of years an abbreviation was in use. Periods
+// counts the separate spells within it, e.g. one per summer for DST. Start
+// is empty for a zone's earliest offset, which tzdata has no start for, and
+// End is empty while the era is ongoing
+type AbbreviationEra struct {
+	Start   string `json:"start,omitempty"`
+	End     string `json:"end,omitempty"`
+	Periods int    `json:"periods"`
+	Ongoing bool   `json:"ongoing"`
+}
+
+// abbreviationState is what an abbreviation entry is keyed by
+type abbreviationState struct {
+	name string
+	zoneState
+}
+
+// abbreviationStateAt returns the abbreviation in effect at t
+func abbreviationStateAt(t time.Time, loc *time.Location) abbreviationState {
+	t = t.In(loc)
+	name, offset := t.Zone()
+	return abbreviationState{name: name, zoneState: zoneState{offset: offset, dst: t.IsDST()}}
+}
+
+// abbreviationSpan is one uninterrupted use of an abbreviation
+type abbreviationSpan struct {
+	state      abbreviationState
+	start, end time.Time
+}
+
+// GetZoneAbbreviations lists the abbreviations a zone has used since its
+// earliest tzdata record, with the eras each was in use
+func (s *timeService) GetZoneAbbreviations(input ZoneAbbreviationsInput) (ZoneAbbreviationsResult, error) {
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	source := defaultString(input.TZData, DefaultTZDataSource)
+
+	loc, err := s.loadLocationFrom(source, timezone)
+	if err != nil {
+		return ZoneAbbreviationsResult{}, err
+	}
+
+	now := s.clock.Now()
+	// Scan a year ahead so announced changes and the coming DST season show
+	end := now.AddDate(1, 0, 0)
+	spans := abbreviationSpans(loc, abbreviationScanStart, end)
+
+	result := ZoneAbbreviationsResult{
+		Timezone:      timezone,
+		TZData:        source,
+		Current:       abbreviationStateAt(now, loc).name,
+		Abbreviations: []ZoneAbbreviation{},
+	}
+
+	entries := make(map[abbreviationState]int)
+	lastEnd := make(map[abbreviationState]time.Time)
+	for i, span := range spans {
+		state := span.state
+		if input.Abbreviation != "" && !strings.EqualFold(state.name, input.Abbreviation) {
+			continue
+		}
+
+		idx, ok := entries[state]
+		if !ok {
+			idx = len(result.Abbreviations)
+			entries[state] = idx
+			result.Abbreviations = append(result.Abbreviations, ZoneAbbreviation{
+				Abbreviation:  state.name,
+				Offset:        formatOffset(state.offset, s.roundOffsets),
+				OffsetSeconds: state.offset,
+				DST:           state.dst,
+				Numeric:       isNumericAbbreviation(state.name),
+				Eras:          []AbbreviationEra{},
+			})
+		}
+
+		entry := &result.Abbreviations[idx]
+		if ok && span.start.Sub(lastEnd[state]) <= abbreviationEraGap {
+			era := &entry.Eras[len(entry.Eras)-1]
+			era.Periods++
+			era.End = span.end.In(loc).Format(time.RFC3339)
+		} else {
+			era := AbbreviationEra{Periods: 1, End: span.end.In(loc).Format(time.RFC3339)}
+			if i > 0 {
+				era.Start = span.start.In(loc).Format(time.RFC3339)
+			}
+			entry.Eras = append(entry.Eras, era)
+		}
+		lastEnd[state] = span.end
+	}
+
+	// An abbreviation still in use, if only seasonally, has a span within
+	// the scan's last year; its latest era has no end yet
+	for state, idx := range entries {
+		if end.Sub(lastEnd[state]) <= abbreviationEraGap {
+			eras := result.Abbreviations[idx].Eras
+			eras[len(eras)-1].End = ""
+			eras[len(eras)-1].Ongoing = true
+		}
+	}
+
+	return result, nil
+}
+
+// abbreviationSpans splits [from, to) into the uninterrupted uses of each
+// abbreviation, scanning a day at a time and bisecting every change down
+// to the second as buildZoneIndex does. The last span ends at to
+func abbreviationSpans(loc *time.Location, from, to time.Time) []abbreviationSpan {
+	current := abbreviationSpan{state: abbreviationStateAt(from, loc), start: from}
+	var spans []abbreviationSpan
+
+	prev := from
+	for prev.Before(to) {
+		next := prev.Add(24 * time.Hour)
+		nextState := abbreviationStateAt(next, loc)
+		if nextState != current.state {
+			lo, hi := prev, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+				if abbreviationStateAt(mid, loc) == current.state {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			current.end = hi
+			spans = append(spans, current)
+			current = abbreviationSpan{state: abbreviationStateAt(hi, loc), start: hi}
+		}
+		prev = next
+	}
+
+	current.end = to
+	return append(spans, current)
+}
+
+// isNumericAbbreviation reports whether tzdata wrote the offset in place of
+// an abbreviation, as in "+03" or "-0330"
+func isNumericAbbreviation(name string) bool {
+	if len(name) < 2 || (name[0] != '+' && name[0] != '-') {
+		return false
+	}
+	return strings.Trim(name[1:], "0123456789") == ""
+}
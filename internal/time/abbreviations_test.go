@@ -0,0 +1,80 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_GetZoneAbbreviations(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	t.Run("full history", func(t *testing.T) {
+		result, err := service.GetZoneAbbreviations(ZoneAbbreviationsInput{Timezone: "Europe/Lisbon"})
+		require.NoError(t, err)
+		assert.Equal(t, "WEST", result.Current)
+
+		var names []string
+		byName := make(map[string]ZoneAbbreviation)
+		for _, abbreviation := range result.Abbreviations {
+			names = append(names, abbreviation.Abbreviation)
+			byName[abbreviation.Abbreviation] = abbreviation
+		}
+		assert.Equal(t, []string{"LMT", "WET", "WEST", "WEMT", "CET", "CEST"}, names)
+
+		lmt := byName["LMT"]
+		assert.Equal(t, "-00:36:45", lmt.Offset)
+		assert.Equal(t, []AbbreviationEra{{End: "1912-01-01T00:00:00Z", Periods: 1}}, lmt.Eras)
+
+		// Double summer time during the war, one spell per summer
+		assert.Equal(t, []AbbreviationEra{{Start: "1942-04-26T00:00:00+02:00", End: "1945-08-25T23:00:00+01:00", Periods: 4}},
+			byName["WEMT"].Eras)
+
+		// Lisbon followed Central European Time twice
+		cet := byName["CET"]
+		require.Len(t, cet.Eras, 2)
+		assert.Equal(t, "1966-10-02T03:00:00+01:00", cet.Eras[0].Start)
+		assert.Equal(t, "1992-09-27T02:00:00+01:00", cet.Eras[1].Start)
+		assert.False(t, cet.Eras[1].Ongoing)
+
+		// Seasonal DST stays ongoing between summers
+		west := byName["WEST"]
+		assert.True(t, west.DST)
+		last := west.Eras[len(west.Eras)-1]
+		assert.Equal(t, "1996-03-31T02:00:00+01:00", last.Start)
+		assert.Empty(t, last.End)
+		assert.True(t, last.Ongoing)
+	})
+
+	t.Run("filtered by abbreviation", func(t *testing.T) {
+		result, err := service.GetZoneAbbreviations(ZoneAbbreviationsInput{Timezone: "Europe/Lisbon", Abbreviation: "cest"})
+		require.NoError(t, err)
+		require.Len(t, result.Abbreviations, 1)
+		assert.Equal(t, "CEST", result.Abbreviations[0].Abbreviation)
+		assert.Equal(t, "+02:00", result.Abbreviations[0].Offset)
+
+		result, err = service.GetZoneAbbreviations(ZoneAbbreviationsInput{Timezone: "Europe/Lisbon", Abbreviation: "EST"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Abbreviations)
+	})
+
+	t.Run("numeric abbreviations", func(t *testing.T) {
+		result, err := service.GetZoneAbbreviations(ZoneAbbreviationsInput{Timezone: "Asia/Dubai"})
+		require.NoError(t, err)
+		require.Len(t, result.Abbreviations, 2)
+		assert.False(t, result.Abbreviations[0].Numeric)
+		assert.Equal(t, "+04", result.Abbreviations[1].Abbreviation)
+		assert.True(t, result.Abbreviations[1].Numeric)
+		assert.True(t, result.Abbreviations[1].Eras[0].Ongoing)
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		_, err := service.GetZoneAbbreviations(ZoneAbbreviationsInput{Timezone: "Mars/Olympus"})
+		assert.ErrorIs(t, err, timeerrors.ErrInvalidTimezone)
+	})
+}
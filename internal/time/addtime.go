@@ -0,0 +1,327 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Semantics of time arithmetic
+const (
+	// ArithmeticCalendar adds years, months, weeks and days to the wall
+	// clock date, keeping the wall time across DST changes; hours, minutes
+	// and seconds are elapsed time
+	ArithmeticCalendar = "calendar"
+	// ArithmeticAbsolute adds elapsed time only: a day is always 24 hours
+	ArithmeticAbsolute = "absolute"
+)
+
+// End-of-month policies, for adding months to a day the target month
+// doesn't have, such as January 31 plus one month
+const (
+	MonthEndClamp    = "clamp"     // the last day of the target month
+	MonthEndRollOver = "roll_over" // the days past its end roll into the next month
+	MonthEndError    = "error"
+)
+
+// maxDurationPart bounds each number of an ISO 8601 duration
+const maxDurationPart = 10000000
+
+// maxElapsed is the longest elapsed time a duration can add, about 292 years
+const maxElapsed = time.Duration(math.MaxInt64)
+
+// isoDurationPattern matches an ISO 8601 duration with an optional sign,
+// such as P1Y2M, P1W, -P1D or PT1H30M
+var isoDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// calendarDuration is an amount of time split into calendar parts, whose
+// length depends on the date, and an exact clock part
+type calendarDuration struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+// hasDate reports whether the duration has calendar parts
+func (d calendarDuration) hasDate() bool {
+	return d.years != 0 || d.months != 0 || d.days != 0
+}
+
+// parseCalendarDuration reads an ISO 8601 duration (P1M, PT36H, -P1DT2H)
+// or a Go duration (90m, -24h)
+func parseCalendarDuration(s string) (calendarDuration, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(strings.ToUpper(s), "P") {
+		clock, err := time.ParseDuration(s)
+		if err != nil {
+			return calendarDuration{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid duration %q: must be an ISO 8601 duration such as P1D or a Go duration such as 24h", s)
+		}
+		return calendarDuration{clock: clock}, nil
+	}
+
+	match := isoDurationPattern.FindStringSubmatch(strings.ToUpper(s))
+	if match == nil || strings.HasSuffix(strings.ToUpper(s), "P") || strings.HasSuffix(strings.ToUpper(s), "T") {
+		return calendarDuration{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid duration %q: must be an ISO 8601 duration such as P1Y2M3DT4H5M6S", s)
+	}
+	var parts [6]int
+	for i, group := range match[2:8] {
+		if group == "" {
+			continue
+		}
+		n, err := strconv.Atoi(group)
+		if err != nil || n > maxDurationPart {
+			return calendarDuration{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "invalid duration %q: %s is too large", s, group)
+		}
+		parts[i] = n
+	}
+	seconds, nanos := int64(0), 0
+	if match[8] != "" {
+		var err error
+		if seconds, nanos, err = parseDecimalSeconds(strings.Replace(match[8], ",", ".", 1)); err != nil || seconds > maxDurationPart {
+			return calendarDuration{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "invalid duration %q: %s is too large", s, match[8])
+		}
+	}
+
+	clock, ok := time.Duration(nanos), true
+	for _, part := range []struct {
+		n    int64
+		unit time.Duration
+	}{{int64(parts[4]), time.Hour}, {int64(parts[5]), time.Minute}, {seconds, time.Second}} {
+		if clock, ok = addDurationPart(clock, part.n, part.unit); !ok {
+			return calendarDuration{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "invalid duration %q: its hours, minutes and seconds are over %s", s, maxElapsed.Truncate(time.Hour))
+		}
+	}
+
+	d := calendarDuration{
+		years:  parts[0],
+		months: parts[1],
+		days:   parts[2]*7 + parts[3],
+		clock:  clock,
+	}
+	if match[1] == "-" {
+		d.years, d.months, d.days, d.clock = -d.years, -d.months, -d.days, -d.clock
+	}
+	return d, nil
+}
+
+// addDurationPart adds n units to sum, which are not negative. ok is false
+// when the total is over maxElapsed
+func addDurationPart(sum time.Duration, n int64, unit time.Duration) (time.Duration, bool) {
+	if n > int64(maxElapsed/unit) || time.Duration(n)*unit > maxElapsed-sum {
+		return 0, false
+	}
+	return sum + time.Duration(n)*unit, true
+}
+
+// elapsed returns the days and clock part as elapsed time, a day being 24
+// hours. ok is false when it is over maxElapsed
+func (d calendarDuration) elapsed() (time.Duration, bool) {
+	sign, days, clock := time.Duration(1), int64(d.days), d.clock
+	if days < 0 || clock < 0 {
+		sign, days, clock = -1, -days, -clock
+	}
+	total, ok := addDurationPart(clock, days, 24*time.Hour)
+	return sign * total, ok
+}
+
+// AddTimeInput represents input for adding a duration to an instant
+type AddTimeInput struct {
+	Timestamp Timestamp `json:"timestamp,omitempty"` // defaults to now
+	// Duration is an ISO 8601 duration (P1M, P1D, PT24H, -P1W) or a Go
+	// duration (90m, -24h)
+	Duration string `json:"duration"`
+	// Semantics is calendar (default) or absolute
+	Semantics string `json:"semantics,omitempty"`
+	// MonthEnd is the policy for months that lack the start's day: clamp
+	// (default), roll_over or error
+	MonthEnd string `json:"month_end,omitempty"`
+	// Timezone is the zone whose wall clock calendar days follow; defaults
+	// to the server default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// AddTimeResult represents an instant moved by a duration
+type AddTimeResult struct {
+	Start     string `json:"start"`
+	Result    string `json:"result"`
+	Duration  string `json:"duration"`
+	Semantics string `json:"semantics"`
+	// MonthEnd is the end-of-month policy applied, set when the duration
+	// has months or years
+	MonthEnd string `json:"month_end,omitempty"`
+	Timezone string `json:"timezone"`
+	// ElapsedSeconds is the time actually elapsed between start and result
+	ElapsedSeconds int64 `json:"elapsed_seconds"`
+	// Notes explain DST changes crossed, and wall times that were skipped
+	// or repeated
+	Notes []string `json:"notes,omitempty"`
+}
+
+// AddTime adds a duration to an instant with calendar or absolute semantics
+func (s *timeService) AddTime(input AddTimeInput) (AddTimeResult, error) {
+	if input.Duration == "" {
+		return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "duration cannot be empty")
+	}
+	duration, err := parseCalendarDuration(input.Duration)
+	if err != nil {
+		return AddTimeResult{}, err
+	}
+	semantics := strings.ToLower(defaultString(input.Semantics, ArithmeticCalendar))
+	if semantics != ArithmeticCalendar && semantics != ArithmeticAbsolute {
+		return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid semantics %q (must be one of: calendar, absolute)", input.Semantics)
+	}
+	monthEnd, err := parseMonthEndPolicy(input.MonthEnd)
+	if err != nil {
+		return AddTimeResult{}, err
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	start := s.clock.Now()
+	if !input.Timestamp.IsZero() {
+		if start, err = input.Timestamp.Resolve(); err != nil {
+			return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid timestamp: %w", err)
+		}
+	}
+	start = start.In(loc)
+
+	var end time.Time
+	var notes []string
+	if semantics == ArithmeticAbsolute {
+		if duration.years != 0 || duration.months != 0 {
+			return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "months and years have no fixed length; use calendar semantics to add %s", input.Duration)
+		}
+		elapsed, ok := duration.elapsed()
+		if !ok {
+			return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s is over %s of elapsed time; use calendar semantics to add days", input.Duration, maxElapsed.Truncate(time.Hour))
+		}
+		end = start.Add(elapsed).In(loc)
+	} else if end, notes, err = duration.addCalendar(start, loc, monthEnd); err != nil {
+		return AddTimeResult{}, err
+	}
+	if semantics == ArithmeticAbsolute || !duration.hasDate() {
+		if before, after := start.Format("-07:00"), end.Format("-07:00"); before != after {
+			notes = append(notes, fmt.Sprintf("the UTC offset changes from %s to %s, so the wall time moved with it; add days with calendar semantics to keep the wall time", before, after))
+		}
+	}
+	if end.Year() < 1 || end.Year() > 9999 {
+		return AddTimeResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s plus %s is outside the years 1 to 9999", start.Format(time.RFC3339), input.Duration)
+	}
+
+	result := AddTimeResult{
+		Start:          start.Format(time.RFC3339Nano),
+		Result:         end.Format(time.RFC3339Nano),
+		Duration:       input.Duration,
+		Semantics:      semantics,
+		Timezone:       timezone,
+		ElapsedSeconds: end.Unix() - start.Unix(),
+		Notes:          notes,
+	}
+	if duration.years != 0 || duration.months != 0 {
+		result.MonthEnd = monthEnd
+	}
+
+	s.logger.Debug("Added time",
+		slog.String("duration", input.Duration),
+		slog.String("semantics", semantics),
+		slog.String("result", result.Result))
+
+	return result, nil
+}
+
+// addCalendar adds the calendar parts to the wall clock date of t in loc,
+// keeping its wall time, then adds the clock part as elapsed time. Years
+// and months go first, with monthEnd deciding days the month lacks.
+func (d calendarDuration) addCalendar(t time.Time, loc *time.Location, monthEnd string) (time.Time, []string, error) {
+	if !d.hasDate() {
+		return t.Add(d.clock).In(loc), nil, nil
+	}
+
+	var notes []string
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if months := d.years*12 + d.months; months != 0 {
+		shifted, adjusted, err := addMonths(wall, months, monthEnd)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		if adjusted {
+			notes = append(notes, fmt.Sprintf("%s has no day %d; the %s policy gives %s",
+				time.Date(wall.Year(), wall.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC).Format("January 2006"),
+				wall.Day(), monthEnd, shifted.Format(time.DateOnly)))
+		}
+		wall = shifted
+	}
+	wall = wall.AddDate(0, 0, d.days)
+	date, note := resolveWallTime(wall, loc)
+	if note != "" {
+		notes = append(notes, note)
+	}
+
+	if before, after := t.Format("-07:00"), date.Format("-07:00"); before != after {
+		note := fmt.Sprintf("the UTC offset changes from %s to %s; the wall time is kept", before, after)
+		if d.years == 0 && d.months == 0 {
+			note += fmt.Sprintf(", so %d day(s) are %s of elapsed time", d.days, date.Sub(t))
+		}
+		notes = append(notes, note)
+	}
+	return date.Add(d.clock).In(loc), notes, nil
+}
+
+// resolveWallTime returns the instant a wall time, given in UTC, has in
+// loc. A wall time skipped by clocks springing forward moves forward by
+// the length of the gap, and a repeated one uses its first occurrence; the
+// note says which happened.
+func resolveWallTime(wall time.Time, loc *time.Location) (time.Time, string) {
+	instants := wallInstants(wall, loc)
+	switch len(instants) {
+	case 0:
+		// Keep the offset from before the gap, which lands as far past it
+		// as the wall time was into it
+		_, offset := wall.Add(-24 * time.Hour).In(loc).Zone()
+		date := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		return date, fmt.Sprintf("%s doesn't exist in %s on %s; moved forward to %s",
+			wall.Format(time.TimeOnly), loc, wall.Format(time.DateOnly), date.Format(time.TimeOnly))
+	case 1:
+		return instants[0], ""
+	default:
+		return instants[0], fmt.Sprintf("%s occurs twice in %s on %s; the first occurrence (%s) is used",
+			wall.Format(time.TimeOnly), loc, wall.Format(time.DateOnly), instants[0].Format("-07:00"))
+	}
+}
+
+// parseMonthEndPolicy validates an end-of-month policy, defaulting to clamp
+func parseMonthEndPolicy(policy string) (string, error) {
+	switch policy = strings.ToLower(defaultString(policy, MonthEndClamp)); policy {
+	case MonthEndClamp, MonthEndRollOver, MonthEndError:
+		return policy, nil
+	}
+	return "", timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid month_end %q (must be one of: clamp, roll_over, error)", policy)
+}
+
+// addMonths adds months to a wall time given in UTC. When the target month
+// is shorter than the day, policy clamps it to the month's last day, rolls
+// the extra days into the next month, or fails; adjusted reports either.
+func addMonths(wall time.Time, months int, policy string) (time.Time, bool, error) {
+	first := time.Date(wall.Year(), wall.Month()+time.Month(months), 1,
+		wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)
+	last := daysInMonth(first.Year(), first.Month())
+	if wall.Day() <= last {
+		return first.AddDate(0, 0, wall.Day()-1), false, nil
+	}
+	switch policy {
+	case MonthEndRollOver:
+		return first.AddDate(0, 0, wall.Day()-1), true, nil
+	case MonthEndError:
+		return time.Time{}, false, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "%s plus %d month(s) lands on %s %d, %d, which doesn't exist (month_end is error)",
+			wall.Format(time.DateOnly), months, first.Month(), wall.Day(), first.Year())
+	default:
+		return first.AddDate(0, 0, last-1), true, nil
+	}
+}
@@ -0,0 +1,190 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_AddTime(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name     string
+		input    AddTimeInput
+		expected string
+		elapsed  int64
+		note     string
+	}{
+		{
+			name:     "calendar day across spring forward",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-09T09:00:00-05:00"), Duration: "P1D", Timezone: "America/New_York"},
+			expected: "2024-03-10T09:00:00-04:00",
+			elapsed:  23 * 3600,
+			note:     "so 1 day(s) are 23h0m0s of elapsed time",
+		},
+		{
+			name:     "absolute day across spring forward",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-09T09:00:00-05:00"), Duration: "P1D", Semantics: "absolute", Timezone: "America/New_York"},
+			expected: "2024-03-10T10:00:00-04:00",
+			elapsed:  24 * 3600,
+			note:     "the wall time moved with it",
+		},
+		{
+			name:     "hours are elapsed time",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-09T09:00:00-05:00"), Duration: "PT24H", Timezone: "America/New_York"},
+			expected: "2024-03-10T10:00:00-04:00",
+			elapsed:  24 * 3600,
+			note:     "the wall time moved with it",
+		},
+		{
+			name:     "go duration",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-09T09:00:00-05:00"), Duration: "-90m", Timezone: "America/New_York"},
+			expected: "2024-03-09T07:30:00-05:00",
+			elapsed:  -5400,
+		},
+		{
+			name:     "days then hours",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-09T09:00:00-05:00"), Duration: "P1DT2H", Timezone: "America/New_York"},
+			expected: "2024-03-10T11:00:00-04:00",
+			elapsed:  25 * 3600,
+			note:     "the wall time is kept",
+		},
+		{
+			name:     "wall time skipped",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-09T02:30:00-05:00"), Duration: "P1D", Timezone: "America/New_York"},
+			expected: "2024-03-10T03:30:00-04:00",
+			elapsed:  24 * 3600,
+			note:     "02:30:00 doesn't exist in America/New_York on 2024-03-10; moved forward to 03:30:00",
+		},
+		{
+			name:     "wall time repeated",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-11-02T01:30:00-04:00"), Duration: "P1D", Timezone: "America/New_York"},
+			expected: "2024-11-03T01:30:00-04:00",
+			elapsed:  24 * 3600,
+			note:     "occurs twice",
+		},
+		{
+			name:     "month",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-01-15T12:00:00Z"), Duration: "p1m"},
+			expected: "2024-02-15T12:00:00Z",
+			elapsed:  31 * 86400,
+		},
+		{
+			name:     "month end clamped",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-01-31T12:00:00Z"), Duration: "P1M"},
+			expected: "2024-02-29T12:00:00Z",
+			elapsed:  29 * 86400,
+			note:     "February 2024 has no day 31; the clamp policy gives 2024-02-29",
+		},
+		{
+			name:     "month end rolled over",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2023-01-31T12:00:00Z"), Duration: "P1M", MonthEnd: "roll_over"},
+			expected: "2023-03-03T12:00:00Z",
+			elapsed:  31 * 86400,
+			note:     "the roll_over policy gives 2023-03-03",
+		},
+		{
+			name:     "leap day plus a year",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-02-29T00:00:00Z"), Duration: "P1Y"},
+			expected: "2025-02-28T00:00:00Z",
+			elapsed:  365 * 86400,
+			note:     "February 2025 has no day 29",
+		},
+		{
+			name:     "months before days",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-01-31T00:00:00Z"), Duration: "P1M1D"},
+			expected: "2024-03-01T00:00:00Z",
+			elapsed:  30 * 86400,
+			note:     "the clamp policy gives 2024-02-29",
+		},
+		{
+			name:     "negative week",
+			input:    AddTimeInput{Timestamp: RFC3339Timestamp("2024-01-15T12:00:00Z"), Duration: "-P1W"},
+			expected: "2024-01-08T12:00:00Z",
+			elapsed:  -7 * 86400,
+		},
+		{
+			name:     "fractional seconds",
+			input:    AddTimeInput{Duration: "PT1,5S"},
+			expected: "2023-12-25T15:30:46.5Z",
+			elapsed:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.AddTime(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Result)
+			assert.Equal(t, tt.elapsed, result.ElapsedSeconds)
+			if tt.note == "" {
+				assert.Empty(t, result.Notes)
+			} else {
+				require.NotEmpty(t, result.Notes)
+				assert.Contains(t, result.Notes[0], tt.note)
+			}
+		})
+	}
+
+	// The policy is echoed only when months or years were added
+	result, err := service.AddTime(AddTimeInput{Duration: "P1Y", MonthEnd: "Roll_Over"})
+	require.NoError(t, err)
+	assert.Equal(t, MonthEndRollOver, result.MonthEnd)
+	result, err = service.AddTime(AddTimeInput{Duration: "P1D", MonthEnd: "error"})
+	require.NoError(t, err)
+	assert.Empty(t, result.MonthEnd)
+}
+
+func TestTimeService_AddTimeErrors(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	_, err := service.AddTime(AddTimeInput{Duration: "P1M", Semantics: "absolute"})
+	assert.ErrorContains(t, err, "months and years have no fixed length")
+
+	for _, duration := range []string{"P", "PT", "P1H", "1 day", "P1DT"} {
+		_, err = service.AddTime(AddTimeInput{Duration: duration})
+		assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument), duration)
+	}
+
+	_, err = service.AddTime(AddTimeInput{Duration: "P99999999999D"})
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	// Parts that fit on their own can still overflow a time.Duration
+	for _, input := range []AddTimeInput{
+		{Duration: "PT3000000H"},
+		{Duration: "-PT3000000H"},
+		{Duration: "PT2562047H60M"},
+		{Duration: "P200000D", Semantics: "absolute"},
+		{Duration: "-P200000D", Semantics: "absolute"},
+		{Duration: "P106751DT24H", Semantics: "absolute"},
+	} {
+		_, err = service.AddTime(input)
+		assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange), input.Duration)
+	}
+	result, err := service.AddTime(AddTimeInput{Duration: "PT2562047H", Semantics: "absolute"})
+	require.NoError(t, err)
+	assert.Equal(t, "2316-04-05T14:30:45Z", result.Result)
+
+	_, err = service.AddTime(AddTimeInput{Duration: "P8000Y"})
+	assert.ErrorContains(t, err, "outside the years 1 to 9999")
+
+	_, err = service.AddTime(AddTimeInput{Timestamp: RFC3339Timestamp("2024-03-31T00:00:00Z"), Duration: "-P1M", MonthEnd: "error"})
+	assert.ErrorContains(t, err, "2024-03-31 plus -1 month(s) lands on February 31, 2024, which doesn't exist")
+
+	_, err = service.AddTime(AddTimeInput{Duration: "P1M", MonthEnd: "last"})
+	assert.ErrorContains(t, err, "must be one of: clamp, roll_over, error")
+
+	_, err = service.AddTime(AddTimeInput{Duration: "P1D", Semantics: "wall"})
+	assert.ErrorContains(t, err, "must be one of: calendar, absolute")
+
+	_, err = service.AddTime(AddTimeInput{})
+	assert.ErrorContains(t, err, "duration cannot be empty")
+}
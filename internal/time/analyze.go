@@ -0,0 +1,153 @@
+package time
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+	"unsafe"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// AnalyzeTimestampsInput represents input for timestamp list statistics
+type AnalyzeTimestampsInput struct {
+	Timestamps   []Timestamp `json:"timestamps"`
+	GapThreshold string      `json:"gap_threshold,omitempty"` // Go duration, e.g. "5m"; gaps are only reported when set
+	Timezone     string      `json:"timezone,omitempty"`      // timezone for rendered times, defaults to the server default
+}
+
+// TimestampGap is an interval between consecutive timestamps above the gap threshold
+type TimestampGap struct {
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Duration        string  `json:"duration"`
+}
+
+// MinuteCount is the number of events in one wall-clock minute
+type MinuteCount struct {
+	Minute string `json:"minute"`
+	Count  int    `json:"count"`
+}
+
+// AnalyzeTimestampsResult represents interval and rate statistics for a list of timestamps
+type AnalyzeTimestampsResult struct {
+	Count                 int            `json:"count"`
+	Timezone              string         `json:"timezone"`
+	Min                   string         `json:"min"`
+	Max                   string         `json:"max"`
+	SpanSeconds           float64        `json:"span_seconds"`
+	MeanIntervalSeconds   float64        `json:"mean_interval_seconds"`
+	MedianIntervalSeconds float64        `json:"median_interval_seconds"`
+	Gaps                  []TimestampGap `json:"gaps,omitempty"`
+	// EventsPerMinute only lists minutes with at least one event
+	EventsPerMinute []MinuteCount `json:"events_per_minute"`
+}
+
+// AnalyzeTimestamps computes min/max, interval statistics, gaps and a
+// per-minute histogram. Timestamps don't need to be sorted.
+func (s *timeService) AnalyzeTimestamps(input AnalyzeTimestampsInput) (AnalyzeTimestampsResult, error) {
+	if len(input.Timestamps) == 0 {
+		return AnalyzeTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamps cannot be empty")
+	}
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return AnalyzeTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	var gapThreshold time.Duration
+	if input.GapThreshold != "" {
+		if gapThreshold, err = time.ParseDuration(input.GapThreshold); err != nil {
+			return AnalyzeTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid gap_threshold: %w", err)
+		}
+		if gapThreshold <= 0 {
+			return AnalyzeTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "gap_threshold must be positive")
+		}
+	}
+
+	// Every timestamp can start a gap and a minute of its own
+	perTimestamp := timeBytes + int64(unsafe.Sizeof(time.Duration(0))) + timestampGapBytes + minuteCountBytes
+	if err := s.checkBulkBudget("analyze_timestamps", int64(len(input.Timestamps)), perTimestamp); err != nil {
+		return AnalyzeTimestampsResult{}, err
+	}
+
+	times, release, err := resolveTimestamps(input.Timestamps)
+	if err != nil {
+		return AnalyzeTimestampsResult{}, err
+	}
+	defer release()
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	first, last := times[0], times[len(times)-1]
+	result := AnalyzeTimestampsResult{
+		Count:       len(times),
+		Timezone:    timezone,
+		Min:         first.In(loc).Format(time.RFC3339Nano),
+		Max:         last.In(loc).Format(time.RFC3339Nano),
+		SpanSeconds: last.Sub(first).Seconds(),
+	}
+
+	intervals := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		interval := times[i].Sub(times[i-1])
+		intervals = append(intervals, interval)
+
+		if gapThreshold > 0 && interval > gapThreshold {
+			result.Gaps = append(result.Gaps, TimestampGap{
+				Start:           times[i-1].In(loc).Format(time.RFC3339Nano),
+				End:             times[i].In(loc).Format(time.RFC3339Nano),
+				DurationSeconds: interval.Seconds(),
+				Duration:        interval.String(),
+			})
+		}
+	}
+
+	if len(intervals) > 0 {
+		result.MeanIntervalSeconds = last.Sub(first).Seconds() / float64(len(intervals))
+		slices.Sort(intervals)
+		mid := len(intervals) / 2
+		if len(intervals)%2 == 1 {
+			result.MedianIntervalSeconds = intervals[mid].Seconds()
+		} else {
+			result.MedianIntervalSeconds = (intervals[mid-1] + intervals[mid]).Seconds() / 2
+		}
+	}
+
+	// Times are sorted, so equal minutes are adjacent
+	for _, t := range times {
+		minute := t.In(loc).Truncate(time.Minute).Format(time.RFC3339)
+		if n := len(result.EventsPerMinute); n > 0 && result.EventsPerMinute[n-1].Minute == minute {
+			result.EventsPerMinute[n-1].Count++
+			continue
+		}
+		result.EventsPerMinute = append(result.EventsPerMinute, MinuteCount{Minute: minute, Count: 1})
+	}
+
+	s.logger.Debug("Analyzed timestamps",
+		slog.Int("count", result.Count),
+		slog.Int("gaps", len(result.Gaps)))
+
+	return result, nil
+}
+
+// resolveTimestamps resolves a list of timestamps, reporting the index of
+// the first invalid one. The times are scratch from timesPool: call release
+// once done with them
+func resolveTimestamps(timestamps []Timestamp) (times []time.Time, release func(), err error) {
+	p := getTimes(len(timestamps))
+	times = *p
+	for i, ts := range timestamps {
+		t, err := ts.Resolve()
+		if err != nil {
+			putTimes(p)
+			return nil, nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid timestamp at index %d: %w", i, err)
+		}
+		times[i] = t
+	}
+	return times, func() { putTimes(p) }, nil
+}
@@ -0,0 +1,73 @@
+package time
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_AnalyzeTimestamps(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	t.Run("statistics, gaps and histogram", func(t *testing.T) {
+		result, err := service.AnalyzeTimestamps(AnalyzeTimestampsInput{
+			// Deliberately unsorted and mixing timestamp shapes
+			Timestamps: []Timestamp{
+				RFC3339Timestamp("2023-12-25T15:00:20Z"),
+				EpochTimestamp(1703516400, EpochSeconds), // 15:00:00
+				RFC3339Timestamp("2023-12-25T15:00:10Z"),
+				EpochTimestamp(1703516700000, EpochMilliseconds), // 15:05:00
+			},
+			GapThreshold: "1m",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, result.Count)
+		assert.Equal(t, "2023-12-25T15:00:00Z", result.Min)
+		assert.Equal(t, "2023-12-25T15:05:00Z", result.Max)
+		assert.Equal(t, 300.0, result.SpanSeconds)
+		assert.Equal(t, 100.0, result.MeanIntervalSeconds)
+		assert.Equal(t, 10.0, result.MedianIntervalSeconds)
+		assert.Equal(t, []TimestampGap{{
+			Start:           "2023-12-25T15:00:20Z",
+			End:             "2023-12-25T15:05:00Z",
+			DurationSeconds: 280,
+			Duration:        "4m40s",
+		}}, result.Gaps)
+		assert.Equal(t, []MinuteCount{
+			{Minute: "2023-12-25T15:00:00Z", Count: 3},
+			{Minute: "2023-12-25T15:05:00Z", Count: 1},
+		}, result.EventsPerMinute)
+	})
+
+	t.Run("timezone applies to rendered times", func(t *testing.T) {
+		result, err := service.AnalyzeTimestamps(AnalyzeTimestampsInput{
+			Timestamps: []Timestamp{RFC3339Timestamp("2023-12-25T15:00:00Z")},
+			Timezone:   "Asia/Kolkata",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "2023-12-25T20:30:00+05:30", result.Min)
+		assert.Equal(t, 0.0, result.MedianIntervalSeconds)
+		assert.Equal(t, "2023-12-25T20:30:00+05:30", result.EventsPerMinute[0].Minute)
+	})
+
+	errorCases := []struct {
+		name  string
+		input AnalyzeTimestampsInput
+	}{
+		{"empty list", AnalyzeTimestampsInput{}},
+		{"invalid timestamp", AnalyzeTimestampsInput{Timestamps: []Timestamp{RFC3339Timestamp("nope")}}},
+		{"invalid threshold", AnalyzeTimestampsInput{Timestamps: []Timestamp{EpochTimestamp(0, EpochSeconds)}, GapThreshold: "soon"}},
+		{"invalid timezone", AnalyzeTimestampsInput{Timestamps: []Timestamp{EpochTimestamp(0, EpochSeconds)}, Timezone: "Invalid/Zone"}},
+	}
+
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AnalyzeTimestamps(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
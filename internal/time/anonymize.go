@@ -0,0 +1,128 @@
+package time
+
+import (
+	"log/slog"
+	"math/rand"
+	"slices"
+	"time"
+	"unsafe"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// AnonymizeTimeInput represents input for coarsening and jittering timestamps
+type AnonymizeTimeInput struct {
+	Timestamps []Timestamp `json:"timestamps"`
+	Shift      string      `json:"shift,omitempty"`  // Go duration added to every timestamp, e.g. "-72h"
+	Jitter     string      `json:"jitter,omitempty"` // maximum random offset in either direction, e.g. "15m"
+	// RoundTo truncates to a window: a Go duration ("1h") or calendar days ("1d")
+	RoundTo  string `json:"round_to,omitempty"`
+	Seed     *int64 `json:"seed,omitempty"`     // makes jitter reproducible; a random seed is used and returned when unset
+	Timezone string `json:"timezone,omitempty"` // timezone for day rounding and rendered times, defaults to the server default
+}
+
+// AnonymizeTimeResult represents anonymized timestamps in input order
+type AnonymizeTimeResult struct {
+	Timestamps []string `json:"timestamps"`
+	Timezone   string   `json:"timezone"`
+	Seed       int64    `json:"seed"`
+}
+
+// AnonymizeTime shifts, jitters and rounds timestamps so exact times are not
+// leaked. The relative order of the input timestamps is preserved.
+func (s *timeService) AnonymizeTime(input AnonymizeTimeInput) (AnonymizeTimeResult, error) {
+	if len(input.Timestamps) == 0 {
+		return AnonymizeTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamps cannot be empty")
+	}
+
+	shift, err := time.ParseDuration(defaultString(input.Shift, "0s"))
+	if err != nil {
+		return AnonymizeTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid shift: %w", err)
+	}
+	jitter, err := parseNonNegativeDuration("jitter", input.Jitter)
+	if err != nil {
+		return AnonymizeTimeResult{}, err
+	}
+
+	var roundTo *bucketWindow
+	if input.RoundTo != "" {
+		window, err := parseBucketWindow(input.RoundTo)
+		if err != nil {
+			return AnonymizeTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid round_to: %w", err)
+		}
+		roundTo = &window
+	}
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return AnonymizeTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	seed := time.Now().UnixNano()
+	if input.Seed != nil {
+		seed = *input.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	perTimestamp := 2*timeBytes + int64(unsafe.Sizeof(0)) + renderedTimeBytes
+	if err := s.checkBulkBudget("anonymize_time", int64(len(input.Timestamps)), perTimestamp); err != nil {
+		return AnonymizeTimeResult{}, err
+	}
+
+	times, release, err := resolveTimestamps(input.Timestamps)
+	if err != nil {
+		return AnonymizeTimeResult{}, err
+	}
+	defer release()
+
+	scratch := getTimes(len(times))
+	defer putTimes(scratch)
+	anonymized := *scratch
+	for i, t := range times {
+		t = t.Add(shift)
+		if jitter > 0 {
+			t = t.Add(time.Duration(rng.Int63n(int64(2*jitter)+1)) - jitter)
+		}
+		if roundTo != nil {
+			t = roundTo.start(t, loc)
+		}
+		anonymized[i] = t
+	}
+
+	// Jitter can reorder timestamps, so hand out the sorted anonymized values
+	// by the rank of each original timestamp
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return times[a].Compare(times[b]) })
+	slices.SortFunc(anonymized, func(a, b time.Time) int { return a.Compare(b) })
+
+	result := AnonymizeTimeResult{
+		Timestamps: make([]string, len(times)),
+		Timezone:   timezone,
+		Seed:       seed,
+	}
+	for rank, i := range order {
+		result.Timestamps[i] = anonymized[rank].In(loc).Format(time.RFC3339)
+	}
+
+	s.logger.Debug("Anonymized timestamps",
+		slog.Int("count", len(times)),
+		slog.Duration("jitter", jitter),
+		slog.String("round_to", input.RoundTo))
+
+	return result, nil
+}
+
+// defaultString returns value, or fallback when value is empty
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_AnonymizeTime(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	seed := int64(42)
+	input := []Timestamp{
+		RFC3339Timestamp("2023-12-25T15:30:45Z"),
+		RFC3339Timestamp("2023-12-25T15:30:46Z"),
+		RFC3339Timestamp("2023-12-25T09:00:00Z"),
+		RFC3339Timestamp("2023-12-25T15:30:47Z"),
+	}
+
+	t.Run("shift and round", func(t *testing.T) {
+		result, err := service.AnonymizeTime(AnonymizeTimeInput{
+			Timestamps: input,
+			Shift:      "-24h",
+			RoundTo:    "1h",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"2023-12-24T15:00:00Z",
+			"2023-12-24T15:00:00Z",
+			"2023-12-24T09:00:00Z",
+			"2023-12-24T15:00:00Z",
+		}, result.Timestamps)
+	})
+
+	t.Run("day rounding in a timezone", func(t *testing.T) {
+		result, err := service.AnonymizeTime(AnonymizeTimeInput{
+			Timestamps: []Timestamp{RFC3339Timestamp("2023-12-25T03:00:00Z")},
+			RoundTo:    "1d",
+			Timezone:   "America/New_York",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"2023-12-24T00:00:00-05:00"}, result.Timestamps)
+	})
+
+	t.Run("seeded jitter is reproducible and preserves order", func(t *testing.T) {
+		in := AnonymizeTimeInput{Timestamps: input, Jitter: "10m", Seed: &seed}
+
+		first, err := service.AnonymizeTime(in)
+		require.NoError(t, err)
+		second, err := service.AnonymizeTime(in)
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Timestamps, second.Timestamps)
+		assert.Equal(t, seed, first.Seed)
+
+		parsed := make([]time.Time, len(first.Timestamps))
+		for i, ts := range first.Timestamps {
+			parsed[i], err = time.Parse(time.RFC3339, ts)
+			require.NoError(t, err)
+		}
+		assert.False(t, parsed[1].Before(parsed[0]))
+		assert.False(t, parsed[3].Before(parsed[1]))
+		assert.True(t, parsed[2].Before(parsed[0]))
+		assert.WithinDuration(t, time.Date(2023, 12, 25, 9, 0, 0, 0, time.UTC), parsed[2], 10*time.Minute)
+	})
+
+	t.Run("random seed is reported", func(t *testing.T) {
+		result, err := service.AnonymizeTime(AnonymizeTimeInput{Timestamps: input, Jitter: "1m"})
+		require.NoError(t, err)
+
+		replayed, err := service.AnonymizeTime(AnonymizeTimeInput{Timestamps: input, Jitter: "1m", Seed: &result.Seed})
+		require.NoError(t, err)
+		assert.Equal(t, result.Timestamps, replayed.Timestamps)
+	})
+
+	errorCases := []struct {
+		name  string
+		input AnonymizeTimeInput
+	}{
+		{"empty list", AnonymizeTimeInput{}},
+		{"invalid shift", AnonymizeTimeInput{Timestamps: input, Shift: "yesterday"}},
+		{"negative jitter", AnonymizeTimeInput{Timestamps: input, Jitter: "-1m"}},
+		{"invalid round_to", AnonymizeTimeInput{Timestamps: input, RoundTo: "0d"}},
+		{"invalid timezone", AnonymizeTimeInput{Timestamps: input, Timezone: "Invalid/Zone"}},
+	}
+
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AnonymizeTime(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/logger"
+)
+
+// Benchmarks of the methods behind the core and bulk tools. make
+// bench-compare runs them on the base branch and on HEAD, and fails on a
+// regression, so keep their names and inputs stable
+
+var benchNow = time.Date(2026, 10, 16, 8, 30, 0, 123456789, time.UTC)
+
+// benchFormats are the formats the benchmarks render, one of each kind
+var benchFormats = []string{"RFC3339", "%Y-%m-%d %H:%M:%S", "Mon, 02 Jan 2006 15:04"}
+
+// newBenchService creates a service that doesn't log, on a frozen clock
+func newBenchService(b *testing.B) TimeService {
+	b.Helper()
+	service := NewTimeService("UTC", "RFC3339", append([]string{"RFC3339Nano", "Unix"}, benchFormats...),
+		logger.Slog(zap.NewNop()), WithClock(FixedClock{Time: benchNow}))
+	// Warm the zone cache, which calls hit in production
+	if _, err := service.ConvertTimezone(benchNow, "UTC", "America/New_York"); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	return service
+}
+
+// benchTimestamps returns n timestamps a minute apart, with gaps
+func benchTimestamps(n int) []Timestamp {
+	timestamps := make([]Timestamp, n)
+	for i := range timestamps {
+		timestamps[i] = EpochTimestamp(benchNow.Unix()+int64(i*60+i%7*600), EpochSeconds)
+	}
+	return timestamps
+}
+
+func BenchmarkTimeService_GetCurrentTime(b *testing.B) {
+	service := newBenchService(b)
+	input := GetTimeInput{Timezone: "America/New_York", Formats: []string{"RFC3339Nano", "Unix"}}
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GetCurrentTime(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeService_FormatTime(b *testing.B) {
+	for _, format := range benchFormats {
+		b.Run(FormatKind(format), func(b *testing.B) {
+			service := newBenchService(b)
+			input := FormatTimeInput{Timestamp: RFC3339Timestamp("2026-10-16T08:30:00Z"), Format: format, Timezone: "Europe/Berlin"}
+			for i := 0; i < b.N; i++ {
+				if _, err := service.FormatTime(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTimeService_ParseTime(b *testing.B) {
+	service := newBenchService(b)
+	input := ParseTimeInput{TimeString: "2026-10-16T08:30:00+02:00"}
+	for i := 0; i < b.N; i++ {
+		if _, err := service.ParseTime(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeService_GetTimezoneInfo(b *testing.B) {
+	service := newBenchService(b)
+	input := TimezoneInfoInput{Timezone: "America/New_York", ReferenceTime: benchNow}
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GetTimezoneInfo(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeService_ConvertTimezone(b *testing.B) {
+	service := newBenchService(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := service.ConvertTimezone(benchNow, "UTC", "America/New_York"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeService_AnalyzeTimestamps(b *testing.B) {
+	service := newBenchService(b)
+	input := AnalyzeTimestampsInput{Timestamps: benchTimestamps(1000), GapThreshold: "5m"}
+	for i := 0; i < b.N; i++ {
+		if _, err := service.AnalyzeTimestamps(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeService_BucketTimestamps(b *testing.B) {
+	service := newBenchService(b)
+	input := BucketTimestampsInput{Timestamps: benchTimestamps(1000), Window: "15m", IncludeEmpty: true}
+	for i := 0; i < b.N; i++ {
+		if _, err := service.BucketTimestamps(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Billing periods
+const (
+	BillingMonthly = "monthly"
+	BillingAnnual  = "annual"
+)
+
+const (
+	defaultBillingDates = 3
+	maxBillingDates     = 120
+)
+
+// NextBillingDateInput represents input for listing the charge dates of a
+// subscription
+type NextBillingDateInput struct {
+	// Anchor is the subscription's billing anchor, usually its first
+	// charge; every charge falls on its day of the month and wall time
+	Anchor Timestamp `json:"anchor"`
+	Period string    `json:"period,omitempty"` // monthly (default) or annual
+	After  Timestamp `json:"after,omitempty"`  // list charges after this instant, defaults to now
+	Count  int       `json:"count,omitempty"`  // defaults to 3, at most 120
+	// MonthEnd is the policy for months that lack the anchor's day: clamp
+	// (default), roll_over or error
+	MonthEnd string `json:"month_end,omitempty"`
+	// Timezone is the billing timezone, whose calendar the anchor's day
+	// follows; defaults to the server default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// BillingDate is one charge of a subscription
+type BillingDate struct {
+	Period int    `json:"period"` // periods since the anchor, which is period 0
+	Date   string `json:"date"`
+	// Adjusted is set when the month lacks the anchor's day and the
+	// month_end policy moved the charge
+	Adjusted bool `json:"adjusted,omitempty"`
+}
+
+// NextBillingDateResult represents the next charge dates of a subscription
+type NextBillingDateResult struct {
+	Anchor    string        `json:"anchor"`
+	AnchorDay int           `json:"anchor_day"`
+	Period    string        `json:"period"`
+	MonthEnd  string        `json:"month_end"`
+	Timezone  string        `json:"timezone"`
+	Dates     []BillingDate `json:"dates"`
+	Notes     []string      `json:"notes,omitempty"`
+}
+
+// NextBillingDate lists the next charge dates of a subscription. Each is
+// computed from the anchor rather than the charge before it, so a January
+// 31 anchor bills on February 29 and then March 31.
+func (s *timeService) NextBillingDate(input NextBillingDateInput) (NextBillingDateResult, error) {
+	if input.Anchor.IsZero() {
+		return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "anchor is required")
+	}
+	anchor, err := input.Anchor.Resolve()
+	if err != nil {
+		return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid anchor: %w", err)
+	}
+	period := strings.ToLower(defaultString(input.Period, BillingMonthly))
+	step := 1
+	switch period {
+	case BillingMonthly:
+	case BillingAnnual:
+		step = 12
+	default:
+		return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid period %q (must be one of: monthly, annual)", input.Period)
+	}
+	count, err := boundedCount("count", input.Count, defaultBillingDates, maxBillingDates)
+	if err != nil {
+		return NextBillingDateResult{}, err
+	}
+	monthEnd, err := parseMonthEndPolicy(input.MonthEnd)
+	if err != nil {
+		return NextBillingDateResult{}, err
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	after := s.clock.Now()
+	if !input.After.IsZero() {
+		if after, err = input.After.Resolve(); err != nil {
+			return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid after: %w", err)
+		}
+	}
+
+	anchor = anchor.In(loc)
+	wall := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(), time.UTC)
+	result := NextBillingDateResult{
+		Anchor:    anchor.Format(time.RFC3339Nano),
+		AnchorDay: anchor.Day(),
+		Period:    period,
+		MonthEnd:  monthEnd,
+		Timezone:  timezone,
+		Dates:     []BillingDate{},
+	}
+
+	// Charges already billed don't fail the error policy, so place them by
+	// clamping, and fail only on one that is listed
+	policy := monthEnd
+	if policy == MonthEndError {
+		policy = MonthEndClamp
+	}
+	// Start a period before the one after falls in, then skip charges not after it
+	local := after.In(loc)
+	k := ((local.Year()-anchor.Year())*12+int(local.Month()-anchor.Month()))/step - 1
+	for k = max(k, 0); len(result.Dates) < count; k++ {
+		charge, adjusted, _ := addMonths(wall, k*step, policy)
+		if charge.Year() > 9999 {
+			return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "charges run past the year 9999")
+		}
+		date, note := resolveWallTime(charge, loc)
+		if !date.After(after) {
+			continue
+		}
+		if adjusted && monthEnd == MonthEndError {
+			return NextBillingDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "the charge for period %d falls in %s, which has no day %d (month_end is error)",
+				k, charge.Format("January 2006"), anchor.Day())
+		}
+		if note != "" {
+			result.Notes = append(result.Notes, note)
+		}
+		result.Dates = append(result.Dates, BillingDate{Period: k, Date: date.Format(time.RFC3339Nano), Adjusted: adjusted})
+	}
+	if monthEnd == MonthEndRollOver && slices.ContainsFunc(result.Dates, func(d BillingDate) bool { return d.Adjusted }) {
+		result.Notes = append(result.Notes, fmt.Sprintf("with roll_over, a month without day %d is billed early in the next month instead, so that month has two charges", anchor.Day()))
+	}
+
+	s.logger.Debug("Listed billing dates",
+		slog.String("anchor", result.Anchor),
+		slog.String("period", period),
+		slog.String("month_end", monthEnd),
+		slog.Int("dates", len(result.Dates)))
+
+	return result, nil
+}
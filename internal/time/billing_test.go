@@ -0,0 +1,92 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_NextBillingDate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	dates := func(result NextBillingDateResult) []string {
+		var out []string
+		for _, date := range result.Dates {
+			out = append(out, date.Date)
+		}
+		return out
+	}
+
+	t.Run("month end clamped from the anchor", func(t *testing.T) {
+		result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2023-10-31T09:00:00Z"), Count: 4})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2024-01-31T09:00:00Z", "2024-02-29T09:00:00Z", "2024-03-31T09:00:00Z", "2024-04-30T09:00:00Z"}, dates(result))
+		assert.Equal(t, 3, result.Dates[0].Period)
+		assert.False(t, result.Dates[0].Adjusted)
+		assert.True(t, result.Dates[1].Adjusted)
+		assert.Equal(t, 31, result.AnchorDay)
+		assert.Equal(t, MonthEndClamp, result.MonthEnd)
+		assert.Empty(t, result.Notes)
+	})
+
+	t.Run("roll over", func(t *testing.T) {
+		result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2023-12-31T00:00:00Z"), MonthEnd: "roll_over", After: RFC3339Timestamp("2024-02-01T00:00:00Z")})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2024-03-02T00:00:00Z", "2024-03-31T00:00:00Z", "2024-05-01T00:00:00Z"}, dates(result))
+		require.Len(t, result.Notes, 1)
+		assert.Contains(t, result.Notes[0], "so that month has two charges")
+	})
+
+	t.Run("annual from a leap day", func(t *testing.T) {
+		result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2020-02-29T12:00:00Z"), Period: "annual", Count: 5})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2024-02-29T12:00:00Z", "2025-02-28T12:00:00Z", "2026-02-28T12:00:00Z", "2027-02-28T12:00:00Z", "2028-02-29T12:00:00Z"}, dates(result))
+	})
+
+	t.Run("anchor in the future is the first charge", func(t *testing.T) {
+		result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2024-03-01T00:00:00Z"), Count: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2024-03-01T00:00:00Z"}, dates(result))
+		assert.Equal(t, 0, result.Dates[0].Period)
+	})
+
+	t.Run("wall time kept across DST", func(t *testing.T) {
+		result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2024-02-10T09:00:00-05:00"), Count: 2, Timezone: "America/New_York"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2024-02-10T09:00:00-05:00", "2024-03-10T09:00:00-04:00"}, dates(result))
+	})
+
+	t.Run("charge at now is not listed", func(t *testing.T) {
+		result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2023-12-15T00:00:00Z"), Count: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2024-02-15T00:00:00Z"}, dates(result))
+	})
+}
+
+func TestTimeService_NextBillingDateErrors(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	// Past charges that lacked the day don't fail the error policy
+	result, err := service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2023-10-31T00:00:00Z"), MonthEnd: "error", Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-31T00:00:00Z", result.Dates[0].Date)
+
+	_, err = service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2023-10-31T00:00:00Z"), MonthEnd: "error", Count: 2})
+	assert.ErrorContains(t, err, "the charge for period 4 falls in February 2024, which has no day 31")
+
+	_, err = service.NextBillingDate(NextBillingDateInput{})
+	assert.ErrorContains(t, err, "anchor is required")
+
+	_, err = service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2024-01-01T00:00:00Z"), Period: "weekly"})
+	assert.ErrorContains(t, err, "must be one of: monthly, annual")
+
+	_, err = service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("2024-01-01T00:00:00Z"), Count: 121})
+	assert.ErrorContains(t, err, "count must be between 1 and 120")
+
+	_, err = service.NextBillingDate(NextBillingDateInput{Anchor: RFC3339Timestamp("9999-06-01T00:00:00Z"), After: RFC3339Timestamp("9999-11-15T00:00:00Z"), Count: 3})
+	assert.ErrorContains(t, err, "past the year 9999")
+}
@@ -0,0 +1,212 @@
+package time
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// maxBuckets bounds the number of buckets returned when empty buckets are included
+const maxBuckets = 10000
+
+// BucketTimestampsInput represents input for grouping timestamps into fixed windows
+type BucketTimestampsInput struct {
+	Timestamps []Timestamp `json:"timestamps"`
+	// Window is a Go duration ("5m", "1h") or a number of calendar days ("1d", "7d")
+	Window       string `json:"window"`
+	Timezone     string `json:"timezone,omitempty"`      // timezone buckets are aligned to, defaults to the server default
+	IncludeEmpty bool   `json:"include_empty,omitempty"` // also return buckets with no timestamps
+	// IntervalSemantics writes bucket ends as the next bucket's start
+	// (half_open, the default) or as their last second (closed)
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// TimestampBucket is one window and the number of timestamps in it
+type TimestampBucket struct {
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	DurationSeconds float64 `json:"duration_seconds"` // 82800 or 90000 for daily buckets on DST transition days
+	Count           int     `json:"count"`
+}
+
+// BucketTimestampsResult represents timestamp counts per window
+type BucketTimestampsResult struct {
+	Window            string            `json:"window"`
+	Timezone          string            `json:"timezone"`
+	IntervalSemantics string            `json:"interval_semantics"`
+	Total             int               `json:"total"`
+	Buckets           []TimestampBucket `json:"buckets"`
+}
+
+// bucketWindow is either a fixed duration or a number of calendar days
+type bucketWindow struct {
+	duration time.Duration
+	days     int
+}
+
+// parseBucketWindow parses a window such as "5m", "1h" or "1d"
+func parseBucketWindow(window string) (bucketWindow, error) {
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return bucketWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid window %s: days must be a positive integer", window)
+		}
+		return bucketWindow{days: n}, nil
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return bucketWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid window %s: %w", window, err)
+	}
+	if d <= 0 {
+		return bucketWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid window %s: must be positive", window)
+	}
+	return bucketWindow{duration: d}, nil
+}
+
+// start returns the start of the bucket containing t
+func (w bucketWindow) start(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+
+	if w.days > 0 {
+		// Calendar days, so buckets follow local midnight across DST changes.
+		// Multi-day windows are aligned to days since 1970-01-01.
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		index := floorDiv(day.Unix()/86400, int64(w.days))
+		start := time.Unix(index*int64(w.days)*86400, 0).UTC()
+		return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	}
+
+	// Fixed windows are aligned to the local offset in effect at t, so an
+	// hour repeated by a DST change lands in two distinct buckets
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(w.duration).Add(-shift).In(loc)
+}
+
+// next returns the start of the bucket following the one starting at start
+func (w bucketWindow) next(start time.Time, loc *time.Location) time.Time {
+	if w.days > 0 {
+		return start.AddDate(0, 0, w.days)
+	}
+	return w.start(start.Add(w.duration), loc)
+}
+
+// count estimates the buckets between first and last, from above: calendar
+// days are taken to be as short as a DST change can make them
+func (w bucketWindow) count(first, last time.Time) int64 {
+	length := w.duration
+	if w.days > 0 {
+		length = time.Duration(w.days) * 23 * time.Hour
+	}
+	return int64(last.Sub(first)/length) + 2
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// BucketTimestamps groups timestamps into fixed windows aligned in a timezone
+func (s *timeService) BucketTimestamps(input BucketTimestampsInput) (BucketTimestampsResult, error) {
+	if len(input.Timestamps) == 0 {
+		return BucketTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamps cannot be empty")
+	}
+
+	window, err := parseBucketWindow(input.Window)
+	if err != nil {
+		return BucketTimestampsResult{}, err
+	}
+	semantics, err := ResolveIntervalSemantics(input.IntervalSemantics, IntervalHalfOpen)
+	if err != nil {
+		return BucketTimestampsResult{}, err
+	}
+	if semantics == IntervalClosed && window.days == 0 && window.duration < time.Second {
+		return BucketTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"interval_semantics closed needs a window of at least 1s, got: %s", input.Window)
+	}
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return BucketTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	if err := s.checkBulkBudget("bucket_timestamps", int64(len(input.Timestamps)), timeBytes+bucketBytes); err != nil {
+		return BucketTimestampsResult{}, err
+	}
+
+	times, release, err := resolveTimestamps(input.Timestamps)
+	if err != nil {
+		return BucketTimestampsResult{}, err
+	}
+	defer release()
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	// Empty buckets fill the whole span, so check it fits before expanding it
+	if input.IncludeEmpty {
+		if err := s.checkBulkBudget("bucket_timestamps", window.count(times[0], times[len(times)-1]), bucketBytes); err != nil {
+			return BucketTimestampsResult{}, err
+		}
+	}
+
+	result := BucketTimestampsResult{
+		Window:            input.Window,
+		Timezone:          timezone,
+		IntervalSemantics: semantics,
+		Total:             len(times),
+		Buckets:           []TimestampBucket{},
+	}
+
+	var current *TimestampBucket
+	var currentStart, currentEnd time.Time
+	for _, t := range times {
+		if current != nil && t.Before(currentEnd) {
+			current.Count++
+			continue
+		}
+
+		start := window.start(t, loc)
+		if current != nil && input.IncludeEmpty {
+			for gap := currentEnd; gap.Before(start); gap = window.next(gap, loc) {
+				if len(result.Buckets) >= maxBuckets {
+					return BucketTimestampsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "too many buckets (max %d), use a larger window", maxBuckets)
+				}
+				result.Buckets = append(result.Buckets, newTimestampBucket(gap, window.next(gap, loc), semantics, 0))
+			}
+		}
+
+		currentStart, currentEnd = start, window.next(start, loc)
+		result.Buckets = append(result.Buckets, newTimestampBucket(currentStart, currentEnd, semantics, 1))
+		current = &result.Buckets[len(result.Buckets)-1]
+	}
+
+	s.logger.Debug("Bucketed timestamps",
+		slog.String("window", input.Window),
+		slog.String("timezone", timezone),
+		slog.Int("buckets", len(result.Buckets)))
+
+	return result, nil
+}
+
+// newTimestampBucket creates a bucket between start and end, writing the
+// end with semantics. The duration is the half-open one either way
+func newTimestampBucket(start, end time.Time, semantics string, count int) TimestampBucket {
+	return TimestampBucket{
+		Start:           start.Format(time.RFC3339),
+		End:             ReportedEnd(end, semantics, IntervalSecond).Format(time.RFC3339),
+		DurationSeconds: end.Sub(start).Seconds(),
+		Count:           count,
+	}
+}
@@ -0,0 +1,154 @@
+package time
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_BucketTimestamps(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	rfc := func(values ...string) []Timestamp {
+		ts := make([]Timestamp, len(values))
+		for i, v := range values {
+			ts[i] = RFC3339Timestamp(v)
+		}
+		return ts
+	}
+
+	tests := []struct {
+		name     string
+		input    BucketTimestampsInput
+		expected []TimestampBucket
+	}{
+		{
+			name: "five minute windows",
+			input: BucketTimestampsInput{
+				Timestamps: rfc("2023-12-25T15:04:59Z", "2023-12-25T15:00:00Z", "2023-12-25T15:12:00Z"),
+				Window:     "5m",
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-12-25T15:00:00Z", End: "2023-12-25T15:05:00Z", DurationSeconds: 300, Count: 2},
+				{Start: "2023-12-25T15:10:00Z", End: "2023-12-25T15:15:00Z", DurationSeconds: 300, Count: 1},
+			},
+		},
+		{
+			name: "closed windows end on their last second",
+			input: BucketTimestampsInput{
+				Timestamps:        rfc("2023-12-25T15:04:59Z", "2023-12-25T15:00:00Z"),
+				Window:            "5m",
+				IntervalSemantics: IntervalClosed,
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-12-25T15:00:00Z", End: "2023-12-25T15:04:59Z", DurationSeconds: 300, Count: 2},
+			},
+		},
+		{
+			name: "include empty buckets",
+			input: BucketTimestampsInput{
+				Timestamps:   rfc("2023-12-25T15:00:00Z", "2023-12-25T15:12:00Z"),
+				Window:       "5m",
+				IncludeEmpty: true,
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-12-25T15:00:00Z", End: "2023-12-25T15:05:00Z", DurationSeconds: 300, Count: 1},
+				{Start: "2023-12-25T15:05:00Z", End: "2023-12-25T15:10:00Z", DurationSeconds: 300, Count: 0},
+				{Start: "2023-12-25T15:10:00Z", End: "2023-12-25T15:15:00Z", DurationSeconds: 300, Count: 1},
+			},
+		},
+		{
+			name: "hourly windows follow half hour offsets",
+			input: BucketTimestampsInput{
+				Timestamps: rfc("2023-12-25T15:00:00Z", "2023-12-25T15:29:00Z", "2023-12-25T15:31:00Z"),
+				Window:     "1h",
+				Timezone:   "Asia/Kolkata",
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-12-25T20:00:00+05:30", End: "2023-12-25T21:00:00+05:30", DurationSeconds: 3600, Count: 2},
+				{Start: "2023-12-25T21:00:00+05:30", End: "2023-12-25T22:00:00+05:30", DurationSeconds: 3600, Count: 1},
+			},
+		},
+		{
+			name: "repeated hour on fall back is two buckets",
+			input: BucketTimestampsInput{
+				// 01:30 EDT and 01:30 EST
+				Timestamps: rfc("2023-11-05T05:30:00Z", "2023-11-05T06:30:00Z"),
+				Window:     "1h",
+				Timezone:   "America/New_York",
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-11-05T01:00:00-04:00", End: "2023-11-05T01:00:00-05:00", DurationSeconds: 3600, Count: 1},
+				{Start: "2023-11-05T01:00:00-05:00", End: "2023-11-05T02:00:00-05:00", DurationSeconds: 3600, Count: 1},
+			},
+		},
+		{
+			name: "daily buckets follow local midnight across DST",
+			input: BucketTimestampsInput{
+				// 23:30 EST on Nov 4 is still Nov 4 locally; the 5th is 25h long
+				Timestamps: rfc("2023-11-05T03:30:00Z", "2023-11-05T12:00:00Z", "2023-11-06T04:30:00Z", "2023-03-12T12:00:00Z"),
+				Window:     "1d",
+				Timezone:   "America/New_York",
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-03-12T00:00:00-05:00", End: "2023-03-13T00:00:00-04:00", DurationSeconds: 82800, Count: 1},
+				{Start: "2023-11-04T00:00:00-04:00", End: "2023-11-05T00:00:00-04:00", DurationSeconds: 86400, Count: 1},
+				{Start: "2023-11-05T00:00:00-04:00", End: "2023-11-06T00:00:00-05:00", DurationSeconds: 90000, Count: 2},
+			},
+		},
+		{
+			name: "weekly windows",
+			input: BucketTimestampsInput{
+				Timestamps: rfc("2023-12-25T15:00:00Z"),
+				Window:     "7d",
+			},
+			expected: []TimestampBucket{
+				{Start: "2023-12-21T00:00:00Z", End: "2023-12-28T00:00:00Z", DurationSeconds: 604800, Count: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.BucketTimestamps(tt.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, result.Buckets)
+			assert.Equal(t, len(tt.input.Timestamps), result.Total)
+		})
+	}
+}
+
+func TestTimeService_BucketTimestamps_Errors(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	one := []Timestamp{EpochTimestamp(0, EpochSeconds)}
+
+	tests := []struct {
+		name  string
+		input BucketTimestampsInput
+	}{
+		{"empty list", BucketTimestampsInput{Window: "1h"}},
+		{"missing window", BucketTimestampsInput{Timestamps: one}},
+		{"invalid days", BucketTimestampsInput{Timestamps: one, Window: "0d"}},
+		{"negative duration", BucketTimestampsInput{Timestamps: one, Window: "-5m"}},
+		{"invalid timezone", BucketTimestampsInput{Timestamps: one, Window: "1h", Timezone: "Invalid/Zone"}},
+		{"invalid interval semantics", BucketTimestampsInput{Timestamps: one, Window: "1h", IntervalSemantics: "inclusive"}},
+		{"closed window under a second", BucketTimestampsInput{Timestamps: one, Window: "500ms", IntervalSemantics: IntervalClosed}},
+		{"too many empty buckets", BucketTimestampsInput{
+			Timestamps:   []Timestamp{EpochTimestamp(0, EpochSeconds), EpochTimestamp(1e9, EpochSeconds)},
+			Window:       "1s",
+			IncludeEmpty: true,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.BucketTimestamps(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
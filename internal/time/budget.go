@@ -0,0 +1,89 @@
+package time
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Estimated bytes each item of a bulk operation holds while it runs: the
+// item itself and the RFC 3339 strings rendered for it
+const (
+	timeBytes         = int64(unsafe.Sizeof(time.Time{}))
+	renderedTimeBytes = int64(unsafe.Sizeof("")) + int64(len(time.RFC3339Nano))
+
+	bucketBytes         = int64(unsafe.Sizeof(TimestampBucket{})) + 2*renderedTimeBytes
+	missingWindowBytes  = int64(unsafe.Sizeof(MissingWindow{})) + 4*renderedTimeBytes
+	duplicateBytes      = int64(unsafe.Sizeof(DuplicatePoint{})) + renderedTimeBytes
+	timestampGapBytes   = int64(unsafe.Sizeof(TimestampGap{})) + 3*renderedTimeBytes
+	minuteCountBytes    = int64(unsafe.Sizeof(MinuteCount{})) + renderedTimeBytes
+	translatedDateBytes = int64(unsafe.Sizeof(TranslatedDate{})) + 3*renderedTimeBytes
+)
+
+// maxPooledTimes bounds the capacity of slices kept in timesPool, so one
+// huge call doesn't pin its scratch memory for good
+const maxPooledTimes = 10000
+
+// timesPool holds scratch slices bulk operations resolve timestamps into
+var timesPool = sync.Pool{
+	New: func() any { return new([]time.Time) },
+}
+
+// WithBulkBudget bounds the memory a bulk operation may need, in bytes.
+// Operations estimate it from their input before expanding it, and fail
+// with ErrInvalidArgument instead of allocating past the budget. Zero
+// turns the budget off
+func WithBulkBudget(bytes int64) Option {
+	return func(s *timeService) {
+		s.bulkBudget = bytes
+	}
+}
+
+// checkBulkBudget rejects an operation that would hold items of itemBytes
+// each, when they don't fit the budget
+func (s *timeService) checkBulkBudget(operation string, items int64, itemBytes int64) error {
+	if s.bulkBudget <= 0 || items <= s.bulkBudget/itemBytes {
+		return nil
+	}
+	return timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+		"%s would hold %d items of about %s each, over the %s memory budget; split the request or make it coarser",
+		operation, items, formatBytes(itemBytes), formatBytes(s.bulkBudget))
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// getTimes takes a slice of length n from timesPool
+func getTimes(n int) *[]time.Time {
+	p := timesPool.Get().(*[]time.Time)
+	if cap(*p) < n {
+		*p = make([]time.Time, n)
+	}
+	*p = (*p)[:n]
+	return p
+}
+
+// putTimes returns a slice from getTimes to timesPool. Times keep their
+// location, so they are cleared first
+func putTimes(p *[]time.Time) {
+	if cap(*p) > maxPooledTimes {
+		return
+	}
+	clear(*p)
+	*p = (*p)[:0]
+	timesPool.Put(p)
+}
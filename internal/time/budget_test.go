@@ -0,0 +1,67 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_BulkBudget(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithBulkBudget(64<<10))
+
+	day := []Timestamp{RFC3339Timestamp("2026-10-16T00:00:00Z"), RFC3339Timestamp("2026-10-16T23:59:00Z")}
+	result, err := service.BucketTimestamps(BucketTimestampsInput{Timestamps: day, Window: "1h", IncludeEmpty: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Buckets, 24)
+
+	// A day of empty one-second buckets is rejected before any is built
+	_, err = service.BucketTimestamps(BucketTimestampsInput{Timestamps: day, Window: "1s", IncludeEmpty: true})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	assert.ErrorContains(t, err, "bucket_timestamps would hold 86342 items of about")
+	assert.ErrorContains(t, err, "over the 64.0 KiB memory budget")
+
+	// Without empty buckets, only the timestamps count
+	_, err = service.BucketTimestamps(BucketTimestampsInput{Timestamps: day, Window: "1s"})
+	assert.NoError(t, err)
+
+	dates := make([]string, 1000)
+	for i := range dates {
+		dates[i] = "16/10/2026"
+	}
+	_, err = service.TranslateDate(TranslateDateInput{Dates: dates, FromLocale: "en-GB", ToLocale: "en-US"})
+	assert.ErrorContains(t, err, "translate_date would hold 1000 items")
+
+	unbounded := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+	_, err = unbounded.TranslateDate(TranslateDateInput{Dates: dates, FromLocale: "en-GB", ToLocale: "en-US"})
+	assert.NoError(t, err)
+}
+
+func TestResolveTimestamps_Pooled(t *testing.T) {
+	times, release, err := resolveTimestamps([]Timestamp{RFC3339Timestamp("2026-10-16T08:00:00+02:00")})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC), times[0].UTC())
+	release()
+
+	_, _, err = resolveTimestamps([]Timestamp{RFC3339Timestamp("2026-10-16"), RFC3339Timestamp("yesterday")})
+	assert.ErrorContains(t, err, "invalid timestamp at index")
+
+	// Pooled slices come back at the length asked for, without stale times
+	p := getTimes(3)
+	assert.Len(t, *p, 3)
+	for _, ts := range *p {
+		assert.True(t, ts.IsZero())
+	}
+	putTimes(p)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "64.0 MiB", formatBytes(64<<20))
+}
@@ -0,0 +1,138 @@
+package time
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Calendar selects how ParseTime interprets dates
+type Calendar string
+
+const (
+	// CalendarGregorian treats every date as proleptic Gregorian (Go's behavior)
+	CalendarGregorian Calendar = "gregorian"
+	// CalendarJulian treats every date as a Julian calendar date
+	CalendarJulian Calendar = "julian"
+	// CalendarAuto treats dates before the Gregorian cutover as Julian
+	CalendarAuto Calendar = "auto"
+)
+
+// DefaultGregorianCutover is the first Gregorian date in the original 1582 adoption
+var DefaultGregorianCutover = time.Date(1582, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+// unixEpochJDN is the Julian Day Number of 1970-01-01
+const unixEpochJDN = 2440588
+
+// minCalendarYear keeps Julian Day Number arithmetic on positive integers
+const minCalendarYear = -4712
+
+// CalendarInfo reports which calendar applied to a parsed date
+type CalendarInfo struct {
+	Calendar      string `json:"calendar"` // calendar the input date was read in: gregorian or julian
+	PreGregorian  bool   `json:"pre_gregorian"`
+	Cutover       string `json:"cutover"`
+	JulianDate    string `json:"julian_date"`
+	GregorianDate string `json:"gregorian_date"`
+}
+
+// WithGregorianCutover sets the first Gregorian date, e.g. 1752-09-14 for Great Britain
+func WithGregorianCutover(cutover time.Time) Option {
+	return func(s *timeService) {
+		s.gregorianCutover = cutover
+	}
+}
+
+// IsValidCalendar checks if a calendar mode is supported
+func IsValidCalendar(calendar string) bool {
+	switch Calendar(calendar) {
+	case CalendarGregorian, CalendarJulian, CalendarAuto:
+		return true
+	default:
+		return false
+	}
+}
+
+// julianToJDN converts a Julian calendar date to its Julian Day Number
+func julianToJDN(year int, month time.Month, day int) int {
+	a := (14 - int(month)) / 12
+	y := year + 4800 - a
+	m := int(month) + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - 32083
+}
+
+// jdnToJulian converts a Julian Day Number to a Julian calendar date
+func jdnToJulian(jdn int) (year int, month time.Month, day int) {
+	c := jdn + 32082
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+	day = e - (153*m+2)/5 + 1
+	month = time.Month(m + 3 - 12*(m/10))
+	year = d - 4800 + m/10
+	return year, month, day
+}
+
+// gregorianToJDN converts a proleptic Gregorian date to its Julian Day Number
+func gregorianToJDN(year int, month time.Month, day int) int {
+	return int(floorDiv(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix(), 86400)) + unixEpochJDN
+}
+
+// jdnToGregorian converts a Julian Day Number to a proleptic Gregorian date
+func jdnToGregorian(jdn int) (year int, month time.Month, day int) {
+	return time.Unix(int64(jdn-unixEpochJDN)*86400, 0).UTC().Date()
+}
+
+// applyCalendar re-reads the wall-clock date of t in the requested calendar,
+// returning the equivalent Gregorian instant and what was applied. Dates are
+// validated by Go's Gregorian parser first, so Julian-only leap days such as
+// 1500-02-29 cannot be parsed.
+func (s *timeService) applyCalendar(t time.Time, calendar Calendar) (time.Time, *CalendarInfo, error) {
+	year, month, day := t.Date()
+	if year < minCalendarYear {
+		return time.Time{}, nil, timeerrors.Errorf(timeerrors.ErrOutOfRange, "year %d is before the supported calendar range (%d)", year, minCalendarYear)
+	}
+
+	cutover := s.gregorianCutover
+	cutoverJDN := gregorianToJDN(cutover.Date())
+
+	readAsJulian := calendar == CalendarJulian
+	if calendar == CalendarAuto {
+		// Dates written before the cutover are in the old calendar
+		cy, cm, cd := cutover.Date()
+		readAsJulian = time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Before(time.Date(cy, cm, cd, 0, 0, 0, 0, time.UTC))
+	}
+
+	jdn := gregorianToJDN(year, month, day)
+	if readAsJulian {
+		jdn = julianToJDN(year, month, day)
+		if calendar == CalendarAuto && jdn >= cutoverJDN {
+			return time.Time{}, nil, timeerrors.Errorf(timeerrors.ErrParseFailure, "%04d-%02d-%02d falls in the calendar reform gap before %s",
+				year, month, day, cutover.Format("2006-01-02"))
+		}
+	}
+
+	preGregorian := jdn < cutoverJDN
+	if !readAsJulian && !preGregorian && calendar == CalendarGregorian {
+		return t, nil, nil
+	}
+
+	gy, gm, gd := jdnToGregorian(jdn)
+	jy, jm, jd := jdnToJulian(jdn)
+	hour, minute, second := t.Clock()
+	converted := time.Date(gy, gm, gd, hour, minute, second, t.Nanosecond(), t.Location())
+
+	applied := CalendarGregorian
+	if readAsJulian {
+		applied = CalendarJulian
+	}
+
+	return converted, &CalendarInfo{
+		Calendar:      string(applied),
+		PreGregorian:  preGregorian,
+		Cutover:       cutover.Format("2006-01-02"),
+		JulianDate:    fmt.Sprintf("%04d-%02d-%02d", jy, jm, jd),
+		GregorianDate: fmt.Sprintf("%04d-%02d-%02d", gy, gm, gd),
+	}, nil
+}
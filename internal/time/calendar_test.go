@@ -0,0 +1,110 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJulianDayNumbers(t *testing.T) {
+	assert.Equal(t, 2451545, gregorianToJDN(2000, time.January, 1))
+	assert.Equal(t, 2299161, gregorianToJDN(1582, time.October, 15))
+	assert.Equal(t, 2299160, julianToJDN(1582, time.October, 4))
+	assert.Equal(t, 0, julianToJDN(-4712, time.January, 1))
+
+	year, month, day := jdnToJulian(2299160)
+	assert.Equal(t, []int{1582, 10, 4}, []int{year, int(month), day})
+
+	year, month, day = jdnToGregorian(2451545)
+	assert.Equal(t, []int{2000, 1, 1}, []int{year, int(month), day})
+}
+
+func TestTimeService_ParseTime_Calendar(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+	british := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger,
+		WithGregorianCutover(time.Date(1752, time.September, 14, 0, 0, 0, 0, time.UTC)))
+
+	tests := []struct {
+		name     string
+		service  TimeService
+		input    string
+		calendar string
+		rfc3339  string
+		expected *CalendarInfo
+		wantErr  bool
+	}{
+		{
+			name:    "modern gregorian dates are not annotated",
+			service: service,
+			input:   "2023-12-25T15:30:45Z",
+			rfc3339: "2023-12-25T15:30:45Z",
+		},
+		{
+			name:    "proleptic gregorian dates are flagged",
+			service: service,
+			input:   "1500-06-01T12:00:00Z",
+			rfc3339: "1500-06-01T12:00:00Z",
+			expected: &CalendarInfo{Calendar: "gregorian", PreGregorian: true, Cutover: "1582-10-15",
+				JulianDate: "1500-05-22", GregorianDate: "1500-06-01"},
+		},
+		{
+			name:     "julian date converted",
+			service:  service,
+			input:    "1582-10-04T12:00:00Z",
+			calendar: "julian",
+			rfc3339:  "1582-10-14T12:00:00Z",
+			expected: &CalendarInfo{Calendar: "julian", PreGregorian: true, Cutover: "1582-10-15",
+				JulianDate: "1582-10-04", GregorianDate: "1582-10-14"},
+		},
+		{
+			name:     "auto reads dates after the cutover as gregorian",
+			service:  service,
+			input:    "1582-10-15T00:00:00Z",
+			calendar: "auto",
+			rfc3339:  "1582-10-15T00:00:00Z",
+			expected: &CalendarInfo{Calendar: "gregorian", PreGregorian: false, Cutover: "1582-10-15",
+				JulianDate: "1582-10-05", GregorianDate: "1582-10-15"},
+		},
+		{
+			name:     "auto rejects dates in the reform gap",
+			service:  service,
+			input:    "1582-10-10T00:00:00Z",
+			calendar: "auto",
+			wantErr:  true,
+		},
+		{
+			name:     "configurable cutover",
+			service:  british,
+			input:    "1752-09-02T00:00:00Z",
+			calendar: "auto",
+			rfc3339:  "1752-09-13T00:00:00Z",
+			expected: &CalendarInfo{Calendar: "julian", PreGregorian: true, Cutover: "1752-09-14",
+				JulianDate: "1752-09-02", GregorianDate: "1752-09-13"},
+		},
+		{
+			name:     "invalid calendar",
+			service:  service,
+			input:    "2023-12-25T15:30:45Z",
+			calendar: "lunar",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.service.ParseTime(ParseTimeInput{TimeString: tt.input, Format: "RFC3339", Calendar: tt.calendar})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.rfc3339, result.RFC3339)
+			assert.Equal(t, tt.expected, result.Calendar)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package time
+
+import (
+	"time"
+)
+
+// Clock provides the current time to the service
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the system wall clock
+type systemClock struct{}
+
+// Now returns the current system time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same instant, used for deterministic replays and tests
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now returns the frozen instant
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+// Option configures optional time service behavior
+type Option func(*timeService)
+
+// WithClock overrides the clock used to read the current time
+func WithClock(clock Clock) Option {
+	return func(s *timeService) {
+		s.clock = clock
+	}
+}
@@ -0,0 +1,134 @@
+package time
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// defaultSkewTolerance is the skew below which clocks are reported in sync
+const defaultSkewTolerance = time.Second
+
+// ClockSample is one completed compare_clock exchange, used to estimate the
+// round trip and correct the skew for network latency (NTP-style)
+type ClockSample struct {
+	ClientTime         Timestamp `json:"client_time"`          // client clock when the request was sent
+	ServerReceiveTime  Timestamp `json:"server_receive_time"`  // from the previous response
+	ServerTransmitTime Timestamp `json:"server_transmit_time"` // from the previous response
+	ClientReceiveTime  Timestamp `json:"client_receive_time"`  // client clock when the response arrived
+}
+
+// CompareClockInput represents input for comparing client and server clocks
+type CompareClockInput struct {
+	ClientTime  Timestamp    `json:"client_time"`            // client's current time
+	Previous    *ClockSample `json:"previous,omitempty"`     // optional completed exchange for round-trip estimation
+	ToleranceMs *int64       `json:"tolerance_ms,omitempty"` // skew considered in sync, defaults to 1000
+}
+
+// CompareClockResult represents the measured clock skew between client and server
+type CompareClockResult struct {
+	ClientTime         string `json:"client_time"`
+	ServerReceiveTime  string `json:"server_receive_time"`
+	ServerTransmitTime string `json:"server_transmit_time"`
+	// SkewMs is server time minus client time. Without a previous sample it
+	// includes the one-way request latency.
+	SkewMs      int64  `json:"skew_ms"`
+	Skew        string `json:"skew"`
+	ClientClock string `json:"client_clock"` // ahead, behind or in_sync
+	// OffsetMs and RoundTripMs are only set when a previous sample is given
+	OffsetMs    *int64 `json:"offset_ms,omitempty"`
+	RoundTripMs *int64 `json:"round_trip_ms,omitempty"`
+	ToleranceMs int64  `json:"tolerance_ms"`
+}
+
+// CompareClock measures the skew between the client's clock and the server's
+func (s *timeService) CompareClock(input CompareClockInput) (CompareClockResult, error) {
+	receivedAt := s.clock.Now()
+
+	clientTime, err := input.ClientTime.Resolve()
+	if err != nil {
+		return CompareClockResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid client_time: %w", err)
+	}
+
+	tolerance := defaultSkewTolerance
+	if input.ToleranceMs != nil {
+		if *input.ToleranceMs < 0 {
+			return CompareClockResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "tolerance_ms cannot be negative")
+		}
+		tolerance = time.Duration(*input.ToleranceMs) * time.Millisecond
+	}
+
+	skew := receivedAt.Sub(clientTime)
+	result := CompareClockResult{
+		ClientTime:        clientTime.UTC().Format(time.RFC3339Nano),
+		ServerReceiveTime: receivedAt.UTC().Format(time.RFC3339Nano),
+		SkewMs:            skew.Milliseconds(),
+		Skew:              skew.String(),
+		ToleranceMs:       tolerance.Milliseconds(),
+	}
+
+	if input.Previous != nil {
+		offset, roundTrip, err := ntpEstimate(*input.Previous)
+		if err != nil {
+			return CompareClockResult{}, err
+		}
+		offsetMs, roundTripMs := offset.Milliseconds(), roundTrip.Milliseconds()
+		result.OffsetMs = &offsetMs
+		result.RoundTripMs = &roundTripMs
+		skew = offset
+	}
+
+	// Skew is server minus client, so a positive skew means the client is behind
+	switch {
+	case skew.Abs() <= tolerance:
+		result.ClientClock = "in_sync"
+	case skew > 0:
+		result.ClientClock = "behind"
+	default:
+		result.ClientClock = "ahead"
+	}
+
+	result.ServerTransmitTime = s.clock.Now().UTC().Format(time.RFC3339Nano)
+
+	s.logger.Debug("Compared clocks",
+		slog.Int64("skew_ms", result.SkewMs),
+		slog.String("client_clock", result.ClientClock))
+
+	return result, nil
+}
+
+// ntpEstimate computes the clock offset and round-trip delay from a completed
+// exchange: offset = ((t1-t0) + (t2-t3)) / 2, delay = (t3-t0) - (t2-t1)
+func ntpEstimate(sample ClockSample) (offset, roundTrip time.Duration, err error) {
+	fields := []struct {
+		name string
+		ts   Timestamp
+	}{
+		{"previous.client_time", sample.ClientTime},
+		{"previous.server_receive_time", sample.ServerReceiveTime},
+		{"previous.server_transmit_time", sample.ServerTransmitTime},
+		{"previous.client_receive_time", sample.ClientReceiveTime},
+	}
+
+	var t [4]time.Time
+	for i, f := range fields {
+		if t[i], err = f.ts.Resolve(); err != nil {
+			return 0, 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s: %w", f.name, err)
+		}
+	}
+
+	if t[3].Before(t[0]) {
+		return 0, 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "previous.client_receive_time is before previous.client_time")
+	}
+	if t[2].Before(t[1]) {
+		return 0, 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "previous.server_transmit_time is before previous.server_receive_time")
+	}
+
+	offset = (t[1].Sub(t[0]) + t[2].Sub(t[3])) / 2
+	roundTrip = t[3].Sub(t[0]) - t[2].Sub(t[1])
+	if roundTrip < 0 {
+		roundTrip = 0
+	}
+	return offset, roundTrip, nil
+}
@@ -0,0 +1,104 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_CompareClock(t *testing.T) {
+	logger := newTestLogger(t)
+	serverNow := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: serverNow}))
+
+	ms := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name        string
+		input       CompareClockInput
+		skewMs      int64
+		clientClock string
+		offsetMs    *int64
+		roundTripMs *int64
+		wantErr     bool
+	}{
+		{
+			name:        "in sync",
+			input:       CompareClockInput{ClientTime: TimestampFromTime(serverNow.Add(-200 * time.Millisecond))},
+			skewMs:      200,
+			clientClock: "in_sync",
+		},
+		{
+			name:        "client behind",
+			input:       CompareClockInput{ClientTime: RFC3339Timestamp("2023-12-25T15:25:45Z")},
+			skewMs:      300000,
+			clientClock: "behind",
+		},
+		{
+			name:        "client ahead with tight tolerance",
+			input:       CompareClockInput{ClientTime: EpochTimestamp(serverNow.UnixMilli()+50, EpochMilliseconds), ToleranceMs: ms(10)},
+			skewMs:      -50,
+			clientClock: "ahead",
+		},
+		{
+			name: "previous sample corrects for latency",
+			input: CompareClockInput{
+				ClientTime: TimestampFromTime(serverNow.Add(-5*time.Second - 100*time.Millisecond)),
+				Previous: &ClockSample{
+					// Client is 5s behind, 100ms each way, 10ms processing
+					ClientTime:         TimestampFromTime(serverNow.Add(-time.Minute - 5*time.Second)),
+					ServerReceiveTime:  TimestampFromTime(serverNow.Add(-time.Minute + 100*time.Millisecond)),
+					ServerTransmitTime: TimestampFromTime(serverNow.Add(-time.Minute + 110*time.Millisecond)),
+					ClientReceiveTime:  TimestampFromTime(serverNow.Add(-time.Minute - 5*time.Second + 210*time.Millisecond)),
+				},
+			},
+			skewMs:      5100,
+			clientClock: "behind",
+			offsetMs:    ms(5000),
+			roundTripMs: ms(200),
+		},
+		{
+			name: "previous sample out of order",
+			input: CompareClockInput{
+				ClientTime: TimestampFromTime(serverNow),
+				Previous: &ClockSample{
+					ClientTime:         TimestampFromTime(serverNow),
+					ServerReceiveTime:  TimestampFromTime(serverNow),
+					ServerTransmitTime: TimestampFromTime(serverNow),
+					ClientReceiveTime:  TimestampFromTime(serverNow.Add(-time.Second)),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing client time",
+			input:   CompareClockInput{},
+			wantErr: true,
+		},
+		{
+			name:    "negative tolerance",
+			input:   CompareClockInput{ClientTime: TimestampFromTime(serverNow), ToleranceMs: ms(-1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.CompareClock(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.skewMs, result.SkewMs)
+			assert.Equal(t, tt.clientClock, result.ClientClock)
+			assert.Equal(t, tt.offsetMs, result.OffsetMs)
+			assert.Equal(t, tt.roundTripMs, result.RoundTripMs)
+			assert.Equal(t, "2023-12-25T15:30:45Z", result.ServerReceiveTime)
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package time
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// cronDSTHorizon is how far ahead runs are checked for DST changes
+	cronDSTHorizon = 366 * 24 * time.Hour
+	// cronNextRunYears bounds the search for next runs, which for
+	// schedules such as February 29 on a Monday can be years away
+	cronNextRunYears = 30
+	maxListedDSTRuns = 10
+)
+
+// cronWallLayout renders wall clock times that may not exist in the zone
+const cronWallLayout = "2006-01-02T15:04"
+
+// cronMacros are the @ shorthands Vixie cron and its descendants accept
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	cronDayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+// cronField describes one of the five schedule fields
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonthNames},
+	// 7 is accepted as Sunday and folded onto 0
+	{name: "day of week", min: 0, max: 7, names: cronDayNames},
+}
+
+// cronSchedule is a parsed five-field cron schedule. Each field is a bit
+// set of the values it matches
+type cronSchedule struct {
+	minutes, hours, doms, months, dows uint64
+	// domStar and dowStar record a day field starting with * (or ?). As in
+	// Vixie cron, when neither day field is, a day matching either fires
+	domStar, dowStar bool
+}
+
+// cronRun is one scheduled wall clock time and the instants it occurs at
+// in the schedule's zone: none when a DST change skips it, two when the
+// clocks fall back over it
+type cronRun struct {
+	wall     time.Time // wall clock time, in UTC
+	instants []time.Time
+}
+
+// parseCron reads a five-field schedule or an @ macro
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		expanded, ok := cronMacros[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("unknown macro %s (must be one of: @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly)", expr)
+		}
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cronFields[i].name, err)
+		}
+		sets[i] = set
+	}
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &cronSchedule{
+		minutes: sets[0], hours: sets[1], doms: sets[2], months: sets[3], dows: sets[4],
+		domStar: strings.HasPrefix(fields[2], "*") || fields[2] == "?",
+		dowStar: strings.HasPrefix(fields[4], "*") || fields[4] == "?",
+	}, nil
+}
+
+// parseCronField reads a comma-separated list of *, values, ranges and
+// steps into a bit set
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = spec.min, spec.max
+			if spec.names != nil && spec.max == 7 {
+				hi = 6
+			}
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(loPart, spec); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(hiPart, spec); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("range %s runs backwards", rangePart)
+			}
+		default:
+			var err error
+			if lo, err = cronValue(rangePart, spec); err != nil {
+				return 0, err
+			}
+			hi = lo
+			// N/step means N through the end of the field
+			if hasStep {
+				hi = spec.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+func cronValue(value string, spec cronField) (int, error) {
+	if n, ok := spec.names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, spec.min, spec.max)
+	}
+	return n, nil
+}
+
+// matchesDay reports whether the schedule fires on a date
+func (c *cronSchedule) matchesDay(date time.Time) bool {
+	if c.months&(1<<int(date.Month())) == 0 {
+		return false
+	}
+	dom := c.doms&(1<<date.Day()) != 0
+	dow := c.dows&(1<<int(date.Weekday())) != 0
+	switch {
+	case c.domStar || c.dowStar:
+		return dom && dow
+	default:
+		return dom || dow
+	}
+}
+
+// neverFires reports whether no date can match, such as February 30
+func (c *cronSchedule) neverFires() bool {
+	if c.domStar || !c.dowStar {
+		return false
+	}
+	for month := 1; month <= 12; month++ {
+		if c.months&(1<<month) == 0 {
+			continue
+		}
+		// February 29 counts: it fires in leap years
+		last := daysInMonth(2024, time.Month(month))
+		if c.doms&(1<<(last+1)-1) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// leapDayOnly reports whether the schedule only fires on February 29
+func (c *cronSchedule) leapDayOnly() bool {
+	return !c.domStar && c.dowStar && c.months&^(1<<2) == 0 && c.doms == 1<<29
+}
+
+// runsPerDay is the number of times the schedule fires on a matching day
+func (c *cronSchedule) runsPerDay() int {
+	return bits.OnesCount64(c.hours) * bits.OnesCount64(c.minutes)
+}
+
+// each calls yield with each run from from's local date in loc onward,
+// in order, until yield returns false or a day after until is reached.
+// Runs whose instants are all before from are left out
+func (c *cronSchedule) each(from, until time.Time, loc *time.Location, yield func(cronRun) bool) {
+	local := from.In(loc)
+	fromWall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	untilWall := until.In(loc)
+	last := time.Date(untilWall.Year(), untilWall.Month(), untilWall.Day(), 0, 0, 0, 0, time.UTC)
+
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !c.matchesDay(day) {
+			continue
+		}
+		// Days without an offset change take the fast path
+		_, startOffset := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc).Zone()
+		_, endOffset := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc).Zone()
+		for hour := 0; hour < 24; hour++ {
+			if c.hours&(1<<hour) == 0 {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if c.minutes&(1<<minute) == 0 {
+					continue
+				}
+				wall := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+				run := cronRun{wall: wall}
+				if startOffset == endOffset {
+					run.instants = []time.Time{time.Date(wall.Year(), wall.Month(), wall.Day(), hour, minute, 0, 0, loc)}
+				} else {
+					run.instants = wallInstants(wall, loc)
+				}
+				switch {
+				case len(run.instants) == 0 && wall.Before(fromWall):
+					continue
+				case len(run.instants) > 0 && run.instants[len(run.instants)-1].Before(from):
+					continue
+				}
+				if !yield(run) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// cronScan is what scanning a schedule's upcoming runs finds
+type cronScan struct {
+	next []time.Time
+	// skipped and repeated are the wall clock runs within cronDSTHorizon
+	// that a DST change skips or that occur twice
+	skipped, repeated []string
+}
+
+// scan finds the next runs from from, up to runs of them, and the runs a
+// DST change affects within a year
+func (c *cronSchedule) scan(from time.Time, loc *time.Location, runs int) cronScan {
+	var result cronScan
+	until := from.Add(cronDSTHorizon).In(loc)
+	dstUntil := time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), 0, 0, time.UTC)
+	c.each(from, from.AddDate(cronNextRunYears, 0, 0), loc, func(run cronRun) bool {
+		beyondDST := !run.wall.Before(dstUntil)
+		switch len(run.instants) {
+		case 0:
+			if !beyondDST && len(result.skipped) < maxListedDSTRuns {
+				result.skipped = append(result.skipped, run.wall.Format(cronWallLayout))
+			}
+		case 2:
+			if !beyondDST && len(result.repeated) < maxListedDSTRuns {
+				result.repeated = append(result.repeated, run.wall.Format(cronWallLayout))
+			}
+		}
+		if len(result.next) < runs {
+			for _, instant := range run.instants {
+				if !instant.Before(from) {
+					result.next = append(result.next, instant)
+					break
+				}
+			}
+		}
+		// Keep scanning the DST horizon once the next runs are known
+		return len(result.next) < runs || !beyondDST
+	})
+	return result
+}
+
+// wallInstants returns the instants a wall clock time, given in UTC,
+// occurs at in loc: none in a DST gap, two when the clocks fall back
+func wallInstants(wall time.Time, loc *time.Location) []time.Time {
+	var instants []time.Time
+	seen := make(map[int]bool)
+	for _, probe := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := wall.Add(probe).In(loc).Zone()
+		if seen[offset] {
+			continue
+		}
+		seen[offset] = true
+		candidate := wall.Add(-time.Duration(offset) * time.Second)
+		if _, actual := candidate.In(loc).Zone(); actual == offset {
+			instants = append(instants, candidate.In(loc))
+		}
+	}
+	sort.Slice(instants, func(i, j int) bool { return instants[i].Before(instants[j]) })
+	return instants
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
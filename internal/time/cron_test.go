@@ -0,0 +1,96 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		minutes uint64
+		dows    uint64
+	}{
+		{"every minute", "* * * * *", 1<<60 - 1, 1<<7 - 1},
+		{"list and range", "0,30 9-17 * * 1-5", 1 | 1<<30, 0b0111110},
+		{"step over a range", "*/15 * * * *", 1 | 1<<15 | 1<<30 | 1<<45, 1<<7 - 1},
+		{"start with step", "50/5 * * * *", 1<<50 | 1<<55, 1<<7 - 1},
+		{"names", "0 0 * JAN-MAR sun,SAT", 1, 1 | 1<<6},
+		{"7 is Sunday", "0 0 * * 7", 1, 1},
+		{"macro", "@weekly", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.minutes, schedule.minutes)
+			assert.Equal(t, tt.dows, schedule.dows)
+		})
+	}
+
+	errorTests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * *", "expected 5 fields (minute hour day-of-month month day-of-week), got 4"},
+		{"60 * * * *", "minute: value 60 out of range 0-59"},
+		{"* 17-9 * * *", "hour: range 17-9 runs backwards"},
+		{"*/0 * * * *", `minute: invalid step "0"`},
+		{"* * * FOO *", `month: invalid value "FOO"`},
+		{"@every 5m", "unknown macro @every 5m (must be one of: @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly)"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parseCron(tt.expr)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestCronSchedule_MatchesDay(t *testing.T) {
+	// Christmas 2023 is a Monday
+	christmas := time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	either, err := parseCron("0 0 1 * 1")
+	require.NoError(t, err)
+	assert.True(t, either.matchesDay(christmas), "restricted day fields match either one")
+
+	both, err := parseCron("0 0 */10 * 1")
+	require.NoError(t, err)
+	assert.False(t, both.matchesDay(christmas), "a day field starting with * requires both")
+}
+
+func TestCronSchedule_Each(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	schedule, err := parseCron("30 1,2 * * *")
+	require.NoError(t, err)
+
+	var runs []string
+	count := 0
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, newYork)
+	schedule.each(from, from.Add(24*time.Hour), newYork, func(run cronRun) bool {
+		runs = append(runs, run.wall.Format(cronWallLayout))
+		for _, instant := range run.instants {
+			runs = append(runs, "  "+instant.Format(time.RFC3339))
+		}
+		count++
+		return count < 3
+	})
+	assert.Equal(t, []string{
+		"2024-03-10T01:30",
+		"  2024-03-10T01:30:00-05:00",
+		"2024-03-10T02:30",
+		"2024-03-11T01:30",
+		"  2024-03-11T01:30:00-04:00",
+	}, runs)
+
+	instants := wallInstants(time.Date(2024, 11, 3, 1, 30, 0, 0, time.UTC), newYork)
+	require.Len(t, instants, 2)
+	assert.Equal(t, "2024-11-03T01:30:00-04:00", instants[0].Format(time.RFC3339))
+	assert.Equal(t, "2024-11-03T01:30:00-05:00", instants[1].Format(time.RFC3339))
+}
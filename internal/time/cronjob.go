@@ -0,0 +1,160 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// CronJob timezone sources
+const (
+	CronJobZoneSpec       = "spec.timeZone"
+	CronJobZoneController = "controller"
+)
+
+// CheckCronJobInput represents input for explaining a Kubernetes CronJob
+// schedule
+type CheckCronJobInput struct {
+	Schedule string `json:"schedule"` // spec.schedule
+	// TimeZone is spec.timeZone. When unset, the schedule follows the
+	// timezone of kube-controller-manager
+	TimeZone string `json:"time_zone,omitempty"`
+	// ControllerTimezone is kube-controller-manager's timezone, defaults to
+	// UTC as on most clusters
+	ControllerTimezone string `json:"controller_timezone,omitempty"`
+	// ViewerTimezone is the zone runs are also shown in, defaults to the
+	// server default
+	ViewerTimezone string    `json:"viewer_timezone,omitempty"`
+	From           Timestamp `json:"from,omitempty"` // defaults to now
+	Runs           int       `json:"runs,omitempty"` // defaults to 5, at most 50
+}
+
+// CronJobRun is one run of a CronJob, as an instant in each zone
+type CronJobRun struct {
+	Local  string `json:"local"` // in the effective timezone
+	UTC    string `json:"utc"`
+	Viewer string `json:"viewer"`
+}
+
+// CheckCronJobResult explains when a CronJob fires
+type CheckCronJobResult struct {
+	Schedule          string `json:"schedule"`
+	EffectiveTimezone string `json:"effective_timezone"`
+	// TimezoneSource is spec.timeZone or controller
+	TimezoneSource string       `json:"timezone_source"`
+	ViewerTimezone string       `json:"viewer_timezone"`
+	NextRuns       []CronJobRun `json:"next_runs"`
+	NeverFires     bool         `json:"never_fires"`
+	// DSTSkipped and DSTRepeated are the wall clock times within a year
+	// that a DST change skips or that occur twice in the effective zone
+	DSTSkipped  []string `json:"dst_skipped,omitempty"`
+	DSTRepeated []string `json:"dst_repeated,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+	Summary     string   `json:"summary"`
+}
+
+// CheckCronJob explains when a Kubernetes CronJob actually fires, given
+// its schedule, spec.timeZone and the controller's timezone, and flags the
+// runs a DST change skips or repeats
+func (s *timeService) CheckCronJob(input CheckCronJobInput) (CheckCronJobResult, error) {
+	schedule := strings.TrimSpace(input.Schedule)
+	if schedule == "" {
+		return CheckCronJobResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "schedule cannot be empty")
+	}
+	runs, err := boundedCount("runs", input.Runs, defaultCronRuns, maxCronRuns)
+	if err != nil {
+		return CheckCronJobResult{}, err
+	}
+
+	var warnings []string
+	// A CRON_TZ= or TZ= prefix was never supported and is rejected by
+	// current API servers; spec.timeZone replaces it
+	if prefix, rest, ok := strings.Cut(schedule, " "); ok && (strings.HasPrefix(prefix, "CRON_TZ=") || strings.HasPrefix(prefix, "TZ=")) {
+		_, zone, _ := strings.Cut(prefix, "=")
+		warnings = append(warnings, fmt.Sprintf("%s in spec.schedule is not supported and is rejected by current API servers; remove it and set spec.timeZone: %s", prefix, zone))
+		schedule = strings.TrimSpace(rest)
+	}
+	parsed, err := parseCron(schedule)
+	if err != nil {
+		return CheckCronJobResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid schedule: %w", err)
+	}
+
+	zone, source := input.TimeZone, CronJobZoneSpec
+	if zone == "" {
+		zone, source = defaultString(input.ControllerTimezone, "UTC"), CronJobZoneController
+		warnings = append(warnings, fmt.Sprintf("spec.timeZone is unset, so the schedule follows kube-controller-manager's timezone (%s); set spec.timeZone to pin it", zone))
+	}
+	if source == CronJobZoneSpec && (zone == "Local" || strings.HasPrefix(zone, "/")) {
+		return CheckCronJobResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid time_zone %s (spec.timeZone must be a tz database name such as Europe/Berlin)", zone)
+	}
+	loc, err := s.loadLocation(zone)
+	if err != nil {
+		return CheckCronJobResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", zone, err)
+	}
+	viewerZone := defaultString(input.ViewerTimezone, s.defaultTimezone)
+	viewerLoc, err := s.loadLocation(viewerZone)
+	if err != nil {
+		return CheckCronJobResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", viewerZone, err)
+	}
+
+	from := s.clock.Now()
+	if !input.From.IsZero() {
+		if from, err = input.From.Resolve(); err != nil {
+			return CheckCronJobResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid from: %w", err)
+		}
+	}
+
+	result := CheckCronJobResult{
+		Schedule:          schedule,
+		EffectiveTimezone: zone,
+		TimezoneSource:    source,
+		ViewerTimezone:    viewerZone,
+		NextRuns:          []CronJobRun{},
+	}
+	if parsed.neverFires() {
+		result.NeverFires = true
+		result.Warnings = append(warnings, "no date matches the day of month and month fields, so the job never runs")
+		result.Summary = fmt.Sprintf("%s never fires", schedule)
+		return result, nil
+	}
+	if !parsed.domStar && !parsed.dowStar {
+		warnings = append(warnings, "both day fields are restricted, so the job runs on days matching either one, not both")
+	}
+
+	scan := parsed.scan(from.Truncate(time.Minute), loc, runs)
+	for _, run := range scan.next {
+		result.NextRuns = append(result.NextRuns, CronJobRun{
+			Local:  run.Format(time.RFC3339),
+			UTC:    run.UTC().Format(time.RFC3339),
+			Viewer: run.In(viewerLoc).Format(time.RFC3339),
+		})
+	}
+	result.DSTSkipped, result.DSTRepeated = scan.skipped, scan.repeated
+	// The controller computes runs with robfig/cron, which steps past a
+	// wall time the clocks skip rather than running it late
+	if len(scan.skipped) > 0 {
+		warnings = append(warnings, "runs in a DST gap are skipped by the CronJob controller, not run late; move them out of the changeover hour or use a zone without DST")
+	}
+	if len(scan.repeated) > 0 {
+		warnings = append(warnings, "runs in the hour repeated when clocks go back can fire twice; make the job idempotent or move it out of the changeover hour")
+	}
+	result.Warnings = warnings
+
+	switch {
+	case len(result.NextRuns) == 0:
+		result.Summary = fmt.Sprintf("%s has no runs in the next %d years in %s", schedule, cronNextRunYears, zone)
+	default:
+		result.Summary = fmt.Sprintf("%s in %s next fires at %s (%s UTC)", schedule, zone, result.NextRuns[0].Local, result.NextRuns[0].UTC)
+	}
+
+	s.logger.Debug("Checked CronJob schedule",
+		slog.String("schedule", schedule),
+		slog.String("timezone", zone),
+		slog.Int("dst_skipped", len(scan.skipped)),
+		slog.Int("dst_repeated", len(scan.repeated)))
+
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_CheckCronJob(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	t.Run("spec.timeZone with DST", func(t *testing.T) {
+		result, err := service.CheckCronJob(CheckCronJobInput{
+			Schedule:       "30 2 * * *",
+			TimeZone:       "America/New_York",
+			ViewerTimezone: "Europe/Berlin",
+			From:           RFC3339Timestamp("2024-03-09T12:00:00Z"),
+			Runs:           2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, CronJobZoneSpec, result.TimezoneSource)
+		assert.Equal(t, []CronJobRun{
+			{Local: "2024-03-11T02:30:00-04:00", UTC: "2024-03-11T06:30:00Z", Viewer: "2024-03-11T07:30:00+01:00"},
+			{Local: "2024-03-12T02:30:00-04:00", UTC: "2024-03-12T06:30:00Z", Viewer: "2024-03-12T07:30:00+01:00"},
+		}, result.NextRuns)
+		assert.Equal(t, []string{"2024-03-10T02:30", "2025-03-09T02:30"}, result.DSTSkipped)
+		assert.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "skipped by the CronJob controller")
+	})
+
+	t.Run("controller timezone", func(t *testing.T) {
+		result, err := service.CheckCronJob(CheckCronJobInput{Schedule: "0 9 * * MON-FRI", Runs: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "UTC", result.EffectiveTimezone)
+		assert.Equal(t, CronJobZoneController, result.TimezoneSource)
+		assert.Equal(t, "2023-12-26T09:00:00Z", result.NextRuns[0].UTC)
+		assert.Empty(t, result.DSTSkipped)
+		assert.Equal(t, []string{"spec.timeZone is unset, so the schedule follows kube-controller-manager's timezone (UTC); set spec.timeZone to pin it"}, result.Warnings)
+		assert.Equal(t, "0 9 * * MON-FRI in UTC next fires at 2023-12-26T09:00:00Z (2023-12-26T09:00:00Z UTC)", result.Summary)
+	})
+
+	t.Run("CRON_TZ prefix", func(t *testing.T) {
+		result, err := service.CheckCronJob(CheckCronJobInput{Schedule: "CRON_TZ=Europe/London 30 1 * * *", TimeZone: "Europe/London"})
+		require.NoError(t, err)
+		assert.Equal(t, "30 1 * * *", result.Schedule)
+		assert.Equal(t, "CRON_TZ=Europe/London in spec.schedule is not supported and is rejected by current API servers; remove it and set spec.timeZone: Europe/London", result.Warnings[0])
+		assert.Equal(t, []string{"2024-10-27T01:30"}, result.DSTRepeated)
+	})
+
+	t.Run("never fires", func(t *testing.T) {
+		result, err := service.CheckCronJob(CheckCronJobInput{Schedule: "0 0 31 4 *", TimeZone: "UTC"})
+		require.NoError(t, err)
+		assert.True(t, result.NeverFires)
+		assert.Empty(t, result.NextRuns)
+	})
+
+	tests := []struct {
+		name  string
+		input CheckCronJobInput
+		err   error
+		want  string
+	}{
+		{"empty schedule", CheckCronJobInput{}, timeerrors.ErrInvalidArgument, "schedule cannot be empty"},
+		{"invalid schedule", CheckCronJobInput{Schedule: "0 25 * * *"}, timeerrors.ErrInvalidArgument, "invalid schedule: hour: value 25 out of range 0-23"},
+		{"Local time zone", CheckCronJobInput{Schedule: "@daily", TimeZone: "Local"}, timeerrors.ErrInvalidTimezone, "invalid time_zone Local (spec.timeZone must be a tz database name such as Europe/Berlin)"},
+		{"unknown time zone", CheckCronJobInput{Schedule: "@daily", TimeZone: "Mars/Olympus"}, timeerrors.ErrInvalidTimezone, "invalid timezone Mars/Olympus: unknown time zone Mars/Olympus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CheckCronJob(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Crontab audit limits
+const (
+	defaultCronRuns    = 5
+	maxCronRuns        = 50
+	defaultOverlapDays = 7
+	maxOverlapDays     = 31
+	maxCrontabEntries  = 200
+)
+
+var (
+	crontabEnvPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+	// crontabPercent finds a % not escaped with a backslash
+	crontabPercent = regexp.MustCompile(`(^|[^\\])%`)
+)
+
+// AuditCrontabInput represents input for reviewing a crontab
+type AuditCrontabInput struct {
+	Crontab string `json:"crontab"`
+	// Timezone is the zone entries run in until a CRON_TZ line changes it,
+	// defaults to the server default
+	Timezone string    `json:"timezone,omitempty"`
+	From     Timestamp `json:"from,omitempty"` // defaults to now
+	Runs     int       `json:"runs,omitempty"` // next runs listed per entry, defaults to 5, at most 50
+	// System reads the /etc/crontab format, with a user before the command
+	System bool `json:"system,omitempty"`
+	// OverlapDays is how far ahead entries are compared for runs in the
+	// same minute, defaults to 7, at most 31
+	OverlapDays int `json:"overlap_days,omitempty"`
+}
+
+// CrontabEntry is the audit of one crontab line
+type CrontabEntry struct {
+	Line     int      `json:"line"`
+	Schedule string   `json:"schedule"`
+	User     string   `json:"user,omitempty"`
+	Command  string   `json:"command"`
+	Timezone string   `json:"timezone"`
+	NextRuns []string `json:"next_runs"`
+	// NeverFires is set for schedules no date matches, such as 0 0 30 2 *
+	NeverFires bool `json:"never_fires"`
+	// DSTSkipped and DSTRepeated are the wall clock times within a year
+	// that a DST change skips or that occur twice
+	DSTSkipped  []string `json:"dst_skipped,omitempty"`
+	DSTRepeated []string `json:"dst_repeated,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// CrontabOverlap is a pair of entries that fire in the same minute
+type CrontabOverlap struct {
+	Lines       []int  `json:"lines"`
+	SharedRuns  int    `json:"shared_runs"`
+	FirstShared string `json:"first_shared"`
+}
+
+// AuditCrontabResult represents the review of a crontab
+type AuditCrontabResult struct {
+	Timezone string           `json:"timezone"`
+	From     string           `json:"from"`
+	Entries  []CrontabEntry   `json:"entries"`
+	Overlaps []CrontabOverlap `json:"overlaps"`
+	// Warnings are about the crontab as a whole, such as TZ lines
+	Warnings []string `json:"warnings,omitempty"`
+	// Issues counts entries with an error, a schedule that never fires or
+	// DST-affected runs, plus overlaps
+	Issues int `json:"issues"`
+}
+
+// auditedEntry keeps an entry's parsed schedule for the overlap check
+type auditedEntry struct {
+	schedule *cronSchedule
+	loc      *time.Location
+}
+
+// AuditCrontab reviews each entry of a crontab: its next runs, whether it
+// can fire at all, runs a DST change skips or repeats, and entries firing
+// in the same minute
+func (s *timeService) AuditCrontab(input AuditCrontabInput) (AuditCrontabResult, error) {
+	if strings.TrimSpace(input.Crontab) == "" {
+		return AuditCrontabResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "crontab cannot be empty")
+	}
+	runs, err := boundedCount("runs", input.Runs, defaultCronRuns, maxCronRuns)
+	if err != nil {
+		return AuditCrontabResult{}, err
+	}
+	overlapDays, err := boundedCount("overlap_days", input.OverlapDays, defaultOverlapDays, maxOverlapDays)
+	if err != nil {
+		return AuditCrontabResult{}, err
+	}
+
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return AuditCrontabResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	from := s.clock.Now()
+	if !input.From.IsZero() {
+		if from, err = input.From.Resolve(); err != nil {
+			return AuditCrontabResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid from: %w", err)
+		}
+	}
+	from = from.Truncate(time.Minute)
+
+	result := AuditCrontabResult{
+		Timezone: timezone,
+		From:     from.In(loc).Format(time.RFC3339),
+		Entries:  []CrontabEntry{},
+		Overlaps: []CrontabOverlap{},
+	}
+	entryZone, entryLoc := timezone, loc
+	var audited []auditedEntry
+	for n, line := range strings.Split(input.Crontab, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := crontabEnvPattern.FindStringSubmatch(line); match != nil {
+			name, value := match[1], strings.Trim(strings.TrimSpace(match[2]), `"'`)
+			switch name {
+			case "CRON_TZ":
+				zoneLoc, err := s.loadLocation(value)
+				if err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("line %d: invalid CRON_TZ %s, entries stay in %s", n+1, value, entryZone))
+					continue
+				}
+				entryZone, entryLoc = value, zoneLoc
+			case "TZ":
+				result.Warnings = append(result.Warnings, fmt.Sprintf("line %d: TZ only sets the environment of commands; use CRON_TZ to change the zone schedules run in", n+1))
+			}
+			continue
+		}
+		if len(result.Entries) == maxCrontabEntries {
+			return AuditCrontabResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "crontab has more than %d entries", maxCrontabEntries)
+		}
+
+		entry, schedule := auditCrontabLine(n+1, line, input.System, entryZone, entryLoc, from, runs)
+		result.Entries = append(result.Entries, entry)
+		if schedule != nil && !entry.NeverFires {
+			audited = append(audited, auditedEntry{schedule: schedule, loc: entryLoc})
+		} else {
+			audited = append(audited, auditedEntry{})
+		}
+	}
+
+	result.Overlaps = crontabOverlaps(result.Entries, audited, from, from.Add(time.Duration(overlapDays)*24*time.Hour), loc)
+	for _, entry := range result.Entries {
+		if entry.Error != "" || entry.NeverFires || len(entry.DSTSkipped) > 0 || len(entry.DSTRepeated) > 0 {
+			result.Issues++
+		}
+	}
+	result.Issues += len(result.Overlaps)
+
+	s.logger.Debug("Audited crontab",
+		slog.Int("entries", len(result.Entries)),
+		slog.Int("issues", result.Issues))
+
+	return result, nil
+}
+
+// auditCrontabLine reviews one entry. The schedule is nil when the line
+// has no time-based schedule
+func auditCrontabLine(line int, text string, system bool, timezone string, loc *time.Location, from time.Time, runs int) (CrontabEntry, *cronSchedule) {
+	entry := CrontabEntry{Line: line, Timezone: timezone, NextRuns: []string{}}
+
+	scheduleFields := 5
+	if strings.HasPrefix(text, "@") {
+		scheduleFields = 1
+	}
+	fields, rest := splitFields(text, scheduleFields)
+	entry.Schedule = strings.Join(fields, " ")
+	if system {
+		var user []string
+		user, rest = splitFields(rest, 1)
+		if len(user) == 1 {
+			entry.User = user[0]
+		}
+	}
+	entry.Command = rest
+	if entry.Command == "" {
+		entry.Error = "missing command"
+		return entry, nil
+	}
+	if crontabPercent.MatchString(entry.Command) {
+		entry.Warnings = append(entry.Warnings, "unescaped % in the command is turned into a newline, and the rest is passed on stdin; escape it as \\%")
+	}
+
+	if strings.EqualFold(entry.Schedule, "@reboot") {
+		entry.Warnings = append(entry.Warnings, "runs once when the cron daemon starts, not on a schedule")
+		return entry, nil
+	}
+	schedule, err := parseCron(entry.Schedule)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry, nil
+	}
+
+	if schedule.neverFires() {
+		entry.NeverFires = true
+		entry.Warnings = append(entry.Warnings, "no date matches the day of month and month fields")
+		return entry, schedule
+	}
+	if schedule.leapDayOnly() {
+		entry.Warnings = append(entry.Warnings, "only fires on February 29, in leap years")
+	}
+	if !schedule.domStar && !schedule.dowStar {
+		entry.Warnings = append(entry.Warnings, "both day fields are restricted, so it fires on days matching either one, not both")
+	}
+
+	scan := schedule.scan(from, loc, runs)
+	for _, run := range scan.next {
+		entry.NextRuns = append(entry.NextRuns, run.Format(time.RFC3339))
+	}
+	entry.DSTSkipped, entry.DSTRepeated = scan.skipped, scan.repeated
+	if len(entry.DSTSkipped) > 0 {
+		entry.Warnings = append(entry.Warnings, "some runs fall in a DST gap; cronie and Vixie cron run them right after the change, other daemons skip them")
+	}
+	if len(entry.DSTRepeated) > 0 {
+		entry.Warnings = append(entry.Warnings, "some runs fall in an hour that repeats when clocks go back; depending on the daemon they run once or twice")
+	}
+	return entry, schedule
+}
+
+// crontabOverlaps finds entries that fire in the same minute in [from, to)
+func crontabOverlaps(entries []CrontabEntry, audited []auditedEntry, from, to time.Time, loc *time.Location) []CrontabOverlap {
+	minutes := make(map[int64][]int)
+	for i, a := range audited {
+		if a.schedule == nil {
+			continue
+		}
+		a.schedule.each(from, to, a.loc, func(run cronRun) bool {
+			if len(run.instants) == 0 {
+				return true
+			}
+			instant := run.instants[0]
+			if instant.Before(from) {
+				instant = run.instants[len(run.instants)-1]
+			}
+			if !instant.Before(to) {
+				return false
+			}
+			key := instant.Unix() / 60
+			minutes[key] = append(minutes[key], i)
+			return true
+		})
+	}
+
+	type pair struct{ a, b int }
+	shared := make(map[pair]*CrontabOverlap)
+	firstAt := make(map[pair]int64)
+	for key, indexes := range minutes {
+		for x := 0; x < len(indexes); x++ {
+			for y := x + 1; y < len(indexes); y++ {
+				p := pair{indexes[x], indexes[y]}
+				overlap, ok := shared[p]
+				if !ok {
+					overlap = &CrontabOverlap{Lines: []int{entries[p.a].Line, entries[p.b].Line}}
+					shared[p] = overlap
+					firstAt[p] = key
+				}
+				overlap.SharedRuns++
+				if key < firstAt[p] {
+					firstAt[p] = key
+				}
+			}
+		}
+	}
+
+	overlaps := make([]CrontabOverlap, 0, len(shared))
+	for p, overlap := range shared {
+		overlap.FirstShared = time.Unix(firstAt[p]*60, 0).In(loc).Format(time.RFC3339)
+		overlaps = append(overlaps, *overlap)
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].Lines[0] != overlaps[j].Lines[0] {
+			return overlaps[i].Lines[0] < overlaps[j].Lines[0]
+		}
+		return overlaps[i].Lines[1] < overlaps[j].Lines[1]
+	})
+	return overlaps
+}
+
+// splitFields returns the first n whitespace-separated fields of text and
+// the rest of it, with its inner spacing kept
+func splitFields(text string, n int) ([]string, string) {
+	var fields []string
+	rest := strings.TrimSpace(text)
+	for len(fields) < n && rest != "" {
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			fields = append(fields, rest)
+			return fields, ""
+		}
+		fields = append(fields, rest[:end])
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+	return fields, rest
+}
+
+// boundedCount reads an optional count argument, 0 meaning fallback
+func boundedCount(field string, value, fallback, max int) (int, error) {
+	switch {
+	case value == 0:
+		return fallback, nil
+	case value < 0 || value > max:
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "%s must be between 1 and %d, got: %d", field, max, value)
+	}
+	return value, nil
+}
@@ -0,0 +1,109 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+const testCrontab = `# maintenance
+CRON_TZ=America/New_York
+30 2 * * * /usr/local/bin/backup
+0 * * * * /usr/sbin/logrotate /etc/logrotate.conf
+0 0 30 2 * /usr/local/bin/never
+@reboot /usr/local/bin/warm-cache
+15 10 * * * tar czf /tmp/$(date +%F).tgz /srv
+61 * * * * /usr/local/bin/typo
+@daily /usr/local/bin/cleanup
+`
+
+func TestTimeService_AuditCrontab(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	result, err := service.AuditCrontab(AuditCrontabInput{Crontab: testCrontab, Runs: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", result.Timezone)
+	assert.Equal(t, "2023-12-25T15:30:00Z", result.From)
+	require.Len(t, result.Entries, 7)
+
+	backup := result.Entries[0]
+	assert.Equal(t, 3, backup.Line)
+	assert.Equal(t, "America/New_York", backup.Timezone)
+	assert.Equal(t, []string{"2023-12-26T02:30:00-05:00", "2023-12-27T02:30:00-05:00"}, backup.NextRuns)
+	assert.Equal(t, []string{"2024-03-10T02:30"}, backup.DSTSkipped)
+	assert.Empty(t, backup.DSTRepeated)
+
+	logrotate := result.Entries[1]
+	assert.Equal(t, "/usr/sbin/logrotate /etc/logrotate.conf", logrotate.Command)
+	assert.Equal(t, []string{"2024-03-10T02:00"}, logrotate.DSTSkipped)
+	assert.Equal(t, []string{"2024-11-03T01:00"}, logrotate.DSTRepeated)
+
+	never := result.Entries[2]
+	assert.True(t, never.NeverFires)
+	assert.Empty(t, never.NextRuns)
+
+	reboot := result.Entries[3]
+	assert.Empty(t, reboot.NextRuns)
+	assert.Equal(t, []string{"runs once when the cron daemon starts, not on a schedule"}, reboot.Warnings)
+
+	assert.Contains(t, result.Entries[4].Warnings[0], "unescaped %")
+	assert.Equal(t, "minute: value 61 out of range 0-59", result.Entries[5].Error)
+
+	// @daily and the hourly logrotate both fire at midnight
+	assert.Equal(t, []CrontabOverlap{{Lines: []int{4, 9}, SharedRuns: 7, FirstShared: "2023-12-26T05:00:00Z"}}, result.Overlaps)
+	assert.Equal(t, 5, result.Issues)
+}
+
+func TestTimeService_AuditCrontab_System(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	result, err := service.AuditCrontab(AuditCrontabInput{
+		Crontab:  "TZ=Europe/Berlin\n17 *\t* * *   root    cd / && run-parts --report /etc/cron.hourly\n0 0 29 2 * root /usr/local/bin/leap",
+		Timezone: "Europe/Berlin",
+		From:     RFC3339Timestamp("2024-01-01T00:00:00Z"),
+		Runs:     1,
+		System:   true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, "root", result.Entries[0].User)
+	assert.Equal(t, "cd / && run-parts --report /etc/cron.hourly", result.Entries[0].Command)
+	assert.Equal(t, []string{"2024-01-01T01:17:00+01:00"}, result.Entries[0].NextRuns)
+	assert.Equal(t, []string{"2024-02-29T00:00:00+01:00"}, result.Entries[1].NextRuns)
+	assert.Equal(t, []string{"only fires on February 29, in leap years"}, result.Entries[1].Warnings)
+	assert.Equal(t, []string{"line 1: TZ only sets the environment of commands; use CRON_TZ to change the zone schedules run in"}, result.Warnings)
+}
+
+func TestTimeService_AuditCrontab_Errors(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	tests := []struct {
+		name  string
+		input AuditCrontabInput
+		err   error
+		want  string
+	}{
+		{"empty crontab", AuditCrontabInput{Crontab: "  \n"}, timeerrors.ErrInvalidArgument, "crontab cannot be empty"},
+		{"too many runs", AuditCrontabInput{Crontab: "@daily true", Runs: 51}, timeerrors.ErrInvalidArgument, "runs must be between 1 and 50, got: 51"},
+		{"negative overlap days", AuditCrontabInput{Crontab: "@daily true", OverlapDays: -1}, timeerrors.ErrInvalidArgument, "overlap_days must be between 1 and 31, got: -1"},
+		{"invalid timezone", AuditCrontabInput{Crontab: "@daily true", Timezone: "Mars/Olympus"}, timeerrors.ErrInvalidTimezone, "invalid timezone Mars/Olympus: unknown time zone Mars/Olympus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AuditCrontab(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package time
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Day numbers count whole days and carry no time of day: formatting writes
+// the date in the target timezone, and parsing gives local midnight
+const (
+	// rataDieEpochDay is the Rata Die number of 1970-01-01; Rata Die day 1
+	// is 0001-01-01 in the proleptic Gregorian calendar
+	rataDieEpochDay = 719163
+	// ordinalLayout writes ISO 8601 ordinal dates, such as 2023-359
+	ordinalLayout = "2006-002"
+)
+
+// Day numbers are limited to the years 1 to 9999
+const (
+	minEpochDay = 1 - rataDieEpochDay
+	maxEpochDay = 3652059 - rataDieEpochDay
+)
+
+// isDayNumberFormat reports whether a format is one of the day number formats
+func isDayNumberFormat(format string) bool {
+	switch FormatType(format) {
+	case FormatEpochDay, FormatOrdinal, FormatRataDie:
+		return true
+	}
+	return false
+}
+
+// epochDay returns the number of days from 1970-01-01 to the date of t in
+// its own location
+func epochDay(t time.Time) int64 {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// formatDayNumber writes the date of t as an epoch day, ordinal date or
+// Rata Die number
+func formatDayNumber(t time.Time, format FormatType) (string, error) {
+	day := epochDay(t)
+	if day < minEpochDay || day > maxEpochDay {
+		return "", timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s cannot be written as %s: only years 1 to 9999 are supported", t.Format(time.RFC3339), format)
+	}
+	switch format {
+	case FormatOrdinal:
+		return t.Format(ordinalLayout), nil
+	case FormatRataDie:
+		return strconv.FormatInt(day+rataDieEpochDay, 10), nil
+	default:
+		return strconv.FormatInt(day, 10), nil
+	}
+}
+
+// parseDayNumber reads an epoch day, ordinal date or Rata Die number as
+// midnight in loc
+func parseDayNumber(s string, format FormatType, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if format == FormatOrdinal {
+		t, err := time.ParseInLocation(ordinalLayout, s, loc)
+		if err != nil {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid ordinal date %q: must be YYYY-DDD, such as 2023-359", s)
+		}
+		return t, nil
+	}
+
+	day, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be a whole number of days", format, s)
+	}
+	min, max := int64(minEpochDay), int64(maxEpochDay)
+	if format == FormatRataDie {
+		day -= rataDieEpochDay
+		min, max = min+rataDieEpochDay, max+rataDieEpochDay
+	}
+	if day < minEpochDay || day > maxEpochDay {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s %s is out of range: representable range is %d to %d (0001-01-01 to 9999-12-31)", format, s, min, max)
+	}
+	date := time.Unix(day*86400, 0).UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc), nil
+}
@@ -0,0 +1,83 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestDayNumberFormats(t *testing.T) {
+	tests := []struct {
+		format   FormatType
+		at       time.Time
+		expected string
+	}{
+		{format: FormatEpochDay, at: time.Date(1970, 1, 1, 23, 59, 0, 0, time.UTC), expected: "0"},
+		{format: FormatEpochDay, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "19716"},
+		{format: FormatEpochDay, at: time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC), expected: "-1"},
+		{format: FormatOrdinal, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "2023-359"},
+		{format: FormatOrdinal, at: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), expected: "2024-366"},
+		{format: FormatRataDie, at: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), expected: "1"},
+		{format: FormatRataDie, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "738879"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format)+"/"+tt.expected, func(t *testing.T) {
+			formatted, err := formatDayNumber(tt.at, tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, formatted)
+
+			parsed, err := parseDayNumber(tt.expected, tt.format, time.UTC)
+			require.NoError(t, err)
+			assert.Equal(t, time.Date(tt.at.Year(), tt.at.Month(), tt.at.Day(), 0, 0, 0, 0, time.UTC), parsed)
+		})
+	}
+
+	// The date is the one in the time's own zone
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	formatted, err := formatDayNumber(time.Date(2023, 12, 25, 20, 0, 0, 0, time.UTC).In(tokyo), FormatEpochDay)
+	require.NoError(t, err)
+	assert.Equal(t, "19717", formatted)
+
+	_, err = parseDayNumber("0", FormatRataDie, time.UTC)
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+	assert.ErrorContains(t, err, "representable range is 1 to 3652059")
+
+	_, err = parseDayNumber("2023-366", FormatOrdinal, time.UTC)
+	assert.True(t, errors.Is(err, timeerrors.ErrParseFailure))
+
+	_, err = parseDayNumber("1.5", FormatEpochDay, time.UTC)
+	assert.ErrorContains(t, err, "must be a whole number of days")
+
+	_, err = formatDayNumber(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), FormatRataDie)
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+}
+
+func TestTimeService_DayNumbers(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "EpochDay", "Ordinal", "RataDie"}, newTestLogger(t))
+
+	formatted, err := service.FormatTime(FormatTimeInput{
+		Timestamp: RFC3339Timestamp("2023-12-25T23:30:00Z"),
+		Format:    "Ordinal",
+		Timezone:  "Asia/Tokyo",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2023-360", formatted.FormattedTime)
+
+	parsed, err := service.ParseTime(ParseTimeInput{TimeString: "19716", Format: "EpochDay", Timezone: "Europe/Paris"})
+	require.NoError(t, err)
+	assert.Equal(t, "2023-12-25T00:00:00+01:00", parsed.RFC3339)
+	assert.False(t, parsed.ExplicitOffset)
+
+	verified, err := service.VerifyFormat(VerifyFormatInput{Format: "RataDie", Sample: "738879"})
+	require.NoError(t, err)
+	assert.True(t, verified.RoundTrip)
+	require.Len(t, verified.Losses, 1)
+	assert.Equal(t, LossTimeOfDay, verified.Losses[0].Kind)
+}
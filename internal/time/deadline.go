@@ -0,0 +1,148 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// DeadlineStatus is the state of a deadline relative to an instant
+type DeadlineStatus string
+
+const (
+	DeadlineUpcoming    DeadlineStatus = "upcoming"
+	DeadlineWithinGrace DeadlineStatus = "within_grace"
+	DeadlineOverdue     DeadlineStatus = "overdue"
+)
+
+// CheckDeadlineInput represents input for evaluating a deadline
+type CheckDeadlineInput struct {
+	Deadline Timestamp `json:"deadline"`
+	Grace    string    `json:"grace,omitempty"` // Go duration after the deadline before it is overdue
+	// EscalateEvery repeats escalations at this interval once overdue
+	EscalateEvery string    `json:"escalate_every,omitempty"`
+	At            Timestamp `json:"at,omitempty"`       // current or virtual time, defaults to now
+	Timezone      string    `json:"timezone,omitempty"` // timezone for rendered times, defaults to the server default
+}
+
+// DeadlineEscalation is the next boundary at which a deadline escalates
+type DeadlineEscalation struct {
+	At        string `json:"at"`
+	Status    string `json:"status"`
+	InSeconds int64  `json:"in_seconds"`
+}
+
+// CheckDeadlineResult represents the status of a deadline
+type CheckDeadlineResult struct {
+	Status      string `json:"status"`
+	Deadline    string `json:"deadline"`
+	GraceEndsAt string `json:"grace_ends_at"`
+	EvaluatedAt string `json:"evaluated_at"`
+	Timezone    string `json:"timezone"`
+	// DeltaSeconds is the time until the deadline, negative once it has passed
+	DeltaSeconds int64  `json:"delta_seconds"`
+	Summary      string `json:"summary"`
+	// EscalationLevel counts escalate_every intervals elapsed since the grace period ended
+	EscalationLevel int                 `json:"escalation_level,omitempty"`
+	NextEscalation  *DeadlineEscalation `json:"next_escalation,omitempty"`
+}
+
+// CheckDeadline evaluates a deadline with an optional grace period against an instant
+func (s *timeService) CheckDeadline(input CheckDeadlineInput) (CheckDeadlineResult, error) {
+	deadline, err := input.Deadline.Resolve()
+	if err != nil {
+		return CheckDeadlineResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid deadline: %w", err)
+	}
+
+	grace, err := parseNonNegativeDuration("grace", input.Grace)
+	if err != nil {
+		return CheckDeadlineResult{}, err
+	}
+	escalateEvery, err := parseNonNegativeDuration("escalate_every", input.EscalateEvery)
+	if err != nil {
+		return CheckDeadlineResult{}, err
+	}
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return CheckDeadlineResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	at := s.clock.Now()
+	if !input.At.IsZero() {
+		if at, err = input.At.Resolve(); err != nil {
+			return CheckDeadlineResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+
+	graceEnd := deadline.Add(grace)
+	delta := deadline.Sub(at).Truncate(time.Second)
+
+	result := CheckDeadlineResult{
+		Deadline:     deadline.In(loc).Format(time.RFC3339),
+		GraceEndsAt:  graceEnd.In(loc).Format(time.RFC3339),
+		EvaluatedAt:  at.In(loc).Format(time.RFC3339),
+		Timezone:     timezone,
+		DeltaSeconds: int64(delta / time.Second),
+	}
+
+	var next time.Time
+	var nextStatus DeadlineStatus
+	switch {
+	case at.Before(deadline):
+		result.Status = string(DeadlineUpcoming)
+		result.Summary = fmt.Sprintf("due in %s", humanizeDuration(delta))
+		next, nextStatus = deadline, DeadlineWithinGrace
+		if grace == 0 {
+			nextStatus = DeadlineOverdue
+		}
+	case at.Before(graceEnd):
+		result.Status = string(DeadlineWithinGrace)
+		result.Summary = fmt.Sprintf("deadline passed %s ago, grace period ends in %s",
+			humanizeDuration(-delta), humanizeDuration(graceEnd.Sub(at)))
+		next, nextStatus = graceEnd, DeadlineOverdue
+	default:
+		result.Status = string(DeadlineOverdue)
+		result.Summary = fmt.Sprintf("overdue by %s", humanizeDuration(at.Sub(graceEnd)))
+		if escalateEvery > 0 {
+			result.EscalationLevel = int(at.Sub(graceEnd)/escalateEvery) + 1
+			next = graceEnd.Add(time.Duration(result.EscalationLevel) * escalateEvery)
+			nextStatus = DeadlineOverdue
+		}
+	}
+
+	if !next.IsZero() {
+		result.NextEscalation = &DeadlineEscalation{
+			At:        next.In(loc).Format(time.RFC3339),
+			Status:    string(nextStatus),
+			InSeconds: int64(next.Sub(at).Truncate(time.Second) / time.Second),
+		}
+	}
+
+	s.logger.Debug("Checked deadline",
+		slog.String("status", result.Status),
+		slog.Int64("delta_seconds", result.DeltaSeconds))
+
+	return result, nil
+}
+
+// parseNonNegativeDuration parses an optional Go duration field
+func parseNonNegativeDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s: %w", field, err)
+	}
+	if d < 0 {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "%s cannot be negative", field)
+	}
+	return d, nil
+}
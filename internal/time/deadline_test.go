@@ -0,0 +1,107 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_CheckDeadline(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	deadline := RFC3339Timestamp("2023-12-25T17:00:00Z")
+
+	tests := []struct {
+		name    string
+		input   CheckDeadlineInput
+		status  string
+		delta   int64
+		summary string
+		level   int
+		next    *DeadlineEscalation
+	}{
+		{
+			name:    "upcoming with grace",
+			input:   CheckDeadlineInput{Deadline: deadline, Grace: "30m"},
+			status:  "upcoming",
+			delta:   5355,
+			summary: "due in 1h 29m",
+			next:    &DeadlineEscalation{At: "2023-12-25T17:00:00Z", Status: "within_grace", InSeconds: 5355},
+		},
+		{
+			name:    "upcoming without grace escalates straight to overdue",
+			input:   CheckDeadlineInput{Deadline: deadline},
+			status:  "upcoming",
+			delta:   5355,
+			summary: "due in 1h 29m",
+			next:    &DeadlineEscalation{At: "2023-12-25T17:00:00Z", Status: "overdue", InSeconds: 5355},
+		},
+		{
+			name:    "within grace at a virtual time",
+			input:   CheckDeadlineInput{Deadline: deadline, Grace: "30m", At: RFC3339Timestamp("2023-12-25T17:10:00Z")},
+			status:  "within_grace",
+			delta:   -600,
+			summary: "deadline passed 10m ago, grace period ends in 20m",
+			next:    &DeadlineEscalation{At: "2023-12-25T17:30:00Z", Status: "overdue", InSeconds: 1200},
+		},
+		{
+			name:    "overdue",
+			input:   CheckDeadlineInput{Deadline: deadline, Grace: "30m", At: RFC3339Timestamp("2023-12-25T18:00:00Z")},
+			status:  "overdue",
+			delta:   -3600,
+			summary: "overdue by 30m",
+		},
+		{
+			name: "overdue with repeated escalation",
+			input: CheckDeadlineInput{Deadline: deadline, Grace: "30m", EscalateEvery: "1h",
+				At: RFC3339Timestamp("2023-12-25T19:00:00Z")},
+			status:  "overdue",
+			delta:   -7200,
+			summary: "overdue by 1h 30m",
+			level:   2,
+			next:    &DeadlineEscalation{At: "2023-12-25T19:30:00Z", Status: "overdue", InSeconds: 1800},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.CheckDeadline(tt.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.delta, result.DeltaSeconds)
+			assert.Equal(t, tt.summary, result.Summary)
+			assert.Equal(t, tt.level, result.EscalationLevel)
+			assert.Equal(t, tt.next, result.NextEscalation)
+		})
+	}
+}
+
+func TestTimeService_CheckDeadline_Errors(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	deadline := RFC3339Timestamp("2023-12-25T17:00:00Z")
+
+	tests := []struct {
+		name  string
+		input CheckDeadlineInput
+	}{
+		{"missing deadline", CheckDeadlineInput{}},
+		{"invalid grace", CheckDeadlineInput{Deadline: deadline, Grace: "a while"}},
+		{"negative grace", CheckDeadlineInput{Deadline: deadline, Grace: "-5m"}},
+		{"negative escalation", CheckDeadlineInput{Deadline: deadline, EscalateEvery: "-1h"}},
+		{"invalid timezone", CheckDeadlineInput{Deadline: deadline, Timezone: "Invalid/Zone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CheckDeadline(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
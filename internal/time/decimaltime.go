@@ -0,0 +1,107 @@
+package time
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Clock formats write the time of day only. Swatch beats and French decimal
+// time both split the day into 100,000 units of 0.864 seconds, a centibeat
+// or a decimal second. Parsing gives the time on 0000-01-01, as Go does for
+// layouts without a date
+const (
+	// decimalSecond is a French decimal second, or a hundredth of a beat
+	decimalSecond = 864 * time.Millisecond
+	// decimalSecondsPerDay is the number of decimal seconds in a day
+	decimalSecondsPerDay = 100000
+)
+
+// biel is Biel Mean Time, the UTC+1 zone Swatch Internet Time counts in,
+// without daylight saving time
+var biel = time.FixedZone("BMT", 3600)
+
+var (
+	// swatchBeatsPattern matches beats, such as @437 or @437.50
+	swatchBeatsPattern = regexp.MustCompile(`^@?(\d{1,3})(?:\.(\d{1,2}))?$`)
+	// decimalTimePattern matches decimal hours, minutes and seconds, such as 6:46:35
+	decimalTimePattern = regexp.MustCompile(`^(\d):(\d{2}):(\d{2})$`)
+	// dayKilosecondsPattern matches kiloseconds of the day, such as 55.845
+	dayKilosecondsPattern = regexp.MustCompile(`^(\d{1,2})(?:\.(\d{1,3}))?$`)
+)
+
+// sinceMidnight returns how long after midnight t is on its own wall clock
+func sinceMidnight(t time.Time) time.Duration {
+	hour, min, sec := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(t.Nanosecond())
+}
+
+// formatClock writes the time of day of t as Swatch beats, French decimal
+// time or kiloseconds of the UTC day
+func formatClock(t time.Time, format FormatType) string {
+	switch format {
+	case FormatSwatchBeats:
+		centibeats := sinceMidnight(t.In(biel)) / decimalSecond
+		return fmt.Sprintf("@%03d.%02d", centibeats/100, centibeats%100)
+	case FormatDecimalTime:
+		seconds := sinceMidnight(t) / decimalSecond
+		return fmt.Sprintf("%d:%02d:%02d", seconds/10000, seconds/100%100, seconds%100)
+	default:
+		seconds := sinceMidnight(t.UTC()) / time.Second
+		return fmt.Sprintf("%d.%03d", seconds/1000, seconds%1000)
+	}
+}
+
+// parseClock reads Swatch beats, French decimal time or kiloseconds of the
+// day as that time on 0000-01-01. Decimal time is read in loc, and the
+// others in their own zone
+func parseClock(s string, format FormatType, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var elapsed time.Duration
+	switch format {
+	case FormatSwatchBeats:
+		match := swatchBeatsPattern.FindStringSubmatch(s)
+		if match == nil {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be beats from @000 to @999.99, such as @437.50", format, s)
+		}
+		elapsed = time.Duration(100*atoi(match[1])+fraction(match[2], 2)) * decimalSecond
+		loc = biel
+	case FormatDecimalTime:
+		match := decimalTimePattern.FindStringSubmatch(s)
+		if match == nil || atoi(match[2]) >= 100 || atoi(match[3]) >= 100 {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be decimal hours, minutes and seconds from 0:00:00 to 9:99:99, such as 6:46:35", format, s)
+		}
+		elapsed = time.Duration(10000*atoi(match[1])+100*atoi(match[2])+atoi(match[3])) * decimalSecond
+	default:
+		match := dayKilosecondsPattern.FindStringSubmatch(s)
+		seconds := 0
+		if match != nil {
+			seconds = 1000*atoi(match[1]) + fraction(match[2], 3)
+		}
+		if match == nil || seconds >= 86400 {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be kiloseconds from 0 to 86.399, such as 55.845", format, s)
+		}
+		elapsed = time.Duration(seconds) * time.Second
+		loc = time.UTC
+	}
+	return time.Date(0, time.January, 1, 0, 0, 0, 0, loc).Add(elapsed), nil
+}
+
+// atoi reads digits matched by a pattern
+func atoi(digits string) int {
+	n, _ := strconv.Atoi(digits)
+	return n
+}
+
+// fraction reads the digits after a decimal point in units of 10^-places
+func fraction(digits string, places int) int {
+	for len(digits) < places {
+		digits += "0"
+	}
+	return atoi(digits)
+}
@@ -0,0 +1,71 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestClockFormats(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		format   FormatType
+		at       time.Time
+		expected string
+	}{
+		// Biel Mean Time is UTC+1 all year
+		{format: FormatSwatchBeats, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "@688.02"},
+		{format: FormatSwatchBeats, at: time.Date(2023, 7, 1, 23, 0, 0, 0, time.UTC), expected: "@000.00"},
+		{format: FormatSwatchBeats, at: time.Date(2023, 7, 1, 22, 59, 59, 999000000, time.UTC), expected: "@999.99"},
+		{format: FormatDecimalTime, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "6:46:35"},
+		{format: FormatDecimalTime, at: time.Date(2023, 12, 25, 12, 0, 0, 0, time.UTC), expected: "5:00:00"},
+		// Decimal time is the wall clock of the time's own zone
+		{format: FormatDecimalTime, at: time.Date(2023, 12, 25, 12, 0, 0, 0, paris), expected: "5:00:00"},
+		{format: FormatDayKiloseconds, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "55.845"},
+		{format: FormatDayKiloseconds, at: time.Date(2023, 12, 25, 16, 30, 45, 0, paris), expected: "55.845"},
+		{format: FormatDayKiloseconds, at: time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), expected: "86.399"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format)+"/"+tt.expected, func(t *testing.T) {
+			formatted, err := renderLayout(tt.at, string(tt.format))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, formatted)
+
+			parsed, err := parseClock(tt.expected, tt.format, tt.at.Location())
+			require.NoError(t, err)
+			assert.Equal(t, 0, parsed.Year())
+			// The units are coarser than a second
+			elapsed := sinceMidnight(parsed) - sinceMidnight(tt.at.In(parsed.Location()))
+			assert.True(t, elapsed <= 0 && elapsed > -decimalSecond, "off by %s", elapsed)
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	parsed, err := parseClock("@437", FormatSwatchBeats, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(0, 1, 1, 10, 29, 16, 800000000, biel), parsed)
+
+	parsed, err = parseClock(" 437.5 ", FormatSwatchBeats, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(0, 1, 1, 10, 30, 0, 0, biel), parsed)
+
+	parsed, err = parseClock("86.4", FormatDayKiloseconds, time.Local)
+	assert.True(t, errors.Is(err, timeerrors.ErrParseFailure))
+	assert.True(t, parsed.IsZero())
+
+	for _, invalid := range []string{"6:100:00", "10:00:00", "6:46"} {
+		_, err = parseClock(invalid, FormatDecimalTime, time.UTC)
+		assert.True(t, errors.Is(err, timeerrors.ErrParseFailure), invalid)
+	}
+	_, err = parseClock("@1000", FormatSwatchBeats, time.UTC)
+	assert.ErrorContains(t, err, "must be beats from @000 to @999.99")
+}
@@ -0,0 +1,57 @@
+package time
+
+import (
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// DST lookahead bounds, in days. A year catches every zone with regular
+// DST; zones that suspend DST for several years need a longer horizon
+const (
+	DefaultDSTLookaheadDays = 365
+	MaxDSTLookaheadDays     = 3660
+)
+
+// Transition status values reported by timezone_info
+const (
+	TransitionStatusScheduled         = "scheduled"
+	TransitionStatusNoneWithinHorizon = "none_within_horizon"
+)
+
+// WithDSTLookahead sets how many days ahead timezone_info looks for the
+// next transition when a request does not set its own horizon
+func WithDSTLookahead(days int) Option {
+	return func(s *timeService) {
+		s.dstLookaheadDays = days
+	}
+}
+
+// lookaheadDays resolves the horizon for a request, falling back to the
+// service default
+func (s *timeService) lookaheadDays(requested int) (int, error) {
+	if requested == 0 {
+		return s.dstLookaheadDays, nil
+	}
+	if requested < 0 || requested > MaxDSTLookaheadDays {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"lookahead_days must be between 1 and %d, got: %d", MaxDSTLookaheadDays, requested)
+	}
+	return requested, nil
+}
+
+// hasDST reports whether tzdata marks any instant within days either side
+// of t as daylight saving time. Unlike is_dst this reads the zone's own
+// isdst flag instead of comparing offsets
+func hasDST(t time.Time, loc *time.Location, days int) bool {
+	t = t.In(loc)
+	if t.IsDST() {
+		return true
+	}
+	for i := 1; i <= days; i++ {
+		if t.AddDate(0, 0, i).IsDST() || t.AddDate(0, 0, -i).IsDST() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_GetTimezoneInfo_Lookahead(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+	longHorizon := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithDSTLookahead(1000))
+
+	// Pyongyang moved to +08:30 in August 2015 and back to +09:00 in May 2018
+	pyongyang := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		service    TimeService
+		input      TimezoneInfoInput
+		status     string
+		days       int
+		hasDST     bool
+		transition time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "regular DST within a year",
+			service:    service,
+			input:      TimezoneInfoInput{Timezone: "America/New_York", ReferenceTime: time.Date(2023, time.December, 25, 15, 30, 45, 0, time.UTC)},
+			status:     TransitionStatusScheduled,
+			days:       DefaultDSTLookaheadDays,
+			hasDST:     true,
+			transition: time.Date(2024, time.March, 10, 14, 30, 45, 0, time.UTC),
+		},
+		{
+			name:    "no DST at all",
+			service: service,
+			input:   TimezoneInfoInput{Timezone: "Asia/Kolkata", ReferenceTime: time.Date(2023, time.December, 25, 15, 30, 45, 0, time.UTC)},
+			status:  TransitionStatusNoneWithinHorizon,
+			days:    DefaultDSTLookaheadDays,
+		},
+		{
+			name:    "change beyond the default horizon",
+			service: service,
+			input:   TimezoneInfoInput{Timezone: "Asia/Pyongyang", ReferenceTime: pyongyang},
+			status:  TransitionStatusNoneWithinHorizon,
+			days:    DefaultDSTLookaheadDays,
+		},
+		{
+			name:       "change within a configured horizon",
+			service:    longHorizon,
+			input:      TimezoneInfoInput{Timezone: "Asia/Pyongyang", ReferenceTime: pyongyang},
+			status:     TransitionStatusScheduled,
+			days:       1000,
+			transition: time.Date(2018, time.May, 4, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "change within a requested horizon",
+			service:    service,
+			input:      TimezoneInfoInput{Timezone: "Asia/Pyongyang", ReferenceTime: pyongyang, LookaheadDays: 900},
+			status:     TransitionStatusScheduled,
+			days:       900,
+			transition: time.Date(2018, time.May, 4, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "abolished DST is outside a short horizon",
+			service: service,
+			input:   TimezoneInfoInput{Timezone: "America/Sao_Paulo", ReferenceTime: time.Date(2023, time.December, 25, 0, 0, 0, 0, time.UTC)},
+			status:  TransitionStatusNoneWithinHorizon,
+			days:    DefaultDSTLookaheadDays,
+		},
+		{
+			name:    "abolished DST is seen by a long horizon",
+			service: service,
+			input:   TimezoneInfoInput{Timezone: "America/Sao_Paulo", ReferenceTime: time.Date(2023, time.December, 25, 0, 0, 0, 0, time.UTC), LookaheadDays: 2000},
+			status:  TransitionStatusNoneWithinHorizon,
+			days:    2000,
+			hasDST:  true,
+		},
+		{
+			name:    "negative horizon",
+			service: service,
+			input:   TimezoneInfoInput{Timezone: "UTC", LookaheadDays: -1},
+			wantErr: true,
+		},
+		{
+			name:    "horizon above the maximum",
+			service: service,
+			input:   TimezoneInfoInput{Timezone: "UTC", LookaheadDays: MaxDSTLookaheadDays + 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := tt.service.GetTimezoneInfo(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, info.TransitionStatus)
+			assert.Equal(t, tt.days, info.LookaheadDays)
+			assert.Equal(t, tt.hasDST, info.HasDST)
+			if tt.transition.IsZero() {
+				assert.Nil(t, info.NextTransition)
+			} else {
+				require.NotNil(t, info.NextTransition)
+				assert.True(t, tt.transition.Equal(info.NextTransition.NextTransition),
+					"got %s", info.NextTransition.NextTransition)
+			}
+		})
+	}
+}
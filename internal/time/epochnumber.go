@@ -0,0 +1,75 @@
+package time
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// DigitSeparators are the separators digit_grouping accepts
+var DigitSeparators = []string{",", ".", "_", " ", "'"}
+
+// integerFormats are the formats written as a bare integer, which
+// epoch_as_number also returns as a JSON number
+var integerFormats = map[FormatType]bool{
+	FormatUnix:       true,
+	FormatUnixMilli:  true,
+	FormatUnixMicro:  true,
+	FormatUnixNano:   true,
+	FormatEpochDay:   true,
+	FormatRataDie:    true,
+	FormatGPSSeconds: true,
+}
+
+// IsIntegerFormat reports whether a format is written as a bare integer
+func IsIntegerFormat(format string) bool {
+	return integerFormats[FormatType(format)]
+}
+
+// epochNumber returns a formatted time as a number, when its format is
+// written as a bare integer
+func epochNumber(formatted, format string) (int64, bool) {
+	if !IsIntegerFormat(format) {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(formatted, 10, 64)
+	return n, err == nil
+}
+
+// validateDigitGrouping checks a digit_grouping separator, empty meaning
+// no grouping
+func validateDigitGrouping(separator string) error {
+	if separator == "" || slices.Contains(DigitSeparators, separator) {
+		return nil
+	}
+	return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid digit_grouping %q (must be one of: %q)", separator, DigitSeparators)
+}
+
+// GroupDigits writes a formatted time with separator between groups of
+// three digits, e.g. 1,703,518,245. Times in formats not written as a bare
+// integer, and any time when separator is empty, are returned unchanged
+func GroupDigits(formatted, format, separator string) string {
+	if separator == "" {
+		return formatted
+	}
+	if _, ok := epochNumber(formatted, format); !ok {
+		return formatted
+	}
+
+	sign, digits := "", formatted
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
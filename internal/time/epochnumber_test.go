@@ -0,0 +1,71 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestGroupDigits(t *testing.T) {
+	tests := []struct {
+		formatted string
+		format    string
+		separator string
+		expected  string
+	}{
+		{formatted: "1703518245", format: "Unix", separator: ",", expected: "1,703,518,245"},
+		{formatted: "1703518245123", format: "UnixMilli", separator: "_", expected: "1_703_518_245_123"},
+		{formatted: "-1703518245", format: "Unix", separator: ".", expected: "-1.703.518.245"},
+		{formatted: "738879", format: "RataDie", separator: " ", expected: "738 879"},
+		{formatted: "245", format: "Unix", separator: ",", expected: "245"},
+		{formatted: "1703518245", format: "Unix", separator: "", expected: "1703518245"},
+		// Only integer formats are grouped
+		{formatted: "2460304.1463555954", format: "JulianDay", separator: ",", expected: "2460304.1463555954"},
+		{formatted: "20231225", format: "20060102", separator: ",", expected: "20231225"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GroupDigits(tt.formatted, tt.format, tt.separator))
+		})
+	}
+}
+
+func TestEpochAsNumber(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 123456789, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix", "UnixMilli", "EpochDay", "JulianDay"},
+		newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	result, err := service.GetCurrentTime(GetTimeInput{Format: "UnixMilli", Formats: []string{"Unix", "EpochDay", "JulianDay"}, EpochAsNumber: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1703518245123", result.FormattedTime)
+	require.NotNil(t, result.FormattedEpoch)
+	assert.Equal(t, int64(1703518245123), *result.FormattedEpoch)
+	assert.Equal(t, map[string]int64{"Unix": 1703518245, "EpochDay": 19716}, result.FormattedEpochs)
+
+	// Without the option, or for a format that isn't an integer, there are
+	// only strings
+	result, err = service.GetCurrentTime(GetTimeInput{Format: "Unix", Formats: []string{"UnixMilli"}})
+	require.NoError(t, err)
+	assert.Nil(t, result.FormattedEpoch)
+	assert.Nil(t, result.FormattedEpochs)
+	result, err = service.GetCurrentTime(GetTimeInput{EpochAsNumber: true})
+	require.NoError(t, err)
+	assert.Nil(t, result.FormattedEpoch)
+
+	formatted, err := service.FormatTime(FormatTimeInput{Timestamp: EpochTimestamp(-1, EpochSeconds), Format: "Unix", EpochAsNumber: true})
+	require.NoError(t, err)
+	require.NotNil(t, formatted.FormattedEpoch)
+	assert.Equal(t, int64(-1), *formatted.FormattedEpoch)
+
+	_, err = service.GetCurrentTime(GetTimeInput{Format: "Unix", DigitGrouping: "-"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	assert.ErrorContains(t, err, `invalid digit_grouping "-"`)
+	_, err = service.FormatTime(FormatTimeInput{Timestamp: EpochTimestamp(1, EpochSeconds), Format: "Unix", DigitGrouping: "::"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+}
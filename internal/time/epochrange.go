@@ -0,0 +1,90 @@
+package time
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// epochLimits are the smallest and largest epochs accepted per unit. Units
+// finer than a second use the full int64 range. Seconds are limited to the
+// millisecond range so every accepted instant can also be written as UnixMilli.
+var epochLimits = map[EpochUnit][2]int64{
+	EpochSeconds:      {math.MinInt64 / 1000, math.MaxInt64 / 1000},
+	EpochMilliseconds: {math.MinInt64, math.MaxInt64},
+	EpochMicroseconds: {math.MinInt64, math.MaxInt64},
+	EpochNanoseconds:  {math.MinInt64, math.MaxInt64},
+}
+
+// epochUnitScale is the number of units per second
+var epochUnitScale = map[EpochUnit]int64{
+	EpochSeconds:      1,
+	EpochMilliseconds: 1e3,
+	EpochMicroseconds: 1e6,
+	EpochNanoseconds:  1e9,
+}
+
+// formatEpochUnits maps the Unix* formats to their epoch unit
+var formatEpochUnits = map[FormatType]EpochUnit{
+	FormatUnix:      EpochSeconds,
+	FormatUnixMilli: EpochMilliseconds,
+	FormatUnixMicro: EpochMicroseconds,
+	FormatUnixNano:  EpochNanoseconds,
+}
+
+// parseEpoch parses a decimal integer epoch in unit. It parses through
+// big.Int so values that overflow int64 get a range error, not a syntax error.
+func parseEpoch(s string, unit EpochUnit) (int64, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return 0, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid epoch %q: must be a decimal integer", s)
+	}
+	if !epochInRange(n, unit) {
+		return 0, epochRangeError(n.String(), unit)
+	}
+	return n.Int64(), nil
+}
+
+// formatEpoch writes t as an epoch in unit, or fails if the epoch would not
+// fit instead of silently wrapping around as time.Time.UnixNano does
+func formatEpoch(t time.Time, unit EpochUnit) (string, error) {
+	scale := epochUnitScale[unit]
+	n := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(scale))
+	n.Add(n, big.NewInt(int64(t.Nanosecond())/(1e9/scale)))
+	if !epochInRange(n, unit) {
+		return "", timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s cannot be written as a %s epoch: %w",
+			t.Format(time.RFC3339Nano), unit, epochRangeError(n.String(), unit))
+	}
+	return n.String(), nil
+}
+
+// epochInRange reports whether n is within the limits for unit
+func epochInRange(n *big.Int, unit EpochUnit) bool {
+	limits := epochLimits[unit]
+	return n.IsInt64() && n.Int64() >= limits[0] && n.Int64() <= limits[1]
+}
+
+// epochRangeError describes the representable range for unit
+func epochRangeError(value string, unit EpochUnit) error {
+	limits := epochLimits[unit]
+	return timeerrors.Errorf(timeerrors.ErrOutOfRange, "epoch %s%s is out of range: representable range is %d%s to %d%s (%s to %s)",
+		value, unit, limits[0], unit, limits[1], unit,
+		epochTime(limits[0], unit).Format(time.RFC3339Nano),
+		epochTime(limits[1], unit).Format(time.RFC3339Nano))
+}
+
+// epochTime converts an in-range epoch to a UTC time
+func epochTime(epoch int64, unit EpochUnit) time.Time {
+	switch unit {
+	case EpochMilliseconds:
+		return time.UnixMilli(epoch).UTC()
+	case EpochMicroseconds:
+		return time.UnixMicro(epoch).UTC()
+	case EpochNanoseconds:
+		return time.Unix(0, epoch).UTC()
+	default:
+		return time.Unix(epoch, 0).UTC()
+	}
+}
@@ -0,0 +1,113 @@
+package time
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEpoch(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		unit        EpochUnit
+		expected    int64
+		expectError string
+	}{
+		{"max nanoseconds", "9223372036854775807", EpochNanoseconds, 9223372036854775807, ""},
+		{"min nanoseconds", "-9223372036854775808", EpochNanoseconds, -9223372036854775808, ""},
+		{"signed", "+42", EpochSeconds, 42, ""},
+		{"overflows int64", "9223372036854775808", EpochNanoseconds, 0, "2262-04-11T23:47:16.854775807Z"},
+		{"far future nanoseconds", "99999999999999999999999", EpochNanoseconds, 0, "epoch 99999999999999999999999ns is out of range"},
+		{"underflows int64", "-9223372036854775809", EpochNanoseconds, 0, "1677-09-21T00:12:43.145224192Z"},
+		{"seconds beyond milliseconds range", "9223372036854776", EpochSeconds, 0, "292278994-08-17T07:12:55Z"},
+		{"not a number", "12a", EpochSeconds, 0, "must be a decimal integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			epoch, err := parseEpoch(tt.value, tt.unit)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, epoch)
+		})
+	}
+}
+
+func TestFormatEpoch(t *testing.T) {
+	tests := []struct {
+		name        string
+		time        time.Time
+		unit        EpochUnit
+		expected    string
+		expectError bool
+	}{
+		{"nanoseconds", time.Unix(1703518245, 123456789), EpochNanoseconds, "1703518245123456789", false},
+		{"microseconds truncate", time.Unix(1703518245, 123456789), EpochMicroseconds, "1703518245123456", false},
+		{"negative milliseconds floor", time.Unix(-1, 999500000), EpochMilliseconds, "-1", false},
+		{"last nanosecond", time.Date(2262, 4, 11, 23, 47, 16, 854775807, time.UTC), EpochNanoseconds, "9223372036854775807", false},
+		{"past nanosecond range", time.Date(2262, 4, 11, 23, 47, 16, 854775808, time.UTC), EpochNanoseconds, "", true},
+		{"year 2300 in seconds", time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), EpochSeconds, "10413792000", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := formatEpoch(tt.time, tt.unit)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "representable range")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestTimestamp_UnmarshalJSON_OutOfRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"huge number", `99999999999999999999999`},
+		{"huge string", `"99999999999999999999999"`},
+		{"huge nanoseconds object", `{"epoch": 99999999999999999999999, "unit": "ns"}`},
+		{"huge exponent", `1e30`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tt.input), &ts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "out of range")
+		})
+	}
+}
+
+func TestTimeService_EpochOverflow(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "UnixNano"}, logger)
+
+	t.Run("parse_time reports bounds", func(t *testing.T) {
+		_, err := service.ParseTime(ParseTimeInput{TimeString: "9223372036854775808", Format: "UnixNano"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "representable range is -9223372036854775808ns to 9223372036854775807ns")
+	})
+
+	t.Run("format_time does not wrap around", func(t *testing.T) {
+		_, err := service.FormatTime(FormatTimeInput{
+			Timestamp: RFC3339Timestamp("2300-01-01T00:00:00Z"),
+			Format:    "UnixNano",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be written as a ns epoch")
+	})
+}
@@ -0,0 +1,187 @@
+package time
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// ExpiryInput represents input for evaluating a certificate or token expiry
+type ExpiryInput struct {
+	ExpiresAt Timestamp `json:"expires_at,omitempty"` // notAfter or exp value
+	// JWT is a token whose exp claim is used when expires_at is not set.
+	// The signature is NOT verified.
+	JWT      string    `json:"jwt,omitempty"`
+	Timezone string    `json:"timezone,omitempty"` // timezone for the rendered times, defaults to the server default
+	At       Timestamp `json:"at,omitempty"`       // instant to evaluate against, defaults to now
+}
+
+// ExpiryResult represents the evaluated expiry
+type ExpiryResult struct {
+	ExpiresAt        string `json:"expires_at"`
+	EvaluatedAt      string `json:"evaluated_at"`
+	Timezone         string `json:"timezone"`
+	Expired          bool   `json:"expired"`
+	RemainingSeconds int64  `json:"remaining_seconds"` // negative once expired
+	Remaining        string `json:"remaining"`
+	Summary          string `json:"summary"`
+	Source           string `json:"source"` // expires_at or jwt
+	// Other JWT time claims, when present
+	IssuedAt  string `json:"issued_at,omitempty"`
+	NotBefore string `json:"not_before,omitempty"`
+}
+
+// jwtTimeClaims are the registered JWT claims holding NumericDate values
+type jwtTimeClaims struct {
+	Exp *json.Number `json:"exp"`
+	Iat *json.Number `json:"iat"`
+	Nbf *json.Number `json:"nbf"`
+}
+
+// EvaluateExpiry reports how long until (or since) an expiry instant
+func (s *timeService) EvaluateExpiry(input ExpiryInput) (ExpiryResult, error) {
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return ExpiryResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	now := s.clock.Now()
+	if !input.At.IsZero() {
+		if now, err = input.At.Resolve(); err != nil {
+			return ExpiryResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+
+	result := ExpiryResult{
+		EvaluatedAt: now.In(loc).Format(time.RFC3339),
+		Timezone:    timezone,
+	}
+
+	var expiresAt time.Time
+	switch {
+	case !input.ExpiresAt.IsZero() && input.JWT != "":
+		return ExpiryResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "set either expires_at or jwt, not both")
+	case !input.ExpiresAt.IsZero():
+		if expiresAt, err = input.ExpiresAt.Resolve(); err != nil {
+			return ExpiryResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid expires_at: %w", err)
+		}
+		result.Source = "expires_at"
+	case input.JWT != "":
+		claims, err := decodeJWTTimeClaims(input.JWT)
+		if err != nil {
+			return ExpiryResult{}, err
+		}
+		if claims.Exp == nil {
+			return ExpiryResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "jwt has no exp claim")
+		}
+		if expiresAt, err = numericDate("exp", *claims.Exp); err != nil {
+			return ExpiryResult{}, err
+		}
+		if claims.Iat != nil {
+			iat, err := numericDate("iat", *claims.Iat)
+			if err != nil {
+				return ExpiryResult{}, err
+			}
+			result.IssuedAt = iat.In(loc).Format(time.RFC3339)
+		}
+		if claims.Nbf != nil {
+			nbf, err := numericDate("nbf", *claims.Nbf)
+			if err != nil {
+				return ExpiryResult{}, err
+			}
+			result.NotBefore = nbf.In(loc).Format(time.RFC3339)
+		}
+		result.Source = "jwt"
+	default:
+		return ExpiryResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "expires_at or jwt is required")
+	}
+
+	remaining := expiresAt.Sub(now).Truncate(time.Second)
+	result.ExpiresAt = expiresAt.In(loc).Format(time.RFC3339)
+	result.Expired = !now.Before(expiresAt)
+	result.RemainingSeconds = int64(remaining / time.Second)
+	result.Remaining = remaining.String()
+
+	if result.Expired {
+		result.Summary = fmt.Sprintf("expired %s ago, at %s", humanizeDuration(-remaining), expiresAt.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"))
+	} else {
+		result.Summary = fmt.Sprintf("expires in %s, at %s", humanizeDuration(remaining), expiresAt.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"))
+	}
+
+	s.logger.Debug("Evaluated expiry",
+		slog.String("source", result.Source),
+		slog.Bool("expired", result.Expired),
+		slog.Int64("remaining_seconds", result.RemainingSeconds))
+
+	return result, nil
+}
+
+// decodeJWTTimeClaims extracts the time claims from a JWT payload without
+// verifying the signature
+func decodeJWTTimeClaims(token string) (jwtTimeClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return jwtTimeClaims{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "jwt must have 3 dot-separated parts, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return jwtTimeClaims{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid jwt payload encoding: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var claims jwtTimeClaims
+	if err := dec.Decode(&claims); err != nil {
+		return jwtTimeClaims{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid jwt payload: %w", err)
+	}
+	return claims, nil
+}
+
+// numericDate converts a JWT NumericDate (seconds since the epoch, fractions allowed)
+func numericDate(claim string, n json.Number) (time.Time, error) {
+	var ts Timestamp
+	if err := ts.fromNumber(n, EpochSeconds); err != nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid jwt %s claim: %w", claim, err)
+	}
+	return ts.Resolve()
+}
+
+// humanizeDuration renders a duration as its two most significant units, e.g. "3d 4h"
+func humanizeDuration(d time.Duration) string {
+	if d < time.Second {
+		return "less than a second"
+	}
+
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	var parts []string
+	for _, u := range units {
+		if d >= u.size {
+			parts = append(parts, fmt.Sprintf("%d%s", d/u.size, u.name))
+			d %= u.size
+		}
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
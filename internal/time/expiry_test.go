@@ -0,0 +1,127 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testJWT      = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxIiwiaWF0IjoxNzAzNTA1NjAwLCJleHAiOjE3MDM1MjAwMDB9.sig"
+	testJWTNoExp = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxIn0.sig"
+)
+
+func TestTimeService_EvaluateExpiry(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name      string
+		input     ExpiryInput
+		expected  ExpiryResult
+		wantErr   bool
+		errSubstr string
+	}{
+		{
+			name:  "certificate not yet expired",
+			input: ExpiryInput{ExpiresAt: RFC3339Timestamp("2023-12-28T19:30:45Z"), Timezone: "America/New_York"},
+			expected: ExpiryResult{
+				ExpiresAt:        "2023-12-28T14:30:45-05:00",
+				EvaluatedAt:      "2023-12-25T10:30:45-05:00",
+				Timezone:         "America/New_York",
+				RemainingSeconds: 273600,
+				Remaining:        "76h0m0s",
+				Summary:          "expires in 3d 4h, at Thu, 28 Dec 2023 14:30 EST",
+				Source:           "expires_at",
+			},
+		},
+		{
+			name:  "expired epoch",
+			input: ExpiryInput{ExpiresAt: EpochTimestamp(now.Unix()-90, EpochSeconds)},
+			expected: ExpiryResult{
+				ExpiresAt:        "2023-12-25T15:29:15Z",
+				EvaluatedAt:      "2023-12-25T15:30:45Z",
+				Timezone:         "UTC",
+				Expired:          true,
+				RemainingSeconds: -90,
+				Remaining:        "-1m30s",
+				Summary:          "expired 1m 30s ago, at Mon, 25 Dec 2023 15:29 UTC",
+				Source:           "expires_at",
+			},
+		},
+		{
+			name:  "jwt exp claim",
+			input: ExpiryInput{JWT: testJWT},
+			expected: ExpiryResult{
+				ExpiresAt:        "2023-12-25T16:00:00Z",
+				EvaluatedAt:      "2023-12-25T15:30:45Z",
+				Timezone:         "UTC",
+				RemainingSeconds: 1755,
+				Remaining:        "29m15s",
+				Summary:          "expires in 29m 15s, at Mon, 25 Dec 2023 16:00 UTC",
+				Source:           "jwt",
+				IssuedAt:         "2023-12-25T12:00:00Z",
+			},
+		},
+		{
+			name:      "jwt without exp",
+			input:     ExpiryInput{JWT: testJWTNoExp},
+			wantErr:   true,
+			errSubstr: "no exp claim",
+		},
+		{
+			name:      "malformed jwt",
+			input:     ExpiryInput{JWT: "not-a-jwt"},
+			wantErr:   true,
+			errSubstr: "3 dot-separated parts",
+		},
+		{
+			name:      "both inputs",
+			input:     ExpiryInput{ExpiresAt: EpochTimestamp(0, EpochSeconds), JWT: testJWT},
+			wantErr:   true,
+			errSubstr: "not both",
+		},
+		{
+			name:      "no inputs",
+			input:     ExpiryInput{},
+			wantErr:   true,
+			errSubstr: "required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.EvaluateExpiry(tt.input)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errSubstr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{500 * time.Millisecond, "less than a second"},
+		{45 * time.Second, "45s"},
+		{3*time.Hour + 5*time.Second, "3h 5s"},
+		{400*24*time.Hour + 3*time.Hour + 2*time.Minute, "400d 3h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, humanizeDuration(tt.d))
+		})
+	}
+}
@@ -0,0 +1,474 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Date orders for samples whose numbers could be a day or a month
+const (
+	DateOrderDMY = "DMY"
+	DateOrderMDY = "MDY"
+)
+
+// Layout token kinds
+const (
+	LayoutTokenDirective = "directive"
+	LayoutTokenLiteral   = "literal"
+)
+
+// layoutMeanings explains each Go layout directive
+var layoutMeanings = map[string]string{
+	"2006": "year, four digits", "06": "year, two digits",
+	"01": "month, two digits (01-12)", "1": "month (1-12)",
+	"Jan": "month name, abbreviated (Jan)", "January": "month name (January)",
+	"02": "day of month, two digits (01-31)", "2": "day of month (1-31)", "_2": "day of month, space-padded ( 1-31)",
+	"002": "day of year, three digits (001-366)", "__2": "day of year, space-padded (  1-366)",
+	"Mon": "weekday, abbreviated (Mon)", "Monday": "weekday (Monday)",
+	"15": "hour, 24-hour clock (00-23)", "03": "hour, 12-hour clock, two digits (01-12)", "3": "hour, 12-hour clock (1-12)",
+	"04": "minute, two digits (00-59)", "4": "minute (0-59)",
+	"05": "second, two digits (00-59)", "5": "second (0-59)",
+	"PM": "AM or PM", "pm": "am or pm",
+	"MST":    "zone abbreviation (UTC, CET, EST)",
+	"-07:00": "UTC offset (+05:30)", "-0700": "UTC offset (+0530)", "-07": "UTC offset in hours (+05)",
+	"-07:00:00": "UTC offset with seconds (+05:30:00)", "-070000": "UTC offset with seconds (+053000)",
+	"Z07:00": "Z for UTC, otherwise the UTC offset (+05:30)", "Z0700": "Z for UTC, otherwise the UTC offset (+0530)",
+	"Z07":       "Z for UTC, otherwise the UTC offset in hours (+05)",
+	"Z07:00:00": "Z for UTC, otherwise the UTC offset with seconds", "Z070000": "Z for UTC, otherwise the UTC offset with seconds",
+}
+
+// layoutStrftime maps Go layout directives to their strftime equivalents
+var layoutStrftime = map[string]string{
+	"2006": "%Y", "06": "%y", "01": "%m", "Jan": "%b", "January": "%B",
+	"02": "%d", "_2": "%e", "002": "%j", "Mon": "%a", "Monday": "%A",
+	"15": "%H", "03": "%I", "04": "%M", "05": "%S", "PM": "%p",
+	"MST": "%Z", "-0700": "%z", "-07:00": "%:z",
+}
+
+// ExplainLayoutInput represents input for inferring or explaining a layout.
+// Exactly one of sample and layout is set
+type ExplainLayoutInput struct {
+	// Sample is a formatted time to infer a layout from, such as 25/12/2023 15:30
+	Sample string `json:"sample,omitempty"`
+	// Layout is a Go layout or strftime pattern to explain
+	Layout string `json:"layout,omitempty"`
+	// DateOrder reads samples such as 03/04/2024 as DMY (default) or MDY
+	DateOrder string `json:"date_order,omitempty"`
+}
+
+// LayoutToken is one directive or run of literal text in a layout
+type LayoutToken struct {
+	Text     string `json:"text"`
+	Kind     string `json:"kind"` // directive or literal
+	Meaning  string `json:"meaning,omitempty"`
+	Strftime string `json:"strftime,omitempty"`
+}
+
+// LayoutAlternative is another reading of an ambiguous sample
+type LayoutAlternative struct {
+	Layout   string `json:"layout"`
+	Strftime string `json:"strftime,omitempty"`
+	Parsed   string `json:"parsed"`
+}
+
+// ExplainLayoutResult explains a layout, inferred from a sample or given
+type ExplainLayoutResult struct {
+	Layout string `json:"layout"`
+	// Strftime is the equivalent strftime pattern, empty when a directive
+	// has no strftime equivalent
+	Strftime string        `json:"strftime,omitempty"`
+	Tokens   []LayoutToken `json:"tokens"`
+	// Example is the current time rendered with the layout, in the default timezone
+	Example string `json:"example"`
+	Sample  string `json:"sample,omitempty"`
+	// Parsed is the sample read with the layout, as RFC3339
+	Parsed string `json:"parsed,omitempty"`
+	// Alternatives are the readings of a sample with day and month swapped,
+	// when both are 12 or less
+	Alternatives []LayoutAlternative `json:"alternatives,omitempty"`
+	Warnings     []string            `json:"warnings,omitempty"`
+}
+
+// ExplainLayout infers the Go layout and strftime pattern of a sample
+// time, or explains what each directive of a given layout means
+func (s *timeService) ExplainLayout(input ExplainLayoutInput) (ExplainLayoutResult, error) {
+	sample, layout := strings.TrimSpace(input.Sample), input.Layout
+	switch {
+	case sample == "" && layout == "":
+		return ExplainLayoutResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "one of sample or layout is required")
+	case sample != "" && layout != "":
+		return ExplainLayoutResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "pass either sample or layout, not both")
+	}
+	order := strings.ToUpper(defaultString(input.DateOrder, DateOrderDMY))
+	if order != DateOrderDMY && order != DateOrderMDY {
+		return ExplainLayoutResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid date_order %s (must be one of: DMY, MDY)", input.DateOrder)
+	}
+	loc, err := s.loadLocation(s.defaultTimezone)
+	if err != nil {
+		return ExplainLayoutResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", s.defaultTimezone, err)
+	}
+
+	var result ExplainLayoutResult
+	if sample != "" {
+		inferred, err := inferLayout(sample, order == DateOrderDMY)
+		if err != nil {
+			return ExplainLayoutResult{}, err
+		}
+		parsed, err := time.ParseInLocation(inferred.layout, sample, loc)
+		if err != nil {
+			return ExplainLayoutResult{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "could not infer a layout for %q: %s does not read it back: %w", sample, inferred.layout, err)
+		}
+		layout = inferred.layout
+		result.Sample, result.Parsed, result.Warnings = sample, parsed.Format(time.RFC3339Nano), inferred.warnings
+		if inferred.swapped != "" {
+			if alternative, err := time.ParseInLocation(inferred.swapped, sample, loc); err == nil {
+				strftime, _ := layoutStrftimePattern(inferred.swapped)
+				result.Alternatives = []LayoutAlternative{{Layout: inferred.swapped, Strftime: strftime, Parsed: alternative.Format(time.RFC3339Nano)}}
+				result.Warnings = append(result.Warnings, fmt.Sprintf("day and month are both 12 or less, so the sample was read as %s; pass date_order to read it the other way", order))
+			}
+		}
+	} else if layout, err = layoutFor(layout); err != nil {
+		return ExplainLayoutResult{}, err
+	}
+
+	result.Layout = layout
+	result.Tokens = explainLayoutTokens(layout)
+	result.Example = s.clock.Now().In(loc).Format(layout)
+	strftime, missing := layoutStrftimePattern(layout)
+	if len(missing) > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("no strftime equivalent for %s", strings.Join(missing, ", ")))
+	} else {
+		result.Strftime = strftime
+	}
+
+	s.logger.Debug("Explained layout",
+		slog.String("sample", sample),
+		slog.String("layout", layout))
+
+	return result, nil
+}
+
+// explainLayoutTokens describes each directive and literal of a Go layout
+func explainLayoutTokens(layout string) []LayoutToken {
+	var tokens []LayoutToken
+	for _, chunk := range splitLayout(layout) {
+		if chunk.component == "" {
+			tokens = append(tokens, LayoutToken{Text: chunk.text, Kind: LayoutTokenLiteral})
+			continue
+		}
+		token := LayoutToken{Text: chunk.text, Kind: LayoutTokenDirective, Meaning: layoutMeanings[chunk.text]}
+		if chunk.component == layoutFraction {
+			digits := len(chunk.text) - 1
+			token.Meaning = fmt.Sprintf("fractional seconds, exactly %d digits", digits)
+			if chunk.text[1] == '9' {
+				token.Meaning = fmt.Sprintf("fractional seconds, up to %d digits with trailing zeros dropped", digits)
+			}
+		}
+		token.Strftime, _ = layoutStrftimePattern(chunk.text)
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// layoutStrftimePattern translates a Go layout into a strftime pattern,
+// listing the directives that have no strftime equivalent
+func layoutStrftimePattern(layout string) (string, []string) {
+	var pattern strings.Builder
+	var missing []string
+	for _, chunk := range splitLayout(layout) {
+		switch {
+		case chunk.component == "":
+			pattern.WriteString(strings.ReplaceAll(chunk.text, "%", "%%"))
+		case chunk.text == ".000000" || chunk.text == ",000000":
+			pattern.WriteString(chunk.text[:1] + "%f")
+		case layoutStrftime[chunk.text] != "":
+			pattern.WriteString(layoutStrftime[chunk.text])
+		default:
+			missing = append(missing, chunk.text)
+		}
+	}
+	return pattern.String(), missing
+}
+
+// sampleToken is a run of digits, a run of letters or a single other
+// character of a sample
+type sampleToken struct {
+	text   string
+	digits bool
+	letter bool
+	// layout is the directive the token was read as; literal text keeps
+	// its own text
+	layout string
+}
+
+// inferredLayout is the layout read from a sample, and the one with day and
+// month swapped when the sample can be read both ways
+type inferredLayout struct {
+	layout, swapped string
+	warnings        []string
+}
+
+// inferLayout reads a sample time into a Go layout: a clock time with
+// colons, month and weekday names, AM/PM, zones and offsets after the
+// time, and the remaining numbers as a date
+func inferLayout(sample string, dayFirst bool) (inferredLayout, error) {
+	tokens := splitSample(sample)
+	var result inferredLayout
+	cannot := func(format string, args ...any) (inferredLayout, error) {
+		return inferredLayout{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "could not infer a layout for %q: %s", sample, fmt.Sprintf(format, args...))
+	}
+
+	if len(tokens) == 1 && tokens[0].digits && len(tokens[0].text) >= 9 {
+		return cannot("it looks like a Unix epoch; use the Unix, UnixMilli, UnixMicro or UnixNano formats")
+	}
+
+	twelveHour := false
+	for _, t := range tokens {
+		if lower := strings.ToLower(t.text); t.letter && (lower == "am" || lower == "pm") {
+			twelveHour = true
+		}
+	}
+
+	// The clock time: h:mm, h:mm:ss, with an optional fraction
+	timeEnd := -1
+	for i := 0; i+2 < len(tokens) && timeEnd < 0; i++ {
+		if !tokens[i].digits || len(tokens[i].text) > 2 || tokens[i+1].text != ":" || !tokens[i+2].digits || len(tokens[i+2].text) != 2 {
+			continue
+		}
+		switch {
+		case !twelveHour:
+			tokens[i].layout = "15"
+		case len(tokens[i].text) == 2:
+			tokens[i].layout = "03"
+		default:
+			tokens[i].layout = "3"
+		}
+		tokens[i+2].layout = "04"
+		timeEnd = i + 3
+		if timeEnd+1 < len(tokens) && tokens[timeEnd].text == ":" && tokens[timeEnd+1].digits && len(tokens[timeEnd+1].text) == 2 {
+			tokens[timeEnd+1].layout = "05"
+			timeEnd += 2
+			if timeEnd+1 < len(tokens) && (tokens[timeEnd].text == "." || tokens[timeEnd].text == ",") && tokens[timeEnd+1].digits {
+				tokens[timeEnd].layout = tokens[timeEnd].text + strings.Repeat("0", len(tokens[timeEnd+1].text))
+				tokens[timeEnd+1].layout = ""
+				tokens[timeEnd+1].text = ""
+				timeEnd += 2
+			}
+		}
+	}
+
+	// An offset right after the time, possibly after a space
+	if timeEnd >= 0 {
+		i := timeEnd
+		if i < len(tokens) && tokens[i].text == " " && i+1 < len(tokens) && (tokens[i+1].text == "+" || tokens[i+1].text == "-") {
+			i++
+		}
+		switch {
+		case i < len(tokens) && tokens[i].text == "Z":
+			tokens[i].layout = "Z07:00"
+		case i+1 < len(tokens) && (tokens[i].text == "+" || tokens[i].text == "-") && tokens[i+1].digits:
+			offset := "-0700"
+			switch {
+			case len(tokens[i+1].text) == 2 && i+3 < len(tokens) && tokens[i+2].text == ":" && tokens[i+3].digits:
+				offset = "-07:00"
+				tokens[i+2].text, tokens[i+3].text = "", ""
+			case len(tokens[i+1].text) == 2:
+				offset = "-07"
+			case len(tokens[i+1].text) != 4:
+				return cannot("the offset %s%s is not hh, hhmm or hh:mm", tokens[i].text, tokens[i+1].text)
+			}
+			tokens[i].layout, tokens[i+1].text = offset, ""
+		}
+	}
+
+	// Names, AM/PM and zone abbreviations
+	monthName := false
+	for i, t := range tokens {
+		if !t.letter || t.layout != "" {
+			continue
+		}
+		switch name := strings.ToLower(t.text); {
+		case name == "am" || name == "pm":
+			if t.text != strings.ToUpper(t.text) && t.text != name {
+				return cannot("%s must be all upper or all lower case", t.text)
+			}
+			tokens[i].layout = "PM"
+			if t.text == name {
+				tokens[i].layout = "pm"
+			}
+		case matchesName(name, func(m int) string { return time.Month(m).String() }, 1, 12):
+			monthName = true
+			tokens[i].layout = "Jan"
+			if len(name) > 3 {
+				tokens[i].layout = "January"
+			}
+		case matchesName(name, func(d int) string { return time.Weekday(d).String() }, 0, 6):
+			tokens[i].layout = "Mon"
+			if len(name) > 3 {
+				tokens[i].layout = "Monday"
+			}
+		case len(t.text) >= 3 && len(t.text) <= 5 && t.text == strings.ToUpper(t.text):
+			tokens[i].layout = "MST"
+		case name == "st" || name == "nd" || name == "rd" || name == "th":
+			result.warnings = append(result.warnings, fmt.Sprintf("the ordinal suffix %s is kept as literal text, so days needing another suffix won't parse", t.text))
+		case len(splitLayout(t.text)) > 1 || splitLayout(t.text)[0].component != "":
+			return cannot("the text %q would be read as a Go layout directive, and Go layouts cannot escape it", t.text)
+		}
+	}
+
+	// The remaining numbers are the date
+	var date []int
+	for i, t := range tokens {
+		if t.digits && t.layout == "" && t.text != "" {
+			date = append(date, i)
+		}
+	}
+	year, rest := -1, []int{}
+	for _, i := range date {
+		switch {
+		case len(tokens[i].text) == 4 && year < 0:
+			year = i
+			tokens[i].layout = "2006"
+		case len(tokens[i].text) == 3 && year >= 0:
+			tokens[i].layout = "002"
+		case len(tokens[i].text) <= 2:
+			rest = append(rest, i)
+		default:
+			return cannot("%s is not a day, month or year", tokens[i].text)
+		}
+	}
+
+	day, month, ambiguous := -1, -1, false
+	switch {
+	case monthName && len(rest) == 1:
+		day = rest[0]
+	case monthName && len(rest) == 2 && year < 0:
+		day = rest[0]
+		tokens[rest[1]].layout = "06"
+	case !monthName && len(rest) == 3 && year < 0:
+		tokens[rest[2]].layout = "06"
+		rest = rest[:2]
+		fallthrough
+	case !monthName && len(rest) == 2:
+		a, b := rest[0], rest[1]
+		va, _ := strconv.Atoi(tokens[a].text)
+		vb, _ := strconv.Atoi(tokens[b].text)
+		switch {
+		case year >= 0 && year < a:
+			// Year first is ISO order, year-month-day
+			month, day = a, b
+			if va > 12 {
+				month, day = b, a
+			}
+		case va > 12:
+			day, month = a, b
+		case vb > 12:
+			month, day = a, b
+		default:
+			day, month = a, b
+			if !dayFirst {
+				day, month = b, a
+			}
+			ambiguous = va != vb
+		}
+		if year < 0 && tokens[rest[len(rest)-1]].layout != "06" {
+			result.warnings = append(result.warnings, "the sample has no year, so it parses as year 0")
+		}
+	case len(rest) == 0 && (year >= 0 || monthName):
+	case len(rest) == 0 && len(date) == 0:
+		if timeEnd < 0 {
+			return cannot("it has no date or clock time")
+		}
+	default:
+		return cannot("%d numbers besides the time don't read as a day, month and year", len(rest))
+	}
+	if day >= 0 {
+		tokens[day].layout = padded(tokens[day].text, "02", "2")
+	}
+	if month >= 0 {
+		tokens[month].layout = padded(tokens[month].text, "01", "1")
+	}
+	if day >= 0 {
+		if v, _ := strconv.Atoi(tokens[day].text); v < 1 || v > 31 {
+			return cannot("%s is not a day of the month", tokens[day].text)
+		}
+	}
+	if month >= 0 {
+		if v, _ := strconv.Atoi(tokens[month].text); v < 1 || v > 12 {
+			return cannot("%s is not a month", tokens[month].text)
+		}
+	}
+
+	result.layout = joinSample(tokens)
+	if ambiguous {
+		tokens[day].layout = padded(tokens[day].text, "01", "1")
+		tokens[month].layout = padded(tokens[month].text, "02", "2")
+		result.swapped = joinSample(tokens)
+	}
+	return result, nil
+}
+
+// splitSample splits a sample into runs of digits, runs of letters and
+// single other characters
+func splitSample(sample string) []sampleToken {
+	var tokens []sampleToken
+	runes := []rune(sample)
+	for i := 0; i < len(runes); {
+		j := i + 1
+		switch {
+		case unicode.IsDigit(runes[i]):
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, sampleToken{text: string(runes[i:j]), digits: true})
+		case unicode.IsLetter(runes[i]):
+			for j < len(runes) && unicode.IsLetter(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, sampleToken{text: string(runes[i:j]), letter: true})
+		default:
+			tokens = append(tokens, sampleToken{text: string(runes[i])})
+		}
+		i = j
+	}
+	return tokens
+}
+
+// joinSample assembles the layout read from a sample's tokens
+func joinSample(tokens []sampleToken) string {
+	var layout strings.Builder
+	for _, t := range tokens {
+		if t.layout != "" {
+			layout.WriteString(t.layout)
+		} else {
+			layout.WriteString(t.text)
+		}
+	}
+	return layout.String()
+}
+
+// matchesName reports whether name is one of the names from first to last,
+// in full or abbreviated to three letters
+func matchesName(name string, names func(int) string, first, last int) bool {
+	for n := first; n <= last; n++ {
+		full := strings.ToLower(names(n))
+		if name == full || name == full[:3] {
+			return true
+		}
+	}
+	return false
+}
+
+// padded returns the zero-padded directive for two-digit values
+func padded(value, zeroPadded, plain string) string {
+	if len(value) == 2 {
+		return zeroPadded
+	}
+	return plain
+}
@@ -0,0 +1,108 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestInferLayout(t *testing.T) {
+	tests := []struct {
+		sample   string
+		mdy      bool
+		expected string
+		swapped  string
+		errPart  string
+	}{
+		{sample: "25/12/2023 15:30", expected: "02/01/2006 15:04"},
+		{sample: "12/25/23 3:04 PM", expected: "01/02/06 3:04 PM"},
+		{sample: "03/04/2024", expected: "02/01/2006", swapped: "01/02/2006"},
+		{sample: "03/04/2024", mdy: true, expected: "01/02/2006", swapped: "02/01/2006"},
+		{sample: "2023-12-25T15:30:45.123+05:30", expected: "2006-01-02T15:04:05.000-07:00"},
+		{sample: "2023-12-25T15:30:45Z", expected: "2006-01-02T15:04:05Z07:00"},
+		{sample: "Mon, 25 Dec 2023 15:30:45 GMT", expected: "Mon, 02 Jan 2006 15:04:05 MST"},
+		{sample: "December 5, 2023 at 9:05am", expected: "January 2, 2006 at 3:04pm"},
+		{sample: "5.1.2024 08:00:00 +0100", expected: "2.1.2006 15:04:05 -0700", swapped: "1.2.2006 15:04:05 -0700"},
+		{sample: "2023-359", expected: "2006-002"},
+		{sample: "1703518245", errPart: "it looks like a Unix epoch"},
+		{sample: "12/13/2023", mdy: true, expected: "01/02/2006"},
+		{sample: "25/13/2023", errPart: "13 is not a month"},
+		{sample: "hello", errPart: "it has no date or clock time"},
+		{sample: "1/2/3/4", errPart: "don't read as a day, month and year"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sample, func(t *testing.T) {
+			inferred, err := inferLayout(tt.sample, !tt.mdy)
+			if tt.errPart != "" {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+				assert.Contains(t, err.Error(), tt.errPart)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, inferred.layout)
+			assert.Equal(t, tt.swapped, inferred.swapped)
+		})
+	}
+}
+
+func TestTimeService_ExplainLayout(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	result, err := service.ExplainLayout(ExplainLayoutInput{Sample: "25/12/2023 15:30"})
+	require.NoError(t, err)
+	assert.Equal(t, "02/01/2006 15:04", result.Layout)
+	assert.Equal(t, "%d/%m/%Y %H:%M", result.Strftime)
+	assert.Equal(t, "2023-12-25T15:30:00Z", result.Parsed)
+	assert.Equal(t, "25/12/2023 15:30", result.Example)
+	assert.Empty(t, result.Alternatives)
+
+	result, err = service.ExplainLayout(ExplainLayoutInput{Sample: "03/04/2024"})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-04-03T00:00:00Z", result.Parsed)
+	require.Len(t, result.Alternatives, 1)
+	assert.Equal(t, LayoutAlternative{Layout: "01/02/2006", Strftime: "%m/%d/%Y", Parsed: "2024-03-04T00:00:00Z"}, result.Alternatives[0])
+	assert.Contains(t, result.Warnings[0], "read as DMY")
+
+	result, err = service.ExplainLayout(ExplainLayoutInput{Layout: "Jan _2 15:04:05.000"})
+	require.NoError(t, err)
+	assert.Equal(t, []LayoutToken{
+		{Text: "Jan", Kind: LayoutTokenDirective, Meaning: "month name, abbreviated (Jan)", Strftime: "%b"},
+		{Text: " ", Kind: LayoutTokenLiteral},
+		{Text: "_2", Kind: LayoutTokenDirective, Meaning: "day of month, space-padded ( 1-31)", Strftime: "%e"},
+		{Text: " ", Kind: LayoutTokenLiteral},
+		{Text: "15", Kind: LayoutTokenDirective, Meaning: "hour, 24-hour clock (00-23)", Strftime: "%H"},
+		{Text: ":", Kind: LayoutTokenLiteral},
+		{Text: "04", Kind: LayoutTokenDirective, Meaning: "minute, two digits (00-59)", Strftime: "%M"},
+		{Text: ":", Kind: LayoutTokenLiteral},
+		{Text: "05", Kind: LayoutTokenDirective, Meaning: "second, two digits (00-59)", Strftime: "%S"},
+		{Text: ".000", Kind: LayoutTokenDirective, Meaning: "fractional seconds, exactly 3 digits"},
+	}, result.Tokens)
+	assert.Empty(t, result.Strftime)
+	assert.Equal(t, []string{"no strftime equivalent for .000"}, result.Warnings)
+	assert.Equal(t, "Dec 25 15:30:45.000", result.Example)
+
+	result, err = service.ExplainLayout(ExplainLayoutInput{Layout: "%Y-%m-%dT%H:%M:%S.%f"})
+	require.NoError(t, err)
+	assert.Equal(t, "2006-01-02T15:04:05.000000", result.Layout)
+	assert.Equal(t, "%Y-%m-%dT%H:%M:%S.%f", result.Strftime)
+
+	_, err = service.ExplainLayout(ExplainLayoutInput{Layout: "2023-12-25"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidFormat))
+
+	_, err = service.ExplainLayout(ExplainLayoutInput{})
+	assert.ErrorContains(t, err, "one of sample or layout is required")
+
+	_, err = service.ExplainLayout(ExplainLayoutInput{Sample: "25/12/2023", Layout: "02/01/2006"})
+	assert.ErrorContains(t, err, "not both")
+
+	_, err = service.ExplainLayout(ExplainLayoutInput{Sample: "25/12/2023", DateOrder: "YMD"})
+	assert.ErrorContains(t, err, "invalid date_order YMD")
+}
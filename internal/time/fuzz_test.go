@@ -0,0 +1,62 @@
+package time
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+var fuzzFormats = []string{"RFC3339", "RFC3339Nano", "Unix", "UnixMilli", "UnixMicro", "UnixNano", "Layout", "EpochDay", "Ordinal", "RataDie", "MySQLDateTime", "PostgresTimestamptz", "JulianDay", "GPSSeconds", "GPSWeek", "TLEEpoch"}
+
+func FuzzParseTime(f *testing.F) {
+	service := NewTimeService("UTC", "RFC3339", fuzzFormats, slog.New(slog.NewTextHandler(io.Discard, nil))).(*timeService)
+
+	seeds := []struct {
+		timeStr, format, timezone string
+	}{
+		{"2023-12-25T15:30:45Z", "RFC3339", ""},
+		{"2023-12-25T15:30:45.123456789+05:45", "RFC3339Nano", "Asia/Kathmandu"},
+		{"1703518245", "Unix", "America/New_York"},
+		{"-9223372036854775808", "UnixMilli", ""},
+		{"9223372036854775807", "UnixNano", ""},
+		{"99999999999999999999999", "Unix", ""},
+		{"2023-12-25 15:30:45", "2006-01-02 15:04:05", "Europe/London"},
+		{"", "", ""},
+		{"\x00\xff", "%Y-%m-%d", "../../etc/passwd"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.timeStr, seed.format, seed.timezone)
+	}
+
+	f.Fuzz(func(t *testing.T, timeStr, format, timezone string) {
+		if _, err := service.parseTimeInternal(timeStr, format, time.UTC); err != nil {
+			return
+		}
+		_, _ = service.ParseTime(ParseTimeInput{TimeString: timeStr, Format: format, Timezone: timezone})
+	})
+}
+
+func FuzzFormatTime(f *testing.F) {
+	service := NewTimeService("UTC", "RFC3339", append(fuzzFormats, "2006-01-02 15:04:05"), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	f.Add([]byte(`"2023-12-25T15:30:45Z"`), "RFC3339", "UTC")
+	f.Add([]byte(`1703518245.5`), "UnixNano", "Asia/Tokyo")
+	f.Add([]byte(`-9223372036854775808`), "Unix", "")
+	f.Add([]byte(`1e300`), "Layout", "Invalid/Zone")
+	f.Add([]byte(`"99999999999999999999999"`), "Unix", "")
+	f.Add([]byte(`{"epoch": 1703518245123, "unit": "ms"}`), "2006-01-02 15:04:05", "")
+	f.Add([]byte(`{"rfc3339": "2023-12-25T15:30:45+05:45", "unit": "ns"}`), "RFC3339", "")
+	f.Add([]byte(`[1, 2]`), "RFC3339", "")
+
+	f.Fuzz(func(t *testing.T, data []byte, format, timezone string) {
+		var timestamp Timestamp
+		if err := json.Unmarshal(data, &timestamp); err != nil {
+			return
+		}
+		_, _ = service.FormatTime(FormatTimeInput{Timestamp: timestamp, Format: format, Timezone: timezone})
+		_, _ = timestamp.MarshalJSON()
+		_ = timestamp.String()
+	})
+}
@@ -0,0 +1,196 @@
+package time
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Bounds on one gap, so counting its missing points stays cheap and
+// Duration arithmetic can't overflow
+const (
+	maxGapSpan  = 100 * 365 * 24 * time.Hour
+	maxGapSlots = 1000000000
+)
+
+// DetectGapsInput represents input for auditing a time series against an
+// expected interval
+type DetectGapsInput struct {
+	Timestamps []Timestamp `json:"timestamps"`
+	// Interval is a Go duration ("5m", "1h") or a number of calendar days ("1d")
+	Interval string `json:"interval"`
+	// Tolerance is how late a point may be and still fill its slot, a Go
+	// duration; defaults to 0
+	Tolerance string `json:"tolerance,omitempty"`
+	Timezone  string `json:"timezone,omitempty"` // timezone for rendered times and calendar days, defaults to the server default
+}
+
+// MissingWindow is a run of expected points missing between two points
+type MissingWindow struct {
+	After        string `json:"after"`  // the last point before the gap
+	Before       string `json:"before"` // the first point after the gap
+	FirstMissing string `json:"first_missing"`
+	LastMissing  string `json:"last_missing"`
+	Missing      int    `json:"missing"`
+}
+
+// DuplicatePoint is an instant that occurs more than once
+type DuplicatePoint struct {
+	Timestamp string `json:"timestamp"`
+	Count     int    `json:"count"`
+}
+
+// DetectGapsResult represents the missing and duplicated points of a time series
+type DetectGapsResult struct {
+	Interval string `json:"interval"`
+	Timezone string `json:"timezone"`
+	Count    int    `json:"count"`
+	// Missing is the number of expected points missing across all gaps
+	Missing    int              `json:"missing"`
+	Gaps       []MissingWindow  `json:"gaps"`
+	Duplicates []DuplicatePoint `json:"duplicates"`
+	// OutOfOrder counts timestamps earlier than the one before them; they
+	// are sorted before looking for gaps
+	OutOfOrder int `json:"out_of_order"`
+}
+
+// DetectGaps finds the points missing from a time series expected every
+// interval, and the points that occur more than once. The expected grid
+// restarts at each point, so a late point doesn't shift later gaps.
+func (s *timeService) DetectGaps(input DetectGapsInput) (DetectGapsResult, error) {
+	if len(input.Timestamps) == 0 {
+		return DetectGapsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamps cannot be empty")
+	}
+	interval, err := parseBucketWindow(input.Interval)
+	if err != nil {
+		return DetectGapsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid interval: %w", err)
+	}
+	var tolerance time.Duration
+	if input.Tolerance != "" {
+		if tolerance, err = time.ParseDuration(input.Tolerance); err != nil {
+			return DetectGapsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid tolerance: %w", err)
+		}
+		if tolerance < 0 {
+			return DetectGapsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "tolerance cannot be negative")
+		}
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return DetectGapsResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	// Every timestamp can follow a gap or repeat the one before
+	perTimestamp := timeBytes + missingWindowBytes + duplicateBytes
+	if err := s.checkBulkBudget("detect_gaps", int64(len(input.Timestamps)), perTimestamp); err != nil {
+		return DetectGapsResult{}, err
+	}
+
+	times, release, err := resolveTimestamps(input.Timestamps)
+	if err != nil {
+		return DetectGapsResult{}, err
+	}
+	defer release()
+	result := DetectGapsResult{
+		Interval:   input.Interval,
+		Timezone:   timezone,
+		Count:      len(times),
+		Gaps:       []MissingWindow{},
+		Duplicates: []DuplicatePoint{},
+	}
+	for i := 1; i < len(times); i++ {
+		if times[i].Before(times[i-1]) {
+			result.OutOfOrder++
+		}
+	}
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	render := func(t time.Time) string { return t.In(loc).Format(time.RFC3339Nano) }
+	for i := 1; i < len(times); i++ {
+		prev, current := times[i-1], times[i]
+		if current.Equal(prev) {
+			if n := len(result.Duplicates); n > 0 && i >= 2 && times[i-2].Equal(prev) {
+				result.Duplicates[n-1].Count++
+			} else {
+				result.Duplicates = append(result.Duplicates, DuplicatePoint{Timestamp: render(current), Count: 2})
+			}
+			continue
+		}
+
+		missing, err := interval.missingSlots(prev, current, tolerance, loc)
+		if err != nil {
+			return DetectGapsResult{}, err
+		}
+		if missing == 0 {
+			continue
+		}
+		result.Missing += missing
+		result.Gaps = append(result.Gaps, MissingWindow{
+			After:        render(prev),
+			Before:       render(current),
+			FirstMissing: render(interval.slot(prev, 1, loc)),
+			LastMissing:  render(interval.slot(prev, missing, loc)),
+			Missing:      missing,
+		})
+	}
+
+	s.logger.Debug("Detected gaps",
+		slog.String("interval", input.Interval),
+		slog.Int("gaps", len(result.Gaps)),
+		slog.Int("missing", result.Missing),
+		slog.Int("duplicates", len(result.Duplicates)))
+
+	return result, nil
+}
+
+// slot returns the k-th point expected after t: k intervals later, or k
+// calendar days later at the same wall time
+func (w bucketWindow) slot(t time.Time, k int, loc *time.Location) time.Time {
+	if w.days > 0 {
+		return t.In(loc).AddDate(0, 0, k*w.days)
+	}
+	return t.Add(time.Duration(k) * w.duration)
+}
+
+// missingSlots counts the points expected after prev that are more than
+// tolerance earlier than current. Gaps longer than maxGapSpan or missing
+// more than maxGapSlots points are rejected
+func (w bucketWindow) missingSlots(prev, current time.Time, tolerance time.Duration, loc *time.Location) (int, error) {
+	span := current.Sub(prev)
+	if span > maxGapSpan {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"points %s and %s are more than %d years apart, split the series", prev.Format(time.RFC3339), current.Format(time.RFC3339), maxGapSpan/(365*24*time.Hour))
+	}
+	if span <= tolerance {
+		return 0, nil
+	}
+
+	// Windows longer than the span miss nothing, and their length may not
+	// fit a Duration
+	length := w.duration
+	if w.days > 0 {
+		if w.days > int(maxGapSpan/(24*time.Hour)) {
+			return 0, nil
+		}
+		length = time.Duration(w.days) * 24 * time.Hour
+	}
+	if (span-tolerance)/length > maxGapSlots {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"more than %d points missing between %s and %s, use a larger interval", maxGapSlots, prev.Format(time.RFC3339), current.Format(time.RFC3339))
+	}
+	missed := func(k int) bool { return w.slot(prev, k, loc).Add(tolerance).Before(current) }
+
+	// Estimate from the length, then adjust for DST-shortened or -lengthened
+	// days. The estimate is at most maxGapSlots, and the slots checked are
+	// within twice maxGapSpan of prev, so neither loop can overflow
+	k := int((span - tolerance) / length)
+	for k > 0 && !missed(k) {
+		k--
+	}
+	for missed(k + 1) {
+		k++
+	}
+	return k, nil
+}
@@ -0,0 +1,89 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_DetectGaps(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+	base := time.Date(2023, 12, 25, 15, 0, 0, 0, time.UTC)
+	at := func(minutes ...int) []Timestamp {
+		var timestamps []Timestamp
+		for _, m := range minutes {
+			timestamps = append(timestamps, TimestampFromTime(base.Add(time.Duration(m)*time.Minute)))
+		}
+		return timestamps
+	}
+
+	result, err := service.DetectGaps(DetectGapsInput{Timestamps: at(0, 5, 10, 25, 25, 25, 30, 45), Interval: "5m"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, result.Count)
+	assert.Equal(t, 4, result.Missing)
+	assert.Equal(t, []MissingWindow{
+		{After: "2023-12-25T15:10:00Z", Before: "2023-12-25T15:25:00Z", FirstMissing: "2023-12-25T15:15:00Z", LastMissing: "2023-12-25T15:20:00Z", Missing: 2},
+		{After: "2023-12-25T15:30:00Z", Before: "2023-12-25T15:45:00Z", FirstMissing: "2023-12-25T15:35:00Z", LastMissing: "2023-12-25T15:40:00Z", Missing: 2},
+	}, result.Gaps)
+	assert.Equal(t, []DuplicatePoint{{Timestamp: "2023-12-25T15:25:00Z", Count: 3}}, result.Duplicates)
+	assert.Zero(t, result.OutOfOrder)
+
+	// Late points within the tolerance fill their slot
+	late := at(0, 5, 10)
+	late[1] = TimestampFromTime(base.Add(5*time.Minute + 20*time.Second))
+	result, err = service.DetectGaps(DetectGapsInput{Timestamps: late, Interval: "5m", Tolerance: "30s"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Gaps)
+
+	result, err = service.DetectGaps(DetectGapsInput{Timestamps: at(10, 0, 5), Interval: "5m"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.OutOfOrder)
+	assert.Empty(t, result.Gaps)
+
+	// Calendar days keep the wall time across the DST change on 2024-03-31
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	result, err = service.DetectGaps(DetectGapsInput{
+		Timestamps: []Timestamp{
+			TimestampFromTime(time.Date(2024, 3, 29, 2, 30, 0, 0, paris)),
+			TimestampFromTime(time.Date(2024, 4, 2, 2, 30, 0, 0, paris)),
+		},
+		Interval: "1d",
+		Timezone: "Europe/Paris",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Gaps, 1)
+	assert.Equal(t, 3, result.Gaps[0].Missing)
+	assert.Equal(t, "2024-03-30T02:30:00+01:00", result.Gaps[0].FirstMissing)
+	assert.Equal(t, "2024-04-01T02:30:00+02:00", result.Gaps[0].LastMissing)
+
+	// Wide spans are rejected instead of counted slot by slot
+	wide := []Timestamp{EpochTimestamp(0, EpochSeconds), EpochTimestamp(1e10, EpochSeconds)}
+	for _, interval := range []string{"1h", "1s", "1d"} {
+		_, err = service.DetectGaps(DetectGapsInput{Timestamps: wide, Interval: interval})
+		assert.ErrorContains(t, err, "more than 100 years apart", interval)
+		assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	}
+	_, err = service.DetectGaps(DetectGapsInput{Timestamps: at(0, 5), Interval: "1ns"})
+	assert.ErrorContains(t, err, "more than 1000000000 points missing")
+	result, err = service.DetectGaps(DetectGapsInput{Timestamps: []Timestamp{EpochTimestamp(0, EpochSeconds), EpochTimestamp(1e8, EpochSeconds)}, Interval: "1s"})
+	require.NoError(t, err)
+	assert.Equal(t, 99999999, result.Missing)
+	result, err = service.DetectGaps(DetectGapsInput{Timestamps: at(0, 5), Interval: "40000d"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Gaps)
+
+	_, err = service.DetectGaps(DetectGapsInput{Timestamps: at(0), Interval: "0s"})
+	assert.ErrorContains(t, err, "invalid interval")
+
+	_, err = service.DetectGaps(DetectGapsInput{Timestamps: at(0), Interval: "5m", Tolerance: "-1s"})
+	assert.ErrorContains(t, err, "tolerance cannot be negative")
+
+	_, err = service.DetectGaps(DetectGapsInput{Interval: "5m"})
+	assert.ErrorContains(t, err, "timestamps cannot be empty")
+}
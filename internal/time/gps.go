@@ -0,0 +1,161 @@
+package time
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+const (
+	// gpsEpochUnix is the start of GPS time, 1980-01-06T00:00:00Z
+	gpsEpochUnix = 315964800
+	// gpsTAIOffset is how far GPS time runs behind TAI; GPS time was UTC
+	// at its epoch and has not taken leap seconds since
+	gpsTAIOffset   = 19
+	secondsPerWeek = 7 * 86400
+	// tleDayUnit is the resolution of a TLE epoch, 1e-8 of a day
+	tleDayUnit = 864 * time.Microsecond
+)
+
+// tleEpochPattern matches a TLE epoch: a two-digit year, the day of the
+// year, and a fraction of the day
+var tleEpochPattern = regexp.MustCompile(`^(\d{2})(\d{3})(\.\d*)?$`)
+
+// decimalSecondsPattern matches a non-negative decimal number of seconds
+var decimalSecondsPattern = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// gpsSeconds returns the whole and nanosecond parts of GPS time at t:
+// seconds since the GPS epoch, counting leap seconds
+func gpsSeconds(t time.Time, format FormatType) (int64, int, error) {
+	if t.Unix() < gpsEpochUnix {
+		return 0, 0, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s cannot be written as %s: GPS time starts at 1980-01-06T00:00:00Z", t.Format(time.RFC3339Nano), format)
+	}
+	return t.Unix() - gpsEpochUnix + taiMinusUTC(t) - gpsTAIOffset, t.Nanosecond(), nil
+}
+
+// gpsTime returns the UTC instant of whole GPS seconds plus nanos. The
+// second a leap second inserts reads as the midnight after it.
+func gpsTime(seconds int64, nanos int) time.Time {
+	offset := int64(0)
+	for _, leap := range leapSeconds {
+		if leap.taiMinusUTC <= gpsTAIOffset {
+			continue
+		}
+		// the GPS second the new offset starts at
+		if leap.unix-gpsEpochUnix+leap.taiMinusUTC-gpsTAIOffset > seconds {
+			break
+		}
+		offset = leap.taiMinusUTC - gpsTAIOffset
+	}
+	return time.Unix(seconds+gpsEpochUnix-offset, int64(nanos)).UTC()
+}
+
+// formatGPS writes t as GPS seconds, or as a GPS week and the seconds into it
+func formatGPS(t time.Time, format FormatType) (string, error) {
+	seconds, nanos, err := gpsSeconds(t, format)
+	if err != nil {
+		return "", err
+	}
+	if format == FormatGPSWeek {
+		return fmt.Sprintf("%d %s", seconds/secondsPerWeek, decimalSeconds(seconds%secondsPerWeek, nanos)), nil
+	}
+	return decimalSeconds(seconds, nanos), nil
+}
+
+// parseGPS reads GPS seconds, or a GPS week and the seconds into it
+// separated by a space or colon
+func parseGPS(s string, format FormatType) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var week int64
+	if format == FormatGPSWeek {
+		fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ':' })
+		if len(fields) != 2 {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be a week and seconds of the week, such as 2294 314463", format, s)
+		}
+		var err error
+		if week, err = strconv.ParseInt(fields[0], 10, 64); err != nil || week < 0 {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid GPS week %q: must be a whole number of weeks since 1980-01-06", fields[0])
+		}
+		s = fields[1]
+	}
+
+	seconds, nanos, err := parseDecimalSeconds(s)
+	if err != nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be a non-negative number of seconds", format, s)
+	}
+	if format == FormatGPSWeek && seconds >= secondsPerWeek {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "seconds of the week %s is out of range: must be below %d", s, secondsPerWeek)
+	}
+	// Keep the instant within the years RFC3339 can write
+	const maxGPSSeconds = 253402300799 - gpsEpochUnix
+	if week > maxGPSSeconds/secondsPerWeek || week*secondsPerWeek+seconds > maxGPSSeconds {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s %s is past the year 9999", format, s)
+	}
+	return gpsTime(week*secondsPerWeek+seconds, nanos), nil
+}
+
+// formatTLEEpoch writes t as the epoch of a two-line element set: a
+// two-digit year, the day of the year, and 8 decimals of the day
+func formatTLEEpoch(t time.Time) (string, error) {
+	t = t.UTC().Round(tleDayUnit)
+	if t.Year() < 1957 || t.Year() > 2056 {
+		return "", timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s cannot be written as %s: two-line elements cover 1957 to 2056", t.Format(time.RFC3339Nano), FormatTLEEpoch)
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return fmt.Sprintf("%02d%03d.%08d", t.Year()%100, t.YearDay(), t.Sub(midnight)/tleDayUnit), nil
+}
+
+// parseTLEEpoch reads the epoch of a two-line element set. Years 57 to 99
+// are 1957 to 1999, and 00 to 56 are 2000 to 2056.
+func parseTLEEpoch(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	match := tleEpochPattern.FindStringSubmatch(s)
+	if match == nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be YYDDD.DDDDDDDD, such as 23359.64635417", FormatTLEEpoch, s)
+	}
+	year, _ := strconv.Atoi(match[1])
+	year += 2000
+	if year >= 2057 {
+		year -= 100
+	}
+	day, _ := strconv.Atoi(match[2])
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if day < 1 || day > start.AddDate(1, 0, -1).YearDay() {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "day %d is out of range for %d", day, year)
+	}
+	fraction := 0.0
+	if len(match[3]) > 1 {
+		fraction, _ = strconv.ParseFloat("0"+match[3], 64)
+	}
+	offset := time.Duration(fraction * float64(24*time.Hour)).Round(time.Microsecond)
+	return start.AddDate(0, 0, day-1).Add(offset), nil
+}
+
+// decimalSeconds writes seconds and nanoseconds as a decimal without
+// trailing zeros
+func decimalSeconds(seconds int64, nanos int) string {
+	if nanos == 0 {
+		return strconv.FormatInt(seconds, 10)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%09d", seconds, nanos), "0")
+}
+
+// parseDecimalSeconds reads a non-negative decimal number of seconds,
+// truncated to the nanosecond
+func parseDecimalSeconds(s string) (int64, int, error) {
+	if !decimalSecondsPattern.MatchString(s) {
+		return 0, 0, fmt.Errorf("invalid seconds %q", s)
+	}
+	whole, fraction, _ := strings.Cut(s, ".")
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	fraction = (fraction + "000000000")[:9]
+	nanos, _ := strconv.Atoi(fraction)
+	return seconds, nanos, nil
+}
@@ -0,0 +1,88 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestGPSFormats(t *testing.T) {
+	tests := []struct {
+		format   FormatType
+		at       time.Time
+		expected string
+	}{
+		{format: FormatGPSSeconds, at: time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), expected: "0"},
+		{format: FormatGPSSeconds, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "1387553463"},
+		{format: FormatGPSSeconds, at: time.Date(2023, 12, 25, 15, 30, 45, 250000000, time.UTC), expected: "1387553463.25"},
+		// 17 leap seconds before 2017, 18 after
+		{format: FormatGPSSeconds, at: time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC), expected: "1167264016"},
+		{format: FormatGPSSeconds, at: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), expected: "1167264018"},
+		{format: FormatGPSWeek, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "2294 142263"},
+		{format: FormatGPSWeek, at: time.Date(1999, 8, 22, 0, 0, 0, 0, time.UTC), expected: "1024 13"},
+		{format: FormatTLEEpoch, at: time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC), expected: "23359.64635417"},
+		{format: FormatTLEEpoch, at: time.Date(1998, 1, 1, 12, 0, 0, 0, time.UTC), expected: "98001.50000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format)+"/"+tt.expected, func(t *testing.T) {
+			formatted, err := renderLayout(tt.at, string(tt.format))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, formatted)
+
+			var parsed time.Time
+			if tt.format == FormatTLEEpoch {
+				parsed, err = parseTLEEpoch(tt.expected)
+			} else {
+				parsed, err = parseGPS(tt.expected, tt.format)
+			}
+			require.NoError(t, err)
+			// TLE epochs keep 1e-8 of a day, under a millisecond
+			assert.WithinDuration(t, tt.at, parsed, time.Millisecond)
+		})
+	}
+}
+
+func TestParseGPS(t *testing.T) {
+	// The leap second itself reads as the midnight after it
+	parsed, err := parseGPS("1167264017", FormatGPSSeconds)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), parsed)
+
+	parsed, err = parseGPS("2294:142263.5", FormatGPSWeek)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 12, 25, 15, 30, 45, 500000000, time.UTC), parsed)
+
+	parsed, err = parseTLEEpoch("57001.5")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(1957, 1, 1, 12, 0, 0, 0, time.UTC), parsed)
+
+	_, err = parseGPS("2294 604800", FormatGPSWeek)
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	_, err = parseGPS("2294", FormatGPSWeek)
+	assert.ErrorContains(t, err, "must be a week and seconds of the week")
+
+	_, err = parseGPS("-1", FormatGPSSeconds)
+	assert.True(t, errors.Is(err, timeerrors.ErrParseFailure))
+
+	_, err = parseGPS("9999999999999", FormatGPSSeconds)
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	_, err = parseTLEEpoch("23366.0")
+	assert.ErrorContains(t, err, "day 366 is out of range for 2023")
+
+	_, err = parseTLEEpoch("2023-359")
+	assert.True(t, errors.Is(err, timeerrors.ErrParseFailure))
+
+	_, err = renderLayout(time.Date(1980, 1, 5, 0, 0, 0, 0, time.UTC), string(FormatGPSSeconds))
+	assert.ErrorContains(t, err, "GPS time starts at 1980-01-06")
+
+	_, err = renderLayout(time.Date(2057, 1, 1, 0, 0, 0, 0, time.UTC), string(FormatTLEEpoch))
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+}
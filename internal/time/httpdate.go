@@ -0,0 +1,169 @@
+package time
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Retry-After value kinds
+const (
+	RetryAfterDelaySeconds = "delay_seconds"
+	RetryAfterHTTPDate     = "http_date"
+)
+
+// HTTPDateInput represents input for rendering an HTTP Date header
+type HTTPDateInput struct {
+	At Timestamp `json:"at,omitempty"` // instant to render, defaults to now
+	// Value is an HTTP-date to normalize instead, in IMF-fixdate or either
+	// obsolete form (RFC 850, asctime)
+	Value string `json:"value,omitempty"`
+}
+
+// HTTPDateResult represents an instant as an HTTP-date
+type HTTPDateResult struct {
+	Date        string `json:"date"` // IMF-fixdate, e.g. Mon, 25 Dec 2023 15:30:45 GMT
+	Timestamp   string `json:"timestamp"`
+	UnixSeconds int64  `json:"unix_seconds"`
+	// Truncated is set when the instant had sub-second precision, which
+	// HTTP-dates drop
+	Truncated bool `json:"truncated"`
+}
+
+// RetryAfterInput represents input for interpreting a Retry-After header
+type RetryAfterInput struct {
+	Value string `json:"value"` // Retry-After header: delay in seconds or an HTTP-date
+	// ReceivedAt is when the response arrived, defaults to now
+	ReceivedAt Timestamp `json:"received_at,omitempty"`
+	// Date is the response's Date header. With an HTTP-date Retry-After, it
+	// measures the wait on the server's clock, so skew between the clocks
+	// doesn't shorten or stretch it
+	Date string `json:"date,omitempty"`
+}
+
+// RetryAfterResult represents when a request may be retried
+type RetryAfterResult struct {
+	Kind        string `json:"kind"` // delay_seconds or http_date
+	RetryAt     string `json:"retry_at"`
+	RetryAtDate string `json:"retry_at_http_date"`
+	ReceivedAt  string `json:"received_at"`
+	// WaitSeconds is how long to wait from the current time, rounded up and
+	// 0 once the retry time has passed
+	WaitSeconds int64  `json:"wait_seconds"`
+	Wait        string `json:"wait"`
+	Elapsed     bool   `json:"elapsed"`
+	// SkewCorrected is set when the response's Date header was used
+	SkewCorrected bool `json:"skew_corrected"`
+}
+
+// HTTPDate renders an instant, or normalizes an HTTP-date, as the
+// IMF-fixdate HTTP uses in Date, Expires and Retry-After headers
+func (s *timeService) HTTPDate(input HTTPDateInput) (HTTPDateResult, error) {
+	at := s.clock.Now()
+	var err error
+	switch {
+	case input.Value != "" && !input.At.IsZero():
+		return HTTPDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "set either at or value, not both")
+	case input.Value != "":
+		if at, err = parseHTTPDate("value", input.Value); err != nil {
+			return HTTPDateResult{}, err
+		}
+	case !input.At.IsZero():
+		if at, err = input.At.Resolve(); err != nil {
+			return HTTPDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+
+	truncated := at.Truncate(time.Second)
+	return HTTPDateResult{
+		Date:        truncated.UTC().Format(http.TimeFormat),
+		Timestamp:   truncated.UTC().Format(time.RFC3339),
+		UnixSeconds: truncated.Unix(),
+		Truncated:   !truncated.Equal(at),
+	}, nil
+}
+
+// RetryAfter interprets a Retry-After header as an absolute retry time and
+// the wait left from now
+func (s *timeService) RetryAfter(input RetryAfterInput) (RetryAfterResult, error) {
+	value := strings.TrimSpace(input.Value)
+	if value == "" {
+		return RetryAfterResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "value cannot be empty")
+	}
+
+	now := s.clock.Now()
+	receivedAt := now
+	if !input.ReceivedAt.IsZero() {
+		var err error
+		if receivedAt, err = input.ReceivedAt.Resolve(); err != nil {
+			return RetryAfterResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid received_at: %w", err)
+		}
+	}
+
+	result := RetryAfterResult{ReceivedAt: receivedAt.UTC().Format(time.RFC3339)}
+	var retryAt time.Time
+	if isDigits(value) {
+		// delay-seconds is 1*DIGIT, counted from when the response was received
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds > math.MaxInt64/int64(time.Second) {
+			return RetryAfterResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "delay %s is too large", value)
+		}
+		result.Kind = RetryAfterDelaySeconds
+		retryAt = receivedAt.Add(time.Duration(seconds) * time.Second)
+	} else {
+		date, err := parseHTTPDate("value", value)
+		if err != nil {
+			return RetryAfterResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid Retry-After %q (must be delay-seconds or an HTTP-date)", value)
+		}
+		result.Kind = RetryAfterHTTPDate
+		retryAt = date
+		if input.Date != "" {
+			serverNow, err := parseHTTPDate("date", input.Date)
+			if err != nil {
+				return RetryAfterResult{}, err
+			}
+			retryAt = receivedAt.Add(date.Sub(serverNow))
+			result.SkewCorrected = true
+		}
+	}
+
+	wait := retryAt.Sub(now)
+	if wait <= 0 {
+		wait = 0
+		result.Elapsed = true
+	}
+	result.RetryAt = retryAt.UTC().Format(time.RFC3339)
+	result.RetryAtDate = retryAt.UTC().Format(http.TimeFormat)
+	result.WaitSeconds = int64((wait + time.Second - 1) / time.Second)
+	result.Wait = (time.Duration(result.WaitSeconds) * time.Second).String()
+
+	s.logger.Debug("Interpreted Retry-After",
+		slog.String("kind", result.Kind),
+		slog.Int64("wait_seconds", result.WaitSeconds))
+
+	return result, nil
+}
+
+// parseHTTPDate reads an HTTP-date in any of the three forms recipients
+// must accept (RFC 9110 5.6.7)
+func parseHTTPDate(field, value string) (time.Time, error) {
+	t, err := http.ParseTime(strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s %q (must be an HTTP-date such as Mon, 25 Dec 2023 15:30:45 GMT)", field, value)
+	}
+	return t, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
@@ -0,0 +1,143 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_HTTPDate(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 600_000_000, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name      string
+		input     HTTPDateInput
+		date      string
+		truncated bool
+	}{
+		{"now drops sub-second precision", HTTPDateInput{}, "Mon, 25 Dec 2023 15:30:45 GMT", true},
+		{"offset instant is rendered in GMT", HTTPDateInput{At: RFC3339Timestamp("2024-02-29T09:00:00+09:00")}, "Thu, 29 Feb 2024 00:00:00 GMT", false},
+		{"IMF-fixdate", HTTPDateInput{Value: "Sun, 06 Nov 1994 08:49:37 GMT"}, "Sun, 06 Nov 1994 08:49:37 GMT", false},
+		{"RFC 850", HTTPDateInput{Value: "Sunday, 06-Nov-94 08:49:37 GMT"}, "Sun, 06 Nov 1994 08:49:37 GMT", false},
+		{"asctime", HTTPDateInput{Value: "Sun Nov  6 08:49:37 1994"}, "Sun, 06 Nov 1994 08:49:37 GMT", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.HTTPDate(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.date, result.Date)
+			assert.Equal(t, tt.truncated, result.Truncated)
+		})
+	}
+
+	_, err := service.HTTPDate(HTTPDateInput{Value: "2023-12-25T15:30:45Z"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+
+	_, err = service.HTTPDate(HTTPDateInput{Value: "Sun, 06 Nov 1994 08:49:37 GMT", At: RFC3339Timestamp("2023-12-25T15:30:45Z")})
+	assert.EqualError(t, err, "set either at or value, not both")
+}
+
+func TestTimeService_RetryAfter(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name    string
+		input   RetryAfterInput
+		kind    string
+		retryAt string
+		wait    int64
+		elapsed bool
+		skew    bool
+	}{
+		{
+			name:    "delay seconds",
+			input:   RetryAfterInput{Value: "120"},
+			kind:    "delay_seconds",
+			retryAt: "2023-12-25T15:32:45Z",
+			wait:    120,
+		},
+		{
+			name:    "delay counts from receipt",
+			input:   RetryAfterInput{Value: "120", ReceivedAt: RFC3339Timestamp("2023-12-25T15:30:00Z")},
+			kind:    "delay_seconds",
+			retryAt: "2023-12-25T15:32:00Z",
+			wait:    75,
+		},
+		{
+			name:    "http date",
+			input:   RetryAfterInput{Value: "Mon, 25 Dec 2023 15:35:00 GMT"},
+			kind:    "http_date",
+			retryAt: "2023-12-25T15:35:00Z",
+			wait:    255,
+		},
+		{
+			// The server's clock runs 10m ahead, so its date is only 4m15s away
+			name:    "http date measured on the server clock",
+			input:   RetryAfterInput{Value: "Mon, 25 Dec 2023 15:45:00 GMT", Date: "Mon, 25 Dec 2023 15:40:45 GMT"},
+			kind:    "http_date",
+			retryAt: "2023-12-25T15:35:00Z",
+			wait:    255,
+			skew:    true,
+		},
+		{
+			name:    "passed",
+			input:   RetryAfterInput{Value: "Mon, 25 Dec 2023 15:00:00 GMT"},
+			kind:    "http_date",
+			retryAt: "2023-12-25T15:00:00Z",
+			elapsed: true,
+		},
+		{
+			name:    "zero delay",
+			input:   RetryAfterInput{Value: " 0 "},
+			kind:    "delay_seconds",
+			retryAt: "2023-12-25T15:30:45Z",
+			elapsed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.RetryAfter(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, result.Kind)
+			assert.Equal(t, tt.retryAt, result.RetryAt)
+			assert.Equal(t, tt.wait, result.WaitSeconds)
+			assert.Equal(t, tt.elapsed, result.Elapsed)
+			assert.Equal(t, tt.skew, result.SkewCorrected)
+		})
+	}
+
+	result, err := service.RetryAfter(RetryAfterInput{Value: "90"})
+	require.NoError(t, err)
+	assert.Equal(t, "1m30s", result.Wait)
+	assert.Equal(t, "Mon, 25 Dec 2023 15:32:15 GMT", result.RetryAtDate)
+
+	errorCases := []struct {
+		name  string
+		input RetryAfterInput
+		want  string
+	}{
+		{"empty", RetryAfterInput{}, "value cannot be empty"},
+		{"fractional seconds", RetryAfterInput{Value: "1.5"}, `invalid Retry-After "1.5" (must be delay-seconds or an HTTP-date)`},
+		{"negative", RetryAfterInput{Value: "-30"}, `invalid Retry-After "-30" (must be delay-seconds or an HTTP-date)`},
+		{"too large", RetryAfterInput{Value: "99999999999999999999"}, "delay 99999999999999999999 is too large"},
+		{"invalid date header", RetryAfterInput{Value: "Mon, 25 Dec 2023 15:45:00 GMT", Date: "yesterday"}, `invalid date "yesterday" (must be an HTTP-date such as Mon, 25 Dec 2023 15:30:45 GMT)`},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.RetryAfter(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package time
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// icsProductID identifies the generator in PRODID
+const icsProductID = "-//hspedro//mcp-server-time//EN"
+
+// icsLineLimit is the content line length, in octets, lines fold at
+const icsLineLimit = 75
+
+// CreateICSInput represents input for generating an iCalendar event
+type CreateICSInput struct {
+	Summary  string    `json:"summary"`
+	Start    Timestamp `json:"start"`
+	End      Timestamp `json:"end,omitempty"`      // defaults to start plus duration
+	Duration string    `json:"duration,omitempty"` // Go duration used without end, defaults to 1h
+	// AllDay writes dates only; end is exclusive and defaults to the day after start
+	AllDay   bool   `json:"all_day,omitempty"`
+	Timezone string `json:"timezone,omitempty"` // TZID the event is written in, defaults to the server default
+	// RRule is an RFC 5545 recurrence rule without the RRULE: prefix, e.g. FREQ=WEEKLY;BYDAY=MO;COUNT=10
+	RRule       string `json:"rrule,omitempty"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+	UID         string `json:"uid,omitempty"` // defaults to a hash of the event, so the same event keeps its UID
+	// Organizer and Attendees are email addresses; attendees make the
+	// calendar an invitation (METHOD:REQUEST), which needs an organizer
+	Organizer string   `json:"organizer,omitempty"`
+	Attendees []string `json:"attendees,omitempty"`
+}
+
+// CreateICSResult represents a generated iCalendar document
+type CreateICSResult struct {
+	ICS         string `json:"ics"`
+	Base64      string `json:"base64"`
+	ContentType string `json:"content_type"`
+	Filename    string `json:"filename"`
+	UID         string `json:"uid"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	Timezone    string `json:"timezone"`
+}
+
+// CreateICS serializes an event as an iCalendar (RFC 5545) document. Events
+// in a zone other than UTC carry a VTIMEZONE built from the zone's rules in
+// the event's year
+func (s *timeService) CreateICS(input CreateICSInput) (CreateICSResult, error) {
+	summary := strings.TrimSpace(input.Summary)
+	if summary == "" {
+		return CreateICSResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "summary cannot be empty")
+	}
+	if input.Start.IsZero() {
+		return CreateICSResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "start is required")
+	}
+	start, err := input.Start.Resolve()
+	if err != nil {
+		return CreateICSResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid start: %w", err)
+	}
+
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return CreateICSResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	start = start.In(loc)
+
+	end, err := icsEnd(input, start, loc)
+	if err != nil {
+		return CreateICSResult{}, err
+	}
+	if input.RRule != "" {
+		if err := validateRRule(input.RRule); err != nil {
+			return CreateICSResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid rrule: %w", err)
+		}
+	}
+	organizer, attendees, err := parseParticipants(input.Organizer, input.Attendees)
+	if err != nil {
+		return CreateICSResult{}, err
+	}
+
+	uid := input.UID
+	if uid == "" {
+		sum := sha256.Sum256([]byte(strings.Join([]string{summary, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), input.RRule}, "\x00")))
+		uid = hex.EncodeToString(sum[:8]) + "@mcp-server-time"
+	}
+
+	method := "PUBLISH"
+	if len(attendees) > 0 {
+		method = "REQUEST"
+	}
+
+	var b icsBuilder
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:" + icsProductID)
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:" + method)
+	utc := loc == time.UTC || timezone == "UTC"
+	if !input.AllDay && !utc {
+		writeVTimezone(&b, timezone, loc, start)
+	}
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + escapeICSText(uid))
+	b.line("DTSTAMP:" + s.clock.Now().UTC().Format("20060102T150405Z"))
+	switch {
+	case input.AllDay:
+		b.line("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		b.line("DTEND;VALUE=DATE:" + end.Format("20060102"))
+	case utc:
+		b.line("DTSTART:" + start.UTC().Format("20060102T150405Z"))
+		b.line("DTEND:" + end.UTC().Format("20060102T150405Z"))
+	default:
+		b.line("DTSTART;TZID=" + timezone + ":" + start.Format("20060102T150405"))
+		b.line("DTEND;TZID=" + timezone + ":" + end.Format("20060102T150405"))
+	}
+	if input.RRule != "" {
+		b.line("RRULE:" + strings.ToUpper(input.RRule))
+	}
+	b.line("SUMMARY:" + escapeICSText(summary))
+	if input.Description != "" {
+		b.line("DESCRIPTION:" + escapeICSText(input.Description))
+	}
+	if input.Location != "" {
+		b.line("LOCATION:" + escapeICSText(input.Location))
+	}
+	if organizer != "" {
+		b.line("ORGANIZER:mailto:" + organizer)
+	}
+	for _, attendee := range attendees {
+		b.line("ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + attendee)
+	}
+	b.line("END:VEVENT")
+	b.line("END:VCALENDAR")
+
+	ics := b.String()
+	layout := time.RFC3339
+	if input.AllDay {
+		layout = time.DateOnly
+	}
+	return CreateICSResult{
+		ICS:         ics,
+		Base64:      base64.StdEncoding.EncodeToString([]byte(ics)),
+		ContentType: "text/calendar; charset=utf-8; method=" + method,
+		Filename:    icsFilename(summary),
+		UID:         uid,
+		Start:       start.Format(layout),
+		End:         end.Format(layout),
+		Timezone:    timezone,
+	}, nil
+}
+
+// icsEnd resolves the event end from end, duration or the defaults
+func icsEnd(input CreateICSInput, start time.Time, loc *time.Location) (time.Time, error) {
+	if !input.End.IsZero() && input.Duration != "" {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "set either end or duration, not both")
+	}
+
+	var end time.Time
+	switch {
+	case !input.End.IsZero():
+		resolved, err := input.End.Resolve()
+		if err != nil {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid end: %w", err)
+		}
+		end = resolved.In(loc)
+	case input.AllDay && input.Duration == "":
+		end = start.AddDate(0, 0, 1)
+	default:
+		duration, err := time.ParseDuration(defaultString(input.Duration, "1h"))
+		if err != nil {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid duration: %w", err)
+		}
+		end = start.Add(duration)
+	}
+
+	if input.AllDay {
+		// Dates only: compare calendar days, so an end on the start day is empty
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+	}
+	if !end.After(start) {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "end must be after start")
+	}
+	return end, nil
+}
+
+// parseParticipants validates the organizer and attendee addresses
+func parseParticipants(organizer string, attendees []string) (string, []string, error) {
+	address := func(field, value string) (string, error) {
+		parsed, err := mail.ParseAddress(value)
+		if err != nil {
+			return "", timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s %q: %w", field, value, err)
+		}
+		return parsed.Address, nil
+	}
+
+	var err error
+	if organizer != "" {
+		if organizer, err = address("organizer", organizer); err != nil {
+			return "", nil, err
+		}
+	}
+	parsed := make([]string, 0, len(attendees))
+	for _, attendee := range attendees {
+		a, err := address("attendee", attendee)
+		if err != nil {
+			return "", nil, err
+		}
+		parsed = append(parsed, a)
+	}
+	if len(parsed) > 0 && organizer == "" {
+		return "", nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "organizer is required with attendees")
+	}
+	return organizer, parsed, nil
+}
+
+// rruleParts lists the RRULE parts of RFC 5545 3.3.10 with a value check
+var rruleParts = map[string]*regexp.Regexp{
+	"FREQ":       regexp.MustCompile(`^(SECONDLY|MINUTELY|HOURLY|DAILY|WEEKLY|MONTHLY|YEARLY)$`),
+	"UNTIL":      regexp.MustCompile(`^\d{8}(T\d{6}Z?)?$`),
+	"COUNT":      regexp.MustCompile(`^[1-9]\d*$`),
+	"INTERVAL":   regexp.MustCompile(`^[1-9]\d*$`),
+	"BYSECOND":   regexp.MustCompile(`^\d{1,2}(,\d{1,2})*$`),
+	"BYMINUTE":   regexp.MustCompile(`^\d{1,2}(,\d{1,2})*$`),
+	"BYHOUR":     regexp.MustCompile(`^\d{1,2}(,\d{1,2})*$`),
+	"BYDAY":      regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)(,([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA))*$`),
+	"BYMONTHDAY": regexp.MustCompile(`^[+-]?\d{1,2}(,[+-]?\d{1,2})*$`),
+	"BYYEARDAY":  regexp.MustCompile(`^[+-]?\d{1,3}(,[+-]?\d{1,3})*$`),
+	"BYWEEKNO":   regexp.MustCompile(`^[+-]?\d{1,2}(,[+-]?\d{1,2})*$`),
+	"BYMONTH":    regexp.MustCompile(`^\d{1,2}(,\d{1,2})*$`),
+	"BYSETPOS":   regexp.MustCompile(`^[+-]?\d{1,3}(,[+-]?\d{1,3})*$`),
+	"WKST":       regexp.MustCompile(`^(SU|MO|TU|WE|TH|FR|SA)$`),
+}
+
+// validateRRule checks a recurrence rule's syntax: known parts, each once,
+// FREQ present and COUNT and UNTIL not combined
+func validateRRule(rule string) error {
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		pattern, known := rruleParts[name]
+		switch {
+		case !ok || value == "":
+			return fmt.Errorf("malformed part %q", part)
+		case !known:
+			return fmt.Errorf("unknown part %s", name)
+		case seen[name]:
+			return fmt.Errorf("%s is set more than once", name)
+		case !pattern.MatchString(value):
+			return fmt.Errorf("invalid %s: %s", name, value)
+		}
+		seen[name] = true
+	}
+	if !seen["FREQ"] {
+		return fmt.Errorf("FREQ is required")
+	}
+	if seen["COUNT"] && seen["UNTIL"] {
+		return fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	return nil
+}
+
+// writeVTimezone writes the zone's observances for the year of ref: a
+// single STANDARD block for a zone without transitions that year, otherwise
+// one block per transition repeating yearly on the same weekday rule
+func writeVTimezone(b *icsBuilder, tzid string, loc *time.Location, ref time.Time) {
+	yearStart := time.Date(ref.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	index := buildZoneIndex(loc, yearStart, yearStart.AddDate(1, 0, 0))
+
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + tzid)
+	if len(index.transitions) == 0 {
+		name, offset := yearStart.Zone()
+		b.line("BEGIN:STANDARD")
+		b.line("DTSTART:" + yearStart.Format("20060102T150405"))
+		b.line("TZOFFSETFROM:" + icsOffset(offset))
+		b.line("TZOFFSETTO:" + icsOffset(offset))
+		b.line("TZNAME:" + escapeICSText(name))
+		b.line("END:STANDARD")
+	}
+	for _, at := range index.transitions {
+		before := zoneStateAt(at.Add(-time.Second), loc)
+		after := zoneStateAt(at, loc)
+		name, _ := at.In(loc).Zone()
+		// Observances start on the wall clock in effect before the change
+		wall := at.In(time.FixedZone("", before.offset))
+
+		component := "STANDARD"
+		if after.dst {
+			component = "DAYLIGHT"
+		}
+		b.line("BEGIN:" + component)
+		b.line("DTSTART:" + wall.Format("20060102T150405"))
+		b.line("TZOFFSETFROM:" + icsOffset(before.offset))
+		b.line("TZOFFSETTO:" + icsOffset(after.offset))
+		b.line("TZNAME:" + escapeICSText(name))
+		b.line("RRULE:FREQ=YEARLY;BYMONTH=" + strconv.Itoa(int(wall.Month())) + ";BYDAY=" + weekdayRule(wall))
+		b.line("END:" + component)
+	}
+	b.line("END:VTIMEZONE")
+}
+
+// weekdayRule writes the BYDAY value matching a date's weekday within its
+// month, such as -1SU for the last Sunday or 2SU for the second
+func weekdayRule(t time.Time) string {
+	day := strings.ToUpper(t.Weekday().String()[:2])
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if t.Day()+7 > daysInMonth {
+		return "-1" + day
+	}
+	return strconv.Itoa((t.Day()-1)/7+1) + day
+}
+
+// icsOffset formats a UTC offset as ±hhmm, or ±hhmmss with seconds
+func icsOffset(offsetSeconds int) string {
+	sign := "+"
+	if offsetSeconds < 0 {
+		sign = "-"
+		offsetSeconds = -offsetSeconds
+	}
+	formatted := fmt.Sprintf("%s%02d%02d", sign, offsetSeconds/3600, offsetSeconds%3600/60)
+	if seconds := offsetSeconds % 60; seconds != 0 {
+		formatted += fmt.Sprintf("%02d", seconds)
+	}
+	return formatted
+}
+
+// escapeICSText escapes a TEXT value (RFC 5545 3.3.11)
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+// icsFilename derives an attachment name from the summary
+func icsFilename(summary string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(summary) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= 48 {
+			break
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		name = "event"
+	}
+	return name + ".ics"
+}
+
+// icsBuilder writes CRLF-terminated content lines, folding them at 75
+// octets without splitting UTF-8 sequences
+type icsBuilder struct {
+	strings.Builder
+}
+
+func (b *icsBuilder) line(content string) {
+	limit := icsLineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines lose an octet to the leading space
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
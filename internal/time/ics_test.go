@@ -0,0 +1,148 @@
+package time
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_CreateICS(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	t.Run("zoned event with recurrence and attendees", func(t *testing.T) {
+		result, err := service.CreateICS(CreateICSInput{
+			Summary:     "Planning; Q4, budget",
+			Start:       RFC3339Timestamp("2026-10-19T07:30:00Z"),
+			Duration:    "45m",
+			Timezone:    "Europe/Paris",
+			RRule:       "FREQ=WEEKLY;BYDAY=MO;COUNT=6",
+			Description: "Agenda:\n1. Review",
+			Organizer:   "Ana <ana@example.com>",
+			Attendees:   []string{"bo@example.com"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//hspedro//mcp-server-time//EN",
+			"CALSCALE:GREGORIAN",
+			"METHOD:REQUEST",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Paris",
+			"BEGIN:DAYLIGHT",
+			"DTSTART:20260329T020000",
+			"TZOFFSETFROM:+0100",
+			"TZOFFSETTO:+0200",
+			"TZNAME:CEST",
+			"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+			"END:DAYLIGHT",
+			"BEGIN:STANDARD",
+			"DTSTART:20261025T030000",
+			"TZOFFSETFROM:+0200",
+			"TZOFFSETTO:+0100",
+			"TZNAME:CET",
+			"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+			"END:STANDARD",
+			"END:VTIMEZONE",
+			"BEGIN:VEVENT",
+			"UID:" + result.UID,
+			"DTSTAMP:20261016T080000Z",
+			"DTSTART;TZID=Europe/Paris:20261019T093000",
+			"DTEND;TZID=Europe/Paris:20261019T101500",
+			"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=6",
+			`SUMMARY:Planning\; Q4\, budget`,
+			`DESCRIPTION:Agenda:\n1. Review`,
+			"ORGANIZER:mailto:ana@example.com",
+			"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:bo@exa",
+			" mple.com",
+			"END:VEVENT",
+			"END:VCALENDAR",
+			"",
+		}, "\r\n"), result.ICS)
+
+		assert.Equal(t, "text/calendar; charset=utf-8; method=REQUEST", result.ContentType)
+		assert.Equal(t, "planning-q4-budget.ics", result.Filename)
+		assert.Equal(t, "2026-10-19T09:30:00+02:00", result.Start)
+		assert.Equal(t, "2026-10-19T10:15:00+02:00", result.End)
+		decoded, err := base64.StdEncoding.DecodeString(result.Base64)
+		require.NoError(t, err)
+		assert.Equal(t, result.ICS, string(decoded))
+
+		// The same event keeps its UID
+		again, err := service.CreateICS(CreateICSInput{
+			Summary: "Planning; Q4, budget", Start: RFC3339Timestamp("2026-10-19T09:30:00+02:00"),
+			End: RFC3339Timestamp("2026-10-19T10:15:00+02:00"), RRule: "FREQ=WEEKLY;BYDAY=MO;COUNT=6",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, result.UID, again.UID)
+	})
+
+	t.Run("utc event", func(t *testing.T) {
+		result, err := service.CreateICS(CreateICSInput{Summary: "Deploy", Start: RFC3339Timestamp("2026-10-20T14:00:00Z"), UID: "deploy-1"})
+		require.NoError(t, err)
+		assert.NotContains(t, result.ICS, "VTIMEZONE")
+		assert.Contains(t, result.ICS, "METHOD:PUBLISH\r\n")
+		assert.Contains(t, result.ICS, "DTSTART:20261020T140000Z\r\nDTEND:20261020T150000Z\r\n")
+		assert.Equal(t, "deploy-1", result.UID)
+	})
+
+	t.Run("all-day event", func(t *testing.T) {
+		result, err := service.CreateICS(CreateICSInput{
+			Summary: "Offsite", Start: RFC3339Timestamp("2026-10-21T22:30:00Z"), AllDay: true, Timezone: "Asia/Tokyo",
+		})
+		require.NoError(t, err)
+		assert.NotContains(t, result.ICS, "VTIMEZONE")
+		assert.Contains(t, result.ICS, "DTSTART;VALUE=DATE:20261022\r\nDTEND;VALUE=DATE:20261023\r\n")
+		assert.Equal(t, "2026-10-22", result.Start)
+		assert.Equal(t, "2026-10-23", result.End)
+	})
+
+	t.Run("zone without DST", func(t *testing.T) {
+		result, err := service.CreateICS(CreateICSInput{Summary: "Sync", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), Timezone: "Asia/Tokyo"})
+		require.NoError(t, err)
+		assert.Contains(t, result.ICS, "BEGIN:STANDARD\r\nDTSTART:20260101T000000\r\nTZOFFSETFROM:+0900\r\nTZOFFSETTO:+0900\r\nTZNAME:JST\r\nEND:STANDARD\r\n")
+	})
+
+	t.Run("long lines fold without splitting characters", func(t *testing.T) {
+		result, err := service.CreateICS(CreateICSInput{Summary: strings.Repeat("é", 60), Start: RFC3339Timestamp("2026-10-20T03:00:00Z")})
+		require.NoError(t, err)
+		for _, line := range strings.Split(strings.TrimSuffix(result.ICS, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(line), 75, line)
+			assert.True(t, strings.ToValidUTF8(line, "?") == line, line)
+		}
+		assert.Equal(t, "event.ics", result.Filename)
+	})
+
+	errorCases := []struct {
+		name  string
+		input CreateICSInput
+		want  string
+	}{
+		{"missing summary", CreateICSInput{Start: RFC3339Timestamp("2026-10-20T03:00:00Z")}, "summary cannot be empty"},
+		{"missing start", CreateICSInput{Summary: "x"}, "start is required"},
+		{"end before start", CreateICSInput{Summary: "x", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), End: RFC3339Timestamp("2026-10-20T02:00:00Z")}, "end must be after start"},
+		{"end and duration", CreateICSInput{Summary: "x", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), End: RFC3339Timestamp("2026-10-20T04:00:00Z"), Duration: "1h"}, "set either end or duration, not both"},
+		{"unknown rrule part", CreateICSInput{Summary: "x", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), RRule: "FREQ=DAILY;EVERY=2"}, "invalid rrule: unknown part EVERY"},
+		{"rrule without freq", CreateICSInput{Summary: "x", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), RRule: "COUNT=2"}, "invalid rrule: FREQ is required"},
+		{"count and until", CreateICSInput{Summary: "x", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), RRule: "FREQ=DAILY;COUNT=2;UNTIL=20261101T000000Z"}, "invalid rrule: COUNT and UNTIL cannot be combined"},
+		{"attendees without organizer", CreateICSInput{Summary: "x", Start: RFC3339Timestamp("2026-10-20T03:00:00Z"), Attendees: []string{"bo@example.com"}}, "organizer is required with attendees"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateICS(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package time
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// ID kinds decode_id_timestamp recognizes
+const (
+	IDKindULID     = "ulid"
+	IDKindUUIDv7   = "uuidv7"
+	IDKindObjectID = "objectid"
+	IDKindKSUID    = "ksuid"
+)
+
+const (
+	// crockfordAlphabet is the base32 alphabet of ULIDs
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// base62Alphabet is the alphabet of KSUIDs
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is the Unix second KSUID timestamps count from (2014-05-13T16:53:20Z)
+	ksuidEpoch = 1400000000
+)
+
+// DecodeIDTimestampInput represents input for extracting the creation time
+// of a time-ordered ID
+type DecodeIDTimestampInput struct {
+	ID string `json:"id"`
+	// Kind is ulid, uuidv7, objectid or ksuid; detected from the ID's
+	// length and alphabet when empty
+	Kind     string `json:"kind,omitempty"`
+	Timezone string `json:"timezone,omitempty"` // defaults to the server default
+}
+
+// DecodeIDTimestampResult represents the creation time embedded in an ID
+type DecodeIDTimestampResult struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Timestamp string `json:"timestamp"`
+	UnixMs    int64  `json:"unix_ms"`
+	// Precision is the resolution of the embedded timestamp: millisecond
+	// for ULIDs and UUIDv7, second for ObjectIds and KSUIDs
+	Precision string   `json:"precision"`
+	Timezone  string   `json:"timezone"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// DecodeIDTimestamp extracts the creation time embedded in a ULID, UUIDv7,
+// MongoDB ObjectId or KSUID
+func (s *timeService) DecodeIDTimestamp(input DecodeIDTimestampInput) (DecodeIDTimestampResult, error) {
+	id := strings.TrimSpace(input.ID)
+	if id == "" {
+		return DecodeIDTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "id cannot be empty")
+	}
+	kind := strings.ToLower(input.Kind)
+	if kind == "" {
+		kind = detectIDKind(id)
+	}
+
+	var created time.Time
+	var err error
+	precision := "millisecond"
+	switch kind {
+	case IDKindULID:
+		created, err = ulidTime(id)
+	case IDKindUUIDv7:
+		created, err = uuidv7Time(id)
+	case IDKindObjectID:
+		created, err = objectIDTime(id)
+		precision = "second"
+	case IDKindKSUID:
+		created, err = ksuidTime(id)
+		precision = "second"
+	case "":
+		return DecodeIDTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "cannot tell the kind of id %q: expected a 26-character ULID, 36-character UUID, 24-character ObjectId or 27-character KSUID", input.ID)
+	default:
+		return DecodeIDTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid kind %q (must be one of: ulid, uuidv7, objectid, ksuid)", input.Kind)
+	}
+	if err != nil {
+		return DecodeIDTimestampResult{}, err
+	}
+
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return DecodeIDTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	result := DecodeIDTimestampResult{
+		ID:        input.ID,
+		Kind:      kind,
+		Timestamp: created.In(loc).Format(time.RFC3339Nano),
+		UnixMs:    created.UnixMilli(),
+		Precision: precision,
+		Timezone:  timezone,
+	}
+	if created.After(s.clock.Now()) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the timestamp is in the future; the ID may not be a %s", kind))
+	}
+
+	s.logger.Debug("Decoded ID timestamp",
+		slog.String("id", input.ID),
+		slog.String("kind", kind),
+		slog.String("timestamp", result.Timestamp))
+
+	return result, nil
+}
+
+// detectIDKind guesses the kind of an ID from its length and alphabet,
+// returning "" when it matches none
+func detectIDKind(id string) string {
+	switch {
+	case len(id) == 26 && inAlphabet(strings.ToUpper(id), crockfordAlphabet):
+		return IDKindULID
+	case len(id) == 36 || len(id) == 32 && isHex(id):
+		return IDKindUUIDv7
+	case len(id) == 24 && isHex(id):
+		return IDKindObjectID
+	case len(id) == 27 && inAlphabet(id, base62Alphabet):
+		return IDKindKSUID
+	}
+	return ""
+}
+
+// ulidTime reads the 48-bit millisecond timestamp in the first 10
+// characters of a ULID
+func ulidTime(id string) (time.Time, error) {
+	upper := strings.ToUpper(id)
+	if len(upper) != 26 || !inAlphabet(upper, crockfordAlphabet) {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid ULID %q: must be 26 Crockford base32 characters", id)
+	}
+	// 26 characters hold 130 bits; a ULID is 128, so the first is at most 7
+	if upper[0] > '7' {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "invalid ULID %q: larger than 128 bits", id)
+	}
+	var ms int64
+	for _, c := range upper[:10] {
+		ms = ms<<5 | int64(strings.IndexRune(crockfordAlphabet, c))
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+// uuidv7Time reads the 48-bit millisecond timestamp at the start of a
+// version 7 UUID
+func uuidv7Time(id string) (time.Time, error) {
+	raw := strings.ToLower(id)
+	if len(raw) == 36 {
+		if raw[8] != '-' || raw[13] != '-' || raw[18] != '-' || raw[23] != '-' {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid UUID %q: must look like 017f22e2-79b0-7cc3-98c4-dc0c0c07398f", id)
+		}
+		raw = strings.ReplaceAll(raw, "-", "")
+	}
+	bytes, err := hex.DecodeString(raw)
+	if err != nil || len(bytes) != 16 {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid UUID %q: must be 32 hexadecimal digits", id)
+	}
+	if version := bytes[6] >> 4; version != 7 {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "UUID %q is version %d; only version 7 UUIDs embed a Unix timestamp", id, version)
+	}
+	var ms int64
+	for _, b := range bytes[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+// objectIDTime reads the Unix seconds in the first 4 bytes of a MongoDB ObjectId
+func objectIDTime(id string) (time.Time, error) {
+	bytes, err := hex.DecodeString(id)
+	if err != nil || len(bytes) != 12 {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid ObjectId %q: must be 24 hexadecimal digits", id)
+	}
+	return time.Unix(int64(binary.BigEndian.Uint32(bytes[:4])), 0).UTC(), nil
+}
+
+// ksuidTime reads the seconds since the KSUID epoch in the first 4 bytes
+// of a base62 KSUID
+func ksuidTime(id string) (time.Time, error) {
+	if len(id) != 27 || !inAlphabet(id, base62Alphabet) {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid KSUID %q: must be 27 base62 characters", id)
+	}
+	n := new(big.Int)
+	base := big.NewInt(62)
+	for _, c := range id {
+		n.Mul(n, base).Add(n, big.NewInt(int64(strings.IndexRune(base62Alphabet, c))))
+	}
+	if n.BitLen() > 160 {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "invalid KSUID %q: larger than 160 bits", id)
+	}
+	bytes := n.FillBytes(make([]byte, 20))
+	return time.Unix(int64(binary.BigEndian.Uint32(bytes[:4]))+ksuidEpoch, 0).UTC(), nil
+}
+
+// inAlphabet reports whether every character of s is in alphabet
+func inAlphabet(s, alphabet string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune(alphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHex reports whether s is made of hexadecimal digits
+func isHex(s string) bool {
+	return inAlphabet(strings.ToLower(s), "0123456789abcdef")
+}
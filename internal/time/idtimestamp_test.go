@@ -0,0 +1,121 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_DecodeIDTimestamp(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name      string
+		input     DecodeIDTimestampInput
+		kind      string
+		expected  string
+		precision string
+	}{
+		{
+			name:      "ulid",
+			input:     DecodeIDTimestampInput{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+			kind:      IDKindULID,
+			expected:  "2016-07-30T23:54:10.259Z",
+			precision: "millisecond",
+		},
+		{
+			name:      "lowercase ulid",
+			input:     DecodeIDTimestampInput{ID: "01arz3ndektsv4rrffq69g5fav"},
+			kind:      IDKindULID,
+			expected:  "2016-07-30T23:54:10.259Z",
+			precision: "millisecond",
+		},
+		{
+			name:      "uuidv7",
+			input:     DecodeIDTimestampInput{ID: "017F22E2-79B0-7CC3-98C4-DC0C0C07398F"},
+			kind:      IDKindUUIDv7,
+			expected:  "2022-02-22T19:22:22Z",
+			precision: "millisecond",
+		},
+		{
+			name:      "uuidv7 without hyphens",
+			input:     DecodeIDTimestampInput{ID: "017f22e279b07cc398c4dc0c0c07398f", Timezone: "America/New_York"},
+			kind:      IDKindUUIDv7,
+			expected:  "2022-02-22T14:22:22-05:00",
+			precision: "millisecond",
+		},
+		{
+			name:      "objectid",
+			input:     DecodeIDTimestampInput{ID: "507f1f77bcf86cd799439011"},
+			kind:      IDKindObjectID,
+			expected:  "2012-10-17T21:13:27Z",
+			precision: "second",
+		},
+		{
+			name:      "ksuid",
+			input:     DecodeIDTimestampInput{ID: "0ujtsYcgvSTl8PAuAdqWYSMnLOv"},
+			kind:      IDKindKSUID,
+			expected:  "2017-10-10T04:00:47Z",
+			precision: "second",
+		},
+		{
+			name:      "explicit kind",
+			input:     DecodeIDTimestampInput{ID: "507f1f77bcf86cd799439011", Kind: "ObjectId"},
+			kind:      IDKindObjectID,
+			expected:  "2012-10-17T21:13:27Z",
+			precision: "second",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.DecodeIDTimestamp(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, result.Kind)
+			assert.Equal(t, tt.expected, result.Timestamp)
+			assert.Equal(t, tt.precision, result.Precision)
+			assert.Empty(t, result.Warnings)
+		})
+	}
+}
+
+func TestTimeService_DecodeIDTimestampErrors(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	// The largest KSUID is in 2150
+	result, err := service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "aWgEPTl1tmebfsQzFP4bxwgy80V"})
+	require.NoError(t, err)
+	assert.Equal(t, "2150-06-19T23:21:35Z", result.Timestamp)
+	assert.Contains(t, result.Warnings[0], "the timestamp is in the future")
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "aWgEPTl1tmebfsQzFP4bxwgy80W"})
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "81ARZ3NDEKTSV4RRFFQ69G5FAV"})
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "550e8400-e29b-41d4-a716-446655440000"})
+	assert.ErrorContains(t, err, "is version 4")
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "017f22e2+79b0+7cc3+98c4+dc0c0c07398f"})
+	assert.ErrorContains(t, err, "invalid UUID")
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "12345"})
+	assert.ErrorContains(t, err, "cannot tell the kind")
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Kind: "ksuid"})
+	assert.ErrorContains(t, err, "invalid KSUID")
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: "1", Kind: "cuid"})
+	assert.ErrorContains(t, err, "must be one of: ulid, uuidv7, objectid, ksuid")
+
+	_, err = service.DecodeIDTimestamp(DecodeIDTimestampInput{ID: " "})
+	assert.ErrorContains(t, err, "id cannot be empty")
+}
@@ -0,0 +1,63 @@
+package time
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Interval semantics of the ranges tools read and write, set per request
+// with interval_semantics and echoed in results
+const (
+	// IntervalHalfOpen ranges include their start and exclude their end,
+	// [start, end): a day ends at the next midnight
+	IntervalHalfOpen = "half_open"
+	// IntervalClosed ranges include both ends, [start, end]: a day ends at
+	// its last second, and a date range includes its end date
+	IntervalClosed = "closed"
+)
+
+// IntervalUnit steps t by n units of the resolution a tool counts in. A
+// closed end is the last unit included, one unit before the half-open end
+type IntervalUnit func(t time.Time, n int) time.Time
+
+// Units closed ends are counted in
+var (
+	IntervalSecond IntervalUnit = func(t time.Time, n int) time.Time { return t.Add(time.Duration(n) * time.Second) }
+	IntervalMinute IntervalUnit = func(t time.Time, n int) time.Time { return t.Add(time.Duration(n) * time.Minute) }
+	// IntervalDay steps calendar days in t's location, so it follows DST
+	IntervalDay IntervalUnit = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }
+)
+
+// ResolveIntervalSemantics validates a request's interval_semantics,
+// falling back to the tool's default when it is empty
+func ResolveIntervalSemantics(value, fallback string) (string, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return fallback, nil
+	case IntervalHalfOpen:
+		return IntervalHalfOpen, nil
+	case IntervalClosed:
+		return IntervalClosed, nil
+	}
+	return "", timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+		"invalid interval_semantics %q (must be one of: %s, %s)", value, IntervalHalfOpen, IntervalClosed)
+}
+
+// ExclusiveEnd converts an end given with semantics to the half-open end
+// tools compute with
+func ExclusiveEnd(end time.Time, semantics string, unit IntervalUnit) time.Time {
+	if semantics == IntervalClosed {
+		return unit(end, 1)
+	}
+	return end
+}
+
+// ReportedEnd converts a half-open end to the end written with semantics
+func ReportedEnd(end time.Time, semantics string, unit IntervalUnit) time.Time {
+	if semantics == IntervalClosed {
+		return unit(end, -1)
+	}
+	return end
+}
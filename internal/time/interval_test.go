@@ -0,0 +1,39 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestResolveIntervalSemantics(t *testing.T) {
+	semantics, err := ResolveIntervalSemantics("", IntervalClosed)
+	require.NoError(t, err)
+	assert.Equal(t, IntervalClosed, semantics)
+
+	semantics, err = ResolveIntervalSemantics("Half_Open", IntervalClosed)
+	require.NoError(t, err)
+	assert.Equal(t, IntervalHalfOpen, semantics)
+
+	_, err = ResolveIntervalSemantics("inclusive", IntervalHalfOpen)
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	assert.EqualError(t, err, `invalid interval_semantics "inclusive" (must be one of: half_open, closed)`)
+}
+
+func TestIntervalEnds(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// March 10 2024 lasts 23 hours in New York; a closed day still steps to the next midnight
+	end := time.Date(2024, 3, 10, 0, 0, 0, 0, ny)
+
+	assert.Equal(t, end, ExclusiveEnd(end, IntervalHalfOpen, IntervalDay))
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, ny), ExclusiveEnd(end, IntervalClosed, IntervalDay))
+	assert.Equal(t, end, ReportedEnd(end, IntervalHalfOpen, IntervalSecond))
+	assert.Equal(t, time.Date(2024, 3, 9, 23, 59, 59, 0, ny), ReportedEnd(end, IntervalClosed, IntervalSecond))
+	assert.Equal(t, time.Date(2024, 3, 9, 23, 59, 0, 0, ny), ReportedEnd(end, IntervalClosed, IntervalMinute))
+}
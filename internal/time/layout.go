@@ -0,0 +1,244 @@
+package time
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// maxLayoutLength bounds custom layouts; real ones are far shorter
+const maxLayoutLength = 100
+
+// layoutTokens lists the Go layout directives, for errors
+const layoutTokens = "2006 or 06 (year), 01, 1, Jan or January (month), 02, _2 or 2 (day), 002 (day of year), " +
+	"Mon or Monday (weekday), 15, 03 or 3 (hour), 04 or 4 (minute), 05 or 5 (second), .000 or .999 (fraction), " +
+	"PM (AM/PM), MST (zone abbreviation), -07:00, -0700 or Z07:00 (offset)"
+
+// strftimeTokens lists the supported strftime directives, for errors
+const strftimeTokens = "%Y %y %m %d %e %j %H %I %M %S %f %p %b %h %B %a %A %z %:z %Z %F %T %D %R %%"
+
+// strftimeLayouts maps strftime directives to Go layout directives
+var strftimeLayouts = map[string]string{
+	"Y": "2006", "y": "06", "m": "01", "d": "02", "e": "_2", "j": "002",
+	"H": "15", "I": "03", "M": "04", "S": "05", "p": "PM",
+	"b": "Jan", "h": "Jan", "B": "January", "a": "Mon", "A": "Monday",
+	"z": "-0700", ":z": "-07:00", "Z": "MST",
+	"F": "2006-01-02", "T": "15:04:05", "D": "01/02/06", "R": "15:04",
+}
+
+// foreignPattern spots the yyyy-MM-dd patterns of Java, .NET and moment.js
+var foreignPattern = regexp.MustCompile(`yyyy|YYYY|yy|MM|dd|DD|HH|hh|mm|ss`)
+
+// Components a layout directive renders
+const (
+	layoutYear     = "year"
+	layoutMonth    = "month"
+	layoutDay      = "day"
+	layoutHour24   = "hour"
+	layoutHour12   = "12-hour hour"
+	layoutMinute   = "minute"
+	layoutSecond   = "second"
+	layoutFraction = "fraction"
+	layoutAMPM     = "AM/PM"
+	layoutWeekday  = "weekday"
+	layoutZone     = "zone"
+)
+
+// layoutDirectives are the Go layout directives, longest first where one
+// is a prefix of another, as package time matches them
+var layoutDirectives = []struct {
+	token     string
+	component string
+}{
+	{"January", layoutMonth}, {"Jan", layoutMonth},
+	{"Monday", layoutWeekday}, {"Mon", layoutWeekday}, {"MST", layoutZone},
+	{"2006", layoutYear}, {"002", layoutDay}, {"__2", layoutDay}, {"_2", layoutDay},
+	{"01", layoutMonth}, {"02", layoutDay}, {"03", layoutHour12}, {"04", layoutMinute}, {"05", layoutSecond}, {"06", layoutYear},
+	{"15", layoutHour24}, {"1", layoutMonth}, {"2", layoutDay}, {"3", layoutHour12}, {"4", layoutMinute}, {"5", layoutSecond},
+	{"PM", layoutAMPM}, {"pm", layoutAMPM},
+	{"-070000", layoutZone}, {"-07:00:00", layoutZone}, {"-0700", layoutZone}, {"-07:00", layoutZone}, {"-07", layoutZone},
+	{"Z070000", layoutZone}, {"Z07:00:00", layoutZone}, {"Z0700", layoutZone}, {"Z07:00", layoutZone}, {"Z07", layoutZone},
+}
+
+// layoutChunk is a directive or a run of literal text in a layout
+type layoutChunk struct {
+	text      string
+	component string // empty for literal text
+}
+
+// splitLayout splits a Go layout into directives and literal text, the way
+// time.Format reads it
+func splitLayout(layout string) []layoutChunk {
+	var chunks []layoutChunk
+	literal := func(text string) {
+		if n := len(chunks); n > 0 && chunks[n-1].component == "" {
+			chunks[n-1].text += text
+			return
+		}
+		chunks = append(chunks, layoutChunk{text: text})
+	}
+
+	for i := 0; i < len(layout); {
+		// A fraction is a . or , and a run of 0s or 9s not followed by a digit
+		if c := layout[i]; (c == '.' || c == ',') && i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+			j := i + 1
+			for j < len(layout) && layout[j] == layout[i+1] {
+				j++
+			}
+			if j == len(layout) || layout[j] < '0' || layout[j] > '9' {
+				chunks = append(chunks, layoutChunk{text: layout[i:j], component: layoutFraction})
+				i = j
+				continue
+			}
+		}
+
+		matched := false
+		for _, d := range layoutDirectives {
+			if strings.HasPrefix(layout[i:], d.token) {
+				// 2006 takes the 2 from _2 in _2006, as in package time
+				if d.token == "_2" && strings.HasPrefix(layout[i:], "_2006") {
+					continue
+				}
+				chunks = append(chunks, layoutChunk{text: d.token, component: d.component})
+				i += len(d.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			literal(layout[i : i+1])
+			i++
+		}
+	}
+	return chunks
+}
+
+// ValidateLayout checks a custom Go layout before it is used, rejecting
+// layouts without directives, with stray digits or with directives that
+// contradict each other
+func ValidateLayout(layout string) error {
+	if layout == "" {
+		return timeerrors.Errorf(timeerrors.ErrInvalidFormat, "layout cannot be empty")
+	}
+	if len(layout) > maxLayoutLength {
+		return timeerrors.Errorf(timeerrors.ErrInvalidFormat, "layout is %d characters long, at most %d allowed", len(layout), maxLayoutLength)
+	}
+
+	components := make(map[string]bool)
+	previous := ""
+	for _, chunk := range splitLayout(layout) {
+		// A date written out, such as 2023-12-25, reads as stray digits or
+		// as the same directive twice in a row
+		problem := ""
+		if i := strings.IndexAny(chunk.text, "0123456789"); chunk.component == "" && i >= 0 {
+			problem = fmt.Sprintf("the digit %c is not part of any directive", chunk.text[i])
+		} else if chunk.component != "" && chunk.component == previous {
+			problem = fmt.Sprintf("%s directly follows another %s directive", chunk.text, chunk.component)
+		}
+		if problem != "" {
+			return timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+				"invalid layout %q: %s. Go layouts spell out the reference time Mon Jan 2 15:04:05 MST 2006, so write 2006-01-02, not a date such as 2023-12-25. Directives: %s",
+				layout, problem, layoutTokens)
+		}
+		if chunk.component != "" {
+			components[chunk.component] = true
+		}
+		previous = chunk.component
+	}
+
+	if len(components) == 0 {
+		hint := ""
+		if foreignPattern.MatchString(layout) {
+			hint = " yyyy-MM-dd style patterns are not supported; write 2006-01-02 or the strftime %Y-%m-%d."
+		}
+		return timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+			"invalid layout %q: it has no directives, so every time would render as the same text.%s Directives: %s",
+			layout, hint, layoutTokens)
+	}
+	if components[layoutAMPM] && !components[layoutHour12] {
+		return timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+			"invalid layout %q: PM needs a 12-hour hour (03 or 3); with 15 it renders times such as 15:04 PM", layout)
+	}
+	return nil
+}
+
+// strftimeLayout translates a strftime pattern into a Go layout. Only the
+// directives in strftimeTokens are supported, and literal text must not
+// read as a Go directive, since Go layouts cannot escape one
+func strftimeLayout(pattern string) (string, error) {
+	var layout strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			j := i
+			for j < len(pattern) && pattern[j] != '%' {
+				j++
+			}
+			text := pattern[i:j]
+			for _, chunk := range splitLayout(text) {
+				if chunk.component != "" || strings.ContainsAny(chunk.text, "0123456789") {
+					return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+						"invalid strftime pattern %q: the literal text %q would be read as a Go layout directive; only directives may contain digits or words such as Mon, Jan, PM and MST",
+						pattern, text)
+				}
+			}
+			layout.WriteString(text)
+			i = j - 1
+			continue
+		}
+
+		directive := ""
+		if i+1 < len(pattern) {
+			directive = pattern[i+1 : i+2]
+			if directive == ":" && i+2 < len(pattern) {
+				directive = pattern[i+1 : i+3]
+			}
+		}
+		switch {
+		case directive == "%":
+			layout.WriteByte('%')
+		case directive == "f":
+			// Go only renders fractions after a . or ,
+			current := layout.String()
+			if !strings.HasSuffix(current, ".") && !strings.HasSuffix(current, ",") {
+				return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat, "invalid strftime pattern %q: %%f must follow a . or , as in %%S.%%f", pattern)
+			}
+			layout.WriteString("000000")
+		case strftimeLayouts[directive] != "":
+			layout.WriteString(strftimeLayouts[directive])
+		default:
+			return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+				"invalid strftime pattern %q: unsupported directive %%%s. Supported directives: %s", pattern, directive, strftimeTokens)
+		}
+		i += len(directive)
+	}
+	return layout.String(), nil
+}
+
+// layoutFor returns the Go layout for a format: named formats are returned
+// as they are, strftime patterns (containing %) are translated, and custom
+// layouts are validated
+func layoutFor(format string) (string, error) {
+	switch {
+	case FormatType(format) == FormatLayout:
+		return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+			"%s is not a format itself: pass a Go layout such as 2006-01-02 15:04 or a strftime pattern such as %%Y-%%m-%%d %%H:%%M as the format", FormatLayout)
+	case IsValidFormat(format):
+		return format, nil
+	case strings.Contains(format, "%"):
+		if len(format) > maxLayoutLength {
+			return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat, "layout is %d characters long, at most %d allowed", len(format), maxLayoutLength)
+		}
+		layout, err := strftimeLayout(format)
+		if err != nil {
+			return "", err
+		}
+		if err := ValidateLayout(layout); err != nil {
+			return "", fmt.Errorf("strftime pattern %q: %w", format, err)
+		}
+		return layout, nil
+	default:
+		return format, ValidateLayout(format)
+	}
+}
@@ -0,0 +1,121 @@
+package time
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		layout  string
+		errPart string
+	}{
+		{layout: time.RFC1123},
+		{layout: time.Kitchen},
+		{layout: time.StampMicro},
+		{layout: "2006-01-02T15:04:05.000Z07:00"},
+		{layout: "_2 January 2006"},
+		{layout: "Monday, 02-Jan-06 3:04 pm"},
+		{layout: "", errPart: "layout cannot be empty"},
+		{layout: strings.Repeat("2006", 30), errPart: "at most 100 allowed"},
+		{layout: "Layout", errPart: "it has no directives"},
+		{layout: "yyyy-MM-dd", errPart: "yyyy-MM-dd style patterns are not supported"},
+		{layout: "2023-12-25", errPart: "02 directly follows another day directive"},
+		{layout: "1999-12-31", errPart: "the digit 9 is not part of any directive"},
+		{layout: "2006-01-02 15:04 PM", errPart: "PM needs a 12-hour hour"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			err := ValidateLayout(tt.layout)
+			if tt.errPart == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, timeerrors.ErrInvalidFormat))
+			assert.Contains(t, err.Error(), tt.errPart)
+		})
+	}
+}
+
+func TestSplitLayout(t *testing.T) {
+	var got []string
+	for _, chunk := range splitLayout("_2006 Month .000") {
+		got = append(got, chunk.text+"|"+chunk.component)
+	}
+	assert.Equal(t, []string{"_|", "2006|year", " |", "Mon|weekday", "th |", ".000|fraction"}, got)
+}
+
+func TestStrftimeLayout(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+		errPart  string
+	}{
+		{pattern: "%Y-%m-%d %H:%M:%S", expected: "2006-01-02 15:04:05"},
+		{pattern: "%a, %d %b %Y %T %z", expected: "Mon, 02 Jan 2006 15:04:05 -0700"},
+		{pattern: "%F %R %:z", expected: "2006-01-02 15:04 -07:00"},
+		{pattern: "%I:%M %p on %A", expected: "03:04 PM on Monday"},
+		{pattern: "%S.%f", expected: "05.000000"},
+		{pattern: "%H:%M %%", expected: "15:04 %"},
+		{pattern: "%Y-%Q", errPart: "unsupported directive %Q. Supported directives: %Y"},
+		{pattern: "%Y%", errPart: "unsupported directive %."},
+		{pattern: "%S%f", errPart: "%f must follow a . or ,"},
+		{pattern: "%Y Month", errPart: `the literal text " Month" would be read as a Go layout directive`},
+		{pattern: "%H:%M in 2024", errPart: "would be read as a Go layout directive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			layout, err := strftimeLayout(tt.pattern)
+			if tt.errPart == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, layout)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, timeerrors.ErrInvalidFormat))
+			assert.Contains(t, err.Error(), tt.errPart)
+		})
+	}
+}
+
+func TestTimeService_CustomLayouts(t *testing.T) {
+	logger := newTestLogger(t)
+	withLayout := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Layout"}, logger)
+	withoutLayout := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+	at := TimestampFromTime(time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC))
+
+	formatted, err := withLayout.FormatTime(FormatTimeInput{Timestamp: at, Format: "%d/%m/%Y %H:%M"})
+	require.NoError(t, err)
+	assert.Equal(t, "25/12/2023 15:30", formatted.FormattedTime)
+
+	formatted, err = withLayout.FormatTime(FormatTimeInput{Timestamp: at, Format: "Jan _2 3:04PM"})
+	require.NoError(t, err)
+	assert.Equal(t, "Dec 25 3:30PM", formatted.FormattedTime)
+
+	_, err = withLayout.FormatTime(FormatTimeInput{Timestamp: at, Format: "Layout"})
+	assert.ErrorContains(t, err, "Layout is not a format itself")
+
+	_, err = withLayout.FormatTime(FormatTimeInput{Timestamp: at, Format: "DD/MM/YYYY"})
+	assert.ErrorContains(t, err, "it has no directives")
+
+	_, err = withoutLayout.FormatTime(FormatTimeInput{Timestamp: at, Format: "2006-01-02"})
+	assert.ErrorContains(t, err, "unsupported format: 2006-01-02")
+
+	parsed, err := withoutLayout.ParseTime(ParseTimeInput{TimeString: "25/12/2023 15:30", Format: "%d/%m/%Y %H:%M"})
+	require.NoError(t, err)
+	assert.Equal(t, "2023-12-25T15:30:00Z", parsed.RFC3339)
+
+	_, err = withoutLayout.ParseTime(ParseTimeInput{TimeString: "2023-12-25", Format: "2023-12-25"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidFormat))
+	assert.ErrorContains(t, err, "write 2006-01-02, not a date such as 2023-12-25")
+}
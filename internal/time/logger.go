@@ -0,0 +1,11 @@
+package time
+
+// Logger is the structured logging interface used by the time service.
+// Arguments are slog-style key-value pairs or slog.Attr values, so a
+// *slog.Logger satisfies it directly; logger.Slog adapts a zap logger.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
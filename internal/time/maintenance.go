@@ -0,0 +1,277 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Maintenance window recurrences
+const (
+	MaintenanceWeekly = "weekly"
+	MaintenanceDaily  = "daily"
+)
+
+const (
+	defaultMaintenanceOccurrences = 3
+	maxMaintenanceOccurrences     = 50
+	minutesPerWeek                = 7 * 24 * 60
+	minutesPerDay                 = 24 * 60
+)
+
+var (
+	// awsWindowPattern matches ddd:hh24:mi-ddd:hh24:mi, the weekly window
+	// of RDS, ElastiCache, Redshift and other AWS services, always in UTC
+	awsWindowPattern = regexp.MustCompile(`^([a-z]{3}):(\d{2}):(\d{2})-([a-z]{3}):(\d{2}):(\d{2})$`)
+	// dailyWindowPattern matches hh24:mi-hh24:mi, as in RDS backup windows
+	dailyWindowPattern = regexp.MustCompile(`^(\d{1,2}):(\d{2})-(\d{1,2}):(\d{2})$`)
+	// dayWindowPattern matches a weekday and a time range with an optional
+	// zone, such as SUN 06:00-07:00 UTC or Saturday 22:00-02:00
+	dayWindowPattern = regexp.MustCompile(`^([a-z]+)\s+(\d{1,2}):(\d{2})\s*-\s*(\d{1,2}):(\d{2})(?:\s+(\S+))?$`)
+)
+
+// MaintenanceWindowInput represents input for resolving a cloud provider
+// maintenance window
+type MaintenanceWindowInput struct {
+	// Window is ddd:hh24:mi-ddd:hh24:mi (AWS, UTC), hh24:mi-hh24:mi (daily)
+	// or a weekday and a time range with an optional zone, such as
+	// SUN 06:00-07:00 UTC
+	Window string `json:"window"`
+	// WindowTimezone is the zone a window without one is in, defaults to
+	// UTC as cloud providers do
+	WindowTimezone string    `json:"window_timezone,omitempty"`
+	Timezone       string    `json:"timezone,omitempty"` // zone occurrences are shown in, defaults to the server default
+	From           Timestamp `json:"from,omitempty"`     // defaults to now
+	Occurrences    int       `json:"occurrences,omitempty"`
+	// IntervalSemantics writes occurrence ends as the first second after
+	// the window (half_open, the default) or as its last second (closed)
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// MaintenanceOccurrence is one occurrence of a maintenance window
+type MaintenanceOccurrence struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	StartUTC string `json:"start_utc"`
+	EndUTC   string `json:"end_utc"`
+}
+
+// MaintenanceWindowResult represents a resolved maintenance window
+type MaintenanceWindowResult struct {
+	Window          string `json:"window"`
+	Recurrence      string `json:"recurrence"` // weekly or daily
+	WindowTimezone  string `json:"window_timezone"`
+	Timezone        string `json:"timezone"`
+	DurationMinutes int    `json:"duration_minutes"`
+	// IntervalSemantics is how occurrence ends are written: half_open or closed
+	IntervalSemantics string `json:"interval_semantics"`
+	// Active is set when from falls inside an occurrence, which is then
+	// the first one listed
+	Active      bool                    `json:"active"`
+	Occurrences []MaintenanceOccurrence `json:"occurrences"`
+	Summary     string                  `json:"summary"`
+}
+
+// maintenanceWindow is a parsed window. Weekly windows start on weekday;
+// start is in minutes after midnight and duration in minutes
+type maintenanceWindow struct {
+	recurrence string
+	weekday    time.Weekday
+	start      int
+	duration   int
+	zone       string
+}
+
+// ParseMaintenanceWindow resolves a cloud provider maintenance window into
+// its next occurrences in a requested zone
+func (s *timeService) ParseMaintenanceWindow(input MaintenanceWindowInput) (MaintenanceWindowResult, error) {
+	count, err := boundedCount("occurrences", input.Occurrences, defaultMaintenanceOccurrences, maxMaintenanceOccurrences)
+	if err != nil {
+		return MaintenanceWindowResult{}, err
+	}
+	window, err := parseMaintenanceWindow(input.Window)
+	if err != nil {
+		return MaintenanceWindowResult{}, err
+	}
+
+	windowZone := defaultString(window.zone, defaultString(input.WindowTimezone, "UTC"))
+	if window.zone != "" && input.WindowTimezone != "" && !strings.EqualFold(window.zone, input.WindowTimezone) {
+		return MaintenanceWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window names zone %s but window_timezone is %s", window.zone, input.WindowTimezone)
+	}
+	windowLoc, err := s.loadLocation(windowZone)
+	if err != nil {
+		return MaintenanceWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", windowZone, err)
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return MaintenanceWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	from := s.clock.Now()
+	if !input.From.IsZero() {
+		if from, err = input.From.Resolve(); err != nil {
+			return MaintenanceWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid from: %w", err)
+		}
+	}
+	semantics, err := ResolveIntervalSemantics(input.IntervalSemantics, IntervalHalfOpen)
+	if err != nil {
+		return MaintenanceWindowResult{}, err
+	}
+
+	result := MaintenanceWindowResult{
+		Window:            strings.TrimSpace(input.Window),
+		Recurrence:        window.recurrence,
+		WindowTimezone:    windowZone,
+		Timezone:          timezone,
+		DurationMinutes:   window.duration,
+		IntervalSemantics: semantics,
+		Occurrences:       []MaintenanceOccurrence{},
+	}
+
+	// Start a day early, so an occurrence still running at from is found
+	local := from.In(windowLoc)
+	day := time.Date(local.Year(), local.Month(), local.Day()-1, 0, 0, 0, 0, windowLoc)
+	length := time.Duration(window.duration) * time.Minute
+	for len(result.Occurrences) < count {
+		if window.recurrence == MaintenanceDaily || day.Weekday() == window.weekday {
+			start := time.Date(day.Year(), day.Month(), day.Day(), window.start/60, window.start%60, 0, 0, windowLoc)
+			end := start.Add(length)
+			if end.After(from) {
+				if len(result.Occurrences) == 0 && !start.After(from) {
+					result.Active = true
+				}
+				reported := ReportedEnd(end, semantics, IntervalSecond)
+				result.Occurrences = append(result.Occurrences, MaintenanceOccurrence{
+					Start:    start.In(loc).Format(time.RFC3339),
+					End:      reported.In(loc).Format(time.RFC3339),
+					StartUTC: start.UTC().Format(time.RFC3339),
+					EndUTC:   reported.UTC().Format(time.RFC3339),
+				})
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, windowLoc)
+	}
+
+	next := result.Occurrences[0]
+	if result.Active {
+		result.Summary = fmt.Sprintf("in maintenance now, until %s", next.End)
+	} else {
+		result.Summary = fmt.Sprintf("next window %s to %s", next.Start, next.End)
+	}
+
+	s.logger.Debug("Resolved maintenance window",
+		slog.String("window", result.Window),
+		slog.String("recurrence", result.Recurrence),
+		slog.Bool("active", result.Active))
+
+	return result, nil
+}
+
+// parseMaintenanceWindow reads the window syntaxes cloud providers use
+func parseMaintenanceWindow(value string) (maintenanceWindow, error) {
+	// Provider docs often use an en or em dash between the times
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer("–", "-", "—", "-").Replace(normalized)
+	if normalized == "" {
+		return maintenanceWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window cannot be empty")
+	}
+
+	var window maintenanceWindow
+	var err error
+	var end int
+	switch {
+	case awsWindowPattern.MatchString(normalized):
+		m := awsWindowPattern.FindStringSubmatch(normalized)
+		window.recurrence, window.zone = MaintenanceWeekly, "UTC"
+		var endDay time.Weekday
+		if window.weekday, err = windowWeekday(m[1]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		if endDay, err = windowWeekday(m[4]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		if window.start, err = windowMinutes(m[2], m[3]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		if end, err = windowMinutes(m[5], m[6]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		window.duration = mod(int(endDay)*minutesPerDay+end-(int(window.weekday)*minutesPerDay+window.start), minutesPerWeek)
+	case dailyWindowPattern.MatchString(normalized):
+		m := dailyWindowPattern.FindStringSubmatch(normalized)
+		window.recurrence = MaintenanceDaily
+		if window.start, err = windowMinutes(m[1], m[2]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		if end, err = windowMinutes(m[3], m[4]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		window.duration = mod(end-window.start, minutesPerDay)
+	case dayWindowPattern.MatchString(normalized):
+		m := dayWindowPattern.FindStringSubmatch(normalized)
+		window.recurrence = MaintenanceWeekly
+		if window.weekday, err = windowWeekday(m[1]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		if window.start, err = windowMinutes(m[2], m[3]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		if end, err = windowMinutes(m[4], m[5]); err != nil {
+			return maintenanceWindow{}, err
+		}
+		// A range ending before it starts runs past midnight
+		window.duration = mod(end-window.start, minutesPerDay)
+		if m[6] != "" {
+			fields := strings.Fields(value)
+			window.zone = windowZoneName(fields[len(fields)-1])
+		}
+	default:
+		return maintenanceWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"invalid window %q (must be ddd:hh24:mi-ddd:hh24:mi, hh24:mi-hh24:mi or a weekday and a time range, e.g. SUN 06:00-07:00 UTC)", value)
+	}
+	if window.duration == 0 {
+		return maintenanceWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window %q starts and ends at the same time", value)
+	}
+	return window, nil
+}
+
+// windowZoneName reads a window's zone as written, since the window is
+// matched lowercased and zone names are case-sensitive
+func windowZoneName(zone string) string {
+	if strings.EqualFold(zone, "utc") || strings.EqualFold(zone, "gmt") || strings.EqualFold(zone, "z") {
+		return "UTC"
+	}
+	return zone
+}
+
+// windowWeekday reads a weekday as a three-letter abbreviation or in full
+func windowWeekday(name string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, nil
+		}
+	}
+	return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid weekday %s (must be e.g. sun or sunday)", name)
+}
+
+// windowMinutes reads hh24:mi as minutes after midnight
+func windowMinutes(hour, minute string) (int, error) {
+	h, _ := strconv.Atoi(hour)
+	m, _ := strconv.Atoi(minute)
+	if h > 23 || m > 59 {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid time %s:%s (must be hh24:mi)", hour, minute)
+	}
+	return h*60 + m, nil
+}
+
+// mod is the non-negative remainder of a divided by b
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
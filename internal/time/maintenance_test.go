@@ -0,0 +1,145 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_ParseMaintenanceWindow(t *testing.T) {
+	logger := newTestLogger(t)
+	// Christmas 2023 is a Monday
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name       string
+		input      MaintenanceWindowInput
+		recurrence string
+		zone       string
+		duration   int
+		active     bool
+		starts     []string
+	}{
+		{
+			name:       "AWS weekly window in another zone",
+			input:      MaintenanceWindowInput{Window: "wed:04:00-wed:04:30", Timezone: "America/Los_Angeles", Occurrences: 2},
+			recurrence: MaintenanceWeekly, zone: "UTC", duration: 30,
+			starts: []string{"2023-12-26T20:00:00-08:00", "2024-01-02T20:00:00-08:00"},
+		},
+		{
+			name:       "AWS window wrapping the week",
+			input:      MaintenanceWindowInput{Window: "Sun:23:30-Mon:00:30", Occurrences: 1},
+			recurrence: MaintenanceWeekly, zone: "UTC", duration: 60,
+			starts: []string{"2023-12-31T23:30:00Z"},
+		},
+		{
+			name:       "active daily window past midnight",
+			input:      MaintenanceWindowInput{Window: "15:00-01:00", Occurrences: 2},
+			recurrence: MaintenanceDaily, zone: "UTC", duration: 600, active: true,
+			starts: []string{"2023-12-25T15:00:00Z", "2023-12-26T15:00:00Z"},
+		},
+		{
+			name:       "weekday with en dash",
+			input:      MaintenanceWindowInput{Window: "SUN 06:00–07:00 UTC", Occurrences: 1},
+			recurrence: MaintenanceWeekly, zone: "UTC", duration: 60,
+			starts: []string{"2023-12-31T06:00:00Z"},
+		},
+		{
+			name:       "weekday in a named zone",
+			input:      MaintenanceWindowInput{Window: "Saturday 22:00-02:00 Europe/Berlin", Timezone: "Europe/Berlin", Occurrences: 1},
+			recurrence: MaintenanceWeekly, zone: "Europe/Berlin", duration: 240,
+			starts: []string{"2023-12-30T22:00:00+01:00"},
+		},
+		{
+			name:       "window_timezone for a window without a zone",
+			input:      MaintenanceWindowInput{Window: "mon 15:00-16:00", WindowTimezone: "Asia/Tokyo", Occurrences: 1},
+			recurrence: MaintenanceWeekly, zone: "Asia/Tokyo", duration: 60,
+			starts: []string{"2024-01-01T06:00:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ParseMaintenanceWindow(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.recurrence, result.Recurrence)
+			assert.Equal(t, tt.zone, result.WindowTimezone)
+			assert.Equal(t, tt.duration, result.DurationMinutes)
+			assert.Equal(t, tt.active, result.Active)
+			var starts []string
+			for _, occurrence := range result.Occurrences {
+				starts = append(starts, occurrence.Start)
+			}
+			assert.Equal(t, tt.starts, starts)
+		})
+	}
+
+	result, err := service.ParseMaintenanceWindow(MaintenanceWindowInput{Window: "15:00-01:00"})
+	require.NoError(t, err)
+	assert.Equal(t, "in maintenance now, until 2023-12-26T01:00:00Z", result.Summary)
+	assert.Equal(t, "2023-12-26T01:00:00Z", result.Occurrences[0].EndUTC)
+
+	errorTests := []struct {
+		name  string
+		input MaintenanceWindowInput
+		err   error
+		want  string
+	}{
+		{"empty", MaintenanceWindowInput{}, timeerrors.ErrInvalidArgument, "window cannot be empty"},
+		{"unknown syntax", MaintenanceWindowInput{Window: "every sunday"}, timeerrors.ErrInvalidArgument, `invalid window "every sunday" (must be ddd:hh24:mi-ddd:hh24:mi, hh24:mi-hh24:mi or a weekday and a time range, e.g. SUN 06:00-07:00 UTC)`},
+		{"invalid weekday", MaintenanceWindowInput{Window: "wed:04:00-wen:04:30"}, timeerrors.ErrInvalidArgument, "invalid weekday wen (must be e.g. sun or sunday)"},
+		{"invalid time", MaintenanceWindowInput{Window: "24:00-01:00"}, timeerrors.ErrInvalidArgument, "invalid time 24:00 (must be hh24:mi)"},
+		{"empty window", MaintenanceWindowInput{Window: "mon:04:00-mon:04:00"}, timeerrors.ErrInvalidArgument, `window "mon:04:00-mon:04:00" starts and ends at the same time`},
+		{"conflicting zones", MaintenanceWindowInput{Window: "SUN 06:00-07:00 UTC", WindowTimezone: "Europe/Paris"}, timeerrors.ErrInvalidArgument, "window names zone UTC but window_timezone is Europe/Paris"},
+		{"unknown zone", MaintenanceWindowInput{Window: "SUN 06:00-07:00 Mars/Olympus"}, timeerrors.ErrInvalidTimezone, "invalid timezone Mars/Olympus: unknown time zone Mars/Olympus"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ParseMaintenanceWindow(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestTimeService_ParseMaintenanceWindow_IntervalSemantics(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		semantics string
+		echoed    string
+		end       string
+		endUTC    string
+		summary   string
+	}{
+		{"", IntervalHalfOpen, "2023-12-31T07:00:00+01:00", "2023-12-31T06:00:00Z", "next window 2023-12-31T06:00:00+01:00 to 2023-12-31T07:00:00+01:00"},
+		{IntervalClosed, IntervalClosed, "2023-12-31T06:59:59+01:00", "2023-12-31T05:59:59Z", "next window 2023-12-31T06:00:00+01:00 to 2023-12-31T06:59:59+01:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.echoed, func(t *testing.T) {
+			result, err := service.ParseMaintenanceWindow(MaintenanceWindowInput{
+				Window: "sun:05:00-sun:06:00", Timezone: "Europe/Paris", Occurrences: 1, IntervalSemantics: tt.semantics,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.echoed, result.IntervalSemantics)
+			assert.Equal(t, 60, result.DurationMinutes)
+			require.Len(t, result.Occurrences, 1)
+			assert.Equal(t, "2023-12-31T06:00:00+01:00", result.Occurrences[0].Start)
+			assert.Equal(t, tt.end, result.Occurrences[0].End)
+			assert.Equal(t, tt.endUTC, result.Occurrences[0].EndUTC)
+			assert.Equal(t, tt.summary, result.Summary)
+		})
+	}
+
+	_, err := service.ParseMaintenanceWindow(MaintenanceWindowInput{Window: "15:00-16:00", IntervalSemantics: "open"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+}
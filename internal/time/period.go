@@ -0,0 +1,230 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Period is a calendar period an instant can be aligned to
+type Period string
+
+const (
+	PeriodDay     Period = "day"
+	PeriodWeek    Period = "week"
+	PeriodMonth   Period = "month"
+	PeriodQuarter Period = "quarter"
+	PeriodHalf    Period = "half"
+	PeriodYear    Period = "year"
+)
+
+// AlignPeriodInput represents input for aligning an instant to a calendar period
+type AlignPeriodInput struct {
+	Period    string    `json:"period"`               // day, week, month, quarter, half or year
+	At        Timestamp `json:"at,omitempty"`         // instant to align, defaults to now
+	Timezone  string    `json:"timezone,omitempty"`   // timezone the calendar is evaluated in, defaults to the server default
+	WeekStart string    `json:"week_start,omitempty"` // first day of the week, defaults to monday (ISO 8601)
+	Locale    string    `json:"locale,omitempty"`     // locale for display_label: en, de, fr or es; defaults to en
+	// IntervalSemantics writes end as the start of the next period
+	// (half_open, the default) or as the period's last second (closed)
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// AlignPeriodResult represents the calendar period containing an instant
+type AlignPeriodResult struct {
+	Period          string  `json:"period"`
+	Timezone        string  `json:"timezone"`
+	Start           string  `json:"start"`
+	End             string  `json:"end"` // the start of the next period, or the last second when closed
+	DurationSeconds float64 `json:"duration_seconds"`
+	// IntervalSemantics is how end is written: half_open or closed
+	IntervalSemantics string `json:"interval_semantics"`
+	// Label is locale-independent and sortable, e.g. "2025-Q3", "2025-07", "2025-W27"
+	Label        string `json:"label"`
+	DisplayLabel string `json:"display_label"`
+	Locale       string `json:"locale"`
+}
+
+// periodLocale holds the words needed to render period display labels
+type periodLocale struct {
+	months   [12]string
+	weekdays [7]string
+	quarter  string // prefix for quarter numbers
+	half     string // prefix for half-year numbers
+	week     string // format with week number and year
+	day      string // format with weekday, day, month and year
+}
+
+// periodLocales are the supported display label locales
+var periodLocales = map[string]periodLocale{
+	"en": {
+		months:   [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		quarter:  "Q",
+		half:     "H",
+		week:     "Week %d, %d",
+		day:      "{weekday}, {month} {day}, {year}",
+	},
+	"de": {
+		months:   [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		quarter:  "Q",
+		half:     "H",
+		week:     "KW %d %d",
+		day:      "{weekday}, {day}. {month} {year}",
+	},
+	"fr": {
+		months:   [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		weekdays: [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		quarter:  "T",
+		half:     "S",
+		week:     "semaine %d %d",
+		day:      "{weekday} {day} {month} {year}",
+	},
+	"es": {
+		months:   [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		weekdays: [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		quarter:  "T",
+		half:     "S",
+		week:     "semana %d de %d",
+		day:      "{weekday}, {day} de {month} de {year}",
+	},
+}
+
+// parseWeekday parses an English weekday name
+func parseWeekday(name string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid week_start %s (must be a weekday name, e.g. monday or sunday)", name)
+}
+
+// AlignPeriod returns the calendar period containing an instant with its boundaries and labels
+func (s *timeService) AlignPeriod(input AlignPeriodInput) (AlignPeriodResult, error) {
+	period := Period(strings.ToLower(input.Period))
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return AlignPeriodResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	localeName := strings.ToLower(input.Locale)
+	if localeName == "" {
+		localeName = "en"
+	}
+	locale, ok := periodLocales[localeName]
+	if !ok {
+		return AlignPeriodResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "unsupported locale %s (supported: en, de, fr, es)", input.Locale)
+	}
+
+	semantics, err := ResolveIntervalSemantics(input.IntervalSemantics, IntervalHalfOpen)
+	if err != nil {
+		return AlignPeriodResult{}, err
+	}
+
+	weekStart := time.Monday
+	if input.WeekStart != "" {
+		if weekStart, err = parseWeekday(input.WeekStart); err != nil {
+			return AlignPeriodResult{}, err
+		}
+	}
+
+	at := s.clock.Now()
+	if !input.At.IsZero() {
+		if at, err = input.At.Resolve(); err != nil {
+			return AlignPeriodResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+	at = at.In(loc)
+
+	year, month, day := at.Date()
+	var start, end time.Time
+	var label, display string
+
+	switch period {
+	case PeriodDay:
+		start = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 0, 1)
+		label = start.Format("2006-01-02")
+		display = strings.NewReplacer(
+			"{weekday}", locale.weekdays[start.Weekday()],
+			"{month}", locale.months[month-1],
+			"{day}", fmt.Sprint(day),
+			"{year}", fmt.Sprint(year),
+		).Replace(locale.day)
+	case PeriodWeek:
+		back := (int(at.Weekday()) - int(weekStart) + 7) % 7
+		start = time.Date(year, month, day-back, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 0, 7)
+		weekYear, week := weekNumber(start, weekStart)
+		label = fmt.Sprintf("%d-W%02d", weekYear, week)
+		display = fmt.Sprintf(locale.week, week, weekYear)
+	case PeriodMonth:
+		start = time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 1, 0)
+		label = start.Format("2006-01")
+		display = fmt.Sprintf("%s %d", locale.months[month-1], year)
+	case PeriodQuarter:
+		quarter := (int(month)-1)/3 + 1
+		start = time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 3, 0)
+		label = fmt.Sprintf("%d-Q%d", year, quarter)
+		display = fmt.Sprintf("%s%d %d", locale.quarter, quarter, year)
+	case PeriodHalf:
+		half := (int(month)-1)/6 + 1
+		start = time.Date(year, time.Month((half-1)*6+1), 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(0, 6, 0)
+		label = fmt.Sprintf("%d-H%d", year, half)
+		display = fmt.Sprintf("%s%d %d", locale.half, half, year)
+	case PeriodYear:
+		start = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+		end = start.AddDate(1, 0, 0)
+		label = fmt.Sprint(year)
+		display = label
+	default:
+		return AlignPeriodResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid period %s (must be one of: day, week, month, quarter, half, year)", input.Period)
+	}
+
+	s.logger.Debug("Aligned instant to period",
+		slog.String("period", string(period)),
+		slog.String("label", label))
+
+	return AlignPeriodResult{
+		Period:            string(period),
+		Timezone:          timezone,
+		Start:             start.Format(time.RFC3339),
+		End:               ReportedEnd(end, semantics, IntervalSecond).Format(time.RFC3339),
+		DurationSeconds:   end.Sub(start).Seconds(),
+		IntervalSemantics: semantics,
+		Label:             label,
+		DisplayLabel:      display,
+		Locale:            localeName,
+	}, nil
+}
+
+// weekNumber numbers the week starting at start. Monday weeks use ISO 8601
+// numbering; other week starts number the week containing January 1 as week 1.
+func weekNumber(start time.Time, weekStart time.Weekday) (year, week int) {
+	if weekStart == time.Monday {
+		return start.ISOWeek()
+	}
+
+	// A week containing January 1 belongs to the new year
+	last := start.AddDate(0, 0, 6)
+	if last.Year() != start.Year() {
+		return last.Year(), 1
+	}
+
+	jan1 := time.Date(start.Year(), time.January, 1, 0, 0, 0, 0, start.Location())
+	lead := (int(jan1.Weekday()) - int(weekStart) + 7) % 7
+	return start.Year(), (start.YearDay()-1+lead)/7 + 1
+}
@@ -0,0 +1,182 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_AlignPeriod(t *testing.T) {
+	logger := newTestLogger(t)
+	// Friday
+	now := time.Date(2025, 7, 4, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name     string
+		input    AlignPeriodInput
+		start    string
+		end      string
+		label    string
+		display  string
+		duration float64
+	}{
+		{
+			name:     "day",
+			input:    AlignPeriodInput{Period: "day"},
+			start:    "2025-07-04T00:00:00Z",
+			end:      "2025-07-05T00:00:00Z",
+			label:    "2025-07-04",
+			display:  "Friday, July 4, 2025",
+			duration: 86400,
+		},
+		{
+			name:     "iso week",
+			input:    AlignPeriodInput{Period: "week"},
+			start:    "2025-06-30T00:00:00Z",
+			end:      "2025-07-07T00:00:00Z",
+			label:    "2025-W27",
+			display:  "Week 27, 2025",
+			duration: 604800,
+		},
+		{
+			name:     "week starting sunday",
+			input:    AlignPeriodInput{Period: "week", WeekStart: "Sunday"},
+			start:    "2025-06-29T00:00:00Z",
+			end:      "2025-07-06T00:00:00Z",
+			label:    "2025-W27",
+			display:  "Week 27, 2025",
+			duration: 604800,
+		},
+		{
+			name:     "sunday week containing january 1 belongs to the new year",
+			input:    AlignPeriodInput{Period: "week", WeekStart: "sunday", At: RFC3339Timestamp("2024-12-30T12:00:00Z")},
+			start:    "2024-12-29T00:00:00Z",
+			end:      "2025-01-05T00:00:00Z",
+			label:    "2025-W01",
+			display:  "Week 1, 2025",
+			duration: 604800,
+		},
+		{
+			name:     "month in german",
+			input:    AlignPeriodInput{Period: "month", Locale: "de"},
+			start:    "2025-07-01T00:00:00Z",
+			end:      "2025-08-01T00:00:00Z",
+			label:    "2025-07",
+			display:  "Juli 2025",
+			duration: 31 * 86400,
+		},
+		{
+			name:     "quarter in french",
+			input:    AlignPeriodInput{Period: "quarter", Locale: "fr"},
+			start:    "2025-07-01T00:00:00Z",
+			end:      "2025-10-01T00:00:00Z",
+			label:    "2025-Q3",
+			display:  "T3 2025",
+			duration: 92 * 86400,
+		},
+		{
+			name:     "quarter spanning a DST change",
+			input:    AlignPeriodInput{Period: "quarter", Timezone: "America/New_York", At: RFC3339Timestamp("2025-02-10T12:00:00Z")},
+			start:    "2025-01-01T00:00:00-05:00",
+			end:      "2025-04-01T00:00:00-04:00",
+			label:    "2025-Q1",
+			display:  "Q1 2025",
+			duration: 90*86400 - 3600,
+		},
+		{
+			name:     "half",
+			input:    AlignPeriodInput{Period: "half", Locale: "es"},
+			start:    "2025-07-01T00:00:00Z",
+			end:      "2026-01-01T00:00:00Z",
+			label:    "2025-H2",
+			display:  "S2 2025",
+			duration: 184 * 86400,
+		},
+		{
+			name:     "year in a timezone ahead of UTC",
+			input:    AlignPeriodInput{Period: "year", Timezone: "Pacific/Auckland", At: RFC3339Timestamp("2024-12-31T12:00:00Z")},
+			start:    "2025-01-01T00:00:00+13:00",
+			end:      "2026-01-01T00:00:00+13:00",
+			label:    "2025",
+			display:  "2025",
+			duration: 365 * 86400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.AlignPeriod(tt.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.start, result.Start)
+			assert.Equal(t, tt.end, result.End)
+			assert.Equal(t, tt.label, result.Label)
+			assert.Equal(t, tt.display, result.DisplayLabel)
+			assert.Equal(t, tt.duration, result.DurationSeconds)
+		})
+	}
+}
+
+func TestTimeService_AlignPeriod_Errors(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	tests := []struct {
+		name  string
+		input AlignPeriodInput
+	}{
+		{"invalid period", AlignPeriodInput{Period: "fortnight"}},
+		{"invalid week start", AlignPeriodInput{Period: "week", WeekStart: "someday"}},
+		{"unsupported locale", AlignPeriodInput{Period: "month", Locale: "xx"}},
+		{"invalid timezone", AlignPeriodInput{Period: "month", Timezone: "Invalid/Zone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AlignPeriod(tt.input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTimeService_AlignPeriod_IntervalSemantics(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2025, 7, 4, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		semantics string
+		period    string
+		timezone  string
+		end       string
+		echoed    string
+		duration  float64
+	}{
+		{"", "day", "UTC", "2025-07-05T00:00:00Z", IntervalHalfOpen, 86400},
+		{IntervalHalfOpen, "month", "UTC", "2025-08-01T00:00:00Z", IntervalHalfOpen, 31 * 86400},
+		{IntervalClosed, "day", "UTC", "2025-07-04T23:59:59Z", IntervalClosed, 86400},
+		{"CLOSED", "month", "UTC", "2025-07-31T23:59:59Z", IntervalClosed, 31 * 86400},
+		// The closed end of a day shortened by DST is still its last second
+		{IntervalClosed, "day", "Europe/Paris", "2025-03-30T23:59:59+02:00", IntervalClosed, 23 * 3600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period+" "+tt.semantics+" "+tt.timezone, func(t *testing.T) {
+			input := AlignPeriodInput{Period: tt.period, Timezone: tt.timezone, IntervalSemantics: tt.semantics}
+			if tt.timezone != "UTC" {
+				input.At = RFC3339Timestamp("2025-03-30T12:00:00+02:00")
+			}
+			result, err := service.AlignPeriod(input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.end, result.End)
+			assert.Equal(t, tt.echoed, result.IntervalSemantics)
+			assert.Equal(t, tt.duration, result.DurationSeconds, "the duration is the half-open one either way")
+		})
+	}
+
+	_, err := service.AlignPeriod(AlignPeriodInput{Period: "day", IntervalSemantics: "inclusive"})
+	assert.ErrorContains(t, err, "invalid interval_semantics")
+}
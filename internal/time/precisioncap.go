@@ -0,0 +1,29 @@
+package time
+
+import (
+	"time"
+)
+
+// WithPrecisionCap caps the instants get_time and format_time render with
+// truncate, the result envelope's precision cap. The envelope caps RFC3339
+// timestamps, HTTP-dates and epoch fields in every result, but can't read
+// back the other formats, such as Unix strings and custom layouts, so they
+// are capped here, before they are rendered
+func WithPrecisionCap(truncate func(time.Time) time.Time) Option {
+	return func(s *timeService) {
+		s.precisionCap = truncate
+	}
+}
+
+// capFormat caps an instant about to be rendered in format. Epoch formats
+// are capped in UTC, so they floor like the envelope's epoch fields; other
+// formats on the wall clock they are rendered in
+func (s *timeService) capFormat(t time.Time, format string) time.Time {
+	if s.precisionCap == nil {
+		return t
+	}
+	if _, ok := formatEpochUnits[FormatType(format)]; ok {
+		return s.precisionCap(t.UTC()).In(t.Location())
+	}
+	return s.precisionCap(t)
+}
@@ -0,0 +1,75 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/envelope"
+)
+
+// withPrecisionCap caps the service with the envelope's precision cap
+func withPrecisionCap(t *testing.T, precision string) Option {
+	t.Helper()
+	precisionCap, err := envelope.NewPrecisionCap(precision, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	return WithPrecisionCap(precisionCap.Truncate)
+}
+
+func TestTimeService_PrecisionCap_FormatTime(t *testing.T) {
+	logger := newTestLogger(t)
+	formats := []string{"RFC3339", "Unix", "UnixMilli", "Layout"}
+	timestamp := RFC3339Timestamp("2026-10-16T08:30:45.123456789Z")
+
+	tests := []struct {
+		name      string
+		precision string
+		format    string
+		timezone  string
+		expected  string
+	}{
+		{name: "minute unix", precision: "minute", format: "Unix", timezone: "UTC", expected: "1792139400"},
+		{name: "minute unix milli", precision: "minute", format: "UnixMilli", timezone: "UTC", expected: "1792139400000"},
+		{name: "minute custom layout", precision: "minute", format: "2006-01-02 15:04:05.000", timezone: "America/New_York", expected: "2026-10-16 04:30:00.000"},
+		// A day is capped to midnight in the zone a layout is rendered in,
+		// and epochs to midnight UTC, as the envelope caps epoch fields
+		{name: "day unix", precision: "day", format: "Unix", timezone: "America/New_York", expected: "1792108800"},
+		{name: "day custom layout", precision: "day", format: "2006-01-02 15:04:05.000 MST", timezone: "America/New_York", expected: "2026-10-16 00:00:00.000 EDT"},
+		{name: "milli custom layout", precision: "milli", format: "15:04:05.000000", timezone: "UTC", expected: "08:30:45.123000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewTimeService("UTC", "RFC3339", formats, logger, withPrecisionCap(t, tt.precision))
+			result, err := service.FormatTime(FormatTimeInput{Timestamp: timestamp, Format: tt.format, Timezone: tt.timezone})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.FormattedTime)
+		})
+	}
+
+	// Without a cap, nothing is truncated
+	service := NewTimeService("UTC", "RFC3339", formats, logger)
+	result, err := service.FormatTime(FormatTimeInput{Timestamp: timestamp, Format: "UnixMilli"})
+	require.NoError(t, err)
+	assert.Equal(t, "1792139445123", result.FormattedTime)
+}
+
+func TestTimeService_PrecisionCap_GetCurrentTime(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 30, 45, 123456789, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix", "UnixMilli", "Layout"}, newTestLogger(t),
+		WithClock(FixedClock{Time: now}), withPrecisionCap(t, "minute"))
+
+	result, err := service.GetCurrentTime(GetTimeInput{
+		Format:        "UnixMilli",
+		Formats:       []string{"Unix", "2006-01-02T15:04:05.000"},
+		EpochAsNumber: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1792139400000", result.FormattedTime)
+	assert.Equal(t, map[string]string{"Unix": "1792139400", "2006-01-02T15:04:05.000": "2026-10-16T08:30:00.000"}, result.FormattedTimes)
+	require.NotNil(t, result.FormattedEpoch)
+	assert.Equal(t, int64(1792139400000), *result.FormattedEpoch)
+}
@@ -0,0 +1,98 @@
+package time
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_ParseTime_NegativeEpochs(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	tests := []struct {
+		name       string
+		timeString string
+		format     string
+		unix       int64
+		rfc3339    string
+		nanosecond int
+		preEpoch   bool
+	}{
+		{"seconds", "-1", "Unix", -1, "1969-12-31T23:59:59Z", 0, true},
+		{"seconds far past", "-2208988800", "Unix", -2208988800, "1900-01-01T00:00:00Z", 0, true},
+		{"milliseconds", "-1", "UnixMilli", -1, "1969-12-31T23:59:59Z", 999000000, true},
+		{"milliseconds whole second", "-86400000", "UnixMilli", -86400, "1969-12-31T00:00:00Z", 0, true},
+		{"microseconds", "-1500000", "UnixMicro", -2, "1969-12-31T23:59:58Z", 500000000, true},
+		{"nanoseconds", "-1", "UnixNano", -1, "1969-12-31T23:59:59Z", 999999999, true},
+		{"zero is not pre-epoch", "0", "Unix", 0, "1970-01-01T00:00:00Z", 0, false},
+		{"rfc3339 before 1970", "1969-07-20T20:17:40Z", "RFC3339", -14182940, "1969-07-20T20:17:40Z", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ParseTime(ParseTimeInput{TimeString: tt.timeString, Format: tt.format})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.unix, result.UnixTimestamp)
+			assert.Equal(t, tt.rfc3339, result.RFC3339)
+			assert.Equal(t, tt.nanosecond, result.Components.Nanosecond)
+			assert.Equal(t, tt.preEpoch, result.IsPreEpoch)
+		})
+	}
+}
+
+func TestTimeService_FormatTime_NegativeEpochs(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339Nano", []string{"RFC3339Nano", "Unix", "UnixMilli", "UnixMicro", "UnixNano"}, logger)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		format    string
+		expected  string
+		preEpoch  bool
+	}{
+		{"number", `-1`, "RFC3339Nano", "1969-12-31T23:59:59Z", true},
+		{"string", `"-1"`, "RFC3339Nano", "1969-12-31T23:59:59Z", true},
+		{"fractional seconds", `-1.5`, "RFC3339Nano", "1969-12-31T23:59:58.5Z", true},
+		{"milliseconds", `{"epoch": -1, "unit": "ms"}`, "RFC3339Nano", "1969-12-31T23:59:59.999Z", true},
+		{"microseconds", `{"epoch": -1, "unit": "us"}`, "RFC3339Nano", "1969-12-31T23:59:59.999999Z", true},
+		{"nanoseconds", `{"epoch": -1, "unit": "ns"}`, "RFC3339Nano", "1969-12-31T23:59:59.999999999Z", true},
+		{"unix seconds round down", `{"epoch": -1, "unit": "ms"}`, "Unix", "-1", true},
+		{"unix milli", `-1.5`, "UnixMilli", "-1500", true},
+		{"unix micro", `{"epoch": -1, "unit": "ns"}`, "UnixMicro", "-1", true},
+		{"unix nano", `-1`, "UnixNano", "-1000000000", true},
+		{"epoch itself", `0`, "Unix", "0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			require.NoError(t, json.Unmarshal([]byte(tt.timestamp), &ts))
+
+			result, err := service.FormatTime(FormatTimeInput{Timestamp: ts, Format: tt.format})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, result.FormattedTime)
+			assert.Equal(t, tt.preEpoch, result.IsPreEpoch)
+		})
+	}
+}
+
+func TestTimestamp_NegativeRoundTrip(t *testing.T) {
+	tests := []string{`-1`, `-1.5`, `{"epoch":-1,"unit":"ms"}`}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			var ts Timestamp
+			require.NoError(t, json.Unmarshal([]byte(input), &ts))
+
+			data, err := json.Marshal(ts)
+			require.NoError(t, err)
+			assert.JSONEq(t, input, string(data))
+		})
+	}
+}
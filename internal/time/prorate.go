@@ -0,0 +1,185 @@
+package time
+
+import (
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Day-count conventions for proration
+const (
+	// DayCountActual counts the calendar days between dates in the timezone
+	DayCountActual = "actual/actual"
+	// DayCount30360 counts every month as 30 days and a year as 360, with
+	// the ISDA bond basis rules for the 31st
+	DayCount30360 = "30/360"
+	// DayCountExact counts elapsed seconds, as usage-based billing does
+	DayCountExact = "exact"
+)
+
+const (
+	defaultProrateDecimals = 2
+	maxProrateDecimals     = 8
+)
+
+// ProrateInput represents input for splitting a period's amount at a change
+type ProrateInput struct {
+	PeriodStart Timestamp `json:"period_start"`
+	PeriodEnd   Timestamp `json:"period_end"`
+	Change      Timestamp `json:"change,omitempty"` // the upgrade, downgrade or cancellation, defaults to now
+	// Amount is the price of the whole period, split into used and remaining
+	Amount float64 `json:"amount,omitempty"`
+	// Decimals rounds the amounts, defaults to 2
+	Decimals *int `json:"decimals,omitempty"`
+	// DayCount is actual/actual (default), 30/360 or exact
+	DayCount string `json:"day_count,omitempty"`
+	// Timezone is the billing timezone, whose calendar days are counted;
+	// defaults to the server default
+	Timezone string `json:"timezone,omitempty"`
+	// IntervalSemantics reads period_end as the first instant after the
+	// period (half_open, the default) or as its last day (closed), so a
+	// closed January 1 to January 31 period is 31 days
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// ProrateResult represents the used and remaining parts of a period
+type ProrateResult struct {
+	PeriodStart string `json:"period_start"`
+	PeriodEnd   string `json:"period_end"`
+	Change      string `json:"change"`
+	DayCount    string `json:"day_count"`
+	Timezone    string `json:"timezone"`
+	// IntervalSemantics is how period_end was read: half_open or closed
+	IntervalSemantics string `json:"interval_semantics"`
+	// Units are days, or seconds for the exact convention
+	Units          string  `json:"units"`
+	PeriodUnits    float64 `json:"period_units"`
+	UsedUnits      float64 `json:"used_units"`
+	RemainingUnits float64 `json:"remaining_units"`
+	UsedFraction   float64 `json:"used_fraction"`
+	// RemainingFraction is the share of the period after the change, the
+	// usual credit for a downgrade or cancellation
+	RemainingFraction float64 `json:"remaining_fraction"`
+	Amount            float64 `json:"amount"`
+	UsedAmount        float64 `json:"used_amount"`
+	// RemainingAmount is the amount less the rounded used amount, so the
+	// two always add up to the amount
+	RemainingAmount float64 `json:"remaining_amount"`
+}
+
+// Prorate splits a billing period and its amount at a change, with a
+// day-count convention
+func (s *timeService) Prorate(input ProrateInput) (ProrateResult, error) {
+	if input.PeriodStart.IsZero() || input.PeriodEnd.IsZero() {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "period_start and period_end are required")
+	}
+	start, err := input.PeriodStart.Resolve()
+	if err != nil {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid period_start: %w", err)
+	}
+	end, err := input.PeriodEnd.Resolve()
+	if err != nil {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid period_end: %w", err)
+	}
+	change := s.clock.Now()
+	if !input.Change.IsZero() {
+		if change, err = input.Change.Resolve(); err != nil {
+			return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid change: %w", err)
+		}
+	}
+	semantics, err := ResolveIntervalSemantics(input.IntervalSemantics, IntervalHalfOpen)
+	if err != nil {
+		return ProrateResult{}, err
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	start, end, change = start.In(loc), end.In(loc), change.In(loc)
+	// A closed period_end is the last day of the period; compute with the
+	// day after it and write it back as given
+	givenEnd := end
+	end = ExclusiveEnd(end, semantics, IntervalDay)
+
+	if !end.After(start) {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "period_end must be after period_start")
+	}
+	if change.Before(start) || change.After(end) {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "change %s is outside the period %s to %s",
+			change.Format(time.RFC3339), start.Format(time.RFC3339), givenEnd.Format(time.RFC3339))
+	}
+	decimals := defaultProrateDecimals
+	if input.Decimals != nil {
+		if decimals = *input.Decimals; decimals < 0 || decimals > maxProrateDecimals {
+			return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "decimals must be between 0 and %d, got %d", maxProrateDecimals, decimals)
+		}
+	}
+	if math.IsNaN(input.Amount) || math.IsInf(input.Amount, 0) {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "amount must be a finite number")
+	}
+	dayCount := strings.ToLower(defaultString(input.DayCount, DayCountActual))
+
+	var count func(from, to time.Time) float64
+	units := "days"
+	switch dayCount {
+	case DayCountActual:
+		count = func(from, to time.Time) float64 { return float64(epochDay(to) - epochDay(from)) }
+	case DayCount30360:
+		count = func(from, to time.Time) float64 { return float64(days30360(from, to)) }
+	case DayCountExact:
+		count = func(from, to time.Time) float64 { return to.Sub(from).Seconds() }
+		units = "seconds"
+	default:
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid day_count %q (must be one of: actual/actual, 30/360, exact)", input.DayCount)
+	}
+	period := count(start, end)
+	if period <= 0 {
+		return ProrateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "the period is empty under %s: it starts and ends on the same day", dayCount)
+	}
+
+	used := count(start, change)
+	scale := math.Pow(10, float64(decimals))
+	round := func(v float64) float64 { return math.Round(v*scale) / scale }
+	result := ProrateResult{
+		PeriodStart:       start.Format(time.RFC3339Nano),
+		PeriodEnd:         givenEnd.Format(time.RFC3339Nano),
+		Change:            change.Format(time.RFC3339Nano),
+		DayCount:          dayCount,
+		Timezone:          timezone,
+		IntervalSemantics: semantics,
+		Units:             units,
+		PeriodUnits:       period,
+		UsedUnits:         used,
+		RemainingUnits:    period - used,
+		UsedFraction:      used / period,
+		RemainingFraction: (period - used) / period,
+		Amount:            input.Amount,
+		UsedAmount:        round(input.Amount * used / period),
+	}
+	result.RemainingAmount = round(input.Amount - result.UsedAmount)
+
+	s.logger.Debug("Prorated period",
+		slog.String("day_count", dayCount),
+		slog.Float64("used_fraction", result.UsedFraction),
+		slog.Float64("used_amount", result.UsedAmount))
+
+	return result, nil
+}
+
+// days30360 counts the days between two dates as if every month had 30,
+// following the ISDA 30/360 bond basis: a 31st counts as the 30th, and an
+// end on the 31st does too when the start is on the 30th or 31st
+func days30360(from, to time.Time) int {
+	d1, d2 := from.Day(), to.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return 360*(to.Year()-from.Year()) + 30*int(to.Month()-from.Month()) + d2 - d1
+}
@@ -0,0 +1,125 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_Prorate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	decimals := func(v int) *int { return &v }
+
+	tests := []struct {
+		name      string
+		input     ProrateInput
+		period    float64
+		used      float64
+		fraction  float64
+		usedAmt   float64
+		remaining float64
+	}{
+		{
+			name: "actual days",
+			input: ProrateInput{PeriodStart: RFC3339Timestamp("2024-02-01T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-03-01T00:00:00Z"),
+				Change: RFC3339Timestamp("2024-02-11T15:00:00Z"), Amount: 29},
+			period: 29, used: 10, fraction: 10.0 / 29, usedAmt: 10, remaining: 19,
+		},
+		{
+			name: "30/360",
+			input: ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-15T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-02-15T00:00:00Z"),
+				Change: RFC3339Timestamp("2024-01-31T00:00:00Z"), Amount: 100, DayCount: "30/360"},
+			period: 30, used: 16, fraction: 16.0 / 30, usedAmt: 53.33, remaining: 46.67,
+		},
+		{
+			name: "exact seconds",
+			input: ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-01T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-01-11T00:00:00Z"),
+				Change: RFC3339Timestamp("2024-01-03T12:00:00Z"), Amount: 99.99, DayCount: "exact"},
+			period: 864000, used: 216000, fraction: 0.25, usedAmt: 25, remaining: 74.99,
+		},
+		{
+			name: "whole currency units",
+			input: ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-01T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-01-04T00:00:00Z"),
+				Change: RFC3339Timestamp("2024-01-02T00:00:00Z"), Amount: 1000, Decimals: decimals(0)},
+			period: 3, used: 1, fraction: 1.0 / 3, usedAmt: 333, remaining: 667,
+		},
+		{
+			name: "days counted in the billing timezone",
+			input: ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-01T05:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-01-11T05:00:00Z"),
+				Change: RFC3339Timestamp("2024-01-06T03:00:00Z"), Amount: 10, Timezone: "America/New_York"},
+			period: 10, used: 4, fraction: 0.4, usedAmt: 4, remaining: 6,
+		},
+		{
+			name: "closed period ends on its last day",
+			input: ProrateInput{PeriodStart: RFC3339Timestamp("2024-02-01T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-02-29T00:00:00Z"),
+				Change: RFC3339Timestamp("2024-02-11T15:00:00Z"), Amount: 29, IntervalSemantics: IntervalClosed},
+			period: 29, used: 10, fraction: 10.0 / 29, usedAmt: 10, remaining: 19,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.Prorate(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.period, result.PeriodUnits)
+			assert.Equal(t, tt.used, result.UsedUnits)
+			assert.InDelta(t, tt.fraction, result.UsedFraction, 1e-12)
+			assert.InDelta(t, 1-tt.fraction, result.RemainingFraction, 1e-12)
+			assert.Equal(t, tt.usedAmt, result.UsedAmount)
+			assert.Equal(t, tt.remaining, result.RemainingAmount)
+		})
+	}
+
+	// The change defaults to now
+	result, err := service.Prorate(ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-01T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-01-31T00:00:00Z")})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-15T00:00:00Z", result.Change)
+	assert.Equal(t, float64(14), result.UsedUnits)
+}
+
+func TestDays30360(t *testing.T) {
+	day := func(s string) time.Time {
+		t, _ := time.Parse(time.DateOnly, s)
+		return t
+	}
+	assert.Equal(t, 60, days30360(day("2024-01-30"), day("2024-03-31")))
+	// The end stays on the 31st when the start is before the 30th
+	assert.Equal(t, 62, days30360(day("2024-01-29"), day("2024-03-31")))
+	assert.Equal(t, 360, days30360(day("2023-12-31"), day("2024-12-31")))
+	assert.Equal(t, 30, days30360(day("2024-02-01"), day("2024-03-01")))
+}
+
+func TestTimeService_ProrateErrors(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	decimals := func(v int) *int { return &v }
+	input := func(mutate func(*ProrateInput)) ProrateInput {
+		in := ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-01T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-02-01T00:00:00Z")}
+		mutate(&in)
+		return in
+	}
+
+	_, err := service.Prorate(input(func(in *ProrateInput) { in.Change = RFC3339Timestamp("2024-02-02T00:00:00Z") }))
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	_, err = service.Prorate(input(func(in *ProrateInput) { in.PeriodEnd = RFC3339Timestamp("2023-12-01T00:00:00Z") }))
+	assert.ErrorContains(t, err, "period_end must be after period_start")
+
+	_, err = service.Prorate(input(func(in *ProrateInput) { in.DayCount = "actual/365" }))
+	assert.ErrorContains(t, err, "must be one of: actual/actual, 30/360, exact")
+
+	_, err = service.Prorate(input(func(in *ProrateInput) { in.Decimals = decimals(9) }))
+	assert.ErrorContains(t, err, "decimals must be between 0 and 8")
+
+	_, err = service.Prorate(ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-15T00:00:00Z"), PeriodEnd: RFC3339Timestamp("2024-01-15T12:00:00Z")})
+	assert.ErrorContains(t, err, "the period is empty under actual/actual")
+
+	_, err = service.Prorate(ProrateInput{PeriodStart: RFC3339Timestamp("2024-01-01T00:00:00Z")})
+	assert.ErrorContains(t, err, "period_start and period_end are required")
+}
@@ -0,0 +1,307 @@
+package time
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Scoring weights for resolve_abbreviation. The prior reflects how often an
+// abbreviation means a zone in general text; hints add to it
+const (
+	countryHintScore  = 3.0
+	locationHintScore = 2.0
+	languageHintScore = 1.0
+	regionHintScore   = 0.5
+	inEffectScore     = 1.0
+	notInEffectScore  = -1.0
+	// locationScale is the distance, in km, at which the location bonus has
+	// fallen to about a third
+	locationScale = 1500.0
+	// ambiguityMargin is the score gap under which the top two candidates
+	// are reported as ambiguous
+	ambiguityMargin = 1.0
+	earthRadiusKm   = 6371.0
+)
+
+// abbreviationMeaning is one zone an abbreviation is used for, with the
+// offset it denotes and the zone's reference city from zone1970.tab
+type abbreviationMeaning struct {
+	timezone  string
+	country   string
+	name      string
+	offset    int
+	dst       bool
+	prior     float64
+	latitude  float64
+	longitude float64
+}
+
+// abbreviationMeanings lists the zones each ambiguous abbreviation is
+// commonly used for. tzdata alone can't answer this: it writes numeric
+// placeholders for many zones, e.g. "+03" where people write AST for Riyadh
+var abbreviationMeanings = map[string][]abbreviationMeaning{
+	"CST": {
+		{"America/Chicago", "US", "Central Standard Time", -6 * 3600, false, 1.0, 41.85, -87.65},
+		{"Asia/Shanghai", "CN", "China Standard Time", 8 * 3600, false, 0.9, 31.23, 121.47},
+		{"America/Mexico_City", "MX", "Central Standard Time", -6 * 3600, false, 0.5, 19.40, -99.15},
+		{"America/Havana", "CU", "Cuba Standard Time", -5 * 3600, false, 0.3, 23.13, -82.37},
+		{"Asia/Taipei", "TW", "Taiwan Standard Time", 8 * 3600, false, 0.3, 25.05, 121.50},
+		{"America/Regina", "CA", "Central Standard Time", -6 * 3600, false, 0.3, 50.40, -104.65},
+	},
+	"CDT": {
+		{"America/Chicago", "US", "Central Daylight Time", -5 * 3600, true, 1.0, 41.85, -87.65},
+		{"America/Havana", "CU", "Cuba Daylight Time", -4 * 3600, true, 0.3, 23.13, -82.37},
+	},
+	"IST": {
+		{"Asia/Kolkata", "IN", "India Standard Time", 5*3600 + 1800, false, 1.0, 22.53, 88.37},
+		{"Asia/Jerusalem", "IL", "Israel Standard Time", 2 * 3600, false, 0.5, 31.78, 35.22},
+		{"Europe/Dublin", "IE", "Irish Standard Time", 1 * 3600, false, 0.4, 53.33, -6.25},
+	},
+	"BST": {
+		{"Europe/London", "GB", "British Summer Time", 1 * 3600, true, 1.0, 51.51, -0.12},
+		{"Asia/Dhaka", "BD", "Bangladesh Standard Time", 6 * 3600, false, 0.4, 23.72, 90.42},
+	},
+	"AST": {
+		{"America/Halifax", "CA", "Atlantic Standard Time", -4 * 3600, false, 0.6, 44.65, -63.60},
+		{"Asia/Riyadh", "SA", "Arabia Standard Time", 3 * 3600, false, 0.6, 24.63, 46.72},
+		{"America/Puerto_Rico", "PR", "Atlantic Standard Time", -4 * 3600, false, 0.4, 18.47, -66.10},
+	},
+	"ADT": {
+		{"America/Halifax", "CA", "Atlantic Daylight Time", -3 * 3600, true, 1.0, 44.65, -63.60},
+	},
+	"EST": {
+		{"America/New_York", "US", "Eastern Standard Time", -5 * 3600, false, 1.0, 40.71, -74.01},
+		{"Australia/Sydney", "AU", "Australian Eastern Standard Time", 10 * 3600, false, 0.3, -33.87, 151.22},
+	},
+	"EDT": {
+		{"America/New_York", "US", "Eastern Daylight Time", -4 * 3600, true, 1.0, 40.71, -74.01},
+		{"Australia/Sydney", "AU", "Australian Eastern Daylight Time", 11 * 3600, true, 0.2, -33.87, 151.22},
+	},
+	"MST": {
+		{"America/Denver", "US", "Mountain Standard Time", -7 * 3600, false, 1.0, 39.74, -104.98},
+		{"America/Phoenix", "US", "Mountain Standard Time", -7 * 3600, false, 0.6, 33.45, -112.07},
+		{"Asia/Kuala_Lumpur", "MY", "Malaysia Standard Time", 8 * 3600, false, 0.3, 3.17, 101.70},
+	},
+	"PST": {
+		{"America/Los_Angeles", "US", "Pacific Standard Time", -8 * 3600, false, 1.0, 34.05, -118.24},
+		{"Asia/Manila", "PH", "Philippine Standard Time", 8 * 3600, false, 0.4, 14.59, 120.98},
+		{"Asia/Karachi", "PK", "Pakistan Standard Time", 5 * 3600, false, 0.4, 24.87, 67.05},
+	},
+	"GST": {
+		{"Asia/Dubai", "AE", "Gulf Standard Time", 4 * 3600, false, 0.8, 25.30, 55.30},
+		{"Atlantic/South_Georgia", "GS", "South Georgia Time", -2 * 3600, false, 0.1, -54.27, -36.53},
+	},
+	"SST": {
+		{"Asia/Singapore", "SG", "Singapore Standard Time", 8 * 3600, false, 0.6, 1.28, 103.85},
+		{"Pacific/Pago_Pago", "AS", "Samoa Standard Time", -11 * 3600, false, 0.5, -14.27, -170.70},
+	},
+	"AMT": {
+		{"America/Manaus", "BR", "Amazon Time", -4 * 3600, false, 0.7, -3.13, -60.02},
+		{"Asia/Yerevan", "AM", "Armenia Time", 4 * 3600, false, 0.3, 40.18, 44.50},
+	},
+	"ECT": {
+		{"America/Guayaquil", "EC", "Ecuador Time", -5 * 3600, false, 0.6, -2.17, -79.83},
+		{"Europe/Paris", "FR", "European Central Time", 1 * 3600, false, 0.2, 48.87, 2.33},
+	},
+}
+
+// languageCountries lists the countries where a language's speakers are
+// likely to use the abbreviations above, by ISO 639-1 code
+var languageCountries = map[string][]string{
+	"en":  {"US", "GB", "CA", "AU", "IE", "IN", "PH", "SG", "PK", "PR"},
+	"zh":  {"CN", "TW", "SG", "MY"},
+	"es":  {"MX", "CU", "EC", "PR", "US"},
+	"pt":  {"BR"},
+	"fr":  {"FR", "CA"},
+	"ar":  {"SA", "AE"},
+	"he":  {"IL"},
+	"hi":  {"IN"},
+	"bn":  {"BD", "IN"},
+	"ur":  {"PK"},
+	"ms":  {"MY", "SG"},
+	"tl":  {"PH"},
+	"fil": {"PH"},
+	"hy":  {"AM"},
+	"sm":  {"AS"},
+	"ga":  {"IE"},
+}
+
+// ResolveAbbreviationInput represents an abbreviation to resolve and the
+// context hints that rank its candidates
+type ResolveAbbreviationInput struct {
+	Abbreviation string `json:"abbreviation"`
+	// At is when the abbreviation was used, e.g. a document's date; defaults to now
+	At Timestamp `json:"at,omitempty"`
+	// Country is an ISO 3166-1 alpha-2 code, e.g. "CN"
+	Country   string   `json:"country,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	// Language is a BCP 47 tag of the text the abbreviation appeared in, e.g. "zh" or "es-CU"
+	Language string `json:"language,omitempty"`
+}
+
+// ResolveAbbreviationResult ranks the zones an abbreviation may mean
+type ResolveAbbreviationResult struct {
+	Abbreviation string                  `json:"abbreviation"`
+	At           string                  `json:"at"`
+	Best         string                  `json:"best"`
+	Ambiguous    bool                    `json:"ambiguous"`
+	Candidates   []AbbreviationCandidate `json:"candidates"`
+}
+
+// AbbreviationCandidate is one zone an abbreviation may mean. Offset is
+// what the abbreviation denotes; ZoneOffset is what tzdata has the zone at
+// at the requested instant, which differs when the abbreviation was not in
+// use then
+type AbbreviationCandidate struct {
+	Timezone   string   `json:"timezone"`
+	Country    string   `json:"country"`
+	Name       string   `json:"name"`
+	Offset     string   `json:"offset"`
+	DST        bool     `json:"dst"`
+	ZoneOffset string   `json:"zone_offset"`
+	InEffect   bool     `json:"in_effect"`
+	Score      float64  `json:"score"`
+	Reasons    []string `json:"reasons"`
+}
+
+// ResolveAbbreviation ranks the zones an abbreviation may mean using the
+// context hints given, and explains each score
+func (s *timeService) ResolveAbbreviation(input ResolveAbbreviationInput) (ResolveAbbreviationResult, error) {
+	abbreviation := strings.ToUpper(strings.TrimSpace(input.Abbreviation))
+	if abbreviation == "" {
+		return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "abbreviation is required")
+	}
+	meanings, ok := abbreviationMeanings[abbreviation]
+	if !ok {
+		return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"unknown abbreviation %q (known: %s)", input.Abbreviation, strings.Join(knownAbbreviations(), ", "))
+	}
+
+	at := s.clock.Now()
+	if !input.At.IsZero() {
+		var err error
+		if at, err = input.At.Resolve(); err != nil {
+			return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(input.Country))
+	if country != "" && len(country) != 2 {
+		return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"invalid country %q (must be an ISO 3166-1 alpha-2 code such as CN)", input.Country)
+	}
+	if (input.Latitude == nil) != (input.Longitude == nil) {
+		return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "latitude and longitude must be set together")
+	}
+	if input.Latitude != nil && (math.Abs(*input.Latitude) > 90 || math.Abs(*input.Longitude) > 180) {
+		return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"invalid coordinates %g,%g (latitude must be within ±90, longitude within ±180)", *input.Latitude, *input.Longitude)
+	}
+	language, region := splitLanguageTag(input.Language)
+
+	result := ResolveAbbreviationResult{
+		Abbreviation: abbreviation,
+		At:           at.UTC().Format(time.RFC3339),
+		Candidates:   make([]AbbreviationCandidate, 0, len(meanings)),
+	}
+	for _, meaning := range meanings {
+		loc, err := s.loadLocation(meaning.timezone)
+		if err != nil {
+			return ResolveAbbreviationResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", meaning.timezone, err)
+		}
+		_, zoneOffset := at.In(loc).Zone()
+
+		candidate := AbbreviationCandidate{
+			Timezone:   meaning.timezone,
+			Country:    meaning.country,
+			Name:       meaning.name,
+			Offset:     formatOffset(meaning.offset, s.roundOffsets),
+			DST:        meaning.dst,
+			ZoneOffset: formatOffset(zoneOffset, s.roundOffsets),
+			InEffect:   zoneOffset == meaning.offset,
+			Score:      meaning.prior,
+			Reasons:    []string{fmt.Sprintf("prior %.1f for %s as %s", meaning.prior, abbreviation, meaning.name)},
+		}
+		add := func(score float64, reason string, args ...any) {
+			candidate.Score += score
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf(reason, args...))
+		}
+
+		if candidate.InEffect {
+			add(inEffectScore, "%s was at %s on %s", meaning.timezone, candidate.ZoneOffset, at.In(loc).Format(time.DateOnly))
+		} else {
+			add(notInEffectScore, "%s was at %s on %s, not %s", meaning.timezone, candidate.ZoneOffset,
+				at.In(loc).Format(time.DateOnly), candidate.Offset)
+		}
+		if country != "" && country == meaning.country {
+			add(countryHintScore, "country %s matches", country)
+		}
+		if input.Latitude != nil {
+			km := distanceKm(*input.Latitude, *input.Longitude, meaning.latitude, meaning.longitude)
+			add(locationHintScore*math.Exp(-km/locationScale), "%.0f km from the zone's reference city", km)
+		}
+		if language != "" && slices.Contains(languageCountries[language], meaning.country) {
+			add(languageHintScore, "language %s is used in %s", language, meaning.country)
+		}
+		if region != "" && region == meaning.country {
+			add(regionHintScore, "language region %s matches", region)
+		}
+
+		candidate.Score = math.Round(candidate.Score*100) / 100
+		result.Candidates = append(result.Candidates, candidate)
+	}
+
+	// Stable, so equal scores keep the table's order of general usage
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].Score > result.Candidates[j].Score
+	})
+	result.Best = result.Candidates[0].Timezone
+	result.Ambiguous = len(result.Candidates) > 1 &&
+		result.Candidates[0].Score-result.Candidates[1].Score < ambiguityMargin
+
+	return result, nil
+}
+
+// knownAbbreviations lists the abbreviations resolve_abbreviation has
+// candidates for, sorted
+func knownAbbreviations() []string {
+	names := make([]string, 0, len(abbreviationMeanings))
+	for name := range abbreviationMeanings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitLanguageTag returns the lowercase language and uppercase region of a
+// BCP 47 tag such as "es-CU" or "zh_Hant_TW". Scripts are skipped
+func splitLanguageTag(tag string) (language, region string) {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return "", ""
+	}
+	language = strings.ToLower(parts[0])
+	for _, part := range parts[1:] {
+		if len(part) == 2 {
+			region = strings.ToUpper(part)
+		}
+	}
+	return language, region
+}
+
+// distanceKm is the great-circle distance between two points
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
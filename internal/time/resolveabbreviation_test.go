@@ -0,0 +1,89 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_ResolveAbbreviation(t *testing.T) {
+	winter := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: winter}))
+	coordinates := func(lat, lon float64) (*float64, *float64) { return &lat, &lon }
+
+	t.Run("no hints follows general usage", func(t *testing.T) {
+		result, err := service.ResolveAbbreviation(ResolveAbbreviationInput{Abbreviation: "cst"})
+		require.NoError(t, err)
+		assert.Equal(t, "CST", result.Abbreviation)
+		assert.Equal(t, "America/Chicago", result.Best)
+		assert.True(t, result.Ambiguous)
+		require.Len(t, result.Candidates, 6)
+		assert.Equal(t, "Asia/Shanghai", result.Candidates[1].Timezone)
+		assert.Equal(t, "+08:00", result.Candidates[1].Offset)
+		assert.True(t, result.Candidates[1].InEffect)
+	})
+
+	t.Run("country hint", func(t *testing.T) {
+		result, err := service.ResolveAbbreviation(ResolveAbbreviationInput{Abbreviation: "CST", Country: "cu"})
+		require.NoError(t, err)
+		assert.Equal(t, "America/Havana", result.Best)
+		assert.False(t, result.Ambiguous)
+		assert.Contains(t, result.Candidates[0].Reasons, "country CU matches")
+	})
+
+	t.Run("coordinates hint", func(t *testing.T) {
+		// Guangzhou
+		lat, lon := coordinates(23.13, 113.26)
+		result, err := service.ResolveAbbreviation(ResolveAbbreviationInput{Abbreviation: "CST", Latitude: lat, Longitude: lon})
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Shanghai", result.Best)
+	})
+
+	t.Run("language hint with region", func(t *testing.T) {
+		result, err := service.ResolveAbbreviation(ResolveAbbreviationInput{Abbreviation: "IST", Language: "he-IL"})
+		require.NoError(t, err)
+		assert.Equal(t, "Asia/Jerusalem", result.Best)
+		assert.Contains(t, result.Candidates[0].Reasons, "language he is used in IL")
+		assert.Contains(t, result.Candidates[0].Reasons, "language region IL matches")
+	})
+
+	t.Run("abbreviation not in effect at the date", func(t *testing.T) {
+		// Irish Standard Time is the summer offset
+		result, err := service.ResolveAbbreviation(ResolveAbbreviationInput{Abbreviation: "IST", Country: "IE"})
+		require.NoError(t, err)
+		dublin := result.Candidates[0]
+		assert.Equal(t, "Europe/Dublin", dublin.Timezone)
+		assert.False(t, dublin.InEffect)
+		assert.Equal(t, "+00:00", dublin.ZoneOffset)
+		assert.Contains(t, dublin.Reasons, "Europe/Dublin was at +00:00 on 2024-01-15, not +01:00")
+
+		result, err = service.ResolveAbbreviation(ResolveAbbreviationInput{Abbreviation: "IST", Country: "IE",
+			At: RFC3339Timestamp("2024-07-01T12:00:00Z")})
+		require.NoError(t, err)
+		assert.True(t, result.Candidates[0].InEffect)
+		assert.Equal(t, "2024-07-01T12:00:00Z", result.At)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		lat, _ := coordinates(95, 0)
+		for _, input := range []ResolveAbbreviationInput{
+			{},
+			{Abbreviation: "XYZ"},
+			{Abbreviation: "CST", Country: "China"},
+			{Abbreviation: "CST", Latitude: lat},
+		} {
+			_, err := service.ResolveAbbreviation(input)
+			assert.ErrorIs(t, err, timeerrors.ErrInvalidArgument, "%+v", input)
+		}
+	})
+}
+
+func TestDistanceKm(t *testing.T) {
+	// London to Paris
+	assert.InDelta(t, 344, distanceKm(51.51, -0.12, 48.87, 2.33), 5)
+	assert.Zero(t, distanceKm(10, 20, 10, 20))
+}
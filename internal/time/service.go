@@ -2,10 +2,10 @@ package time
 
 import (
 	"fmt"
-	"strconv"
+	"log/slog"
 	"time"
 
-	"go.uber.org/zap"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/service_mock.go
@@ -32,6 +32,105 @@ type TimeService interface {
 
 	// GetSupportedFormats returns a list of supported formats
 	GetSupportedFormats() []string
+
+	// GetServerUptime returns the server start time, uptime and a monotonic counter
+	GetServerUptime(input UptimeInput) (UptimeResult, error)
+
+	// CompareClock measures the skew between a client's clock and the server's
+	CompareClock(input CompareClockInput) (CompareClockResult, error)
+
+	// TOTPWindow returns the TOTP time-step counter and window for an instant
+	TOTPWindow(input TOTPWindowInput) (TOTPWindowResult, error)
+
+	// EvaluateExpiry reports the time remaining until a certificate or token expires
+	EvaluateExpiry(input ExpiryInput) (ExpiryResult, error)
+
+	// AnalyzeTimestamps computes interval and rate statistics for a list of timestamps
+	AnalyzeTimestamps(input AnalyzeTimestampsInput) (AnalyzeTimestampsResult, error)
+
+	// BucketTimestamps groups timestamps into fixed windows aligned in a timezone
+	BucketTimestamps(input BucketTimestampsInput) (BucketTimestampsResult, error)
+
+	// AlignPeriod returns the calendar period containing an instant
+	AlignPeriod(input AlignPeriodInput) (AlignPeriodResult, error)
+
+	// CheckDeadline evaluates a deadline with a grace period against an instant
+	CheckDeadline(input CheckDeadlineInput) (CheckDeadlineResult, error)
+
+	// AnonymizeTime shifts, jitters and rounds timestamps while preserving their order
+	AnonymizeTime(input AnonymizeTimeInput) (AnonymizeTimeResult, error)
+
+	// DiffZoneRules compares a zone's rules between two dates or tzdata sources
+	DiffZoneRules(input ZoneRulesDiffInput) (ZoneRulesDiffResult, error)
+
+	// GetZoneAbbreviations lists the abbreviations a zone has used over time
+	GetZoneAbbreviations(input ZoneAbbreviationsInput) (ZoneAbbreviationsResult, error)
+
+	// ResolveAbbreviation ranks the zones an ambiguous abbreviation may mean from context hints
+	ResolveAbbreviation(input ResolveAbbreviationInput) (ResolveAbbreviationResult, error)
+
+	// TranslateDate rewrites numeric dates from one locale's convention to another's
+	TranslateDate(input TranslateDateInput) (TranslateDateResult, error)
+
+	// WasDST reports tzdata's DST flag at an instant in a zone, with the transitions around it
+	WasDST(input WasDSTInput) (WasDSTResult, error)
+
+	// CreateICS serializes an event as an iCalendar document
+	CreateICS(input CreateICSInput) (CreateICSResult, error)
+
+	// ValidateWebhookTimestamp checks a webhook timestamp header against a replay window
+	ValidateWebhookTimestamp(input ValidateWebhookTimestampInput) (ValidateWebhookTimestampResult, error)
+
+	// HTTPDate renders an instant as an HTTP Date header value
+	HTTPDate(input HTTPDateInput) (HTTPDateResult, error)
+
+	// RetryAfter interprets a Retry-After header as a retry time and wait
+	RetryAfter(input RetryAfterInput) (RetryAfterResult, error)
+
+	// AuditCrontab reviews a crontab's schedules for never-firing, overlapping and DST-affected entries
+	AuditCrontab(input AuditCrontabInput) (AuditCrontabResult, error)
+
+	// CheckCronJob explains when a Kubernetes CronJob fires in its effective timezone
+	CheckCronJob(input CheckCronJobInput) (CheckCronJobResult, error)
+
+	// ParseMaintenanceWindow resolves a cloud provider maintenance window into next occurrences
+	ParseMaintenanceWindow(input MaintenanceWindowInput) (MaintenanceWindowResult, error)
+
+	// ExplainLayout infers a layout from a sample time, or explains a layout's directives
+	ExplainLayout(input ExplainLayoutInput) (ExplainLayoutResult, error)
+
+	// VerifyFormat parses a sample, formats it again and reports the information lost
+	VerifyFormat(input VerifyFormatInput) (VerifyFormatResult, error)
+
+	// ParseTable reads a column of a CSV or TSV snippet as timestamps, with per-row errors
+	ParseTable(input ParseTableInput) (ParseTableResult, error)
+
+	// DetectGaps finds missing and duplicated points of a time series with an expected interval
+	DetectGaps(input DetectGapsInput) (DetectGapsResult, error)
+
+	// ToSQLLiteral writes an instant as a typed, quoted literal for a SQL dialect
+	ToSQLLiteral(input ToSQLLiteralInput) (ToSQLLiteralResult, error)
+
+	// DecodeSnowflake extracts the creation time embedded in a snowflake ID
+	DecodeSnowflake(input DecodeSnowflakeInput) (DecodeSnowflakeResult, error)
+
+	// DecodeIDTimestamp extracts the creation time embedded in a ULID, UUIDv7, ObjectId or KSUID
+	DecodeIDTimestamp(input DecodeIDTimestampInput) (DecodeIDTimestampResult, error)
+
+	// AddTime adds a duration to an instant with calendar or absolute semantics
+	AddTime(input AddTimeInput) (AddTimeResult, error)
+
+	// NextBillingDate lists the next charge dates of a subscription from its billing anchor
+	NextBillingDate(input NextBillingDateInput) (NextBillingDateResult, error)
+
+	// Prorate splits a billing period and its amount at a change with a day-count convention
+	Prorate(input ProrateInput) (ProrateResult, error)
+
+	// Timesheet rounds punch times and splits worked time into regular time and overtime
+	Timesheet(input TimesheetInput) (TimesheetResult, error)
+
+	// EvaluateShiftPremium splits a worked interval into premium windows and regular time
+	EvaluateShiftPremium(input EvaluateShiftPremiumInput) (EvaluateShiftPremiumResult, error)
 }
 
 // timeService implements the TimeService interface
@@ -39,17 +138,60 @@ type timeService struct {
 	defaultTimezone  string
 	defaultFormat    string
 	supportedFormats []string
-	logger           *zap.Logger
+	logger           Logger
+	clock            Clock
+	startedAt        time.Time
+
+	// Parsing policies
+	validRange        *ValidRange
+	twoDigitYearPivot int
+	gregorianCutover  time.Time
+
+	// Transition lookups
+	dstLookaheadDays int
+
+	// Offset display
+	roundOffsets bool
+
+	// Formatter migration
+	shadow *formatShadow
+
+	// Memory budget of bulk operations, in bytes
+	bulkBudget int64
+
+	// Caps instants rendered in formats the envelope can't read back; nil
+	// is uncapped
+	precisionCap func(time.Time) time.Time
+
+	// Timezone caching
+	zones            zoneCache
+	preloadTimezones []string
+	tzdataSources    map[string]string
 }
 
 // NewTimeService creates a new time service instance
-func NewTimeService(defaultTimezone, defaultFormat string, supportedFormats []string, logger *zap.Logger) TimeService {
-	return &timeService{
+func NewTimeService(defaultTimezone, defaultFormat string, supportedFormats []string, logger Logger, opts ...Option) TimeService {
+	s := &timeService{
 		defaultTimezone:  defaultTimezone,
 		defaultFormat:    defaultFormat,
 		supportedFormats: supportedFormats,
 		logger:           logger,
+		clock:            systemClock{},
+
+		twoDigitYearPivot: goTwoDigitYearPivot,
+		gregorianCutover:  DefaultGregorianCutover,
+		dstLookaheadDays:  DefaultDSTLookaheadDays,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	// Uptime is read from the same clock, so a frozen clock freezes it too
+	s.startedAt = s.clock.Now()
+	s.preloadZones()
+
+	return s
 }
 
 // GetCurrentTime returns the current time with result information
@@ -69,17 +211,75 @@ func (s *timeService) GetCurrentTime(input GetTimeInput) (GetTimeResult, error)
 		return GetTimeResult{}, err
 	}
 
+	// Truncate before formatting so no output carries more precision than requested
+	precision := Precision(input.Precision)
+	if precision != "" {
+		if precision.Duration() == 0 {
+			return GetTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid precision %s (must be one of: second, milli, micro, nano)", precision)
+		}
+		currentTime = currentTime.Truncate(precision.Duration())
+	}
+	if err := validateDigitGrouping(input.DigitGrouping); err != nil {
+		return GetTimeResult{}, err
+	}
+
 	formatted, err := s.formatTimeInternal(currentTime, format)
 	if err != nil {
 		return GetTimeResult{}, err
 	}
 
-	return GetTimeResult{
-		FormattedTime: formatted,
-		Timezone:      timezone,
-		Format:        format,
-		UnixTimestamp: currentTime.Unix(),
-	}, nil
+	// Render every requested format from the same instant so the values
+	// can't straddle a second boundary
+	var formattedTimes map[string]string
+	if len(input.Formats) > 0 {
+		formattedTimes = make(map[string]string, len(input.Formats))
+		for _, f := range input.Formats {
+			formattedTimes[f], err = s.formatTimeInternal(currentTime, f)
+			if err != nil {
+				return GetTimeResult{}, err
+			}
+		}
+	}
+
+	result := GetTimeResult{
+		FormattedTime:  formatted,
+		Timezone:       timezone,
+		Format:         format,
+		UnixTimestamp:  currentTime.Unix(),
+		FormattedTimes: formattedTimes,
+		Precision:      string(precision),
+	}
+	if input.EpochAsNumber {
+		if n, ok := epochNumber(formatted, format); ok {
+			result.FormattedEpoch = &n
+		}
+		for f, text := range formattedTimes {
+			if n, ok := epochNumber(text, f); ok {
+				if result.FormattedEpochs == nil {
+					result.FormattedEpochs = make(map[string]int64)
+				}
+				result.FormattedEpochs[f] = n
+			}
+		}
+	}
+
+	// Only expose epoch fields up to the requested precision
+	if d := precision.Duration(); d != 0 {
+		if d <= time.Millisecond {
+			ms := currentTime.UnixMilli()
+			result.UnixTimestampMs = &ms
+		}
+		if d <= time.Microsecond {
+			us := currentTime.UnixMicro()
+			result.UnixTimestampUs = &us
+		}
+		if d <= time.Nanosecond {
+			ns := currentTime.UnixNano()
+			result.UnixTimestampNs = &ns
+		}
+	}
+
+	return result, nil
 }
 
 // getCurrentTimeInternal returns the current time in the specified timezone (internal method)
@@ -89,21 +289,21 @@ func (s *timeService) getCurrentTimeInternal(timezone string) (time.Time, error)
 	}
 
 	s.logger.Debug("Getting current time",
-		zap.String("timezone", timezone),
-		zap.String("default_timezone", s.defaultTimezone))
+		slog.String("timezone", timezone),
+		slog.String("default_timezone", s.defaultTimezone))
 
-	loc, err := time.LoadLocation(timezone)
+	loc, err := s.loadLocation(timezone)
 	if err != nil {
 		s.logger.Error("Failed to load timezone location",
-			zap.String("timezone", timezone),
-			zap.Error(err))
-		return time.Time{}, fmt.Errorf("invalid timezone %s: %w", timezone, err)
+			slog.String("timezone", timezone),
+			slog.Any("error", err))
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
 	}
 
-	currentTime := time.Now().In(loc)
+	currentTime := s.clock.Now().In(loc)
 	s.logger.Debug("Successfully retrieved current time",
-		zap.String("timezone", timezone),
-		zap.Time("time", currentTime))
+		slog.String("timezone", timezone),
+		slog.Time("time", currentTime))
 
 	return currentTime, nil
 }
@@ -117,53 +317,57 @@ func (s *timeService) FormatTime(input FormatTimeInput) (FormatTimeResult, error
 		timezone = s.defaultTimezone
 	}
 
-	// Parse the timestamp
-	var t time.Time
-	var err error
-
-	switch v := input.Timestamp.(type) {
-	case string:
-		// Try to parse as Unix timestamp first, then as RFC3339
-		if unixTime, parseErr := strconv.ParseInt(v, 10, 64); parseErr == nil {
-			t = time.Unix(unixTime, 0)
-		} else {
-			t, err = time.Parse(time.RFC3339, v)
-			if err != nil {
-				return FormatTimeResult{}, fmt.Errorf("failed to parse timestamp string: %w", err)
-			}
-		}
-	case int:
-		t = time.Unix(int64(v), 0)
-	case int64:
-		t = time.Unix(v, 0)
-	case float64:
-		t = time.Unix(int64(v), 0)
-	case time.Time:
-		t = v
-	default:
-		return FormatTimeResult{}, fmt.Errorf("unsupported timestamp type: %T", input.Timestamp)
+	// Resolve the timestamp
+	t, err := input.Timestamp.Resolve()
+	if err != nil {
+		return FormatTimeResult{}, err
+	}
+	if err := validateDigitGrouping(input.DigitGrouping); err != nil {
+		return FormatTimeResult{}, err
 	}
 
 	// Convert to target timezone
 	if timezone != "" {
-		loc, err := time.LoadLocation(timezone)
+		loc, err := s.loadLocation(timezone)
 		if err != nil {
-			return FormatTimeResult{}, fmt.Errorf("invalid timezone %s: %w", timezone, err)
+			return FormatTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
 		}
 		t = t.In(loc)
 	}
 
+	warning, err := s.checkRange(t)
+	if err != nil {
+		return FormatTimeResult{}, err
+	}
+
 	formatted, err := s.formatTimeInternal(t, format)
 	if err != nil {
 		return FormatTimeResult{}, err
 	}
 
-	return FormatTimeResult{
+	result := FormatTimeResult{
 		FormattedTime: formatted,
 		Timezone:      t.Location().String(),
 		Format:        format,
 		UnixTimestamp: t.Unix(),
-	}, nil
+		IsPreEpoch:    t.Before(unixEpoch),
+	}
+	if input.EpochAsNumber {
+		if n, ok := epochNumber(formatted, format); ok {
+			result.FormattedEpoch = &n
+		}
+	}
+	if input.SpellOut || input.RomanYear {
+		if result.WrittenDate, err = writeDate(t, input.Locale, input.SpellOut, input.RomanYear); err != nil {
+			return FormatTimeResult{}, err
+		}
+	}
+	if warning != "" {
+		result.OutOfRange = true
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	return result, nil
 }
 
 // formatTimeInternal formats a time value using the specified format (internal method)
@@ -173,42 +377,60 @@ func (s *timeService) formatTimeInternal(t time.Time, format string) (string, er
 	}
 
 	s.logger.Debug("Formatting time",
-		zap.Time("time", t),
-		zap.String("format", format))
+		slog.Time("time", t),
+		slog.String("format", format))
 
-	if !s.IsFormatSupported(format) {
-		return "", fmt.Errorf("unsupported format: %s (supported: %v)", format, s.supportedFormats)
+	// Layout in the supported formats allows any custom layout
+	if !s.IsFormatSupported(format) && (IsValidFormat(format) || !s.IsFormatSupported(string(FormatLayout))) {
+		return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat, "unsupported format: %s (supported: %v)", format, s.supportedFormats)
+	}
+	t = s.capFormat(t, format)
+	result, err := renderFormat(t, format)
+	s.compareShadow(t, format, result, err)
+	if err != nil {
+		return "", err
 	}
 
-	var result string
-	var err error
+	s.logger.Debug("Successfully formatted time",
+		slog.String("format", format),
+		slog.String("result", result))
 
-	switch FormatType(format) {
+	return result, err
+}
+
+// renderFormat formats a time with a named format, strftime pattern or Go
+// layout
+func renderFormat(t time.Time, format string) (string, error) {
+	layout, err := layoutFor(format)
+	if err != nil {
+		return "", err
+	}
+	return renderLayout(t, layout)
+}
+
+// renderLayout formats a time with a named format or a Go layout, as
+// returned by layoutFor
+func renderLayout(t time.Time, layout string) (string, error) {
+	switch FormatType(layout) {
 	case FormatRFC3339:
-		result = t.Format(time.RFC3339)
+		return t.Format(time.RFC3339), nil
 	case FormatRFC3339Nano:
-		result = t.Format(time.RFC3339Nano)
-	case FormatUnix:
-		result = strconv.FormatInt(t.Unix(), 10)
-	case FormatUnixMilli:
-		result = strconv.FormatInt(t.UnixMilli(), 10)
-	case FormatUnixMicro:
-		result = strconv.FormatInt(t.UnixMicro(), 10)
-	case FormatUnixNano:
-		result = strconv.FormatInt(t.UnixNano(), 10)
-	case FormatLayout:
-		// For layout format, we expect the format to be a Go time layout
-		result = t.Format(format)
+		return t.Format(time.RFC3339Nano), nil
+	case FormatUnix, FormatUnixMilli, FormatUnixMicro, FormatUnixNano:
+		return formatEpoch(t, formatEpochUnits[FormatType(layout)])
+	case FormatEpochDay, FormatOrdinal, FormatRataDie:
+		return formatDayNumber(t, FormatType(layout))
+	case FormatMySQLDateTime, FormatPostgresTimestampTZ, FormatJulianDay:
+		return formatSQL(t, FormatType(layout)), nil
+	case FormatGPSSeconds, FormatGPSWeek:
+		return formatGPS(t, FormatType(layout))
+	case FormatTLEEpoch:
+		return formatTLEEpoch(t)
+	case FormatSwatchBeats, FormatDecimalTime, FormatDayKiloseconds:
+		return formatClock(t, FormatType(layout)), nil
 	default:
-		// Try as a Go time layout
-		result = t.Format(format)
+		return t.Format(layout), nil
 	}
-
-	s.logger.Debug("Successfully formatted time",
-		zap.String("format", format),
-		zap.String("result", result))
-
-	return result, err
 }
 
 // ParseTime parses a time string and returns result information
@@ -216,98 +438,194 @@ func (s *timeService) ParseTime(input ParseTimeInput) (ParseTimeResult, error) {
 	timeStr := input.TimeString
 	format := input.Format
 	timezone := input.Timezone
+	mode := TimezoneHandling(input.TimezoneHandling)
 
 	if format == "" {
 		format = s.defaultFormat
 	}
-
-	parsedTime, err := s.parseTimeInternal(timeStr, format)
+	if mode == "" {
+		mode = TimezoneHandlingAssume
+	}
+	if !IsValidTimezoneHandling(string(mode)) {
+		return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid timezone_handling %s (must be one of: assume, convert, require_explicit)", mode)
+	}
+	// Custom layouts and strftime patterns are checked before any parsing
+	layout, err := layoutFor(format)
 	if err != nil {
 		return ParseTimeResult{}, err
 	}
 
-	// Apply timezone if specified
+	loc := time.UTC
 	if timezone != "" {
-		loc, err := time.LoadLocation(timezone)
+		loc, err = s.loadLocation(timezone)
 		if err != nil {
-			return ParseTimeResult{}, fmt.Errorf("invalid timezone %s: %w", timezone, err)
+			return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
 		}
-		// If the parsed time has no timezone info, assume it's in the specified timezone
-		if parsedTime.Location() == time.UTC {
-			parsedTime = time.Date(parsedTime.Year(), parsedTime.Month(), parsedTime.Day(),
-				parsedTime.Hour(), parsedTime.Minute(), parsedTime.Second(), parsedTime.Nanosecond(), loc)
-		} else {
-			parsedTime = parsedTime.In(loc)
+	}
+
+	explicit := hasExplicitOffset(timeStr, layout)
+	if mode == TimezoneHandlingRequireExplicit && !explicit {
+		return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse time string %s: no explicit UTC offset (timezone_handling=require_explicit)", timeStr)
+	}
+
+	// Strings without an offset are wall-clock times in the target timezone
+	// when assuming, and UTC otherwise. Strings with an offset, including
+	// "Z", are always absolute instants.
+	parseLoc := time.UTC
+	if mode == TimezoneHandlingAssume {
+		parseLoc = loc
+	}
+
+	parsedTime, err := s.parseTimeInternal(timeStr, layout, parseLoc)
+	if err != nil {
+		return ParseTimeResult{}, err
+	}
+
+	var twoDigitYear *TwoDigitYearInfo
+	if hasTwoDigitYear(layout) {
+		pivot := s.twoDigitYearPivot
+		if input.TwoDigitYearPivot != nil {
+			pivot = *input.TwoDigitYearPivot
 		}
+		if err := validateTwoDigitYearPivot(pivot); err != nil {
+			return ParseTimeResult{}, err
+		}
+
+		var info TwoDigitYearInfo
+		parsedTime, info, err = applyTwoDigitYearPivot(parsedTime, pivot)
+		if err != nil {
+			return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse time string %s: %w", timeStr, err)
+		}
+		twoDigitYear = &info
+	}
+
+	calendar := Calendar(input.Calendar)
+	if calendar == "" {
+		calendar = CalendarGregorian
+	}
+	if !IsValidCalendar(string(calendar)) {
+		return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid calendar %s (must be one of: gregorian, julian, auto)", calendar)
+	}
+
+	var calendarInfo *CalendarInfo
+	parsedTime, calendarInfo, err = s.applyCalendar(parsedTime, calendar)
+	if err != nil {
+		return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse time string %s: %w", timeStr, err)
 	}
 
-	return ParseTimeResult{
-		UnixTimestamp: parsedTime.Unix(),
-		RFC3339:       parsedTime.Format(time.RFC3339),
-		Timezone:      parsedTime.Location().String(),
-		IsDST:         s.isDST(parsedTime, parsedTime.Location()),
-	}, nil
+	if timezone != "" {
+		parsedTime = parsedTime.In(loc)
+	}
+
+	warning, err := s.checkRange(parsedTime)
+	if err != nil {
+		return ParseTimeResult{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse time string %s: %w", timeStr, err)
+	}
+
+	result := ParseTimeResult{
+		UnixTimestamp:    parsedTime.Unix(),
+		IsPreEpoch:       parsedTime.Before(unixEpoch),
+		RFC3339:          parsedTime.Format(time.RFC3339),
+		Timezone:         parsedTime.Location().String(),
+		IsDST:            s.isDST(parsedTime, parsedTime.Location()),
+		TimezoneHandling: string(mode),
+		ExplicitOffset:   explicit,
+		Components:       NewTimeComponents(parsedTime),
+		TwoDigitYear:     twoDigitYear,
+		Calendar:         calendarInfo,
+	}
+	if warning != "" {
+		result.OutOfRange = true
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	return result, nil
+}
+
+// hasExplicitOffset reports whether a time string carries its own UTC offset.
+// Epoch formats are absolute by definition. For layouts, the string is parsed
+// in two different locations: only strings without an offset change instant.
+func hasExplicitOffset(timeStr, format string) bool {
+	switch FormatType(format) {
+	case FormatUnix, FormatUnixMilli, FormatUnixMicro, FormatUnixNano, FormatPostgresTimestampTZ, FormatJulianDay,
+		FormatGPSSeconds, FormatGPSWeek, FormatTLEEpoch, FormatSwatchBeats, FormatDayKiloseconds:
+		return true
+	case FormatEpochDay, FormatOrdinal, FormatRataDie, FormatMySQLDateTime, FormatDecimalTime:
+		return false
+	}
+
+	layout := format
+	switch FormatType(format) {
+	case FormatRFC3339:
+		layout = time.RFC3339
+	case FormatRFC3339Nano:
+		layout = time.RFC3339Nano
+	}
+
+	inUTC, err := time.ParseInLocation(layout, timeStr, time.UTC)
+	if err != nil {
+		return false
+	}
+	inProbe, err := time.ParseInLocation(layout, timeStr, time.FixedZone("probe", 3600))
+	if err != nil {
+		return false
+	}
+	return inUTC.Equal(inProbe)
 }
 
-// parseTimeInternal parses a time string using the specified format (internal method)
-func (s *timeService) parseTimeInternal(timeStr, format string) (time.Time, error) {
+// parseTimeInternal parses a time string using the specified format (internal method).
+// Strings without an offset are interpreted in loc.
+func (s *timeService) parseTimeInternal(timeStr, format string, loc *time.Location) (time.Time, error) {
 	if format == "" {
 		format = s.defaultFormat
 	}
 
 	s.logger.Debug("Parsing time string",
-		zap.String("time_string", timeStr),
-		zap.String("format", format))
+		slog.String("time_string", timeStr),
+		slog.String("format", format))
 
 	var parsedTime time.Time
 	var err error
 
 	switch FormatType(format) {
 	case FormatRFC3339:
-		parsedTime, err = time.Parse(time.RFC3339, timeStr)
+		parsedTime, err = time.ParseInLocation(time.RFC3339, timeStr, loc)
 	case FormatRFC3339Nano:
-		parsedTime, err = time.Parse(time.RFC3339Nano, timeStr)
-	case FormatUnix:
-		var unixTime int64
-		unixTime, err = strconv.ParseInt(timeStr, 10, 64)
-		if err == nil {
-			parsedTime = time.Unix(unixTime, 0)
-		}
-	case FormatUnixMilli:
-		var milliTime int64
-		milliTime, err = strconv.ParseInt(timeStr, 10, 64)
-		if err == nil {
-			parsedTime = time.UnixMilli(milliTime)
-		}
-	case FormatUnixMicro:
-		var microTime int64
-		microTime, err = strconv.ParseInt(timeStr, 10, 64)
+		parsedTime, err = time.ParseInLocation(time.RFC3339Nano, timeStr, loc)
+	case FormatUnix, FormatUnixMilli, FormatUnixMicro, FormatUnixNano:
+		var epoch int64
+		unit := formatEpochUnits[FormatType(format)]
+		epoch, err = parseEpoch(timeStr, unit)
 		if err == nil {
-			parsedTime = time.UnixMicro(microTime)
-		}
-	case FormatUnixNano:
-		var nanoTime int64
-		nanoTime, err = strconv.ParseInt(timeStr, 10, 64)
-		if err == nil {
-			parsedTime = time.Unix(0, nanoTime)
+			parsedTime = epochTime(epoch, unit).In(loc)
 		}
+	case FormatEpochDay, FormatOrdinal, FormatRataDie:
+		parsedTime, err = parseDayNumber(timeStr, FormatType(format), loc)
+	case FormatMySQLDateTime, FormatPostgresTimestampTZ, FormatJulianDay:
+		parsedTime, err = parseSQL(timeStr, FormatType(format), loc)
+	case FormatGPSSeconds, FormatGPSWeek:
+		parsedTime, err = parseGPS(timeStr, FormatType(format))
+	case FormatTLEEpoch:
+		parsedTime, err = parseTLEEpoch(timeStr)
+	case FormatSwatchBeats, FormatDecimalTime, FormatDayKiloseconds:
+		parsedTime, err = parseClock(timeStr, FormatType(format), loc)
 	default:
 		// Try as Go time layout
-		parsedTime, err = time.Parse(format, timeStr)
+		parsedTime, err = time.ParseInLocation(format, timeStr, loc)
 	}
 
 	if err != nil {
 		s.logger.Error("Failed to parse time string",
-			zap.String("time_string", timeStr),
-			zap.String("format", format),
-			zap.Error(err))
-		return time.Time{}, fmt.Errorf("failed to parse time string %s with format %s: %w", timeStr, format, err)
+			slog.String("time_string", timeStr),
+			slog.String("format", format),
+			slog.Any("error", err))
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse time string %s with format %s: %w", timeStr, format, err)
 	}
 
 	s.logger.Debug("Successfully parsed time string",
-		zap.String("time_string", timeStr),
-		zap.String("format", format),
-		zap.Time("parsed_time", parsedTime))
+		slog.String("time_string", timeStr),
+		slog.String("format", format),
+		slog.Time("parsed_time", parsedTime))
 
 	return parsedTime, nil
 }
@@ -320,12 +638,17 @@ func (s *timeService) GetTimezoneInfo(input TimezoneInfoInput) (TimezoneInfo, er
 	}
 
 	// Use provided reference time or current time
-	refTime := time.Now()
+	refTime := s.clock.Now()
 	if !input.ReferenceTime.IsZero() {
 		refTime = input.ReferenceTime
 	}
 
-	info, err := s.getTimezoneInfoInternal(timezone, &refTime)
+	days, err := s.lookaheadDays(input.LookaheadDays)
+	if err != nil {
+		return TimezoneInfo{}, err
+	}
+
+	info, err := s.getTimezoneInfoInternal(timezone, &refTime, days)
 	if err != nil {
 		return TimezoneInfo{}, err
 	}
@@ -335,24 +658,24 @@ func (s *timeService) GetTimezoneInfo(input TimezoneInfoInput) (TimezoneInfo, er
 }
 
 // getTimezoneInfoInternal returns information about a timezone (internal method)
-func (s *timeService) getTimezoneInfoInternal(timezone string, referenceTime *time.Time) (*TimezoneInfo, error) {
+func (s *timeService) getTimezoneInfoInternal(timezone string, referenceTime *time.Time, lookaheadDays int) (*TimezoneInfo, error) {
 	if timezone == "" {
 		timezone = s.defaultTimezone
 	}
 
 	s.logger.Debug("Getting timezone info",
-		zap.String("timezone", timezone))
+		slog.String("timezone", timezone))
 
-	loc, err := time.LoadLocation(timezone)
+	loc, err := s.loadLocation(timezone)
 	if err != nil {
 		s.logger.Error("Failed to load timezone location for info",
-			zap.String("timezone", timezone),
-			zap.Error(err))
-		return nil, fmt.Errorf("invalid timezone %s: %w", timezone, err)
+			slog.String("timezone", timezone),
+			slog.Any("error", err))
+		return nil, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
 	}
 
 	// Use provided reference time or current time
-	refTime := time.Now()
+	refTime := s.clock.Now()
 	if referenceTime != nil {
 		refTime = *referenceTime
 	}
@@ -367,22 +690,29 @@ func (s *timeService) getTimezoneInfoInternal(timezone string, referenceTime *ti
 	isDST := s.isDST(timeInZone, loc)
 
 	// Calculate DST transition info
-	dstTransition := s.getNextDSTTransition(timeInZone, loc)
+	dstTransition := s.getNextDSTTransition(timeInZone, loc, lookaheadDays)
+	transitionStatus := TransitionStatusScheduled
+	if dstTransition == nil {
+		transitionStatus = TransitionStatusNoneWithinHorizon
+	}
 
 	info := &TimezoneInfo{
-		Name:          timezone,
-		Abbreviation:  zoneName,
-		Offset:        formatOffset(offset),
-		OffsetSeconds: offset,
-		IsDST:         isDST,
-		DSTTransition: dstTransition,
+		Name:             timezone,
+		Abbreviation:     zoneName,
+		Offset:           formatOffset(offset, s.roundOffsets),
+		OffsetSeconds:    offset,
+		IsDST:            isDST,
+		HasDST:           hasDST(timeInZone, loc, lookaheadDays),
+		NextTransition:   dstTransition,
+		TransitionStatus: transitionStatus,
+		LookaheadDays:    lookaheadDays,
 	}
 
 	s.logger.Debug("Successfully retrieved timezone info",
-		zap.String("timezone", timezone),
-		zap.String("abbreviation", zoneName),
-		zap.Int("offset_seconds", offset),
-		zap.Bool("is_dst", isDST))
+		slog.String("timezone", timezone),
+		slog.String("abbreviation", zoneName),
+		slog.Int("offset_seconds", offset),
+		slog.Bool("is_dst", isDST))
 
 	return info, nil
 }
@@ -390,26 +720,26 @@ func (s *timeService) getTimezoneInfoInternal(timezone string, referenceTime *ti
 // ConvertTimezone converts a time from one timezone to another
 func (s *timeService) ConvertTimezone(t time.Time, fromTZ, toTZ string) (time.Time, error) {
 	s.logger.Debug("Converting timezone",
-		zap.Time("time", t),
-		zap.String("from_timezone", fromTZ),
-		zap.String("to_timezone", toTZ))
+		slog.Time("time", t),
+		slog.String("from_timezone", fromTZ),
+		slog.String("to_timezone", toTZ))
 
-	toLoc, err := time.LoadLocation(toTZ)
+	toLoc, err := s.loadLocation(toTZ)
 	if err != nil {
 		s.logger.Error("Failed to load destination timezone",
-			zap.String("to_timezone", toTZ),
-			zap.Error(err))
-		return time.Time{}, fmt.Errorf("invalid destination timezone %s: %w", toTZ, err)
+			slog.String("to_timezone", toTZ),
+			slog.Any("error", err))
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid destination timezone %s: %w", toTZ, err)
 	}
 
 	// If the time doesn't have location info and fromTZ is specified, set it
 	if fromTZ != "" && t.Location() == time.UTC {
-		fromLoc, err := time.LoadLocation(fromTZ)
+		fromLoc, err := s.loadLocation(fromTZ)
 		if err != nil {
 			s.logger.Error("Failed to load source timezone",
-				zap.String("from_timezone", fromTZ),
-				zap.Error(err))
-			return time.Time{}, fmt.Errorf("invalid source timezone %s: %w", fromTZ, err)
+				slog.String("from_timezone", fromTZ),
+				slog.Any("error", err))
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid source timezone %s: %w", fromTZ, err)
 		}
 		// Interpret the time as being in the source timezone
 		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), fromLoc)
@@ -418,10 +748,10 @@ func (s *timeService) ConvertTimezone(t time.Time, fromTZ, toTZ string) (time.Ti
 	convertedTime := t.In(toLoc)
 
 	s.logger.Debug("Successfully converted timezone",
-		zap.String("from_timezone", fromTZ),
-		zap.String("to_timezone", toTZ),
-		zap.Time("original_time", t),
-		zap.Time("converted_time", convertedTime))
+		slog.String("from_timezone", fromTZ),
+		slog.String("to_timezone", toTZ),
+		slog.Time("original_time", t),
+		slog.Time("converted_time", convertedTime))
 
 	return convertedTime, nil
 }
@@ -459,41 +789,51 @@ func (s *timeService) isDST(t time.Time, loc *time.Location) bool {
 	return offset1 > offset2
 }
 
-// getNextDSTTransition finds the next DST transition
-func (s *timeService) getNextDSTTransition(t time.Time, loc *time.Location) *DSTTransitionInfo {
+// getNextDSTTransition finds the next DST transition within days of t
+func (s *timeService) getNextDSTTransition(t time.Time, loc *time.Location, days int) *DSTTransitionInfo {
 	// This is a simplified implementation
 	// In a production system, you might want to use a more sophisticated approach
 	// or a library that has complete DST transition data
 
 	current := t
-	_, currentOffset := current.Zone()
+	currentState := zoneStateAt(current, loc)
+
+	// Preloaded zones skip straight to the day before their next transition
+	start := 0
+	if index := s.zones.index(loc); index != nil {
+		if steps, ok := index.stepsBeforeTransition(t, days); ok {
+			start = steps
+			current = t.AddDate(0, 0, steps)
+		}
+	}
 
-	// Look forward up to 1 year to find a transition
-	for i := 0; i < 365; i++ {
+	// Look forward up to the horizon to find a transition
+	for i := start; i < days; i++ {
 		next := current.AddDate(0, 0, 1)
 		nextInZone := next.In(loc)
-		_, nextOffset := nextInZone.Zone()
+		nextState := zoneStateAt(nextInZone, loc)
 
-		if nextOffset != currentOffset {
-			transitionType := "enter_dst"
-			if nextOffset < currentOffset {
-				transitionType = "exit_dst"
-			}
+		if nextState != currentState {
+			kind, transitionType := classifyTransition(currentState, nextState)
 
 			return &DSTTransitionInfo{
 				NextTransition: nextInZone,
 				TransitionType: transitionType,
-				OffsetChange:   nextOffset - currentOffset,
+				Kind:           kind,
+				OffsetChange:   nextState.offset - currentState.offset,
 			}
 		}
 		current = next
 	}
 
-	return nil // No transition found within a year
+	return nil // No transition found within the horizon
 }
 
-// formatOffset formats a timezone offset in seconds to a human-readable string
-func formatOffset(offsetSeconds int) string {
+// formatOffset formats a timezone offset in seconds as ±HH:MM. Offsets
+// with seconds, such as the local mean time of most zones before
+// standard time, are written ±HH:MM:SS, or rounded to the nearest minute
+// when roundSeconds is set
+func formatOffset(offsetSeconds int, roundSeconds bool) string {
 	if offsetSeconds == 0 {
 		return "+00:00"
 	}
@@ -503,9 +843,23 @@ func formatOffset(offsetSeconds int) string {
 		sign = "-"
 		offsetSeconds = -offsetSeconds
 	}
+	if roundSeconds {
+		offsetSeconds = (offsetSeconds + 30) / 60 * 60
+	}
 
 	hours := offsetSeconds / 3600
 	minutes := (offsetSeconds % 3600) / 60
+	if seconds := offsetSeconds % 60; seconds != 0 {
+		return fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, seconds)
+	}
 
 	return fmt.Sprintf("%s%02d:%02d", sign, hours, minutes)
 }
+
+// WithRoundedOffsets writes offsets with seconds rounded to the nearest
+// minute, for clients that only read ±HH:MM
+func WithRoundedOffsets(round bool) Option {
+	return func(s *timeService) {
+		s.roundOffsets = round
+	}
+}
@@ -7,10 +7,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/logger"
 )
 
+// newTestLogger returns a service logger that writes to the test log
+func newTestLogger(t testing.TB) Logger {
+	return logger.Slog(zaptest.NewLogger(t))
+}
+
 func TestNewTimeService(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	supportedFormats := []string{"RFC3339", "Unix"}
 
 	service := NewTimeService("UTC", "RFC3339", supportedFormats, logger)
@@ -20,7 +27,7 @@ func TestNewTimeService(t *testing.T) {
 }
 
 func TestTimeService_GetCurrentTime(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
 
 	tests := []struct {
@@ -75,7 +82,7 @@ func TestTimeService_GetCurrentTime(t *testing.T) {
 }
 
 func TestTimeService_FormatTime(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	supportedFormats := []string{"RFC3339", "Unix", "UnixMilli", "2006-01-02 15:04:05"}
 	service := NewTimeService("UTC", "RFC3339", supportedFormats, logger)
 
@@ -89,37 +96,37 @@ func TestTimeService_FormatTime(t *testing.T) {
 	}{
 		{
 			name:     "default format (empty string)",
-			input:    FormatTimeInput{Timestamp: testTime, Format: "", Timezone: "UTC"},
+			input:    FormatTimeInput{Timestamp: TimestampFromTime(testTime), Format: "", Timezone: "UTC"},
 			expected: "2023-12-25T15:30:45Z",
 			wantErr:  false,
 		},
 		{
 			name:     "RFC3339 format",
-			input:    FormatTimeInput{Timestamp: testTime, Format: "RFC3339", Timezone: "UTC"},
+			input:    FormatTimeInput{Timestamp: TimestampFromTime(testTime), Format: "RFC3339", Timezone: "UTC"},
 			expected: "2023-12-25T15:30:45Z",
 			wantErr:  false,
 		},
 		{
 			name:     "Unix format",
-			input:    FormatTimeInput{Timestamp: testTime, Format: "Unix", Timezone: "UTC"},
+			input:    FormatTimeInput{Timestamp: TimestampFromTime(testTime), Format: "Unix", Timezone: "UTC"},
 			expected: "1703518245",
 			wantErr:  false,
 		},
 		{
 			name:     "UnixMilli format",
-			input:    FormatTimeInput{Timestamp: testTime, Format: "UnixMilli", Timezone: "UTC"},
+			input:    FormatTimeInput{Timestamp: TimestampFromTime(testTime), Format: "UnixMilli", Timezone: "UTC"},
 			expected: "1703518245123",
 			wantErr:  false,
 		},
 		{
 			name:     "custom layout",
-			input:    FormatTimeInput{Timestamp: testTime, Format: "2006-01-02 15:04:05", Timezone: "UTC"},
+			input:    FormatTimeInput{Timestamp: TimestampFromTime(testTime), Format: "2006-01-02 15:04:05", Timezone: "UTC"},
 			expected: "2023-12-25 15:30:45",
 			wantErr:  false,
 		},
 		{
 			name:    "unsupported format",
-			input:   FormatTimeInput{Timestamp: testTime, Format: "UnsupportedFormat", Timezone: "UTC"},
+			input:   FormatTimeInput{Timestamp: TimestampFromTime(testTime), Format: "UnsupportedFormat", Timezone: "UTC"},
 			wantErr: true,
 		},
 	}
@@ -140,7 +147,7 @@ func TestTimeService_FormatTime(t *testing.T) {
 }
 
 func TestTimeService_ParseTime(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	supportedFormats := []string{"RFC3339", "Unix", "UnixMilli"}
 	service := NewTimeService("UTC", "RFC3339", supportedFormats, logger)
 
@@ -204,7 +211,7 @@ func TestTimeService_ParseTime(t *testing.T) {
 }
 
 func TestTimeService_GetTimezoneInfo(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
 
 	tests := []struct {
@@ -268,7 +275,7 @@ func TestTimeService_GetTimezoneInfo(t *testing.T) {
 }
 
 func TestTimeService_ConvertTimezone(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
 
 	// Create a time in UTC
@@ -336,7 +343,7 @@ func TestTimeService_ConvertTimezone(t *testing.T) {
 }
 
 func TestTimeService_IsFormatSupported(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	supportedFormats := []string{"RFC3339", "Unix", "UnixMilli"}
 	service := NewTimeService("UTC", "RFC3339", supportedFormats, logger)
 
@@ -360,7 +367,7 @@ func TestTimeService_IsFormatSupported(t *testing.T) {
 }
 
 func TestTimeService_GetSupportedFormats(t *testing.T) {
-	logger := zaptest.NewLogger(t)
+	logger := newTestLogger(t)
 	supportedFormats := []string{"RFC3339", "Unix", "UnixMilli"}
 	service := NewTimeService("UTC", "RFC3339", supportedFormats, logger)
 
@@ -418,21 +425,237 @@ func TestGetFormatLayout(t *testing.T) {
 func Test_formatOffset(t *testing.T) {
 	tests := []struct {
 		offsetSeconds int
+		round         bool
 		expected      string
 	}{
-		{0, "+00:00"},
-		{3600, "+01:00"},
-		{-3600, "-01:00"},
-		{5400, "+01:30"},
-		{-5400, "-01:30"},
-		{43200, "+12:00"},
-		{-43200, "-12:00"},
+		{0, false, "+00:00"},
+		{3600, false, "+01:00"},
+		{-3600, false, "-01:00"},
+		{5400, false, "+01:30"},
+		{-5400, false, "-01:30"},
+		{43200, false, "+12:00"},
+		{-43200, false, "-12:00"},
+		// Asia/Kathmandu, Pacific/Chatham and Pacific/Marquesas
+		{20700, false, "+05:45"},
+		{45900, true, "+12:45"},
+		{-34200, false, "-09:30"},
+		// Local mean time of Europe/Amsterdam, America/New_York and Africa/Monrovia
+		{1172, false, "+00:19:32"},
+		{1172, true, "+00:20"},
+		{-17762, false, "-04:56:02"},
+		{-17762, true, "-04:56"},
+		{-2670, false, "-00:44:30"},
+		{-2670, true, "-00:45"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.expected, func(t *testing.T) {
-			result := formatOffset(tt.offsetSeconds)
+			result := formatOffset(tt.offsetSeconds, tt.round)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestTimeService_HistoricalOffsets(t *testing.T) {
+	lmt := time.Date(1880, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		timezone string
+		at       time.Time
+		round    bool
+		expected string
+	}{
+		{"Asia/Kathmandu", time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), false, "+05:45"},
+		{"Europe/Amsterdam", lmt, false, "+00:19:32"},
+		{"Europe/Amsterdam", lmt, true, "+00:20"},
+		{"America/New_York", lmt, false, "-04:56:02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone+"/"+tt.expected, func(t *testing.T) {
+			service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithRoundedOffsets(tt.round))
+			info, err := service.GetTimezoneInfo(TimezoneInfoInput{Timezone: tt.timezone, ReferenceTime: tt.at})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, info.Offset)
+		})
+	}
+}
+
+func TestTimeService_WithClock(t *testing.T) {
+	logger := newTestLogger(t)
+	frozen := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: frozen}))
+
+	first, err := service.GetCurrentTime(GetTimeInput{})
+	require.NoError(t, err)
+	second, err := service.GetCurrentTime(GetTimeInput{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2023-12-25T15:30:45Z", first.FormattedTime)
+	assert.Equal(t, first, second)
+}
+
+func TestTimeService_ParseTime_TimezoneHandling(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	tests := []struct {
+		name         string
+		input        ParseTimeInput
+		wantUnix     int64
+		wantRFC3339  string
+		wantMode     string
+		wantExplicit bool
+		wantErr      bool
+	}{
+		{
+			name:         "assume keeps explicit UTC instant",
+			input:        ParseTimeInput{TimeString: "2023-12-25T15:30:45Z", Timezone: "America/New_York"},
+			wantUnix:     1703518245,
+			wantRFC3339:  "2023-12-25T10:30:45-05:00",
+			wantMode:     "assume",
+			wantExplicit: true,
+		},
+		{
+			name:        "assume interprets wall clock in target zone",
+			input:       ParseTimeInput{TimeString: "2023-12-25 15:30:45", Format: "2006-01-02 15:04:05", Timezone: "America/New_York"},
+			wantUnix:    1703536245,
+			wantRFC3339: "2023-12-25T15:30:45-05:00",
+			wantMode:    "assume",
+		},
+		{
+			name:        "convert treats wall clock as UTC",
+			input:       ParseTimeInput{TimeString: "2023-12-25 15:30:45", Format: "2006-01-02 15:04:05", Timezone: "America/New_York", TimezoneHandling: "convert"},
+			wantUnix:    1703518245,
+			wantRFC3339: "2023-12-25T10:30:45-05:00",
+			wantMode:    "convert",
+		},
+		{
+			name:         "require_explicit accepts offset",
+			input:        ParseTimeInput{TimeString: "2023-12-25T15:30:45+01:00", Timezone: "UTC", TimezoneHandling: "require_explicit"},
+			wantUnix:     1703514645,
+			wantRFC3339:  "2023-12-25T14:30:45Z",
+			wantMode:     "require_explicit",
+			wantExplicit: true,
+		},
+		{
+			name:    "require_explicit rejects wall clock",
+			input:   ParseTimeInput{TimeString: "2023-12-25 15:30:45", Format: "2006-01-02 15:04:05", TimezoneHandling: "require_explicit"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			input:   ParseTimeInput{TimeString: "2023-12-25T15:30:45Z", TimezoneHandling: "guess"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ParseTime(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUnix, result.UnixTimestamp)
+			assert.Equal(t, tt.wantRFC3339, result.RFC3339)
+			assert.Equal(t, tt.wantMode, result.TimezoneHandling)
+			assert.Equal(t, tt.wantExplicit, result.ExplicitOffset)
+		})
+	}
+}
+
+func Test_hasExplicitOffset(t *testing.T) {
+	assert.True(t, hasExplicitOffset("2023-12-25T15:30:45Z", "RFC3339"))
+	assert.True(t, hasExplicitOffset("2023-12-25T15:30:45-03:00", "RFC3339"))
+	assert.True(t, hasExplicitOffset("1703518245", "Unix"))
+	assert.False(t, hasExplicitOffset("2023-12-25 15:30", "2006-01-02 15:04"))
+	assert.False(t, hasExplicitOffset("garbage", "RFC3339"))
+}
+
+func TestTimeService_ParseTime_Components(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339Nano", []string{"RFC3339Nano"}, logger)
+
+	result, err := service.ParseTime(ParseTimeInput{TimeString: "2021-01-03T23:59:58.5+05:45", Timezone: "Asia/Kathmandu"})
+	require.NoError(t, err)
+
+	assert.Equal(t, TimeComponents{
+		Year:             2021,
+		Month:            1,
+		Day:              3,
+		Hour:             23,
+		Minute:           59,
+		Second:           58,
+		Nanosecond:       500000000,
+		Weekday:          "Sunday",
+		DayOfYear:        3,
+		ISOYear:          2020,
+		ISOWeek:          53,
+		OffsetSeconds:    20700,
+		ZoneAbbreviation: "+0545",
+	}, result.Components)
+}
+
+func TestTimeService_GetCurrentTime_Formats(t *testing.T) {
+	logger := newTestLogger(t)
+	frozen := time.Date(2023, 12, 25, 15, 30, 45, 123000000, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix", "UnixMilli", "2006-01-02"}, logger,
+		WithClock(FixedClock{Time: frozen}))
+
+	result, err := service.GetCurrentTime(GetTimeInput{Formats: []string{"Unix", "UnixMilli", "2006-01-02"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2023-12-25T15:30:45Z", result.FormattedTime)
+	assert.Equal(t, map[string]string{
+		"Unix":       "1703518245",
+		"UnixMilli":  "1703518245123",
+		"2006-01-02": "2023-12-25",
+	}, result.FormattedTimes)
+
+	_, err = service.GetCurrentTime(GetTimeInput{Formats: []string{"Unix", "Bogus"}})
+	assert.Error(t, err)
+}
+
+func TestTimeService_GetCurrentTime_Precision(t *testing.T) {
+	logger := newTestLogger(t)
+	frozen := time.Date(2023, 12, 25, 15, 30, 45, 123456789, time.UTC)
+	service := NewTimeService("UTC", "RFC3339Nano", []string{"RFC3339Nano"}, logger, WithClock(FixedClock{Time: frozen}))
+
+	ptr := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		precision string
+		formatted string
+		ms, us    *int64
+		ns        *int64
+		wantErr   bool
+	}{
+		{precision: "", formatted: "2023-12-25T15:30:45.123456789Z"},
+		{precision: "second", formatted: "2023-12-25T15:30:45Z"},
+		{precision: "milli", formatted: "2023-12-25T15:30:45.123Z", ms: ptr(1703518245123)},
+		{precision: "micro", formatted: "2023-12-25T15:30:45.123456Z", ms: ptr(1703518245123), us: ptr(1703518245123456)},
+		{precision: "nano", formatted: "2023-12-25T15:30:45.123456789Z", ms: ptr(1703518245123), us: ptr(1703518245123456), ns: ptr(1703518245123456789)},
+		{precision: "pico", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.precision, func(t *testing.T) {
+			result, err := service.GetCurrentTime(GetTimeInput{Precision: tt.precision})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.formatted, result.FormattedTime)
+			assert.Equal(t, tt.precision, result.Precision)
+			assert.Equal(t, tt.ms, result.UnixTimestampMs)
+			assert.Equal(t, tt.us, result.UnixTimestampUs)
+			assert.Equal(t, tt.ns, result.UnixTimestampNs)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// FormatEngine is a formatter being migrated to. It runs in shadow next to
+// the current one, see WithFormatShadow
+type FormatEngine interface {
+	// Handles reports whether the engine renders format; others aren't compared
+	Handles(format string) bool
+	Format(t time.Time, format string) (string, error)
+}
+
+// Shadow engine names
+const (
+	// ShadowEngineStrftime renders strftime patterns directly from the time's
+	// fields instead of translating them into a Go layout
+	ShadowEngineStrftime = "strftime"
+)
+
+// ShadowEngines are the engines that can run in shadow, by name
+var ShadowEngines = map[string]FormatEngine{
+	ShadowEngineStrftime: strftimeEngine{},
+}
+
+// Shadow comparison outcomes
+const (
+	ShadowMatch    = "match"
+	ShadowMismatch = "mismatch"
+	// ShadowPrimaryError is a format only the shadow engine rendered
+	ShadowPrimaryError = "primary_error"
+	// ShadowCandidateError is a format only the current formatter rendered
+	ShadowCandidateError = "candidate_error"
+)
+
+// Format kinds, for reporting comparisons without the format itself
+const (
+	FormatKindNamed    = "named"
+	FormatKindStrftime = "strftime"
+	FormatKindLayout   = "layout"
+)
+
+// ShadowObserver is told the outcome of every shadow comparison
+type ShadowObserver func(engine, kind, outcome string)
+
+// formatShadow runs an engine next to formatTimeInternal
+type formatShadow struct {
+	name       string
+	engine     FormatEngine
+	sampleRate float64
+	observe    ShadowObserver
+}
+
+// WithFormatShadow renders a sample of formatted times a second time with
+// engine, comparing it with the current formatter. Divergences are logged
+// and passed to observe with every other outcome; responses always carry the
+// current formatter's output. A sample rate of 1 compares every format
+func WithFormatShadow(name string, engine FormatEngine, sampleRate float64, observe ShadowObserver) Option {
+	return func(s *timeService) {
+		s.shadow = &formatShadow{name: name, engine: engine, sampleRate: sampleRate, observe: observe}
+	}
+}
+
+// FormatKind classifies a format as a named format, a strftime pattern or
+// a Go layout
+func FormatKind(format string) string {
+	switch {
+	case IsValidFormat(format):
+		return FormatKindNamed
+	case strings.Contains(format, "%"):
+		return FormatKindStrftime
+	default:
+		return FormatKindLayout
+	}
+}
+
+// compareShadow renders t with the shadow engine and reports how it
+// compares with the current formatter's result. The engine can't change
+// the result: its errors and panics are only reported
+func (s *timeService) compareShadow(t time.Time, format, primary string, primaryErr error) {
+	shadow := s.shadow
+	if shadow == nil || !shadow.engine.Handles(format) {
+		return
+	}
+	if shadow.sampleRate < 1 && rand.Float64() >= shadow.sampleRate {
+		return
+	}
+
+	candidate, candidateErr := shadowFormat(shadow.engine, t, format)
+	outcome := ShadowMatch
+	switch {
+	case primaryErr != nil && candidateErr != nil:
+		// Both reject the format
+	case primaryErr != nil:
+		outcome = ShadowPrimaryError
+	case candidateErr != nil:
+		outcome = ShadowCandidateError
+	case primary != candidate:
+		outcome = ShadowMismatch
+	}
+
+	if outcome != ShadowMatch {
+		attrs := []any{
+			slog.String("engine", shadow.name),
+			slog.String("outcome", outcome),
+			slog.String("format", format),
+			slog.String("time", t.Format(time.RFC3339Nano)),
+			slog.String("timezone", t.Location().String()),
+			slog.String("primary", primary),
+			slog.String("candidate", candidate),
+		}
+		if primaryErr != nil {
+			attrs = append(attrs, slog.String("primary_error", primaryErr.Error()))
+		}
+		if candidateErr != nil {
+			attrs = append(attrs, slog.String("candidate_error", candidateErr.Error()))
+		}
+		s.logger.Warn("Shadow format engine diverged", attrs...)
+	}
+	if shadow.observe != nil {
+		shadow.observe(shadow.name, FormatKind(format), outcome)
+	}
+}
+
+// shadowFormat runs engine, turning a panic into an error
+func shadowFormat(engine FormatEngine, t time.Time, format string) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("shadow engine panicked: %v", r)
+		}
+	}()
+	return engine.Format(t, format)
+}
+
+// strftimeEngine renders strftime patterns directly, directive by
+// directive. Unlike the Go layout translation it has no literal text it
+// can't write
+type strftimeEngine struct{}
+
+func (strftimeEngine) Handles(format string) bool {
+	return FormatKind(format) == FormatKindStrftime
+}
+
+func (strftimeEngine) Format(t time.Time, pattern string) (string, error) {
+	if len(pattern) > maxLayoutLength {
+		return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat, "layout is %d characters long, at most %d allowed", len(pattern), maxLayoutLength)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			b.WriteByte(pattern[i])
+			continue
+		}
+
+		directive := ""
+		if i+1 < len(pattern) {
+			directive = pattern[i+1 : i+2]
+			if directive == ":" && i+2 < len(pattern) {
+				directive = pattern[i+1 : i+3]
+			}
+		}
+		text, ok := strftimeDirective(t, directive)
+		if !ok {
+			return "", timeerrors.Errorf(timeerrors.ErrInvalidFormat,
+				"invalid strftime pattern %q: unsupported directive %%%s. Supported directives: %s", pattern, directive, strftimeTokens)
+		}
+		b.WriteString(text)
+		i += len(directive)
+	}
+	return b.String(), nil
+}
+
+// strftimeDirective renders one strftime directive, without its %
+func strftimeDirective(t time.Time, directive string) (string, bool) {
+	switch directive {
+	case "Y":
+		return fmt.Sprintf("%04d", t.Year()), true
+	case "y":
+		return fmt.Sprintf("%02d", t.Year()%100), true
+	case "m":
+		return fmt.Sprintf("%02d", int(t.Month())), true
+	case "d":
+		return fmt.Sprintf("%02d", t.Day()), true
+	case "e":
+		return fmt.Sprintf("%2d", t.Day()), true
+	case "j":
+		return fmt.Sprintf("%03d", t.YearDay()), true
+	case "H":
+		return fmt.Sprintf("%02d", t.Hour()), true
+	case "I":
+		hour := t.Hour() % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return fmt.Sprintf("%02d", hour), true
+	case "M":
+		return fmt.Sprintf("%02d", t.Minute()), true
+	case "S":
+		return fmt.Sprintf("%02d", t.Second()), true
+	case "f":
+		return fmt.Sprintf("%06d", t.Nanosecond()/1000), true
+	case "p":
+		if t.Hour() < 12 {
+			return "AM", true
+		}
+		return "PM", true
+	case "b", "h":
+		return t.Month().String()[:3], true
+	case "B":
+		return t.Month().String(), true
+	case "a":
+		return t.Weekday().String()[:3], true
+	case "A":
+		return t.Weekday().String(), true
+	case "z", ":z":
+		_, offset := t.Zone()
+		sign := '+'
+		if offset < 0 {
+			sign = '-'
+			offset = -offset
+		}
+		if directive == ":z" {
+			return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60), true
+		}
+		return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60), true
+	case "Z":
+		if name, _ := t.Zone(); name != "" {
+			return name, true
+		}
+		return strftimeDirective(t, "z")
+	case "F":
+		return fmt.Sprintf("%04d-%02d-%02d", t.Year(), int(t.Month()), t.Day()), true
+	case "T":
+		return fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second()), true
+	case "D":
+		return fmt.Sprintf("%02d/%02d/%02d", int(t.Month()), t.Day(), t.Year()%100), true
+	case "R":
+		return fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute()), true
+	case "%":
+		return "%", true
+	}
+	return "", false
+}
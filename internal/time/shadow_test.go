@@ -0,0 +1,110 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shadowCall is one comparison passed to a ShadowObserver
+type shadowCall struct {
+	engine, kind, outcome string
+}
+
+// stubEngine renders every format as the same text
+type stubEngine struct {
+	text  string
+	panic bool
+}
+
+func (stubEngine) Handles(format string) bool { return true }
+
+func (e stubEngine) Format(t time.Time, format string) (string, error) {
+	if e.panic {
+		panic("boom")
+	}
+	return e.text, nil
+}
+
+func newShadowService(t *testing.T, engine FormatEngine) (TimeService, *[]shadowCall) {
+	var calls []shadowCall
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix", "Layout"}, newTestLogger(t),
+		WithFormatShadow("test", engine, 1, func(engine, kind, outcome string) {
+			calls = append(calls, shadowCall{engine, kind, outcome})
+		}))
+	return service, &calls
+}
+
+func TestFormatShadow(t *testing.T) {
+	at := RFC3339Timestamp("2024-03-10T07:00:01Z")
+
+	t.Run("strftime engine matches", func(t *testing.T) {
+		service, calls := newShadowService(t, ShadowEngines[ShadowEngineStrftime])
+		result, err := service.FormatTime(FormatTimeInput{Timestamp: at, Format: "%A %d %B %Y %I:%M %p %Z", Timezone: "America/New_York"})
+		require.NoError(t, err)
+		assert.Equal(t, "Sunday 10 March 2024 03:00 AM EDT", result.FormattedTime)
+		assert.Equal(t, []shadowCall{{"test", FormatKindStrftime, ShadowMatch}}, *calls)
+
+		// Formats the engine doesn't handle aren't compared
+		_, err = service.FormatTime(FormatTimeInput{Timestamp: at, Format: "Unix"})
+		require.NoError(t, err)
+		assert.Len(t, *calls, 1)
+	})
+
+	t.Run("only the engine renders", func(t *testing.T) {
+		service, calls := newShadowService(t, ShadowEngines[ShadowEngineStrftime])
+		_, err := service.FormatTime(FormatTimeInput{Timestamp: at, Format: "day 1 is %d"})
+		require.Error(t, err)
+		assert.Equal(t, []shadowCall{{"test", FormatKindStrftime, ShadowPrimaryError}}, *calls)
+	})
+
+	t.Run("divergence leaves the response alone", func(t *testing.T) {
+		service, calls := newShadowService(t, stubEngine{text: "nope"})
+		result, err := service.FormatTime(FormatTimeInput{Timestamp: at, Format: "RFC3339"})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-03-10T07:00:01Z", result.FormattedTime)
+		assert.Equal(t, []shadowCall{{"test", FormatKindNamed, ShadowMismatch}}, *calls)
+	})
+
+	t.Run("engine panics", func(t *testing.T) {
+		service, calls := newShadowService(t, stubEngine{panic: true})
+		result, err := service.FormatTime(FormatTimeInput{Timestamp: at, Format: "2006-01-02"})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-03-10", result.FormattedTime)
+		assert.Equal(t, []shadowCall{{"test", FormatKindLayout, ShadowCandidateError}}, *calls)
+	})
+}
+
+func TestStrftimeEngine(t *testing.T) {
+	patterns := []string{
+		"%Y-%m-%d %H:%M:%S", "%y%m%d", "%e %b %Y", "%j", "%I:%M %p", "%a %A %h %B",
+		"%z %:z %Z", "%F %T", "%D %R", "%H:%M:%S.%f", "%H%%",
+	}
+	zones := []string{"UTC", "America/New_York", "Asia/Kolkata", "Europe/Lisbon"}
+	instants := []time.Time{
+		time.Date(2024, time.February, 29, 0, 5, 9, 123456789, time.UTC),
+		time.Date(2024, time.November, 3, 13, 59, 59, 0, time.UTC),
+		time.Date(1999, time.December, 31, 23, 0, 0, 0, time.UTC),
+		time.Date(1900, time.January, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	engine := strftimeEngine{}
+	for _, zone := range zones {
+		loc, err := time.LoadLocation(zone)
+		require.NoError(t, err)
+		for _, at := range instants {
+			for _, pattern := range patterns {
+				want, err := renderFormat(at.In(loc), pattern)
+				require.NoError(t, err)
+				got, err := engine.Format(at.In(loc), pattern)
+				require.NoError(t, err)
+				assert.Equal(t, want, got, "%s at %s in %s", pattern, at, zone)
+			}
+		}
+	}
+
+	_, err := engine.Format(time.Now(), "%Q")
+	assert.Error(t, err)
+}
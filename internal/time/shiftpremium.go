@@ -0,0 +1,287 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+const (
+	// PremiumRegular is the category of time outside every premium window
+	PremiumRegular = "regular"
+
+	maxPremiumWindows = 20
+	maxPremiumSpan    = 31 * 24 * time.Hour
+)
+
+// defaultPremiumWindows are used when a call names none
+var defaultPremiumWindows = []PremiumWindow{
+	{Name: "night", Hours: "22:00-06:00"},
+	{Name: "weekend", Days: []string{"saturday", "sunday"}},
+}
+
+// PremiumWindow is a recurring period paid at a premium
+type PremiumWindow struct {
+	Name string `json:"name"` // category the window's time counts in, such as night or holiday
+	// Hours is hh24:mi-hh24:mi on the local clock, ending the next day when
+	// the end is not after the start; the whole day when empty
+	Hours string `json:"hours,omitempty"`
+	// Days are the weekdays the window starts on, such as saturday
+	Days []string `json:"days,omitempty"`
+	// Dates are the dates the window starts on, YYYY-MM-DD, as for
+	// holidays. Without days or dates, the window starts every day
+	Dates []string `json:"dates,omitempty"`
+}
+
+// EvaluateShiftPremiumInput represents input for splitting a worked
+// interval into premium categories
+type EvaluateShiftPremiumInput struct {
+	Start Timestamp `json:"start"`
+	End   Timestamp `json:"end"`
+	// Windows are the premium windows; time in several counts in the first
+	// listed, so list the highest premium first. Defaults to night
+	// (22:00-06:00) and weekend (saturday and sunday)
+	Windows []PremiumWindow `json:"windows,omitempty"`
+	// Timezone is the zone of the windows' clock times and dates, defaults
+	// to the server default
+	Timezone string `json:"timezone,omitempty"`
+	// IntervalSemantics reads end as the minute after the last one worked
+	// (half_open, the default) or as the last minute worked (closed), and
+	// writes ends the same way
+	IntervalSemantics string `json:"interval_semantics,omitempty"`
+}
+
+// PremiumCategory is the time worked in one category
+type PremiumCategory struct {
+	Name    string `json:"name"`
+	Minutes int    `json:"minutes"`
+}
+
+// PremiumSegment is a stretch of the interval in one category
+type PremiumSegment struct {
+	Category string `json:"category"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Minutes  int    `json:"minutes"`
+}
+
+// EvaluateShiftPremiumResult represents a worked interval split into
+// premium categories
+type EvaluateShiftPremiumResult struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+	// IntervalSemantics is how end and segment ends are written: half_open or closed
+	IntervalSemantics string `json:"interval_semantics"`
+	Minutes           int    `json:"minutes"`
+	// Categories are in window order, then regular time
+	Categories []PremiumCategory `json:"categories"`
+	Segments   []PremiumSegment  `json:"segments"`
+	Notes      []string          `json:"notes,omitempty"`
+}
+
+// premiumWindow is a parsed window; start and end are minutes after
+// midnight, and whole covers the day
+type premiumWindow struct {
+	name       string
+	start, end int
+	whole      bool
+	days       []time.Weekday
+	dates      []string
+}
+
+// startsOn reports whether the window starts on a local date
+func (w premiumWindow) startsOn(day time.Time) bool {
+	if len(w.days) == 0 && len(w.dates) == 0 {
+		return true
+	}
+	return slices.Contains(w.days, day.Weekday()) || slices.Contains(w.dates, day.Format(time.DateOnly))
+}
+
+// EvaluateShiftPremium splits a worked interval into the premium windows it
+// overlaps and regular time. Window bounds are local clock times, and the
+// time in each category is elapsed time, so a night shift across a
+// fall-back change has an hour more night time than the clock shows.
+func (s *timeService) EvaluateShiftPremium(input EvaluateShiftPremiumInput) (EvaluateShiftPremiumResult, error) {
+	if input.Start.IsZero() || input.End.IsZero() {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "start and end are required")
+	}
+	start, err := input.Start.Resolve()
+	if err != nil {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid start: %w", err)
+	}
+	end, err := input.End.Resolve()
+	if err != nil {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid end: %w", err)
+	}
+	semantics, err := ResolveIntervalSemantics(input.IntervalSemantics, IntervalHalfOpen)
+	if err != nil {
+		return EvaluateShiftPremiumResult{}, err
+	}
+	// Read to the minute, as timesheet punches are
+	start, end = start.Truncate(time.Minute), ExclusiveEnd(end.Truncate(time.Minute), semantics, IntervalMinute)
+	if !end.After(start) {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "end must be after start")
+	}
+	if end.Sub(start) > maxPremiumSpan {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "the interval cannot exceed %d days", int(maxPremiumSpan/(24*time.Hour)))
+	}
+	configured := input.Windows
+	if len(configured) == 0 {
+		configured = defaultPremiumWindows
+	}
+	if len(configured) > maxPremiumWindows {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "at most %d windows, got %d", maxPremiumWindows, len(configured))
+	}
+	windows := make([]premiumWindow, 0, len(configured))
+	for _, window := range configured {
+		parsed, err := parsePremiumWindow(window)
+		if err != nil {
+			return EvaluateShiftPremiumResult{}, err
+		}
+		windows = append(windows, parsed)
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return EvaluateShiftPremiumResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	start, end = start.In(loc), end.In(loc)
+
+	// Place every window on each day from the one before the interval,
+	// whose windows may run past midnight, to the last
+	type occurrence struct {
+		window     int
+		start, end time.Time
+	}
+	var occurrences []occurrence
+	cuts := []time.Time{start, end}
+	first := time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		for n, window := range windows {
+			if !window.startsOn(day) {
+				continue
+			}
+			from, to := day.Add(time.Duration(window.start)*time.Minute), day.Add(time.Duration(window.end)*time.Minute)
+			if window.whole || window.end <= window.start {
+				to = to.AddDate(0, 0, 1)
+			}
+			o := occurrence{window: n, start: windowBound(from, loc, false), end: windowBound(to, loc, true)}
+			if o.end.After(start) && o.start.Before(end) {
+				occurrences = append(occurrences, o)
+				cuts = append(cuts, o.start, o.end)
+			}
+		}
+	}
+	sort.Slice(cuts, func(a, b int) bool { return cuts[a].Before(cuts[b]) })
+
+	result := EvaluateShiftPremiumResult{
+		Start:             start.Format(time.RFC3339),
+		End:               ReportedEnd(end, semantics, IntervalMinute).Format(time.RFC3339),
+		Timezone:          timezone,
+		IntervalSemantics: semantics,
+		Minutes:           int(end.Sub(start) / time.Minute),
+		Segments:          []PremiumSegment{},
+	}
+	minutes := make(map[string]int)
+	for n, cut := range cuts[:len(cuts)-1] {
+		next := cuts[n+1]
+		if cut.Before(start) || !next.After(cut) || next.After(end) {
+			continue
+		}
+		// The earliest-listed window covering the piece decides its category
+		category, window := PremiumRegular, len(windows)
+		for _, o := range occurrences {
+			if o.window < window && !cut.Before(o.start) && !next.After(o.end) {
+				category, window = windows[o.window].name, o.window
+			}
+		}
+		length := int(next.Sub(cut) / time.Minute)
+		minutes[category] += length
+		if segments := result.Segments; len(segments) > 0 && segments[len(segments)-1].Category == category {
+			segments[len(segments)-1].End = ReportedEnd(next, semantics, IntervalMinute).Format(time.RFC3339)
+			segments[len(segments)-1].Minutes += length
+			continue
+		}
+		result.Segments = append(result.Segments, PremiumSegment{Category: category, Start: cut.Format(time.RFC3339),
+			End: ReportedEnd(next, semantics, IntervalMinute).Format(time.RFC3339), Minutes: length})
+	}
+	for _, window := range windows {
+		if !slices.ContainsFunc(result.Categories, func(c PremiumCategory) bool { return c.Name == window.name }) {
+			result.Categories = append(result.Categories, PremiumCategory{Name: window.name, Minutes: minutes[window.name]})
+		}
+	}
+	result.Categories = append(result.Categories, PremiumCategory{Name: PremiumRegular, Minutes: minutes[PremiumRegular]})
+
+	_, startOffset := start.Zone()
+	_, endOffset := end.Zone()
+	if startOffset != endOffset {
+		result.Notes = append(result.Notes, fmt.Sprintf("the interval spans a UTC offset change; %s were worked, not the %s the clock shows",
+			end.Sub(start), wallDuration(start, end)))
+	}
+
+	s.logger.Debug("Evaluated shift premium",
+		slog.String("start", result.Start),
+		slog.Int("minutes", result.Minutes),
+		slog.Int("segments", len(result.Segments)))
+
+	return result, nil
+}
+
+// parsePremiumWindow validates a premium window
+func parsePremiumWindow(window PremiumWindow) (premiumWindow, error) {
+	name := strings.TrimSpace(window.Name)
+	if name == "" {
+		return premiumWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "every window needs a name")
+	}
+	if strings.EqualFold(name, PremiumRegular) {
+		return premiumWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window name %q is reserved for time outside every window", PremiumRegular)
+	}
+	parsed := premiumWindow{name: name, whole: true}
+	if hours := strings.TrimSpace(window.Hours); hours != "" {
+		m := dailyWindowPattern.FindStringSubmatch(hours)
+		if m == nil {
+			return premiumWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window %s: invalid hours %q (must be hh24:mi-hh24:mi)", name, window.Hours)
+		}
+		var err error
+		if parsed.start, err = windowMinutes(m[1], m[2]); err != nil {
+			return premiumWindow{}, err
+		}
+		if parsed.end, err = windowMinutes(m[3], m[4]); err != nil {
+			return premiumWindow{}, err
+		}
+		parsed.whole = false
+	}
+	for _, day := range window.Days {
+		weekday, err := parseWeekday(day)
+		if err != nil {
+			return premiumWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window %s: invalid day %q (must be a weekday name)", name, day)
+		}
+		parsed.days = append(parsed.days, weekday)
+	}
+	for _, date := range window.Dates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return premiumWindow{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "window %s: invalid date %q (must be YYYY-MM-DD)", name, date)
+		}
+		parsed.dates = append(parsed.dates, date)
+	}
+	return parsed, nil
+}
+
+// windowBound resolves a window's wall-clock bound. A bound the clock skips
+// moves forward, as in add_time; of a repeated one, a start takes the first
+// occurrence and an end the last, so the window keeps the repeated hour
+func windowBound(wall time.Time, loc *time.Location, end bool) time.Time {
+	instants := wallInstants(wall, loc)
+	if end && len(instants) > 1 {
+		return instants[len(instants)-1]
+	}
+	bound, _ := resolveWallTime(wall, loc)
+	return bound
+}
@@ -0,0 +1,158 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_EvaluateShiftPremium(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	minutes := func(result EvaluateShiftPremiumResult) map[string]int {
+		out := make(map[string]int)
+		for _, category := range result.Categories {
+			out[category.Name] = category.Minutes
+		}
+		return out
+	}
+
+	t.Run("default windows, night listed before weekend", func(t *testing.T) {
+		result, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{Start: RFC3339Timestamp("2024-01-12T18:00:00Z"), End: RFC3339Timestamp("2024-01-13T10:00:00Z")})
+		require.NoError(t, err)
+		assert.Equal(t, []PremiumCategory{{Name: "night", Minutes: 480}, {Name: "weekend", Minutes: 240}, {Name: "regular", Minutes: 240}}, result.Categories)
+		assert.Equal(t, []PremiumSegment{
+			{Category: "regular", Start: "2024-01-12T18:00:00Z", End: "2024-01-12T22:00:00Z", Minutes: 240},
+			{Category: "night", Start: "2024-01-12T22:00:00Z", End: "2024-01-13T06:00:00Z", Minutes: 480},
+			{Category: "weekend", Start: "2024-01-13T06:00:00Z", End: "2024-01-13T10:00:00Z", Minutes: 240},
+		}, result.Segments)
+		assert.Equal(t, 960, result.Minutes)
+		assert.Empty(t, result.Notes)
+	})
+
+	t.Run("holiday first", func(t *testing.T) {
+		result, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+			Start: RFC3339Timestamp("2024-12-24T20:00:00Z"), End: RFC3339Timestamp("2024-12-25T04:00:00Z"),
+			Windows: []PremiumWindow{{Name: "holiday", Dates: []string{"2024-12-25"}}, {Name: "night", Hours: "22:00-06:00"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"holiday": 240, "night": 120, "regular": 120}, minutes(result))
+	})
+
+	t.Run("window on chosen days", func(t *testing.T) {
+		result, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+			Start: RFC3339Timestamp("2024-01-12T23:00:00Z"), End: RFC3339Timestamp("2024-01-13T03:00:00Z"),
+			Windows: []PremiumWindow{{Name: "friday_late", Hours: "23:30-01:00", Days: []string{"Friday"}}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"friday_late": 90, "regular": 150}, minutes(result))
+	})
+
+	// 2024-11-02 is a Saturday
+	t.Run("night across fall back", func(t *testing.T) {
+		result, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+			Start: RFC3339Timestamp("2024-11-02T20:00:00-04:00"), End: RFC3339Timestamp("2024-11-03T04:00:00-05:00"),
+			Timezone: "America/New_York",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"night": 420, "weekend": 120, "regular": 0}, minutes(result))
+		require.Len(t, result.Notes, 1)
+		assert.Contains(t, result.Notes[0], "9h0m0s were worked, not the 8h0m0s the clock shows")
+	})
+
+	t.Run("window ending in the repeated hour keeps it", func(t *testing.T) {
+		result, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+			Start: RFC3339Timestamp("2024-11-02T23:00:00-04:00"), End: RFC3339Timestamp("2024-11-03T03:00:00-05:00"),
+			Windows:  []PremiumWindow{{Name: "late", Hours: "22:00-01:30"}},
+			Timezone: "America/New_York",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"late": 210, "regular": 90}, minutes(result))
+	})
+
+	t.Run("seconds are dropped", func(t *testing.T) {
+		result, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{Start: RFC3339Timestamp("2024-01-10T08:00:45Z"), End: RFC3339Timestamp("2024-01-10T09:00:30Z")})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-10T08:00:00Z", result.Start)
+		assert.Equal(t, 60, result.Minutes)
+	})
+}
+
+func TestTimeService_EvaluateShiftPremiumErrors(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	input := func(windows ...PremiumWindow) EvaluateShiftPremiumInput {
+		return EvaluateShiftPremiumInput{Start: RFC3339Timestamp("2024-01-10T08:00:00Z"), End: RFC3339Timestamp("2024-01-10T16:00:00Z"), Windows: windows}
+	}
+
+	_, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{})
+	assert.ErrorContains(t, err, "start and end are required")
+
+	_, err = service.EvaluateShiftPremium(EvaluateShiftPremiumInput{Start: RFC3339Timestamp("2024-01-10T08:00:00Z"), End: RFC3339Timestamp("2024-01-10T08:00:30Z")})
+	assert.ErrorContains(t, err, "end must be after start")
+
+	_, err = service.EvaluateShiftPremium(EvaluateShiftPremiumInput{Start: RFC3339Timestamp("2024-01-01T00:00:00Z"), End: RFC3339Timestamp("2024-03-01T00:00:00Z")})
+	assert.ErrorContains(t, err, "cannot exceed 31 days")
+
+	_, err = service.EvaluateShiftPremium(input(PremiumWindow{Hours: "22:00-06:00"}))
+	assert.ErrorContains(t, err, "every window needs a name")
+
+	_, err = service.EvaluateShiftPremium(input(PremiumWindow{Name: "Regular"}))
+	assert.ErrorContains(t, err, "is reserved")
+
+	_, err = service.EvaluateShiftPremium(input(PremiumWindow{Name: "night", Hours: "10pm-6am"}))
+	assert.ErrorContains(t, err, `invalid hours "10pm-6am"`)
+
+	_, err = service.EvaluateShiftPremium(input(PremiumWindow{Name: "night", Hours: "25:00-06:00"}))
+	assert.ErrorContains(t, err, "invalid time 25:00")
+
+	_, err = service.EvaluateShiftPremium(input(PremiumWindow{Name: "weekend", Days: []string{"sat"}}))
+	assert.ErrorContains(t, err, `invalid day "sat"`)
+
+	_, err = service.EvaluateShiftPremium(input(PremiumWindow{Name: "holiday", Dates: []string{"12/25/2024"}}))
+	assert.ErrorContains(t, err, `invalid date "12/25/2024"`)
+}
+
+func TestTimeService_EvaluateShiftPremium_IntervalSemantics(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	// The same shift, with its end given as the minute after it and as its last minute
+	halfOpen, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+		Start: RFC3339Timestamp("2024-01-12T18:00:00Z"), End: RFC3339Timestamp("2024-01-13T10:00:00Z"),
+	})
+	require.NoError(t, err)
+	closed, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+		Start: RFC3339Timestamp("2024-01-12T18:00:00Z"), End: RFC3339Timestamp("2024-01-13T09:59:00Z"),
+		IntervalSemantics: IntervalClosed,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, IntervalHalfOpen, halfOpen.IntervalSemantics)
+	assert.Equal(t, IntervalClosed, closed.IntervalSemantics)
+	assert.Equal(t, "2024-01-13T10:00:00Z", halfOpen.End)
+	assert.Equal(t, "2024-01-13T09:59:00Z", closed.End)
+	assert.Equal(t, 960, closed.Minutes)
+	assert.Equal(t, halfOpen.Categories, closed.Categories)
+	assert.Equal(t, []PremiumSegment{
+		{Category: "regular", Start: "2024-01-12T18:00:00Z", End: "2024-01-12T21:59:00Z", Minutes: 240},
+		{Category: "night", Start: "2024-01-12T22:00:00Z", End: "2024-01-13T05:59:00Z", Minutes: 480},
+		{Category: "weekend", Start: "2024-01-13T06:00:00Z", End: "2024-01-13T09:59:00Z", Minutes: 240},
+	}, closed.Segments)
+
+	// A closed shift of one minute ends where it starts
+	single, err := service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+		Start: RFC3339Timestamp("2024-01-15T09:00:00Z"), End: RFC3339Timestamp("2024-01-15T09:00:00Z"),
+		IntervalSemantics: IntervalClosed,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, single.Minutes)
+	assert.Equal(t, "2024-01-15T09:00:00Z", single.End)
+
+	_, err = service.EvaluateShiftPremium(EvaluateShiftPremiumInput{
+		Start: RFC3339Timestamp("2024-01-15T09:00:00Z"), End: RFC3339Timestamp("2024-01-15T09:00:00Z"),
+	})
+	assert.ErrorContains(t, err, "end must be after start")
+}
@@ -0,0 +1,141 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Snowflake timestamps are limited to 0001-01-01 to 9999-12-31, in Unix milliseconds
+const (
+	minSnowflakeMs = -62135596800000
+	maxSnowflakeMs = 253402300799999
+)
+
+// SnowflakeField is a bit field of a snowflake ID below its timestamp
+type SnowflakeField struct {
+	Name  string `json:"name"`
+	Bits  int    `json:"bits"`
+	Value uint64 `json:"value"`
+}
+
+// snowflakeLayout is where a service keeps the millisecond timestamp of its
+// IDs: above shift bits of other fields, counted from a custom epoch
+type snowflakeLayout struct {
+	epochMs int64
+	shift   int
+	fields  []SnowflakeField // from the most significant bits down, values unset
+}
+
+// snowflakePresets are the layouts of well-known snowflake IDs
+var snowflakePresets = map[string]snowflakeLayout{
+	"twitter": {epochMs: 1288834974657, shift: 22, fields: []SnowflakeField{
+		{Name: "datacenter", Bits: 5}, {Name: "worker", Bits: 5}, {Name: "sequence", Bits: 12},
+	}},
+	"discord": {epochMs: 1420070400000, shift: 22, fields: []SnowflakeField{
+		{Name: "worker", Bits: 5}, {Name: "process", Bits: 5}, {Name: "increment", Bits: 12},
+	}},
+	"instagram": {epochMs: 1314220021721, shift: 23, fields: []SnowflakeField{
+		{Name: "shard", Bits: 13}, {Name: "sequence", Bits: 10},
+	}},
+}
+
+// DecodeSnowflakeInput represents input for extracting the timestamp of a
+// snowflake ID
+type DecodeSnowflakeInput struct {
+	// ID is the decimal ID, as a string so it keeps all 64 bits
+	ID string `json:"id"`
+	// Preset is the ID's layout: twitter (default), discord or instagram
+	Preset string `json:"preset,omitempty"`
+	// Epoch overrides the preset's epoch, for services with their own
+	Epoch Timestamp `json:"epoch,omitempty"`
+	// TimestampShift overrides the number of bits below the timestamp
+	TimestampShift *int   `json:"timestamp_shift,omitempty"`
+	Timezone       string `json:"timezone,omitempty"` // defaults to the server default
+}
+
+// DecodeSnowflakeResult represents the creation time of a snowflake ID
+type DecodeSnowflakeResult struct {
+	ID        string `json:"id"`
+	Preset    string `json:"preset"`
+	Timestamp string `json:"timestamp"`
+	UnixMs    int64  `json:"unix_ms"`
+	Timezone  string `json:"timezone"`
+	// Fields are the preset's other bit fields; left out when the layout
+	// is overridden
+	Fields   []SnowflakeField `json:"fields,omitempty"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// DecodeSnowflake extracts the millisecond creation time embedded in a
+// snowflake ID
+func (s *timeService) DecodeSnowflake(input DecodeSnowflakeInput) (DecodeSnowflakeResult, error) {
+	preset := strings.ToLower(defaultString(input.Preset, "twitter"))
+	layout, ok := snowflakePresets[preset]
+	if !ok {
+		presets := make([]string, 0, len(snowflakePresets))
+		for name := range snowflakePresets {
+			presets = append(presets, name)
+		}
+		sort.Strings(presets)
+		return DecodeSnowflakeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid preset %q (must be one of: %s)", input.Preset, strings.Join(presets, ", "))
+	}
+	id, err := strconv.ParseUint(strings.TrimSpace(input.ID), 10, 64)
+	if err != nil {
+		return DecodeSnowflakeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid id %q: must be a decimal integer of at most 64 bits", input.ID)
+	}
+	if !input.Epoch.IsZero() {
+		epoch, err := input.Epoch.Resolve()
+		if err != nil {
+			return DecodeSnowflakeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid epoch: %w", err)
+		}
+		layout.epochMs, layout.fields = epoch.UnixMilli(), nil
+	}
+	if input.TimestampShift != nil {
+		if *input.TimestampShift < 0 || *input.TimestampShift > 63 {
+			return DecodeSnowflakeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp_shift must be between 0 and 63, got %d", *input.TimestampShift)
+		}
+		layout.shift, layout.fields = *input.TimestampShift, nil
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return DecodeSnowflakeResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	// Keep the creation time within the years RFC3339 can write
+	ticks := id >> layout.shift
+	ms := int64(ticks) + layout.epochMs
+	if ticks > maxSnowflakeMs || ms < minSnowflakeMs || ms > maxSnowflakeMs {
+		return DecodeSnowflakeResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "id %s puts its timestamp outside the years 1 to 9999; check the preset, epoch or timestamp_shift", input.ID)
+	}
+	created := time.UnixMilli(ms)
+	result := DecodeSnowflakeResult{
+		ID:        input.ID,
+		Preset:    preset,
+		Timestamp: created.In(loc).Format(time.RFC3339Nano),
+		UnixMs:    ms,
+		Timezone:  timezone,
+	}
+	shift := layout.shift
+	for _, field := range layout.fields {
+		shift -= field.Bits
+		field.Value = (id >> shift) & (1<<field.Bits - 1)
+		result.Fields = append(result.Fields, field)
+	}
+	if created.After(s.clock.Now()) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("the timestamp is in the future; the ID may not use the %s layout", preset))
+	}
+
+	s.logger.Debug("Decoded snowflake",
+		slog.String("id", input.ID),
+		slog.String("preset", preset),
+		slog.String("timestamp", result.Timestamp))
+
+	return result, nil
+}
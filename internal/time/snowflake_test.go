@@ -0,0 +1,76 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_DecodeSnowflake(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	shift := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		input    DecodeSnowflakeInput
+		expected string
+		fields   []SnowflakeField
+	}{
+		{
+			name:     "twitter",
+			input:    DecodeSnowflakeInput{ID: "1212092628029698048"},
+			expected: "2019-12-31T19:26:16.771Z",
+			fields:   []SnowflakeField{{Name: "datacenter", Bits: 5, Value: 10}, {Name: "worker", Bits: 5, Value: 7}, {Name: "sequence", Bits: 12, Value: 0}},
+		},
+		{
+			name:     "discord",
+			input:    DecodeSnowflakeInput{ID: "175928847299117063", Preset: "Discord"},
+			expected: "2016-04-30T11:18:25.796Z",
+			fields:   []SnowflakeField{{Name: "worker", Bits: 5, Value: 1}, {Name: "process", Bits: 5, Value: 0}, {Name: "increment", Bits: 12, Value: 7}},
+		},
+		{
+			name:     "instagram",
+			input:    DecodeSnowflakeInput{ID: "2896324063536611612", Preset: "instagram", Timezone: "America/New_York"},
+			expected: "2022-08-02T21:05:55.644-04:00",
+		},
+		{
+			name:     "custom layout",
+			input:    DecodeSnowflakeInput{ID: "65536000", Epoch: RFC3339Timestamp("2020-01-01T00:00:00Z"), TimestampShift: shift(16)},
+			expected: "2020-01-01T00:00:01Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.DecodeSnowflake(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Timestamp)
+			if tt.fields != nil {
+				assert.Equal(t, tt.fields, result.Fields)
+			}
+			assert.Empty(t, result.Warnings)
+		})
+	}
+
+	result, err := service.DecodeSnowflake(DecodeSnowflakeInput{ID: "9223372036854775807", Preset: "discord"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Warnings[0], "the timestamp is in the future")
+
+	_, err = service.DecodeSnowflake(DecodeSnowflakeInput{ID: "18446744073709551615", TimestampShift: shift(0)})
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+
+	_, err = service.DecodeSnowflake(DecodeSnowflakeInput{ID: "12ab"})
+	assert.ErrorContains(t, err, "must be a decimal integer")
+
+	_, err = service.DecodeSnowflake(DecodeSnowflakeInput{ID: "1", Preset: "mastodon"})
+	assert.ErrorContains(t, err, "must be one of: discord, instagram, twitter")
+
+	_, err = service.DecodeSnowflake(DecodeSnowflakeInput{ID: "1", TimestampShift: shift(64)})
+	assert.ErrorContains(t, err, "timestamp_shift must be between 0 and 63")
+}
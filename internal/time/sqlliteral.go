@@ -0,0 +1,215 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// SQL dialects to_sql_literal writes for
+const (
+	SQLDialectPostgres  = "postgres"
+	SQLDialectMySQL     = "mysql"
+	SQLDialectSQLite    = "sqlite"
+	SQLDialectSQLServer = "sqlserver"
+)
+
+// SQL column types to_sql_literal writes for
+const (
+	SQLTypeTimestamp   = "timestamp"   // wall time without a zone: TIMESTAMP, DATETIME, DATETIME2
+	SQLTypeTimestampTZ = "timestamptz" // an instant: TIMESTAMPTZ, DATETIMEOFFSET
+	SQLTypeDate        = "date"
+)
+
+const (
+	// sqlDateTimeLayout is the text form of MySQL DATETIME and Postgres
+	// timestamp, up to microseconds
+	sqlDateTimeLayout = "2006-01-02 15:04:05.999999"
+	// julianDayUnixEpoch is the Julian day of 1970-01-01T00:00:00Z
+	julianDayUnixEpoch = 2440587.5
+)
+
+// formatSQL writes t as a MySQL DATETIME, the Postgres text output of a
+// timestamptz, or a SQLite julianday value
+func formatSQL(t time.Time, format FormatType) string {
+	switch format {
+	case FormatPostgresTimestampTZ:
+		return t.Format(sqlDateTimeLayout) + postgresOffset(t)
+	case FormatJulianDay:
+		seconds := float64(t.Unix()) + float64(t.Nanosecond())/1e9
+		return strconv.FormatFloat(julianDayUnixEpoch+seconds/86400, 'f', -1, 64)
+	default:
+		return t.Format(sqlDateTimeLayout)
+	}
+}
+
+// postgresOffset writes an offset the way Postgres does: +01, or +05:30
+// when the offset isn't whole hours
+func postgresOffset(t time.Time) string {
+	_, offset := t.Zone()
+	if offset%3600 == 0 {
+		return t.Format("-07")
+	}
+	if offset%60 == 0 {
+		return t.Format("-07:00")
+	}
+	return t.Format("-07:00:00")
+}
+
+// parseSQL reads a MySQL DATETIME as a wall time in loc, a Postgres
+// timestamptz with its offset, or a SQLite julianday value
+func parseSQL(s string, format FormatType, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch format {
+	case FormatPostgresTimestampTZ:
+		for _, offset := range []string{"-07", "-07:00", "-07:00:00"} {
+			if t, err := time.ParseInLocation("2006-01-02 15:04:05"+offset, s, loc); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must look like 2023-12-25 15:30:45.123+00", format, s)
+	case FormatJulianDay:
+		jd, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(jd, 0) || math.IsNaN(jd) {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must be a number of days", format, s)
+		}
+		// SQLite resolves julianday values to the millisecond
+		ms := math.Round((jd - julianDayUnixEpoch) * 86400000)
+		if ms < math.MinInt64/2 || ms > math.MaxInt64/2 {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s %s is out of range", format, s)
+		}
+		return time.UnixMilli(int64(ms)).In(loc), nil
+	default:
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", s, loc)
+		if err != nil {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid %s %q: must look like 2023-12-25 15:30:45", format, s)
+		}
+		return t, nil
+	}
+}
+
+// ToSQLLiteralInput represents input for writing an instant as a SQL literal
+type ToSQLLiteralInput struct {
+	Timestamp Timestamp `json:"timestamp,omitempty"` // defaults to now
+	Dialect   string    `json:"dialect"`             // postgres, mysql, sqlite or sqlserver
+	// Type is the column type: timestamp (wall time, default), timestamptz
+	// (an instant with its offset) or date
+	Type string `json:"type,omitempty"`
+	// Timezone is the zone of the wall time and date; defaults to the server default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ToSQLLiteralResult represents a SQL literal for an instant
+type ToSQLLiteralResult struct {
+	Literal  string `json:"literal"` // e.g. TIMESTAMPTZ '2023-12-25 15:30:45+00'
+	Value    string `json:"value"`   // the quoted text alone
+	Dialect  string `json:"dialect"`
+	Type     string `json:"type"`
+	Timezone string `json:"timezone"`
+	// Truncated is set when the dialect can't hold the instant's precision
+	Truncated bool     `json:"truncated"`
+	Notes     []string `json:"notes,omitempty"`
+}
+
+// sqlPrecision is the finest fraction each dialect stores
+var sqlPrecision = map[string]time.Duration{
+	SQLDialectPostgres:  time.Microsecond,
+	SQLDialectMySQL:     time.Microsecond,
+	SQLDialectSQLite:    time.Millisecond,
+	SQLDialectSQLServer: 100 * time.Nanosecond,
+}
+
+// ToSQLLiteral writes an instant as a typed, correctly quoted literal for
+// a SQL dialect
+func (s *timeService) ToSQLLiteral(input ToSQLLiteralInput) (ToSQLLiteralResult, error) {
+	dialect := strings.ToLower(input.Dialect)
+	precision, ok := sqlPrecision[dialect]
+	if !ok {
+		return ToSQLLiteralResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid dialect %q (must be one of: postgres, mysql, sqlite, sqlserver)", input.Dialect)
+	}
+	sqlType := strings.ToLower(defaultString(input.Type, SQLTypeTimestamp))
+	if sqlType != SQLTypeTimestamp && sqlType != SQLTypeTimestampTZ && sqlType != SQLTypeDate {
+		return ToSQLLiteralResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid type %q (must be one of: timestamp, timestamptz, date)", input.Type)
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return ToSQLLiteralResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+	at := s.clock.Now()
+	if !input.Timestamp.IsZero() {
+		if at, err = input.Timestamp.Resolve(); err != nil {
+			return ToSQLLiteralResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid timestamp: %w", err)
+		}
+	}
+	if at.Year() < 1 || at.Year() > 9999 {
+		return ToSQLLiteralResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s is outside the years 1 to 9999 SQL dates support", at.Format(time.RFC3339))
+	}
+
+	result := ToSQLLiteralResult{Dialect: dialect, Type: sqlType, Timezone: timezone}
+	at = at.In(loc)
+	if sqlType != SQLTypeDate {
+		result.Truncated = !at.Truncate(precision).Equal(at)
+		at = at.Truncate(precision)
+	}
+	fraction := ".999999"
+	switch dialect {
+	case SQLDialectSQLite:
+		fraction = ".999"
+	case SQLDialectSQLServer:
+		fraction = ".9999999"
+	}
+
+	var keyword string
+	switch sqlType {
+	case SQLTypeDate:
+		result.Value, keyword = at.Format(time.DateOnly), "DATE"
+	case SQLTypeTimestamp:
+		result.Value, keyword = at.Format("2006-01-02 15:04:05"+fraction), "TIMESTAMP"
+	case SQLTypeTimestampTZ:
+		keyword = "TIMESTAMPTZ"
+		result.Value = at.Format("2006-01-02 15:04:05"+fraction) + postgresOffset(at)
+		if dialect != SQLDialectPostgres {
+			// MySQL 8.0.19+, SQLite and SQL Server take a full +hh:mm offset
+			result.Value = at.Format("2006-01-02 15:04:05" + fraction + "-07:00")
+		}
+	}
+
+	quoted := "'" + strings.ReplaceAll(result.Value, "'", "''") + "'"
+	switch dialect {
+	case SQLDialectPostgres:
+		result.Literal = keyword + " " + quoted
+	case SQLDialectMySQL:
+		result.Literal = "TIMESTAMP " + quoted
+		switch sqlType {
+		case SQLTypeDate:
+			result.Literal = "DATE " + quoted
+		case SQLTypeTimestampTZ:
+			result.Notes = append(result.Notes, "MySQL converts the offset to the session time_zone; offsets in literals need MySQL 8.0.19 or later")
+		}
+	case SQLDialectSQLite:
+		// SQLite has no date types; its date functions read this text
+		result.Literal = quoted
+		if sqlType == SQLTypeTimestamp && timezone != "UTC" {
+			result.Notes = append(result.Notes, fmt.Sprintf("SQLite date functions treat text without an offset as UTC; this is wall time in %s", timezone))
+		}
+	case SQLDialectSQLServer:
+		sqlServerType := map[string]string{SQLTypeDate: "DATE", SQLTypeTimestamp: "DATETIME2", SQLTypeTimestampTZ: "DATETIMEOFFSET"}[sqlType]
+		// The ISO 8601 T separator reads the same under every DATEFORMAT setting
+		value := strings.Replace(result.Value, " ", "T", 1)
+		result.Literal = fmt.Sprintf("CAST('%s' AS %s)", strings.ReplaceAll(value, "'", "''"), sqlServerType)
+		result.Value = value
+	}
+
+	s.logger.Debug("Wrote SQL literal",
+		slog.String("dialect", dialect),
+		slog.String("type", sqlType),
+		slog.String("literal", result.Literal))
+
+	return result, nil
+}
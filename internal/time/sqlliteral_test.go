@@ -0,0 +1,90 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestSQLFormats(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	at := time.Date(2023, 12, 25, 15, 30, 45, 123456789, time.UTC)
+
+	tests := []struct {
+		format   FormatType
+		at       time.Time
+		expected string
+		parsed   time.Time
+	}{
+		{format: FormatMySQLDateTime, at: at, expected: "2023-12-25 15:30:45.123456", parsed: at.Truncate(time.Microsecond)},
+		{format: FormatPostgresTimestampTZ, at: at, expected: "2023-12-25 15:30:45.123456+00", parsed: at.Truncate(time.Microsecond)},
+		{format: FormatPostgresTimestampTZ, at: at.In(kolkata), expected: "2023-12-25 21:00:45.123456+05:30", parsed: at.Truncate(time.Microsecond)},
+		{format: FormatJulianDay, at: time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), expected: "2451545", parsed: time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{format: FormatJulianDay, at: at, expected: "2460304.1463555954", parsed: at.Truncate(time.Millisecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format)+"/"+tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatSQL(tt.at, tt.format))
+			parsed, err := parseSQL(tt.expected, tt.format, time.UTC)
+			require.NoError(t, err)
+			assert.True(t, tt.parsed.Equal(parsed), "parsed %s", parsed)
+		})
+	}
+
+	_, err = parseSQL("2023-12-25T15:30:45Z", FormatMySQLDateTime, time.UTC)
+	assert.True(t, errors.Is(err, timeerrors.ErrParseFailure))
+	_, err = parseSQL("2023-12-25 15:30:45", FormatPostgresTimestampTZ, time.UTC)
+	assert.ErrorContains(t, err, "must look like 2023-12-25 15:30:45.123+00")
+	_, err = parseSQL("NaN", FormatJulianDay, time.UTC)
+	assert.ErrorContains(t, err, "must be a number of days")
+}
+
+func TestTimeService_ToSQLLiteral(t *testing.T) {
+	now := time.Date(2023, 12, 25, 15, 30, 45, 123456789, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		dialect, sqlType, timezone string
+		literal                    string
+	}{
+		{dialect: "postgres", literal: "TIMESTAMP '2023-12-25 15:30:45.123456'"},
+		{dialect: "postgres", sqlType: "timestamptz", timezone: "Asia/Kolkata", literal: "TIMESTAMPTZ '2023-12-25 21:00:45.123456+05:30'"},
+		{dialect: "postgres", sqlType: "date", timezone: "Asia/Tokyo", literal: "DATE '2023-12-26'"},
+		{dialect: "mysql", timezone: "Europe/Paris", literal: "TIMESTAMP '2023-12-25 16:30:45.123456'"},
+		{dialect: "MySQL", sqlType: "timestamptz", literal: "TIMESTAMP '2023-12-25 15:30:45.123456+00:00'"},
+		{dialect: "sqlite", literal: "'2023-12-25 15:30:45.123'"},
+		{dialect: "sqlite", sqlType: "timestamptz", timezone: "Europe/Paris", literal: "'2023-12-25 16:30:45.123+01:00'"},
+		{dialect: "sqlserver", literal: "CAST('2023-12-25T15:30:45.1234567' AS DATETIME2)"},
+		{dialect: "sqlserver", sqlType: "timestamptz", literal: "CAST('2023-12-25T15:30:45.1234567+00:00' AS DATETIMEOFFSET)"},
+		{dialect: "sqlserver", sqlType: "date", literal: "CAST('2023-12-25' AS DATE)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.literal, func(t *testing.T) {
+			result, err := service.ToSQLLiteral(ToSQLLiteralInput{Dialect: tt.dialect, Type: tt.sqlType, Timezone: tt.timezone})
+			require.NoError(t, err)
+			assert.Equal(t, tt.literal, result.Literal)
+			assert.Equal(t, tt.sqlType != "date", result.Truncated)
+		})
+	}
+
+	result, err := service.ToSQLLiteral(ToSQLLiteralInput{Dialect: "sqlite", Timezone: "Europe/Paris"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Notes[0], "treat text without an offset as UTC")
+
+	_, err = service.ToSQLLiteral(ToSQLLiteralInput{Dialect: "oracle"})
+	assert.ErrorContains(t, err, "invalid dialect")
+
+	_, err = service.ToSQLLiteral(ToSQLLiteralInput{Dialect: "postgres", Type: "interval"})
+	assert.ErrorContains(t, err, "invalid type")
+
+	_, err = service.ToSQLLiteral(ToSQLLiteralInput{Dialect: "postgres", Timestamp: EpochTimestamp(-62167219200, EpochSeconds)})
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+}
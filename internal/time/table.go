@@ -0,0 +1,153 @@
+package time
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// maxTableRows bounds the rows of a pasted table
+const maxTableRows = 10000
+
+// ParseTableInput represents input for parsing a column of timestamps from
+// delimited text
+type ParseTableInput struct {
+	// Text is a CSV or TSV snippet, one row per line
+	Text string `json:"text"`
+	// Column is the zero-based index of the column holding the timestamps
+	Column int `json:"column,omitempty"`
+	// Delimiter is a single character; defaults to tab when the first line
+	// has one, otherwise comma
+	Delimiter string `json:"delimiter,omitempty"`
+	// Header skips the first row
+	Header bool `json:"header,omitempty"`
+	// Format is a named format, Go layout or strftime pattern. Without one,
+	// cells are read as RFC3339 or epoch seconds
+	Format string `json:"format,omitempty"`
+	// Timezone reads cells without an offset and renders results; defaults
+	// to the server default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// TableRow is the timestamp read from one row, or why it couldn't be
+type TableRow struct {
+	Row       int    `json:"row"` // 1-based record number, counting the header
+	Value     string `json:"value"`
+	Timestamp string `json:"timestamp,omitempty"` // RFC3339 in the timezone
+	Unix      int64  `json:"unix,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ParseTableResult represents the timestamps of a table column
+type ParseTableResult struct {
+	Column    int        `json:"column"`
+	Delimiter string     `json:"delimiter"`
+	Timezone  string     `json:"timezone"`
+	Parsed    int        `json:"parsed"`
+	Failed    int        `json:"failed"`
+	Rows      []TableRow `json:"rows"`
+}
+
+// ParseTable reads one column of a CSV or TSV snippet as timestamps,
+// reporting errors per row rather than failing the whole table
+func (s *timeService) ParseTable(input ParseTableInput) (ParseTableResult, error) {
+	if strings.TrimSpace(input.Text) == "" {
+		return ParseTableResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "text cannot be empty")
+	}
+	if input.Column < 0 {
+		return ParseTableResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "column must be zero or positive, got %d", input.Column)
+	}
+	delimiter := input.Delimiter
+	if delimiter == "" {
+		delimiter = ","
+		if firstLine, _, _ := strings.Cut(input.Text, "\n"); strings.Contains(firstLine, "\t") {
+			delimiter = "\t"
+		}
+	}
+	comma, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || comma == '"' || comma == '\r' || comma == '\n' {
+		return ParseTableResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid delimiter %q: must be a single character other than a quote or newline", delimiter)
+	}
+	layout := ""
+	if input.Format != "" {
+		var err error
+		if layout, err = layoutFor(input.Format); err != nil {
+			return ParseTableResult{}, err
+		}
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return ParseTableResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	reader := csv.NewReader(strings.NewReader(input.Text))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	result := ParseTableResult{Column: input.Column, Delimiter: delimiter, Timezone: timezone, Rows: []TableRow{}}
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ParseTableResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid table: %w", err)
+		}
+		if row == 1 && input.Header {
+			continue
+		}
+		if len(result.Rows) == maxTableRows {
+			return ParseTableResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "table has more than %d rows", maxTableRows)
+		}
+
+		tableRow := TableRow{Row: row}
+		if input.Column >= len(record) {
+			tableRow.Error = fmt.Sprintf("row has %d columns, no column %d", len(record), input.Column)
+		} else {
+			tableRow.Value = strings.TrimSpace(record[input.Column])
+			if t, err := s.parseCell(tableRow.Value, layout, loc); err != nil {
+				tableRow.Error = err.Error()
+			} else {
+				tableRow.Timestamp = t.In(loc).Format(time.RFC3339Nano)
+				tableRow.Unix = t.Unix()
+			}
+		}
+		if tableRow.Error != "" {
+			result.Failed++
+		} else {
+			result.Parsed++
+		}
+		result.Rows = append(result.Rows, tableRow)
+	}
+
+	s.logger.Debug("Parsed table",
+		slog.Int("column", input.Column),
+		slog.Int("parsed", result.Parsed),
+		slog.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// parseCell reads one table cell with a layout, or as a Timestamp string
+// when there is none
+func (s *timeService) parseCell(cell, layout string, loc *time.Location) (time.Time, error) {
+	if cell == "" {
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "cell is empty")
+	}
+	if layout != "" {
+		return s.parseTimeInternal(cell, layout, loc)
+	}
+	var ts Timestamp
+	if err := ts.fromString(cell); err != nil {
+		return time.Time{}, err
+	}
+	return ts.Resolve()
+}
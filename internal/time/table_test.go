@@ -0,0 +1,60 @@
+package time
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_ParseTable(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+
+	result, err := service.ParseTable(ParseTableInput{
+		Text:   "id,created,note\n1,25/12/2023 15:30,\"a, b\"\n2,not a date,x\n3\n4, 26/12/2023 08:00 ,y\n",
+		Column: 1,
+		Header: true,
+		Format: "%d/%m/%Y %H:%M",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ",", result.Delimiter)
+	assert.Equal(t, 2, result.Parsed)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Rows, 4)
+	assert.Equal(t, TableRow{Row: 2, Value: "25/12/2023 15:30", Timestamp: "2023-12-25T15:30:00Z", Unix: 1703518200}, result.Rows[0])
+	assert.Equal(t, 3, result.Rows[1].Row)
+	assert.Contains(t, result.Rows[1].Error, "failed to parse time string not a date")
+	assert.Equal(t, TableRow{Row: 4, Error: "row has 1 columns, no column 1"}, result.Rows[2])
+	assert.Equal(t, "2023-12-26T08:00:00Z", result.Rows[3].Timestamp)
+
+	// Tabs are detected, and cells are read as RFC3339 or epoch seconds
+	result, err = service.ParseTable(ParseTableInput{
+		Text:     "2023-12-25T15:30:00Z\tok\n1703518200\tok\n\tempty",
+		Timezone: "Europe/Paris",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "\t", result.Delimiter)
+	assert.Equal(t, "2023-12-25T16:30:00+01:00", result.Rows[0].Timestamp)
+	assert.Equal(t, "2023-12-25T16:30:00+01:00", result.Rows[1].Timestamp)
+	assert.Contains(t, result.Rows[2].Error, "cell is empty")
+
+	result, err = service.ParseTable(ParseTableInput{Text: "a;2023-12-25T15:30:00Z", Column: 1, Delimiter: ";"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Parsed)
+
+	_, err = service.ParseTable(ParseTableInput{Text: "a", Delimiter: "||"})
+	assert.ErrorContains(t, err, "must be a single character")
+
+	_, err = service.ParseTable(ParseTableInput{Text: "a", Column: -1})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+
+	_, err = service.ParseTable(ParseTableInput{Text: "a", Format: "DD/MM/YYYY"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidFormat))
+
+	_, err = service.ParseTable(ParseTableInput{Text: strings.Repeat("1703518200\n", maxTableRows+1)})
+	assert.ErrorContains(t, err, "more than 10000 rows")
+}
@@ -0,0 +1,303 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Timesheet rounding modes
+const (
+	// RoundingNearest rounds to the nearest increment, the 7/8-minute rule
+	// for 15-minute increments: 7 minutes past rounds down, 8 rounds up
+	RoundingNearest = "nearest"
+	RoundingDown    = "down"
+	RoundingUp      = "up"
+)
+
+const (
+	defaultTimesheetIncrement = 15
+	defaultWeeklyOvertime     = 40 * time.Hour
+	maxTimesheetShifts        = 500
+)
+
+// TimesheetShift is one punch-in and punch-out
+type TimesheetShift struct {
+	In  Timestamp `json:"in"`
+	Out Timestamp `json:"out"`
+}
+
+// TimesheetInput represents input for rounding punches and finding overtime
+type TimesheetInput struct {
+	Shifts []TimesheetShift `json:"shifts"`
+	// Increment is the rounding increment in minutes, which must divide an
+	// hour; defaults to 15
+	Increment int    `json:"increment,omitempty"`
+	Rounding  string `json:"rounding,omitempty"` // nearest (default), down or up
+	// DailyOvertime is the hours per day after which time is overtime, as
+	// a Go duration; no daily overtime when empty
+	DailyOvertime string `json:"daily_overtime,omitempty"`
+	// WeeklyOvertime is the hours per week after which time is overtime,
+	// defaults to 40h; 0 turns it off
+	WeeklyOvertime string `json:"weekly_overtime,omitempty"`
+	WeekStart      string `json:"week_start,omitempty"` // first day of the workweek, defaults to monday
+	// Timezone decides the days and weeks shifts fall in and the clock the
+	// increments align to; defaults to the server default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// RoundedShift is a shift with its rounded punches
+type RoundedShift struct {
+	In         string `json:"in"`
+	Out        string `json:"out"`
+	RoundedIn  string `json:"rounded_in"`
+	RoundedOut string `json:"rounded_out"`
+	// Date is the day the shift counts on, the day it starts
+	Date          string `json:"date"`
+	WorkedMinutes int    `json:"worked_minutes"`
+}
+
+// TimesheetDay is the time worked on one day
+type TimesheetDay struct {
+	Date            string `json:"date"`
+	WorkedMinutes   int    `json:"worked_minutes"`
+	OvertimeMinutes int    `json:"overtime_minutes"`        // beyond daily_overtime
+	OvertimeFrom    string `json:"overtime_from,omitempty"` // when daily_overtime was crossed
+}
+
+// TimesheetWeek is the time worked in one workweek
+type TimesheetWeek struct {
+	Start         string `json:"start"` // first day of the workweek
+	WorkedMinutes int    `json:"worked_minutes"`
+	// OvertimeMinutes are beyond weekly_overtime. Daily overtime doesn't
+	// count toward the weekly threshold, so no time is overtime twice
+	OvertimeMinutes int    `json:"overtime_minutes"`
+	OvertimeFrom    string `json:"overtime_from,omitempty"` // when weekly_overtime was crossed
+}
+
+// TimesheetResult represents rounded shifts and their overtime
+type TimesheetResult struct {
+	Timezone        string          `json:"timezone"`
+	Increment       int             `json:"increment"`
+	Rounding        string          `json:"rounding"`
+	DailyOvertime   string          `json:"daily_overtime,omitempty"`
+	WeeklyOvertime  string          `json:"weekly_overtime,omitempty"`
+	Shifts          []RoundedShift  `json:"shifts"`
+	Days            []TimesheetDay  `json:"days"`
+	Weeks           []TimesheetWeek `json:"weeks"`
+	WorkedMinutes   int             `json:"worked_minutes"`
+	RegularMinutes  int             `json:"regular_minutes"`
+	OvertimeMinutes int             `json:"overtime_minutes"` // daily and weekly overtime
+	Notes           []string        `json:"notes,omitempty"`
+}
+
+// Timesheet rounds punch times to an increment and splits the rounded time
+// into regular time and daily and weekly overtime. Punches are read to the
+// minute, as time clocks do, and worked time is elapsed time, so a night
+// shift across a DST change is paid for the hours actually worked.
+func (s *timeService) Timesheet(input TimesheetInput) (TimesheetResult, error) {
+	if len(input.Shifts) == 0 {
+		return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "shifts cannot be empty")
+	}
+	if len(input.Shifts) > maxTimesheetShifts {
+		return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "at most %d shifts, got %d", maxTimesheetShifts, len(input.Shifts))
+	}
+	increment := input.Increment
+	if increment == 0 {
+		increment = defaultTimesheetIncrement
+	}
+	if increment < 1 || increment > 60 || 60%increment != 0 {
+		return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "increment must divide an hour (e.g. 6, 10, 15 or 30), got %d", increment)
+	}
+	rounding := strings.ToLower(defaultString(input.Rounding, RoundingNearest))
+	if rounding != RoundingNearest && rounding != RoundingDown && rounding != RoundingUp {
+		return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid rounding %q (must be one of: nearest, down, up)", input.Rounding)
+	}
+	daily, err := overtimeThreshold("daily_overtime", input.DailyOvertime, 0)
+	if err != nil {
+		return TimesheetResult{}, err
+	}
+	weekly, err := overtimeThreshold("weekly_overtime", input.WeeklyOvertime, defaultWeeklyOvertime)
+	if err != nil {
+		return TimesheetResult{}, err
+	}
+	weekStart := time.Monday
+	if input.WeekStart != "" {
+		if weekStart, err = parseWeekday(input.WeekStart); err != nil {
+			return TimesheetResult{}, err
+		}
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	type shift struct {
+		in, out time.Time
+		rounded RoundedShift
+	}
+	shifts := make([]shift, 0, len(input.Shifts))
+	for n, punch := range input.Shifts {
+		if punch.In.IsZero() || punch.Out.IsZero() {
+			return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "shift %d: in and out are required", n+1)
+		}
+		in, err := punch.In.Resolve()
+		if err != nil {
+			return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "shift %d: invalid in: %w", n+1, err)
+		}
+		out, err := punch.Out.Resolve()
+		if err != nil {
+			return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "shift %d: invalid out: %w", n+1, err)
+		}
+		if !out.After(in) {
+			return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "shift %d: out must be after in", n+1)
+		}
+		in, out = in.In(loc), out.In(loc)
+		roundedIn := roundPunch(in, increment, rounding)
+		roundedOut := roundPunch(out, increment, rounding)
+		shifts = append(shifts, shift{in: roundedIn, out: roundedOut, rounded: RoundedShift{
+			In:            in.Format(time.RFC3339),
+			Out:           out.Format(time.RFC3339),
+			RoundedIn:     roundedIn.Format(time.RFC3339),
+			RoundedOut:    roundedOut.Format(time.RFC3339),
+			Date:          roundedIn.Format(time.DateOnly),
+			WorkedMinutes: int(roundedOut.Sub(roundedIn) / time.Minute),
+		}})
+	}
+	sort.SliceStable(shifts, func(a, b int) bool { return shifts[a].in.Before(shifts[b].in) })
+
+	result := TimesheetResult{
+		Timezone:  timezone,
+		Increment: increment,
+		Rounding:  rounding,
+		Shifts:    []RoundedShift{},
+		Days:      []TimesheetDay{},
+		Weeks:     []TimesheetWeek{},
+	}
+	if daily > 0 {
+		result.DailyOvertime = daily.String()
+	}
+	if weekly > 0 {
+		result.WeeklyOvertime = weekly.String()
+	}
+
+	// Walk the shifts in order, filling each day's and week's regular time
+	// before overtime starts
+	var dayWorked, weekRegular time.Duration
+	for n, sh := range shifts {
+		if n > 0 && sh.in.Before(shifts[n-1].out) {
+			return TimesheetResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "shifts starting %s and %s overlap after rounding",
+				shifts[n-1].rounded.RoundedIn, sh.rounded.RoundedIn)
+		}
+		worked := time.Duration(sh.rounded.WorkedMinutes) * time.Minute
+		result.Shifts = append(result.Shifts, sh.rounded)
+		if worked == 0 {
+			result.Notes = append(result.Notes, fmt.Sprintf("the shift from %s to %s rounds to no time worked", sh.rounded.In, sh.rounded.Out))
+		}
+		_, inOffset := sh.in.Zone()
+		_, outOffset := sh.out.Zone()
+		if inOffset != outOffset {
+			result.Notes = append(result.Notes, fmt.Sprintf("the shift starting %s spans a UTC offset change; %s were worked, not the %s the clock shows",
+				sh.rounded.RoundedIn, worked, wallDuration(sh.in, sh.out)))
+		}
+
+		if len(result.Days) == 0 || result.Days[len(result.Days)-1].Date != sh.rounded.Date {
+			result.Days = append(result.Days, TimesheetDay{Date: sh.rounded.Date})
+			dayWorked = 0
+		}
+		day := &result.Days[len(result.Days)-1]
+		start := workweekStart(sh.in, weekStart)
+		if len(result.Weeks) == 0 || result.Weeks[len(result.Weeks)-1].Start != start {
+			result.Weeks = append(result.Weeks, TimesheetWeek{Start: start})
+			weekRegular = 0
+		}
+		week := &result.Weeks[len(result.Weeks)-1]
+
+		// Time past the daily threshold is daily overtime; what is left of
+		// the shift counts toward the weekly threshold
+		counted := worked
+		if daily > 0 {
+			counted = min(worked, max(daily-dayWorked, 0))
+			if overtime := worked - counted; overtime > 0 {
+				if day.OvertimeFrom == "" {
+					day.OvertimeFrom = sh.in.Add(counted).Format(time.RFC3339)
+				}
+				day.OvertimeMinutes += int(overtime / time.Minute)
+			}
+		}
+		if weekly > 0 {
+			regular := min(counted, max(weekly-weekRegular, 0))
+			if overtime := counted - regular; overtime > 0 {
+				if week.OvertimeFrom == "" {
+					week.OvertimeFrom = sh.in.Add(regular).Format(time.RFC3339)
+				}
+				week.OvertimeMinutes += int(overtime / time.Minute)
+			}
+			weekRegular += regular
+		}
+		dayWorked += worked
+		day.WorkedMinutes += sh.rounded.WorkedMinutes
+		week.WorkedMinutes += sh.rounded.WorkedMinutes
+		result.WorkedMinutes += sh.rounded.WorkedMinutes
+	}
+	for _, day := range result.Days {
+		result.OvertimeMinutes += day.OvertimeMinutes
+	}
+	for _, week := range result.Weeks {
+		result.OvertimeMinutes += week.OvertimeMinutes
+	}
+	result.RegularMinutes = result.WorkedMinutes - result.OvertimeMinutes
+
+	s.logger.Debug("Computed timesheet",
+		slog.Int("shifts", len(result.Shifts)),
+		slog.Int("worked_minutes", result.WorkedMinutes),
+		slog.Int("overtime_minutes", result.OvertimeMinutes))
+
+	return result, nil
+}
+
+// overtimeThreshold parses an overtime threshold, where 0 turns it off
+func overtimeThreshold(field, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold < 0 {
+		return 0, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid %s %q (must be a Go duration such as 8h, or 0 for none)", field, value)
+	}
+	return threshold, nil
+}
+
+// roundPunch reads a punch to the minute and rounds it to an increment of
+// the local clock, so increments stay on the quarter hours in zones with
+// half-hour offsets
+func roundPunch(t time.Time, increment int, rounding string) time.Time {
+	t = t.Add(-time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	past := t.Minute() % increment
+	if past == 0 {
+		return t
+	}
+	if rounding == RoundingUp || (rounding == RoundingNearest && 2*past >= increment) {
+		return t.Add(time.Duration(increment-past) * time.Minute)
+	}
+	return t.Add(-time.Duration(past) * time.Minute)
+}
+
+// workweekStart returns the date of the workweek a local time falls in
+func workweekStart(t time.Time, weekStart time.Weekday) string {
+	back := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-back, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+}
+
+// wallDuration is the time between two local times as the wall clock reads it
+func wallDuration(from, to time.Time) time.Duration {
+	wall := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+	return wall(to).Sub(wall(from))
+}
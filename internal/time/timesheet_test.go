@@ -0,0 +1,157 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_Timesheet(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	shift := func(in, out string) TimesheetShift {
+		return TimesheetShift{In: RFC3339Timestamp(in), Out: RFC3339Timestamp(out)}
+	}
+
+	t.Run("7/8-minute rule and daily overtime", func(t *testing.T) {
+		result, err := service.Timesheet(TimesheetInput{
+			Shifts:        []TimesheetShift{shift("2024-01-08T08:07:59Z", "2024-01-08T16:08:00Z")},
+			DailyOvertime: "8h",
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Shifts, 1)
+		assert.Equal(t, "2024-01-08T08:00:00Z", result.Shifts[0].RoundedIn)
+		assert.Equal(t, "2024-01-08T16:15:00Z", result.Shifts[0].RoundedOut)
+		assert.Equal(t, 495, result.WorkedMinutes)
+		assert.Equal(t, []TimesheetDay{{Date: "2024-01-08", WorkedMinutes: 495, OvertimeMinutes: 15, OvertimeFrom: "2024-01-08T16:00:00Z"}}, result.Days)
+		assert.Equal(t, 480, result.RegularMinutes)
+		assert.Equal(t, "8h0m0s", result.DailyOvertime)
+		assert.Equal(t, "40h0m0s", result.WeeklyOvertime)
+	})
+
+	t.Run("rounding modes", func(t *testing.T) {
+		tests := []struct {
+			rounding  string
+			increment int
+			in, out   string
+		}{
+			{"down", 15, "2024-01-08T08:00:00Z", "2024-01-08T16:00:00Z"},
+			{"up", 15, "2024-01-08T08:15:00Z", "2024-01-08T16:15:00Z"},
+			{"nearest", 6, "2024-01-08T08:06:00Z", "2024-01-08T16:06:00Z"},
+			{"nearest", 1, "2024-01-08T08:07:00Z", "2024-01-08T16:08:00Z"},
+		}
+		for _, tt := range tests {
+			result, err := service.Timesheet(TimesheetInput{
+				Shifts:    []TimesheetShift{shift("2024-01-08T08:07:30Z", "2024-01-08T16:08:00Z")},
+				Rounding:  tt.rounding,
+				Increment: tt.increment,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.in, result.Shifts[0].RoundedIn, tt.rounding)
+			assert.Equal(t, tt.out, result.Shifts[0].RoundedOut, tt.rounding)
+		}
+	})
+
+	t.Run("weekly overtime", func(t *testing.T) {
+		var shifts []TimesheetShift
+		for day := 8; day <= 13; day++ {
+			end := "17:00"
+			if day == 13 {
+				end = "12:00"
+			}
+			date := time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly)
+			shifts = append(shifts, shift(date+"T08:00:00Z", date+"T"+end+":00Z"))
+		}
+		result, err := service.Timesheet(TimesheetInput{Shifts: shifts})
+		require.NoError(t, err)
+		assert.Equal(t, []TimesheetWeek{{Start: "2024-01-08", WorkedMinutes: 49 * 60, OvertimeMinutes: 9 * 60, OvertimeFrom: "2024-01-12T12:00:00Z"}}, result.Weeks)
+		assert.Equal(t, 40*60, result.RegularMinutes)
+
+		// Daily overtime doesn't count toward the weekly threshold
+		result, err = service.Timesheet(TimesheetInput{Shifts: shifts, DailyOvertime: "8h"})
+		require.NoError(t, err)
+		assert.Equal(t, 5*60, result.Days[0].OvertimeMinutes+result.Days[1].OvertimeMinutes+result.Days[2].OvertimeMinutes+
+			result.Days[3].OvertimeMinutes+result.Days[4].OvertimeMinutes)
+		assert.Equal(t, "2024-01-13T08:00:00Z", result.Weeks[0].OvertimeFrom)
+		assert.Equal(t, 4*60, result.Weeks[0].OvertimeMinutes)
+		assert.Equal(t, 9*60, result.OvertimeMinutes)
+	})
+
+	t.Run("workweek start", func(t *testing.T) {
+		result, err := service.Timesheet(TimesheetInput{
+			Shifts:    []TimesheetShift{shift("2024-01-13T08:00:00Z", "2024-01-13T12:00:00Z"), shift("2024-01-14T08:00:00Z", "2024-01-14T12:00:00Z")},
+			WeekStart: "sunday",
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Weeks, 2)
+		assert.Equal(t, "2024-01-07", result.Weeks[0].Start)
+		assert.Equal(t, "2024-01-14", result.Weeks[1].Start)
+	})
+
+	t.Run("night shift across fall back", func(t *testing.T) {
+		result, err := service.Timesheet(TimesheetInput{
+			Shifts:   []TimesheetShift{shift("2024-11-02T22:00:00-04:00", "2024-11-03T06:00:00-05:00")},
+			Timezone: "America/New_York",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 9*60, result.WorkedMinutes)
+		assert.Equal(t, "2024-11-02", result.Shifts[0].Date)
+		require.Len(t, result.Notes, 1)
+		assert.Contains(t, result.Notes[0], "9h0m0s were worked, not the 8h0m0s the clock shows")
+	})
+
+	t.Run("increments follow the local clock", func(t *testing.T) {
+		result, err := service.Timesheet(TimesheetInput{
+			Shifts:   []TimesheetShift{shift("2024-01-08T02:37:00Z", "2024-01-08T10:30:00Z")},
+			Timezone: "Asia/Kolkata",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-08T08:00:00+05:30", result.Shifts[0].RoundedIn)
+		assert.Equal(t, "2024-01-08T16:00:00+05:30", result.Shifts[0].RoundedOut)
+	})
+
+	t.Run("shifts sorted and split by day", func(t *testing.T) {
+		result, err := service.Timesheet(TimesheetInput{
+			Shifts: []TimesheetShift{shift("2024-01-08T13:00:00Z", "2024-01-08T17:00:00Z"), shift("2024-01-08T08:00:00Z", "2024-01-08T12:00:00Z"),
+				shift("2024-01-09T08:00:00Z", "2024-01-09T08:05:00Z")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-08T08:00:00Z", result.Shifts[0].RoundedIn)
+		require.Len(t, result.Days, 2)
+		assert.Equal(t, 480, result.Days[0].WorkedMinutes)
+		require.Len(t, result.Notes, 1)
+		assert.Contains(t, result.Notes[0], "rounds to no time worked")
+	})
+}
+
+func TestTimeService_TimesheetErrors(t *testing.T) {
+	now := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+	shifts := []TimesheetShift{{In: RFC3339Timestamp("2024-01-08T08:00:00Z"), Out: RFC3339Timestamp("2024-01-08T16:00:00Z")}}
+
+	_, err := service.Timesheet(TimesheetInput{})
+	assert.ErrorContains(t, err, "shifts cannot be empty")
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: shifts, Increment: 7})
+	assert.ErrorContains(t, err, "increment must divide an hour")
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: shifts, Rounding: "employer"})
+	assert.ErrorContains(t, err, "must be one of: nearest, down, up")
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: shifts, DailyOvertime: "8 hours"})
+	assert.ErrorContains(t, err, `invalid daily_overtime "8 hours"`)
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: shifts, WeekStart: "someday"})
+	assert.ErrorContains(t, err, "invalid week_start someday")
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: []TimesheetShift{{In: RFC3339Timestamp("2024-01-08T16:00:00Z"), Out: RFC3339Timestamp("2024-01-08T08:00:00Z")}}})
+	assert.ErrorContains(t, err, "shift 1: out must be after in")
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: append(shifts, TimesheetShift{In: RFC3339Timestamp("2024-01-08T15:00:00Z"), Out: RFC3339Timestamp("2024-01-08T18:00:00Z")})})
+	assert.ErrorContains(t, err, "overlap after rounding")
+
+	_, err = service.Timesheet(TimesheetInput{Shifts: []TimesheetShift{{In: RFC3339Timestamp("2024-01-08T08:00:00Z")}}})
+	assert.ErrorContains(t, err, "shift 1: in and out are required")
+}
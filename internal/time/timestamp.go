@@ -0,0 +1,280 @@
+package time
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// TimestampKind identifies how a Timestamp was supplied
+type TimestampKind string
+
+const (
+	TimestampKindEpoch   TimestampKind = "epoch"
+	TimestampKindRFC3339 TimestampKind = "rfc3339"
+	TimestampKindTime    TimestampKind = "time"
+)
+
+// unixEpoch is 1970-01-01T00:00:00Z
+var unixEpoch = time.Unix(0, 0)
+
+// EpochUnit is the unit of an epoch timestamp
+type EpochUnit string
+
+const (
+	EpochSeconds      EpochUnit = "s"
+	EpochMilliseconds EpochUnit = "ms"
+	EpochMicroseconds EpochUnit = "us"
+	EpochNanoseconds  EpochUnit = "ns"
+)
+
+// Timestamp is the union of the timestamp shapes accepted by tools.
+//
+// JSON decoding rules:
+//   - a JSON number is an epoch in seconds (fractions allowed)
+//   - a JSON string of digits, optionally signed, is an epoch in seconds
+//   - any other JSON string is an RFC3339 timestamp
+//   - an object {"epoch": n, "unit": "s|ms|us|ns"} is an epoch in the given unit
+//   - an object {"rfc3339": "..."} is an RFC3339 timestamp
+type Timestamp struct {
+	Kind    TimestampKind
+	Epoch   int64
+	Nanos   int64 // fractional part of a seconds epoch, in nanoseconds
+	Unit    EpochUnit
+	RFC3339 string
+	Time    time.Time
+}
+
+// TimestampFromTime wraps a time.Time value
+func TimestampFromTime(t time.Time) Timestamp {
+	return Timestamp{Kind: TimestampKindTime, Time: t}
+}
+
+// EpochTimestamp creates an epoch timestamp in the given unit
+func EpochTimestamp(epoch int64, unit EpochUnit) Timestamp {
+	return Timestamp{Kind: TimestampKindEpoch, Epoch: epoch, Unit: unit}
+}
+
+// RFC3339Timestamp creates an RFC3339 timestamp
+func RFC3339Timestamp(s string) Timestamp {
+	return Timestamp{Kind: TimestampKindRFC3339, RFC3339: s}
+}
+
+// timestampObject is the explicit object form of a Timestamp
+type timestampObject struct {
+	Epoch   *json.Number `json:"epoch,omitempty"`
+	Unit    EpochUnit    `json:"unit,omitempty"`
+	RFC3339 string       `json:"rfc3339,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler following the rules documented on Timestamp
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*ts = Timestamp{}
+		return nil
+	}
+
+	switch data[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid timestamp string: %w", err)
+		}
+		return ts.fromString(s)
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		dec.DisallowUnknownFields()
+		var obj timestampObject
+		if err := dec.Decode(&obj); err != nil {
+			return timeerrors.Errorf(timeerrors.ErrParseFailure, "invalid timestamp object: %w", err)
+		}
+		return ts.fromObject(obj)
+	default:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return timeerrors.Errorf(timeerrors.ErrParseFailure, "timestamp must be a string, number or object: %w", err)
+		}
+		return ts.fromNumber(n, EpochSeconds)
+	}
+}
+
+// fromString decodes a string timestamp
+func (ts *Timestamp) fromString(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp cannot be empty")
+	}
+	if isEpochString(s) {
+		return ts.fromNumber(json.Number(s), EpochSeconds)
+	}
+	*ts = RFC3339Timestamp(s)
+	return nil
+}
+
+// fromObject decodes the explicit object form
+func (ts *Timestamp) fromObject(obj timestampObject) error {
+	switch {
+	case obj.Epoch != nil && obj.RFC3339 != "":
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp object must set either epoch or rfc3339, not both")
+	case obj.Epoch != nil:
+		unit := obj.Unit
+		if unit == "" {
+			unit = EpochSeconds
+		}
+		return ts.fromNumber(*obj.Epoch, unit)
+	case obj.RFC3339 != "":
+		if obj.Unit != "" {
+			return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp unit only applies to epoch values")
+		}
+		*ts = RFC3339Timestamp(obj.RFC3339)
+		return nil
+	default:
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp object must set epoch or rfc3339")
+	}
+}
+
+// fromNumber decodes an epoch number in the given unit
+func (ts *Timestamp) fromNumber(n json.Number, unit EpochUnit) error {
+	if !isValidEpochUnit(unit) {
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid epoch unit %q (must be one of: s, ms, us, ns)", unit)
+	}
+
+	if isEpochString(n.String()) {
+		i, err := parseEpoch(n.String(), unit)
+		if err != nil {
+			return err
+		}
+		*ts = EpochTimestamp(i, unit)
+		return nil
+	}
+
+	// Fractional epochs are only meaningful in seconds
+	f, err := n.Float64()
+	if err != nil && !errors.Is(err, strconv.ErrRange) || math.IsNaN(f) {
+		return timeerrors.Errorf(timeerrors.ErrOutOfRange, "epoch %s is not a representable number", n.String())
+	}
+	if limits := epochLimits[unit]; math.IsInf(f, 0) || f < float64(limits[0]) || f >= float64(limits[1]) {
+		return epochRangeError(n.String(), unit)
+	}
+	if unit != EpochSeconds {
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "epoch %s must be an integer for unit %s", n.String(), unit)
+	}
+
+	secs, frac := math.Modf(f)
+	*ts = Timestamp{
+		Kind:  TimestampKindEpoch,
+		Epoch: int64(secs),
+		Nanos: int64(math.Round(frac * 1e9)),
+		Unit:  EpochSeconds,
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler using the canonical form for each kind
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	switch ts.Kind {
+	case TimestampKindEpoch:
+		if ts.Unit == EpochSeconds && ts.Nanos == 0 {
+			return json.Marshal(ts.Epoch)
+		}
+		if ts.Nanos != 0 {
+			return json.Marshal(float64(ts.Epoch) + float64(ts.Nanos)/1e9)
+		}
+		return json.Marshal(map[string]any{"epoch": ts.Epoch, "unit": ts.Unit})
+	case TimestampKindRFC3339:
+		return json.Marshal(ts.RFC3339)
+	case TimestampKindTime:
+		return json.Marshal(ts.Time.Format(time.RFC3339Nano))
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// IsZero reports whether no timestamp was supplied
+func (ts Timestamp) IsZero() bool {
+	return ts.Kind == ""
+}
+
+// String returns the timestamp as it was supplied
+func (ts Timestamp) String() string {
+	switch ts.Kind {
+	case TimestampKindEpoch:
+		if ts.Unit == EpochSeconds {
+			if ts.Nanos != 0 {
+				return strconv.FormatFloat(float64(ts.Epoch)+float64(ts.Nanos)/1e9, 'f', -1, 64)
+			}
+			return strconv.FormatInt(ts.Epoch, 10)
+		}
+		return fmt.Sprintf("%d%s", ts.Epoch, ts.Unit)
+	case TimestampKindRFC3339:
+		return ts.RFC3339
+	case TimestampKindTime:
+		return ts.Time.Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
+
+// Resolve converts the timestamp into a time.Time
+func (ts Timestamp) Resolve() (time.Time, error) {
+	switch ts.Kind {
+	case TimestampKindEpoch:
+		switch ts.Unit {
+		case EpochSeconds, "":
+			return time.Unix(ts.Epoch, ts.Nanos), nil
+		case EpochMilliseconds:
+			return time.UnixMilli(ts.Epoch), nil
+		case EpochMicroseconds:
+			return time.UnixMicro(ts.Epoch), nil
+		case EpochNanoseconds:
+			return time.Unix(0, ts.Epoch), nil
+		default:
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid epoch unit %q (must be one of: s, ms, us, ns)", ts.Unit)
+		}
+	case TimestampKindRFC3339:
+		t, err := time.Parse(time.RFC3339Nano, ts.RFC3339)
+		if err != nil {
+			return time.Time{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse timestamp string: %w", err)
+		}
+		return t, nil
+	case TimestampKindTime:
+		return ts.Time, nil
+	default:
+		return time.Time{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp is required")
+	}
+}
+
+// isEpochString reports whether s is an optionally signed run of digits
+func isEpochString(s string) bool {
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+	}
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidEpochUnit checks if an epoch unit is supported
+func isValidEpochUnit(unit EpochUnit) bool {
+	switch unit {
+	case EpochSeconds, EpochMilliseconds, EpochMicroseconds, EpochNanoseconds:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,135 @@
+package time
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		expected Timestamp
+		wantErr  bool
+	}{
+		{"integer number", `1703518245`, EpochTimestamp(1703518245, EpochSeconds), false},
+		{"negative number", `-86400`, EpochTimestamp(-86400, EpochSeconds), false},
+		{"fractional number", `1703518245.5`, Timestamp{Kind: TimestampKindEpoch, Epoch: 1703518245, Nanos: 500000000, Unit: EpochSeconds}, false},
+		{"exponent number", `1.703518245e9`, EpochTimestamp(1703518245, EpochSeconds), false},
+		{"digit string", `"1703518245"`, EpochTimestamp(1703518245, EpochSeconds), false},
+		{"signed digit string", `"-1"`, EpochTimestamp(-1, EpochSeconds), false},
+		{"rfc3339 string", `"2023-12-25T15:30:45Z"`, RFC3339Timestamp("2023-12-25T15:30:45Z"), false},
+		{"epoch object", `{"epoch": 1703518245123, "unit": "ms"}`, EpochTimestamp(1703518245123, EpochMilliseconds), false},
+		{"epoch object default unit", `{"epoch": 1703518245}`, EpochTimestamp(1703518245, EpochSeconds), false},
+		{"rfc3339 object", `{"rfc3339": "2023-12-25T15:30:45Z"}`, RFC3339Timestamp("2023-12-25T15:30:45Z"), false},
+		{"null", `null`, Timestamp{}, false},
+		{"empty string", `""`, Timestamp{}, true},
+		{"overflowing digit string", `"99999999999999999999999"`, Timestamp{}, true},
+		{"overflowing number", `1e300`, Timestamp{}, true},
+		{"fractional milliseconds", `{"epoch": 1.5, "unit": "ms"}`, Timestamp{}, true},
+		{"invalid unit", `{"epoch": 1, "unit": "days"}`, Timestamp{}, true},
+		{"both epoch and rfc3339", `{"epoch": 1, "rfc3339": "2023-12-25T15:30:45Z"}`, Timestamp{}, true},
+		{"unit with rfc3339", `{"rfc3339": "2023-12-25T15:30:45Z", "unit": "ms"}`, Timestamp{}, true},
+		{"empty object", `{}`, Timestamp{}, true},
+		{"unknown object field", `{"seconds": 1}`, Timestamp{}, true},
+		{"array", `[1]`, Timestamp{}, true},
+		{"boolean", `true`, Timestamp{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			err := json.Unmarshal([]byte(tt.json), &ts)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ts)
+		})
+	}
+}
+
+func TestTimestamp_Resolve(t *testing.T) {
+	expected := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ts      Timestamp
+		want    time.Time
+		wantErr bool
+	}{
+		{"seconds", EpochTimestamp(1703518245, EpochSeconds), expected, false},
+		{"milliseconds", EpochTimestamp(1703518245123, EpochMilliseconds), expected.Add(123 * time.Millisecond), false},
+		{"microseconds", EpochTimestamp(1703518245123456, EpochMicroseconds), expected.Add(123456 * time.Microsecond), false},
+		{"nanoseconds", EpochTimestamp(1703518245123456789, EpochNanoseconds), expected.Add(123456789), false},
+		{"fractional seconds", Timestamp{Kind: TimestampKindEpoch, Epoch: 1703518245, Nanos: 500000000, Unit: EpochSeconds}, expected.Add(500 * time.Millisecond), false},
+		{"pre-epoch seconds", EpochTimestamp(-86400, EpochSeconds), time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"rfc3339", RFC3339Timestamp("2023-12-25T15:30:45Z"), expected, false},
+		{"rfc3339 nano", RFC3339Timestamp("2023-12-25T15:30:45.5Z"), expected.Add(500 * time.Millisecond), false},
+		{"time", TimestampFromTime(expected), expected, false},
+		{"invalid rfc3339", RFC3339Timestamp("December 25"), time.Time{}, true},
+		{"invalid unit", EpochTimestamp(1, "days"), time.Time{}, true},
+		{"zero value", Timestamp{}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ts.Resolve()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestTimestamp_MarshalJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		ts       Timestamp
+		expected string
+	}{
+		{EpochTimestamp(1703518245, EpochSeconds), `1703518245`},
+		{EpochTimestamp(1703518245123, EpochMilliseconds), `{"epoch":1703518245123,"unit":"ms"}`},
+		{RFC3339Timestamp("2023-12-25T15:30:45Z"), `"2023-12-25T15:30:45Z"`},
+		{Timestamp{}, `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			data, err := json.Marshal(tt.ts)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+
+			var decoded Timestamp
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.ts, decoded)
+		})
+	}
+}
+
+func TestTimestamp_String(t *testing.T) {
+	assert.Equal(t, "1703518245", EpochTimestamp(1703518245, EpochSeconds).String())
+	assert.Equal(t, "1703518245123ms", EpochTimestamp(1703518245123, EpochMilliseconds).String())
+	assert.Equal(t, "2023-12-25T15:30:45Z", RFC3339Timestamp("2023-12-25T15:30:45Z").String())
+	assert.Equal(t, "", Timestamp{}.String())
+}
+
+func TestFormatTimeInput_UnmarshalJSON(t *testing.T) {
+	var input FormatTimeInput
+	err := json.Unmarshal([]byte(`{"timestamp": {"epoch": 1703518245000, "unit": "ms"}, "format": "Unix"}`), &input)
+
+	require.NoError(t, err)
+	assert.Equal(t, EpochTimestamp(1703518245000, EpochMilliseconds), input.Timestamp)
+	assert.Equal(t, "Unix", input.Format)
+}
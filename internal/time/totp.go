@@ -0,0 +1,91 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// defaultTOTPStep is the RFC 6238 default time step
+const defaultTOTPStep = 30
+
+// maxTOTPStep bounds the time step to a year, far beyond any real TOTP
+// configuration, so the window end stays within a Duration
+const maxTOTPStep = 366 * 24 * 60 * 60
+
+// TOTPWindowInput represents input for computing a TOTP time-step window
+type TOTPWindowInput struct {
+	StepSeconds *int64    `json:"step_seconds,omitempty"` // time step X, defaults to 30
+	T0          int64     `json:"t0,omitempty"`           // Unix time to start counting steps, defaults to 0, within the Unix epoch range
+	At          Timestamp `json:"at,omitempty"`           // instant to evaluate, defaults to now
+}
+
+// TOTPWindowResult represents the TOTP time-step counter and window for an instant
+type TOTPWindowResult struct {
+	At               string `json:"at"`
+	StepSeconds      int64  `json:"step_seconds"`
+	Counter          int64  `json:"counter"`
+	CounterHex       string `json:"counter_hex"` // 8-byte big-endian value fed to the HMAC
+	WindowStart      string `json:"window_start"`
+	WindowEnd        string `json:"window_end"`
+	SecondsElapsed   int64  `json:"seconds_elapsed"`
+	SecondsRemaining int64  `json:"seconds_remaining"`
+}
+
+// TOTPWindow computes the RFC 6238 time-step counter T = floor((now - T0) / X)
+// and the boundaries of its window. No secrets are involved.
+func (s *timeService) TOTPWindow(input TOTPWindowInput) (TOTPWindowResult, error) {
+	step := int64(defaultTOTPStep)
+	if input.StepSeconds != nil {
+		step = *input.StepSeconds
+	}
+	if step <= 0 {
+		return TOTPWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "step_seconds must be positive, got %d", step)
+	}
+	if step > maxTOTPStep {
+		return TOTPWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "step_seconds must be at most %d (a year), got %d", maxTOTPStep, step)
+	}
+
+	// Within the range of Unix epochs, at - t0 and the window bounds can't
+	// overflow
+	if !epochInRange(big.NewInt(input.T0), EpochSeconds) {
+		return TOTPWindowResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "invalid t0: %w", epochRangeError(strconv.FormatInt(input.T0, 10), EpochSeconds))
+	}
+
+	at := s.clock.Now()
+	if !input.At.IsZero() {
+		var err error
+		if at, err = input.At.Resolve(); err != nil {
+			return TOTPWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+
+	elapsed := at.Unix() - input.T0
+	if elapsed < 0 {
+		return TOTPWindowResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "at is before t0 (%d)", input.T0)
+	}
+
+	counter := elapsed / step
+	windowStart := time.Unix(input.T0+counter*step, 0).UTC()
+	windowEnd := windowStart.Add(time.Duration(step) * time.Second)
+	intoWindow := elapsed % step
+
+	s.logger.Debug("Computed TOTP window",
+		slog.Int64("counter", counter),
+		slog.Int64("step_seconds", step))
+
+	return TOTPWindowResult{
+		At:               at.UTC().Format(time.RFC3339Nano),
+		StepSeconds:      step,
+		Counter:          counter,
+		CounterHex:       fmt.Sprintf("%016x", counter),
+		WindowStart:      windowStart.Format(time.RFC3339),
+		WindowEnd:        windowEnd.Format(time.RFC3339),
+		SecondsElapsed:   intoWindow,
+		SecondsRemaining: step - intoWindow,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package time
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_TOTPWindow(t *testing.T) {
+	logger := newTestLogger(t)
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	step := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name        string
+		input       TOTPWindowInput
+		counter     int64
+		counterHex  string
+		windowStart string
+		remaining   int64
+		wantErr     bool
+		errKind     error
+	}{
+		{
+			name:        "defaults to now and 30s steps",
+			input:       TOTPWindowInput{},
+			counter:     56783941,
+			counterHex:  "0000000003627445",
+			windowStart: "2023-12-25T15:30:30Z",
+			remaining:   15,
+		},
+		{
+			// RFC 6238 Appendix B test vector
+			name:        "rfc 6238 test vector",
+			input:       TOTPWindowInput{At: EpochTimestamp(1111111109, EpochSeconds)},
+			counter:     37037036,
+			counterHex:  "00000000023523ec",
+			windowStart: "2005-03-18T01:58:00Z",
+			remaining:   1,
+		},
+		{
+			name:        "custom step and t0",
+			input:       TOTPWindowInput{StepSeconds: step(60), T0: 1703518200, At: RFC3339Timestamp("2023-12-25T15:31:10Z")},
+			counter:     1,
+			counterHex:  "0000000000000001",
+			windowStart: "2023-12-25T15:31:00Z",
+			remaining:   50,
+		},
+		{
+			name:        "earliest t0",
+			input:       TOTPWindowInput{T0: math.MinInt64 / 1000},
+			counter:     307445791345767,
+			counterHex:  "0001179ecd2e4c67",
+			windowStart: "2023-12-25T15:30:35Z",
+			remaining:   20,
+		},
+		{
+			name:    "t0 before the epoch range",
+			input:   TOTPWindowInput{T0: math.MinInt64/1000 - 1},
+			wantErr: true,
+			errKind: timeerrors.ErrOutOfRange,
+		},
+		{
+			// at - t0 would wrap around to a positive elapsed time
+			name:    "t0 at the int64 minimum",
+			input:   TOTPWindowInput{T0: math.MinInt64},
+			wantErr: true,
+			errKind: timeerrors.ErrOutOfRange,
+		},
+		{
+			name:    "t0 past the epoch range",
+			input:   TOTPWindowInput{T0: math.MaxInt64},
+			wantErr: true,
+			errKind: timeerrors.ErrOutOfRange,
+		},
+		{
+			name:    "zero step",
+			input:   TOTPWindowInput{StepSeconds: step(0)},
+			wantErr: true,
+		},
+		{
+			name:    "step over a year",
+			input:   TOTPWindowInput{StepSeconds: step(math.MaxInt64 / 1000)},
+			wantErr: true,
+		},
+		{
+			name:    "before t0",
+			input:   TOTPWindowInput{T0: now.Unix() + 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.TOTPWindow(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errKind != nil {
+					assert.ErrorIs(t, err, tt.errKind)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.counter, result.Counter)
+			assert.Equal(t, tt.counterHex, result.CounterHex)
+			assert.Equal(t, tt.windowStart, result.WindowStart)
+			assert.Equal(t, tt.remaining, result.SecondsRemaining)
+			assert.Equal(t, result.StepSeconds-tt.remaining, result.SecondsElapsed)
+		})
+	}
+}
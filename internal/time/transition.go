@@ -0,0 +1,52 @@
+package time
+
+import (
+	"time"
+)
+
+// Transition kinds reported in next_transition.kind
+const (
+	// TransitionKindDST is a seasonal move into or out of daylight saving time
+	TransitionKindDST = "dst_transition"
+	// TransitionKindStandardOffset is a permanent change that isn't DST, such
+	// as a zone moving its standard offset or abolishing DST
+	TransitionKindStandardOffset = "standard_offset_change"
+)
+
+// Transition types reported in next_transition.transition_type
+const (
+	TransitionTypeEnterDST             = "enter_dst"
+	TransitionTypeExitDST              = "exit_dst"
+	TransitionTypeStandardOffsetChange = "standard_offset_change"
+)
+
+// zoneState is what a transition changes: the UTC offset and tzdata's isdst flag
+type zoneState struct {
+	offset int
+	dst    bool
+}
+
+// zoneStateAt returns the zone state in effect at t
+func zoneStateAt(t time.Time, loc *time.Location) zoneState {
+	t = t.In(loc)
+	_, offset := t.Zone()
+	return zoneState{offset: offset, dst: t.IsDST()}
+}
+
+// classifyTransition names a change between two zone states. Seasonal
+// changes keep their offset-based enter/exit label, which also holds for
+// zones with negative DST such as Europe/Dublin. A change between two
+// standard offsets, or one that clears the DST flag without moving the wall
+// clock (DST made permanent), is a standard offset change
+func classifyTransition(before, after zoneState) (kind, transitionType string) {
+	standard := !before.dst && !after.dst
+	permanent := before.dst != after.dst && before.offset == after.offset
+	if standard || permanent {
+		return TransitionKindStandardOffset, TransitionTypeStandardOffsetChange
+	}
+
+	if after.offset < before.offset {
+		return TransitionKindDST, TransitionTypeExitDST
+	}
+	return TransitionKindDST, TransitionTypeEnterDST
+}
@@ -0,0 +1,91 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyTransition(t *testing.T) {
+	tests := []struct {
+		name           string
+		before, after  zoneState
+		kind           string
+		transitionType string
+	}{
+		{"spring forward", zoneState{-18000, false}, zoneState{-14400, true}, TransitionKindDST, TransitionTypeEnterDST},
+		{"fall back", zoneState{-14400, true}, zoneState{-18000, false}, TransitionKindDST, TransitionTypeExitDST},
+		{"negative DST ends in spring", zoneState{0, true}, zoneState{3600, false}, TransitionKindDST, TransitionTypeEnterDST},
+		{"standard offset moves", zoneState{32400, false}, zoneState{30600, false}, TransitionKindStandardOffset, TransitionTypeStandardOffsetChange},
+		{"DST made permanent", zoneState{10800, true}, zoneState{10800, false}, TransitionKindStandardOffset, TransitionTypeStandardOffsetChange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, transitionType := classifyTransition(tt.before, tt.after)
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.transitionType, transitionType)
+		})
+	}
+}
+
+func TestTimeService_GetTimezoneInfo_TransitionKind(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithDSTLookahead(1000))
+
+	tests := []struct {
+		name           string
+		timezone       string
+		reference      time.Time
+		kind           string
+		transitionType string
+		offsetChange   int
+	}{
+		{
+			name:           "seasonal DST",
+			timezone:       "America/New_York",
+			reference:      time.Date(2023, time.December, 25, 15, 30, 45, 0, time.UTC),
+			kind:           TransitionKindDST,
+			transitionType: TransitionTypeEnterDST,
+			offsetChange:   3600,
+		},
+		{
+			name:           "negative DST keeps its seasonal label",
+			timezone:       "Europe/Dublin",
+			reference:      time.Date(2023, time.December, 25, 15, 30, 45, 0, time.UTC),
+			kind:           TransitionKindDST,
+			transitionType: TransitionTypeEnterDST,
+			offsetChange:   3600,
+		},
+		{
+			name:           "standard offset moved",
+			timezone:       "Asia/Pyongyang",
+			reference:      time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC),
+			kind:           TransitionKindStandardOffset,
+			transitionType: TransitionTypeStandardOffsetChange,
+			offsetChange:   1800,
+		},
+		{
+			// Turkey stayed on summer time in September 2016 and made it standard
+			name:           "DST abolished without moving the clock",
+			timezone:       "Europe/Istanbul",
+			reference:      time.Date(2016, time.August, 1, 0, 0, 0, 0, time.UTC),
+			kind:           TransitionKindStandardOffset,
+			transitionType: TransitionTypeStandardOffsetChange,
+			offsetChange:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := service.GetTimezoneInfo(TimezoneInfoInput{Timezone: tt.timezone, ReferenceTime: tt.reference})
+			require.NoError(t, err)
+			require.NotNil(t, info.NextTransition)
+
+			assert.Equal(t, tt.kind, info.NextTransition.Kind)
+			assert.Equal(t, tt.transitionType, info.NextTransition.TransitionType)
+			assert.Equal(t, tt.offsetChange, info.NextTransition.OffsetChange)
+		})
+	}
+}
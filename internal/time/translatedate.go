@@ -0,0 +1,311 @@
+package time
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// maxTranslateDates bounds the dates of one translate_date call
+const maxTranslateDates = 10000
+
+// Field orders of numeric dates
+const (
+	dateOrderMDY = "MDY"
+	dateOrderDMY = "DMY"
+	dateOrderYMD = "YMD"
+)
+
+// dateConvention is how a locale writes a numeric date
+type dateConvention struct {
+	order     string
+	separator string
+	// suffix follows the last field, as in Korean "2025. 12. 03."
+	suffix string
+}
+
+// pattern writes the convention as e.g. "DD.MM.YYYY"
+func (c dateConvention) pattern() string {
+	return c.format("YYYY", "MM", "DD")
+}
+
+// format joins the year, month and day in the convention's order
+func (c dateConvention) format(year, month, day string) string {
+	fields := map[byte]string{'Y': year, 'M': month, 'D': day}
+	parts := []string{fields[c.order[0]], fields[c.order[1]], fields[c.order[2]]}
+	return strings.Join(parts, c.separator) + c.suffix
+}
+
+// swapped returns the convention with day and month exchanged, the usual
+// misreading of a numeric date
+func (c dateConvention) swapped() dateConvention {
+	switch c.order {
+	case dateOrderMDY:
+		c.order = dateOrderDMY
+	case dateOrderDMY:
+		c.order = dateOrderMDY
+	default:
+		return c
+	}
+	return c
+}
+
+// dateConventions are the numeric date conventions translate_date knows,
+// keyed by lowercase locale. Tags without an entry fall back to their
+// language, e.g. de-AT to de
+var dateConventions = map[string]dateConvention{
+	"iso":   {order: dateOrderYMD, separator: "-"},
+	"en":    {order: dateOrderMDY, separator: "/"},
+	"en-us": {order: dateOrderMDY, separator: "/"},
+	"en-gb": {order: dateOrderDMY, separator: "/"},
+	"en-au": {order: dateOrderDMY, separator: "/"},
+	"en-in": {order: dateOrderDMY, separator: "/"},
+	"en-ca": {order: dateOrderYMD, separator: "-"},
+	"de":    {order: dateOrderDMY, separator: "."},
+	"fr":    {order: dateOrderDMY, separator: "/"},
+	"es":    {order: dateOrderDMY, separator: "/"},
+	"it":    {order: dateOrderDMY, separator: "/"},
+	"pt":    {order: dateOrderDMY, separator: "/"},
+	"pt-br": {order: dateOrderDMY, separator: "/"},
+	"nl":    {order: dateOrderDMY, separator: "-"},
+	"ru":    {order: dateOrderDMY, separator: "."},
+	"pl":    {order: dateOrderDMY, separator: "."},
+	"sv":    {order: dateOrderYMD, separator: "-"},
+	"ja":    {order: dateOrderYMD, separator: "/"},
+	"zh":    {order: dateOrderYMD, separator: "/"},
+	"ko":    {order: dateOrderYMD, separator: ". ", suffix: "."},
+}
+
+// TranslateDateInput represents numeric dates to rewrite from one locale's
+// convention to another's
+type TranslateDateInput struct {
+	Dates []string `json:"dates"`
+	// FromLocale and ToLocale are locale tags such as "en-US", "de" or "iso"
+	FromLocale string `json:"from_locale"`
+	ToLocale   string `json:"to_locale"`
+	// TwoDigitYearPivot overrides the configured pivot for two-digit years
+	TwoDigitYearPivot *int `json:"two_digit_year_pivot,omitempty"`
+}
+
+// TranslatedDate is one date in the target convention, or why it couldn't
+// be read. Ambiguous marks dates whose day and month could be swapped,
+// with Alternative the ISO date of that other reading
+type TranslatedDate struct {
+	Input        string            `json:"input"`
+	Output       string            `json:"output,omitempty"`
+	ISO          string            `json:"iso,omitempty"`
+	Ambiguous    bool              `json:"ambiguous"`
+	Alternative  string            `json:"alternative,omitempty"`
+	TwoDigitYear *TwoDigitYearInfo `json:"two_digit_year,omitempty"`
+	Notes        []string          `json:"notes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// TranslateDateResult represents dates rewritten between conventions
+type TranslateDateResult struct {
+	FromLocale  string           `json:"from_locale"`
+	ToLocale    string           `json:"to_locale"`
+	FromPattern string           `json:"from_pattern"`
+	ToPattern   string           `json:"to_pattern"`
+	Translated  int              `json:"translated"`
+	Ambiguous   int              `json:"ambiguous"`
+	Failed      int              `json:"failed"`
+	Dates       []TranslatedDate `json:"dates"`
+}
+
+// TranslateDate rewrites numeric dates from one locale's convention to
+// another's, reporting errors per date rather than failing the whole call
+func (s *timeService) TranslateDate(input TranslateDateInput) (TranslateDateResult, error) {
+	if len(input.Dates) == 0 {
+		return TranslateDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "dates cannot be empty")
+	}
+	if len(input.Dates) > maxTranslateDates {
+		return TranslateDateResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "dates cannot have more than %d entries", maxTranslateDates)
+	}
+
+	if err := s.checkBulkBudget("translate_date", int64(len(input.Dates)), translatedDateBytes); err != nil {
+		return TranslateDateResult{}, err
+	}
+
+	fromLocale, from, err := lookupDateConvention(input.FromLocale, "from_locale")
+	if err != nil {
+		return TranslateDateResult{}, err
+	}
+	toLocale, to, err := lookupDateConvention(input.ToLocale, "to_locale")
+	if err != nil {
+		return TranslateDateResult{}, err
+	}
+
+	pivot := s.twoDigitYearPivot
+	if input.TwoDigitYearPivot != nil {
+		pivot = *input.TwoDigitYearPivot
+	}
+	if err := validateTwoDigitYearPivot(pivot); err != nil {
+		return TranslateDateResult{}, err
+	}
+
+	result := TranslateDateResult{
+		FromLocale:  fromLocale,
+		ToLocale:    toLocale,
+		FromPattern: from.pattern(),
+		ToPattern:   to.pattern(),
+		Dates:       make([]TranslatedDate, 0, len(input.Dates)),
+	}
+	for _, value := range input.Dates {
+		translated := translateDate(strings.TrimSpace(value), from, to, pivot)
+		switch {
+		case translated.Error != "":
+			result.Failed++
+		case translated.Ambiguous:
+			result.Ambiguous++
+			result.Translated++
+		default:
+			result.Translated++
+		}
+		result.Dates = append(result.Dates, translated)
+	}
+
+	return result, nil
+}
+
+// lookupDateConvention resolves a locale tag to its convention, returning
+// the tag the convention is keyed by
+func lookupDateConvention(tag, field string) (string, dateConvention, error) {
+	key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if convention, ok := dateConventions[key]; ok {
+		return key, convention, nil
+	}
+	if language, _, found := strings.Cut(key, "-"); found {
+		if convention, ok := dateConventions[language]; ok {
+			return language, convention, nil
+		}
+	}
+
+	known := make([]string, 0, len(dateConventions))
+	for name := range dateConventions {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+	if key == "" {
+		return "", dateConvention{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "%s is required (supported: %s)", field, strings.Join(known, ", "))
+	}
+	return "", dateConvention{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "unsupported %s %q (supported: %s)", field, tag, strings.Join(known, ", "))
+}
+
+// translateDate reads value in the from convention and writes it in the to
+// convention
+func translateDate(value string, from, to dateConvention, pivot int) TranslatedDate {
+	translated := TranslatedDate{Input: value}
+
+	fields, separators := splitNumericDate(value)
+	if len(fields) != 3 {
+		translated.Error = fmt.Sprintf("expected a numeric date like %s", from.pattern())
+		return translated
+	}
+	for _, separator := range separators {
+		if separator != strings.TrimSpace(from.separator) {
+			translated.Notes = append(translated.Notes, fmt.Sprintf("separators differ from %s; read by its field order", from.pattern()))
+			break
+		}
+	}
+
+	date, info, err := readNumericDate(fields, from.order, pivot)
+	if err != nil {
+		translated.Error = err.Error()
+		if other := from.swapped(); other != from {
+			if swapped, _, swapErr := readNumericDate(fields, other.order, pivot); swapErr == nil {
+				translated.Error += fmt.Sprintf("; as %s it would be %s", other.pattern(), swapped.Format(time.DateOnly))
+			}
+		}
+		return translated
+	}
+	translated.TwoDigitYear = info
+
+	translated.ISO = date.Format(time.DateOnly)
+	translated.Output = to.format(fmt.Sprintf("%04d", date.Year()), fmt.Sprintf("%02d", int(date.Month())), fmt.Sprintf("%02d", date.Day()))
+
+	if other := from.swapped(); other != from && date.Day() != int(date.Month()) {
+		if swapped, _, err := readNumericDate(fields, other.order, pivot); err == nil {
+			translated.Ambiguous = true
+			translated.Alternative = swapped.Format(time.DateOnly)
+			translated.Notes = append(translated.Notes, fmt.Sprintf("read as %s it would be %s", other.pattern(), translated.Alternative))
+		}
+	}
+
+	return translated
+}
+
+// splitNumericDate returns the digit runs of value and the trimmed
+// separators between them. A trailing separator, as in "2025. 12. 03.", is
+// dropped
+func splitNumericDate(value string) (fields, separators []string) {
+	start := -1
+	sepStart := 0
+	for i, r := range value + " " {
+		digit := unicode.IsDigit(r) && r < unicode.MaxASCII
+		switch {
+		case digit && start < 0:
+			if len(fields) > 0 {
+				separators = append(separators, strings.TrimSpace(value[sepStart:i]))
+			} else if strings.TrimSpace(value[:i]) != "" {
+				// Text before the first number isn't a numeric date
+				return nil, nil
+			}
+			start = i
+		case !digit && start >= 0:
+			fields = append(fields, value[start:i])
+			start = -1
+			sepStart = i
+		}
+	}
+	if len(fields) > 0 {
+		rest := strings.TrimSpace(value[sepStart:])
+		if rest != "" && rest != "." {
+			return nil, nil
+		}
+	}
+	return fields, separators
+}
+
+// readNumericDate reads three digit runs in the given field order. Years
+// have four digits, or two expanded with pivot
+func readNumericDate(fields []string, order string, pivot int) (time.Time, *TwoDigitYearInfo, error) {
+	values := make(map[byte]string, 3)
+	for i := range fields {
+		values[order[i]] = fields[i]
+	}
+
+	yearText := values['Y']
+	if len(yearText) != 4 && len(yearText) != 2 {
+		return time.Time{}, nil, fmt.Errorf("year %s must have 4 digits, or 2", yearText)
+	}
+	year, _ := strconv.Atoi(yearText)
+	var info *TwoDigitYearInfo
+	if len(yearText) == 2 {
+		century := 1900
+		if year < pivot {
+			century = 2000
+		}
+		info = &TwoDigitYearInfo{Input: year, Pivot: pivot, Century: century}
+		year += century
+	}
+	if year == 0 {
+		return time.Time{}, nil, fmt.Errorf("year 0000 does not exist")
+	}
+
+	month, _ := strconv.Atoi(values['M'])
+	if len(values['M']) > 2 || month < 1 || month > 12 {
+		return time.Time{}, nil, fmt.Errorf("month %s is out of range", values['M'])
+	}
+	day, _ := strconv.Atoi(values['D'])
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if len(values['D']) > 2 || day < 1 || date.Day() != day {
+		return time.Time{}, nil, fmt.Errorf("%s %s does not exist in %d", time.Month(month), values['D'], year)
+	}
+	return date, info, nil
+}
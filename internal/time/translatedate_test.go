@@ -0,0 +1,73 @@
+package time
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_TranslateDate(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+
+	result, err := service.TranslateDate(TranslateDateInput{
+		Dates:      []string{"12/03/2025", "12/25/2025", "25/12/2025", "7/4/76", "03.04.2025", "not a date", "02/30/2024"},
+		FromLocale: "en-US",
+		ToLocale:   "de-AT",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "en-us", result.FromLocale)
+	assert.Equal(t, "de", result.ToLocale)
+	assert.Equal(t, "MM/DD/YYYY", result.FromPattern)
+	assert.Equal(t, "DD.MM.YYYY", result.ToPattern)
+	assert.Equal(t, 4, result.Translated)
+	assert.Equal(t, 3, result.Ambiguous)
+	assert.Equal(t, 3, result.Failed)
+
+	dates := result.Dates
+	assert.Equal(t, TranslatedDate{
+		Input: "12/03/2025", Output: "03.12.2025", ISO: "2025-12-03",
+		Ambiguous: true, Alternative: "2025-03-12",
+		Notes: []string{"read as DD/MM/YYYY it would be 2025-03-12"},
+	}, dates[0])
+	assert.Equal(t, TranslatedDate{Input: "12/25/2025", Output: "25.12.2025", ISO: "2025-12-25"}, dates[1])
+	assert.Equal(t, "month 25 is out of range; as DD/MM/YYYY it would be 2025-12-25", dates[2].Error)
+	assert.Equal(t, "1976-07-04", dates[3].ISO)
+	assert.Equal(t, &TwoDigitYearInfo{Input: 76, Pivot: 69, Century: 1900}, dates[3].TwoDigitYear)
+	assert.Contains(t, dates[4].Notes, "separators differ from MM/DD/YYYY; read by its field order")
+	assert.Equal(t, "expected a numeric date like MM/DD/YYYY", dates[5].Error)
+	assert.Equal(t, "February 30 does not exist in 2024", dates[6].Error)
+}
+
+func TestTimeService_TranslateDate_Conventions(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+
+	tests := []struct {
+		from, to, date, want string
+	}{
+		{"iso", "en-US", "2025-12-03", "12/03/2025"},
+		{"de", "iso", "03.12.2025", "2025-12-03"},
+		{"ko", "ja", "2025. 12. 03.", "2025/12/03"},
+		{"iso", "ko", "2025-12-03", "2025. 12. 03."},
+		{"pt_BR", "nl", "3/12/2025", "03-12-2025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			result, err := service.TranslateDate(TranslateDateInput{Dates: []string{tt.date}, FromLocale: tt.from, ToLocale: tt.to})
+			require.NoError(t, err)
+			require.Empty(t, result.Dates[0].Error)
+			assert.Equal(t, tt.want, result.Dates[0].Output)
+		})
+	}
+
+	for _, input := range []TranslateDateInput{
+		{FromLocale: "en-US", ToLocale: "de"},
+		{Dates: []string{"12/03/2025"}, ToLocale: "de"},
+		{Dates: []string{"12/03/2025"}, FromLocale: "en-US", ToLocale: "tlh"},
+	} {
+		_, err := service.TranslateDate(input)
+		assert.ErrorIs(t, err, timeerrors.ErrInvalidArgument)
+	}
+}
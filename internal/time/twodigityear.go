@@ -0,0 +1,68 @@
+package time
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// goTwoDigitYearPivot is the pivot Go's time package uses for "06" years:
+// 69-99 become 1969-1999 and 00-68 become 2000-2068
+const goTwoDigitYearPivot = 69
+
+// TwoDigitYearInfo reports how a two-digit year was expanded
+type TwoDigitYearInfo struct {
+	Input   int `json:"input"`   // the two-digit year as written
+	Pivot   int `json:"pivot"`   // years below the pivot are in the 2000s, the rest in the 1900s
+	Century int `json:"century"` // 1900 or 2000
+}
+
+// WithTwoDigitYearPivot sets the default pivot for layouts with two-digit years
+func WithTwoDigitYearPivot(pivot int) Option {
+	return func(s *timeService) {
+		s.twoDigitYearPivot = pivot
+	}
+}
+
+// hasTwoDigitYear reports whether a Go layout contains the two-digit year
+// token. Named formats aren't layouts, whatever their names contain
+func hasTwoDigitYear(layout string) bool {
+	if IsValidFormat(layout) {
+		return false
+	}
+	return strings.Contains(strings.ReplaceAll(layout, "2006", ""), "06")
+}
+
+// applyTwoDigitYearPivot re-expands a year that Go parsed from a two-digit
+// year using the given pivot, keeping the rest of the wall clock
+func applyTwoDigitYearPivot(t time.Time, pivot int) (time.Time, TwoDigitYearInfo, error) {
+	yy := t.Year() % 100
+	century := 1900
+	if yy < pivot {
+		century = 2000
+	}
+	info := TwoDigitYearInfo{Input: yy, Pivot: pivot, Century: century}
+
+	year := century + yy
+	if year == t.Year() {
+		return t, info, nil
+	}
+
+	month, day := t.Month(), t.Day()
+	hour, minute, second := t.Clock()
+	adjusted := time.Date(year, month, day, hour, minute, second, t.Nanosecond(), t.Location())
+	if adjusted.Day() != day {
+		return time.Time{}, TwoDigitYearInfo{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "%s %d does not exist in %d", month, day, year)
+	}
+
+	return adjusted, info, nil
+}
+
+// validateTwoDigitYearPivot checks a pivot is between 0 and 100
+func validateTwoDigitYearPivot(pivot int) error {
+	if pivot < 0 || pivot > 100 {
+		return timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid two_digit_year_pivot %d (must be between 0 and 100)", pivot)
+	}
+	return nil
+}
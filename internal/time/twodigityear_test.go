@@ -0,0 +1,109 @@
+package time
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasTwoDigitYear(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected bool
+	}{
+		{"01/02/06", true},
+		{"02-Jan-06 15:04", true},
+		{"2006-01-02", false},
+		{"RFC3339", false},
+		{"Unix", false},
+		{"GPSWeek", false},
+		{"DayKiloseconds", false},
+		{"Jan 2 15:04:05", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hasTwoDigitYear(tt.layout))
+		})
+	}
+}
+
+func TestTimeService_ParseTime_TwoDigitYearPivot(t *testing.T) {
+	logger := newTestLogger(t)
+	goDefault := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+	pivot50 := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithTwoDigitYearPivot(50))
+
+	override := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		service  TimeService
+		input    ParseTimeInput
+		rfc3339  string
+		expected *TwoDigitYearInfo
+		wantErr  bool
+	}{
+		{
+			name:     "go default pivot",
+			service:  goDefault,
+			input:    ParseTimeInput{TimeString: "07/04/69", Format: "01/02/06"},
+			rfc3339:  "1969-07-04T00:00:00Z",
+			expected: &TwoDigitYearInfo{Input: 69, Pivot: 69, Century: 1900},
+		},
+		{
+			name:     "configured pivot moves 55 to the 1900s",
+			service:  pivot50,
+			input:    ParseTimeInput{TimeString: "07/04/55", Format: "01/02/06"},
+			rfc3339:  "1955-07-04T00:00:00Z",
+			expected: &TwoDigitYearInfo{Input: 55, Pivot: 50, Century: 1900},
+		},
+		{
+			name:     "configured pivot keeps 49 in the 2000s",
+			service:  pivot50,
+			input:    ParseTimeInput{TimeString: "07/04/49", Format: "01/02/06"},
+			rfc3339:  "2049-07-04T00:00:00Z",
+			expected: &TwoDigitYearInfo{Input: 49, Pivot: 50, Century: 2000},
+		},
+		{
+			name:     "request override",
+			service:  pivot50,
+			input:    ParseTimeInput{TimeString: "07/04/75", Format: "01/02/06", TwoDigitYearPivot: override(100)},
+			rfc3339:  "2075-07-04T00:00:00Z",
+			expected: &TwoDigitYearInfo{Input: 75, Pivot: 100, Century: 2000},
+		},
+		{
+			name:    "four digit years are not reported",
+			service: pivot50,
+			input:   ParseTimeInput{TimeString: "2055-07-04", Format: "2006-01-02"},
+			rfc3339: "2055-07-04T00:00:00Z",
+		},
+		{
+			name:    "leap day that does not exist in the pivoted century",
+			service: goDefault,
+			input:   ParseTimeInput{TimeString: "02/29/00", Format: "01/02/06", TwoDigitYearPivot: override(0)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pivot",
+			service: goDefault,
+			input:   ParseTimeInput{TimeString: "07/04/69", Format: "01/02/06", TwoDigitYearPivot: override(101)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.service.ParseTime(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.rfc3339, result.RFC3339)
+			assert.Equal(t, tt.expected, result.TwoDigitYear)
+		})
+	}
+}
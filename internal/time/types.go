@@ -6,13 +6,20 @@ import (
 
 // TimezoneInfo contains information about a timezone
 type TimezoneInfo struct {
-	Name          string             `json:"name"`
-	Abbreviation  string             `json:"abbreviation"`
-	Offset        string             `json:"offset"`
-	OffsetSeconds int                `json:"offset_seconds"`
-	IsDST         bool               `json:"is_dst"`
-	DST           *DSTInfo           `json:"dst,omitempty"`
-	DSTTransition *DSTTransitionInfo `json:"dst_transition,omitempty"` // Keep for backward compatibility
+	Name          string   `json:"name"`
+	Abbreviation  string   `json:"abbreviation"`
+	Offset        string   `json:"offset"`
+	OffsetSeconds int      `json:"offset_seconds"`
+	IsDST         bool     `json:"is_dst"`
+	HasDST        bool     `json:"has_dst"` // tzdata marks DST within the horizon either side
+	DST           *DSTInfo `json:"dst,omitempty"`
+	// NextTransition is the next offset change, DST or not. It was named
+	// dst_transition, which compat.emit_legacy_fields still emits
+	NextTransition *DSTTransitionInfo `json:"next_transition,omitempty"`
+	// TransitionStatus is "scheduled" when a transition was found within
+	// LookaheadDays, or "none_within_horizon"
+	TransitionStatus string `json:"transition_status"`
+	LookaheadDays    int    `json:"lookahead_days"`
 }
 
 // DSTInfo contains DST period information
@@ -25,7 +32,8 @@ type DSTInfo struct {
 // DSTTransitionInfo contains information about DST transitions
 type DSTTransitionInfo struct {
 	NextTransition time.Time `json:"next_transition"`
-	TransitionType string    `json:"transition_type"` // "enter_dst" or "exit_dst"
+	TransitionType string    `json:"transition_type"` // "enter_dst", "exit_dst" or "standard_offset_change"
+	Kind           string    `json:"kind"`            // "dst_transition" or "standard_offset_change"
 	OffsetChange   int       `json:"offset_change"`   // seconds
 }
 
@@ -40,12 +48,34 @@ const (
 	FormatUnixMicro   FormatType = "UnixMicro"
 	FormatUnixNano    FormatType = "UnixNano"
 	FormatLayout      FormatType = "Layout"
+	// Day numbers: days since 1970-01-01, ISO 8601 ordinal dates (YYYY-DDD)
+	// and Rata Die numbers, day 1 being 0001-01-01
+	FormatEpochDay FormatType = "EpochDay"
+	FormatOrdinal  FormatType = "Ordinal"
+	FormatRataDie  FormatType = "RataDie"
+	// Database text: MySQL DATETIME, the Postgres text output of timestamptz
+	// and SQLite julianday values
+	FormatMySQLDateTime       FormatType = "MySQLDateTime"
+	FormatPostgresTimestampTZ FormatType = "PostgresTimestamptz"
+	FormatJulianDay           FormatType = "JulianDay"
+	// Satellite time: GPS seconds, GPS week and seconds of the week, and
+	// the epoch of two-line element sets
+	FormatGPSSeconds FormatType = "GPSSeconds"
+	FormatGPSWeek    FormatType = "GPSWeek"
+	FormatTLEEpoch   FormatType = "TLEEpoch"
+	// Clock formats: Swatch Internet Time beats, French decimal time and
+	// kiloseconds of the UTC day
+	FormatSwatchBeats    FormatType = "SwatchBeats"
+	FormatDecimalTime    FormatType = "DecimalTime"
+	FormatDayKiloseconds FormatType = "DayKiloseconds"
 )
 
 // IsValidFormat checks if a format type is supported
 func IsValidFormat(format string) bool {
 	switch FormatType(format) {
-	case FormatRFC3339, FormatRFC3339Nano, FormatUnix, FormatUnixMilli, FormatUnixMicro, FormatUnixNano, FormatLayout:
+	case FormatRFC3339, FormatRFC3339Nano, FormatUnix, FormatUnixMilli, FormatUnixMicro, FormatUnixNano, FormatLayout,
+		FormatEpochDay, FormatOrdinal, FormatRataDie, FormatMySQLDateTime, FormatPostgresTimestampTZ, FormatJulianDay,
+		FormatGPSSeconds, FormatGPSWeek, FormatTLEEpoch, FormatSwatchBeats, FormatDecimalTime, FormatDayKiloseconds:
 		return true
 	default:
 		return false
@@ -64,40 +94,123 @@ func GetFormatLayout(format FormatType) string {
 	}
 }
 
+// TimezoneHandling controls how ParseTime applies the requested timezone
+type TimezoneHandling string
+
+const (
+	// TimezoneHandlingAssume interprets strings without an offset as wall-clock
+	// time in the requested timezone; strings with an offset are converted
+	TimezoneHandlingAssume TimezoneHandling = "assume"
+	// TimezoneHandlingConvert interprets strings without an offset as UTC and
+	// converts the instant to the requested timezone
+	TimezoneHandlingConvert TimezoneHandling = "convert"
+	// TimezoneHandlingRequireExplicit rejects strings without an offset
+	TimezoneHandlingRequireExplicit TimezoneHandling = "require_explicit"
+)
+
+// IsValidTimezoneHandling checks if a timezone handling mode is supported
+func IsValidTimezoneHandling(mode string) bool {
+	switch TimezoneHandling(mode) {
+	case TimezoneHandlingAssume, TimezoneHandlingConvert, TimezoneHandlingRequireExplicit:
+		return true
+	default:
+		return false
+	}
+}
+
 // ParseTimeInput represents input for parsing time strings
 type ParseTimeInput struct {
-	TimeString string `json:"time_string"`
-	Format     string `json:"format,omitempty"`
-	Timezone   string `json:"timezone,omitempty"`
+	TimeString       string `json:"time_string"`
+	Format           string `json:"format,omitempty"`
+	Timezone         string `json:"timezone,omitempty"`
+	TimezoneHandling string `json:"timezone_handling,omitempty"` // assume (default), convert or require_explicit
+	// TwoDigitYearPivot overrides the configured pivot for layouts with "06" years
+	TwoDigitYearPivot *int `json:"two_digit_year_pivot,omitempty"`
+	// Calendar is gregorian (proleptic, default), julian or auto (Julian before the cutover)
+	Calendar string `json:"calendar,omitempty"`
 }
 
 // FormatTimeInput represents input for formatting time
 type FormatTimeInput struct {
-	Timestamp interface{} `json:"timestamp"` // can be string, int, or time.Time
-	Format    string      `json:"format"`
-	Timezone  string      `json:"timezone,omitempty"`
+	Timestamp Timestamp `json:"timestamp"` // see Timestamp for accepted JSON shapes
+	Format    string    `json:"format"`
+	Timezone  string    `json:"timezone,omitempty"`
+	// EpochAsNumber also returns integer formats such as Unix as a number
+	EpochAsNumber bool `json:"epoch_as_number,omitempty"`
+	// DigitGrouping separates digit groups of integer formats in the text
+	// rendering, one of DigitSeparators
+	DigitGrouping string `json:"digit_grouping,omitempty"`
+	// SpellOut and RomanYear add the date written out for formal documents,
+	// in Locale: the day and year in words, or the year in roman numerals
+	SpellOut  bool   `json:"spell_out,omitempty"`
+	RomanYear bool   `json:"roman_year,omitempty"`
+	Locale    string `json:"locale,omitempty"`
 }
 
 // GetTimeInput represents input for getting current time
 type GetTimeInput struct {
-	Timezone string `json:"timezone,omitempty"`
-	Format   string `json:"format,omitempty"`
+	Timezone  string   `json:"timezone,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	Formats   []string `json:"formats,omitempty"`   // additional formats rendered from the same instant
+	Precision string   `json:"precision,omitempty"` // second, milli, micro or nano
+	// EpochAsNumber also returns integer formats such as Unix as numbers
+	EpochAsNumber bool `json:"epoch_as_number,omitempty"`
+	// DigitGrouping separates digit groups of integer formats in the text
+	// rendering, one of DigitSeparators
+	DigitGrouping string `json:"digit_grouping,omitempty"`
+}
+
+// Precision controls the resolution of returned instants
+type Precision string
+
+const (
+	PrecisionSecond Precision = "second"
+	PrecisionMilli  Precision = "milli"
+	PrecisionMicro  Precision = "micro"
+	PrecisionNano   Precision = "nano"
+)
+
+// Duration returns the resolution of the precision, or 0 if unknown
+func (p Precision) Duration() time.Duration {
+	switch p {
+	case PrecisionSecond:
+		return time.Second
+	case PrecisionMilli:
+		return time.Millisecond
+	case PrecisionMicro:
+		return time.Microsecond
+	case PrecisionNano:
+		return time.Nanosecond
+	default:
+		return 0
+	}
 }
 
 // TimezoneInfoInput represents input for timezone information
 type TimezoneInfoInput struct {
 	Timezone      string    `json:"timezone"`
 	ReferenceTime time.Time `json:"reference_time,omitempty"`
+	// LookaheadDays overrides the configured DST lookahead horizon
+	LookaheadDays int `json:"lookahead_days,omitempty"`
 }
 
 // Result types for MCP tool responses
 
 // GetTimeResult represents the result of getting current time
 type GetTimeResult struct {
-	FormattedTime string `json:"formatted_time"`
-	Timezone      string `json:"timezone"`
-	Format        string `json:"format"`
-	UnixTimestamp int64  `json:"unix_timestamp"`
+	FormattedTime   string            `json:"formatted_time"`
+	Timezone        string            `json:"timezone"`
+	Format          string            `json:"format"`
+	UnixTimestamp   int64             `json:"unix_timestamp"`
+	FormattedTimes  map[string]string `json:"formatted_times,omitempty"`
+	Precision       string            `json:"precision,omitempty"`
+	UnixTimestampMs *int64            `json:"unix_timestamp_ms,omitempty"`
+	UnixTimestampUs *int64            `json:"unix_timestamp_us,omitempty"`
+	UnixTimestampNs *int64            `json:"unix_timestamp_ns,omitempty"`
+	// FormattedEpoch and FormattedEpochs repeat integer formats as numbers
+	// when epoch_as_number is set
+	FormattedEpoch  *int64           `json:"formatted_epoch,omitempty"`
+	FormattedEpochs map[string]int64 `json:"formatted_epochs,omitempty"`
 }
 
 // FormatTimeResult represents the result of formatting time
@@ -106,12 +219,71 @@ type FormatTimeResult struct {
 	Timezone      string `json:"timezone"`
 	Format        string `json:"format"`
 	UnixTimestamp int64  `json:"unix_timestamp"`
+	IsPreEpoch    bool   `json:"is_pre_epoch"` // before 1970-01-01T00:00:00Z
+	// OutOfRange is set when the timestamp is outside time.valid_range
+	OutOfRange bool     `json:"out_of_range,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+	// FormattedEpoch repeats an integer format as a number when
+	// epoch_as_number is set
+	FormattedEpoch *int64 `json:"formatted_epoch,omitempty"`
+	// WrittenDate is set when spell_out or roman_year is
+	WrittenDate string `json:"written_date,omitempty"`
 }
 
 // ParseTimeResult represents the result of parsing time
 type ParseTimeResult struct {
-	UnixTimestamp int64  `json:"unix_timestamp"`
-	RFC3339       string `json:"rfc3339"`
-	Timezone      string `json:"timezone"`
-	IsDST         bool   `json:"is_dst"`
+	UnixTimestamp    int64          `json:"unix_timestamp"`
+	IsPreEpoch       bool           `json:"is_pre_epoch"` // before 1970-01-01T00:00:00Z
+	RFC3339          string         `json:"rfc3339"`
+	Timezone         string         `json:"timezone"`
+	IsDST            bool           `json:"is_dst"`
+	TimezoneHandling string         `json:"timezone_handling"`
+	ExplicitOffset   bool           `json:"explicit_offset"`
+	Components       TimeComponents `json:"components"`
+	// TwoDigitYear is set when the layout had a two-digit year
+	TwoDigitYear *TwoDigitYearInfo `json:"two_digit_year,omitempty"`
+	// Calendar is set for dates before the Gregorian cutover or read as Julian
+	Calendar *CalendarInfo `json:"calendar,omitempty"`
+	// OutOfRange is set when the timestamp is outside time.valid_range
+	OutOfRange bool     `json:"out_of_range,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// TimeComponents is the calendar breakdown of an instant in its timezone
+type TimeComponents struct {
+	Year             int    `json:"year"`
+	Month            int    `json:"month"`
+	Day              int    `json:"day"`
+	Hour             int    `json:"hour"`
+	Minute           int    `json:"minute"`
+	Second           int    `json:"second"`
+	Nanosecond       int    `json:"nanosecond"`
+	Weekday          string `json:"weekday"`
+	DayOfYear        int    `json:"day_of_year"`
+	ISOYear          int    `json:"iso_year"`
+	ISOWeek          int    `json:"iso_week"`
+	OffsetSeconds    int    `json:"offset_seconds"`
+	ZoneAbbreviation string `json:"zone_abbreviation"`
+}
+
+// NewTimeComponents breaks t down into its calendar components
+func NewTimeComponents(t time.Time) TimeComponents {
+	isoYear, isoWeek := t.ISOWeek()
+	abbreviation, offset := t.Zone()
+
+	return TimeComponents{
+		Year:             t.Year(),
+		Month:            int(t.Month()),
+		Day:              t.Day(),
+		Hour:             t.Hour(),
+		Minute:           t.Minute(),
+		Second:           t.Second(),
+		Nanosecond:       t.Nanosecond(),
+		Weekday:          t.Weekday().String(),
+		DayOfYear:        t.YearDay(),
+		ISOYear:          isoYear,
+		ISOWeek:          isoWeek,
+		OffsetSeconds:    offset,
+		ZoneAbbreviation: abbreviation,
+	}
 }
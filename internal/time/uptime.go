@@ -0,0 +1,53 @@
+package time
+
+import (
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// UptimeInput represents input for the server uptime tool
+type UptimeInput struct {
+	// SinceMonotonicNs is a monotonic_ns value from a previous call; when set,
+	// the elapsed time since that call is returned
+	SinceMonotonicNs *int64 `json:"since_monotonic_ns,omitempty"`
+}
+
+// UptimeResult represents the server start time, uptime and monotonic counter
+type UptimeResult struct {
+	StartTime     string  `json:"start_time"`
+	Uptime        string  `json:"uptime"`
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	MonotonicNs   int64   `json:"monotonic_ns"`
+	ElapsedNs     *int64  `json:"elapsed_ns,omitempty"`
+	Elapsed       string  `json:"elapsed,omitempty"`
+}
+
+// GetServerUptime returns the server uptime and a monotonic counter.
+// The counter is nanoseconds since the service started, read from the
+// service clock. The system clock's readings carry Go's monotonic clock, so
+// the counter is unaffected by wall-clock changes; a frozen clock keeps it
+// at zero, so replays and deterministic runs give the same output.
+func (s *timeService) GetServerUptime(input UptimeInput) (UptimeResult, error) {
+	uptime := s.clock.Now().Sub(s.startedAt)
+
+	result := UptimeResult{
+		StartTime:     s.startedAt.UTC().Format(time.RFC3339Nano),
+		Uptime:        uptime.Round(time.Millisecond).String(),
+		UptimeSeconds: uptime.Seconds(),
+		MonotonicNs:   uptime.Nanoseconds(),
+	}
+
+	if input.SinceMonotonicNs != nil {
+		since := *input.SinceMonotonicNs
+		if since < 0 || since > result.MonotonicNs {
+			return UptimeResult{}, timeerrors.Errorf(timeerrors.ErrOutOfRange, "since_monotonic_ns %d is outside this server's monotonic range [0, %d]; the server may have restarted",
+				since, result.MonotonicNs)
+		}
+		elapsed := result.MonotonicNs - since
+		result.ElapsedNs = &elapsed
+		result.Elapsed = time.Duration(elapsed).String()
+	}
+
+	return result, nil
+}
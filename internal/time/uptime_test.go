@@ -0,0 +1,54 @@
+package time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_GetServerUptime(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	first, err := service.GetServerUptime(UptimeInput{})
+	require.NoError(t, err)
+	assert.Nil(t, first.ElapsedNs)
+	assert.NotEmpty(t, first.StartTime)
+
+	time.Sleep(2 * time.Millisecond)
+
+	second, err := service.GetServerUptime(UptimeInput{SinceMonotonicNs: &first.MonotonicNs})
+	require.NoError(t, err)
+	assert.Equal(t, first.StartTime, second.StartTime)
+	assert.Greater(t, second.MonotonicNs, first.MonotonicNs)
+	require.NotNil(t, second.ElapsedNs)
+	assert.GreaterOrEqual(t, *second.ElapsedNs, int64(2*time.Millisecond))
+}
+
+func TestTimeService_GetServerUptime_FrozenClock(t *testing.T) {
+	logger := newTestLogger(t)
+	frozen := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: frozen}))
+
+	time.Sleep(2 * time.Millisecond)
+	result, err := service.GetServerUptime(UptimeInput{})
+	require.NoError(t, err)
+	assert.Equal(t, UptimeResult{
+		StartTime:     "2000-01-01T00:00:00Z",
+		Uptime:        "0s",
+		UptimeSeconds: 0,
+		MonotonicNs:   0,
+	}, result)
+}
+
+func TestTimeService_GetServerUptime_InvalidSince(t *testing.T) {
+	logger := newTestLogger(t)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+
+	for _, since := range []int64{-1, int64(time.Hour)} {
+		_, err := service.GetServerUptime(UptimeInput{SinceMonotonicNs: &since})
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,71 @@
+package time
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// ValidRange bounds the years of timestamps accepted by ParseTime and FormatTime
+type ValidRange struct {
+	MinYear int
+	MaxYear int
+	// Reject returns an error for out-of-range timestamps instead of flagging them
+	Reject bool
+}
+
+// WithValidRange enables range checking of parsed and formatted timestamps
+func WithValidRange(r ValidRange) Option {
+	return func(s *timeService) {
+		s.validRange = &r
+	}
+}
+
+// checkRange reports a warning, or an error when rejecting, for instants
+// whose year falls outside the configured range
+func (s *timeService) checkRange(t time.Time) (string, error) {
+	if s.validRange == nil {
+		return "", nil
+	}
+
+	year := t.UTC().Year()
+	if year >= s.validRange.MinYear && year <= s.validRange.MaxYear {
+		return "", nil
+	}
+
+	msg := fmt.Sprintf("timestamp year %d is outside the valid range %d-%d",
+		year, s.validRange.MinYear, s.validRange.MaxYear)
+	if year > s.validRange.MaxYear {
+		if hint := s.epochUnitHint(t); hint != "" {
+			msg += "; " + hint
+		}
+	}
+
+	if s.validRange.Reject {
+		return "", timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s", msg)
+	}
+	return msg, nil
+}
+
+// epochUnitHint suggests an epoch unit mix-up when scaling a far-future
+// instant down lands it inside the valid range, e.g. milliseconds read as seconds
+func (s *timeService) epochUnitHint(t time.Time) string {
+	seconds := t.Unix()
+	scales := []struct {
+		factor int64
+		unit   string
+	}{
+		{1e3, "milliseconds"},
+		{1e6, "microseconds"},
+		{1e9, "nanoseconds"},
+	}
+
+	for _, scale := range scales {
+		year := time.Unix(seconds/scale.factor, 0).UTC().Year()
+		if year >= s.validRange.MinYear && year <= s.validRange.MaxYear {
+			return fmt.Sprintf("the value looks like an epoch in %s read as seconds", scale.unit)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package time
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeService_ValidRange(t *testing.T) {
+	logger := newTestLogger(t)
+	flagging := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix"}, logger,
+		WithValidRange(ValidRange{MinYear: 1900, MaxYear: 2100}))
+	rejecting := NewTimeService("UTC", "RFC3339", []string{"RFC3339", "Unix"}, logger,
+		WithValidRange(ValidRange{MinYear: 1900, MaxYear: 2100, Reject: true}))
+
+	t.Run("in range is not flagged", func(t *testing.T) {
+		result, err := flagging.FormatTime(FormatTimeInput{Timestamp: EpochTimestamp(1703518245, EpochSeconds)})
+		require.NoError(t, err)
+		assert.False(t, result.OutOfRange)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("milliseconds read as seconds are flagged with a hint", func(t *testing.T) {
+		result, err := flagging.FormatTime(FormatTimeInput{Timestamp: EpochTimestamp(1703518245000, EpochSeconds)})
+		require.NoError(t, err)
+		assert.True(t, result.OutOfRange)
+		require.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "year 55952 is outside the valid range 1900-2100")
+		assert.Contains(t, result.Warnings[0], "epoch in milliseconds")
+	})
+
+	t.Run("parse flags dates before the range", func(t *testing.T) {
+		result, err := flagging.ParseTime(ParseTimeInput{TimeString: "1850-01-01T00:00:00Z", Format: "RFC3339"})
+		require.NoError(t, err)
+		assert.True(t, result.OutOfRange)
+		assert.NotContains(t, result.Warnings[0], "looks like")
+	})
+
+	t.Run("reject mode returns errors", func(t *testing.T) {
+		_, err := rejecting.FormatTime(FormatTimeInput{Timestamp: EpochTimestamp(1703518245000000, EpochSeconds)})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "epoch in microseconds")
+
+		_, err = rejecting.ParseTime(ParseTimeInput{TimeString: "1703518245000", Format: "Unix"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "outside the valid range")
+	})
+
+	t.Run("no range configured", func(t *testing.T) {
+		service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger)
+		result, err := service.FormatTime(FormatTimeInput{Timestamp: EpochTimestamp(1703518245000, EpochSeconds)})
+		require.NoError(t, err)
+		assert.False(t, result.OutOfRange)
+	})
+}
@@ -0,0 +1,161 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Information a format can lose on a round trip
+const (
+	LossTimezone   = "timezone"
+	LossSubSeconds = "sub_seconds"
+	LossYearDigits = "year_digits"
+	LossDate       = "date"
+	LossTimeOfDay  = "time_of_day"
+)
+
+// VerifyFormatInput represents input for checking a format against a sample
+type VerifyFormatInput struct {
+	// Format is a named format, Go layout or strftime pattern
+	Format string `json:"format"`
+	Sample string `json:"sample"`
+	// Timezone reads samples without an offset; defaults to the server default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// FormatLoss is information a format drops from a sample
+type FormatLoss struct {
+	Kind   string `json:"kind"` // timezone, sub_seconds, year_digits, date or time_of_day
+	Detail string `json:"detail"`
+}
+
+// VerifyFormatResult reports what survives parsing a sample and formatting
+// it again
+type VerifyFormatResult struct {
+	Format string `json:"format"`
+	// Layout is the Go layout the format resolves to
+	Layout string `json:"layout"`
+	Sample string `json:"sample"`
+	// Parsed is the instant read from the sample, as RFC3339
+	Parsed string `json:"parsed"`
+	// Reformatted is the parsed instant formatted again with the format
+	Reformatted string `json:"reformatted"`
+	// RoundTrip is set when reformatting gives back the sample exactly
+	RoundTrip bool `json:"round_trip"`
+	// Lossless is set when no information was lost, even if the text
+	// changed, as with dec becoming Dec
+	Lossless bool         `json:"lossless"`
+	Losses   []FormatLoss `json:"losses,omitempty"`
+}
+
+// VerifyFormat parses a sample with a format, formats it again and reports
+// the information the format loses
+func (s *timeService) VerifyFormat(input VerifyFormatInput) (VerifyFormatResult, error) {
+	if input.Format == "" {
+		return VerifyFormatResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "format is required")
+	}
+	if input.Sample == "" {
+		return VerifyFormatResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "sample is required")
+	}
+	layout, err := layoutFor(input.Format)
+	if err != nil {
+		return VerifyFormatResult{}, err
+	}
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	loc, err := s.loadLocation(timezone)
+	if err != nil {
+		return VerifyFormatResult{}, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", timezone, err)
+	}
+
+	parsed, err := s.parseTimeInternal(input.Sample, layout, loc)
+	if err != nil {
+		return VerifyFormatResult{}, err
+	}
+	var losses []FormatLoss
+	if hasTwoDigitYear(layout) {
+		var info TwoDigitYearInfo
+		if parsed, info, err = applyTwoDigitYearPivot(parsed, s.twoDigitYearPivot); err != nil {
+			return VerifyFormatResult{}, timeerrors.Errorf(timeerrors.ErrParseFailure, "failed to parse time string %s: %w", input.Sample, err)
+		}
+		losses = append(losses, FormatLoss{Kind: LossYearDigits, Detail: fmt.Sprintf(
+			"the year has two digits, so the century is guessed: %02d was read as %d (pivot %d)", info.Input, parsed.Year(), info.Pivot)})
+	}
+
+	reformatted, err := renderLayout(parsed, layout)
+	if err != nil {
+		return VerifyFormatResult{}, err
+	}
+	// Go parses a fraction after the seconds even when the layout has none,
+	// and then drops it when formatting
+	if reparsed, err := s.parseTimeInternal(reformatted, layout, loc); err == nil && reparsed.Nanosecond() != parsed.Nanosecond() {
+		losses = append(losses, FormatLoss{Kind: LossSubSeconds, Detail: fmt.Sprintf(
+			"the sample has fractional seconds (%s) that the layout drops when formatting", parsed.Format(".999999999"))})
+	}
+	losses = append(losses, layoutLosses(layout, parsed, timezone)...)
+
+	s.logger.Debug("Verified format",
+		slog.String("format", input.Format),
+		slog.String("sample", input.Sample),
+		slog.String("reformatted", reformatted),
+		slog.Int("losses", len(losses)))
+
+	return VerifyFormatResult{
+		Format:      input.Format,
+		Layout:      layout,
+		Sample:      input.Sample,
+		Parsed:      parsed.Format(time.RFC3339Nano),
+		Reformatted: reformatted,
+		RoundTrip:   reformatted == input.Sample,
+		Lossless:    len(losses) == 0,
+		Losses:      losses,
+	}, nil
+}
+
+// layoutLosses reports what a custom layout cannot carry: a UTC offset, a
+// year or a time of day. Named formats carry all three, except day numbers
+// and MySQL DATETIME
+func layoutLosses(layout string, parsed time.Time, timezone string) []FormatLoss {
+	timeOfDay := FormatLoss{Kind: LossTimeOfDay, Detail: fmt.Sprintf(
+		"the format has no time of day, so the sample was read as midnight in %s", timezone)}
+	if isDayNumberFormat(layout) {
+		return []FormatLoss{timeOfDay}
+	}
+	if FormatType(layout) == FormatMySQLDateTime {
+		return []FormatLoss{{Kind: LossTimezone, Detail: fmt.Sprintf(
+			"DATETIME has no UTC offset, so the sample was read as a wall time in %s", timezone)}}
+	}
+	if IsValidFormat(layout) {
+		return nil
+	}
+	components := make(map[string]bool)
+	offset := false
+	for _, chunk := range splitLayout(layout) {
+		components[chunk.component] = true
+		if chunk.component == layoutZone && chunk.text != "MST" {
+			offset = true
+		}
+	}
+
+	var losses []FormatLoss
+	name, seconds := parsed.Zone()
+	switch {
+	case !components[layoutZone]:
+		losses = append(losses, FormatLoss{Kind: LossTimezone, Detail: fmt.Sprintf(
+			"the layout has no UTC offset, so the sample was read as a wall time in %s and the same text is another instant elsewhere", timezone)})
+	case !offset && seconds == 0 && name != "UTC" && name != "GMT":
+		// Go only knows the offset of an abbreviation that belongs to the
+		// zone the time is read in
+		losses = append(losses, FormatLoss{Kind: LossTimezone, Detail: fmt.Sprintf(
+			"the abbreviation %s is not one of %s's, so it was read with offset 0; add a numeric offset such as -07:00 to the layout", name, timezone)})
+	}
+	if !components[layoutYear] {
+		losses = append(losses, FormatLoss{Kind: LossDate, Detail: "the layout has no year, so parsed times fall in year 0"})
+	}
+	if !components[layoutHour24] && !components[layoutHour12] {
+		losses = append(losses, timeOfDay)
+	}
+	return losses
+}
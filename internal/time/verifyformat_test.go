@@ -0,0 +1,93 @@
+package time
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_VerifyFormat(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+
+	tests := []struct {
+		name        string
+		input       VerifyFormatInput
+		reformatted string
+		roundTrip   bool
+		losses      []string
+	}{
+		{
+			name:        "lossless",
+			input:       VerifyFormatInput{Format: "2006-01-02T15:04:05.000Z07:00", Sample: "2023-12-25T15:30:45.123+01:00"},
+			reformatted: "2023-12-25T15:30:45.123+01:00",
+			roundTrip:   true,
+		},
+		{
+			name:        "named format",
+			input:       VerifyFormatInput{Format: "RFC3339", Sample: "2023-12-25T15:30:45Z"},
+			reformatted: "2023-12-25T15:30:45Z",
+			roundTrip:   true,
+		},
+		{
+			name:        "sub-seconds dropped",
+			input:       VerifyFormatInput{Format: "2006-01-02 15:04:05 -0700", Sample: "2023-12-25 15:30:45.5 +0000"},
+			reformatted: "2023-12-25 15:30:45 +0000",
+			losses:      []string{LossSubSeconds},
+		},
+		{
+			name:        "no offset",
+			input:       VerifyFormatInput{Format: "%d/%m/%Y %H:%M", Sample: "25/12/2023 15:30", Timezone: "Europe/Paris"},
+			reformatted: "25/12/2023 15:30",
+			roundTrip:   true,
+			losses:      []string{LossTimezone},
+		},
+		{
+			name:        "two-digit year",
+			input:       VerifyFormatInput{Format: "02/01/06 15:04 Z07:00", Sample: "25/12/70 15:30 Z"},
+			reformatted: "25/12/70 15:30 Z",
+			roundTrip:   true,
+			losses:      []string{LossYearDigits},
+		},
+		{
+			name:        "foreign abbreviation",
+			input:       VerifyFormatInput{Format: "2006-01-02 15:04 MST", Sample: "2023-12-25 15:30 CET", Timezone: "America/New_York"},
+			reformatted: "2023-12-25 15:30 CET",
+			roundTrip:   true,
+			losses:      []string{LossTimezone},
+		},
+		{
+			name:        "no year, normalized month",
+			input:       VerifyFormatInput{Format: "Jan 2 15:04 -07:00", Sample: "dec 25 15:30 +00:00"},
+			reformatted: "Dec 25 15:30 +00:00",
+			losses:      []string{LossDate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.VerifyFormat(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.reformatted, result.Reformatted)
+			assert.Equal(t, tt.roundTrip, result.RoundTrip)
+			var losses []string
+			for _, loss := range result.Losses {
+				losses = append(losses, loss.Kind)
+			}
+			assert.Equal(t, tt.losses, losses)
+			assert.Equal(t, len(tt.losses) == 0, result.Lossless)
+		})
+	}
+
+	_, err := service.VerifyFormat(VerifyFormatInput{Format: "02/01/2006", Sample: "2023-12-25"})
+	assert.True(t, errors.Is(err, timeerrors.ErrParseFailure))
+
+	_, err = service.VerifyFormat(VerifyFormatInput{Format: "DD/MM/YYYY", Sample: "25/12/2023"})
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidFormat))
+
+	_, err = service.VerifyFormat(VerifyFormatInput{Format: "RFC3339"})
+	assert.ErrorContains(t, err, "sample is required")
+}
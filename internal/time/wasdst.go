@@ -0,0 +1,176 @@
+package time
+
+import (
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// WasDSTInput represents an instant to check for daylight saving time
+type WasDSTInput struct {
+	At       Timestamp `json:"at,omitempty"` // instant to check, defaults to now
+	Timezone string    `json:"timezone,omitempty"`
+	// TZData names a configured tzdata source; defaults to "default"
+	TZData string `json:"tzdata,omitempty"`
+}
+
+// WasDSTResult reports tzdata's DST flag and offset at an instant, and the
+// transitions either side of it. Previous is absent for an instant in a
+// zone's earliest recorded offset, and Next when no change is scheduled
+// within ten years
+type WasDSTResult struct {
+	At            string          `json:"at"`
+	AtUTC         string          `json:"at_utc"`
+	Timezone      string          `json:"timezone"`
+	TZData        string          `json:"tzdata"`
+	DST           bool            `json:"dst"`
+	Abbreviation  string          `json:"abbreviation"`
+	Offset        string          `json:"offset"`
+	OffsetSeconds int             `json:"offset_seconds"`
+	Previous      *ZoneTransition `json:"previous_transition,omitempty"`
+	Next          *ZoneTransition `json:"next_transition,omitempty"`
+	Notes         []string        `json:"notes,omitempty"`
+}
+
+// ZoneTransition is one change of a zone's offset or DST flag, to the
+// second. At is the first instant of the new state, shown in the zone
+type ZoneTransition struct {
+	At                 string `json:"at"`
+	AtUTC              string `json:"at_utc"`
+	Kind               string `json:"kind"`
+	TransitionType     string `json:"transition_type"`
+	AbbreviationBefore string `json:"abbreviation_before"`
+	AbbreviationAfter  string `json:"abbreviation_after"`
+	OffsetBefore       string `json:"offset_before"`
+	OffsetAfter        string `json:"offset_after"`
+	DSTBefore          bool   `json:"dst_before"`
+	DSTAfter           bool   `json:"dst_after"`
+	// SecondsFromAt is how far the transition is from the checked instant,
+	// negative for the previous one
+	SecondsFromAt int64 `json:"seconds_from_at"`
+}
+
+// WasDST reports whether tzdata marks an instant as daylight saving time
+// in a zone, reading the zone's isdst flag rather than comparing offsets
+// six months apart, with the exact transitions around the instant
+func (s *timeService) WasDST(input WasDSTInput) (WasDSTResult, error) {
+	timezone := defaultString(input.Timezone, s.defaultTimezone)
+	source := defaultString(input.TZData, DefaultTZDataSource)
+
+	loc, err := s.loadLocationFrom(source, timezone)
+	if err != nil {
+		return WasDSTResult{}, err
+	}
+
+	at := s.clock.Now()
+	if !input.At.IsZero() {
+		if at, err = input.At.Resolve(); err != nil {
+			return WasDSTResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+	at = at.In(loc)
+
+	state := abbreviationStateAt(at, loc)
+	result := WasDSTResult{
+		At:            at.Format(time.RFC3339),
+		AtUTC:         at.UTC().Format(time.RFC3339),
+		Timezone:      timezone,
+		TZData:        source,
+		DST:           state.dst,
+		Abbreviation:  state.name,
+		Offset:        formatOffset(state.offset, s.roundOffsets),
+		OffsetSeconds: state.offset,
+	}
+
+	// tzdata transitions fall on whole seconds, which the scans keep to
+	from := at.Truncate(time.Second)
+	var changes []zoneChange
+	if previous, ok := previousZoneChange(from, loc, abbreviationScanStart); ok {
+		result.Previous = s.zoneTransition(previous, at, loc)
+		changes = append(changes, previous)
+	}
+	if next, ok := nextZoneChange(from, loc, from.AddDate(0, 0, MaxDSTLookaheadDays)); ok {
+		result.Next = s.zoneTransition(next, at, loc)
+		changes = append(changes, next)
+	}
+
+	// Zones with negative DST, such as Europe/Dublin, flag their winter
+	// offset as DST in tzdata; say so rather than leave it to surprise
+	for _, change := range changes {
+		other := change.before
+		if other.zoneState == state.zoneState {
+			other = change.after
+		}
+		if state.dst && !other.dst && other.offset > state.offset {
+			result.Notes = append(result.Notes, "tzdata marks this zone's winter offset as DST (negative DST), so dst is set outside summer")
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// zoneChange is a transition between two abbreviation states
+type zoneChange struct {
+	at            time.Time
+	before, after abbreviationState
+}
+
+// zoneTransition renders a change relative to the checked instant
+func (s *timeService) zoneTransition(change zoneChange, at time.Time, loc *time.Location) *ZoneTransition {
+	kind, transitionType := classifyTransition(change.before.zoneState, change.after.zoneState)
+	return &ZoneTransition{
+		At:                 change.at.In(loc).Format(time.RFC3339),
+		AtUTC:              change.at.UTC().Format(time.RFC3339),
+		Kind:               kind,
+		TransitionType:     transitionType,
+		AbbreviationBefore: change.before.name,
+		AbbreviationAfter:  change.after.name,
+		OffsetBefore:       formatOffset(change.before.offset, s.roundOffsets),
+		OffsetAfter:        formatOffset(change.after.offset, s.roundOffsets),
+		DSTBefore:          change.before.dst,
+		DSTAfter:           change.after.dst,
+		SecondsFromAt:      int64(change.at.Sub(at) / time.Second),
+	}
+}
+
+// previousZoneChange finds the last change of offset or DST flag at or
+// before at, scanning back a day at a time to limit
+func previousZoneChange(at time.Time, loc *time.Location, limit time.Time) (zoneChange, bool) {
+	current := zoneStateAt(at, loc)
+	for hi := at; hi.After(limit); hi = hi.Add(-24 * time.Hour) {
+		lo := hi.Add(-24 * time.Hour)
+		if zoneStateAt(lo, loc) != current {
+			return bisectZoneChange(lo, hi, loc), true
+		}
+	}
+	return zoneChange{}, false
+}
+
+// nextZoneChange finds the first change of offset or DST flag after at,
+// scanning forward a day at a time to limit
+func nextZoneChange(at time.Time, loc *time.Location, limit time.Time) (zoneChange, bool) {
+	current := zoneStateAt(at, loc)
+	for lo := at; lo.Before(limit); lo = lo.Add(24 * time.Hour) {
+		hi := lo.Add(24 * time.Hour)
+		if zoneStateAt(hi, loc) != current {
+			return bisectZoneChange(lo, hi, loc), true
+		}
+	}
+	return zoneChange{}, false
+}
+
+// bisectZoneChange narrows the change between lo and hi, which are in
+// different states, down to the second as buildZoneIndex does
+func bisectZoneChange(lo, hi time.Time, loc *time.Location) zoneChange {
+	before := zoneStateAt(lo, loc)
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if zoneStateAt(mid, loc) == before {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return zoneChange{at: hi, before: abbreviationStateAt(lo, loc), after: abbreviationStateAt(hi, loc)}
+}
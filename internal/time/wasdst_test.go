@@ -0,0 +1,87 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_WasDST(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t), WithClock(FixedClock{Time: now}))
+
+	t.Run("a second after spring forward", func(t *testing.T) {
+		result, err := service.WasDST(WasDSTInput{At: RFC3339Timestamp("2024-03-10T07:00:01Z"), Timezone: "America/New_York"})
+		require.NoError(t, err)
+		assert.True(t, result.DST)
+		assert.Equal(t, "2024-03-10T03:00:01-04:00", result.At)
+		assert.Equal(t, "EDT", result.Abbreviation)
+		assert.Equal(t, -4*3600, result.OffsetSeconds)
+		assert.Empty(t, result.Notes)
+
+		assert.Equal(t, &ZoneTransition{
+			At: "2024-03-10T03:00:00-04:00", AtUTC: "2024-03-10T07:00:00Z",
+			Kind: TransitionKindDST, TransitionType: TransitionTypeEnterDST,
+			AbbreviationBefore: "EST", AbbreviationAfter: "EDT",
+			OffsetBefore: "-05:00", OffsetAfter: "-04:00",
+			DSTBefore: false, DSTAfter: true,
+			SecondsFromAt: -1,
+		}, result.Previous)
+		require.NotNil(t, result.Next)
+		assert.Equal(t, "2024-11-03T01:00:00-05:00", result.Next.At)
+		assert.Equal(t, TransitionTypeExitDST, result.Next.TransitionType)
+	})
+
+	t.Run("the second before", func(t *testing.T) {
+		result, err := service.WasDST(WasDSTInput{At: RFC3339Timestamp("2024-03-10T06:59:59Z"), Timezone: "America/New_York"})
+		require.NoError(t, err)
+		assert.False(t, result.DST)
+		assert.Equal(t, "EST", result.Abbreviation)
+		require.NotNil(t, result.Next)
+		assert.Equal(t, int64(1), result.Next.SecondsFromAt)
+	})
+
+	t.Run("defaults to now", func(t *testing.T) {
+		result, err := service.WasDST(WasDSTInput{Timezone: "Europe/Berlin"})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-06-01T02:00:00+02:00", result.At)
+		assert.True(t, result.DST)
+	})
+
+	t.Run("negative DST", func(t *testing.T) {
+		result, err := service.WasDST(WasDSTInput{At: RFC3339Timestamp("2024-01-15T12:00:00Z"), Timezone: "Europe/Dublin"})
+		require.NoError(t, err)
+		assert.True(t, result.DST)
+		assert.Equal(t, "GMT", result.Abbreviation)
+		assert.Len(t, result.Notes, 1)
+	})
+
+	t.Run("zone without DST", func(t *testing.T) {
+		result, err := service.WasDST(WasDSTInput{At: RFC3339Timestamp("2024-01-15T12:00:00Z"), Timezone: "Asia/Tokyo"})
+		require.NoError(t, err)
+		assert.False(t, result.DST)
+		assert.Nil(t, result.Next)
+		require.NotNil(t, result.Previous)
+		assert.Equal(t, "1951-09-09T00:00:00+09:00", result.Previous.At)
+	})
+
+	t.Run("earliest record", func(t *testing.T) {
+		result, err := service.WasDST(WasDSTInput{At: RFC3339Timestamp("1850-01-01T00:00:00Z"), Timezone: "Europe/Lisbon"})
+		require.NoError(t, err)
+		assert.Equal(t, "LMT", result.Abbreviation)
+		assert.Nil(t, result.Previous)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := service.WasDST(WasDSTInput{Timezone: "Mars/Olympus"})
+		assert.True(t, errors.Is(err, timeerrors.ErrInvalidTimezone))
+
+		_, err = service.WasDST(WasDSTInput{At: RFC3339Timestamp("yesterday")})
+		assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	})
+}
@@ -0,0 +1,182 @@
+package time
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// defaultWebhookTolerance is the window Stripe, Slack and Standard Webhooks
+// all accept by default
+const defaultWebhookTolerance = 5 * time.Minute
+
+// maxWebhookSeconds is the largest plausible Unix seconds value; larger
+// ones are almost always milliseconds sent with unit s
+const maxWebhookSeconds = 1e11
+
+// Webhook timestamp units
+const (
+	WebhookUnitSeconds      = "s"
+	WebhookUnitMilliseconds = "ms"
+)
+
+// WebhookTimestampStatus is the outcome of a webhook timestamp check
+type WebhookTimestampStatus string
+
+const (
+	WebhookTimestampValid   WebhookTimestampStatus = "valid"
+	WebhookTimestampExpired WebhookTimestampStatus = "expired"
+	WebhookTimestampFuture  WebhookTimestampStatus = "future"
+)
+
+// ValidateWebhookTimestampInput represents input for checking the signed
+// timestamp of a webhook delivery against a replay window
+type ValidateWebhookTimestampInput struct {
+	// Timestamp is the header value: Unix time, as in Slack's
+	// X-Slack-Request-Timestamp or Standard Webhooks' webhook-timestamp, or a
+	// whole Stripe-Signature header, whose t= part is read
+	Timestamp string `json:"timestamp"`
+	Unit      string `json:"unit,omitempty"`      // s (default) or ms
+	Tolerance string `json:"tolerance,omitempty"` // Go duration a delivery stays valid for, defaults to 5m
+	// FutureTolerance bounds how far ahead of the receiver's clock the
+	// timestamp may be, defaults to tolerance
+	FutureTolerance string    `json:"future_tolerance,omitempty"`
+	At              Timestamp `json:"at,omitempty"` // receipt time, defaults to now
+}
+
+// ValidateWebhookTimestampResult represents the outcome of a webhook
+// timestamp check
+type ValidateWebhookTimestampResult struct {
+	Valid       bool   `json:"valid"`
+	Status      string `json:"status"`
+	Timestamp   string `json:"timestamp"`
+	UnixSeconds int64  `json:"unix_seconds"`
+	EvaluatedAt string `json:"evaluated_at"`
+	// AgeSeconds is the receipt time minus the timestamp, negative when the
+	// timestamp is ahead of the receiver's clock
+	AgeSeconds             int64  `json:"age_seconds"`
+	ToleranceSeconds       int64  `json:"tolerance_seconds"`
+	FutureToleranceSeconds int64  `json:"future_tolerance_seconds"`
+	ExpiresAt              string `json:"expires_at"` // last instant the delivery is accepted
+	// Source is how the timestamp was read: unix_seconds,
+	// unix_milliseconds or stripe_signature
+	Source  string `json:"source"`
+	Summary string `json:"summary"`
+}
+
+// ValidateWebhookTimestamp checks a webhook timestamp header against the
+// receipt time, the replay protection Stripe, Slack and Standard Webhooks
+// signatures rely on. The timestamp must also be covered by the signature
+// for the check to mean anything; that verification is left to the caller
+func (s *timeService) ValidateWebhookTimestamp(input ValidateWebhookTimestampInput) (ValidateWebhookTimestampResult, error) {
+	value, source := strings.TrimSpace(input.Timestamp), "unix_seconds"
+	if value == "" {
+		return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp cannot be empty")
+	}
+	if strings.Contains(value, "=") {
+		t, ok := stripeSignatureTimestamp(value)
+		if !ok {
+			return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "signature header has no t= timestamp")
+		}
+		value, source = t, "stripe_signature"
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid timestamp %q (must be a non-negative integer Unix time)", value)
+	}
+
+	var signedAt time.Time
+	switch defaultString(input.Unit, WebhookUnitSeconds) {
+	case WebhookUnitSeconds:
+		if n > maxWebhookSeconds {
+			return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "timestamp %d is too large for seconds; set unit to ms if it is in milliseconds", n)
+		}
+		signedAt = time.Unix(n, 0)
+	case WebhookUnitMilliseconds:
+		if source == "stripe_signature" {
+			return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "Stripe signature timestamps are in seconds")
+		}
+		signedAt, source = time.UnixMilli(n), "unix_milliseconds"
+	default:
+		return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid unit %s (must be one of: s, ms)", input.Unit)
+	}
+
+	tolerance, err := parseNonNegativeDuration("tolerance", input.Tolerance)
+	if err != nil {
+		return ValidateWebhookTimestampResult{}, err
+	}
+	if input.Tolerance == "" {
+		tolerance = defaultWebhookTolerance
+	}
+	futureTolerance, err := parseNonNegativeDuration("future_tolerance", input.FutureTolerance)
+	if err != nil {
+		return ValidateWebhookTimestampResult{}, err
+	}
+	if input.FutureTolerance == "" {
+		futureTolerance = tolerance
+	}
+
+	at := s.clock.Now()
+	if !input.At.IsZero() {
+		if at, err = input.At.Resolve(); err != nil {
+			return ValidateWebhookTimestampResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid at: %w", err)
+		}
+	}
+
+	age := at.Sub(signedAt)
+	expiresAt := signedAt.Add(tolerance)
+	result := ValidateWebhookTimestampResult{
+		Timestamp:              signedAt.UTC().Format(time.RFC3339Nano),
+		UnixSeconds:            signedAt.Unix(),
+		EvaluatedAt:            at.UTC().Format(time.RFC3339Nano),
+		AgeSeconds:             int64(age.Truncate(time.Second) / time.Second),
+		ToleranceSeconds:       int64(tolerance / time.Second),
+		FutureToleranceSeconds: int64(futureTolerance / time.Second),
+		ExpiresAt:              expiresAt.UTC().Format(time.RFC3339Nano),
+		Source:                 source,
+	}
+
+	// Deliveries are accepted up to and including the edge of either window
+	switch {
+	case age > tolerance:
+		result.Status = string(WebhookTimestampExpired)
+		result.Summary = fmt.Sprintf("expired: signed %s ago, %s past the %s tolerance",
+			humanizeDuration(age), humanizeDuration(age-tolerance), humanizeDuration(tolerance))
+	case -age > futureTolerance:
+		result.Status = string(WebhookTimestampFuture)
+		result.Summary = fmt.Sprintf("rejected: timestamp is %s ahead of the receiver's clock, beyond the %s future tolerance",
+			humanizeDuration(-age), humanizeDuration(futureTolerance))
+	case age >= 0:
+		result.Valid = true
+		result.Status = string(WebhookTimestampValid)
+		result.Summary = fmt.Sprintf("valid: signed %s ago, expires in %s", humanizeDuration(age), humanizeDuration(tolerance-age))
+	default:
+		result.Valid = true
+		result.Status = string(WebhookTimestampValid)
+		result.Summary = fmt.Sprintf("valid: timestamp is %s ahead of the receiver's clock, within the %s future tolerance",
+			humanizeDuration(-age), humanizeDuration(futureTolerance))
+	}
+
+	s.logger.Debug("Validated webhook timestamp",
+		slog.String("status", result.Status),
+		slog.Int64("age_seconds", result.AgeSeconds))
+
+	return result, nil
+}
+
+// stripeSignatureTimestamp reads the t= part of a Stripe-Signature header
+// such as t=1492774577,v1=5257a869...
+func stripeSignatureTimestamp(header string) (string, bool) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && key == "t" {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
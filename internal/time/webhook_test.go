@@ -0,0 +1,131 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestTimeService_ValidateWebhookTimestamp(t *testing.T) {
+	logger := newTestLogger(t)
+	// 1703518245 in Unix seconds
+	now := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger, WithClock(FixedClock{Time: now}))
+
+	tests := []struct {
+		name    string
+		input   ValidateWebhookTimestampInput
+		status  string
+		age     int64
+		source  string
+		summary string
+	}{
+		{
+			name:    "slack timestamp within tolerance",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703518203"},
+			status:  "valid",
+			age:     42,
+			source:  "unix_seconds",
+			summary: "valid: signed 42s ago, expires in 4m 18s",
+		},
+		{
+			name:    "stripe signature header",
+			input:   ValidateWebhookTimestampInput{Timestamp: "t=1703517645,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd,v0=6ffbb59b2300aae63f272406069a9788598b792a944a07aba816edb039989a39"},
+			status:  "expired",
+			age:     600,
+			source:  "stripe_signature",
+			summary: "expired: signed 10m ago, 5m past the 5m tolerance",
+		},
+		{
+			name:    "edge of the window is accepted",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703517945"},
+			status:  "valid",
+			age:     300,
+			summary: "valid: signed 5m ago, expires in less than a second",
+		},
+		{
+			name:    "custom tolerance",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703517645", Tolerance: "15m"},
+			status:  "valid",
+			age:     600,
+			summary: "valid: signed 10m ago, expires in 5m",
+		},
+		{
+			name:    "milliseconds",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703518244500", Unit: "ms"},
+			status:  "valid",
+			age:     0,
+			source:  "unix_milliseconds",
+			summary: "valid: signed less than a second ago, expires in 4m 59s",
+		},
+		{
+			name:    "slightly ahead of the receiver",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703518275"},
+			status:  "valid",
+			age:     -30,
+			summary: "valid: timestamp is 30s ahead of the receiver's clock, within the 5m future tolerance",
+		},
+		{
+			name:    "too far ahead",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703518275", FutureTolerance: "10s"},
+			status:  "future",
+			age:     -30,
+			summary: "rejected: timestamp is 30s ahead of the receiver's clock, beyond the 10s future tolerance",
+		},
+		{
+			name:    "explicit receipt time",
+			input:   ValidateWebhookTimestampInput{Timestamp: "1703518203", At: RFC3339Timestamp("2023-12-25T16:00:00Z")},
+			status:  "expired",
+			age:     1797,
+			summary: "expired: signed 29m 57s ago, 24m 57s past the 5m tolerance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.ValidateWebhookTimestamp(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.status == "valid", result.Valid)
+			assert.Equal(t, tt.age, result.AgeSeconds)
+			assert.Equal(t, tt.summary, result.Summary)
+			if tt.source != "" {
+				assert.Equal(t, tt.source, result.Source)
+			}
+		})
+	}
+
+	result, err := service.ValidateWebhookTimestamp(ValidateWebhookTimestampInput{Timestamp: " 1703518203 "})
+	require.NoError(t, err)
+	assert.Equal(t, "2023-12-25T15:30:03Z", result.Timestamp)
+	assert.Equal(t, "2023-12-25T15:35:03Z", result.ExpiresAt)
+	assert.Equal(t, int64(300), result.ToleranceSeconds)
+	assert.Equal(t, int64(300), result.FutureToleranceSeconds)
+
+	errorCases := []struct {
+		name  string
+		input ValidateWebhookTimestampInput
+		want  string
+	}{
+		{"empty", ValidateWebhookTimestampInput{}, "timestamp cannot be empty"},
+		{"not a number", ValidateWebhookTimestampInput{Timestamp: "2023-12-25T15:30:03Z"}, `invalid timestamp "2023-12-25T15:30:03Z" (must be a non-negative integer Unix time)`},
+		{"milliseconds as seconds", ValidateWebhookTimestampInput{Timestamp: "1703518203000"}, "timestamp 1703518203000 is too large for seconds; set unit to ms if it is in milliseconds"},
+		{"signature without timestamp", ValidateWebhookTimestampInput{Timestamp: "v1=abc"}, "signature header has no t= timestamp"},
+		{"stripe in milliseconds", ValidateWebhookTimestampInput{Timestamp: "t=1703518203,v1=abc", Unit: "ms"}, "Stripe signature timestamps are in seconds"},
+		{"unknown unit", ValidateWebhookTimestampInput{Timestamp: "1703518203", Unit: "us"}, "invalid unit us (must be one of: s, ms)"},
+		{"negative tolerance", ValidateWebhookTimestampInput{Timestamp: "1703518203", Tolerance: "-5m"}, "tolerance cannot be negative"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ValidateWebhookTimestamp(tt.input)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
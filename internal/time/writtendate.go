@@ -0,0 +1,270 @@
+package time
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// Written dates are for formal documents such as certificates: the date
+// with its day and year spelled out in words, or its year in roman
+// numerals. They take the locales of tool error messages
+const (
+	LocaleEnglish      = "en"
+	LocalePortugueseBR = "pt-BR"
+	LocaleSpanish      = "es"
+)
+
+// maxRomanYear is the last year roman numerals can write without a vinculum
+const maxRomanYear = 3999
+
+// dateWriter writes dates in one language
+type dateWriter struct {
+	months   [12]string
+	cardinal func(n int) string
+	// day writes the day of the month in words
+	day func(n int) string
+	// layout joins the month, day and year
+	layout func(month, day, year string) string
+}
+
+var dateWriters = map[string]dateWriter{
+	LocaleEnglish: {
+		months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		cardinal: englishCardinal,
+		day:      englishOrdinal,
+		layout: func(month, day, year string) string {
+			return fmt.Sprintf("%s %s, %s", month, day, year)
+		},
+	},
+	LocalePortugueseBR: {
+		months: [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		cardinal: portugueseCardinal,
+		day: func(n int) string {
+			if n == 1 {
+				return "primeiro"
+			}
+			return portugueseCardinal(n)
+		},
+		layout: func(month, day, year string) string {
+			return fmt.Sprintf("%s de %s de %s", day, month, year)
+		},
+	},
+	LocaleSpanish: {
+		months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		cardinal: spanishCardinal,
+		day: func(n int) string {
+			if n == 1 {
+				return "primero"
+			}
+			return spanishCardinal(n)
+		},
+		layout: func(month, day, year string) string {
+			return fmt.Sprintf("%s de %s de %s", day, month, year)
+		},
+	},
+}
+
+// writeDate writes the date of t in locale, with the day and year in
+// words when spellOut is set and the year in roman numerals when
+// romanYear is
+func writeDate(t time.Time, locale string, spellOut, romanYear bool) (string, error) {
+	if locale == "" {
+		locale = LocaleEnglish
+	}
+	writer, ok := dateWriters[locale]
+	if !ok {
+		return "", timeerrors.Errorf(timeerrors.ErrInvalidArgument, "unsupported locale %q (supported: %s, %s, %s)",
+			locale, LocaleEnglish, LocalePortugueseBR, LocaleSpanish)
+	}
+	if t.Year() < 1 || t.Year() > 9999 {
+		return "", timeerrors.Errorf(timeerrors.ErrOutOfRange, "%s cannot be written out: only years 1 to 9999 are supported", t.Format(time.RFC3339))
+	}
+
+	day := strconv.Itoa(t.Day())
+	year := strconv.Itoa(t.Year())
+	if spellOut {
+		day = writer.day(t.Day())
+		year = writer.cardinal(t.Year())
+	}
+	if romanYear {
+		var err error
+		if year, err = roman(t.Year()); err != nil {
+			return "", err
+		}
+	}
+	return writer.layout(writer.months[t.Month()-1], day, year), nil
+}
+
+// romanNumerals pairs roman numerals with their values, largest first,
+// including the subtractive pairs
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+	{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// roman writes a year in roman numerals
+func roman(year int) (string, error) {
+	if year < 1 || year > maxRomanYear {
+		return "", timeerrors.Errorf(timeerrors.ErrOutOfRange, "year %d cannot be written in roman numerals: only years 1 to %d are supported", year, maxRomanYear)
+	}
+	var b strings.Builder
+	for _, r := range romanNumerals {
+		for ; year >= r.value; year -= r.value {
+			b.WriteString(r.numeral)
+		}
+	}
+	return b.String(), nil
+}
+
+var (
+	englishOnes = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	englishTens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	// englishOrdinalWords are the ordinals not made by adding -th
+	englishOrdinalWords = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+// englishCardinal writes 0 to 9999 in English words, such as two thousand
+// twenty-three
+func englishCardinal(n int) string {
+	var words []string
+	if n >= 1000 {
+		words = append(words, englishOnes[n/1000], "thousand")
+		n %= 1000
+	}
+	if n >= 100 {
+		words = append(words, englishOnes[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		words = append(words, englishTens[n/10]+"-"+englishOnes[n%10])
+	case n >= 20:
+		words = append(words, englishTens[n/10])
+	case n > 0 || len(words) == 0:
+		words = append(words, englishOnes[n])
+	}
+	return strings.Join(words, " ")
+}
+
+// englishOrdinal writes an ordinal in English words, such as twenty-fifth
+func englishOrdinal(n int) string {
+	cardinal := englishCardinal(n)
+	i := strings.LastIndexAny(cardinal, " -") + 1
+	last := cardinal[i:]
+	switch ordinal, ok := englishOrdinalWords[last]; {
+	case ok:
+		last = ordinal
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return cardinal[:i] + last
+}
+
+var (
+	portugueseOnes = []string{"zero", "um", "dois", "três", "quatro", "cinco", "seis", "sete", "oito", "nove",
+		"dez", "onze", "doze", "treze", "quatorze", "quinze", "dezesseis", "dezessete", "dezoito", "dezenove"}
+	portugueseTens     = []string{"", "", "vinte", "trinta", "quarenta", "cinquenta", "sessenta", "setenta", "oitenta", "noventa"}
+	portugueseHundreds = []string{"", "cento", "duzentos", "trezentos", "quatrocentos", "quinhentos",
+		"seiscentos", "setecentos", "oitocentos", "novecentos"}
+)
+
+// portugueseCardinal writes 0 to 9999 in Brazilian Portuguese words, such
+// as dois mil e vinte e três
+func portugueseCardinal(n int) string {
+	if n == 0 {
+		return portugueseOnes[0]
+	}
+	var parts []string
+	if n >= 1000 {
+		if n/1000 == 1 {
+			parts = append(parts, "mil")
+		} else {
+			parts = append(parts, portugueseOnes[n/1000]+" mil")
+		}
+		n %= 1000
+		if n == 0 {
+			return parts[0]
+		}
+		// "e" follows mil before a round hundred or anything under 100
+		if n < 100 || n%100 == 0 {
+			return parts[0] + " e " + portugueseCardinal(n)
+		}
+	}
+	var below []string
+	switch {
+	case n == 100:
+		below = append(below, "cem")
+		n = 0
+	case n > 100:
+		below = append(below, portugueseHundreds[n/100])
+		n %= 100
+	}
+	switch {
+	case n >= 20:
+		below = append(below, portugueseTens[n/10])
+		if n%10 != 0 {
+			below = append(below, portugueseOnes[n%10])
+		}
+	case n > 0:
+		below = append(below, portugueseOnes[n])
+	}
+	return strings.Join(append(parts, strings.Join(below, " e ")), " ")
+}
+
+var (
+	spanishOnes = []string{"cero", "uno", "dos", "tres", "cuatro", "cinco", "seis", "siete", "ocho", "nueve",
+		"diez", "once", "doce", "trece", "catorce", "quince", "dieciséis", "diecisiete", "dieciocho", "diecinueve",
+		"veinte", "veintiuno", "veintidós", "veintitrés", "veinticuatro", "veinticinco", "veintiséis", "veintisiete", "veintiocho", "veintinueve"}
+	spanishTens     = []string{"", "", "", "treinta", "cuarenta", "cincuenta", "sesenta", "setenta", "ochenta", "noventa"}
+	spanishHundreds = []string{"", "ciento", "doscientos", "trescientos", "cuatrocientos", "quinientos",
+		"seiscientos", "setecientos", "ochocientos", "novecientos"}
+)
+
+// spanishCardinal writes 0 to 9999 in Spanish words, such as dos mil
+// veintitrés
+func spanishCardinal(n int) string {
+	if n == 0 {
+		return spanishOnes[0]
+	}
+	var words []string
+	if n >= 1000 {
+		if n/1000 > 1 {
+			words = append(words, spanishOnes[n/1000])
+		}
+		words = append(words, "mil")
+		n %= 1000
+	}
+	switch {
+	case n == 100:
+		words = append(words, "cien")
+		n = 0
+	case n > 100:
+		words = append(words, spanishHundreds[n/100])
+		n %= 100
+	}
+	switch {
+	case n >= 30 && n%10 != 0:
+		words = append(words, spanishTens[n/10], "y", spanishOnes[n%10])
+	case n >= 30:
+		words = append(words, spanishTens[n/10])
+	case n > 0:
+		words = append(words, spanishOnes[n])
+	}
+	return strings.Join(words, " ")
+}
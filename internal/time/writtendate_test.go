@@ -0,0 +1,74 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestWriteDate(t *testing.T) {
+	christmas := time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+	tests := []struct {
+		name      string
+		at        time.Time
+		locale    string
+		spellOut  bool
+		romanYear bool
+		expected  string
+	}{
+		{name: "spelled", at: christmas, spellOut: true, expected: "December twenty-fifth, two thousand twenty-three"},
+		{name: "roman year", at: christmas, locale: LocaleEnglish, romanYear: true, expected: "December 25, MMXXIII"},
+		{name: "both", at: christmas, locale: LocaleEnglish, spellOut: true, romanYear: true, expected: "December twenty-fifth, MMXXIII"},
+		{name: "english ordinals", at: time.Date(1999, 7, 12, 0, 0, 0, 0, time.UTC), spellOut: true, expected: "July twelfth, one thousand nine hundred ninety-nine"},
+		{name: "english thirtieth", at: time.Date(1900, 4, 30, 0, 0, 0, 0, time.UTC), spellOut: true, expected: "April thirtieth, one thousand nine hundred"},
+		{name: "portuguese", at: christmas, locale: LocalePortugueseBR, spellOut: true, expected: "vinte e cinco de dezembro de dois mil e vinte e três"},
+		{name: "portuguese first", at: time.Date(1999, 3, 1, 0, 0, 0, 0, time.UTC), locale: LocalePortugueseBR, spellOut: true, expected: "primeiro de março de mil novecentos e noventa e nove"},
+		{name: "portuguese round", at: time.Date(2100, 1, 21, 0, 0, 0, 0, time.UTC), locale: LocalePortugueseBR, spellOut: true, expected: "vinte e um de janeiro de dois mil e cem"},
+		{name: "portuguese roman", at: christmas, locale: LocalePortugueseBR, romanYear: true, expected: "25 de dezembro de MMXXIII"},
+		{name: "spanish", at: christmas, locale: LocaleSpanish, spellOut: true, expected: "veinticinco de diciembre de dos mil veintitrés"},
+		{name: "spanish first", at: time.Date(1984, 5, 1, 0, 0, 0, 0, time.UTC), locale: LocaleSpanish, spellOut: true, expected: "primero de mayo de mil novecientos ochenta y cuatro"},
+		{name: "spanish roman", at: time.Date(1666, 9, 2, 0, 0, 0, 0, time.UTC), locale: LocaleSpanish, romanYear: true, expected: "2 de septiembre de MDCLXVI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written, err := writeDate(tt.at, tt.locale, tt.spellOut, tt.romanYear)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, written)
+		})
+	}
+
+	_, err := writeDate(christmas, "de", true, false)
+	assert.True(t, errors.Is(err, timeerrors.ErrInvalidArgument))
+	_, err = writeDate(time.Date(4000, 1, 1, 0, 0, 0, 0, time.UTC), LocaleEnglish, false, true)
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+	_, err = writeDate(time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), LocaleEnglish, true, false)
+	assert.True(t, errors.Is(err, timeerrors.ErrOutOfRange))
+}
+
+func TestRoman(t *testing.T) {
+	for year, expected := range map[int]string{1: "I", 4: "IV", 9: "IX", 14: "XIV", 40: "XL", 90: "XC", 400: "CD", 1994: "MCMXCIV", 3999: "MMMCMXCIX"} {
+		numeral, err := roman(year)
+		require.NoError(t, err)
+		assert.Equal(t, expected, numeral, year)
+	}
+}
+
+func TestFormatTime_WrittenDate(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t))
+
+	// The date is the one in the target timezone
+	result, err := service.FormatTime(FormatTimeInput{Timestamp: RFC3339Timestamp("2023-12-31T23:30:00Z"), Format: "RFC3339",
+		Timezone: "Asia/Tokyo", SpellOut: true, Locale: LocaleSpanish})
+	require.NoError(t, err)
+	assert.Equal(t, "primero de enero de dos mil veinticuatro", result.WrittenDate)
+
+	result, err = service.FormatTime(FormatTimeInput{Timestamp: RFC3339Timestamp("2023-12-31T23:30:00Z"), Format: "RFC3339"})
+	require.NoError(t, err)
+	assert.Empty(t, result.WrittenDate)
+}
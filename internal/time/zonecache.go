@@ -0,0 +1,198 @@
+package time
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Years before and after startup covered by the transition index of a
+// preloaded zone, enough for year-ahead lookups over a long-running process
+const (
+	zoneIndexBefore = 1
+	zoneIndexAfter  = 2
+)
+
+// zoneCache memoizes loaded locations, since time.LoadLocation reads and
+// parses tzdata on every call, and holds transition indexes for preloaded zones
+type zoneCache struct {
+	mu        sync.RWMutex
+	locations map[string]*time.Location
+	indexes   map[*time.Location]*zoneIndex
+	// loader reads zones the cache doesn't hold; nil uses time.LoadLocation
+	loader ZoneLoader
+}
+
+// ZoneLoader loads a zone by its IANA name
+type ZoneLoader func(name string) (*time.Location, error)
+
+// load returns the cached location for name, loading it on first use.
+// Failed loads are not cached so the cache only grows with valid zones
+func (c *zoneCache) load(name string) (*time.Location, error) {
+	c.mu.RLock()
+	loc, ok := c.locations[name]
+	c.mu.RUnlock()
+	if ok {
+		return loc, nil
+	}
+
+	loader := c.loader
+	if loader == nil {
+		loader = time.LoadLocation
+	}
+	loc, err := loader(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.locations[name]; ok {
+		return cached, nil
+	}
+	if c.locations == nil {
+		c.locations = make(map[string]*time.Location)
+	}
+	c.locations[name] = loc
+	return loc, nil
+}
+
+// index returns the transition index built for loc, if any
+func (c *zoneCache) index(loc *time.Location) *zoneIndex {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.indexes[loc]
+}
+
+// preload loads name and indexes its offset transitions around now
+func (c *zoneCache) preload(name string, now time.Time) (*zoneIndex, error) {
+	loc, err := c.load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	index := buildZoneIndex(loc, now.AddDate(-zoneIndexBefore, 0, 0), now.AddDate(zoneIndexAfter, 0, 0))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.indexes == nil {
+		c.indexes = make(map[*time.Location]*zoneIndex)
+	}
+	c.indexes[loc] = index
+	return index, nil
+}
+
+// zoneIndex lists the instants within [from, to) at which a zone's UTC
+// offset or DST flag changes
+type zoneIndex struct {
+	from        time.Time
+	to          time.Time
+	transitions []time.Time
+}
+
+// buildZoneIndex scans [from, to) a day at a time and bisects every state
+// change down to the second
+func buildZoneIndex(loc *time.Location, from, to time.Time) *zoneIndex {
+	from = from.Truncate(time.Second)
+	index := &zoneIndex{from: from, to: to}
+
+	prev := from
+	prevState := zoneStateAt(prev, loc)
+	for prev.Before(to) {
+		next := prev.Add(24 * time.Hour)
+		nextState := zoneStateAt(next, loc)
+		if nextState != prevState {
+			lo, hi := prev, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+				if zoneStateAt(mid, loc) == prevState {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			if hi.Before(to) {
+				index.transitions = append(index.transitions, hi)
+			}
+		}
+		prev, prevState = next, nextState
+	}
+
+	return index
+}
+
+// stepsBeforeTransition returns how many whole days t can be advanced in
+// its own zone while staying before the next state change, capped at
+// limit. ok is false when the index does not cover the lookup
+func (z *zoneIndex) stepsBeforeTransition(t time.Time, limit int) (steps int, ok bool) {
+	horizon := t.AddDate(0, 0, limit+1)
+	if t.Before(z.from) || !horizon.Before(z.to) {
+		return 0, false
+	}
+
+	i := sort.Search(len(z.transitions), func(i int) bool {
+		return z.transitions[i].After(t)
+	})
+	if i == len(z.transitions) || !z.transitions[i].Before(horizon) {
+		return limit, true
+	}
+	transition := z.transitions[i]
+
+	// No state changes before the transition, so day steps up to it are
+	// plain calendar additions; start just short of the estimate and walk up
+	steps = int(transition.Sub(t)/(24*time.Hour)) - 1
+	if steps < 0 {
+		steps = 0
+	}
+	for steps < limit && t.AddDate(0, 0, steps+1).Before(transition) {
+		steps++
+	}
+	return steps, true
+}
+
+// loadLocation resolves a timezone name through the service's location cache
+func (s *timeService) loadLocation(name string) (*time.Location, error) {
+	return s.zones.load(name)
+}
+
+// WithPreloadedTimezones loads the named zones and indexes their DST
+// transitions when the service is built, so first requests for common
+// zones skip tzdata reads and the day-by-day transition scan
+func WithPreloadedTimezones(names []string) Option {
+	return func(s *timeService) {
+		s.preloadTimezones = append(s.preloadTimezones, names...)
+	}
+}
+
+// WithZoneLoader loads zones with loader instead of time.LoadLocation,
+// such as from a pinned tzdata snapshot
+func WithZoneLoader(loader ZoneLoader) Option {
+	return func(s *timeService) {
+		s.zones.loader = loader
+	}
+}
+
+// preloadZones warms the zone cache with the configured zones
+func (s *timeService) preloadZones() {
+	if len(s.preloadTimezones) == 0 {
+		return
+	}
+
+	start := time.Now()
+	now := s.clock.Now()
+	loaded, transitions := 0, 0
+	for _, name := range s.preloadTimezones {
+		index, err := s.zones.preload(name, now)
+		if err != nil {
+			s.logger.Warn("Failed to preload timezone", "timezone", name, "error", err)
+			continue
+		}
+		loaded++
+		transitions += len(index.transitions)
+	}
+
+	s.logger.Info("Preloaded timezones",
+		"timezones", loaded,
+		"transitions", transitions,
+		"duration", time.Since(start))
+}
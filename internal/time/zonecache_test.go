@@ -0,0 +1,114 @@
+package time
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+func TestZoneCache_Load(t *testing.T) {
+	var cache zoneCache
+
+	first, err := cache.load("America/New_York")
+	require.NoError(t, err)
+	second, err := cache.load("America/New_York")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = cache.load("Invalid/Zone")
+	assert.Error(t, err)
+	assert.NotContains(t, cache.locations, "Invalid/Zone")
+}
+
+func TestBuildZoneIndex(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	index := buildZoneIndex(loc,
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.March, 10, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, time.November, 3, 6, 0, 0, 0, time.UTC),
+	}, index.transitions)
+
+	utc := buildZoneIndex(time.UTC,
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	assert.Empty(t, utc.transitions)
+}
+
+func TestTimeService_PreloadedTimezones_MatchScan(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+	zones := []string{"America/New_York", "Europe/London", "Australia/Sydney", "Asia/Tokyo", "Australia/Lord_Howe", "UTC"}
+
+	plain := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t),
+		WithClock(FixedClock{Time: now})).(*timeService)
+	preloaded := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t),
+		WithClock(FixedClock{Time: now}), WithPreloadedTimezones(zones)).(*timeService)
+
+	for _, zone := range zones {
+		loc, err := preloaded.loadLocation(zone)
+		require.NoError(t, err)
+		require.NotNil(t, preloaded.zones.index(loc), zone)
+
+		plainLoc, err := plain.loadLocation(zone)
+		require.NoError(t, err)
+		require.Nil(t, plain.zones.index(plainLoc))
+
+		// Reference times at assorted wall clocks, including ones that land in
+		// DST gaps on later days, must give the same answer as the full scan
+		for hours := 0; hours < 24*200; hours += 37 {
+			ref := now.Add(time.Duration(hours) * time.Hour)
+			want := plain.getNextDSTTransition(ref.In(plainLoc), plainLoc, 365)
+			got := preloaded.getNextDSTTransition(ref.In(loc), loc, 365)
+			if want == nil {
+				assert.Nil(t, got, "%s at %s", zone, ref)
+				continue
+			}
+			require.NotNil(t, got, "%s at %s", zone, ref)
+			assert.True(t, want.NextTransition.Equal(got.NextTransition), "%s at %s", zone, ref)
+			assert.Equal(t, want.TransitionType, got.TransitionType)
+			assert.Equal(t, want.OffsetChange, got.OffsetChange)
+		}
+	}
+}
+
+func TestTimeService_PreloadedTimezones_SkipsInvalid(t *testing.T) {
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t),
+		WithPreloadedTimezones([]string{"Invalid/Zone", "Europe/Paris"})).(*timeService)
+
+	assert.Contains(t, service.zones.locations, "Europe/Paris")
+	assert.Len(t, service.zones.indexes, 1)
+}
+
+func TestTimeService_ZoneLoader(t *testing.T) {
+	// A loader that only knows one zone, like a pinned snapshot
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	loads := 0
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t),
+		WithZoneLoader(func(name string) (*time.Location, error) {
+			loads++
+			if name != "Asia/Kolkata" {
+				return nil, fmt.Errorf("unknown time zone %s", name)
+			}
+			return kolkata, nil
+		}))
+
+	for i := 0; i < 2; i++ {
+		result, err := service.FormatTime(FormatTimeInput{Timestamp: RFC3339Timestamp("2024-01-01T00:00:00Z"), Timezone: "Asia/Kolkata"})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-01T05:30:00+05:30", result.FormattedTime)
+	}
+	assert.Equal(t, 1, loads)
+
+	// Zones the loader lacks don't fall back to the host's tzdata
+	_, err := service.FormatTime(FormatTimeInput{Timestamp: RFC3339Timestamp("2024-01-01T00:00:00Z"), Timezone: "Europe/Paris"})
+	assert.ErrorIs(t, err, timeerrors.ErrInvalidTimezone)
+}
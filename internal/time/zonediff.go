@@ -0,0 +1,302 @@
+package time
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// DefaultTZDataSource names the tzdata the process loads zones from
+const DefaultTZDataSource = "default"
+
+// ZoneRulesDiffInput represents input for comparing a zone's rules
+type ZoneRulesDiffInput struct {
+	Timezone string    `json:"timezone"`
+	From     Timestamp `json:"from,omitempty"` // defaults to now
+	// To is the second reference date; defaults to From when comparing tzdata versions
+	To Timestamp `json:"to,omitempty"`
+	// FromTZData and ToTZData name configured tzdata sources; both default to "default"
+	FromTZData string `json:"from_tzdata,omitempty"`
+	ToTZData   string `json:"to_tzdata,omitempty"`
+}
+
+// ZoneRulesDiffResult represents the rules on both sides and how they differ
+type ZoneRulesDiffResult struct {
+	Timezone    string           `json:"timezone"`
+	From        ZoneRules        `json:"from"`
+	To          ZoneRules        `json:"to"`
+	Changed     bool             `json:"changed"`
+	Differences []ZoneDifference `json:"differences"`
+}
+
+// ZoneRules describes a zone as of a reference date, with the DST windows
+// of the reference date's year
+type ZoneRules struct {
+	Reference             string      `json:"reference"`
+	TZData                string      `json:"tzdata"`
+	Abbreviation          string      `json:"abbreviation"`
+	Offset                string      `json:"offset"`
+	StandardAbbreviation  string      `json:"standard_abbreviation"`
+	StandardOffset        string      `json:"standard_offset"`
+	StandardOffsetSeconds int         `json:"standard_offset_seconds"`
+	DSTWindows            []DSTWindow `json:"dst_windows"`
+}
+
+// DSTWindow is a DST period within a year. The rules describe the local
+// wall clock the change happens at, e.g. "last Sunday of March at 01:00",
+// so they can be compared across years
+type DSTWindow struct {
+	Start         string `json:"start"`
+	End           string `json:"end"`
+	StartRule     string `json:"start_rule"`
+	EndRule       string `json:"end_rule"`
+	Abbreviation  string `json:"abbreviation"`
+	SavingSeconds int    `json:"saving_seconds"`
+}
+
+// ZoneDifference is one rule that differs between the two sides
+type ZoneDifference struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// WithTZDataSources registers named tzdata sources for version comparisons.
+// A source is a zoneinfo directory or a zoneinfo.zip archive
+func WithTZDataSources(sources map[string]string) Option {
+	return func(s *timeService) {
+		if s.tzdataSources == nil {
+			s.tzdataSources = make(map[string]string)
+		}
+		for name, path := range sources {
+			s.tzdataSources[name] = path
+		}
+	}
+}
+
+// DiffZoneRules compares a zone's rules between two reference dates or two tzdata sources
+func (s *timeService) DiffZoneRules(input ZoneRulesDiffInput) (ZoneRulesDiffResult, error) {
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+
+	from := s.clock.Now()
+	if !input.From.IsZero() {
+		var err error
+		if from, err = input.From.Resolve(); err != nil {
+			return ZoneRulesDiffResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid from: %w", err)
+		}
+	}
+
+	fromSource := defaultString(input.FromTZData, DefaultTZDataSource)
+	toSource := defaultString(input.ToTZData, DefaultTZDataSource)
+
+	to := from
+	switch {
+	case !input.To.IsZero():
+		var err error
+		if to, err = input.To.Resolve(); err != nil {
+			return ZoneRulesDiffResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "invalid to: %w", err)
+		}
+	case fromSource == toSource:
+		return ZoneRulesDiffResult{}, timeerrors.Errorf(timeerrors.ErrInvalidArgument,
+			"set to, or from_tzdata and to_tzdata naming different sources")
+	}
+
+	fromLoc, err := s.loadLocationFrom(fromSource, timezone)
+	if err != nil {
+		return ZoneRulesDiffResult{}, err
+	}
+	toLoc, err := s.loadLocationFrom(toSource, timezone)
+	if err != nil {
+		return ZoneRulesDiffResult{}, err
+	}
+
+	result := ZoneRulesDiffResult{
+		Timezone: timezone,
+		From:     zoneRulesAt(from, fromLoc, fromSource, s.roundOffsets),
+		To:       zoneRulesAt(to, toLoc, toSource, s.roundOffsets),
+	}
+	result.Differences = diffZoneRules(result.From, result.To)
+	result.Changed = len(result.Differences) > 0
+
+	return result, nil
+}
+
+// loadLocationFrom loads a zone from the named tzdata source
+func (s *timeService) loadLocationFrom(source, name string) (*time.Location, error) {
+	if source == DefaultTZDataSource {
+		loc, err := s.loadLocation(name)
+		if err != nil {
+			return nil, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s: %w", name, err)
+		}
+		return loc, nil
+	}
+
+	path, ok := s.tzdataSources[source]
+	if !ok {
+		return nil, timeerrors.Errorf(timeerrors.ErrInvalidArgument, "unknown tzdata source %q (configured: %s)",
+			source, strings.Join(s.tzdataSourceNames(), ", "))
+	}
+
+	// Zone names index into a directory or archive, so keep them relative
+	if name == "" || filepath.IsAbs(name) || strings.Contains(name, "..") || strings.Contains(name, `\`) {
+		return nil, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid timezone %s", name)
+	}
+
+	data, err := readTZData(path, name)
+	if err != nil {
+		return nil, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "timezone %s not found in tzdata source %s: %w", name, source, err)
+	}
+	loc, err := time.LoadLocationFromTZData(name, data)
+	if err != nil {
+		return nil, timeerrors.Errorf(timeerrors.ErrInvalidTimezone, "invalid tzdata for %s in source %s: %w", name, source, err)
+	}
+	return loc, nil
+}
+
+// tzdataSourceNames lists the sources a request can name, sorted
+func (s *timeService) tzdataSourceNames() []string {
+	names := []string{DefaultTZDataSource}
+	for name := range s.tzdataSources {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// readTZData reads a compiled zone file from a zoneinfo directory or zip archive
+func readTZData(path, name string) ([]byte, error) {
+	if !strings.HasSuffix(path, ".zip") {
+		return os.ReadFile(filepath.Join(path, filepath.FromSlash(name)))
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	f, err := archive.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// zoneRulesAt describes loc at ref, with the DST windows of ref's local
+// year. roundOffsets rounds offsets with seconds as formatOffset does
+func zoneRulesAt(ref time.Time, loc *time.Location, source string, roundOffsets bool) ZoneRules {
+	local := ref.In(loc)
+	abbreviation, offset := local.Zone()
+	rules := ZoneRules{
+		Reference:    local.Format(time.RFC3339),
+		TZData:       source,
+		Abbreviation: abbreviation,
+		Offset:       formatOffset(offset, roundOffsets),
+		DSTWindows:   []DSTWindow{},
+	}
+
+	yearStart := time.Date(local.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+	index := buildZoneIndex(loc, yearStart, yearEnd)
+
+	// Walk the year's segments between transitions
+	bounds := append([]time.Time{yearStart}, index.transitions...)
+	bounds = append(bounds, yearEnd)
+	standardSet := false
+	var windowOffsets []int
+	for i := 0; i+1 < len(bounds); i++ {
+		start, end := bounds[i], bounds[i+1]
+		state := zoneStateAt(start, loc)
+		name, _ := start.In(loc).Zone()
+
+		if !state.dst {
+			// Prefer the standard time in effect at the reference date
+			if !standardSet || (!ref.Before(start) && ref.Before(end)) {
+				rules.StandardAbbreviation = name
+				rules.StandardOffsetSeconds = state.offset
+				standardSet = true
+			}
+			continue
+		}
+
+		window := DSTWindow{
+			Start:        start.In(loc).Format(time.RFC3339),
+			End:          end.In(loc).Format(time.RFC3339),
+			Abbreviation: name,
+			StartRule:    "in effect at start of year",
+			EndRule:      "in effect at end of year",
+		}
+		if i > 0 {
+			window.StartRule = transitionRule(start, zoneStateAt(start.Add(-time.Second), loc))
+		}
+		if i+2 < len(bounds) {
+			window.EndRule = transitionRule(end, state)
+		}
+		rules.DSTWindows = append(rules.DSTWindows, window)
+		windowOffsets = append(windowOffsets, state.offset)
+	}
+
+	if !standardSet {
+		// DST all year has no standard segment to read, so fall back to the
+		// reference offset
+		rules.StandardAbbreviation = abbreviation
+		rules.StandardOffsetSeconds = offset
+	}
+	rules.StandardOffset = formatOffset(rules.StandardOffsetSeconds, roundOffsets)
+	for i, windowOffset := range windowOffsets {
+		rules.DSTWindows[i].SavingSeconds = windowOffset - rules.StandardOffsetSeconds
+	}
+
+	return rules
+}
+
+// transitionRule describes when a transition happens on the wall clock in
+// effect before it, as tzdata rules are written
+func transitionRule(at time.Time, before zoneState) string {
+	wall := at.In(time.FixedZone("", before.offset))
+	daysInMonth := time.Date(wall.Year(), wall.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	ordinal := [...]string{"first", "second", "third", "fourth", "fifth"}[(wall.Day()-1)/7]
+	if wall.Day()+7 > daysInMonth {
+		ordinal = "last"
+	}
+	return fmt.Sprintf("%s %s of %s at %s", ordinal, wall.Weekday(), wall.Month(), wall.Format("15:04"))
+}
+
+// diffZoneRules lists the rules that differ between two descriptions
+func diffZoneRules(from, to ZoneRules) []ZoneDifference {
+	diffs := []ZoneDifference{}
+	add := func(field, a, b string) {
+		if a != b {
+			diffs = append(diffs, ZoneDifference{Field: field, From: a, To: b})
+		}
+	}
+
+	add("offset", from.Offset, to.Offset)
+	add("abbreviation", from.Abbreviation, to.Abbreviation)
+	add("standard_offset", from.StandardOffset, to.StandardOffset)
+	add("standard_abbreviation", from.StandardAbbreviation, to.StandardAbbreviation)
+	add("dst_windows", fmt.Sprint(len(from.DSTWindows)), fmt.Sprint(len(to.DSTWindows)))
+
+	for i := 0; i < len(from.DSTWindows) && i < len(to.DSTWindows); i++ {
+		a, b := from.DSTWindows[i], to.DSTWindows[i]
+		prefix := fmt.Sprintf("dst_windows[%d].", i)
+		add(prefix+"start_rule", a.StartRule, b.StartRule)
+		add(prefix+"end_rule", a.EndRule, b.EndRule)
+		add(prefix+"abbreviation", a.Abbreviation, b.Abbreviation)
+		add(prefix+"saving_seconds", fmt.Sprint(a.SavingSeconds), fmt.Sprint(b.SavingSeconds))
+	}
+
+	return diffs
+}
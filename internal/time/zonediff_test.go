@@ -0,0 +1,140 @@
+package time
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneRulesAt(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	rules := zoneRulesAt(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), newYork, DefaultTZDataSource, false)
+	assert.Equal(t, "EDT", rules.Abbreviation)
+	assert.Equal(t, "-04:00", rules.Offset)
+	assert.Equal(t, "EST", rules.StandardAbbreviation)
+	assert.Equal(t, "-05:00", rules.StandardOffset)
+	assert.Equal(t, []DSTWindow{{
+		Start:         "2024-03-10T03:00:00-04:00",
+		End:           "2024-11-03T01:00:00-05:00",
+		StartRule:     "second Sunday of March at 02:00",
+		EndRule:       "first Sunday of November at 02:00",
+		Abbreviation:  "EDT",
+		SavingSeconds: 3600,
+	}}, rules.DSTWindows)
+
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
+	rules = zoneRulesAt(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), sydney, DefaultTZDataSource, false)
+	require.Len(t, rules.DSTWindows, 2)
+	assert.Equal(t, "in effect at start of year", rules.DSTWindows[0].StartRule)
+	assert.Equal(t, "first Sunday of April at 03:00", rules.DSTWindows[0].EndRule)
+	assert.Equal(t, "first Sunday of October at 02:00", rules.DSTWindows[1].StartRule)
+	assert.Equal(t, "in effect at end of year", rules.DSTWindows[1].EndRule)
+}
+
+func TestTimeService_DiffZoneRules(t *testing.T) {
+	// A fake older release where New York follows Tokyo's rules
+	goZip := filepath.Join(runtime.GOROOT(), "lib", "time", "zoneinfo.zip")
+	fake := t.TempDir()
+	data, err := readTZData(goZip, "Asia/Tokyo")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(fake, "America"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(fake, "America", "New_York"), data, 0o644))
+
+	service := NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, newTestLogger(t),
+		WithTZDataSources(map[string]string{"go": goZip, "fake": fake}))
+
+	tests := []struct {
+		name    string
+		input   ZoneRulesDiffInput
+		changed bool
+		fields  []string
+		wantErr bool
+	}{
+		{
+			name: "same rules in different years",
+			input: ZoneRulesDiffInput{Timezone: "America/New_York",
+				From: RFC3339Timestamp("2023-06-01T00:00:00Z"), To: RFC3339Timestamp("2024-06-01T00:00:00Z")},
+		},
+		{
+			name: "DST made permanent",
+			input: ZoneRulesDiffInput{Timezone: "Europe/Istanbul",
+				From: RFC3339Timestamp("2015-06-01T00:00:00Z"), To: RFC3339Timestamp("2017-06-01T00:00:00Z")},
+			changed: true,
+			fields:  []string{"abbreviation", "standard_offset", "standard_abbreviation", "dst_windows"},
+		},
+		{
+			name: "US rules changed in 2007",
+			input: ZoneRulesDiffInput{Timezone: "America/New_York",
+				From: RFC3339Timestamp("2006-06-01T00:00:00Z"), To: RFC3339Timestamp("2007-06-01T00:00:00Z")},
+			changed: true,
+			fields:  []string{"dst_windows[0].start_rule", "dst_windows[0].end_rule"},
+		},
+		{
+			name: "identical tzdata sources",
+			input: ZoneRulesDiffInput{Timezone: "America/New_York",
+				From: RFC3339Timestamp("2024-06-01T00:00:00Z"), ToTZData: "go"},
+		},
+		{
+			name: "changed tzdata source",
+			input: ZoneRulesDiffInput{Timezone: "America/New_York",
+				From: RFC3339Timestamp("2024-06-01T00:00:00Z"), FromTZData: "fake", ToTZData: "go"},
+			changed: true,
+			fields:  []string{"offset", "abbreviation", "standard_offset", "standard_abbreviation", "dst_windows"},
+		},
+		{
+			name:    "nothing to compare",
+			input:   ZoneRulesDiffInput{Timezone: "America/New_York", From: RFC3339Timestamp("2024-06-01T00:00:00Z")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown source",
+			input:   ZoneRulesDiffInput{Timezone: "America/New_York", ToTZData: "2019c"},
+			wantErr: true,
+		},
+		{
+			name:    "zone missing from source",
+			input:   ZoneRulesDiffInput{Timezone: "Europe/Paris", ToTZData: "fake"},
+			wantErr: true,
+		},
+		{
+			name:    "path traversal",
+			input:   ZoneRulesDiffInput{Timezone: "../../etc/passwd", ToTZData: "fake"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			input:   ZoneRulesDiffInput{Timezone: "Invalid/Zone", To: RFC3339Timestamp("2024-06-01T00:00:00Z")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.DiffZoneRules(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.changed, result.Changed)
+			fields := []string{}
+			for _, diff := range result.Differences {
+				fields = append(fields, diff.Field)
+			}
+			if tt.fields == nil {
+				tt.fields = []string{}
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
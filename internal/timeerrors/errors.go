@@ -0,0 +1,60 @@
+// Package timeerrors defines the error kinds returned by the time service.
+//
+// Errors are tagged with a sentinel kind while keeping their message, so
+// callers can classify them with errors.Is:
+//
+//	if errors.Is(err, timeerrors.ErrInvalidTimezone) { ... }
+package timeerrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel error kinds
+var (
+	// ErrInvalidTimezone is returned for unknown IANA timezone names
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrInvalidFormat is returned for unsupported or malformed formats
+	ErrInvalidFormat = errors.New("invalid format")
+	// ErrInvalidArgument is returned for any other invalid tool argument
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrParseFailure is returned when a time string or timestamp cannot be parsed
+	ErrParseFailure = errors.New("parse failure")
+	// ErrOutOfRange is returned for instants outside a supported range
+	ErrOutOfRange = errors.New("out of range")
+)
+
+// Error is an error tagged with a sentinel kind. Its message is the message
+// of the wrapped error alone; the kind is only visible to errors.Is.
+type Error struct {
+	Kind error
+	Err  error
+	// Format and Args are what Errorf was called with, so the message can
+	// be rendered again in another language. Wrap leaves them empty
+	Format string
+	Args   []any
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes both the kind and the wrapped error to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Errorf formats an error like fmt.Errorf, including %w, and tags it with kind
+func Errorf(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...), Format: format, Args: args}
+}
+
+// Wrap tags err with kind, or returns nil if err is nil
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
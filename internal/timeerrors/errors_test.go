@@ -0,0 +1,41 @@
+package timeerrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("unknown time zone Mars/Olympus")
+	err := Errorf(ErrInvalidTimezone, "invalid timezone %s: %w", "Mars/Olympus", cause)
+
+	assert.Equal(t, "invalid timezone Mars/Olympus: unknown time zone Mars/Olympus", err.Error())
+	assert.ErrorIs(t, err, ErrInvalidTimezone)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrParseFailure)
+}
+
+func TestErrorf_NestedKinds(t *testing.T) {
+	inner := Errorf(ErrOutOfRange, "epoch is out of range")
+	err := fmt.Errorf("invalid at: %w", Errorf(ErrInvalidArgument, "wrapped: %w", inner))
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	var tagged *Error
+	assert.ErrorAs(t, err, &tagged)
+	assert.Equal(t, ErrInvalidArgument, tagged.Kind)
+}
+
+func TestWrap(t *testing.T) {
+	assert.NoError(t, Wrap(ErrParseFailure, nil))
+
+	cause := errors.New("bad input")
+	err := Wrap(ErrParseFailure, cause)
+	assert.Equal(t, "bad input", err.Error())
+	assert.ErrorIs(t, err, ErrParseFailure)
+	assert.ErrorIs(t, err, cause)
+}
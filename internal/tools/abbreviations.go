@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// zoneAbbreviationsTool serves the get_zone_abbreviations tool
+func zoneAbbreviationsTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "get_zone_abbreviations",
+		Description: "List every abbreviation a timezone has used over time (e.g. LMT, WET/WEST, CET/CEST for Europe/Lisbon), " +
+			"with its offset and the eras it was in use, from tzdata, to disambiguate abbreviations found in old documents",
+		InputSchema: inputSchema[timeservice.ZoneAbbreviationsInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ZoneAbbreviationsInput) (*mcp.CallToolResult, timeservice.ZoneAbbreviationsResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.GetZoneAbbreviations(input)
+		if err != nil {
+			recordError(metrics, "get_zone_abbreviations", "get_zone_abbreviations", startTime, logger, err)
+			return nil, timeservice.ZoneAbbreviationsResult{}, err
+		}
+
+		recordSuccess(metrics, "get_zone_abbreviations", "get_zone_abbreviations", startTime)
+
+		text := fmt.Sprintf("%s abbreviations (tzdata %s, current %s):", result.Timezone, result.TZData, result.Current)
+		if len(result.Abbreviations) == 0 {
+			text = fmt.Sprintf("%s has never used the abbreviation %s (tzdata %s)", result.Timezone, input.Abbreviation, result.TZData)
+		}
+		for _, abbreviation := range result.Abbreviations {
+			label := abbreviation.Offset
+			if abbreviation.DST {
+				label += ", DST"
+			}
+			text += fmt.Sprintf("\n- %s (%s):", abbreviation.Abbreviation, label)
+			for _, era := range abbreviation.Eras {
+				start, end := era.Start, era.End
+				if start == "" {
+					start = "earliest record"
+				}
+				if era.Ongoing {
+					end = "ongoing"
+				}
+				text += fmt.Sprintf(" %s to %s (%d periods);", start, end, era.Periods)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
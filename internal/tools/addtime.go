@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// addTimeTool serves the add_time tool
+func addTimeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "add_time",
+		Description: "Add a duration to a time in a timezone. Calendar semantics (default) add days, weeks, months and years " +
+			"to the wall clock date, so P1D keeps the same wall time across a 23-hour DST day; absolute semantics add " +
+			"elapsed time, so P1D is always 24 hours. month_end picks what Jan 31 + P1M gives: clamp (Feb 28/29), " +
+			"roll_over (into March) or error. Returns the semantics and policy applied and the time actually elapsed",
+		InputSchema: inputSchema[timeservice.AddTimeInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.AddTimeInput) (*mcp.CallToolResult, timeservice.AddTimeResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.AddTime(input)
+		if err != nil {
+			recordError(metrics, "add_time", "add_time", startTime, logger, err)
+			return nil, timeservice.AddTimeResult{}, err
+		}
+
+		recordSuccess(metrics, "add_time", "add_time", startTime)
+
+		text := fmt.Sprintf("%s + %s = %s (%s semantics, %s elapsed)", result.Start, result.Duration, result.Result,
+			result.Semantics, time.Duration(result.ElapsedSeconds)*time.Second)
+		if result.MonthEnd != "" {
+			text += fmt.Sprintf("\nMonth end: %s", result.MonthEnd)
+		}
+		for _, note := range result.Notes {
+			text += "\nNote: " + note
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
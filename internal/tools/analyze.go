@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// analyzeTimestampsTool serves the analyze_timestamps tool
+func analyzeTimestampsTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "analyze_timestamps",
+		Description: "Analyze a list of timestamps (any order): min/max, mean and median interval, gaps larger " +
+			"than gap_threshold and an events-per-minute histogram",
+		InputSchema: inputSchema[timeservice.AnalyzeTimestampsInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.AnalyzeTimestampsInput) (*mcp.CallToolResult, timeservice.AnalyzeTimestampsResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.AnalyzeTimestamps(input)
+		if err != nil {
+			recordError(metrics, "analyze_timestamps", "analyze_timestamps", startTime, logger, err)
+			return nil, timeservice.AnalyzeTimestampsResult{}, err
+		}
+
+		recordSuccess(metrics, "analyze_timestamps", "analyze_timestamps", startTime)
+
+		text := fmt.Sprintf("Events: %d\nFirst: %s\nLast: %s\nMean interval: %gs\nMedian interval: %gs",
+			result.Count, result.Min, result.Max, result.MeanIntervalSeconds, result.MedianIntervalSeconds)
+		for _, gap := range result.Gaps {
+			text += fmt.Sprintf("\nGap: %s to %s (%s)", gap.Start, gap.End, gap.Duration)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
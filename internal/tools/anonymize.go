@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// anonymizeTimeTool serves the anonymize_time tool
+func anonymizeTimeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "anonymize_time",
+		Description: "Anonymize timestamps for data samples: shift them, add bounded random jitter and round to a " +
+			"window (e.g. 1h, 1d) while preserving their order. Pass seed for reproducible jitter; the seed used is always returned",
+		InputSchema: inputSchema[timeservice.AnonymizeTimeInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.AnonymizeTimeInput) (*mcp.CallToolResult, timeservice.AnonymizeTimeResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.AnonymizeTime(input)
+		if err != nil {
+			recordError(metrics, "anonymize_time", "anonymize_time", startTime, logger, err)
+			return nil, timeservice.AnonymizeTimeResult{}, err
+		}
+
+		recordSuccess(metrics, "anonymize_time", "anonymize_time", startTime)
+
+		text := fmt.Sprintf("Anonymized %d timestamps (seed %d):", len(result.Timestamps), result.Seed)
+		for _, ts := range result.Timestamps {
+			text += "\n- " + ts
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
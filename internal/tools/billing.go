@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// nextBillingDateTool serves the next_billing_date tool
+func nextBillingDateTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "next_billing_date",
+		Description: "List the next charge dates of a monthly or annual subscription from its billing anchor, in the billing " +
+			"timezone. Charges keep the anchor's day and wall time; month_end decides months without that day: " +
+			"clamp (default, the last day), roll_over (into the next month) or error",
+		InputSchema: inputSchema[timeservice.NextBillingDateInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.NextBillingDateInput) (*mcp.CallToolResult, timeservice.NextBillingDateResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.NextBillingDate(input)
+		if err != nil {
+			recordError(metrics, "next_billing_date", "next_billing_date", startTime, logger, err)
+			return nil, timeservice.NextBillingDateResult{}, err
+		}
+
+		recordSuccess(metrics, "next_billing_date", "next_billing_date", startTime)
+
+		text := fmt.Sprintf("Billing %s from %s (month_end %s):", result.Period, result.Anchor, result.MonthEnd)
+		for _, date := range result.Dates {
+			text += fmt.Sprintf("\n%d. %s", date.Period, date.Date)
+			if date.Adjusted {
+				text += " (adjusted)"
+			}
+		}
+		for _, note := range result.Notes {
+			text += "\nNote: " + note
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// bucketTimestampsTool serves the bucket_timestamps tool
+func bucketTimestampsTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "bucket_timestamps",
+		Description: "Group timestamps into fixed windows (e.g. 5m, 1h, 1d, 7d) aligned in a timezone and count them " +
+			"per bucket. Daily buckets follow local midnight, so DST transition days are 23 or 25 hours long",
+		InputSchema: inputSchema[timeservice.BucketTimestampsInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.BucketTimestampsInput) (*mcp.CallToolResult, timeservice.BucketTimestampsResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.BucketTimestamps(input)
+		if err != nil {
+			recordError(metrics, "bucket_timestamps", "bucket_timestamps", startTime, logger, err)
+			return nil, timeservice.BucketTimestampsResult{}, err
+		}
+
+		recordSuccess(metrics, "bucket_timestamps", "bucket_timestamps", startTime)
+
+		text := fmt.Sprintf("Window: %s (%s)\nTotal: %d", result.Window, result.Timezone, result.Total)
+		for _, bucket := range result.Buckets {
+			text += fmt.Sprintf("\n%s: %d", bucket.Start, bucket.Count)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
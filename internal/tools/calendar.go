@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/calendar"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Calendar tool names
+const (
+	freeBusyToolName     = "get_free_busy"
+	nextFreeSlotToolName = "next_free_slot"
+)
+
+// RegisterCalendarTools registers the tools reading the configured calendars
+func RegisterCalendarTools(server *mcp.Server, client *calendar.Client, metrics *metrics.Metrics, logger *zap.Logger) {
+	addProviders(server, make(map[string]bool),
+		freeBusyTool(client, metrics, logger),
+		nextFreeSlotTool(client, metrics, logger))
+}
+
+// freeBusyTool serves the get_free_busy tool
+func freeBusyTool(client *calendar.Client, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: freeBusyToolName,
+		Description: "Get busy and free time over a range from the configured calendars (" + strings.Join(client.Names(), ", ") + "): " +
+			"busy periods per calendar, merged across them, and the free gaps left",
+		InputSchema: inputSchema[calendar.FreeBusyInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input calendar.FreeBusyInput) (*mcp.CallToolResult, calendar.FreeBusyResult, error) {
+		startTime := time.Now()
+
+		result, err := client.FreeBusy(ctx, input)
+		if err != nil {
+			recordError(metrics, freeBusyToolName, "get_free_busy", startTime, logger, err)
+			return nil, calendar.FreeBusyResult{}, err
+		}
+
+		recordSuccess(metrics, freeBusyToolName, "get_free_busy", startTime)
+
+		text := fmt.Sprintf("Free/busy from %s to %s (%s)", result.Start, result.End, result.Timezone)
+		if len(result.Busy) == 0 {
+			text += "\nNo busy time"
+		}
+		for _, period := range result.Busy {
+			text += fmt.Sprintf("\nBusy: %s - %s", period.Start, period.End)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
+
+// nextFreeSlotTool serves the next_free_slot tool
+func nextFreeSlotTool(client *calendar.Client, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: nextFreeSlotToolName,
+		Description: "Find the earliest slot of a given duration free in every selected calendar, " +
+			"optionally within daily working hours",
+		InputSchema: inputSchema[calendar.NextFreeSlotInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input calendar.NextFreeSlotInput) (*mcp.CallToolResult, calendar.NextFreeSlotResult, error) {
+		startTime := time.Now()
+
+		result, err := client.NextFreeSlot(ctx, input)
+		if err != nil {
+			recordError(metrics, nextFreeSlotToolName, "next_free_slot", startTime, logger, err)
+			return nil, calendar.NextFreeSlotResult{}, err
+		}
+
+		recordSuccess(metrics, nextFreeSlotToolName, "next_free_slot", startTime)
+
+		text := fmt.Sprintf("No free slot until %s", result.SearchedUntil)
+		if result.Found {
+			text = fmt.Sprintf("Next free slot: %s - %s (%s)", result.Start, result.End, result.Timezone)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/calendar"
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func TestCalendarTools(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20261016T090000Z\r\nDTEND:20261016T100000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")
+	}))
+	t.Cleanup(feed.Close)
+
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	client, err := calendar.New(config.CalendarConfig{
+		Timeout: time.Second,
+		Sources: []config.CalendarSourceConfig{{Name: "team", Type: config.CalendarTypeICS, URL: feed.URL}},
+	}, "UTC", nil, nil, "test", m, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	RegisterCalendarTools(server, client, m, zaptest.NewLogger(t))
+	session := connect(t, server)
+	ctx := context.Background()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "get_free_busy", Arguments: map[string]any{
+		"start": "2026-10-16T08:00:00Z",
+		"end":   "2026-10-16T12:00:00Z",
+	}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+	assert.Equal(t, "Free/busy from 2026-10-16T08:00:00Z to 2026-10-16T12:00:00Z (UTC)\nBusy: 2026-10-16T09:00:00Z - 2026-10-16T10:00:00Z",
+		res.Content[0].(*mcp.TextContent).Text)
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "next_free_slot", Arguments: map[string]any{
+		"duration":      "2h",
+		"after":         "2026-10-16T08:00:00Z",
+		"working_hours": map[string]any{"start": "08:00", "end": "18:00"},
+	}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+	assert.Equal(t, "Next free slot: 2026-10-16T10:00:00Z - 2026-10-16T12:00:00Z (UTC)", res.Content[0].(*mcp.TextContent).Text)
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "next_free_slot", Arguments: map[string]any{
+		"duration":  "1h",
+		"calendars": []string{"payroll"},
+	}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+}
@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// compareClockTool serves the compare_clock tool
+func compareClockTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "compare_clock",
+		Description: "Compare the client's clock with the server's. Send client_time as the client's current time " +
+			"to get the skew. For a latency-corrected offset and round-trip estimate, pass a previous exchange " +
+			"(client_time, server_receive_time, server_transmit_time and the client time the response arrived) as previous",
+		InputSchema: inputSchema[timeservice.CompareClockInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.CompareClockInput) (*mcp.CallToolResult, timeservice.CompareClockResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.CompareClock(input)
+		if err != nil {
+			recordError(metrics, "compare_clock", "compare_clock", startTime, logger, err)
+			return nil, timeservice.CompareClockResult{}, err
+		}
+
+		recordSuccess(metrics, "compare_clock", "compare_clock", startTime)
+
+		text := fmt.Sprintf("Client time: %s\nServer time: %s\nSkew: %s (client clock %s)",
+			result.ClientTime, result.ServerReceiveTime, result.Skew, result.ClientClock)
+		if result.RoundTripMs != nil {
+			text += fmt.Sprintf("\nOffset: %d ms\nRound trip: %d ms", *result.OffsetMs, *result.RoundTripMs)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
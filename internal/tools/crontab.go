@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// auditCrontabTool serves the audit_crontab tool
+func auditCrontabTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "audit_crontab",
+		Description: "Review a crontab: each entry's next runs in the given timezone (CRON_TZ lines are honored), " +
+			"entries that never fire, entries firing in the same minute, and runs a DST change skips or repeats. " +
+			"Set system for the /etc/crontab format with a user field",
+		InputSchema: inputSchema[timeservice.AuditCrontabInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.AuditCrontabInput) (*mcp.CallToolResult, timeservice.AuditCrontabResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.AuditCrontab(input)
+		if err != nil {
+			recordError(metrics, "audit_crontab", "audit_crontab", startTime, logger, err)
+			return nil, timeservice.AuditCrontabResult{}, err
+		}
+
+		recordSuccess(metrics, "audit_crontab", "audit_crontab", startTime)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: formatCrontabAudit(result)},
+			},
+		}, result, nil
+	})
+}
+
+// checkCronJobTool serves the check_cronjob_schedule tool
+func checkCronJobTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "check_cronjob_schedule",
+		Description: "Explain when a Kubernetes CronJob actually fires: its schedule in spec.timeZone, or in the " +
+			"controller's timezone (usually UTC) when unset, with runs shown in UTC and the viewer's zone. " +
+			"Flags runs a DST change skips or repeats and CRON_TZ= prefixes in the schedule",
+		InputSchema: inputSchema[timeservice.CheckCronJobInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.CheckCronJobInput) (*mcp.CallToolResult, timeservice.CheckCronJobResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.CheckCronJob(input)
+		if err != nil {
+			recordError(metrics, "check_cronjob_schedule", "check_cronjob_schedule", startTime, logger, err)
+			return nil, timeservice.CheckCronJobResult{}, err
+		}
+
+		recordSuccess(metrics, "check_cronjob_schedule", "check_cronjob_schedule", startTime)
+
+		text := result.Summary
+		for _, warning := range result.Warnings {
+			text += "\n" + warning
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
+
+// formatCrontabAudit summarizes an audit, one line per entry
+func formatCrontabAudit(result timeservice.AuditCrontabResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d entries, %d issues", len(result.Entries), result.Issues)
+	for _, entry := range result.Entries {
+		fmt.Fprintf(&b, "\nline %d (%s): ", entry.Line, entry.Schedule)
+		switch {
+		case entry.Error != "":
+			b.WriteString("error: " + entry.Error)
+		case entry.NeverFires:
+			b.WriteString("never fires")
+		case len(entry.NextRuns) > 0:
+			b.WriteString("next " + entry.NextRuns[0])
+		default:
+			b.WriteString("no scheduled runs")
+		}
+		if n := len(entry.DSTSkipped); n > 0 {
+			fmt.Fprintf(&b, ", %d skipped by DST", n)
+		}
+		if n := len(entry.DSTRepeated); n > 0 {
+			fmt.Fprintf(&b, ", %d repeated by DST", n)
+		}
+	}
+	for _, overlap := range result.Overlaps {
+		fmt.Fprintf(&b, "\nlines %d and %d share %d runs, first at %s", overlap.Lines[0], overlap.Lines[1], overlap.SharedRuns, overlap.FirstShared)
+	}
+	for _, warning := range result.Warnings {
+		b.WriteString("\n" + warning)
+	}
+	return b.String()
+}
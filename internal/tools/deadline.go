@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// checkDeadlineTool serves the check_deadline tool
+func checkDeadlineTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "check_deadline",
+		Description: "Check a deadline with an optional grace period against the current (or a virtual) time: " +
+			"status upcoming/within_grace/overdue, time delta and the next escalation boundary",
+		InputSchema: inputSchema[timeservice.CheckDeadlineInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.CheckDeadlineInput) (*mcp.CallToolResult, timeservice.CheckDeadlineResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.CheckDeadline(input)
+		if err != nil {
+			recordError(metrics, "check_deadline", "check_deadline", startTime, logger, err)
+			return nil, timeservice.CheckDeadlineResult{}, err
+		}
+
+		recordSuccess(metrics, "check_deadline", "check_deadline", startTime)
+
+		text := fmt.Sprintf("Status: %s\nDeadline: %s\n%s", result.Status, result.Deadline, result.Summary)
+		if result.NextEscalation != nil {
+			text += fmt.Sprintf("\nNext escalation: %s at %s", result.NextEscalation.Status, result.NextEscalation.At)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
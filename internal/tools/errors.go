@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"errors"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/timeerrors"
+)
+
+// errorClasses maps timeerrors kinds to metrics labels. Errors can carry
+// several kinds when one wraps another, so the most specific kind is listed
+// first: an out-of-range epoch inside "invalid at" is reported as out_of_range.
+var errorClasses = []struct {
+	kind      error
+	category  string
+	errorType string
+}{
+	{timeerrors.ErrInvalidTimezone, metrics.ErrorCategoryValidation, metrics.ErrorTypeInvalidTimezone},
+	{timeerrors.ErrInvalidFormat, metrics.ErrorCategoryValidation, metrics.ErrorTypeInvalidFormat},
+	{timeerrors.ErrOutOfRange, metrics.ErrorCategoryTime, metrics.ErrorTypeOutOfRange},
+	{timeerrors.ErrParseFailure, metrics.ErrorCategoryTime, metrics.ErrorTypeParseFailure},
+	{timeerrors.ErrInvalidArgument, metrics.ErrorCategoryValidation, metrics.ErrorTypeInvalidRequest},
+}
+
+// classifyError returns the metrics category and error type for err
+func classifyError(err error) (category, errorType string) {
+	for _, class := range errorClasses {
+		if errors.Is(err, class.kind) {
+			return class.category, class.errorType
+		}
+	}
+	return metrics.ErrorCategoryInternal, metrics.ErrorTypeUnknown
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/i18n"
+	"github.com/hspedro/mcp-server-time/internal/logger"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+func TestClassifyError(t *testing.T) {
+	service := timeservice.NewTimeService("UTC", "RFC3339", []string{"RFC3339", "UnixNano"}, logger.Slog(zaptest.NewLogger(t)))
+
+	tests := []struct {
+		name      string
+		call      func() error
+		category  string
+		errorType string
+	}{
+		{
+			name: "invalid timezone",
+			call: func() error {
+				_, err := service.GetCurrentTime(timeservice.GetTimeInput{Timezone: "Mars/Olympus"})
+				return err
+			},
+			category:  metrics.ErrorCategoryValidation,
+			errorType: metrics.ErrorTypeInvalidTimezone,
+		},
+		{
+			name: "unsupported format",
+			call: func() error {
+				_, err := service.FormatTime(timeservice.FormatTimeInput{Timestamp: timeservice.EpochTimestamp(0, timeservice.EpochSeconds), Format: "NotAFormat"})
+				return err
+			},
+			category:  metrics.ErrorCategoryValidation,
+			errorType: metrics.ErrorTypeInvalidFormat,
+		},
+		{
+			name: "parse failure",
+			call: func() error {
+				_, err := service.ParseTime(timeservice.ParseTimeInput{TimeString: "not a time", Format: "RFC3339"})
+				return err
+			},
+			category:  metrics.ErrorCategoryTime,
+			errorType: metrics.ErrorTypeParseFailure,
+		},
+		{
+			name: "out of range wins over parse failure",
+			call: func() error {
+				_, err := service.ParseTime(timeservice.ParseTimeInput{TimeString: "99999999999999999999", Format: "UnixNano"})
+				return err
+			},
+			category:  metrics.ErrorCategoryTime,
+			errorType: metrics.ErrorTypeOutOfRange,
+		},
+		{
+			name: "invalid argument",
+			call: func() error {
+				_, err := service.ParseTime(timeservice.ParseTimeInput{TimeString: "2023-12-25T15:30:45Z", Calendar: "mayan"})
+				return err
+			},
+			category:  metrics.ErrorCategoryValidation,
+			errorType: metrics.ErrorTypeInvalidRequest,
+		},
+		{
+			name:      "untagged error",
+			call:      func() error { return errors.New("boom") },
+			category:  metrics.ErrorCategoryInternal,
+			errorType: metrics.ErrorTypeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			assert.Error(t, err)
+
+			category, errorType := classifyError(err)
+			assert.Equal(t, tt.category, category)
+			assert.Equal(t, tt.errorType, errorType)
+		})
+	}
+}
+
+func TestErrorResult(t *testing.T) {
+	service := timeservice.NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger.Slog(zaptest.NewLogger(t)))
+	_, err := service.GetCurrentTime(timeservice.GetTimeInput{Timezone: "Mars/Olympus"})
+	require.Error(t, err)
+
+	res := errorResult(context.Background(), err)
+	assert.True(t, res.IsError)
+	assert.Equal(t, err.Error(), res.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, mcp.Meta{metaErrorCode: metrics.ErrorTypeInvalidTimezone}, res.Meta)
+
+	// The message follows the locale, the code does not
+	res = errorResult(i18n.WithLocale(context.Background(), config.LocalePortugueseBR), err)
+	assert.Contains(t, res.Content[0].(*mcp.TextContent).Text, "fuso horário inválido Mars/Olympus")
+	assert.Equal(t, mcp.Meta{metaErrorCode: metrics.ErrorTypeInvalidTimezone}, res.Meta)
+}
@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// checkExpiryTool serves the check_expiry tool
+func checkExpiryTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "check_expiry",
+		Description: "Evaluate a certificate notAfter or token exp timestamp: time remaining, whether it has expired " +
+			"and a human summary in a target timezone. Alternatively pass a JWT to read its exp claim; the signature is NOT verified",
+		InputSchema: inputSchema[timeservice.ExpiryInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ExpiryInput) (*mcp.CallToolResult, timeservice.ExpiryResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.EvaluateExpiry(input)
+		if err != nil {
+			recordError(metrics, "check_expiry", "check_expiry", startTime, logger, err)
+			return nil, timeservice.ExpiryResult{}, err
+		}
+
+		recordSuccess(metrics, "check_expiry", "check_expiry", startTime)
+
+		text := fmt.Sprintf("Expires at: %s\nExpired: %t\n%s", result.ExpiresAt, result.Expired, result.Summary)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/features"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// featureTool is a tool that only registers when its feature flag is on
+type featureTool struct {
+	name string
+	flag features.Flag
+	tool func(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider
+}
+
+// featureTools lists the tools behind feature flags. Natural-language
+// parsing, astronomy and scheduler tools register here so they can ship
+// dark and be enabled per deployment
+var featureTools = []featureTool{}
+
+// RegisterFeatureTools registers the tools whose feature flag is enabled.
+// Flags are checked once, here: a disabled tool is never listed or callable
+func RegisterFeatureTools(server *mcp.Server, flags *features.Flags, timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) {
+	seen := make(map[string]bool)
+	for _, tool := range featureTools {
+		if !flags.Enabled(tool.flag) {
+			logger.Debug("Tool disabled by feature flag", zap.String("tool", tool.name), zap.String("flag", string(tool.flag)))
+			continue
+		}
+		addProviders(server, seen, tool.tool(timeService, metrics, logger))
+		logger.Info("Tool enabled by feature flag", zap.String("tool", tool.name), zap.String("flag", string(tool.flag)))
+	}
+}
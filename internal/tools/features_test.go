@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/features"
+	"github.com/hspedro/mcp-server-time/internal/logger"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+type echoInput struct {
+	Text string `json:"text"`
+}
+
+// connect opens an in-memory client session to server
+func connect(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "tools-test", Version: "test"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestRegisterFeatureTools(t *testing.T) {
+	original := featureTools
+	t.Cleanup(func() { featureTools = original })
+	featureTools = []featureTool{{
+		name: "sunrise",
+		flag: features.Astronomy,
+		tool: func(_ timeservice.TimeService, _ *metrics.Metrics, _ *zap.Logger) ToolProvider {
+			return NewTypedTool(&mcp.Tool{Name: "sunrise"}, func(ctx context.Context, req *mcp.CallToolRequest, input echoInput) (*mcp.CallToolResult, any, error) {
+				return &mcp.CallToolResult{}, nil, nil
+			})
+		},
+	}}
+
+	tests := []struct {
+		name   string
+		config map[string]bool
+		want   bool
+	}{
+		{"flag off", map[string]bool{"astronomy": false}, false},
+		{"other flag on", map[string]bool{"scheduler": true}, false},
+		{"flag on", map[string]bool{"astronomy": true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prometheus.DefaultRegisterer = prometheus.NewRegistry()
+			zapLogger := zaptest.NewLogger(t)
+			flags, err := features.New(tt.config)
+			require.NoError(t, err)
+
+			timeService := timeservice.NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger.Slog(zapLogger))
+			server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+			collector := metrics.New()
+			RegisterTimeTools(server, timeService, collector, zapLogger)
+			RegisterFeatureTools(server, flags, timeService, collector, zapLogger)
+
+			listed, err := connect(t, server).ListTools(context.Background(), nil)
+			require.NoError(t, err)
+			names := make([]string, 0, len(listed.Tools))
+			for _, tool := range listed.Tools {
+				names = append(names, tool.Name)
+			}
+			assert.Contains(t, names, "get_time")
+			if tt.want {
+				assert.Contains(t, names, "sunrise")
+			} else {
+				assert.NotContains(t, names, "sunrise")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// detectGapsTool serves the detect_gaps tool
+func detectGapsTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "detect_gaps",
+		Description: "Audit a time series against an expected interval (Go duration or calendar days such as 1d): " +
+			"return the windows of missing points and the duplicated points, rendered in a timezone",
+		InputSchema: inputSchema[timeservice.DetectGapsInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.DetectGapsInput) (*mcp.CallToolResult, timeservice.DetectGapsResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.DetectGaps(input)
+		if err != nil {
+			recordError(metrics, "detect_gaps", "detect_gaps", startTime, logger, err)
+			return nil, timeservice.DetectGapsResult{}, err
+		}
+
+		recordSuccess(metrics, "detect_gaps", "detect_gaps", startTime)
+
+		text := fmt.Sprintf("Points: %d\nMissing: %d in %d gaps\nDuplicated: %d\nOut of order: %d",
+			result.Count, result.Missing, len(result.Gaps), len(result.Duplicates), result.OutOfOrder)
+		for _, gap := range result.Gaps {
+			text += fmt.Sprintf("\n- %d missing from %s to %s", gap.Missing, gap.FirstMissing, gap.LastMissing)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
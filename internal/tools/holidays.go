@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/holidays"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Tools reading the imported holiday feeds
+const (
+	holidaysToolName   = "get_holidays"
+	settlementToolName = "settlement_date"
+)
+
+// RegisterHolidayTools registers the tools reading the imported holiday feeds
+func RegisterHolidayTools(server *mcp.Server, importer *holidays.Importer, metrics *metrics.Metrics, logger *zap.Logger) {
+	addProviders(server, make(map[string]bool), holidaysTool(importer, metrics, logger), settlementTool(importer, metrics, logger))
+}
+
+// holidaysTool serves the get_holidays tool
+func holidaysTool(importer *holidays.Importer, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: holidaysToolName,
+		Description: "List public holidays between two dates from the imported holiday feeds (" + strings.Join(importer.Names(), ", ") + "), " +
+			"with when each feed was last synced",
+		InputSchema: inputSchema[holidays.Input](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input holidays.Input) (*mcp.CallToolResult, holidays.Result, error) {
+		startTime := time.Now()
+
+		result, err := importer.Holidays(input)
+		if err != nil {
+			recordError(metrics, holidaysToolName, "get_holidays", startTime, logger, err)
+			return nil, holidays.Result{}, err
+		}
+
+		recordSuccess(metrics, holidaysToolName, "get_holidays", startTime)
+
+		text := fmt.Sprintf("Holidays from %s to %s", result.Start, result.End)
+		if len(result.Holidays) == 0 {
+			text += "\nNo holidays"
+		}
+		for _, holiday := range result.Holidays {
+			text += fmt.Sprintf("\n%s: %s (%s)", holiday.Date, holiday.Name, holiday.Feed)
+		}
+		for _, feed := range result.Feeds {
+			if feed.Stale {
+				text += fmt.Sprintf("\nWarning: feed %s is stale", feed.Name)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
+
+// settlementTool serves the settlement_date tool
+func settlementTool(importer *holidays.Importer, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: settlementToolName,
+		Description: "Compute the settlement date of a trade, T+N business days after it (T+2 by default), skipping weekends and the holidays " +
+			"of the market calendars among the imported holiday feeds (" + strings.Join(importer.Names(), ", ") + "). " +
+			"For an FX pair, name both currencies' feeds; settlement_feeds are only checked on the settlement date, as USD is for spot",
+		InputSchema: inputSchema[holidays.SettlementInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input holidays.SettlementInput) (*mcp.CallToolResult, holidays.SettlementResult, error) {
+		startTime := time.Now()
+
+		result, err := importer.SettlementDate(input)
+		if err != nil {
+			recordError(metrics, settlementToolName, "settlement_date", startTime, logger, err)
+			return nil, holidays.SettlementResult{}, err
+		}
+
+		recordSuccess(metrics, settlementToolName, "settlement_date", startTime)
+
+		text := fmt.Sprintf("Trade %s settles T+%d on %s", result.Trade, result.Days, result.Settlement)
+		for _, day := range result.Skipped {
+			text += fmt.Sprintf("\nSkipped %s: %s", day.Date, day.Reason)
+		}
+		for _, note := range result.Notes {
+			text += "\nNote: " + note
+		}
+		for _, feed := range result.Feeds {
+			if feed.Stale {
+				text += fmt.Sprintf("\nWarning: feed %s is stale", feed.Name)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
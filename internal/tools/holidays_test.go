@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/holidays"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func TestHolidayTools(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261225\r\nSUMMARY:Christmas Day\r\nTRANSP:TRANSPARENT\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n")
+	}))
+	t.Cleanup(feed.Close)
+
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	importer, err := holidays.New(config.HolidaysConfig{
+		Interval: time.Hour,
+		Timeout:  time.Second,
+		Feeds:    []config.HolidayFeedConfig{{Name: "us", URL: feed.URL}},
+	}, "UTC", nil, nil, "test", m, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	importer.Sync(context.Background())
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	RegisterHolidayTools(server, importer, m, zaptest.NewLogger(t))
+	session := connect(t, server)
+	ctx := context.Background()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "get_holidays", Arguments: map[string]any{
+		"start": "2026-12-01",
+		"end":   "2026-12-31",
+	}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+	assert.Equal(t, "Holidays from 2026-12-01 to 2026-12-31\n2026-12-25: Christmas Day (us)", res.Content[0].(*mcp.TextContent).Text)
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "get_holidays", Arguments: map[string]any{
+		"feeds": []string{"fr"},
+	}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "settlement_date", Arguments: map[string]any{
+		"trade": "2026-12-23",
+		"days":  1,
+	}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+	assert.Equal(t, "Trade 2026-12-23 settles T+1 on 2026-12-24", res.Content[0].(*mcp.TextContent).Text)
+
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "settlement_date", Arguments: map[string]any{
+		"trade": "2026-12-24",
+	}})
+	require.NoError(t, err)
+	require.False(t, res.IsError, res.Content)
+	assert.Equal(t, "Trade 2026-12-24 settles T+2 on 2026-12-29\nSkipped 2026-12-25: Christmas Day (us)\n"+
+		"Skipped 2026-12-26: weekend\nSkipped 2026-12-27: weekend", res.Content[0].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// httpDateTool serves the http_date tool
+func httpDateTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "http_date",
+		Description: "Render the current time, or a given instant, as an HTTP Date header value (IMF-fixdate, " +
+			"e.g. Mon, 25 Dec 2023 15:30:45 GMT). Pass value to normalize an HTTP-date in an obsolete form",
+		InputSchema: inputSchema[timeservice.HTTPDateInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.HTTPDateInput) (*mcp.CallToolResult, timeservice.HTTPDateResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.HTTPDate(input)
+		if err != nil {
+			recordError(metrics, "http_date", "http_date", startTime, logger, err)
+			return nil, timeservice.HTTPDateResult{}, err
+		}
+
+		recordSuccess(metrics, "http_date", "http_date", startTime)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "Date: " + result.Date},
+			},
+		}, result, nil
+	})
+}
+
+// retryAfterTool serves the retry_after tool
+func retryAfterTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "retry_after",
+		Description: "Interpret a Retry-After header (delay in seconds or an HTTP-date) as the absolute time a request " +
+			"may be retried and the wait left. Pass the response's Date header to measure an HTTP-date on the server's clock",
+		InputSchema: inputSchema[timeservice.RetryAfterInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.RetryAfterInput) (*mcp.CallToolResult, timeservice.RetryAfterResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.RetryAfter(input)
+		if err != nil {
+			recordError(metrics, "retry_after", "retry_after", startTime, logger, err)
+			return nil, timeservice.RetryAfterResult{}, err
+		}
+
+		recordSuccess(metrics, "retry_after", "retry_after", startTime)
+
+		text := fmt.Sprintf("Retry at: %s\nWait: %s", result.RetryAt, result.Wait)
+		if result.Elapsed {
+			text = fmt.Sprintf("Retry at: %s\nThe retry time has passed; retry now", result.RetryAt)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// createICSTool serves the create_ics tool
+func createICSTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "create_ics",
+		Description: "Create an iCalendar (.ics) event with a summary, start/end in a timezone (TZID) and an optional " +
+			"recurrence rule, returned as text and base64 to attach to invites. Attendees make it an invitation",
+		InputSchema: inputSchema[timeservice.CreateICSInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.CreateICSInput) (*mcp.CallToolResult, timeservice.CreateICSResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.CreateICS(input)
+		if err != nil {
+			recordError(metrics, "create_ics", "create_ics", startTime, logger, err)
+			return nil, timeservice.CreateICSResult{}, err
+		}
+
+		recordSuccess(metrics, "create_ics", "create_ics", startTime)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: result.ICS},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// decodeIDTimestampTool serves the decode_id_timestamp tool
+func decodeIDTimestampTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "decode_id_timestamp",
+		Description: "Extract the creation time embedded in a ULID, UUIDv7, MongoDB ObjectId or KSUID; " +
+			"the kind is detected from the ID when not given",
+		InputSchema: inputSchema[timeservice.DecodeIDTimestampInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.DecodeIDTimestampInput) (*mcp.CallToolResult, timeservice.DecodeIDTimestampResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.DecodeIDTimestamp(input)
+		if err != nil {
+			recordError(metrics, "decode_id_timestamp", "decode_id_timestamp", startTime, logger, err)
+			return nil, timeservice.DecodeIDTimestampResult{}, err
+		}
+
+		recordSuccess(metrics, "decode_id_timestamp", "decode_id_timestamp", startTime)
+
+		text := fmt.Sprintf("Created: %s (%s, %s precision)", result.Timestamp, result.Kind, result.Precision)
+		for _, warning := range result.Warnings {
+			text += "\nWarning: " + warning
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// explainLayoutTool serves the explain_layout tool
+func explainLayoutTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "explain_layout",
+		Description: "Infer the Go layout and strftime pattern of a sample time such as 25/12/2023 15:30, " +
+			"or explain what each directive of a Go layout or strftime pattern means. " +
+			"Use the inferred layout as the format of parse_time and format_time",
+		InputSchema: inputSchema[timeservice.ExplainLayoutInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ExplainLayoutInput) (*mcp.CallToolResult, timeservice.ExplainLayoutResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.ExplainLayout(input)
+		if err != nil {
+			recordError(metrics, "explain_layout", "explain_layout", startTime, logger, err)
+			return nil, timeservice.ExplainLayoutResult{}, err
+		}
+
+		recordSuccess(metrics, "explain_layout", "explain_layout", startTime)
+
+		text := fmt.Sprintf("Layout: %s\nStrftime: %s\nExample: %s", result.Layout, result.Strftime, result.Example)
+		if result.Parsed != "" {
+			text += "\nSample parses as: " + result.Parsed
+		}
+		for _, token := range result.Tokens {
+			if token.Kind == timeservice.LayoutTokenDirective {
+				text += fmt.Sprintf("\n- %s: %s", token.Text, token.Meaning)
+			}
+		}
+		for _, alternative := range result.Alternatives {
+			text += fmt.Sprintf("\nAlternative: %s parses as %s", alternative.Layout, alternative.Parsed)
+		}
+		for _, warning := range result.Warnings {
+			text += "\nWarning: " + warning
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// maintenanceWindowTool serves the parse_maintenance_window tool
+func maintenanceWindowTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "parse_maintenance_window",
+		Description: "Resolve a cloud provider maintenance window (AWS ddd:hh24:mi-ddd:hh24:mi in UTC, daily hh24:mi-hh24:mi, " +
+			"or a weekday and time range such as SUN 06:00-07:00 UTC) into its next occurrences in a timezone, " +
+			"and whether it is active now",
+		InputSchema: inputSchema[timeservice.MaintenanceWindowInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.MaintenanceWindowInput) (*mcp.CallToolResult, timeservice.MaintenanceWindowResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.ParseMaintenanceWindow(input)
+		if err != nil {
+			recordError(metrics, "parse_maintenance_window", "parse_maintenance_window", startTime, logger, err)
+			return nil, timeservice.MaintenanceWindowResult{}, err
+		}
+
+		recordSuccess(metrics, "parse_maintenance_window", "parse_maintenance_window", startTime)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: result.Summary},
+			},
+		}, result, nil
+	})
+}
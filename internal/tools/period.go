@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// alignPeriodTool serves the align_period tool
+func alignPeriodTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "align_period",
+		Description: "Align an instant (default now) to the containing calendar period (day, week, month, quarter, " +
+			"half or year) in a timezone, returning the period start/end, a sortable label such as 2025-Q3 and a localized display label",
+		InputSchema: inputSchema[timeservice.AlignPeriodInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.AlignPeriodInput) (*mcp.CallToolResult, timeservice.AlignPeriodResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.AlignPeriod(input)
+		if err != nil {
+			recordError(metrics, "align_period", "align_period", startTime, logger, err)
+			return nil, timeservice.AlignPeriodResult{}, err
+		}
+
+		recordSuccess(metrics, "align_period", "align_period", startTime)
+
+		text := fmt.Sprintf("Period: %s (%s)\nStart: %s\nEnd: %s",
+			result.Label, result.DisplayLabel, result.Start, result.End)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// prorateTool serves the prorate tool
+func prorateTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "prorate",
+		Description: "Split a billing period and its amount at a plan change or cancellation into used and remaining " +
+			"fractions and amounts, counting days with actual/actual (default), 30/360 or exact elapsed seconds",
+		InputSchema: inputSchema[timeservice.ProrateInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ProrateInput) (*mcp.CallToolResult, timeservice.ProrateResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.Prorate(input)
+		if err != nil {
+			recordError(metrics, "prorate", "prorate", startTime, logger, err)
+			return nil, timeservice.ProrateResult{}, err
+		}
+
+		recordSuccess(metrics, "prorate", "prorate", startTime)
+
+		text := fmt.Sprintf("Used %g of %g %s (%s): %.6f used, %.6f remaining\nAmount %g: %g used, %g remaining",
+			result.UsedUnits, result.PeriodUnits, result.Units, result.DayCount, result.UsedFraction, result.RemainingFraction,
+			result.Amount, result.UsedAmount, result.RemainingAmount)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
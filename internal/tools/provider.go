@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/i18n"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/rpcerror"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// ToolProvider is a tool the server exposes. Core tools implement it, and
+// downstream forks implement it to add their own tools, such as a company
+// calendar, then call Register from an init function
+type ToolProvider interface {
+	// Name is the tool name clients call
+	Name() string
+	// Schema describes the tool; it must carry an object InputSchema
+	Schema() *mcp.Tool
+	// Handle serves a call. A returned error is a protocol error; failures
+	// the client should see belong in a result with IsError set
+	Handle(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+var (
+	providersMu sync.Mutex
+	providers   = make(map[string]ToolProvider)
+)
+
+// Register adds an extension tool to the ones RegisterTimeTools serves.
+// It panics when a tool with the same name is already registered, as two
+// packages claiming a name is a build mistake
+func Register(provider ToolProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if _, ok := providers[provider.Name()]; ok {
+		panic(fmt.Sprintf("tools: provider %q registered twice", provider.Name()))
+	}
+	providers[provider.Name()] = provider
+}
+
+// Registered returns the extension tools added with Register, by name
+func Registered() []ToolProvider {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	list := make([]ToolProvider, 0, len(providers))
+	for _, provider := range providers {
+		list = append(list, provider)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// ReservedNames returns the names of the tools the server serves itself:
+// core tools, registered extension tools, feature-flagged tools,
+// get_server_info, clear_session_state, and the calendar and holiday
+// tools. Tools loaded at runtime must not claim any of them
+func ReservedNames(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) map[string]bool {
+	names := map[string]bool{
+		serverInfoToolName:        true,
+		freeBusyToolName:          true,
+		nextFreeSlotToolName:      true,
+		holidaysToolName:          true,
+		settlementToolName:        true,
+		clearSessionStateToolName: true,
+	}
+	for _, provider := range CoreTools(timeService, metrics, logger) {
+		names[provider.Name()] = true
+	}
+	for _, provider := range Registered() {
+		names[provider.Name()] = true
+	}
+	for _, tool := range featureTools {
+		names[tool.name] = true
+	}
+	return names
+}
+
+// RegisterExternalTools adds tools loaded at runtime, such as those served
+// by extension programs. Their names must not clash with ReservedNames
+func RegisterExternalTools(server *mcp.Server, list []ToolProvider) {
+	addProviders(server, make(map[string]bool), list...)
+}
+
+// addProviders adds tools to the MCP server, as schema version 1 unless
+// wrapped with Versioned. A name used twice, such as an extension shadowing
+// a core tool, panics rather than silently replacing it
+func addProviders(server *mcp.Server, seen map[string]bool, list ...ToolProvider) {
+	for _, provider := range list {
+		if seen[provider.Name()] {
+			panic(fmt.Sprintf("tools: tool %q added twice", provider.Name()))
+		}
+		seen[provider.Name()] = true
+		if _, ok := provider.(*versionedTool); !ok {
+			// Without a deprecation, metrics and logger are never used
+			provider = Versioned(provider, 1, nil, nil, nil)
+		}
+		server.AddTool(provider.Schema(), provider.Handle)
+	}
+}
+
+// errInvalidParams is the JSON-RPC invalid params error the SDK answers
+// malformed arguments with; wrapping it keeps the code
+var errInvalidParams = rpcerror.New(rpcerror.CodeInvalidParams, "invalid params", nil)
+
+// typedTool adapts a handler with typed input and output to ToolProvider.
+// Arguments are validated against the input schema, with defaults applied,
+// before being decoded into In; Out becomes the structured content
+type typedTool[In, Out any] struct {
+	tool    *mcp.Tool
+	input   *jsonschema.Resolved
+	output  *jsonschema.Resolved
+	handler mcp.ToolHandlerFor[In, Out]
+}
+
+// NewTypedTool builds a ToolProvider from a typed handler, inferring the
+// input and output schemas from In and Out unless tool sets them. It panics
+// on types that have no JSON schema, as tools are built at startup
+func NewTypedTool[In, Out any](tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) ToolProvider {
+	t := *tool
+	typed := &typedTool[In, Out]{tool: &t, handler: handler}
+
+	var err error
+	if typed.input, err = resolveSchema[In](&t.InputSchema); err != nil {
+		panic(fmt.Sprintf("tool %q: input schema: %v", t.Name, err))
+	}
+	if t.OutputSchema != nil || reflect.TypeFor[Out]() != reflect.TypeFor[any]() {
+		if typed.output, err = resolveSchema[Out](&t.OutputSchema); err != nil {
+			panic(fmt.Sprintf("tool %q: output schema: %v", t.Name, err))
+		}
+	}
+	return typed
+}
+
+// resolveSchema infers the schema for T into field when unset, and resolves it
+func resolveSchema[T any](field *any) (*jsonschema.Resolved, error) {
+	var schema *jsonschema.Schema
+	switch s := (*field).(type) {
+	case *jsonschema.Schema:
+		schema = s
+	case nil:
+		if reflect.TypeFor[T]() == reflect.TypeFor[any]() {
+			schema = &jsonschema.Schema{Type: "object"}
+		} else {
+			var err error
+			if schema, err = jsonschema.For[T](&jsonschema.ForOptions{}); err != nil {
+				return nil, err
+			}
+		}
+		*field = schema
+	default:
+		raw, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, err
+		}
+	}
+	return schema.Resolve(&jsonschema.ResolveOptions{ValidateDefaults: true})
+}
+
+func (t *typedTool[In, Out]) Name() string {
+	return t.tool.Name
+}
+
+func (t *typedTool[In, Out]) Schema() *mcp.Tool {
+	return t.tool
+}
+
+func (t *typedTool[In, Out]) Handle(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	in, err := t.decode(req.Params.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("%w: validating \"arguments\": %v", errInvalidParams, err)
+	}
+
+	res, out, err := t.handler(ctx, req, in)
+	if err != nil {
+		return errorResult(ctx, err), nil
+	}
+	if res == nil {
+		res = &mcp.CallToolResult{}
+	}
+	if t.output == nil {
+		return res, nil
+	}
+
+	raw, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling output: %w", err)
+	}
+	var structured map[string]any
+	if err := json.Unmarshal(raw, &structured); err != nil {
+		return nil, fmt.Errorf("output is not an object: %w", err)
+	}
+	fields := len(structured)
+	if err := t.output.ApplyDefaults(&structured); err != nil {
+		return nil, fmt.Errorf("applying output defaults: %w", err)
+	}
+	if err := t.output.Validate(&structured); err != nil {
+		return nil, fmt.Errorf("validating tool output: %w", err)
+	}
+	// Results keep the struct's own encoding, with fields in declaration
+	// order, unless defaults filled in fields it left out
+	if len(structured) != fields {
+		if raw, err = json.Marshal(structured); err != nil {
+			return nil, fmt.Errorf("marshaling output: %w", err)
+		}
+	}
+	res.StructuredContent = json.RawMessage(raw)
+	if res.Content == nil {
+		res.Content = []mcp.Content{&mcp.TextContent{Text: string(raw)}}
+	}
+	return res, nil
+}
+
+// decode validates arguments against the input schema, applies its defaults
+// and decodes them into In
+func (t *typedTool[In, Out]) decode(arguments json.RawMessage) (In, error) {
+	var in In
+	args := make(map[string]any)
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return in, err
+		}
+	}
+	if err := t.input.ApplyDefaults(&args); err != nil {
+		return in, err
+	}
+	if err := t.input.Validate(&args); err != nil {
+		return in, err
+	}
+
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return in, err
+	}
+	err = json.Unmarshal(raw, &in)
+	return in, err
+}
+
+// errorResult reports a failed call to the client as a tool error, in the
+// session's locale. The error code in _meta stays the same in every locale
+func errorResult(ctx context.Context, err error) *mcp.CallToolResult {
+	_, code := classifyError(err)
+	return &mcp.CallToolResult{
+		Meta:    mcp.Meta{metaErrorCode: code},
+		Content: []mcp.Content{&mcp.TextContent{Text: i18n.Localize(err, i18n.FromContext(ctx))}},
+		IsError: true,
+	}
+}
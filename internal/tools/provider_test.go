@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/logger"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+type holidayInput struct {
+	Date   string `json:"date"`
+	Region string `json:"region,omitempty"`
+}
+
+type holidayOutput struct {
+	Date    string `json:"date"`
+	Holiday bool   `json:"holiday"`
+}
+
+// holidayTool is an extension tool as a downstream fork would write it
+func holidayTool() ToolProvider {
+	return NewTypedTool(&mcp.Tool{Name: "company_holiday", Description: "Check the company holiday calendar"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input holidayInput) (*mcp.CallToolResult, holidayOutput, error) {
+			if input.Region == "mars" {
+				return nil, holidayOutput{}, assert.AnError
+			}
+			return nil, holidayOutput{Date: input.Date, Holiday: input.Date == "2026-12-25"}, nil
+		})
+}
+
+// withProviders swaps the extension registry for the duration of a test
+func withProviders(t *testing.T) {
+	providersMu.Lock()
+	saved := providers
+	providers = make(map[string]ToolProvider)
+	providersMu.Unlock()
+	t.Cleanup(func() {
+		providersMu.Lock()
+		providers = saved
+		providersMu.Unlock()
+	})
+}
+
+func newToolServer(t *testing.T) *mcp.Server {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	zapLogger := zaptest.NewLogger(t)
+	timeService := timeservice.NewTimeService("UTC", "RFC3339", []string{"RFC3339"}, logger.Slog(zapLogger))
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	RegisterTimeTools(server, timeService, metrics.New(), zapLogger)
+	return server
+}
+
+func TestRegister(t *testing.T) {
+	withProviders(t)
+	Register(holidayTool())
+	Register(NewTypedTool(&mcp.Tool{Name: "baggage_cutoff"}, func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{}, nil, nil
+	}))
+
+	var names []string
+	for _, provider := range Registered() {
+		names = append(names, provider.Name())
+	}
+	assert.Equal(t, []string{"baggage_cutoff", "company_holiday"}, names)
+	assert.PanicsWithValue(t, `tools: provider "company_holiday" registered twice`, func() { Register(holidayTool()) })
+}
+
+func TestRegisterTimeTools_Extensions(t *testing.T) {
+	withProviders(t)
+	Register(holidayTool())
+	session := connect(t, newToolServer(t))
+	ctx := context.Background()
+
+	listed, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	var names []string
+	for _, tool := range listed.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.Contains(t, names, "get_time")
+	assert.Contains(t, names, "company_holiday")
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "company_holiday", Arguments: map[string]any{"date": "2026-12-25"}})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	assert.Equal(t, map[string]any{"date": "2026-12-25", "holiday": true}, res.StructuredContent)
+	assert.Equal(t, `{"date":"2026-12-25","holiday":true}`, res.Content[0].(*mcp.TextContent).Text)
+
+	// Handler errors are tool errors
+	res, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "company_holiday", Arguments: map[string]any{"date": "2026-12-25", "region": "mars"}})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+	assert.Equal(t, assert.AnError.Error(), res.Content[0].(*mcp.TextContent).Text)
+
+	// Arguments that don't match the schema are protocol errors, as with the SDK's typed tools
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "company_holiday", Arguments: map[string]any{"date": 20261225}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid params: validating "arguments"`)
+}
+
+func TestRegisterTimeTools_ShadowedCoreTool(t *testing.T) {
+	withProviders(t)
+	Register(NewTypedTool(&mcp.Tool{Name: "get_time"}, func(ctx context.Context, req *mcp.CallToolRequest, input any) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{}, nil, nil
+	}))
+	assert.PanicsWithValue(t, `tools: tool "get_time" added twice`, func() { newToolServer(t) })
+}
+
+func TestNewTypedTool_Schema(t *testing.T) {
+	tool := holidayTool().Schema()
+	assert.Equal(t, "company_holiday", tool.Name)
+	require.NotNil(t, tool.InputSchema)
+	require.NotNil(t, tool.OutputSchema)
+
+	// An explicit input schema is kept
+	explicit := inputSchema[timeservice.GetTimeInput]()
+	typed := NewTypedTool(&mcp.Tool{Name: "explicit", InputSchema: explicit}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.GetTimeInput) (*mcp.CallToolResult, any, error) {
+		return nil, nil, nil
+	})
+	assert.Same(t, explicit, typed.Schema().InputSchema)
+	assert.Nil(t, typed.Schema().OutputSchema)
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// resolveAbbreviationTool serves the resolve_abbreviation tool
+func resolveAbbreviationTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "resolve_abbreviation",
+		Description: "Rank the timezones an ambiguous abbreviation such as CST (US Central, China, Cuba) or IST may mean, " +
+			"using optional context hints: the country, rough coordinates and language of the text, and when it was written. " +
+			"Each candidate carries its score and the reasons for it",
+		InputSchema: inputSchema[timeservice.ResolveAbbreviationInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ResolveAbbreviationInput) (*mcp.CallToolResult, timeservice.ResolveAbbreviationResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.ResolveAbbreviation(input)
+		if err != nil {
+			recordError(metrics, "resolve_abbreviation", "resolve_abbreviation", startTime, logger, err)
+			return nil, timeservice.ResolveAbbreviationResult{}, err
+		}
+
+		recordSuccess(metrics, "resolve_abbreviation", "resolve_abbreviation", startTime)
+
+		text := fmt.Sprintf("%s most likely means %s", result.Abbreviation, result.Best)
+		if result.Ambiguous {
+			text = fmt.Sprintf("%s is ambiguous; best guess %s", result.Abbreviation, result.Best)
+		}
+		for _, candidate := range result.Candidates {
+			text += fmt.Sprintf("\n- %s (%s, %s): score %.2f", candidate.Timezone, candidate.Name, candidate.Offset, candidate.Score)
+			for _, reason := range candidate.Reasons {
+				text += "\n  - " + reason
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// customTypeSchemas overrides schema inference for types with custom JSON decoding
+var customTypeSchemas = map[reflect.Type]*jsonschema.Schema{
+	reflect.TypeFor[timeservice.Timestamp](): {
+		Types: []string{"string", "number", "object"},
+		Description: "Epoch seconds as a number or digit string, an RFC3339 string, " +
+			`or an object {"epoch": n, "unit": "s|ms|us|ns"} / {"rfc3339": "..."}`,
+	},
+}
+
+// inputSchema infers the input schema for T, applying customTypeSchemas.
+// The MCP SDK infers schemas without custom type mappings, so tools whose
+// input contains such types must set their schema explicitly.
+func inputSchema[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{TypeSchemas: customTypeSchemas})
+	if err != nil {
+		panic(fmt.Sprintf("input schema for %T: %v", *new(T), err))
+	}
+	return schema
+}
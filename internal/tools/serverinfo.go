@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/updates"
+)
+
+// ServerInfo identifies the running server and the build it came from
+type ServerInfo struct {
+	Service string `json:"service"`
+	// Environment is the MCP_ENV config overlay in use, if any
+	Environment string         `json:"environment,omitempty"`
+	Build       buildinfo.Info `json:"build"`
+	// TZData is the version of the tzdata zones load from
+	TZData string `json:"tzdata"`
+	// Updates is the last update check, omitted when checks are disabled
+	Updates *updates.Status `json:"updates,omitempty"`
+}
+
+// serverInfoToolName is the name of the get_server_info tool
+const serverInfoToolName = "get_server_info"
+
+// ServerInfoInput takes no arguments
+type ServerInfoInput struct{}
+
+// RegisterServerInfoTool registers the get_server_info tool. checker may be
+// nil when update checks are disabled
+func RegisterServerInfoTool(server *mcp.Server, info ServerInfo, checker *updates.Checker, metrics *metrics.Metrics, logger *zap.Logger) {
+	addProviders(server, make(map[string]bool), serverInfoTool(info, checker, metrics, logger))
+}
+
+// serverInfoTool serves the get_server_info tool
+func serverInfoTool(info ServerInfo, checker *updates.Checker, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name:        serverInfoToolName,
+		Description: "Get the server's version, git commit, build date and Go version, to check which build a deployment runs",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ServerInfoInput) (*mcp.CallToolResult, ServerInfo, error) {
+		startTime := time.Now()
+		info := info
+		info.Updates = checker.Status()
+		recordSuccess(metrics, serverInfoToolName, "get_server_info", startTime)
+
+		text := fmt.Sprintf("Service: %s\nVersion: %s\nCommit: %s\nBuilt: %s\nGo: %s\ntzdata: %s",
+			info.Service, info.Build.Version, info.Build.Commit, info.Build.BuildTime, info.Build.GoVersion, info.TZData)
+		if info.Build.Modified {
+			text += "\nBuilt from a modified tree"
+		}
+		if info.Environment != "" {
+			text += "\nEnvironment: " + info.Environment
+		}
+		if info.Updates != nil {
+			for _, component := range []struct {
+				name   string
+				status *updates.ComponentStatus
+			}{{"Server", info.Updates.Server}, {"tzdata", info.Updates.TZData}} {
+				if component.status != nil && component.status.UpdateAvailable {
+					text += fmt.Sprintf("\n%s update available: %s", component.name, component.status.Latest)
+				}
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, info, nil
+	})
+}
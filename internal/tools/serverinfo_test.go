@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/buildinfo"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func TestServerInfoTool(t *testing.T) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	RegisterServerInfoTool(server, ServerInfo{
+		Service:     "mcp-server-time",
+		Environment: "prod",
+		Build: buildinfo.Info{
+			Version:   "v1.4.0",
+			Commit:    "0123456789abcdef",
+			BuildTime: "2026-10-16T08:00:00Z",
+			GoVersion: "go1.23.4",
+		},
+		TZData: "2025b",
+	}, nil, metrics.New(), zaptest.NewLogger(t))
+
+	res, err := connect(t, server).CallTool(context.Background(), &mcp.CallToolParams{Name: "get_server_info"})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	assert.Equal(t, map[string]any{
+		"service":     "mcp-server-time",
+		"environment": "prod",
+		"build": map[string]any{
+			"version":    "v1.4.0",
+			"commit":     "0123456789abcdef",
+			"build_time": "2026-10-16T08:00:00Z",
+			"go_version": "go1.23.4",
+			"modified":   false,
+		},
+		"tzdata": "2025b",
+	}, res.StructuredContent)
+	assert.Equal(t, "Service: mcp-server-time\nVersion: v1.4.0\nCommit: 0123456789abcdef\nBuilt: 2026-10-16T08:00:00Z\nGo: go1.23.4\ntzdata: 2025b\nEnvironment: prod",
+		res.Content[0].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	"github.com/hspedro/mcp-server-time/internal/sessionstate"
+)
+
+// clearSessionStateToolName is the tool resetting a session's state
+const clearSessionStateToolName = "clear_session_state"
+
+// RegisterSessionStateTool registers the clear_session_state tool
+func RegisterSessionStateTool(server *mcp.Server, store *sessionstate.Store, metrics *metrics.Metrics, logger *zap.Logger) {
+	addProviders(server, make(map[string]bool), clearSessionStateTool(store, metrics, logger))
+}
+
+// clearSessionStateTool serves the clear_session_state tool
+func clearSessionStateTool(store *sessionstate.Store, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: clearSessionStateToolName,
+		Description: "Clear the state this session keeps on the server, such as the output format negotiated at initialize, " +
+			"so later calls use the server defaults. Pass keys to clear only those. Cleared state can be brought back with " +
+			"restore until restorable_until",
+		InputSchema: inputSchema[sessionstate.ClearInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input sessionstate.ClearInput) (*mcp.CallToolResult, sessionstate.ClearResult, error) {
+		startTime := time.Now()
+
+		result := store.ClearSession(req.Session, input)
+
+		recordSuccess(metrics, clearSessionStateToolName, "clear_session_state", startTime)
+		logger.Debug("Cleared session state",
+			zap.Strings("cleared", result.Cleared),
+			zap.Strings("restored", result.Restored))
+
+		text := "Cleared: " + listOrNone(result.Cleared)
+		if input.Restore {
+			text = "Restored: " + listOrNone(result.Restored)
+		}
+		if result.RestorableUntil != "" {
+			text += fmt.Sprintf("\nRestorable until %s", result.RestorableUntil)
+		}
+		text += "\nSession state: " + listOrNone(result.Keys)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
+
+// listOrNone joins keys for display
+func listOrNone(keys []string) string {
+	if len(keys) == 0 {
+		return "none"
+	}
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// shiftPremiumTool serves the evaluate_shift_premium tool
+func shiftPremiumTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "evaluate_shift_premium",
+		Description: "Split a worked interval into the shift premium windows it overlaps, such as nights, weekends or holidays, " +
+			"and regular time, with the minutes in each category counted as elapsed time across DST changes",
+		InputSchema: inputSchema[timeservice.EvaluateShiftPremiumInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.EvaluateShiftPremiumInput) (*mcp.CallToolResult, timeservice.EvaluateShiftPremiumResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.EvaluateShiftPremium(input)
+		if err != nil {
+			recordError(metrics, "evaluate_shift_premium", "evaluate_shift_premium", startTime, logger, err)
+			return nil, timeservice.EvaluateShiftPremiumResult{}, err
+		}
+
+		recordSuccess(metrics, "evaluate_shift_premium", "evaluate_shift_premium", startTime)
+
+		text := fmt.Sprintf("Worked %.2fh from %s to %s", float64(result.Minutes)/60, result.Start, result.End)
+		for _, category := range result.Categories {
+			text += fmt.Sprintf("\n%s: %.2fh", category.Name, float64(category.Minutes)/60)
+		}
+		for _, note := range result.Notes {
+			text += "\nNote: " + note
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
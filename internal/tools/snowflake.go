@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// decodeSnowflakeTool serves the decode_snowflake tool
+func decodeSnowflakeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "decode_snowflake",
+		Description: "Extract the creation time embedded in a snowflake ID (Twitter/X, Discord or Instagram presets, " +
+			"or a custom epoch and bit layout), answering when a post, message or object was created",
+		InputSchema: inputSchema[timeservice.DecodeSnowflakeInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.DecodeSnowflakeInput) (*mcp.CallToolResult, timeservice.DecodeSnowflakeResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.DecodeSnowflake(input)
+		if err != nil {
+			recordError(metrics, "decode_snowflake", "decode_snowflake", startTime, logger, err)
+			return nil, timeservice.DecodeSnowflakeResult{}, err
+		}
+
+		recordSuccess(metrics, "decode_snowflake", "decode_snowflake", startTime)
+
+		text := fmt.Sprintf("Created: %s (%s layout)", result.Timestamp, result.Preset)
+		for _, field := range result.Fields {
+			text += fmt.Sprintf("\n%s: %d", field.Name, field.Value)
+		}
+		for _, warning := range result.Warnings {
+			text += "\nWarning: " + warning
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
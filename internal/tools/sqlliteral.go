@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// toSQLLiteralTool serves the to_sql_literal tool
+func toSQLLiteralTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "to_sql_literal",
+		Description: "Write an instant (default now) as a correctly typed and quoted SQL literal for postgres, mysql, " +
+			"sqlite or sqlserver, as a timestamp (wall time in a timezone), timestamptz (with its offset) or date",
+		InputSchema: inputSchema[timeservice.ToSQLLiteralInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ToSQLLiteralInput) (*mcp.CallToolResult, timeservice.ToSQLLiteralResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.ToSQLLiteral(input)
+		if err != nil {
+			recordError(metrics, "to_sql_literal", "to_sql_literal", startTime, logger, err)
+			return nil, timeservice.ToSQLLiteralResult{}, err
+		}
+
+		recordSuccess(metrics, "to_sql_literal", "to_sql_literal", startTime)
+
+		text := result.Literal
+		for _, note := range result.Notes {
+			text += "\nNote: " + note
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// parseTableTool serves the parse_table tool
+func parseTableTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "parse_table",
+		Description: "Parse one column of a CSV or TSV snippet as timestamps, with an optional format (named format, " +
+			"Go layout or strftime pattern), returning a normalized RFC3339 timestamp per row and per-row errors",
+		InputSchema: inputSchema[timeservice.ParseTableInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ParseTableInput) (*mcp.CallToolResult, timeservice.ParseTableResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.ParseTable(input)
+		if err != nil {
+			recordError(metrics, "parse_table", "parse_table", startTime, logger, err)
+			return nil, timeservice.ParseTableResult{}, err
+		}
+
+		recordSuccess(metrics, "parse_table", "parse_table", startTime)
+
+		text := fmt.Sprintf("Parsed %d rows, %d failed (timezone %s)", result.Parsed, result.Failed, result.Timezone)
+		for _, row := range result.Rows {
+			if row.Error != "" {
+				text += fmt.Sprintf("\nRow %d: %s", row.Row, row.Error)
+			} else {
+				text += fmt.Sprintf("\nRow %d: %s", row.Row, row.Timestamp)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// timesheetTool serves the timesheet tool
+func timesheetTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "timesheet",
+		Description: "Round punch-in and punch-out times to an increment (15 minutes with the 7/8-minute rule by default) and split " +
+			"the worked time into regular time and daily and weekly overtime, with when each threshold was crossed, in a timezone",
+		InputSchema: inputSchema[timeservice.TimesheetInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.TimesheetInput) (*mcp.CallToolResult, timeservice.TimesheetResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.Timesheet(input)
+		if err != nil {
+			recordError(metrics, "timesheet", "timesheet", startTime, logger, err)
+			return nil, timeservice.TimesheetResult{}, err
+		}
+
+		recordSuccess(metrics, "timesheet", "timesheet", startTime)
+
+		hours := func(minutes int) string { return fmt.Sprintf("%.2fh", float64(minutes)/60) }
+		text := fmt.Sprintf("Worked %s: %s regular, %s overtime (%d-minute increments, %s)",
+			hours(result.WorkedMinutes), hours(result.RegularMinutes), hours(result.OvertimeMinutes), result.Increment, result.Rounding)
+		for _, shift := range result.Shifts {
+			text += fmt.Sprintf("\n%s to %s rounded to %s to %s: %s", shift.In, shift.Out, shift.RoundedIn, shift.RoundedOut, hours(shift.WorkedMinutes))
+		}
+		for _, day := range result.Days {
+			if day.OvertimeFrom != "" {
+				text += fmt.Sprintf("\nDaily overtime on %s from %s: %s", day.Date, day.OvertimeFrom, hours(day.OvertimeMinutes))
+			}
+		}
+		for _, week := range result.Weeks {
+			if week.OvertimeFrom != "" {
+				text += fmt.Sprintf("\nWeekly overtime in the week of %s from %s: %s", week.Start, week.OvertimeFrom, hours(week.OvertimeMinutes))
+			}
+		}
+		for _, note := range result.Notes {
+			text += "\nNote: " + note
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -8,21 +8,65 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.uber.org/zap"
 
+	"github.com/hspedro/mcp-server-time/internal/i18n"
 	"github.com/hspedro/mcp-server-time/internal/metrics"
 	timeservice "github.com/hspedro/mcp-server-time/internal/time"
 )
 
-// RegisterTimeTools registers all time-related tools with the MCP server
+// RegisterTimeTools registers the core time tools, and the extension tools
+// added with Register, with the MCP server
 func RegisterTimeTools(server *mcp.Server, timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) {
-	registerGetTimeTool(server, timeService, metrics, logger)
-	registerFormatTimeTool(server, timeService, metrics, logger)
-	registerParseTimeTool(server, timeService, metrics, logger)
-	registerTimezoneInfoTool(server, timeService, metrics, logger)
+	seen := make(map[string]bool)
+	addProviders(server, seen, CoreTools(timeService, metrics, logger)...)
+	addProviders(server, seen, Registered()...)
 }
 
-// registerGetTimeTool registers the get_time tool
-func registerGetTimeTool(server *mcp.Server, timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) {
-	mcp.AddTool(server, &mcp.Tool{
+// CoreTools returns the built-in time tools
+func CoreTools(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) []ToolProvider {
+	return []ToolProvider{
+		getTimeTool(timeService, metrics, logger),
+		formatTimeTool(timeService, metrics, logger),
+		parseTimeTool(timeService, metrics, logger),
+		timezoneInfoTool(timeService, metrics, logger),
+		serverUptimeTool(timeService, metrics, logger),
+		compareClockTool(timeService, metrics, logger),
+		totpWindowTool(timeService, metrics, logger),
+		checkExpiryTool(timeService, metrics, logger),
+		analyzeTimestampsTool(timeService, metrics, logger),
+		bucketTimestampsTool(timeService, metrics, logger),
+		alignPeriodTool(timeService, metrics, logger),
+		checkDeadlineTool(timeService, metrics, logger),
+		anonymizeTimeTool(timeService, metrics, logger),
+		diffZoneRulesTool(timeService, metrics, logger),
+		zoneAbbreviationsTool(timeService, metrics, logger),
+		resolveAbbreviationTool(timeService, metrics, logger),
+		translateDateTool(timeService, metrics, logger),
+		wasDSTTool(timeService, metrics, logger),
+		createICSTool(timeService, metrics, logger),
+		validateWebhookTimestampTool(timeService, metrics, logger),
+		httpDateTool(timeService, metrics, logger),
+		retryAfterTool(timeService, metrics, logger),
+		auditCrontabTool(timeService, metrics, logger),
+		checkCronJobTool(timeService, metrics, logger),
+		maintenanceWindowTool(timeService, metrics, logger),
+		explainLayoutTool(timeService, metrics, logger),
+		verifyFormatTool(timeService, metrics, logger),
+		parseTableTool(timeService, metrics, logger),
+		detectGapsTool(timeService, metrics, logger),
+		toSQLLiteralTool(timeService, metrics, logger),
+		decodeSnowflakeTool(timeService, metrics, logger),
+		decodeIDTimestampTool(timeService, metrics, logger),
+		addTimeTool(timeService, metrics, logger),
+		nextBillingDateTool(timeService, metrics, logger),
+		prorateTool(timeService, metrics, logger),
+		timesheetTool(timeService, metrics, logger),
+		shiftPremiumTool(timeService, metrics, logger),
+	}
+}
+
+// getTimeTool serves the get_time tool
+func getTimeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
 		Name:        "get_time",
 		Description: "Get the current time in a specified timezone and format",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.GetTimeInput) (*mcp.CallToolResult, timeservice.GetTimeResult, error) {
@@ -36,25 +80,35 @@ func registerGetTimeTool(server *mcp.Server, timeService timeservice.TimeService
 
 		recordSuccess(metrics, "get_time", "get_current_time", startTime)
 
+		text := fmt.Sprintf("Current time: %s\nTimezone: %s\nFormat: %s",
+			timeservice.GroupDigits(result.FormattedTime, result.Format, input.DigitGrouping), result.Timezone, result.Format)
+		for _, format := range input.Formats {
+			text += fmt.Sprintf("\n- %s: %s", format, timeservice.GroupDigits(result.FormattedTimes[format], format, input.DigitGrouping))
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Current time: %s\nTimezone: %s\nFormat: %s",
-						result.FormattedTime, result.Timezone, result.Format),
-				},
+				&mcp.TextContent{Text: text},
 			},
 		}, result, nil
 	})
 }
 
-// registerFormatTimeTool registers the format_time tool
-func registerFormatTimeTool(server *mcp.Server, timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) {
-	mcp.AddTool(server, &mcp.Tool{
+// formatTimeTool serves the format_time tool
+func formatTimeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
 		Name:        "format_time",
 		Description: "Format a timestamp into a specified format and timezone",
+		InputSchema: inputSchema[timeservice.FormatTimeInput](),
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.FormatTimeInput) (*mcp.CallToolResult, timeservice.FormatTimeResult, error) {
 		startTime := time.Now()
 
+		// Dates are written in the session's locale unless the call names one
+		if input.Locale == "" {
+			input.Locale = i18n.FromContext(ctx)
+		} else if locale, ok := i18n.Match(input.Locale); ok {
+			input.Locale = locale
+		}
 		result, err := timeService.FormatTime(input)
 		if err != nil {
 			recordError(metrics, "format_time", "format_time", startTime, logger, err)
@@ -63,20 +117,26 @@ func registerFormatTimeTool(server *mcp.Server, timeService timeservice.TimeServ
 
 		recordSuccess(metrics, "format_time", "format_time", startTime)
 
+		text := fmt.Sprintf("Formatted time: %s\nOriginal: %s\nTimezone: %s\nFormat: %s",
+			timeservice.GroupDigits(result.FormattedTime, result.Format, input.DigitGrouping), input.Timestamp, result.Timezone, result.Format)
+		if result.WrittenDate != "" {
+			text += "\nWritten: " + result.WrittenDate
+		}
+		for _, warning := range result.Warnings {
+			text += "\nWarning: " + warning
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Formatted time: %s\nOriginal: %s\nTimezone: %s\nFormat: %s",
-						result.FormattedTime, input.Timestamp, result.Timezone, result.Format),
-				},
+				&mcp.TextContent{Text: text},
 			},
 		}, result, nil
 	})
 }
 
-// registerParseTimeTool registers the parse_time tool
-func registerParseTimeTool(server *mcp.Server, timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) {
-	mcp.AddTool(server, &mcp.Tool{
+// parseTimeTool serves the parse_time tool
+func parseTimeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
 		Name:        "parse_time",
 		Description: "Parse a time string and return timestamp information",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ParseTimeInput) (*mcp.CallToolResult, timeservice.ParseTimeResult, error) {
@@ -90,20 +150,28 @@ func registerParseTimeTool(server *mcp.Server, timeService timeservice.TimeServi
 
 		recordSuccess(metrics, "parse_time", "parse_time", startTime)
 
+		text := fmt.Sprintf("Parsed time:\n- Unix timestamp: %d\n- RFC3339: %s\n- Timezone: %s\n- Is DST: %t\n- Timezone handling: %s (explicit offset: %t)\n- Weekday: %s\n- Day of year: %d\n- ISO week: %d-W%02d",
+			result.UnixTimestamp, result.RFC3339, result.Timezone, result.IsDST, result.TimezoneHandling, result.ExplicitOffset,
+			result.Components.Weekday, result.Components.DayOfYear, result.Components.ISOYear, result.Components.ISOWeek)
+		if result.TwoDigitYear != nil {
+			text += fmt.Sprintf("\n- Two-digit year: %02d expanded to %d (pivot %d)",
+				result.TwoDigitYear.Input, result.TwoDigitYear.Century+result.TwoDigitYear.Input, result.TwoDigitYear.Pivot)
+		}
+		for _, warning := range result.Warnings {
+			text += "\nWarning: " + warning
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Parsed time:\n- Unix timestamp: %d\n- RFC3339: %s\n- Timezone: %s\n- Is DST: %t",
-						result.UnixTimestamp, result.RFC3339, result.Timezone, result.IsDST),
-				},
+				&mcp.TextContent{Text: text},
 			},
 		}, result, nil
 	})
 }
 
-// registerTimezoneInfoTool registers the timezone_info tool
-func registerTimezoneInfoTool(server *mcp.Server, timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) {
-	mcp.AddTool(server, &mcp.Tool{
+// timezoneInfoTool serves the timezone_info tool
+func timezoneInfoTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
 		Name:        "timezone_info",
 		Description: "Get detailed information about a timezone",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.TimezoneInfoInput) (*mcp.CallToolResult, timeservice.TimezoneInfo, error) {
@@ -125,11 +193,18 @@ func registerTimezoneInfoTool(server *mcp.Server, timeService timeservice.TimeSe
 				result.DST.Saving.String())
 		}
 
+		transitionInfo := fmt.Sprintf("No transition within %d days", result.LookaheadDays)
+		if result.NextTransition != nil {
+			transitionInfo = fmt.Sprintf("Next transition: %s (%s)",
+				result.NextTransition.NextTransition.Format(time.RFC3339),
+				result.NextTransition.TransitionType)
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				&mcp.TextContent{
-					Text: fmt.Sprintf("Timezone: %s\nAbbreviation: %s\nOffset: %s\nCurrent DST: %t\n%s",
-						result.Name, result.Abbreviation, result.Offset, result.IsDST, dstInfo),
+					Text: fmt.Sprintf("Timezone: %s\nAbbreviation: %s\nOffset: %s\nCurrent DST: %t\nObserves DST: %t\n%s\n%s",
+						result.Name, result.Abbreviation, result.Offset, result.IsDST, result.HasDST, dstInfo, transitionInfo),
 				},
 			},
 		}, result, nil
@@ -139,9 +214,12 @@ func registerTimezoneInfoTool(server *mcp.Server, timeService timeservice.TimeSe
 // recordError is a helper function to record error metrics and log
 func recordError(metrics *metrics.Metrics, toolName, operationName string, startTime time.Time, logger *zap.Logger, err error) {
 	duration := time.Since(startTime).Seconds()
+	category, errorType := classifyError(err)
 	metrics.RecordToolRequestDuration(toolName, "error", duration)
 	metrics.RecordTimeOperationDuration(operationName, "error", duration)
-	logger.Error(fmt.Sprintf("%s failed", toolName), zap.Error(err))
+	metrics.RecordError(category, errorType)
+	logger.Error(fmt.Sprintf("%s failed", toolName), zap.Error(err),
+		zap.String("category", category), zap.String("error_type", errorType))
 }
 
 // recordSuccess is a helper function to record success metrics
@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// totpWindowTool serves the totp_window tool
+func totpWindowTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "totp_window",
+		Description: "Get the RFC 6238 TOTP time-step counter for an instant (default now), the window boundaries " +
+			"and the seconds remaining in the window. Pure time computation, no secrets involved",
+		InputSchema: inputSchema[timeservice.TOTPWindowInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.TOTPWindowInput) (*mcp.CallToolResult, timeservice.TOTPWindowResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.TOTPWindow(input)
+		if err != nil {
+			recordError(metrics, "totp_window", "totp_window", startTime, logger, err)
+			return nil, timeservice.TOTPWindowResult{}, err
+		}
+
+		recordSuccess(metrics, "totp_window", "totp_window", startTime)
+
+		text := fmt.Sprintf("Counter: %d (step %ds)\nWindow: %s to %s\nSeconds remaining: %d",
+			result.Counter, result.StepSeconds, result.WindowStart, result.WindowEnd, result.SecondsRemaining)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
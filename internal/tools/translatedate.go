@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// translateDateTool serves the translate_date tool
+func translateDateTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "translate_date",
+		Description: "Rewrite numeric dates from one locale's convention to another's, e.g. 12/03/2025 (en-US) to 03.12.2025 (de) " +
+			"or 2025-12-03 (iso), flagging dates whose day and month could be swapped. Errors are reported per date",
+		InputSchema: inputSchema[timeservice.TranslateDateInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.TranslateDateInput) (*mcp.CallToolResult, timeservice.TranslateDateResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.TranslateDate(input)
+		if err != nil {
+			recordError(metrics, "translate_date", "translate_date", startTime, logger, err)
+			return nil, timeservice.TranslateDateResult{}, err
+		}
+
+		recordSuccess(metrics, "translate_date", "translate_date", startTime)
+
+		text := fmt.Sprintf("%s (%s) -> %s (%s): %d translated, %d ambiguous, %d failed", result.FromLocale, result.FromPattern,
+			result.ToLocale, result.ToPattern, result.Translated, result.Ambiguous, result.Failed)
+		for _, date := range result.Dates {
+			switch {
+			case date.Error != "":
+				text += fmt.Sprintf("\n- %s: error: %s", date.Input, date.Error)
+			case date.Ambiguous:
+				text += fmt.Sprintf("\n- %s -> %s (ambiguous: could be %s)", date.Input, date.Output, date.Alternative)
+			default:
+				text += fmt.Sprintf("\n- %s -> %s", date.Input, date.Output)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
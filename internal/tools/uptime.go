@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// serverUptimeTool serves the get_server_uptime tool
+func serverUptimeTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "get_server_uptime",
+		Description: "Get the server start time, uptime and a monotonic counter. Pass a previous monotonic_ns " +
+			"as since_monotonic_ns to measure elapsed time between calls independent of wall-clock changes",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.UptimeInput) (*mcp.CallToolResult, timeservice.UptimeResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.GetServerUptime(input)
+		if err != nil {
+			recordError(metrics, "get_server_uptime", "get_server_uptime", startTime, logger, err)
+			return nil, timeservice.UptimeResult{}, err
+		}
+
+		recordSuccess(metrics, "get_server_uptime", "get_server_uptime", startTime)
+
+		text := fmt.Sprintf("Server started: %s\nUptime: %s\nMonotonic counter: %d ns",
+			result.StartTime, result.Uptime, result.MonotonicNs)
+		if result.ElapsedNs != nil {
+			text += fmt.Sprintf("\nElapsed since previous reading: %s", result.Elapsed)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// verifyFormatTool serves the verify_format tool
+func verifyFormatTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "verify_format",
+		Description: "Check a format (named format, Go layout or strftime pattern) against a sample string: parse it, " +
+			"format it again and report whether information is lost, such as the timezone, sub-seconds or year digits. " +
+			"Use it to validate a layout before processing a whole file",
+		InputSchema: inputSchema[timeservice.VerifyFormatInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.VerifyFormatInput) (*mcp.CallToolResult, timeservice.VerifyFormatResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.VerifyFormat(input)
+		if err != nil {
+			recordError(metrics, "verify_format", "verify_format", startTime, logger, err)
+			return nil, timeservice.VerifyFormatResult{}, err
+		}
+
+		recordSuccess(metrics, "verify_format", "verify_format", startTime)
+
+		text := fmt.Sprintf("Parsed: %s\nReformatted: %s\nRound trip: %t\nLossless: %t",
+			result.Parsed, result.Reformatted, result.RoundTrip, result.Lossless)
+		for _, loss := range result.Losses {
+			text += fmt.Sprintf("\nLost %s: %s", loss.Kind, loss.Detail)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// Keys versioned tools use in tool and result _meta
+const (
+	metaSchemaVersion = "schema_version"
+	metaDeprecated    = "deprecated"
+)
+
+// metaErrorCode is the _meta key of an error result's code, the error type
+// of mcp_time_errors_total
+const metaErrorCode = "error_code"
+
+// Deprecation announces that a tool version is going away
+type Deprecation struct {
+	Replacement string `json:"replacement,omitempty"` // tool to call instead, e.g. parse_time_v2
+	RemovedIn   string `json:"removed_in,omitempty"`  // server release the tool is removed in
+	Reason      string `json:"reason,omitempty"`
+}
+
+// VersionedName is the name a tool's result schema version is served as.
+// Version 1 keeps the plain name; later ones get a _vN suffix, so a new
+// result shape is served next to the one it replaces
+func VersionedName(name string, version int) string {
+	if version <= 1 {
+		return name
+	}
+	return fmt.Sprintf("%s_v%d", name, version)
+}
+
+// versionedTool serves a tool as one schema version, optionally deprecated
+type versionedTool struct {
+	ToolProvider
+	tool        *mcp.Tool
+	version     int
+	deprecation *Deprecation
+	warned      atomic.Bool
+	metrics     *metrics.Metrics
+	logger      *zap.Logger
+}
+
+// Versioned serves a provider as a schema version, reported in the tool's
+// and each result's _meta. Tools not wrapped are version 1. A deprecation
+// is announced in the description and _meta, logged on the first call and
+// counted on every call, for one or more releases before the tool goes
+func Versioned(provider ToolProvider, version int, deprecation *Deprecation, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	t := *provider.Schema()
+	t.Name = VersionedName(provider.Name(), version)
+	t.Meta = maps.Clone(t.Meta)
+	if t.Meta == nil {
+		t.Meta = mcp.Meta{}
+	}
+	t.Meta[metaSchemaVersion] = version
+	if deprecation != nil {
+		t.Meta[metaDeprecated] = deprecation
+		t.Description = deprecationNotice(deprecation) + t.Description
+	}
+	return &versionedTool{
+		ToolProvider: provider,
+		tool:         &t,
+		version:      version,
+		deprecation:  deprecation,
+		metrics:      metrics,
+		logger:       logger,
+	}
+}
+
+func (v *versionedTool) Name() string {
+	return v.tool.Name
+}
+
+func (v *versionedTool) Schema() *mcp.Tool {
+	return v.tool
+}
+
+func (v *versionedTool) Handle(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if v.deprecation != nil {
+		v.metrics.RecordDeprecatedToolCall(v.tool.Name)
+		if !v.warned.Swap(true) {
+			v.logger.Warn("Deprecated tool called",
+				zap.String("tool", v.tool.Name),
+				zap.String("replacement", v.deprecation.Replacement),
+				zap.String("removed_in", v.deprecation.RemovedIn))
+		}
+	}
+
+	res, err := v.ToolProvider.Handle(ctx, req)
+	if err != nil || res == nil {
+		return res, err
+	}
+	if res.Meta == nil {
+		res.Meta = mcp.Meta{}
+	}
+	res.Meta[metaSchemaVersion] = v.version
+	if v.deprecation != nil {
+		res.Meta[metaDeprecated] = v.deprecation
+	}
+	return res, nil
+}
+
+// deprecationNotice leads a deprecated tool's description, where agents
+// choosing a tool read it
+func deprecationNotice(d *Deprecation) string {
+	notice := "DEPRECATED"
+	if d.Replacement != "" {
+		notice += ": use " + d.Replacement + " instead"
+	}
+	if d.RemovedIn != "" {
+		notice += fmt.Sprintf(" (removed in %s)", d.RemovedIn)
+	}
+	if d.Reason != "" {
+		notice += ". " + d.Reason
+	}
+	return notice + ". "
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func TestVersionedName(t *testing.T) {
+	assert.Equal(t, "parse_time", VersionedName("parse_time", 1))
+	assert.Equal(t, "parse_time_v2", VersionedName("parse_time", 2))
+}
+
+func TestVersioned(t *testing.T) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	m := metrics.New()
+	logger := zaptest.NewLogger(t)
+
+	v1 := Versioned(holidayTool(), 1, &Deprecation{Replacement: "company_holiday_v2", RemovedIn: "v3.0.0"}, m, logger)
+	wrapped := holidayTool()
+	v2 := Versioned(wrapped, 2, nil, m, logger)
+	assert.Equal(t, "company_holiday", v1.Name())
+	assert.Equal(t, "company_holiday_v2", v2.Name())
+	assert.Equal(t, "company_holiday", wrapped.Schema().Name, "the wrapped schema is left as it was")
+	assert.Nil(t, wrapped.Schema().Meta)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	addProviders(server, make(map[string]bool), v1, v2)
+	session := connect(t, server)
+	ctx := context.Background()
+
+	listed, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, listed.Tools, 2)
+	deprecated := listed.Tools[0]
+	assert.Equal(t, "company_holiday", deprecated.Name)
+	assert.Equal(t, "DEPRECATED: use company_holiday_v2 instead (removed in v3.0.0). Check the company holiday calendar", deprecated.Description)
+	assert.Equal(t, map[string]any{"replacement": "company_holiday_v2", "removed_in": "v3.0.0"}, deprecated.Meta["deprecated"])
+	assert.Equal(t, 2.0, listed.Tools[1].Meta["schema_version"])
+	assert.Equal(t, "Check the company holiday calendar", listed.Tools[1].Description)
+
+	for range 2 {
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "company_holiday", Arguments: map[string]any{"date": "2026-12-25"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, res.Meta["schema_version"])
+		assert.NotNil(t, res.Meta["deprecated"])
+	}
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.DeprecatedToolCallsTotal.WithLabelValues("company_holiday")))
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "company_holiday_v2", Arguments: map[string]any{"date": "2026-12-25"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, res.Meta["schema_version"])
+	assert.Nil(t, res.Meta["deprecated"])
+}
+
+func TestUnwrappedToolsAreVersionOne(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	addProviders(server, make(map[string]bool), holidayTool())
+	session := connect(t, server)
+	ctx := context.Background()
+
+	listed, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, listed.Tools, 1)
+	assert.Equal(t, "company_holiday", listed.Tools[0].Name)
+	assert.Equal(t, 1.0, listed.Tools[0].Meta["schema_version"])
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "company_holiday", Arguments: map[string]any{"date": "2026-12-25"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, res.Meta["schema_version"])
+	assert.Nil(t, res.Meta["deprecated"])
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// wasDSTTool serves the was_dst tool
+func wasDSTTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "was_dst",
+		Description: "Tell whether daylight saving time was in effect at an instant in a timezone, from tzdata's own DST flag, " +
+			"with the offset and abbreviation then and the exact transitions before and after it, to the second. " +
+			"For forensic reading of logs around DST changes",
+		InputSchema: inputSchema[timeservice.WasDSTInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.WasDSTInput) (*mcp.CallToolResult, timeservice.WasDSTResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.WasDST(input)
+		if err != nil {
+			recordError(metrics, "was_dst", "was_dst", startTime, logger, err)
+			return nil, timeservice.WasDSTResult{}, err
+		}
+
+		recordSuccess(metrics, "was_dst", "was_dst", startTime)
+
+		state := "not in effect"
+		if result.DST {
+			state = "in effect"
+		}
+		text := fmt.Sprintf("DST was %s in %s at %s (%s, %s)", state, result.Timezone, result.At, result.Abbreviation, result.Offset)
+		if result.Previous != nil {
+			text += fmt.Sprintf("\nprevious transition: %s, %s to %s", result.Previous.At, result.Previous.AbbreviationBefore, result.Previous.AbbreviationAfter)
+		}
+		if result.Next != nil {
+			text += fmt.Sprintf("\nnext transition: %s, %s to %s", result.Next.At, result.Next.AbbreviationBefore, result.Next.AbbreviationAfter)
+		}
+		for _, note := range result.Notes {
+			text += "\n" + note
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// validateWebhookTimestampTool serves the validate_webhook_timestamp tool
+func validateWebhookTimestampTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "validate_webhook_timestamp",
+		Description: "Check a webhook's signed timestamp header against a replay window, Stripe/Slack style: " +
+			"pass the header value (Unix seconds, or a whole Stripe-Signature header) and a tolerance (default 5m) " +
+			"to get valid/expired, the delivery's age and when it expires. Verify the signature separately",
+		InputSchema: inputSchema[timeservice.ValidateWebhookTimestampInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ValidateWebhookTimestampInput) (*mcp.CallToolResult, timeservice.ValidateWebhookTimestampResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.ValidateWebhookTimestamp(input)
+		if err != nil {
+			recordError(metrics, "validate_webhook_timestamp", "validate_webhook_timestamp", startTime, logger, err)
+			return nil, timeservice.ValidateWebhookTimestampResult{}, err
+		}
+
+		recordSuccess(metrics, "validate_webhook_timestamp", "validate_webhook_timestamp", startTime)
+
+		text := fmt.Sprintf("Webhook timestamp %s: %s\nSigned at: %s\nAge: %ds (tolerance %ds)",
+			result.Status, result.Summary, result.Timestamp, result.AgeSeconds, result.ToleranceSeconds)
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
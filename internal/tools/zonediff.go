@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+)
+
+// diffZoneRulesTool serves the diff_zone_rules tool
+func diffZoneRulesTool(timeService timeservice.TimeService, metrics *metrics.Metrics, logger *zap.Logger) ToolProvider {
+	return NewTypedTool(&mcp.Tool{
+		Name: "diff_zone_rules",
+		Description: "Compare a timezone's rules (offset, abbreviation, standard offset, DST windows) between two reference " +
+			"dates, or between two configured tzdata sources, e.g. to explain why historical timestamps shifted after a tzdata upgrade",
+		InputSchema: inputSchema[timeservice.ZoneRulesDiffInput](),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input timeservice.ZoneRulesDiffInput) (*mcp.CallToolResult, timeservice.ZoneRulesDiffResult, error) {
+		startTime := time.Now()
+
+		result, err := timeService.DiffZoneRules(input)
+		if err != nil {
+			recordError(metrics, "diff_zone_rules", "diff_zone_rules", startTime, logger, err)
+			return nil, timeservice.ZoneRulesDiffResult{}, err
+		}
+
+		recordSuccess(metrics, "diff_zone_rules", "diff_zone_rules", startTime)
+
+		text := fmt.Sprintf("%s: no rule differences between %s (%s) and %s (%s)", result.Timezone,
+			result.From.Reference, result.From.TZData, result.To.Reference, result.To.TZData)
+		if result.Changed {
+			text = fmt.Sprintf("%s: %d rule differences between %s (%s) and %s (%s):", result.Timezone, len(result.Differences),
+				result.From.Reference, result.From.TZData, result.To.Reference, result.To.TZData)
+			for _, diff := range result.Differences {
+				text += fmt.Sprintf("\n- %s: %s -> %s", diff.Field, diff.From, diff.To)
+			}
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: text},
+			},
+		}, result, nil
+	})
+}
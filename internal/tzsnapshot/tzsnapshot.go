@@ -0,0 +1,85 @@
+// Package tzsnapshot embeds tzdata releases, so zones can load from a
+// pinned snapshot instead of whatever tzdata the host has. Each snapshot is
+// a zoneinfo.zip as built by Go's lib/time/update.bash, stored as
+// snapshots/<version>.zip; adding a release is adding its archive.
+package tzsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Latest selects the newest embedded snapshot
+const Latest = "latest"
+
+//go:embed snapshots/*.zip
+var snapshots embed.FS
+
+// Snapshot is one embedded tzdata release
+type Snapshot struct {
+	Version string
+	archive *zip.Reader
+}
+
+// Versions lists the embedded snapshots, oldest first. tzdata versions
+// sort as strings: 2025b < 2025c < 2026a
+func Versions() []string {
+	entries, _ := snapshots.ReadDir("snapshots")
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		versions = append(versions, strings.TrimSuffix(entry.Name(), ".zip"))
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Select opens the snapshot of a tzdata version, or the newest one for
+// Latest or an empty version
+func Select(version string) (*Snapshot, error) {
+	versions := Versions()
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no tzdata snapshots embedded")
+	}
+	if version == "" || version == Latest {
+		version = versions[len(versions)-1]
+	}
+
+	data, err := snapshots.ReadFile(path.Join("snapshots", version+".zip"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown tzdata snapshot %s (embedded: %s)", version, strings.Join(versions, ", "))
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("tzdata snapshot %s: %w", version, err)
+	}
+	return &Snapshot{Version: version, archive: archive}, nil
+}
+
+// LoadLocation loads a zone from the snapshot alone, never from the host
+func (s *Snapshot) LoadLocation(name string) (*time.Location, error) {
+	// Like time.LoadLocation, UTC and Local aren't zone files
+	switch name {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		return nil, fmt.Errorf("the Local zone depends on the host, so tzdata snapshot %s has no Local", s.Version)
+	}
+
+	f, err := s.archive.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %s in tzdata snapshot %s", name, s.Version)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocationFromTZData(name, data)
+}
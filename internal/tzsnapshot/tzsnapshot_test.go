@@ -0,0 +1,46 @@
+package tzsnapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	versions := Versions()
+	require.NotEmpty(t, versions)
+
+	latest, err := Select(Latest)
+	require.NoError(t, err)
+	assert.Equal(t, versions[len(versions)-1], latest.Version)
+
+	pinned, err := Select(versions[0])
+	require.NoError(t, err)
+	assert.Equal(t, versions[0], pinned.Version)
+
+	_, err = Select("1999z")
+	assert.ErrorContains(t, err, "unknown tzdata snapshot 1999z (embedded: ")
+}
+
+func TestSnapshot_LoadLocation(t *testing.T) {
+	snapshot, err := Select(Latest)
+	require.NoError(t, err)
+
+	loc, err := snapshot.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", loc.String())
+	name, offset := time.Date(2024, time.July, 1, 12, 0, 0, 0, time.UTC).In(loc).Zone()
+	assert.Equal(t, "EDT", name)
+	assert.Equal(t, -4*3600, offset)
+
+	loc, err = snapshot.LoadLocation("UTC")
+	require.NoError(t, err)
+	assert.Same(t, time.UTC, loc)
+
+	for _, name := range []string{"Mars/Olympus", "Local", "../etc/passwd"} {
+		_, err := snapshot.LoadLocation(name)
+		assert.Error(t, err, name)
+	}
+}
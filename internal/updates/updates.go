@@ -0,0 +1,291 @@
+package updates
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+// unknownVersion is reported when a version can't be determined
+const unknownVersion = "unknown"
+
+// maxResponseBytes bounds what is read from an update source
+const maxResponseBytes = 64 << 10
+
+// zoneSources are the directories the time package loads zones from, after
+// $ZONEINFO, in the same order
+var zoneSources = []string{
+	"/usr/share/zoneinfo",
+	"/usr/share/lib/zoneinfo",
+	"/usr/lib/locale/TZ",
+	"/etc/zoneinfo",
+}
+
+var (
+	tzdataVersionPattern = regexp.MustCompile(`^(\d{4})([a-z]+)$`)
+	semverPattern        = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
+)
+
+// ComponentStatus is the last update check for one component
+type ComponentStatus struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Status is the outcome of the last update check
+type Status struct {
+	CheckedAt string           `json:"checked_at,omitempty"`
+	Server    *ComponentStatus `json:"server,omitempty"`
+	TZData    *ComponentStatus `json:"tzdata,omitempty"`
+}
+
+// Checker periodically compares the running server and tzdata versions
+// against upstream sources
+type Checker struct {
+	cfg           config.UpdatesConfig
+	serverVersion string
+	tzdataVersion string
+	client        *http.Client
+	metrics       *metrics.Metrics
+	logger        *zap.Logger
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New creates an update checker for the running server and tzdata versions
+func New(cfg config.UpdatesConfig, serverVersion, tzdataVersion string, metrics *metrics.Metrics, logger *zap.Logger) *Checker {
+	return &Checker{
+		cfg:           cfg,
+		serverVersion: serverVersion,
+		tzdataVersion: tzdataVersion,
+		client:        &http.Client{Timeout: cfg.Timeout},
+		metrics:       metrics,
+		logger:        logger,
+	}
+}
+
+// Run checks immediately and then every interval until ctx is done
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		c.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check queries the configured sources once and records the outcome
+func (c *Checker) Check(ctx context.Context) Status {
+	status := Status{CheckedAt: time.Now().UTC().Format(time.RFC3339)}
+	if c.cfg.ServerURL != "" {
+		status.Server = c.checkComponent(ctx, metrics.ComponentServer, c.cfg.ServerURL, c.serverVersion, newerServer)
+	}
+	if c.cfg.TZDataURL != "" {
+		status.TZData = c.checkComponent(ctx, metrics.ComponentTZData, c.cfg.TZDataURL, c.tzdataVersion, newerTZData)
+	}
+
+	c.mu.Lock()
+	c.status = status
+	c.mu.Unlock()
+	return status
+}
+
+// Status returns the outcome of the last check. A nil checker, as when
+// update checks are disabled, returns nil
+func (c *Checker) Status() *Status {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.status.CheckedAt == "" {
+		return nil
+	}
+	status := c.status
+	return &status
+}
+
+// checkComponent fetches the latest version of a component and compares it
+// with the current one
+func (c *Checker) checkComponent(ctx context.Context, component, url, current string, newer func(current, latest string) (bool, bool)) *ComponentStatus {
+	result := &ComponentStatus{Current: current}
+
+	latest, err := c.fetchVersion(ctx, url)
+	if err != nil {
+		result.Error = err.Error()
+		c.metrics.RecordUpdateCheck(component, metrics.StatusError, false)
+		c.logger.Warn("Update check failed", zap.String("component", component), zap.String("url", url), zap.Error(err))
+		return result
+	}
+	result.Latest = latest
+
+	available, comparable := newer(current, latest)
+	result.UpdateAvailable = available
+	c.metrics.RecordUpdateCheck(component, metrics.StatusSuccess, available)
+
+	switch {
+	case available:
+		c.logger.Warn("Update available", zap.String("component", component),
+			zap.String("current", current), zap.String("latest", latest))
+	case !comparable:
+		c.logger.Debug("Version not comparable", zap.String("component", component),
+			zap.String("current", current), zap.String("latest", latest))
+	}
+	return result
+}
+
+// fetchVersion reads a version from url: the tag_name of a GitHub release
+// JSON object, or the first line of a plain text response
+func (c *Checker) fetchVersion(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json, text/plain")
+	req.Header.Set("User-Agent", "mcp-server-time/"+c.serverVersion)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", err
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if json.Unmarshal(body, &release) == nil && release.TagName != "" {
+		return release.TagName, nil
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(body)), "\n")
+	if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "{") {
+		return "", fmt.Errorf("no version in response")
+	}
+	return line, nil
+}
+
+// newerServer reports whether latest is a newer release than current, and
+// whether the two could be compared at all (dev builds can't)
+func newerServer(current, latest string) (newer, comparable bool) {
+	a, okA := parseSemver(current)
+	b, okB := parseSemver(latest)
+	if !okA || !okB {
+		return false, false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return b[i] > a[i], true
+		}
+	}
+	return false, true
+}
+
+func parseSemver(v string) ([3]int, bool) {
+	var parts [3]int
+	match := semverPattern.FindStringSubmatch(v)
+	if match == nil {
+		return parts, false
+	}
+	for i := range parts {
+		parts[i], _ = strconv.Atoi(match[i+1])
+	}
+	return parts, true
+}
+
+// newerTZData reports whether latest is a newer tzdata release than
+// current. Releases are a year and letters: 2025b, then 2025c, ..., 2025z, 2025za
+func newerTZData(current, latest string) (newer, comparable bool) {
+	a := tzdataVersionPattern.FindStringSubmatch(current)
+	b := tzdataVersionPattern.FindStringSubmatch(latest)
+	if a == nil || b == nil {
+		return false, false
+	}
+	if a[1] != b[1] {
+		return b[1] > a[1], true
+	}
+	if len(a[2]) != len(b[2]) {
+		return len(b[2]) > len(a[2]), true
+	}
+	return b[2] > a[2], true
+}
+
+// LocalTZDataVersion returns the version of the tzdata zones are loaded
+// from: $ZONEINFO when it is a directory, else the first system zoneinfo
+// directory, read from its tzdata.zi or +VERSION file. Go's bundled
+// zoneinfo.zip carries no version, so it reports "unknown"
+func LocalTZDataVersion() string {
+	dirs := zoneSources
+	if zoneinfo := os.Getenv("ZONEINFO"); zoneinfo != "" {
+		if info, err := os.Stat(zoneinfo); err == nil && info.IsDir() {
+			dirs = []string{zoneinfo}
+		} else {
+			return unknownVersion
+		}
+	}
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if version := readZoneInfoVersion(dir); version != "" {
+			return version
+		}
+		// The first existing directory is the one zones load from
+		return unknownVersion
+	}
+	return unknownVersion
+}
+
+// readZoneInfoVersion reads the tzdata version recorded in a zoneinfo directory
+func readZoneInfoVersion(dir string) string {
+	if data, err := os.ReadFile(filepath.Join(dir, "+VERSION")); err == nil {
+		if version := strings.TrimSpace(string(data)); version != "" {
+			return version
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "tzdata.zi"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		if version, ok := strings.CutPrefix(scanner.Text(), "# version "); ok {
+			return strings.TrimSpace(version)
+		}
+	}
+	return ""
+}
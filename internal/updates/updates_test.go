@@ -0,0 +1,146 @@
+package updates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/config"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+)
+
+func TestNewerServer(t *testing.T) {
+	tests := []struct {
+		current, latest   string
+		newer, comparable bool
+	}{
+		{"v1.4.0", "v1.5.0", true, true},
+		{"v1.4.0", "v1.4.0", false, true},
+		{"1.4.2", "v1.4.10", true, true},
+		{"v2.0.0", "v1.9.9", false, true},
+		{"v1.4.0-3-gabc123-dirty", "v1.4.1", true, true},
+		{"dev", "v1.4.0", false, false},
+		{"v1.4.0", "nightly", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+" vs "+tt.latest, func(t *testing.T) {
+			newer, comparable := newerServer(tt.current, tt.latest)
+			assert.Equal(t, tt.newer, newer)
+			assert.Equal(t, tt.comparable, comparable)
+		})
+	}
+}
+
+func TestNewerTZData(t *testing.T) {
+	tests := []struct {
+		current, latest   string
+		newer, comparable bool
+	}{
+		{"2025b", "2026a", true, true},
+		{"2025b", "2025c", true, true},
+		{"2025b", "2025b", false, true},
+		{"2025z", "2025za", true, true},
+		{"2026a", "2025g", false, true},
+		{"unknown", "2026a", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+" vs "+tt.latest, func(t *testing.T) {
+			newer, comparable := newerTZData(tt.current, tt.latest)
+			assert.Equal(t, tt.newer, newer)
+			assert.Equal(t, tt.comparable, comparable)
+		})
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	collector := metrics.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tag_name": "v1.5.0", "name": "Release 1.5.0"}`))
+	})
+	mux.HandleFunc("/tzdb/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("2026c\n"))
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	})
+	upstream := httptest.NewServer(mux)
+	t.Cleanup(upstream.Close)
+
+	checker := New(config.UpdatesConfig{
+		Interval:  time.Hour,
+		Timeout:   time.Second,
+		ServerURL: upstream.URL + "/releases/latest",
+		TZDataURL: upstream.URL + "/tzdb/version",
+	}, "v1.4.0", "2025b", collector, zaptest.NewLogger(t))
+	assert.Nil(t, checker.Status(), "no status before the first check")
+
+	status := checker.Check(context.Background())
+	assert.Equal(t, &ComponentStatus{Current: "v1.4.0", Latest: "v1.5.0", UpdateAvailable: true}, status.Server)
+	assert.Equal(t, &ComponentStatus{Current: "2025b", Latest: "2026c", UpdateAvailable: true}, status.TZData)
+	assert.Equal(t, &status, checker.Status())
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.UpdateAvailable.WithLabelValues(metrics.ComponentServer)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.UpdateAvailable.WithLabelValues(metrics.ComponentTZData)))
+
+	// A failing source reports its error and keeps the other component
+	checker.cfg.ServerURL = upstream.URL + "/broken"
+	status = checker.Check(context.Background())
+	assert.Equal(t, "unexpected status 429 Too Many Requests", status.Server.Error)
+	assert.False(t, status.Server.UpdateAvailable)
+	assert.True(t, status.TZData.UpdateAvailable)
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.UpdateChecksTotal.WithLabelValues(metrics.ComponentServer, metrics.StatusError)))
+
+	// An empty URL skips the component
+	checker.cfg.ServerURL = ""
+	assert.Nil(t, checker.Check(context.Background()).Server)
+}
+
+func TestChecker_NilStatus(t *testing.T) {
+	var checker *Checker
+	assert.Nil(t, checker.Status())
+}
+
+func TestLocalTZDataVersion(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	t.Run("tzdata.zi", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "tzdata.zi", "# version 2025b\n# This zic input file is in the public domain.\n")
+		t.Setenv("ZONEINFO", dir)
+		assert.Equal(t, "2025b", LocalTZDataVersion())
+	})
+
+	t.Run("+VERSION", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "+VERSION", "2024a\n")
+		t.Setenv("ZONEINFO", dir)
+		assert.Equal(t, "2024a", LocalTZDataVersion())
+	})
+
+	t.Run("no version file", func(t *testing.T) {
+		t.Setenv("ZONEINFO", t.TempDir())
+		assert.Equal(t, "unknown", LocalTZDataVersion())
+	})
+
+	t.Run("zip archive", func(t *testing.T) {
+		t.Setenv("ZONEINFO", filepath.Join(t.TempDir(), "zoneinfo.zip"))
+		assert.Equal(t, "unknown", LocalTZDataVersion())
+	})
+}
@@ -0,0 +1,75 @@
+#!/usr/bin/env bash
+# Compares the benchmarks of HEAD with those of a base ref using benchstat,
+# and fails when any benchmark got significantly slower or allocates more
+# than THRESHOLD percent over the base. benchstat marks changes it can't
+# tell from noise with "~"; those never fail the build.
+#
+# Usage: scripts/bench-compare.sh [base-ref]
+#   BENCH_COUNT     runs of each benchmark, for benchstat's statistics (10)
+#   BENCH_THRESHOLD regression allowed, in percent (10)
+#   BENCH_PKGS      packages benchmarked
+#   BENCH_OUT       directory the raw results and comparison are written to
+set -euo pipefail
+
+base=${1:-origin/main}
+count=${BENCH_COUNT:-10}
+threshold=${BENCH_THRESHOLD:-10}
+pkgs=${BENCH_PKGS:-./internal/time/ ./internal/server/}
+out=${BENCH_OUT:-.bench}
+
+if ! command -v benchstat >/dev/null; then
+	echo "benchstat not found; install it with: make tools" >&2
+	exit 1
+fi
+
+root=$(git rev-parse --show-toplevel)
+mkdir -p "$out"
+out=$(cd "$out" && pwd)
+worktree=$(mktemp -d)
+trap 'git -C "$root" worktree remove --force "$worktree" >/dev/null 2>&1 || true; rm -rf "$worktree"' EXIT
+
+run() {
+	# Packages without benchmarks on the base are skipped, not failed
+	local existing=()
+	for pkg in $pkgs; do
+		[ -d "$pkg" ] && existing+=("$pkg")
+	done
+	[ ${#existing[@]} -gt 0 ] || return 0
+	go test -run '^$' -bench . -benchmem -count "$count" "${existing[@]}"
+}
+
+echo ">>> Benchmarking $base"
+git -C "$root" worktree add --detach --quiet "$worktree" "$base"
+(cd "$worktree" && run) >"$out/base.txt"
+
+echo ">>> Benchmarking HEAD"
+(cd "$root" && run) >"$out/head.txt"
+
+benchstat "$out/base.txt" "$out/head.txt" | tee "$out/compare.txt"
+benchstat -format csv "$out/base.txt" "$out/head.txt" >"$out/compare.csv"
+
+# Rows of the CSV are grouped by unit: a ",<unit>,CI,<unit>,CI,vs base,P"
+# header starts each group. A benchmark row's change is its signed
+# percentage, "+12.34%"; rows new on HEAD have none
+awk -F, -v threshold="$threshold" '
+	$1 == "" && $2 ~ /\/op$/ { unit = $2; next }
+	$1 == "" || $1 == "geomean" || NF < 3 { next }
+	unit == "sec/op" || unit == "B/op" || unit == "allocs/op" {
+		for (i = NF; i > 1; i--) {
+			if ($i ~ /^[+-][0-9.]+%$/) {
+				change = $i
+				sub(/%$/, "", change)
+				if (change + 0 > threshold) {
+					printf "REGRESSION %s %s: %s\n", $1, unit, $i
+					failed = 1
+				}
+				break
+			}
+		}
+	}
+	END { exit failed }
+' "$out/compare.csv" || {
+	echo ">>> Benchmarks regressed more than ${threshold}% against $base" >&2
+	exit 1
+}
+echo ">>> No regression over ${threshold}% against $base"
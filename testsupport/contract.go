@@ -0,0 +1,65 @@
+package testsupport
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// CallFunc calls a tool on the server under test
+type CallFunc func(ctx context.Context, tool string, arguments map[string]any) (*mcp.CallToolResult, error)
+
+// SessionCaller returns a CallFunc backed by an MCP client session
+func SessionCaller(session *mcp.ClientSession) CallFunc {
+	return func(ctx context.Context, tool string, arguments map[string]any) (*mcp.CallToolResult, error) {
+		return session.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: arguments})
+	}
+}
+
+// RunContract runs every fixture against the server as a subtest
+func RunContract(t *testing.T, call CallFunc) {
+	t.Helper()
+
+	for _, fixture := range Fixtures() {
+		t.Run(fixture.Name, func(t *testing.T) {
+			res, err := call(context.Background(), fixture.Tool, fixture.Arguments)
+			require.NoError(t, err)
+			require.NotNil(t, res)
+
+			if fixture.ExpectError {
+				assert.True(t, res.IsError, "expected a tool error")
+				return
+			}
+
+			require.False(t, res.IsError, "unexpected tool error: %v", res.Content)
+
+			got, err := normalize(res.StructuredContent)
+			require.NoError(t, err)
+			want, err := normalize(fixture.Expected)
+			require.NoError(t, err)
+
+			for field, value := range want {
+				assert.Equal(t, value, got[field], "field %s", field)
+			}
+		})
+	}
+}
+
+// normalize round-trips a value through JSON so numbers and nested values
+// compare equal regardless of their Go representation
+func normalize(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
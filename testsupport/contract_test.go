@@ -0,0 +1,89 @@
+package testsupport
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/hspedro/mcp-server-time/internal/envelope"
+	applogger "github.com/hspedro/mcp-server-time/internal/logger"
+	"github.com/hspedro/mcp-server-time/internal/metrics"
+	timeservice "github.com/hspedro/mcp-server-time/internal/time"
+	"github.com/hspedro/mcp-server-time/internal/tools"
+)
+
+// newContractSession starts the real tool set on an in-memory transport,
+// with any extra server middleware
+func newContractSession(t *testing.T, middleware ...mcp.Middleware) *mcp.ClientSession {
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	logger := zaptest.NewLogger(t)
+
+	timeService := timeservice.NewTimeService("UTC", "RFC3339",
+		[]string{"RFC3339", "RFC3339Nano", "Unix", "UnixMilli", "UnixMicro", "UnixNano", "Layout", "EpochDay", "Ordinal", "RataDie", "MySQLDateTime", "PostgresTimestamptz", "JulianDay", "GPSSeconds", "GPSWeek", "TLEEpoch"},
+		applogger.Slog(logger), timeservice.WithClock(timeservice.FixedClock{Time: FrozenTime}))
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-server-time", Version: "test"}, nil)
+	tools.RegisterTimeTools(server, timeService, metrics.New(), logger)
+	server.AddReceivingMiddleware(middleware...)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "contract-test", Version: "test"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+
+	return session
+}
+
+func TestContract(t *testing.T) {
+	RunContract(t, SessionCaller(newContractSession(t)))
+}
+
+// TestContract_LegacyFields checks that results carrying legacy field
+// names still meet the contract, and that a client sees both names, the
+// legacy one in its original shape
+func TestContract_LegacyFields(t *testing.T) {
+	session := newContractSession(t, envelope.NewLegacyFields(envelope.Renames, zaptest.NewLogger(t)).Middleware())
+	RunContract(t, SessionCaller(session))
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "timezone_info",
+		Arguments: map[string]any{"timezone": "America/New_York"},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+	structured, ok := res.StructuredContent.(map[string]any)
+	require.True(t, ok)
+	require.Contains(t, structured, "next_transition")
+	legacy := maps.Clone(structured["next_transition"].(map[string]any))
+	delete(legacy, "kind")
+	assert.Equal(t, legacy, structured["dst_transition"])
+}
+
+func TestFixtures_CoverEveryTool(t *testing.T) {
+	session := newContractSession(t)
+
+	listed, err := session.ListTools(context.Background(), nil)
+	require.NoError(t, err)
+
+	covered := make(map[string]bool)
+	for _, fixture := range Fixtures() {
+		covered[fixture.Tool] = true
+	}
+
+	for _, tool := range listed.Tools {
+		require.True(t, covered[tool.Name], "no fixture for tool %s", tool.Name)
+	}
+}
@@ -0,0 +1,1131 @@
+// Package testsupport provides canned tool inputs with their expected outputs
+// and a contract-test runner, so MCP client implementations can verify interop
+// with mcp-server-time without maintaining their own fixtures.
+//
+// Servers under test must have their clock frozen at FrozenTime (for example
+// with replay.frozen_time) and use the default time configuration.
+package testsupport
+
+import (
+	"time"
+)
+
+// FrozenTime is the instant the server under test must report as the current time
+var FrozenTime = time.Date(2023, 12, 25, 15, 30, 45, 0, time.UTC)
+
+// Fixture is a single tool call with its expected structured output
+type Fixture struct {
+	// Name uniquely identifies the fixture
+	Name string
+	// Tool is the MCP tool to call
+	Tool string
+	// Arguments are sent as the tool call arguments
+	Arguments map[string]any
+	// Expected holds the structured output fields that must match exactly.
+	// Fields not listed are not compared.
+	Expected map[string]any
+	// ExpectError reports whether the call must fail with a tool error
+	ExpectError bool
+}
+
+// Fixtures returns the canned fixtures for every tool
+func Fixtures() []Fixture {
+	return []Fixture{
+		// get_time
+		{
+			Name:      "get_time/utc_rfc3339",
+			Tool:      "get_time",
+			Arguments: map[string]any{"timezone": "UTC", "format": "RFC3339"},
+			Expected: map[string]any{
+				"formatted_time": "2023-12-25T15:30:45Z",
+				"timezone":       "UTC",
+				"format":         "RFC3339",
+				"unix_timestamp": 1703518245,
+			},
+		},
+		{
+			Name:      "get_time/new_york_unix",
+			Tool:      "get_time",
+			Arguments: map[string]any{"timezone": "America/New_York", "format": "Unix"},
+			Expected: map[string]any{
+				"formatted_time": "1703518245",
+				"timezone":       "America/New_York",
+				"format":         "Unix",
+			},
+		},
+		{
+			Name:      "get_time/multiple_formats",
+			Tool:      "get_time",
+			Arguments: map[string]any{"timezone": "UTC", "formats": []string{"Unix", "UnixMilli", "RFC3339Nano"}},
+			Expected: map[string]any{
+				"formatted_times": map[string]any{
+					"Unix":        "1703518245",
+					"UnixMilli":   "1703518245000",
+					"RFC3339Nano": "2023-12-25T15:30:45Z",
+				},
+			},
+		},
+		{
+			Name:      "get_time/milli_precision",
+			Tool:      "get_time",
+			Arguments: map[string]any{"timezone": "UTC", "format": "RFC3339Nano", "precision": "milli"},
+			Expected: map[string]any{
+				"formatted_time":    "2023-12-25T15:30:45Z",
+				"precision":         "milli",
+				"unix_timestamp_ms": 1703518245000,
+			},
+		},
+		{
+			Name:        "get_time/invalid_timezone",
+			Tool:        "get_time",
+			Arguments:   map[string]any{"timezone": "Invalid/Timezone"},
+			ExpectError: true,
+		},
+
+		// format_time
+		{
+			Name:      "format_time/rfc3339_string_to_unix",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": "2023-12-25T15:30:45Z", "format": "Unix"},
+			Expected: map[string]any{
+				"formatted_time": "1703518245",
+				"timezone":       "UTC",
+				"format":         "Unix",
+				"unix_timestamp": 1703518245,
+			},
+		},
+		{
+			Name:      "format_time/ordinal_date",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": "2023-12-25T15:30:45Z", "format": "Ordinal", "timezone": "Asia/Tokyo"},
+			Expected: map[string]any{
+				"formatted_time": "2023-360",
+				"format":         "Ordinal",
+			},
+		},
+		{
+			Name:      "format_time/gps_week",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": "2023-12-25T15:30:45Z", "format": "GPSWeek"},
+			Expected: map[string]any{
+				"formatted_time": "2294 142263",
+				"format":         "GPSWeek",
+			},
+		},
+		{
+			Name:      "format_time/epoch_number_to_tokyo",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": 1703518245, "format": "RFC3339", "timezone": "Asia/Tokyo"},
+			Expected: map[string]any{
+				"formatted_time": "2023-12-26T00:30:45+09:00",
+				"timezone":       "Asia/Tokyo",
+			},
+		},
+		{
+			Name:      "format_time/epoch_object_milliseconds",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": map[string]any{"epoch": 1703518245123, "unit": "ms"}, "format": "RFC3339Nano"},
+			Expected: map[string]any{
+				"formatted_time": "2023-12-25T15:30:45.123Z",
+				"timezone":       "UTC",
+			},
+		},
+		{
+			Name:      "format_time/negative_epoch_milliseconds",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": map[string]any{"epoch": -1, "unit": "ms"}, "format": "RFC3339Nano"},
+			Expected: map[string]any{
+				"formatted_time": "1969-12-31T23:59:59.999Z",
+				"unix_timestamp": -1,
+				"is_pre_epoch":   true,
+			},
+		},
+		{
+			Name:      "format_time/strftime_pattern",
+			Tool:      "format_time",
+			Arguments: map[string]any{"timestamp": "2023-12-25T15:30:45Z", "format": "%a %d %b %Y, %I:%M %p", "timezone": "Europe/Paris"},
+			Expected: map[string]any{
+				"formatted_time": "Mon 25 Dec 2023, 04:30 PM",
+				"timezone":       "Europe/Paris",
+			},
+		},
+		{
+			Name:        "format_time/written_out_date_as_layout",
+			Tool:        "format_time",
+			Arguments:   map[string]any{"timestamp": "2023-12-25T15:30:45Z", "format": "2023-12-25"},
+			ExpectError: true,
+		},
+		{
+			Name:        "format_time/unsupported_format",
+			Tool:        "format_time",
+			Arguments:   map[string]any{"timestamp": "2023-12-25T15:30:45Z", "format": "NotAFormat"},
+			ExpectError: true,
+		},
+
+		// parse_time
+		{
+			Name:      "parse_time/rfc3339",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "2023-12-25T15:30:45Z", "format": "RFC3339"},
+			Expected: map[string]any{
+				"unix_timestamp": 1703518245,
+				"rfc3339":        "2023-12-25T15:30:45Z",
+				"timezone":       "UTC",
+				"is_dst":         false,
+			},
+		},
+		{
+			Name:      "parse_time/explicit_utc_converted",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "2023-12-25T15:30:45Z", "timezone": "Asia/Tokyo"},
+			Expected: map[string]any{
+				"unix_timestamp":    1703518245,
+				"rfc3339":           "2023-12-26T00:30:45+09:00",
+				"timezone_handling": "assume",
+				"explicit_offset":   true,
+				"components": map[string]any{
+					"year": 2023, "month": 12, "day": 26,
+					"hour": 0, "minute": 30, "second": 45, "nanosecond": 0,
+					"weekday": "Tuesday", "day_of_year": 360,
+					"iso_year": 2023, "iso_week": 52,
+					"offset_seconds": 32400, "zone_abbreviation": "JST",
+				},
+			},
+		},
+		{
+			Name:        "parse_time/require_explicit_rejects_wall_clock",
+			Tool:        "parse_time",
+			Arguments:   map[string]any{"time_string": "2023-12-25 15:30:45", "format": "2006-01-02 15:04:05", "timezone_handling": "require_explicit"},
+			ExpectError: true,
+		},
+		{
+			Name:      "parse_time/unix",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "1703518245", "format": "Unix"},
+			Expected: map[string]any{
+				"unix_timestamp": 1703518245,
+			},
+		},
+		{
+			Name:      "parse_time/postgres_timestamptz",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "2023-12-25 21:00:45.5+05:30", "format": "PostgresTimestamptz"},
+			Expected: map[string]any{
+				"unix_timestamp":  1703518245,
+				"explicit_offset": true,
+			},
+		},
+		{
+			Name:      "parse_time/rata_die",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "738879", "format": "RataDie"},
+			Expected: map[string]any{
+				"rfc3339":         "2023-12-25T00:00:00Z",
+				"explicit_offset": false,
+			},
+		},
+		{
+			Name:      "parse_time/tle_epoch",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "23359.50000000", "format": "TLEEpoch"},
+			Expected: map[string]any{
+				"rfc3339":         "2023-12-25T12:00:00Z",
+				"explicit_offset": true,
+			},
+		},
+		{
+			Name:        "parse_time/invalid_string",
+			Tool:        "parse_time",
+			Arguments:   map[string]any{"time_string": "not a time", "format": "RFC3339"},
+			ExpectError: true,
+		},
+
+		{
+			Name:      "parse_time/two_digit_year_pivot",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "07/04/55", "format": "01/02/06", "two_digit_year_pivot": 50},
+			Expected: map[string]any{
+				"rfc3339":        "1955-07-04T00:00:00Z",
+				"two_digit_year": map[string]any{"input": 55, "pivot": 50, "century": 1900},
+			},
+		},
+
+		{
+			Name:      "parse_time/negative_unix",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "-14182940", "format": "Unix"},
+			Expected: map[string]any{
+				"unix_timestamp": -14182940,
+				"rfc3339":        "1969-07-20T20:17:40Z",
+				"is_pre_epoch":   true,
+			},
+		},
+		{
+			Name:      "parse_time/julian_calendar",
+			Tool:      "parse_time",
+			Arguments: map[string]any{"time_string": "1582-10-04T12:00:00Z", "format": "RFC3339", "calendar": "julian"},
+			Expected: map[string]any{
+				"rfc3339": "1582-10-14T12:00:00Z",
+				"calendar": map[string]any{
+					"calendar":       "julian",
+					"pre_gregorian":  true,
+					"cutover":        "1582-10-15",
+					"julian_date":    "1582-10-04",
+					"gregorian_date": "1582-10-14",
+				},
+			},
+		},
+
+		// timezone_info
+		{
+			Name:      "timezone_info/kolkata",
+			Tool:      "timezone_info",
+			Arguments: map[string]any{"timezone": "Asia/Kolkata", "reference_time": "2023-12-25T15:30:45Z"},
+			Expected: map[string]any{
+				"name":           "Asia/Kolkata",
+				"abbreviation":   "IST",
+				"offset":         "+05:30",
+				"offset_seconds": 19800,
+				"is_dst":         false,
+			},
+		},
+		{
+			Name:      "timezone_info/new_york_winter",
+			Tool:      "timezone_info",
+			Arguments: map[string]any{"timezone": "America/New_York", "reference_time": "2023-12-25T15:30:45Z"},
+			Expected: map[string]any{
+				"name":              "America/New_York",
+				"abbreviation":      "EST",
+				"offset":            "-05:00",
+				"offset_seconds":    -18000,
+				"is_dst":            false,
+				"has_dst":           true,
+				"transition_status": "scheduled",
+				"lookahead_days":    365,
+			},
+		},
+		{
+			Name:      "timezone_info/standard_offset_change",
+			Tool:      "timezone_info",
+			Arguments: map[string]any{"timezone": "Asia/Pyongyang", "reference_time": "2016-01-01T00:00:00Z", "lookahead_days": 1000},
+			Expected: map[string]any{
+				"offset": "+08:30",
+				"next_transition": map[string]any{
+					"next_transition": "2018-05-05T08:30:00+09:00",
+					"transition_type": "standard_offset_change",
+					"kind":            "standard_offset_change",
+					"offset_change":   1800,
+				},
+			},
+		},
+		{
+			Name:      "timezone_info/no_transition_within_horizon",
+			Tool:      "timezone_info",
+			Arguments: map[string]any{"timezone": "America/Sao_Paulo", "reference_time": "2023-12-25T15:30:45Z", "lookahead_days": 730},
+			Expected: map[string]any{
+				"abbreviation":      "-03",
+				"has_dst":           false,
+				"transition_status": "none_within_horizon",
+				"lookahead_days":    730,
+			},
+		},
+		{
+			Name:        "timezone_info/invalid_timezone",
+			Tool:        "timezone_info",
+			Arguments:   map[string]any{"timezone": "Invalid/Timezone"},
+			ExpectError: true,
+		},
+
+		// diff_zone_rules
+		{
+			Name:      "diff_zone_rules/dst_made_permanent",
+			Tool:      "diff_zone_rules",
+			Arguments: map[string]any{"timezone": "Europe/Istanbul", "from": "2015-06-01T00:00:00Z", "to": "2017-06-01T00:00:00Z"},
+			Expected: map[string]any{
+				"timezone": "Europe/Istanbul",
+				"changed":  true,
+				"differences": []any{
+					map[string]any{"field": "abbreviation", "from": "EEST", "to": "+03"},
+					map[string]any{"field": "standard_offset", "from": "+02:00", "to": "+03:00"},
+					map[string]any{"field": "standard_abbreviation", "from": "EET", "to": "+03"},
+					map[string]any{"field": "dst_windows", "from": "1", "to": "0"},
+				},
+			},
+		},
+		{
+			Name:        "diff_zone_rules/unknown_tzdata_source",
+			Tool:        "diff_zone_rules",
+			Arguments:   map[string]any{"timezone": "Europe/Istanbul", "to_tzdata": "2019c"},
+			ExpectError: true,
+		},
+
+		// get_zone_abbreviations
+		{
+			Name:      "get_zone_abbreviations/lisbon_cet",
+			Tool:      "get_zone_abbreviations",
+			Arguments: map[string]any{"timezone": "Europe/Lisbon", "abbreviation": "CET"},
+			Expected: map[string]any{
+				"timezone": "Europe/Lisbon",
+				"abbreviations": []any{
+					map[string]any{
+						"abbreviation":   "CET",
+						"offset":         "+01:00",
+						"offset_seconds": 3600,
+						"dst":            false,
+						"numeric":        false,
+						"eras": []any{
+							map[string]any{"start": "1966-10-02T03:00:00+01:00", "end": "1976-09-26T00:00:00Z", "periods": 1, "ongoing": false},
+							map[string]any{"start": "1992-09-27T02:00:00+01:00", "end": "1996-03-31T02:00:00+01:00", "periods": 4, "ongoing": false},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_zone_abbreviations/invalid_timezone",
+			Tool:        "get_zone_abbreviations",
+			Arguments:   map[string]any{"timezone": "Mars/Olympus"},
+			ExpectError: true,
+		},
+
+		// resolve_abbreviation
+		{
+			Name:      "resolve_abbreviation/country_hint",
+			Tool:      "resolve_abbreviation",
+			Arguments: map[string]any{"abbreviation": "CST", "country": "CN", "at": "2024-01-15T00:00:00Z"},
+			Expected: map[string]any{
+				"abbreviation": "CST",
+				"at":           "2024-01-15T00:00:00Z",
+				"best":         "Asia/Shanghai",
+				"ambiguous":    false,
+			},
+		},
+		{
+			Name:        "resolve_abbreviation/unknown",
+			Tool:        "resolve_abbreviation",
+			Arguments:   map[string]any{"abbreviation": "XYZ"},
+			ExpectError: true,
+		},
+
+		// translate_date
+		{
+			Name:      "translate_date/us_to_german",
+			Tool:      "translate_date",
+			Arguments: map[string]any{"dates": []any{"12/03/2025", "12/25/2025"}, "from_locale": "en-US", "to_locale": "de"},
+			Expected: map[string]any{
+				"from_pattern": "MM/DD/YYYY",
+				"to_pattern":   "DD.MM.YYYY",
+				"translated":   2,
+				"ambiguous":    1,
+				"failed":       0,
+				"dates": []any{
+					map[string]any{"input": "12/03/2025", "output": "03.12.2025", "iso": "2025-12-03", "ambiguous": true,
+						"alternative": "2025-03-12", "notes": []any{"read as DD/MM/YYYY it would be 2025-03-12"}},
+					map[string]any{"input": "12/25/2025", "output": "25.12.2025", "iso": "2025-12-25", "ambiguous": false},
+				},
+			},
+		},
+		{
+			Name:        "translate_date/unsupported_locale",
+			Tool:        "translate_date",
+			Arguments:   map[string]any{"dates": []any{"12/03/2025"}, "from_locale": "en-US", "to_locale": "tlh"},
+			ExpectError: true,
+		},
+
+		// was_dst
+		{
+			Name:      "was_dst/after_spring_forward",
+			Tool:      "was_dst",
+			Arguments: map[string]any{"at": "2024-03-10T07:00:01Z", "timezone": "America/New_York"},
+			Expected: map[string]any{
+				"at":           "2024-03-10T03:00:01-04:00",
+				"dst":          true,
+				"abbreviation": "EDT",
+				"offset":       "-04:00",
+				"previous_transition": map[string]any{
+					"at": "2024-03-10T03:00:00-04:00", "at_utc": "2024-03-10T07:00:00Z",
+					"kind": "dst_transition", "transition_type": "enter_dst",
+					"abbreviation_before": "EST", "abbreviation_after": "EDT",
+					"offset_before": "-05:00", "offset_after": "-04:00",
+					"dst_before": false, "dst_after": true, "seconds_from_at": -1,
+				},
+			},
+		},
+		{
+			Name:        "was_dst/invalid_timezone",
+			Tool:        "was_dst",
+			Arguments:   map[string]any{"timezone": "Mars/Olympus"},
+			ExpectError: true,
+		},
+
+		// get_server_uptime
+		{
+			Name:      "get_server_uptime/basic",
+			Tool:      "get_server_uptime",
+			Arguments: map[string]any{},
+			Expected: map[string]any{
+				"start_time":   "2023-12-25T15:30:45Z",
+				"uptime":       "0s",
+				"monotonic_ns": 0,
+			},
+		},
+		{
+			Name:        "get_server_uptime/negative_since",
+			Tool:        "get_server_uptime",
+			Arguments:   map[string]any{"since_monotonic_ns": -1},
+			ExpectError: true,
+		},
+
+		// compare_clock
+		{
+			Name:      "compare_clock/client_behind",
+			Tool:      "compare_clock",
+			Arguments: map[string]any{"client_time": "2023-12-25T15:30:40Z"},
+			Expected: map[string]any{
+				"client_time":         "2023-12-25T15:30:40Z",
+				"server_receive_time": "2023-12-25T15:30:45Z",
+				"skew_ms":             5000,
+				"client_clock":        "behind",
+			},
+		},
+		{
+			Name:        "compare_clock/invalid_client_time",
+			Tool:        "compare_clock",
+			Arguments:   map[string]any{"client_time": "not a time"},
+			ExpectError: true,
+		},
+
+		// totp_window
+		{
+			Name:      "totp_window/default_step",
+			Tool:      "totp_window",
+			Arguments: map[string]any{},
+			Expected: map[string]any{
+				"step_seconds":      30,
+				"counter":           56783941,
+				"counter_hex":       "0000000003627445",
+				"window_start":      "2023-12-25T15:30:30Z",
+				"window_end":        "2023-12-25T15:31:00Z",
+				"seconds_remaining": 15,
+			},
+		},
+		{
+			Name:        "totp_window/invalid_step",
+			Tool:        "totp_window",
+			Arguments:   map[string]any{"step_seconds": -30},
+			ExpectError: true,
+		},
+
+		// check_expiry
+		{
+			Name:      "check_expiry/jwt",
+			Tool:      "check_expiry",
+			Arguments: map[string]any{"jwt": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzdWIiOiIxIiwiaWF0IjoxNzAzNTA1NjAwLCJleHAiOjE3MDM1MjAwMDB9.sig"},
+			Expected: map[string]any{
+				"expires_at":        "2023-12-25T16:00:00Z",
+				"expired":           false,
+				"remaining_seconds": 1755,
+				"source":            "jwt",
+				"issued_at":         "2023-12-25T12:00:00Z",
+			},
+		},
+		{
+			Name:      "check_expiry/expired_certificate",
+			Tool:      "check_expiry",
+			Arguments: map[string]any{"expires_at": "2023-12-01T00:00:00Z", "timezone": "Europe/London"},
+			Expected: map[string]any{
+				"expires_at": "2023-12-01T00:00:00Z",
+				"expired":    true,
+				"summary":    "expired 24d 15h ago, at Fri, 01 Dec 2023 00:00 GMT",
+			},
+		},
+		{
+			Name:        "check_expiry/missing_input",
+			Tool:        "check_expiry",
+			Arguments:   map[string]any{},
+			ExpectError: true,
+		},
+
+		// analyze_timestamps
+		{
+			Name: "analyze_timestamps/gaps",
+			Tool: "analyze_timestamps",
+			Arguments: map[string]any{
+				"timestamps":    []any{"2023-12-25T15:00:20Z", 1703516400, "2023-12-25T15:00:10Z", "2023-12-25T15:05:00Z"},
+				"gap_threshold": "1m",
+			},
+			Expected: map[string]any{
+				"count":                   4,
+				"min":                     "2023-12-25T15:00:00Z",
+				"max":                     "2023-12-25T15:05:00Z",
+				"mean_interval_seconds":   100,
+				"median_interval_seconds": 10,
+				"gaps": []any{
+					map[string]any{"start": "2023-12-25T15:00:20Z", "end": "2023-12-25T15:05:00Z", "duration_seconds": 280, "duration": "4m40s"},
+				},
+				"events_per_minute": []any{
+					map[string]any{"minute": "2023-12-25T15:00:00Z", "count": 3},
+					map[string]any{"minute": "2023-12-25T15:05:00Z", "count": 1},
+				},
+			},
+		},
+		{
+			Name:        "analyze_timestamps/empty",
+			Tool:        "analyze_timestamps",
+			Arguments:   map[string]any{"timestamps": []any{}},
+			ExpectError: true,
+		},
+
+		// bucket_timestamps
+		{
+			Name: "bucket_timestamps/daily_across_dst",
+			Tool: "bucket_timestamps",
+			Arguments: map[string]any{
+				"timestamps": []any{"2023-11-05T03:30:00Z", "2023-11-05T12:00:00Z", "2023-11-06T04:30:00Z"},
+				"window":     "1d",
+				"timezone":   "America/New_York",
+			},
+			Expected: map[string]any{
+				"total": 3,
+				"buckets": []any{
+					map[string]any{"start": "2023-11-04T00:00:00-04:00", "end": "2023-11-05T00:00:00-04:00", "duration_seconds": 86400, "count": 1},
+					map[string]any{"start": "2023-11-05T00:00:00-04:00", "end": "2023-11-06T00:00:00-05:00", "duration_seconds": 90000, "count": 2},
+				},
+			},
+		},
+		{
+			Name:        "bucket_timestamps/invalid_window",
+			Tool:        "bucket_timestamps",
+			Arguments:   map[string]any{"timestamps": []any{1703518245}, "window": "fortnight"},
+			ExpectError: true,
+		},
+
+		// align_period
+		{
+			Name:      "align_period/quarter",
+			Tool:      "align_period",
+			Arguments: map[string]any{"period": "quarter"},
+			Expected: map[string]any{
+				"start":         "2023-10-01T00:00:00Z",
+				"end":           "2024-01-01T00:00:00Z",
+				"label":         "2023-Q4",
+				"display_label": "Q4 2023",
+			},
+		},
+		{
+			Name:      "align_period/week_starting_sunday",
+			Tool:      "align_period",
+			Arguments: map[string]any{"period": "week", "week_start": "sunday", "locale": "de"},
+			Expected: map[string]any{
+				"start":         "2023-12-24T00:00:00Z",
+				"end":           "2023-12-31T00:00:00Z",
+				"label":         "2023-W52",
+				"display_label": "KW 52 2023",
+			},
+		},
+		{
+			Name:        "align_period/invalid_period",
+			Tool:        "align_period",
+			Arguments:   map[string]any{"period": "fortnight"},
+			ExpectError: true,
+		},
+
+		// check_deadline
+		{
+			Name:      "check_deadline/within_grace",
+			Tool:      "check_deadline",
+			Arguments: map[string]any{"deadline": "2023-12-25T15:00:00Z", "grace": "1h"},
+			Expected: map[string]any{
+				"status":        "within_grace",
+				"grace_ends_at": "2023-12-25T16:00:00Z",
+				"delta_seconds": -1845,
+				"next_escalation": map[string]any{
+					"at":         "2023-12-25T16:00:00Z",
+					"status":     "overdue",
+					"in_seconds": 1755,
+				},
+			},
+		},
+		{
+			Name:        "check_deadline/negative_grace",
+			Tool:        "check_deadline",
+			Arguments:   map[string]any{"deadline": "2023-12-25T15:00:00Z", "grace": "-1h"},
+			ExpectError: true,
+		},
+
+		// anonymize_time
+		{
+			Name: "anonymize_time/shift_and_round",
+			Tool: "anonymize_time",
+			Arguments: map[string]any{
+				"timestamps": []any{"2023-12-25T15:30:45Z", "2023-12-25T09:10:00Z"},
+				"shift":      "-24h",
+				"round_to":   "1h",
+				"seed":       7,
+			},
+			Expected: map[string]any{
+				"timestamps": []any{"2023-12-24T15:00:00Z", "2023-12-24T09:00:00Z"},
+				"seed":       7,
+			},
+		},
+		{
+			Name:        "anonymize_time/empty",
+			Tool:        "anonymize_time",
+			Arguments:   map[string]any{"timestamps": []any{}},
+			ExpectError: true,
+		},
+
+		// create_ics
+		{
+			Name: "create_ics/zoned",
+			Tool: "create_ics",
+			Arguments: map[string]any{
+				"summary":  "Team sync",
+				"start":    "2023-12-27T10:00:00Z",
+				"duration": "30m",
+				"timezone": "Europe/Paris",
+				"uid":      "team-sync@example.com",
+			},
+			Expected: map[string]any{
+				"uid":          "team-sync@example.com",
+				"start":        "2023-12-27T11:00:00+01:00",
+				"end":          "2023-12-27T11:30:00+01:00",
+				"timezone":     "Europe/Paris",
+				"filename":     "team-sync.ics",
+				"content_type": "text/calendar; charset=utf-8; method=PUBLISH",
+			},
+		},
+		{
+			Name:        "create_ics/empty_summary",
+			Tool:        "create_ics",
+			Arguments:   map[string]any{"summary": "", "start": "2023-12-27T10:00:00Z"},
+			ExpectError: true,
+		},
+
+		// validate_webhook_timestamp
+		{
+			Name:      "validate_webhook_timestamp/valid",
+			Tool:      "validate_webhook_timestamp",
+			Arguments: map[string]any{"timestamp": "1703518203"},
+			Expected: map[string]any{
+				"valid":       true,
+				"status":      "valid",
+				"age_seconds": 42,
+				"expires_at":  "2023-12-25T15:35:03Z",
+			},
+		},
+		{
+			Name:      "validate_webhook_timestamp/stripe_expired",
+			Tool:      "validate_webhook_timestamp",
+			Arguments: map[string]any{"timestamp": "t=1703517645,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd"},
+			Expected: map[string]any{
+				"valid":       false,
+				"status":      "expired",
+				"age_seconds": 600,
+				"source":      "stripe_signature",
+			},
+		},
+		{
+			Name:        "validate_webhook_timestamp/milliseconds_as_seconds",
+			Tool:        "validate_webhook_timestamp",
+			Arguments:   map[string]any{"timestamp": "1703518203000"},
+			ExpectError: true,
+		},
+
+		// http_date
+		{
+			Name:      "http_date/now",
+			Tool:      "http_date",
+			Arguments: map[string]any{},
+			Expected:  map[string]any{"date": "Mon, 25 Dec 2023 15:30:45 GMT", "unix_seconds": 1703518245},
+		},
+		{
+			Name:      "http_date/rfc850",
+			Tool:      "http_date",
+			Arguments: map[string]any{"value": "Sunday, 06-Nov-94 08:49:37 GMT"},
+			Expected:  map[string]any{"date": "Sun, 06 Nov 1994 08:49:37 GMT"},
+		},
+
+		// retry_after
+		{
+			Name:      "retry_after/delay_seconds",
+			Tool:      "retry_after",
+			Arguments: map[string]any{"value": "120"},
+			Expected: map[string]any{
+				"kind":         "delay_seconds",
+				"retry_at":     "2023-12-25T15:32:45Z",
+				"wait_seconds": 120,
+				"elapsed":      false,
+			},
+		},
+		{
+			Name:      "retry_after/http_date",
+			Tool:      "retry_after",
+			Arguments: map[string]any{"value": "Mon, 25 Dec 2023 15:00:00 GMT"},
+			Expected: map[string]any{
+				"kind":         "http_date",
+				"wait_seconds": 0,
+				"elapsed":      true,
+			},
+		},
+		{
+			Name:        "retry_after/fractional",
+			Tool:        "retry_after",
+			Arguments:   map[string]any{"value": "1.5"},
+			ExpectError: true,
+		},
+		// audit_crontab
+		{
+			Name: "audit_crontab/never_fires_and_overlap",
+			Tool: "audit_crontab",
+			Arguments: map[string]any{
+				"crontab": "0 0 30 2 * /bin/true\n@hourly /bin/date\n0 * * * * /usr/bin/uptime",
+			},
+			Expected: map[string]any{
+				"timezone": "UTC",
+				"from":     "2023-12-25T15:30:00Z",
+				"issues":   2,
+			},
+		},
+		{
+			Name:        "audit_crontab/empty",
+			Tool:        "audit_crontab",
+			Arguments:   map[string]any{"crontab": ""},
+			ExpectError: true,
+		},
+		// check_cronjob_schedule
+		{
+			Name: "check_cronjob_schedule/spec_time_zone",
+			Tool: "check_cronjob_schedule",
+			Arguments: map[string]any{
+				"schedule":  "0 9 * * 1-5",
+				"time_zone": "Asia/Tokyo",
+			},
+			Expected: map[string]any{
+				"effective_timezone": "Asia/Tokyo",
+				"timezone_source":    "spec.timeZone",
+				"never_fires":        false,
+			},
+		},
+		{
+			Name:        "check_cronjob_schedule/invalid_schedule",
+			Tool:        "check_cronjob_schedule",
+			Arguments:   map[string]any{"schedule": "0 9 * *"},
+			ExpectError: true,
+		},
+		// parse_maintenance_window
+		{
+			Name: "parse_maintenance_window/aws",
+			Tool: "parse_maintenance_window",
+			Arguments: map[string]any{
+				"window":   "wed:04:00-wed:04:30",
+				"timezone": "America/Los_Angeles",
+			},
+			Expected: map[string]any{
+				"recurrence":       "weekly",
+				"window_timezone":  "UTC",
+				"duration_minutes": 30,
+				"active":           false,
+			},
+		},
+		{
+			Name:        "parse_maintenance_window/invalid",
+			Tool:        "parse_maintenance_window",
+			Arguments:   map[string]any{"window": "wed 4am"},
+			ExpectError: true,
+		},
+
+		// explain_layout
+		{
+			Name:      "explain_layout/sample",
+			Tool:      "explain_layout",
+			Arguments: map[string]any{"sample": "25/12/2023 15:30"},
+			Expected: map[string]any{
+				"layout":   "02/01/2006 15:04",
+				"strftime": "%d/%m/%Y %H:%M",
+				"parsed":   "2023-12-25T15:30:00Z",
+				"example":  "25/12/2023 15:30",
+			},
+		},
+		{
+			Name:      "explain_layout/layout",
+			Tool:      "explain_layout",
+			Arguments: map[string]any{"layout": "Mon Jan _2 15:04:05 MST 2006"},
+			Expected: map[string]any{
+				"strftime": "%a %b %e %H:%M:%S %Z %Y",
+				"example":  "Mon Dec 25 15:30:45 UTC 2023",
+			},
+		},
+		{
+			Name:        "explain_layout/written_out_date",
+			Tool:        "explain_layout",
+			Arguments:   map[string]any{"layout": "2023-12-25"},
+			ExpectError: true,
+		},
+
+		// verify_format
+		{
+			Name: "verify_format/lossless",
+			Tool: "verify_format",
+			Arguments: map[string]any{
+				"format": "2006-01-02T15:04:05.000Z07:00",
+				"sample": "2023-12-25T15:30:45.123+01:00",
+			},
+			Expected: map[string]any{
+				"reformatted": "2023-12-25T15:30:45.123+01:00",
+				"round_trip":  true,
+				"lossless":    true,
+			},
+		},
+		{
+			Name: "verify_format/drops_sub_seconds",
+			Tool: "verify_format",
+			Arguments: map[string]any{
+				"format": "%Y-%m-%d %H:%M:%S %z",
+				"sample": "2023-12-25 15:30:45.5 +0000",
+			},
+			Expected: map[string]any{
+				"reformatted": "2023-12-25 15:30:45 +0000",
+				"round_trip":  false,
+				"lossless":    false,
+			},
+		},
+		{
+			Name:        "verify_format/unparseable",
+			Tool:        "verify_format",
+			Arguments:   map[string]any{"format": "02/01/2006", "sample": "2023-12-25"},
+			ExpectError: true,
+		},
+
+		// parse_table
+		{
+			Name: "parse_table/csv_with_header",
+			Tool: "parse_table",
+			Arguments: map[string]any{
+				"text":     "id,created\n1,25/12/2023 15:30\n2,yesterday\n",
+				"column":   1,
+				"header":   true,
+				"format":   "%d/%m/%Y %H:%M",
+				"timezone": "Europe/Paris",
+			},
+			Expected: map[string]any{
+				"delimiter": ",",
+				"parsed":    1,
+				"failed":    1,
+			},
+		},
+		{
+			Name:        "parse_table/empty",
+			Tool:        "parse_table",
+			Arguments:   map[string]any{"text": ""},
+			ExpectError: true,
+		},
+
+		// detect_gaps
+		{
+			Name: "detect_gaps/missing_and_duplicated",
+			Tool: "detect_gaps",
+			Arguments: map[string]any{
+				"timestamps": []any{
+					"2023-12-25T15:00:00Z", "2023-12-25T15:05:00Z", "2023-12-25T15:20:00Z", "2023-12-25T15:20:00Z",
+				},
+				"interval": "5m",
+				"timezone": "Europe/Paris",
+			},
+			Expected: map[string]any{
+				"count":        4,
+				"missing":      2,
+				"out_of_order": 0,
+			},
+		},
+		{
+			Name:        "detect_gaps/invalid_interval",
+			Tool:        "detect_gaps",
+			Arguments:   map[string]any{"timestamps": []any{"2023-12-25T15:00:00Z"}, "interval": "often"},
+			ExpectError: true,
+		},
+
+		// to_sql_literal
+		{
+			Name:      "to_sql_literal/postgres_timestamptz",
+			Tool:      "to_sql_literal",
+			Arguments: map[string]any{"timestamp": "2023-12-25T15:30:45Z", "dialect": "postgres", "type": "timestamptz", "timezone": "Asia/Kolkata"},
+			Expected: map[string]any{
+				"literal":   "TIMESTAMPTZ '2023-12-25 21:00:45+05:30'",
+				"truncated": false,
+			},
+		},
+		{
+			Name:      "to_sql_literal/sqlserver_now",
+			Tool:      "to_sql_literal",
+			Arguments: map[string]any{"dialect": "sqlserver"},
+			Expected: map[string]any{
+				"literal": "CAST('2023-12-25T15:30:45' AS DATETIME2)",
+			},
+		},
+		{
+			Name:        "to_sql_literal/unknown_dialect",
+			Tool:        "to_sql_literal",
+			Arguments:   map[string]any{"dialect": "oracle"},
+			ExpectError: true,
+		},
+
+		// decode_snowflake
+		{
+			Name:      "decode_snowflake/discord",
+			Tool:      "decode_snowflake",
+			Arguments: map[string]any{"id": "175928847299117063", "preset": "discord"},
+			Expected: map[string]any{
+				"timestamp": "2016-04-30T11:18:25.796Z",
+				"unix_ms":   1462015105796,
+			},
+		},
+		{
+			Name:        "decode_snowflake/not_a_number",
+			Tool:        "decode_snowflake",
+			Arguments:   map[string]any{"id": "abc"},
+			ExpectError: true,
+		},
+
+		// decode_id_timestamp
+		{
+			Name:      "decode_id_timestamp/ulid",
+			Tool:      "decode_id_timestamp",
+			Arguments: map[string]any{"id": "01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+			Expected: map[string]any{
+				"kind":      "ulid",
+				"timestamp": "2016-07-30T23:54:10.259Z",
+				"precision": "millisecond",
+			},
+		},
+		{
+			Name:        "decode_id_timestamp/uuidv4",
+			Tool:        "decode_id_timestamp",
+			Arguments:   map[string]any{"id": "550e8400-e29b-41d4-a716-446655440000"},
+			ExpectError: true,
+		},
+
+		// add_time
+		{
+			Name:      "add_time/calendar_day_across_dst",
+			Tool:      "add_time",
+			Arguments: map[string]any{"timestamp": "2024-03-09T09:00:00-05:00", "duration": "P1D", "timezone": "America/New_York"},
+			Expected: map[string]any{
+				"result":          "2024-03-10T09:00:00-04:00",
+				"semantics":       "calendar",
+				"elapsed_seconds": 82800,
+			},
+		},
+		{
+			Name:      "add_time/absolute_day_across_dst",
+			Tool:      "add_time",
+			Arguments: map[string]any{"timestamp": "2024-03-09T09:00:00-05:00", "duration": "P1D", "semantics": "absolute", "timezone": "America/New_York"},
+			Expected: map[string]any{
+				"result":          "2024-03-10T10:00:00-04:00",
+				"semantics":       "absolute",
+				"elapsed_seconds": 86400,
+			},
+		},
+		{
+			Name:      "add_time/month_end_clamp",
+			Tool:      "add_time",
+			Arguments: map[string]any{"timestamp": "2024-01-31T12:00:00Z", "duration": "P1M"},
+			Expected: map[string]any{
+				"result":    "2024-02-29T12:00:00Z",
+				"month_end": "clamp",
+			},
+		},
+		{
+			Name:        "add_time/month_end_error",
+			Tool:        "add_time",
+			Arguments:   map[string]any{"timestamp": "2024-01-31T12:00:00Z", "duration": "P1M", "month_end": "error"},
+			ExpectError: true,
+		},
+		{
+			Name:        "add_time/absolute_month",
+			Tool:        "add_time",
+			Arguments:   map[string]any{"duration": "P1M", "semantics": "absolute"},
+			ExpectError: true,
+		},
+
+		// next_billing_date
+		{
+			Name:      "next_billing_date/month_end_anchor",
+			Tool:      "next_billing_date",
+			Arguments: map[string]any{"anchor": "2023-10-31T09:00:00Z", "count": 2},
+			Expected: map[string]any{
+				"anchor_day": 31,
+				"month_end":  "clamp",
+				"dates": []any{
+					map[string]any{"period": 2, "date": "2023-12-31T09:00:00Z"},
+					map[string]any{"period": 3, "date": "2024-01-31T09:00:00Z"},
+				},
+			},
+		},
+		{
+			Name:        "next_billing_date/weekly",
+			Tool:        "next_billing_date",
+			Arguments:   map[string]any{"anchor": "2023-10-31T09:00:00Z", "period": "weekly"},
+			ExpectError: true,
+		},
+
+		// prorate
+		{
+			Name: "prorate/thirty_360",
+			Tool: "prorate",
+			Arguments: map[string]any{"period_start": "2024-01-15T00:00:00Z", "period_end": "2024-02-15T00:00:00Z",
+				"change": "2024-01-31T00:00:00Z", "amount": 100, "day_count": "30/360"},
+			Expected: map[string]any{
+				"period_units":     30,
+				"used_units":       16,
+				"used_amount":      53.33,
+				"remaining_amount": 46.67,
+			},
+		},
+		{
+			Name:        "prorate/change_outside_period",
+			Tool:        "prorate",
+			Arguments:   map[string]any{"period_start": "2024-01-01T00:00:00Z", "period_end": "2024-02-01T00:00:00Z", "change": "2024-03-01T00:00:00Z"},
+			ExpectError: true,
+		},
+		// timesheet
+		{
+			Name: "timesheet/daily_overtime",
+			Tool: "timesheet",
+			Arguments: map[string]any{"shifts": []any{map[string]any{"in": "2024-01-08T08:07:00Z", "out": "2024-01-08T16:08:00Z"}},
+				"daily_overtime": "8h"},
+			Expected: map[string]any{
+				"worked_minutes":   495,
+				"regular_minutes":  480,
+				"overtime_minutes": 15,
+				"days":             []any{map[string]any{"date": "2024-01-08", "worked_minutes": 495, "overtime_minutes": 15, "overtime_from": "2024-01-08T16:00:00Z"}},
+			},
+		},
+		{
+			Name:        "timesheet/bad_increment",
+			Tool:        "timesheet",
+			Arguments:   map[string]any{"shifts": []any{map[string]any{"in": "2024-01-08T08:00:00Z", "out": "2024-01-08T16:00:00Z"}}, "increment": 7},
+			ExpectError: true,
+		},
+		// evaluate_shift_premium
+		{
+			Name:      "evaluate_shift_premium/default_windows",
+			Tool:      "evaluate_shift_premium",
+			Arguments: map[string]any{"start": "2024-01-12T18:00:00Z", "end": "2024-01-13T10:00:00Z"},
+			Expected: map[string]any{
+				"minutes": 960,
+				"categories": []any{
+					map[string]any{"name": "night", "minutes": 480},
+					map[string]any{"name": "weekend", "minutes": 240},
+					map[string]any{"name": "regular", "minutes": 240},
+				},
+			},
+		},
+		{
+			Name:        "evaluate_shift_premium/reserved_name",
+			Tool:        "evaluate_shift_premium",
+			Arguments:   map[string]any{"start": "2024-01-12T18:00:00Z", "end": "2024-01-13T10:00:00Z", "windows": []any{map[string]any{"name": "regular"}}},
+			ExpectError: true,
+		},
+	}
+}
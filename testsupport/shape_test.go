@@ -0,0 +1,120 @@
+package testsupport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/result_shapes.golden")
+
+const shapesGolden = "testdata/result_shapes.golden"
+
+// TestResultShapes pins the field order, presence and JSON types of every
+// fixture's structured result. Agents learn field positions, so a change
+// here is a breaking change: run with -update only when it is intended.
+func TestResultShapes(t *testing.T) {
+	var structured json.RawMessage
+	capture := func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			if result, ok := res.(*mcp.CallToolResult); ok && err == nil {
+				structured, err = json.Marshal(result.StructuredContent)
+			}
+			return res, err
+		}
+	}
+	session := newContractSession(t, capture)
+
+	var lines []string
+	for _, fixture := range Fixtures() {
+		if fixture.ExpectError {
+			continue
+		}
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: fixture.Tool, Arguments: fixture.Arguments})
+		require.NoError(t, err, fixture.Name)
+		require.False(t, res.IsError, "%s: unexpected tool error: %v", fixture.Name, res.Content)
+
+		shape, err := resultShape(structured)
+		require.NoError(t, err, fixture.Name)
+		assert.NotContains(t, shape, "null", "%s: results never contain null", fixture.Name)
+		lines = append(lines, fixture.Name+" "+shape)
+	}
+	got := strings.Join(lines, "\n") + "\n"
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(shapesGolden), 0o755))
+		require.NoError(t, os.WriteFile(shapesGolden, []byte(got), 0o644))
+	}
+	want, err := os.ReadFile(shapesGolden)
+	require.NoError(t, err, "run go test ./testsupport -run TestResultShapes -update to create it")
+	assert.Equal(t, string(want), got)
+}
+
+// resultShape renders a JSON value's shape: objects with their keys in
+// order, arrays as the distinct shapes of their elements, and scalars as
+// their JSON type
+func resultShape(raw []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return shapeOf(dec)
+}
+
+func shapeOf(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		var parts []string
+		seen := make(map[string]bool)
+		for dec.More() {
+			var key string
+			if t == '{' {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return "", err
+				}
+				key = fmt.Sprintf("%s:", keyTok)
+			}
+			child, err := shapeOf(dec)
+			if err != nil {
+				return "", err
+			}
+			if t == '[' && seen[child] {
+				continue
+			}
+			seen[child] = true
+			parts = append(parts, key+child)
+		}
+		if _, err := dec.Token(); err != nil {
+			return "", err
+		}
+		if t == '[' {
+			sort.Strings(parts)
+			return "[" + strings.Join(parts, "|") + "]", nil
+		}
+		return "{" + strings.Join(parts, ",") + "}", nil
+	case string:
+		return "string", nil
+	case json.Number:
+		return "number", nil
+	case bool:
+		return "bool", nil
+	default:
+		return "null", nil
+	}
+}